@@ -0,0 +1,216 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jeremyhahn/go-codec/pkg/codecgen"
+)
+
+// cborFormat generates code matching the cbor library's default options
+type cborFormat struct{}
+
+func (cborFormat) name() string  { return "cbor" }
+func (cborFormat) ident() string { return "CBOR" }
+
+// tag follows the cbor library, which reads the json tag of fields
+// without a cbor tag
+func (cborFormat) tag(tag reflect.StructTag, field string) (fieldTag, bool, error) {
+	value := tag.Get("cbor")
+	if value == "" {
+		value = tag.Get("json")
+	}
+	if value == "-" {
+		return fieldTag{}, false, nil
+	}
+	name, opts, _ := strings.Cut(value, ",")
+	if name == "" {
+		name = field
+	}
+	ft := fieldTag{key: name}
+	for _, opt := range strings.Split(opts, ",") {
+		switch opt {
+		case "omitempty":
+			ft.omitEmpty = true
+		case "omitzero", "keyasint", "toarray":
+			return fieldTag{}, false, fmt.Errorf("cbor option %q is not supported", opt)
+		}
+	}
+	return ft, true, nil
+}
+
+func (f cborFormat) writeAppend(g *gen, s *structType) {
+	fields, tags := fieldsOf(f, s)
+	g.function(fmt.Sprintf("func append%sCBOR(buf []byte, v *%s, depth int) ([]byte, error)", s.name, s.name), func() {
+		g.enter()
+		pending := appendMapHeader(g, f, fields, tags, codecgen.AppendCBORMapLen)
+		for i, fld := range fields {
+			x := "v." + fld.name
+			key := tags[i].key
+			enc := codecgen.AppendCBORString(pending, key)
+			omit := omits(f, fld, tags[i])
+			if omit {
+				g.line("if %s {", nonEmpty(f, x, fld.typ))
+			}
+			g.appendHead(enc[:len(enc)-len(key)], key)
+			pending = nil
+			vx, vt := present(omit, x, fld.typ)
+			f.appendValue(g, vx, vt)
+			if omit {
+				g.line("}")
+			}
+		}
+		if pending != nil {
+			g.appendHead(pending, "")
+		}
+		g.line("return buf, nil")
+	})
+}
+
+// appendValue writes the statements appending x, of type t
+func (cborFormat) appendValue(g *gen, x string, t *goType) {
+	switch t.kind {
+	case kindBool:
+		g.line("buf = codecgen.AppendCBORBool(buf, %s)", conv("bool", x, t))
+	case kindInt:
+		g.line("buf = codecgen.AppendCBORInt(buf, %s)", conv("int64", x, t))
+	case kindUint:
+		g.line("buf = codecgen.AppendCBORUint(buf, %s)", conv("uint64", x, t))
+	case kindFloat:
+		g.line("buf = codecgen.AppendCBORFloat%d(buf, %s)", t.bits, conv(t.basic, x, t))
+	case kindString:
+		g.line("buf = codecgen.AppendCBORString(buf, %s)", conv("string", x, t))
+	case kindBytes:
+		g.line("buf = codecgen.AppendCBORBytes(buf, %s)", x)
+	case kindSlice:
+		i := g.tmp("i")
+		g.line("if %s == nil {", x)
+		g.line("buf = codecgen.AppendCBORNull(buf)")
+		g.line("} else {")
+		g.line("buf = codecgen.AppendCBORArrayLen(buf, len(%s))", x)
+		g.line("for %s := range %s {", i, x)
+		cborFormat{}.appendValue(g, x+"["+i+"]", t.elem)
+		g.line("}")
+		g.line("}")
+	case kindMap:
+		k, e := g.tmp("k"), g.tmp("e")
+		g.line("if %s == nil {", x)
+		g.line("buf = codecgen.AppendCBORNull(buf)")
+		g.line("} else {")
+		g.line("buf = codecgen.AppendCBORMapLen(buf, len(%s))", x)
+		g.line("for _, %s := range codecgen.SortedCBORKeys(%s) {", k, x)
+		g.line("buf = codecgen.AppendCBORString(buf, %s)", k)
+		g.line("%s := %s[%s]", e, x, k)
+		cborFormat{}.appendValue(g, e, t.elem)
+		g.line("}")
+		g.line("}")
+	case kindPointer:
+		g.line("if %s == nil {", x)
+		g.line("buf = codecgen.AppendCBORNull(buf)")
+		g.line("} else {")
+		cborFormat{}.appendValue(g, deref(x), t.elem)
+		g.line("}")
+	case kindStruct:
+		g.call("append"+t.strct.name+"CBOR", x)
+	}
+}
+
+func (f cborFormat) writeParse(g *gen, s *structType) {
+	fields, tags := fieldsOf(f, s)
+	g.function(fmt.Sprintf("func parse%sCBOR(r *codecgen.CBORReader, v *%s)", s.name, s.name), func() {
+		g.line("if r.Null() {")
+		g.line("return")
+		g.line("}")
+		if len(fields) > 0 {
+			g.line("var seen [%d]bool", len(fields))
+		}
+		g.line("for n := r.MapLen(); n > 0; n-- {")
+		g.line("switch key := r.Key(); string(key) {")
+		for i, fld := range fields {
+			g.line("case %q:", tags[i].key)
+			g.line("r.Field(&seen[%d])", i)
+			f.parseValue(g, "v."+fld.name, fld.typ)
+		}
+		g.line("default:")
+		if len(tags) == 0 {
+			g.line("r.Unknown(key)")
+		} else {
+			g.line("r.Unknown(key, %s)", keyNames(tags))
+		}
+		g.line("}")
+		g.line("}")
+		g.line("r.Leave()")
+	})
+}
+
+// parseValue writes the statements decoding into x, of type t
+func (cborFormat) parseValue(g *gen, x string, t *goType) {
+	switch t.kind {
+	case kindBool:
+		g.line("if !r.Null() {")
+		g.line("%s = %s", x, as(t, "bool", "r.Bool()"))
+		g.line("}")
+	case kindInt:
+		g.line("if !r.Null() {")
+		g.line("%s = %s", x, as(t, "int64", fmt.Sprintf("r.Int(%d)", t.bits)))
+		g.line("}")
+	case kindUint:
+		g.line("if !r.Null() {")
+		g.line("%s = %s", x, as(t, "uint64", fmt.Sprintf("r.Uint(%d)", t.bits)))
+		g.line("}")
+	case kindFloat:
+		g.line("if !r.Null() {")
+		g.line("%s = %s", x, as(t, "float64", fmt.Sprintf("r.Float(%d)", t.bits)))
+		g.line("}")
+	case kindString:
+		g.line("if !r.Null() {")
+		g.line("%s = %s", x, as(t, "string", "r.String()"))
+		g.line("}")
+	case kindBytes:
+		g.line("if r.Null() {")
+		g.line("%s = nil", x)
+		g.line("} else {")
+		g.line("%s = r.Bytes()", x)
+		g.line("}")
+	case kindSlice:
+		i := g.tmp("i")
+		g.line("if r.Null() {")
+		g.line("%s = nil", x)
+		g.line("} else {")
+		g.line("%s = codecgen.ResizeCBOR(%s, r.ArrayLen())", x, x)
+		g.line("for %s := range %s {", i, x)
+		cborFormat{}.parseValue(g, x+"["+i+"]", t.elem)
+		g.line("}")
+		g.line("r.Leave()")
+		g.line("}")
+	case kindMap:
+		n, k, e := g.tmp("n"), g.tmp("k"), g.tmp("e")
+		g.line("if r.Null() {")
+		g.line("%s = nil", x)
+		g.line("} else {")
+		g.line("%s := r.MapLen()", n)
+		g.line("if %s == nil {", x)
+		g.line("%s = make(%s, %s)", x, t.expr, n)
+		g.line("}")
+		g.line("for ; %s > 0; %s-- {", n, n)
+		g.line("%s := r.String()", k)
+		g.line("var %s %s", e, t.elem.expr)
+		cborFormat{}.parseValue(g, e, t.elem)
+		g.line("%s[%s] = %s", x, k, e)
+		g.line("}")
+		g.line("r.Leave()")
+		g.line("}")
+	case kindPointer:
+		g.line("if r.Null() {")
+		g.line("%s = nil", x)
+		g.line("} else {")
+		g.line("if %s == nil {", x)
+		g.line("%s = new(%s)", x, t.elem.expr)
+		g.line("}")
+		cborFormat{}.parseValue(g, deref(x), t.elem)
+		g.line("}")
+	case kindStruct:
+		g.line("parse%sCBOR(r, %s)", t.strct.name, addr(x))
+	}
+}
@@ -0,0 +1,503 @@
+//go:build codec_json && codec_yaml && codec_toml && codec_msgpack && codec_bson && codec_cbor && codec_avro && codec_protobuf
+
+package main
+
+import (
+	"bytes"
+	stdjson "encoding/json"
+	"math"
+	"math/rand/v2"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/cmd/codecgen/testdata"
+	cborcodec "github.com/jeremyhahn/go-codec/pkg/cbor"
+	"github.com/jeremyhahn/go-codec/pkg/codecgen"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+	msgpackcodec "github.com/jeremyhahn/go-codec/pkg/msgpack"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// library is the reflection-based library behind one of the codecs
+type library struct {
+	typ       codec.Type
+	marshal   func(v any) ([]byte, error)
+	unmarshal func(data []byte, v any) error
+
+	// codecs are the codec package's codec, which finds the generated code
+	// registered for the type, and the generated codec type
+	codecs []codec.OptimizedCodec[testdata.Order]
+}
+
+var libraries = []library{
+	{codec.JSON, stdjson.Marshal, stdjson.Unmarshal, []codec.OptimizedCodec[testdata.Order]{jsoncodec.NewPool[testdata.Order](), testdata.NewOrderJSON()}},
+	{codec.MsgPack, msgpack.Marshal, msgpack.Unmarshal, []codec.OptimizedCodec[testdata.Order]{msgpackcodec.NewPool[testdata.Order](), testdata.NewOrderMsgPack()}},
+	{codec.CBOR, cbor.Marshal, cbor.Unmarshal, []codec.OptimizedCodec[testdata.Order]{cborcodec.NewPool[testdata.Order](), testdata.NewOrderCBOR()}},
+}
+
+// random builds values of the fixture types
+type random struct {
+	r      *rand.Rand
+	maxMap int
+}
+
+func newRandom(seed uint64, maxMap int) *random {
+	return &random{r: rand.New(rand.NewPCG(seed, seed)), maxMap: maxMap}
+}
+
+var trickyStrings = []string{
+	"", "plain", `quote " and \ backslash`, "<html> & entities", "tab\tnewline\ncr\r",
+	"\x00\x01\x1f\x7f", "é ü 日本語 😀", "  ", "\xff invalid \xc3", strings.Repeat("long", 80),
+}
+
+func (g *random) string() string {
+	if g.r.IntN(3) > 0 {
+		return trickyStrings[g.r.IntN(len(trickyStrings))]
+	}
+	runes := make([]rune, g.r.IntN(40))
+	for i := range runes {
+		runes[i] = rune(g.r.IntN(0x1000))
+	}
+	return string(runes)
+}
+
+var trickyFloats = []float64{
+	0, math.Copysign(0, -1), 1, -1.5, 1e-6, 9.99e-7, 1e20, 1e21, 123456789e-15,
+	math.MaxFloat64, math.SmallestNonzeroFloat64, math.MaxFloat32, math.SmallestNonzeroFloat32,
+}
+
+func (g *random) float64() float64 {
+	if g.r.IntN(2) == 0 {
+		return trickyFloats[g.r.IntN(len(trickyFloats))]
+	}
+	for {
+		if f := math.Float64frombits(g.r.Uint64()); !math.IsNaN(f) && !math.IsInf(f, 0) {
+			return f
+		}
+	}
+}
+
+func (g *random) float32() float32 {
+	for {
+		f := math.Float32frombits(g.r.Uint32())
+		if g.r.IntN(2) == 0 {
+			f = float32(trickyFloats[g.r.IntN(len(trickyFloats))])
+		}
+		if !math.IsNaN(float64(f)) && !math.IsInf(float64(f), 0) {
+			return f
+		}
+	}
+}
+
+// int returns an integer of the given bit size, favouring small ones
+func (g *random) int(bits int) int64 {
+	n := int64(g.r.Uint64())
+	switch g.r.IntN(3) {
+	case 0:
+		n %= 40
+	case 1:
+		n %= 70000
+	}
+	return n << (64 - bits) >> (64 - bits)
+}
+
+func (g *random) uint(bits int) uint64 {
+	return uint64(g.int(64)) << (64 - bits) >> (64 - bits)
+}
+
+func (g *random) bytes() []byte {
+	switch g.r.IntN(4) {
+	case 0:
+		return nil
+	case 1:
+		return []byte{}
+	}
+	b := make([]byte, g.r.IntN(300))
+	for i := range b {
+		b[i] = byte(g.r.Uint32())
+	}
+	return b
+}
+
+// size returns a length for a slice or map, or -1 for nil
+func (g *random) size(max int) int {
+	return g.r.IntN(max+2) - 1
+}
+
+func (g *random) strings() []string {
+	n := g.size(4)
+	if n < 0 {
+		return nil
+	}
+	s := make([]string, n)
+	for i := range s {
+		s[i] = g.string()
+	}
+	return s
+}
+
+func (g *random) line() testdata.Line {
+	return testdata.Line{SKU: g.string(), Qty: uint16(g.uint(16)), Price: g.float64()}
+}
+
+func (g *random) address() testdata.Address {
+	a := testdata.Address{}
+	if g.r.IntN(2) == 0 {
+		a.Street = g.string()
+	}
+	if g.r.IntN(2) == 0 {
+		a.City = g.string()
+	}
+	return a
+}
+
+func (g *random) order() testdata.Order {
+	o := testdata.Order{
+		ID:       g.int(64),
+		Number:   int(g.int(strconvIntSize)),
+		Customer: g.string(),
+		Status:   testdata.Status(g.int(8)),
+		Paid:     g.r.IntN(2) == 0,
+		Total:    g.float64(),
+		Discount: g.float32(),
+		Small:    uint8(g.uint(8)),
+		Count:    uint(g.uint(strconvIntSize)),
+		Big:      g.uint(64),
+		Code:     int16(g.int(16)),
+		Rune:     rune(g.int(32)),
+		Label:    testdata.Label(g.string()),
+		Tags:     testdata.Tags(g.strings()),
+		Names:    g.strings(),
+		Raw:      g.bytes(),
+		Bill:     g.address(),
+		Ignored:  "ignored",
+		Skipped:  g.string(),
+	}
+	if g.r.IntN(2) == 0 {
+		note := g.string()
+		o.Note = &note
+	}
+	if n := g.size(3); n >= 0 {
+		o.Lines = make([]testdata.Line, n)
+		for i := range o.Lines {
+			o.Lines[i] = g.line()
+		}
+	}
+	if g.r.IntN(2) == 0 {
+		ship := g.address()
+		o.Ship = &ship
+	}
+	if n := g.size(g.maxMap); n >= 0 {
+		o.Attrs = make(map[string]string)
+		for range n {
+			o.Attrs[g.string()] = g.string()
+		}
+	}
+	if n := g.size(g.maxMap); n >= 0 {
+		o.Scores = make(map[string][]int32)
+		for range n {
+			var scores []int32
+			if m := g.size(3); m >= 0 {
+				scores = make([]int32, m)
+				for i := range scores {
+					scores[i] = int32(g.int(32))
+				}
+			}
+			o.Scores[g.string()] = scores
+		}
+	}
+	if n := g.size(g.maxMap); n >= 0 {
+		o.Extra = make(map[string]*testdata.Line)
+		for range n {
+			var line *testdata.Line
+			if g.r.IntN(3) > 0 {
+				l := g.line()
+				line = &l
+			}
+			o.Extra[g.string()] = line
+		}
+	}
+	if n := g.size(3); n >= 0 {
+		o.Matrix = make([][]float64, n)
+		for i := range o.Matrix {
+			if m := g.size(3); m >= 0 {
+				o.Matrix[i] = make([]float64, m)
+				for j := range o.Matrix[i] {
+					o.Matrix[i][j] = g.float64()
+				}
+			}
+		}
+	}
+	return o
+}
+
+const strconvIntSize = 32 << (^uint(0) >> 63)
+
+func TestDifferential_Marshal(t *testing.T) {
+	for _, lib := range libraries {
+		funcs, ok := codecgen.Lookup[testdata.Order](lib.typ)
+		if !ok {
+			t.Fatalf("%s: no generated code registered", lib.typ)
+		}
+		for seed := range uint64(500) {
+			// Maps of more than one entry are written in random order by
+			// msgpack and CBOR
+			v := newRandom(seed, 1).order()
+			want, err := lib.marshal(v)
+			if err != nil {
+				t.Fatalf("%s seed %d: library Marshal: %v", lib.typ, seed, err)
+			}
+			got, err := funcs.Append(nil, v)
+			if err != nil {
+				t.Fatalf("%s seed %d: generated code fell back: %v", lib.typ, seed, err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Fatalf("%s seed %d: generated\n%q\nwant\n%q", lib.typ, seed, got, want)
+			}
+			for i, c := range lib.codecs {
+				viaCodec, err := c.Marshal(v)
+				if err != nil || !bytes.Equal(viaCodec, want) {
+					t.Fatalf("%s seed %d: codec %d Marshal = %q, %v", lib.typ, seed, i, viaCodec, err)
+				}
+				appended, err := c.AppendMarshal([]byte("prefix"), v)
+				if err != nil || !bytes.Equal(appended, append([]byte("prefix"), want...)) {
+					t.Fatalf("%s seed %d: codec %d AppendMarshal = %q, %v", lib.typ, seed, i, appended, err)
+				}
+				to, err := c.MarshalTo(make([]byte, 0, 64), v)
+				if err != nil || !bytes.Equal(to, want) {
+					t.Fatalf("%s seed %d: codec %d MarshalTo = %q, %v", lib.typ, seed, i, to, err)
+				}
+			}
+		}
+	}
+}
+
+func TestDifferential_Unmarshal(t *testing.T) {
+	for _, lib := range libraries {
+		funcs, _ := codecgen.Lookup[testdata.Order](lib.typ)
+		for seed := range uint64(500) {
+			data, err := lib.marshal(newRandom(seed, 6).order())
+			if err != nil {
+				t.Fatalf("%s seed %d: library Marshal: %v", lib.typ, seed, err)
+			}
+
+			// Decoding into a zero value and into one holding a different
+			// value, so that slices, maps and pointers are reused
+			for _, prior := range []func() testdata.Order{
+				func() testdata.Order { return testdata.Order{} },
+				func() testdata.Order { return newRandom(seed+1000, 6).order() },
+			} {
+				want, direct := prior(), prior()
+				if err := lib.unmarshal(data, &want); err != nil {
+					// CBOR rejects the invalid UTF-8 that the other
+					// formats write
+					if funcs.Parse(data, &direct) == nil {
+						t.Fatalf("%s seed %d: library error %v not reported", lib.typ, seed, err)
+					}
+					for i, c := range lib.codecs {
+						if got := prior(); c.UnmarshalFrom(data, &got, nil) == nil {
+							t.Fatalf("%s seed %d: library error %v not reported by codec %d", lib.typ, seed, err, i)
+						}
+					}
+					continue
+				}
+				if err := funcs.Parse(data, &direct); err != nil {
+					t.Fatalf("%s seed %d: generated code fell back: %v", lib.typ, seed, err)
+				}
+				if !reflect.DeepEqual(direct, want) {
+					t.Fatalf("%s seed %d: generated\n%+v\nwant\n%+v", lib.typ, seed, direct, want)
+				}
+				for i, c := range lib.codecs {
+					got := prior()
+					if err := c.UnmarshalFrom(data, &got, nil); err != nil {
+						t.Fatalf("%s seed %d: codec %d Unmarshal: %v", lib.typ, seed, i, err)
+					}
+					if !reflect.DeepEqual(got, want) {
+						t.Fatalf("%s seed %d: codec %d\n%+v\nwant\n%+v", lib.typ, seed, i, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestDifferential_Fallback checks input that generated code leaves to
+// reflection, which must decode as it does without generated code
+func TestDifferential_Fallback(t *testing.T) {
+	cases := map[codec.Type][]string{
+		codec.JSON: {
+			`{"ID":1,"Customer":"folded keys"}`,
+			`{"id":1} trailing`,
+			`{"id":1e2}`,
+			`{"id":"1"}`,
+			`{"small":256}`,
+			`{"total":1e400}`,
+			`{"discount":1e39}`,
+			`{"raw":"not base64!"}`,
+			`{"id":`,
+			`[1,2]`,
+			`{"bill":{"STREET":"x"}}`,
+			`{"scores":{"a":[1,"2",3]}}`,
+			`null`,
+			`{"id":7,"id":8,"note":null,"tags":[],"customer":"é😀\ud800"}`,
+		},
+		codec.MsgPack: {
+			"\x92\x01\x02",                   // array-encoded struct
+			"\x81\xa8Discount\x05",           // integer into float32
+			"\x81\xa5Total\x05",              // integer into float64
+			"\x81\xa2id\x01\xc0",             // trailing data
+			"\x81\xa2id\xca\x3f\x80\x00\x00", // float into integer
+			"\x81\xa2id",
+			"\xc0",
+			"\x82\xa2id\x01\xc4\x02id\x02", // bin key, duplicate field
+		},
+		codec.CBOR: {
+			"\xa2\x62id\x01\x62id\x02",                             // duplicate field
+			"\xa1\x62ID\x01",                                       // folded key
+			"\xa1\x65total\xf9\x3c\x00",                            // half-precision float
+			"\xa1\x65total\x01",                                    // integer into float
+			"\xbf\x62id\x01\xff",                                   // indefinite-length map
+			"\xa1\x62id\xc2\x41\x01",                               // tagged bignum
+			"\xa1\x64note\xf7",                                     // undefined
+			"\xa1\x62id\x01\x00",                                   // extraneous data
+			"\xa1\x65small\x19\x01\x00",                            // overflow
+			"\xa1\x68discount\xfb\x7f\xef\xff\xff\xff\xff\xff\xff", // float32 overflow
+			"\xa1\x68customer\x62\xff\xfe",                         // invalid UTF-8
+			"\xf6",
+		},
+	}
+	for _, lib := range libraries {
+		for _, input := range cases[lib.typ] {
+			data := []byte(input)
+			for seed := range uint64(2) {
+				for i, c := range lib.codecs {
+					want, got := newRandom(seed, 3).order(), newRandom(seed, 3).order()
+					if seed == 0 {
+						want, got = testdata.Order{}, testdata.Order{}
+					}
+					wantErr := lib.unmarshal(data, &want)
+					gotErr := c.Unmarshal(data, &got)
+					if (wantErr == nil) != (gotErr == nil) {
+						t.Errorf("%s %q: codec %d error %v, library error %v", lib.typ, input, i, gotErr, wantErr)
+						continue
+					}
+					if wantErr == nil && !reflect.DeepEqual(got, want) {
+						t.Errorf("%s %q: codec %d\n%+v\nwant\n%+v", lib.typ, input, i, got, want)
+					}
+				}
+			}
+		}
+	}
+}
+
+// TestDifferential_FloatFallback checks floats that generated code leaves
+// to reflection or writes in a special form
+func TestDifferential_FloatFallback(t *testing.T) {
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		v := testdata.Order{Total: f, Discount: float32(f)}
+		for _, lib := range libraries {
+			want, wantErr := lib.marshal(v)
+			for i, c := range lib.codecs {
+				got, gotErr := c.Marshal(v)
+				if (wantErr == nil) != (gotErr == nil) || !bytes.Equal(got, want) {
+					t.Errorf("%s %v: codec %d %x, %v; library %x, %v", lib.typ, f, i, got, gotErr, want, wantErr)
+				}
+			}
+		}
+	}
+}
+
+func TestDifferential_Nesting(t *testing.T) {
+	// Deeper than generated code encodes
+	var deep *testdata.Node
+	for i := range codecgen.MaxDepth + 10 {
+		deep = &testdata.Node{Name: string(rune('a' + i%26)), Next: deep}
+	}
+	tree := testdata.Node{Name: "root", Children: []testdata.Node{{Name: "a"}, {Name: "b", Children: []testdata.Node{}}}}
+
+	for _, tc := range []struct {
+		typ       codec.Type
+		marshal   func(v any) ([]byte, error)
+		unmarshal func(data []byte, v any) error
+		codec     codec.Codec[testdata.Node]
+	}{
+		{codec.JSON, stdjson.Marshal, stdjson.Unmarshal, jsoncodec.New[testdata.Node]()},
+		{codec.MsgPack, msgpack.Marshal, msgpack.Unmarshal, msgpackcodec.New[testdata.Node]()},
+		{codec.CBOR, cbor.Marshal, cbor.Unmarshal, cborcodec.New[testdata.Node]()},
+	} {
+		for _, v := range []testdata.Node{*deep, tree} {
+			want, wantErr := tc.marshal(v)
+			got, gotErr := tc.codec.Marshal(v)
+			if (wantErr == nil) != (gotErr == nil) || !bytes.Equal(got, want) {
+				t.Fatalf("%s: codec Marshal differs: %v, library %v", tc.typ, gotErr, wantErr)
+			}
+			// CBOR limits nesting on decode
+			var a, b testdata.Node
+			wantErr = tc.unmarshal(want, &a)
+			gotErr = tc.codec.Unmarshal(want, &b)
+			if (wantErr == nil) != (gotErr == nil) || wantErr == nil && !reflect.DeepEqual(a, b) {
+				t.Fatalf("%s: codec Unmarshal differs: %v, library %v", tc.typ, gotErr, wantErr)
+			}
+		}
+	}
+
+	// encoding/json reports cycles, which generated code leaves to it
+	cyclic := &testdata.Node{Name: "loop"}
+	cyclic.Next = cyclic
+	if _, err := jsoncodec.New[testdata.Node]().Marshal(*cyclic); err == nil {
+		t.Error("expected an error for a cyclic value")
+	}
+}
+
+// TestDifferential_Options checks that codecs with non-default settings do
+// not use generated code
+func TestDifferential_Options(t *testing.T) {
+	v := newRandom(1, 1).order()
+
+	indented, err := jsoncodec.New[testdata.Order](jsoncodec.WithIndent("", "  ")).Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, _ := stdjson.MarshalIndent(v, "", "  ")
+	if !bytes.Equal(indented, want) {
+		t.Errorf("indented JSON differs:\n%s\nwant\n%s", indented, want)
+	}
+
+	compact, err := msgpackcodec.New[testdata.Order](msgpackcodec.WithCompactInts(true)).Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	enc.UseCompactInts(true)
+	if err := enc.Encode(v); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(compact, buf.Bytes()) {
+		t.Errorf("compact msgpack differs:\n%x\nwant\n%x", compact, buf.Bytes())
+	}
+
+	canonical, err := cborcodec.New[testdata.Order](cborcodec.WithCanonical()).Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	mode, _ := cbor.CanonicalEncOptions().EncMode()
+	want, _ = mode.Marshal(v)
+	if !bytes.Equal(canonical, want) {
+		t.Errorf("canonical CBOR differs:\n%x\nwant\n%x", canonical, want)
+	}
+
+	// Unknown fields are errors only with strict decoding, which the
+	// generated code does not do
+	data := []byte(`{"id":1,"unknown":true}`)
+	var o testdata.Order
+	if err := jsoncodec.New[testdata.Order](codec.WithStrict()).Unmarshal(data, &o); err == nil {
+		t.Error("expected strict JSON decoding to reject an unknown field")
+	}
+	if err := jsoncodec.New[testdata.Order]().Unmarshal(data, &o); err != nil || o.ID != 1 {
+		t.Errorf("default JSON decoding: %v, %+v", err, o)
+	}
+}
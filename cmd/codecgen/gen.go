@@ -0,0 +1,387 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	goformat "go/format"
+	"go/token"
+	"reflect"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// modulePath is the import path of the module whose packages generated
+// code uses
+const modulePath = "github.com/jeremyhahn/go-codec"
+
+// format writes the generated functions of one encoding
+type format interface {
+	// name is the name of the format in the -formats flag
+	name() string
+
+	// ident names the format in generated function names and is the
+	// codec.Type constant of its codec
+	ident() string
+
+	// tag returns how the format encodes a field with the given tag and Go
+	// name, reporting false if it skips the field
+	tag(tag reflect.StructTag, field string) (fieldTag, bool, error)
+
+	// writeAppend and writeParse write the functions encoding and
+	// decoding s
+	writeAppend(g *gen, s *structType)
+	writeParse(g *gen, s *structType)
+}
+
+// allFormats are the supported formats in the order they are generated
+var allFormats = []format{jsonFormat{}, msgpackFormat{}, cborFormat{}}
+
+// generate returns the formatted source of a file implementing formats for
+// the named types and the struct types they refer to. Each named type also
+// gets a codec type per format, which satisfies codec.OptimizedCodec.
+func generate(p *pkg, names []string, formats []format) ([]byte, error) {
+	r := newResolver(p, formats)
+	for _, name := range names {
+		spec, ok := p.types[name]
+		if !ok {
+			return nil, fmt.Errorf("type %s not found in package %s", name, p.name)
+		}
+		t, err := r.resolve(spec.Name)
+		if err != nil {
+			return nil, err
+		}
+		if t.kind != kindStruct {
+			return nil, r.errorf(spec.Pos(), "%s is not a struct type", name)
+		}
+		for _, f := range formats {
+			if other, ok := p.types[name+f.ident()]; ok {
+				return nil, r.errorf(other.Pos(), "%s%s is declared in package %s; it names the generated %s codec of %s", name, f.ident(), p.name, f.ident(), name)
+			}
+		}
+	}
+
+	g := &gen{imports: make(map[string]bool)}
+	for _, s := range r.order {
+		for _, f := range formats {
+			f.writeAppend(g, s)
+			f.writeParse(g, s)
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "%s\n\npackage %s\n\nimport (\n", generatedHeader, p.name)
+	if g.imports["strconv"] {
+		out.WriteString("\"strconv\"\n\n")
+	}
+	fmt.Fprintf(&out, "codec %q\n%q\n", modulePath, modulePath+"/pkg/codecgen")
+	for _, f := range formats {
+		fmt.Fprintf(&out, "%scodec %q\n", f.name(), modulePath+"/pkg/"+f.name())
+	}
+	out.WriteString(")\n\n")
+
+	out.WriteString("func init() {\n")
+	for _, s := range r.order {
+		for _, f := range formats {
+			fmt.Fprintf(&out, "codecgen.Register(codec.%s, funcs%s%s)\n", f.ident(), s.name, f.ident())
+		}
+	}
+	out.WriteString("}\n\n")
+
+	for _, name := range names {
+		for _, f := range formats {
+			writeCodec(&out, f, name)
+		}
+	}
+	for _, s := range r.order {
+		for _, f := range formats {
+			fmt.Fprintf(&out, `var funcs%[2]s%[1]s = codecgen.Funcs[%[2]s]{
+				Append: func(buf []byte, v %[2]s) ([]byte, error) {
+					return append%[2]s%[1]s(buf, &v, 0)
+				},
+				Parse: func(data []byte, v *%[2]s) error {
+					var r codecgen.%[1]sReader
+					r.Reset(data)
+					parse%[2]s%[1]s(&r, v)
+					return r.End()
+				},
+			}
+
+			`, f.ident(), s.name)
+		}
+	}
+	out.Write(g.out.Bytes())
+
+	src, err := goformat.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %v", err)
+	}
+	return src, nil
+}
+
+// writeCodec writes the codec type of format f for the named type, with
+// its constructor and a check that it satisfies codec.OptimizedCodec
+func writeCodec(out *bytes.Buffer, f format, name string) {
+	typ := name + f.ident()
+	constructor := "New" + typ
+	if !token.IsExported(name) {
+		r, size := utf8.DecodeRuneInString(name)
+		constructor = "new" + string(unicode.ToUpper(r)) + name[size:] + f.ident()
+	}
+	fmt.Fprintf(out, `// %[1]s is a %[2]s codec for %[3]s that calls the generated code
+		// directly, falling back to reflection for values and input it does not
+		// handle. It has the default settings of the %[2]s codec.
+		type %[1]s struct {
+			*codecgen.Codec[%[3]s]
+		}
+
+		// %[4]s returns a %[2]s codec for %[3]s
+		func %[4]s() %[1]s {
+			return %[1]s{codecgen.NewCodec(%[5]scodec.NewPool[%[3]s](), funcs%[3]s%[2]s)}
+		}
+
+		var _ codec.OptimizedCodec[%[3]s] = %[1]s{}
+
+		`, typ, f.ident(), name, constructor, f.name())
+}
+
+// gen accumulates generated functions
+type gen struct {
+	out     bytes.Buffer
+	body    bytes.Buffer
+	imports map[string]bool
+	vars    int
+	usesErr bool
+}
+
+// function writes a function with the given signature whose body emit
+// writes, declaring err first if the body assigns it
+func (g *gen) function(signature string, emit func()) {
+	g.body.Reset()
+	g.vars = 0
+	g.usesErr = false
+	emit()
+	fmt.Fprintf(&g.out, "%s {\n", signature)
+	if g.usesErr {
+		g.out.WriteString("var err error\n")
+	}
+	g.out.Write(g.body.Bytes())
+	g.out.WriteString("}\n\n")
+}
+
+// line writes a line of the function body
+func (g *gen) line(format string, args ...any) {
+	fmt.Fprintf(&g.body, format, args...)
+	g.body.WriteByte('\n')
+}
+
+// tmp returns a new variable name
+func (g *gen) tmp(prefix string) string {
+	g.vars++
+	return prefix + strconv.Itoa(g.vars)
+}
+
+// appendLiteral writes a statement appending b to buf
+func (g *gen) appendLiteral(b []byte) {
+	g.line("buf = append(buf, %s...)", literal(b))
+}
+
+// appendHead writes a statement appending head, the binary encoding of a
+// map or string header, followed by key
+func (g *gen) appendHead(head []byte, key string) {
+	var lit strings.Builder
+	lit.WriteByte('"')
+	for _, c := range head {
+		fmt.Fprintf(&lit, "\\x%02x", c)
+	}
+	quoted := strconv.Quote(key)
+	lit.WriteString(quoted[1:])
+	g.line("buf = append(buf, %s...)", lit.String())
+}
+
+// call writes a call of a generated append function that may fail
+func (g *gen) call(fn, x string) {
+	g.usesErr = true
+	g.line("if buf, err = %s(buf, %s, depth+1); err != nil {", fn, addr(x))
+	g.line("return buf, err")
+	g.line("}")
+}
+
+// enter writes the check that stops encoding of values nested too deep
+func (g *gen) enter() {
+	g.line("if depth > codecgen.MaxDepth {")
+	g.line("return buf, codecgen.ErrFallback")
+	g.line("}")
+}
+
+// literal returns a Go string literal holding b
+func literal(b []byte) string {
+	if s := string(b); strconv.CanBackquote(s) && !strings.Contains(s, "\\") {
+		return "`" + s + "`"
+	}
+	return strconv.Quote(string(b))
+}
+
+// addr returns the address of the addressable expression x
+func addr(x string) string {
+	if strings.HasPrefix(x, "(*") && strings.HasSuffix(x, ")") {
+		return x[2 : len(x)-1]
+	}
+	return "&" + x
+}
+
+// deref returns the expression for the value x points to
+func deref(x string) string {
+	return "(*" + x + ")"
+}
+
+// conv returns x, of type t, converted to the predeclared type to
+func conv(to, x string, t *goType) string {
+	if t.expr == to {
+		return x
+	}
+	return to + "(" + x + ")"
+}
+
+// as returns x, of the predeclared type from, converted to t
+func as(t *goType, from, x string) string {
+	if t.expr == from {
+		return x
+	}
+	return t.expr + "(" + x + ")"
+}
+
+// present returns the expression and type to encode for a field written
+// only when omitempty keeps it; a pointer is then known not to be nil
+func present(omit bool, x string, t *goType) (string, *goType) {
+	if omit && t.kind == kindPointer {
+		return deref(x), t.elem
+	}
+	return x, t
+}
+
+// omits reports whether format f may omit fld, with the given tag. The
+// msgpack and CBOR libraries consider a struct empty when all of its fields
+// are omitempty and empty; encoding/json never omits a struct.
+func omits(f format, fld *field, tag fieldTag) bool {
+	if !tag.omitEmpty {
+		return false
+	}
+	if fld.typ.kind != kindStruct {
+		return true
+	}
+	return f.name() != "json" && canBeEmpty(f, fld.typ.strct)
+}
+
+// canBeEmpty reports whether every field format f encodes in s may be
+// omitted
+func canBeEmpty(f format, s *structType) bool {
+	fields, tags := fieldsOf(f, s)
+	for i, fld := range fields {
+		if !omits(f, fld, tags[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// empty returns the condition under which format f omits x, of type t, if
+// it has omitempty
+func empty(f format, x string, t *goType) string {
+	switch t.kind {
+	case kindBool:
+		return "!" + x
+	case kindInt, kindUint, kindFloat:
+		return x + " == 0"
+	case kindString, kindBytes, kindSlice, kindMap:
+		return "len(" + x + ") == 0"
+	case kindPointer:
+		return x + " == nil"
+	}
+	fields, _ := fieldsOf(f, t.strct)
+	if len(fields) == 0 {
+		return "true"
+	}
+	var conds []string
+	for _, fld := range fields {
+		cond := empty(f, x+"."+fld.name, fld.typ)
+		if strings.Contains(cond, "||") {
+			cond = "(" + cond + ")"
+		}
+		conds = append(conds, cond)
+	}
+	return strings.Join(conds, " && ")
+}
+
+// nonEmpty is the negation of empty
+func nonEmpty(f format, x string, t *goType) string {
+	switch t.kind {
+	case kindBool:
+		return x
+	case kindInt, kindUint, kindFloat:
+		return x + " != 0"
+	case kindString, kindBytes, kindSlice, kindMap:
+		return "len(" + x + ") != 0"
+	case kindPointer:
+		return x + " != nil"
+	}
+	fields, _ := fieldsOf(f, t.strct)
+	if len(fields) == 0 {
+		return "false"
+	}
+	var conds []string
+	for _, fld := range fields {
+		cond := nonEmpty(f, x+"."+fld.name, fld.typ)
+		if strings.Contains(cond, "&&") {
+			cond = "(" + cond + ")"
+		}
+		conds = append(conds, cond)
+	}
+	return strings.Join(conds, " || ")
+}
+
+// fieldsOf returns the fields f encodes and their tags
+func fieldsOf(f format, s *structType) ([]*field, []fieldTag) {
+	var fields []*field
+	var tags []fieldTag
+	for _, fld := range s.fields {
+		if tag, ok := fld.tags[f.name()]; ok {
+			fields = append(fields, fld)
+			tags = append(tags, tag)
+		}
+	}
+	return fields, tags
+}
+
+// keyNames returns the keys of tags as Go string literals
+func keyNames(tags []fieldTag) string {
+	var names []string
+	for _, tag := range tags {
+		names = append(names, strconv.Quote(tag.key))
+	}
+	return strings.Join(names, ", ")
+}
+
+// appendMapHeader writes the map header of a msgpack or CBOR struct,
+// counting the fields omitempty keeps when any may be omitted, and returns
+// the bytes still to be written before the first key
+func appendMapHeader(g *gen, f format, fields []*field, tags []fieldTag, appendLen func([]byte, int) []byte) []byte {
+	omitted := false
+	for i, fld := range fields {
+		omitted = omitted || omits(f, fld, tags[i])
+	}
+	if !omitted {
+		return appendLen(nil, len(fields))
+	}
+	g.line("n := %d", len(fields))
+	for i, fld := range fields {
+		if omits(f, fld, tags[i]) {
+			g.line("if %s {", empty(f, "v."+fld.name, fld.typ))
+			g.line("n--")
+			g.line("}")
+		}
+	}
+	g.line("buf = codecgen.Append%sMapLen(buf, n)", f.ident())
+	return nil
+}
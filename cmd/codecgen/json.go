@@ -0,0 +1,267 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jeremyhahn/go-codec/pkg/codecgen"
+)
+
+// jsonFormat generates code matching encoding/json
+type jsonFormat struct{}
+
+func (jsonFormat) name() string  { return "json" }
+func (jsonFormat) ident() string { return "JSON" }
+
+// tag follows the json tag rules of encoding/json
+func (jsonFormat) tag(tag reflect.StructTag, field string) (fieldTag, bool, error) {
+	value := tag.Get("json")
+	if value == "-" {
+		return fieldTag{}, false, nil
+	}
+	name, opts, _ := strings.Cut(value, ",")
+	if !isValidJSONName(name) {
+		name = field
+	}
+	ft := fieldTag{key: name}
+	for _, opt := range strings.Split(opts, ",") {
+		switch opt {
+		case "omitempty":
+			ft.omitEmpty = true
+		case "string", "omitzero":
+			return fieldTag{}, false, fmt.Errorf("json option %q is not supported", opt)
+		}
+	}
+	return ft, true, nil
+}
+
+// isValidJSONName reports whether encoding/json accepts name from a tag
+func isValidJSONName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for _, c := range name {
+		switch {
+		case strings.ContainsRune("!#$%&()*+-./:;<=>?@[]^_{|}~ ", c):
+		case !('a' <= c && c <= 'z' || 'A' <= c && c <= 'Z' || '0' <= c && c <= '9' || c > 0x7f):
+			return false
+		}
+	}
+	return true
+}
+
+func (f jsonFormat) writeAppend(g *gen, s *structType) {
+	fields, tags := fieldsOf(f, s)
+	g.function(fmt.Sprintf("func append%sJSON(buf []byte, v *%s, depth int) ([]byte, error)", s.name, s.name), func() {
+		g.enter()
+		if len(fields) == 0 {
+			g.appendLiteral([]byte("{}"))
+			g.line("return buf, nil")
+			return
+		}
+		// Until a field that is always written, whether a comma is needed
+		// depends on what was written since start
+		const (
+			none = iota
+			maybe
+			some
+		)
+		written := none
+		if omits(f, fields[0], tags[0]) {
+			g.line("buf = append(buf, '{')")
+			if len(fields) > 1 {
+				g.line("start := len(buf)")
+			}
+		}
+		for i, fld := range fields {
+			x := "v." + fld.name
+			key := codecgen.AppendJSONString(nil, tags[i].key)
+			key = append(key, ':')
+			omit := omits(f, fld, tags[i])
+			if omit {
+				g.line("if %s {", nonEmpty(f, x, fld.typ))
+			}
+			switch {
+			case i == 0 && !omit:
+				g.appendLiteral(append([]byte{'{'}, key...))
+			case written == some:
+				g.appendLiteral(append([]byte{','}, key...))
+			case written == maybe:
+				g.line("if len(buf) > start {")
+				g.line("buf = append(buf, ',')")
+				g.line("}")
+				g.appendLiteral(key)
+			default:
+				g.appendLiteral(key)
+			}
+			vx, vt := present(omit, x, fld.typ)
+			f.appendValue(g, vx, vt)
+			if omit {
+				g.line("}")
+				if written == none {
+					written = maybe
+				}
+			} else {
+				written = some
+			}
+		}
+		g.line("buf = append(buf, '}')")
+		g.line("return buf, nil")
+	})
+}
+
+// appendValue writes the statements appending x, of type t
+func (jsonFormat) appendValue(g *gen, x string, t *goType) {
+	switch t.kind {
+	case kindBool:
+		g.imports["strconv"] = true
+		g.line("buf = strconv.AppendBool(buf, %s)", conv("bool", x, t))
+	case kindInt:
+		g.imports["strconv"] = true
+		g.line("buf = strconv.AppendInt(buf, %s, 10)", conv("int64", x, t))
+	case kindUint:
+		g.imports["strconv"] = true
+		g.line("buf = strconv.AppendUint(buf, %s, 10)", conv("uint64", x, t))
+	case kindFloat:
+		g.usesErr = true
+		g.line("if buf, err = codecgen.AppendJSONFloat(buf, %s, %d); err != nil {", conv("float64", x, t), t.bits)
+		g.line("return buf, err")
+		g.line("}")
+	case kindString:
+		g.line("buf = codecgen.AppendJSONString(buf, %s)", conv("string", x, t))
+	case kindBytes:
+		g.line("buf = codecgen.AppendJSONBytes(buf, %s)", x)
+	case kindSlice:
+		i := g.tmp("i")
+		g.line("if %s == nil {", x)
+		g.line("buf = append(buf, \"null\"...)")
+		g.line("} else {")
+		g.line("buf = append(buf, '[')")
+		g.line("for %s := range %s {", i, x)
+		g.line("if %s > 0 {", i)
+		g.line("buf = append(buf, ',')")
+		g.line("}")
+		jsonFormat{}.appendValue(g, x+"["+i+"]", t.elem)
+		g.line("}")
+		g.line("buf = append(buf, ']')")
+		g.line("}")
+	case kindMap:
+		i, k, e := g.tmp("i"), g.tmp("k"), g.tmp("e")
+		g.line("if %s == nil {", x)
+		g.line("buf = append(buf, \"null\"...)")
+		g.line("} else {")
+		g.line("buf = append(buf, '{')")
+		g.line("for %s, %s := range codecgen.SortedKeys(%s) {", i, k, x)
+		g.line("if %s > 0 {", i)
+		g.line("buf = append(buf, ',')")
+		g.line("}")
+		g.line("buf = append(codecgen.AppendJSONString(buf, %s), ':')", k)
+		g.line("%s := %s[%s]", e, x, k)
+		jsonFormat{}.appendValue(g, e, t.elem)
+		g.line("}")
+		g.line("buf = append(buf, '}')")
+		g.line("}")
+	case kindPointer:
+		g.line("if %s == nil {", x)
+		g.line("buf = append(buf, \"null\"...)")
+		g.line("} else {")
+		jsonFormat{}.appendValue(g, deref(x), t.elem)
+		g.line("}")
+	case kindStruct:
+		g.call("append"+t.strct.name+"JSON", x)
+	}
+}
+
+func (f jsonFormat) writeParse(g *gen, s *structType) {
+	fields, tags := fieldsOf(f, s)
+	g.function(fmt.Sprintf("func parse%sJSON(r *codecgen.JSONReader, v *%s)", s.name, s.name), func() {
+		g.line("if r.Null() || !r.BeginObject() {")
+		g.line("return")
+		g.line("}")
+		g.line("for r.NextMember() {")
+		g.line("switch key := r.Key(); string(key) {")
+		for i, fld := range fields {
+			g.line("case %q:", tags[i].key)
+			f.parseValue(g, "v."+fld.name, fld.typ)
+		}
+		g.line("default:")
+		if len(tags) == 0 {
+			g.line("r.Unknown(key)")
+		} else {
+			g.line("r.Unknown(key, %s)", keyNames(tags))
+		}
+		g.line("}")
+		g.line("}")
+	})
+}
+
+// parseValue writes the statements decoding into x, of type t
+func (jsonFormat) parseValue(g *gen, x string, t *goType) {
+	switch t.kind {
+	case kindBool:
+		g.line("if !r.Null() {")
+		g.line("%s = %s", x, as(t, "bool", "r.Bool()"))
+		g.line("}")
+	case kindInt:
+		g.line("if !r.Null() {")
+		g.line("%s = %s", x, as(t, "int64", fmt.Sprintf("r.Int(%d)", t.bits)))
+		g.line("}")
+	case kindUint:
+		g.line("if !r.Null() {")
+		g.line("%s = %s", x, as(t, "uint64", fmt.Sprintf("r.Uint(%d)", t.bits)))
+		g.line("}")
+	case kindFloat:
+		g.line("if !r.Null() {")
+		g.line("%s = %s", x, as(t, "float64", fmt.Sprintf("r.Float(%d)", t.bits)))
+		g.line("}")
+	case kindString:
+		g.line("if !r.Null() {")
+		g.line("%s = %s", x, as(t, "string", "r.String()"))
+		g.line("}")
+	case kindBytes:
+		g.line("if r.Null() {")
+		g.line("%s = nil", x)
+		g.line("} else {")
+		g.line("%s = r.Bytes()", x)
+		g.line("}")
+	case kindSlice:
+		n := g.tmp("n")
+		g.line("if r.Null() {")
+		g.line("%s = nil", x)
+		g.line("} else if r.BeginArray() {")
+		g.line("%s := 0", n)
+		g.line("for ; r.NextItem(); %s++ {", n)
+		g.line("%s = codecgen.ExtendJSON(%s, %s)", x, x, n)
+		jsonFormat{}.parseValue(g, x+"["+n+"]", t.elem)
+		g.line("}")
+		g.line("%s = codecgen.TruncateJSON(%s, %s)", x, x, n)
+		g.line("}")
+	case kindMap:
+		k, e := g.tmp("k"), g.tmp("e")
+		g.line("if r.Null() {")
+		g.line("%s = nil", x)
+		g.line("} else if r.BeginObject() {")
+		g.line("if %s == nil {", x)
+		g.line("%s = make(%s)", x, t.expr)
+		g.line("}")
+		g.line("for r.NextMember() {")
+		g.line("%s := string(r.Key())", k)
+		g.line("var %s %s", e, t.elem.expr)
+		jsonFormat{}.parseValue(g, e, t.elem)
+		g.line("%s[%s] = %s", x, k, e)
+		g.line("}")
+		g.line("}")
+	case kindPointer:
+		g.line("if r.Null() {")
+		g.line("%s = nil", x)
+		g.line("} else {")
+		g.line("if %s == nil {", x)
+		g.line("%s = new(%s)", x, t.elem.expr)
+		g.line("}")
+		jsonFormat{}.parseValue(g, deref(x), t.elem)
+		g.line("}")
+	case kindStruct:
+		g.line("parse%sJSON(r, %s)", t.strct.name, addr(x))
+	}
+}
@@ -0,0 +1,342 @@
+package main
+
+import (
+	"fmt"
+	"go/ast"
+	"go/build"
+	"go/parser"
+	"go/token"
+	"go/types"
+	"path/filepath"
+	"reflect"
+	"strconv"
+)
+
+// pkg holds the declarations of a package read without type-checking
+type pkg struct {
+	name    string
+	fset    *token.FileSet
+	types   map[string]*ast.TypeSpec
+	methods map[string][]string
+}
+
+// generatedHeader starts every file written by codecgen
+const generatedHeader = "// Code generated by codecgen; DO NOT EDIT."
+
+// load reads the Go files of the package in dir that the current build
+// context selects, skipping tests and files written by codecgen, which a
+// new run replaces
+func load(dir string) (*pkg, error) {
+	bp, err := build.ImportDir(dir, 0)
+	if err != nil {
+		return nil, err
+	}
+	p := &pkg{
+		name:    bp.Name,
+		fset:    token.NewFileSet(),
+		types:   make(map[string]*ast.TypeSpec),
+		methods: make(map[string][]string),
+	}
+	for _, name := range bp.GoFiles {
+		f, err := parser.ParseFile(p.fset, filepath.Join(dir, name), nil, parser.SkipObjectResolution|parser.ParseComments)
+		if err != nil {
+			return nil, err
+		}
+		if len(f.Comments) > 0 && f.Comments[0].Pos() < f.Package && f.Comments[0].List[0].Text == generatedHeader {
+			continue
+		}
+		for _, decl := range f.Decls {
+			switch d := decl.(type) {
+			case *ast.GenDecl:
+				for _, spec := range d.Specs {
+					if ts, ok := spec.(*ast.TypeSpec); ok {
+						p.types[ts.Name.Name] = ts
+					}
+				}
+			case *ast.FuncDecl:
+				if d.Recv != nil && len(d.Recv.List) == 1 {
+					recv := receiverName(d.Recv.List[0].Type)
+					p.methods[recv] = append(p.methods[recv], d.Name.Name)
+				}
+			}
+		}
+	}
+	return p, nil
+}
+
+// receiverName returns the name of the type of a method receiver
+func receiverName(e ast.Expr) string {
+	for {
+		switch x := e.(type) {
+		case *ast.StarExpr:
+			e = x.X
+		case *ast.ParenExpr:
+			e = x.X
+		case *ast.IndexExpr:
+			e = x.X
+		case *ast.IndexListExpr:
+			e = x.X
+		case *ast.Ident:
+			return x.Name
+		default:
+			return ""
+		}
+	}
+}
+
+// kind classifies the types generated code handles
+type kind int
+
+const (
+	kindBool kind = iota
+	kindInt
+	kindUint
+	kindFloat
+	kindString
+	kindBytes
+	kindSlice
+	kindMap
+	kindPointer
+	kindStruct
+)
+
+// goType describes a type generated code handles
+type goType struct {
+	kind  kind
+	expr  string // Go source of the type
+	basic string // predeclared type underlying bool, number and string kinds
+	bits  int    // size of number kinds, 0 for int and uint
+	elem  *goType
+	strct *structType
+}
+
+// structType is a struct type with the fields each format encodes
+type structType struct {
+	name   string
+	fields []*field
+}
+
+// field is an exported struct field
+type field struct {
+	name string
+	typ  *goType
+	tags map[string]fieldTag // by format name; absent when a format skips it
+}
+
+// fieldTag is how a format encodes a field
+type fieldTag struct {
+	key       string
+	omitEmpty bool
+}
+
+// basicTypes maps predeclared type names to their kinds and sizes
+var basicTypes = map[string]goType{
+	"bool":    {kind: kindBool, basic: "bool"},
+	"string":  {kind: kindString, basic: "string"},
+	"int":     {kind: kindInt, basic: "int"},
+	"int8":    {kind: kindInt, basic: "int8", bits: 8},
+	"int16":   {kind: kindInt, basic: "int16", bits: 16},
+	"int32":   {kind: kindInt, basic: "int32", bits: 32},
+	"rune":    {kind: kindInt, basic: "int32", bits: 32},
+	"int64":   {kind: kindInt, basic: "int64", bits: 64},
+	"uint":    {kind: kindUint, basic: "uint"},
+	"uint8":   {kind: kindUint, basic: "uint8", bits: 8},
+	"byte":    {kind: kindUint, basic: "uint8", bits: 8},
+	"uint16":  {kind: kindUint, basic: "uint16", bits: 16},
+	"uint32":  {kind: kindUint, basic: "uint32", bits: 32},
+	"uint64":  {kind: kindUint, basic: "uint64", bits: 64},
+	"float32": {kind: kindFloat, basic: "float32", bits: 32},
+	"float64": {kind: kindFloat, basic: "float64", bits: 64},
+}
+
+// marshalMethods are methods through which a library would marshal a type
+// or decide that it is empty, bypassing what generated code does
+var marshalMethods = []string{
+	"MarshalJSON", "UnmarshalJSON", "MarshalText", "UnmarshalText",
+	"MarshalBinary", "UnmarshalBinary", "MarshalCBOR", "UnmarshalCBOR",
+	"MarshalMsgpack", "UnmarshalMsgpack", "EncodeMsgpack", "DecodeMsgpack",
+	"IsZero",
+}
+
+// resolver builds goTypes from the declarations of a package
+type resolver struct {
+	pkg       *pkg
+	formats   []format
+	structs   map[string]*structType
+	order     []*structType
+	resolving map[string]bool
+}
+
+func newResolver(p *pkg, formats []format) *resolver {
+	return &resolver{
+		pkg:       p,
+		formats:   formats,
+		structs:   make(map[string]*structType),
+		resolving: make(map[string]bool),
+	}
+}
+
+func (r *resolver) errorf(pos token.Pos, format string, args ...any) error {
+	return fmt.Errorf("%s: %s", r.pkg.fset.Position(pos), fmt.Sprintf(format, args...))
+}
+
+// resolve returns the goType of a type expression
+func (r *resolver) resolve(e ast.Expr) (*goType, error) {
+	switch x := e.(type) {
+	case *ast.Ident:
+		if _, ok := r.pkg.types[x.Name]; ok {
+			return r.named(x)
+		}
+		if t, ok := basicTypes[x.Name]; ok {
+			t.expr = t.basic
+			return &t, nil
+		}
+	case *ast.ParenExpr:
+		return r.resolve(x.X)
+	case *ast.StarExpr:
+		elem, err := r.resolve(x.X)
+		if err != nil {
+			return nil, err
+		}
+		if elem.kind == kindPointer {
+			return nil, r.errorf(x.Pos(), "pointers to pointers are not supported")
+		}
+		return &goType{kind: kindPointer, expr: "*" + elem.expr, elem: elem}, nil
+	case *ast.ArrayType:
+		if x.Len != nil {
+			return nil, r.errorf(x.Pos(), "arrays are not supported; use a slice")
+		}
+		elem, err := r.resolve(x.Elt)
+		if err != nil {
+			return nil, err
+		}
+		if elem.basic == "uint8" {
+			if elem.expr != "uint8" {
+				return nil, r.errorf(x.Pos(), "slices of %s are not supported", elem.expr)
+			}
+			return &goType{kind: kindBytes, expr: "[]byte"}, nil
+		}
+		return &goType{kind: kindSlice, expr: "[]" + elem.expr, elem: elem}, nil
+	case *ast.MapType:
+		if key, ok := x.Key.(*ast.Ident); !ok || key.Name != "string" || r.pkg.types["string"] != nil {
+			return nil, r.errorf(x.Pos(), "map keys must be string")
+		}
+		elem, err := r.resolve(x.Value)
+		if err != nil {
+			return nil, err
+		}
+		return &goType{kind: kindMap, expr: "map[string]" + elem.expr, elem: elem}, nil
+	case *ast.SelectorExpr:
+		return nil, r.errorf(x.Pos(), "%s: types of other packages are not supported", types.ExprString(x))
+	}
+	return nil, r.errorf(e.Pos(), "type %s is not supported", types.ExprString(e))
+}
+
+// named returns the goType of a type declared in the package
+func (r *resolver) named(id *ast.Ident) (*goType, error) {
+	name := id.Name
+	if s, ok := r.structs[name]; ok {
+		return &goType{kind: kindStruct, expr: name, strct: s}, nil
+	}
+	spec := r.pkg.types[name]
+	if spec.TypeParams != nil {
+		return nil, r.errorf(id.Pos(), "generic type %s is not supported", name)
+	}
+	if spec.Assign.IsValid() {
+		return r.resolve(spec.Type)
+	}
+	for _, m := range r.pkg.methods[name] {
+		for _, denied := range marshalMethods {
+			if m == denied {
+				return nil, r.errorf(id.Pos(), "type %s has method %s, which generated code would bypass", name, m)
+			}
+		}
+	}
+	if st, ok := spec.Type.(*ast.StructType); ok {
+		return r.structType(name, st)
+	}
+
+	if r.resolving[name] {
+		return nil, r.errorf(id.Pos(), "recursive type %s is not supported", name)
+	}
+	r.resolving[name] = true
+	defer delete(r.resolving, name)
+	u, err := r.resolve(spec.Type)
+	if err != nil {
+		return nil, err
+	}
+	if u.kind == kindPointer || u.kind == kindStruct {
+		return nil, r.errorf(id.Pos(), "type %s: defined %s types are not supported", name, kindName(u.kind))
+	}
+	t := *u
+	t.expr = name
+	return &t, nil
+}
+
+// structType returns the goType of a struct type declared in the package,
+// resolving its fields
+func (r *resolver) structType(name string, st *ast.StructType) (*goType, error) {
+	s := &structType{name: name}
+	r.structs[name] = s
+	r.order = append(r.order, s)
+	keys := make(map[string]map[string]bool)
+	for _, f := range st.Fields.List {
+		if len(f.Names) == 0 {
+			return nil, r.errorf(f.Pos(), "%s: embedded field %s is not supported", name, types.ExprString(f.Type))
+		}
+		var tag reflect.StructTag
+		if f.Tag != nil {
+			unquoted, err := strconv.Unquote(f.Tag.Value)
+			if err != nil {
+				return nil, r.errorf(f.Tag.Pos(), "%s: malformed tag", name)
+			}
+			tag = reflect.StructTag(unquoted)
+		}
+		for _, id := range f.Names {
+			if id.Name == "_msgpack" || id.Name == "_" && tag.Get("cbor") != "" {
+				return nil, r.errorf(id.Pos(), "%s: struct options in field %s are not supported", name, id.Name)
+			}
+			if !id.IsExported() {
+				continue
+			}
+			fld := &field{name: id.Name, tags: make(map[string]fieldTag)}
+			for _, fm := range r.formats {
+				ft, ok, err := fm.tag(tag, id.Name)
+				if err != nil {
+					return nil, r.errorf(id.Pos(), "%s.%s: %v", name, id.Name, err)
+				}
+				if !ok {
+					continue
+				}
+				if keys[fm.name()] == nil {
+					keys[fm.name()] = make(map[string]bool)
+				}
+				if keys[fm.name()][ft.key] {
+					return nil, r.errorf(id.Pos(), "%s.%s: duplicate %s key %q", name, id.Name, fm.name(), ft.key)
+				}
+				keys[fm.name()][ft.key] = true
+				fld.tags[fm.name()] = ft
+			}
+			if len(fld.tags) == 0 {
+				continue
+			}
+			t, err := r.resolve(f.Type)
+			if err != nil {
+				return nil, fmt.Errorf("%s.%s: %w", name, id.Name, err)
+			}
+			fld.typ = t
+			s.fields = append(s.fields, fld)
+		}
+	}
+	return &goType{kind: kindStruct, expr: name, strct: s}, nil
+}
+
+func kindName(k kind) string {
+	switch k {
+	case kindPointer:
+		return "pointer"
+	case kindStruct:
+		return "struct"
+	}
+	return "other"
+}
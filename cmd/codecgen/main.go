@@ -0,0 +1,120 @@
+// Command codecgen writes type-specific JSON, MessagePack and CBOR
+// marshaling code for Go struct types, so that codecs with default
+// settings skip reflection. The generated file registers itself with
+// pkg/codecgen; the codecs find it there and fall back to reflection for
+// types without generated code, for non-default settings and for input the
+// generated code does not handle, with identical results. For each named
+// type and format it also declares a codec type, such as OrderJSON with
+// the constructor NewOrderJSON, that implements codec.OptimizedCodec and
+// calls the generated code directly.
+//
+// Usage:
+//
+//	codecgen -type Name[,Name...] [-formats json,msgpack,cbor] [-output file] [dir]
+//
+// Typically it is run by go generate from a comment next to the types:
+//
+//	//go:generate go run github.com/jeremyhahn/go-codec/cmd/codecgen -type Order
+//
+// The package in dir, by default the current directory, is read without
+// type-checking. Struct types declared in it that the named types refer to
+// are generated as well. Fields may be booleans, numbers, strings, []byte,
+// slices, maps with string keys, pointers and struct types of the same
+// package, or types of the package defined from these. Anything else, such
+// as embedded fields, interfaces, arrays, types of other packages and
+// types with custom marshaling methods, is reported as an error rather
+// than generated incorrectly.
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Exit codes: 1 for a type that cannot be generated, 2 for a usage error
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitError   = 2
+)
+
+func main() {
+	os.Exit(run(os.Args[1:], os.Stderr))
+}
+
+// run generates code as directed by args and returns the exit code
+func run(args []string, stderr io.Writer) int {
+	fs := flag.NewFlagSet("codecgen", flag.ContinueOnError)
+	fs.SetOutput(stderr)
+	typeNames := fs.String("type", "", "comma-separated list of struct type `names`; required")
+	formatNames := fs.String("formats", "json,msgpack,cbor", "comma-separated list of `formats` to generate")
+	output := fs.String("output", "", "output `file` (default <type>_codecgen.go in dir)")
+	fs.Usage = func() {
+		fmt.Fprintln(stderr, "usage: codecgen -type Name[,Name...] [-formats json,msgpack,cbor] [-output file] [dir]")
+		fs.PrintDefaults()
+	}
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK
+		}
+		return exitError
+	}
+	if *typeNames == "" || fs.NArg() > 1 {
+		fs.Usage()
+		return exitError
+	}
+	formats, err := parseFormats(*formatNames)
+	if err != nil {
+		fmt.Fprintf(stderr, "codecgen: %v\n", err)
+		return exitError
+	}
+	dir := "."
+	if fs.NArg() == 1 {
+		dir = fs.Arg(0)
+	}
+	names := strings.Split(*typeNames, ",")
+	if *output == "" {
+		*output = filepath.Join(dir, strings.ToLower(names[0])+"_codecgen.go")
+	}
+
+	pkg, err := load(dir)
+	if err != nil {
+		fmt.Fprintf(stderr, "codecgen: %v\n", err)
+		return exitError
+	}
+	src, err := generate(pkg, names, formats)
+	if err != nil {
+		fmt.Fprintf(stderr, "codecgen: %v\n", err)
+		return exitFailure
+	}
+	if err := os.WriteFile(*output, src, 0o644); err != nil {
+		fmt.Fprintf(stderr, "codecgen: %v\n", err)
+		return exitError
+	}
+	return exitOK
+}
+
+// parseFormats returns the formats named in a comma-separated list, in
+// the order of allFormats
+func parseFormats(list string) ([]format, error) {
+	want := make(map[string]bool)
+	for _, name := range strings.Split(list, ",") {
+		want[strings.TrimSpace(name)] = true
+	}
+	var formats []format
+	for _, f := range allFormats {
+		if want[f.name()] {
+			formats = append(formats, f)
+			delete(want, f.name())
+		}
+	}
+	for name := range want {
+		return nil, fmt.Errorf("unknown format %q", name)
+	}
+	return formats, nil
+}
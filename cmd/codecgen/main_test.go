@@ -0,0 +1,135 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// runCodecgen runs the command with args, returning its exit code and stderr
+func runCodecgen(t *testing.T, args ...string) (int, string) {
+	t.Helper()
+	var stderr bytes.Buffer
+	code := run(args, &stderr)
+	return code, stderr.String()
+}
+
+// writePackage writes a package holding src to a temporary directory and
+// returns the directory
+func writePackage(t *testing.T, src string) string {
+	t.Helper()
+	dir := t.TempDir()
+	if err := os.WriteFile(filepath.Join(dir, "types.go"), []byte("package types\n\n"+src), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return dir
+}
+
+func TestGenerate_Golden(t *testing.T) {
+	p, err := load("testdata")
+	if err != nil {
+		t.Fatal(err)
+	}
+	src, err := generate(p, []string{"Order", "Node", "Flag", "Empty"}, allFormats)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want, err := os.ReadFile(filepath.Join("testdata", "order_codecgen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(src, want) {
+		t.Error("testdata/order_codecgen.go is out of date; run go generate ./cmd/codecgen/testdata")
+	}
+}
+
+func TestRun_Output(t *testing.T) {
+	dir := writePackage(t, "type Point struct {\n\tX int `json:\"x\"`\n\tY int\n}\n")
+	if code, stderr := runCodecgen(t, "-type", "Point", "-formats", "json", dir); code != exitOK {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	src, err := os.ReadFile(filepath.Join(dir, "point_codecgen.go"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{
+		"package types", "func appendPointJSON(", "func parsePointJSON(",
+		"type PointJSON struct", "func NewPointJSON() PointJSON", "var _ codec.OptimizedCodec[Point] = PointJSON{}",
+	} {
+		if !strings.Contains(string(src), want) {
+			t.Errorf("generated code lacks %q", want)
+		}
+	}
+	if strings.Contains(string(src), "MsgPack") || strings.Contains(string(src), "CBOR") {
+		t.Error("generated code has formats that were not requested")
+	}
+
+	// Unexported types get unexported constructors
+	unexported := writePackage(t, "type point struct{ X int }\n")
+	if code, stderr := runCodecgen(t, "-type", "point", "-formats", "cbor", unexported); code != exitOK {
+		t.Fatalf("exit %d: %s", code, stderr)
+	}
+	if src, _ := os.ReadFile(filepath.Join(unexported, "point_codecgen.go")); !strings.Contains(string(src), "func newPointCBOR() pointCBOR") {
+		t.Error("generated code lacks newPointCBOR")
+	}
+
+	output := filepath.Join(t.TempDir(), "out.go")
+	if code, stderr := runCodecgen(t, "-type", "Point", "-output", output, dir); code != exitOK {
+		t.Fatalf("-output: exit %d: %s", code, stderr)
+	}
+	if _, err := os.Stat(output); err != nil {
+		t.Error(err)
+	}
+}
+
+func TestRun_Usage(t *testing.T) {
+	dir := writePackage(t, "type Point struct{ X int }\n")
+	for _, tc := range []struct {
+		args []string
+		want string
+	}{
+		{nil, "usage:"},
+		{[]string{"-type", "Point", dir, dir}, "usage:"},
+		{[]string{"-type", "Point", "-formats", "json,xml", dir}, `unknown format "xml"`},
+		{[]string{"-type", "Point", filepath.Join(dir, "missing")}, "missing"},
+	} {
+		if code, stderr := runCodecgen(t, tc.args...); code != exitError || !strings.Contains(stderr, tc.want) {
+			t.Errorf("%q: exit %d, stderr %q", tc.args, code, stderr)
+		}
+	}
+	if code, _ := runCodecgen(t, "-h"); code != exitOK {
+		t.Errorf("-h: exit %d", code)
+	}
+}
+
+func TestRun_Unsupported(t *testing.T) {
+	for _, tc := range []struct {
+		src  string
+		want string
+	}{
+		{"type T struct{ X int }", "type Missing not found"},
+		{"type Missing int", "Missing is not a struct type"},
+		{"type Missing struct{ A [2]int }", "arrays are not supported"},
+		{"type Missing struct{ M map[int]string }", "map keys must be string"},
+		{"type Missing struct{ P **int }", "pointers to pointers"},
+		{"type Missing struct{ V any }", "type any is not supported"},
+		{"type Missing struct{ T time.Time }", "types of other packages"},
+		{"type Missing struct{ T }\ntype T struct{}", "embedded field"},
+		{"type Missing struct{ X int `json:\"x,string\"` }", `json option "string"`},
+		{"type Missing struct{ X int `cbor:\"1,keyasint\"` }", `cbor option "keyasint"`},
+		{"type Missing struct{ X, Y int `json:\"x\"` }", `duplicate json key "x"`},
+		{"type Missing struct{ B []B }\ntype B byte", "slices of B"},
+		{"type Missing struct{ L L }\ntype L []L", "recursive type L"},
+		{"type Missing[T any] struct{ X T }", "generic type Missing"},
+		{"type Missing struct{ X int }\n\nfunc (Missing) MarshalJSON() ([]byte, error) { return nil, nil }", "method MarshalJSON"},
+		{"type Missing struct{ _msgpack struct{} `msgpack:\",as_array\"` }", "struct options"},
+		{"type Missing struct{ X int }\ntype MissingCBOR int", "MissingCBOR is declared"},
+	} {
+		dir := writePackage(t, tc.src)
+		if code, stderr := runCodecgen(t, "-type", "Missing", dir); code != exitFailure || !strings.Contains(stderr, tc.want) {
+			t.Errorf("%s: exit %d, stderr %q, want %q", tc.src, code, stderr, tc.want)
+		}
+	}
+}
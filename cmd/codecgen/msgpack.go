@@ -0,0 +1,195 @@
+package main
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	"github.com/jeremyhahn/go-codec/pkg/codecgen"
+)
+
+// msgpackFormat generates code matching msgpack.Marshal and Unmarshal
+type msgpackFormat struct{}
+
+func (msgpackFormat) name() string  { return "msgpack" }
+func (msgpackFormat) ident() string { return "MsgPack" }
+
+// tag follows the msgpack tag rules of the msgpack library, which ignores
+// json tags by default
+func (msgpackFormat) tag(tag reflect.StructTag, field string) (fieldTag, bool, error) {
+	name, opts, _ := strings.Cut(tag.Get("msgpack"), ",")
+	if name == "-" {
+		return fieldTag{}, false, nil
+	}
+	if name == "" {
+		name = field
+	}
+	ft := fieldTag{key: name}
+	if opts != "" {
+		for _, opt := range strings.Split(opts, ",") {
+			if opt != "omitempty" {
+				return fieldTag{}, false, fmt.Errorf("msgpack option %q is not supported", opt)
+			}
+			ft.omitEmpty = true
+		}
+	}
+	return ft, true, nil
+}
+
+func (f msgpackFormat) writeAppend(g *gen, s *structType) {
+	fields, tags := fieldsOf(f, s)
+	g.function(fmt.Sprintf("func append%sMsgPack(buf []byte, v *%s, depth int) ([]byte, error)", s.name, s.name), func() {
+		g.enter()
+		pending := appendMapHeader(g, f, fields, tags, codecgen.AppendMsgPackMapLen)
+		for i, fld := range fields {
+			x := "v." + fld.name
+			key := tags[i].key
+			enc := codecgen.AppendMsgPackString(pending, key)
+			omit := omits(f, fld, tags[i])
+			if omit {
+				g.line("if %s {", nonEmpty(f, x, fld.typ))
+			}
+			g.appendHead(enc[:len(enc)-len(key)], key)
+			pending = nil
+			vx, vt := present(omit, x, fld.typ)
+			f.appendValue(g, vx, vt)
+			if omit {
+				g.line("}")
+			}
+		}
+		if pending != nil {
+			g.appendHead(pending, "")
+		}
+		g.line("return buf, nil")
+	})
+}
+
+// appendValue writes the statements appending x, of type t
+func (msgpackFormat) appendValue(g *gen, x string, t *goType) {
+	switch t.kind {
+	case kindBool:
+		g.line("buf = codecgen.AppendMsgPackBool(buf, %s)", conv("bool", x, t))
+	case kindInt:
+		g.line("buf = codecgen.AppendMsgPackInt(buf, %s, %d)", conv("int64", x, t), t.bits)
+	case kindUint:
+		g.line("buf = codecgen.AppendMsgPackUint(buf, %s, %d)", conv("uint64", x, t), t.bits)
+	case kindFloat:
+		g.line("buf = codecgen.AppendMsgPackFloat%d(buf, %s)", t.bits, conv(t.basic, x, t))
+	case kindString:
+		g.line("buf = codecgen.AppendMsgPackString(buf, %s)", conv("string", x, t))
+	case kindBytes:
+		g.line("buf = codecgen.AppendMsgPackBytes(buf, %s)", x)
+	case kindSlice:
+		i := g.tmp("i")
+		g.line("if %s == nil {", x)
+		g.line("buf = codecgen.AppendMsgPackNil(buf)")
+		g.line("} else {")
+		g.line("buf = codecgen.AppendMsgPackArrayLen(buf, len(%s))", x)
+		g.line("for %s := range %s {", i, x)
+		msgpackFormat{}.appendValue(g, x+"["+i+"]", t.elem)
+		g.line("}")
+		g.line("}")
+	case kindMap:
+		k, e := g.tmp("k"), g.tmp("e")
+		g.line("if %s == nil {", x)
+		g.line("buf = codecgen.AppendMsgPackNil(buf)")
+		g.line("} else {")
+		g.line("buf = codecgen.AppendMsgPackMapLen(buf, len(%s))", x)
+		g.line("for _, %s := range codecgen.SortedKeys(%s) {", k, x)
+		g.line("buf = codecgen.AppendMsgPackString(buf, %s)", k)
+		g.line("%s := %s[%s]", e, x, k)
+		msgpackFormat{}.appendValue(g, e, t.elem)
+		g.line("}")
+		g.line("}")
+	case kindPointer:
+		g.line("if %s == nil {", x)
+		g.line("buf = codecgen.AppendMsgPackNil(buf)")
+		g.line("} else {")
+		msgpackFormat{}.appendValue(g, deref(x), t.elem)
+		g.line("}")
+	case kindStruct:
+		g.call("append"+t.strct.name+"MsgPack", x)
+	}
+}
+
+func (f msgpackFormat) writeParse(g *gen, s *structType) {
+	fields, tags := fieldsOf(f, s)
+	g.function(fmt.Sprintf("func parse%sMsgPack(r *codecgen.MsgPackReader, v *%s)", s.name, s.name), func() {
+		g.line("if r.Nil() {")
+		g.line("*v = %s{}", s.name)
+		g.line("return")
+		g.line("}")
+		g.line("for n := r.MapLen(); n > 0; n-- {")
+		g.line("switch string(r.Key()) {")
+		for i, fld := range fields {
+			g.line("case %q:", tags[i].key)
+			f.parseValue(g, "v."+fld.name, fld.typ)
+		}
+		g.line("default:")
+		g.line("r.Skip()")
+		g.line("}")
+		g.line("}")
+	})
+}
+
+// parseValue writes the statements decoding into x, of type t
+func (msgpackFormat) parseValue(g *gen, x string, t *goType) {
+	switch t.kind {
+	case kindBool:
+		g.line("%s = %s", x, as(t, "bool", "r.Bool()"))
+	case kindInt:
+		g.line("%s = %s", x, as(t, "int64", "r.Int()"))
+	case kindUint:
+		g.line("%s = %s", x, as(t, "uint64", "r.Uint()"))
+	case kindFloat:
+		g.line("%s = %s", x, as(t, t.basic, fmt.Sprintf("r.Float%d()", t.bits)))
+	case kindString:
+		g.line("%s = %s", x, as(t, "string", "r.String()"))
+	case kindBytes:
+		g.line("%s = r.Bytes(%s)", x, x)
+	case kindSlice:
+		i := g.tmp("i")
+		if t.elem.expr == "string" {
+			// The library decodes []string in place and leaves it
+			// unchanged for nil
+			g.line("if !r.Nil() {")
+			g.line("%s = codecgen.ResizeMsgPackStrings(%s, r.ArrayLen())", x, x)
+		} else {
+			g.line("if r.Nil() {")
+			g.line("%s = nil", x)
+			g.line("} else {")
+			g.line("%s = codecgen.ResizeMsgPack(%s, r.ArrayLen())", x, x)
+		}
+		g.line("for %s := range %s {", i, x)
+		msgpackFormat{}.parseValue(g, x+"["+i+"]", t.elem)
+		g.line("}")
+		g.line("}")
+	case kindMap:
+		n, k, e := g.tmp("n"), g.tmp("k"), g.tmp("e")
+		g.line("if r.Nil() {")
+		g.line("%s = nil", x)
+		g.line("} else {")
+		g.line("%s := r.MapLen()", n)
+		g.line("if %s == nil {", x)
+		g.line("%s = make(%s, %s)", x, t.expr, n)
+		g.line("}")
+		g.line("for ; %s > 0; %s-- {", n, n)
+		g.line("%s := r.String()", k)
+		g.line("var %s %s", e, t.elem.expr)
+		msgpackFormat{}.parseValue(g, e, t.elem)
+		g.line("%s[%s] = %s", x, k, e)
+		g.line("}")
+		g.line("}")
+	case kindPointer:
+		g.line("if r.Nil() {")
+		g.line("%s = nil", x)
+		g.line("} else {")
+		g.line("if %s == nil {", x)
+		g.line("%s = new(%s)", x, t.elem.expr)
+		g.line("}")
+		msgpackFormat{}.parseValue(g, deref(x), t.elem)
+		g.line("}")
+	case kindStruct:
+		g.line("parse%sMsgPack(r, %s)", t.strct.name, addr(x))
+	}
+}
@@ -0,0 +1,2169 @@
+// Code generated by codecgen; DO NOT EDIT.
+
+package testdata
+
+import (
+	"strconv"
+
+	codec "github.com/jeremyhahn/go-codec"
+	cborcodec "github.com/jeremyhahn/go-codec/pkg/cbor"
+	"github.com/jeremyhahn/go-codec/pkg/codecgen"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+	msgpackcodec "github.com/jeremyhahn/go-codec/pkg/msgpack"
+)
+
+func init() {
+	codecgen.Register(codec.JSON, funcsOrderJSON)
+	codecgen.Register(codec.MsgPack, funcsOrderMsgPack)
+	codecgen.Register(codec.CBOR, funcsOrderCBOR)
+	codecgen.Register(codec.JSON, funcsLineJSON)
+	codecgen.Register(codec.MsgPack, funcsLineMsgPack)
+	codecgen.Register(codec.CBOR, funcsLineCBOR)
+	codecgen.Register(codec.JSON, funcsAddressJSON)
+	codecgen.Register(codec.MsgPack, funcsAddressMsgPack)
+	codecgen.Register(codec.CBOR, funcsAddressCBOR)
+	codecgen.Register(codec.JSON, funcsNodeJSON)
+	codecgen.Register(codec.MsgPack, funcsNodeMsgPack)
+	codecgen.Register(codec.CBOR, funcsNodeCBOR)
+	codecgen.Register(codec.JSON, funcsFlagJSON)
+	codecgen.Register(codec.MsgPack, funcsFlagMsgPack)
+	codecgen.Register(codec.CBOR, funcsFlagCBOR)
+	codecgen.Register(codec.JSON, funcsEmptyJSON)
+	codecgen.Register(codec.MsgPack, funcsEmptyMsgPack)
+	codecgen.Register(codec.CBOR, funcsEmptyCBOR)
+}
+
+// OrderJSON is a JSON codec for Order that calls the generated code
+// directly, falling back to reflection for values and input it does not
+// handle. It has the default settings of the JSON codec.
+type OrderJSON struct {
+	*codecgen.Codec[Order]
+}
+
+// NewOrderJSON returns a JSON codec for Order
+func NewOrderJSON() OrderJSON {
+	return OrderJSON{codecgen.NewCodec(jsoncodec.NewPool[Order](), funcsOrderJSON)}
+}
+
+var _ codec.OptimizedCodec[Order] = OrderJSON{}
+
+// OrderMsgPack is a MsgPack codec for Order that calls the generated code
+// directly, falling back to reflection for values and input it does not
+// handle. It has the default settings of the MsgPack codec.
+type OrderMsgPack struct {
+	*codecgen.Codec[Order]
+}
+
+// NewOrderMsgPack returns a MsgPack codec for Order
+func NewOrderMsgPack() OrderMsgPack {
+	return OrderMsgPack{codecgen.NewCodec(msgpackcodec.NewPool[Order](), funcsOrderMsgPack)}
+}
+
+var _ codec.OptimizedCodec[Order] = OrderMsgPack{}
+
+// OrderCBOR is a CBOR codec for Order that calls the generated code
+// directly, falling back to reflection for values and input it does not
+// handle. It has the default settings of the CBOR codec.
+type OrderCBOR struct {
+	*codecgen.Codec[Order]
+}
+
+// NewOrderCBOR returns a CBOR codec for Order
+func NewOrderCBOR() OrderCBOR {
+	return OrderCBOR{codecgen.NewCodec(cborcodec.NewPool[Order](), funcsOrderCBOR)}
+}
+
+var _ codec.OptimizedCodec[Order] = OrderCBOR{}
+
+// NodeJSON is a JSON codec for Node that calls the generated code
+// directly, falling back to reflection for values and input it does not
+// handle. It has the default settings of the JSON codec.
+type NodeJSON struct {
+	*codecgen.Codec[Node]
+}
+
+// NewNodeJSON returns a JSON codec for Node
+func NewNodeJSON() NodeJSON {
+	return NodeJSON{codecgen.NewCodec(jsoncodec.NewPool[Node](), funcsNodeJSON)}
+}
+
+var _ codec.OptimizedCodec[Node] = NodeJSON{}
+
+// NodeMsgPack is a MsgPack codec for Node that calls the generated code
+// directly, falling back to reflection for values and input it does not
+// handle. It has the default settings of the MsgPack codec.
+type NodeMsgPack struct {
+	*codecgen.Codec[Node]
+}
+
+// NewNodeMsgPack returns a MsgPack codec for Node
+func NewNodeMsgPack() NodeMsgPack {
+	return NodeMsgPack{codecgen.NewCodec(msgpackcodec.NewPool[Node](), funcsNodeMsgPack)}
+}
+
+var _ codec.OptimizedCodec[Node] = NodeMsgPack{}
+
+// NodeCBOR is a CBOR codec for Node that calls the generated code
+// directly, falling back to reflection for values and input it does not
+// handle. It has the default settings of the CBOR codec.
+type NodeCBOR struct {
+	*codecgen.Codec[Node]
+}
+
+// NewNodeCBOR returns a CBOR codec for Node
+func NewNodeCBOR() NodeCBOR {
+	return NodeCBOR{codecgen.NewCodec(cborcodec.NewPool[Node](), funcsNodeCBOR)}
+}
+
+var _ codec.OptimizedCodec[Node] = NodeCBOR{}
+
+// FlagJSON is a JSON codec for Flag that calls the generated code
+// directly, falling back to reflection for values and input it does not
+// handle. It has the default settings of the JSON codec.
+type FlagJSON struct {
+	*codecgen.Codec[Flag]
+}
+
+// NewFlagJSON returns a JSON codec for Flag
+func NewFlagJSON() FlagJSON {
+	return FlagJSON{codecgen.NewCodec(jsoncodec.NewPool[Flag](), funcsFlagJSON)}
+}
+
+var _ codec.OptimizedCodec[Flag] = FlagJSON{}
+
+// FlagMsgPack is a MsgPack codec for Flag that calls the generated code
+// directly, falling back to reflection for values and input it does not
+// handle. It has the default settings of the MsgPack codec.
+type FlagMsgPack struct {
+	*codecgen.Codec[Flag]
+}
+
+// NewFlagMsgPack returns a MsgPack codec for Flag
+func NewFlagMsgPack() FlagMsgPack {
+	return FlagMsgPack{codecgen.NewCodec(msgpackcodec.NewPool[Flag](), funcsFlagMsgPack)}
+}
+
+var _ codec.OptimizedCodec[Flag] = FlagMsgPack{}
+
+// FlagCBOR is a CBOR codec for Flag that calls the generated code
+// directly, falling back to reflection for values and input it does not
+// handle. It has the default settings of the CBOR codec.
+type FlagCBOR struct {
+	*codecgen.Codec[Flag]
+}
+
+// NewFlagCBOR returns a CBOR codec for Flag
+func NewFlagCBOR() FlagCBOR {
+	return FlagCBOR{codecgen.NewCodec(cborcodec.NewPool[Flag](), funcsFlagCBOR)}
+}
+
+var _ codec.OptimizedCodec[Flag] = FlagCBOR{}
+
+// EmptyJSON is a JSON codec for Empty that calls the generated code
+// directly, falling back to reflection for values and input it does not
+// handle. It has the default settings of the JSON codec.
+type EmptyJSON struct {
+	*codecgen.Codec[Empty]
+}
+
+// NewEmptyJSON returns a JSON codec for Empty
+func NewEmptyJSON() EmptyJSON {
+	return EmptyJSON{codecgen.NewCodec(jsoncodec.NewPool[Empty](), funcsEmptyJSON)}
+}
+
+var _ codec.OptimizedCodec[Empty] = EmptyJSON{}
+
+// EmptyMsgPack is a MsgPack codec for Empty that calls the generated code
+// directly, falling back to reflection for values and input it does not
+// handle. It has the default settings of the MsgPack codec.
+type EmptyMsgPack struct {
+	*codecgen.Codec[Empty]
+}
+
+// NewEmptyMsgPack returns a MsgPack codec for Empty
+func NewEmptyMsgPack() EmptyMsgPack {
+	return EmptyMsgPack{codecgen.NewCodec(msgpackcodec.NewPool[Empty](), funcsEmptyMsgPack)}
+}
+
+var _ codec.OptimizedCodec[Empty] = EmptyMsgPack{}
+
+// EmptyCBOR is a CBOR codec for Empty that calls the generated code
+// directly, falling back to reflection for values and input it does not
+// handle. It has the default settings of the CBOR codec.
+type EmptyCBOR struct {
+	*codecgen.Codec[Empty]
+}
+
+// NewEmptyCBOR returns a CBOR codec for Empty
+func NewEmptyCBOR() EmptyCBOR {
+	return EmptyCBOR{codecgen.NewCodec(cborcodec.NewPool[Empty](), funcsEmptyCBOR)}
+}
+
+var _ codec.OptimizedCodec[Empty] = EmptyCBOR{}
+
+var funcsOrderJSON = codecgen.Funcs[Order]{
+	Append: func(buf []byte, v Order) ([]byte, error) {
+		return appendOrderJSON(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Order) error {
+		var r codecgen.JSONReader
+		r.Reset(data)
+		parseOrderJSON(&r, v)
+		return r.End()
+	},
+}
+
+var funcsOrderMsgPack = codecgen.Funcs[Order]{
+	Append: func(buf []byte, v Order) ([]byte, error) {
+		return appendOrderMsgPack(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Order) error {
+		var r codecgen.MsgPackReader
+		r.Reset(data)
+		parseOrderMsgPack(&r, v)
+		return r.End()
+	},
+}
+
+var funcsOrderCBOR = codecgen.Funcs[Order]{
+	Append: func(buf []byte, v Order) ([]byte, error) {
+		return appendOrderCBOR(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Order) error {
+		var r codecgen.CBORReader
+		r.Reset(data)
+		parseOrderCBOR(&r, v)
+		return r.End()
+	},
+}
+
+var funcsLineJSON = codecgen.Funcs[Line]{
+	Append: func(buf []byte, v Line) ([]byte, error) {
+		return appendLineJSON(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Line) error {
+		var r codecgen.JSONReader
+		r.Reset(data)
+		parseLineJSON(&r, v)
+		return r.End()
+	},
+}
+
+var funcsLineMsgPack = codecgen.Funcs[Line]{
+	Append: func(buf []byte, v Line) ([]byte, error) {
+		return appendLineMsgPack(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Line) error {
+		var r codecgen.MsgPackReader
+		r.Reset(data)
+		parseLineMsgPack(&r, v)
+		return r.End()
+	},
+}
+
+var funcsLineCBOR = codecgen.Funcs[Line]{
+	Append: func(buf []byte, v Line) ([]byte, error) {
+		return appendLineCBOR(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Line) error {
+		var r codecgen.CBORReader
+		r.Reset(data)
+		parseLineCBOR(&r, v)
+		return r.End()
+	},
+}
+
+var funcsAddressJSON = codecgen.Funcs[Address]{
+	Append: func(buf []byte, v Address) ([]byte, error) {
+		return appendAddressJSON(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Address) error {
+		var r codecgen.JSONReader
+		r.Reset(data)
+		parseAddressJSON(&r, v)
+		return r.End()
+	},
+}
+
+var funcsAddressMsgPack = codecgen.Funcs[Address]{
+	Append: func(buf []byte, v Address) ([]byte, error) {
+		return appendAddressMsgPack(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Address) error {
+		var r codecgen.MsgPackReader
+		r.Reset(data)
+		parseAddressMsgPack(&r, v)
+		return r.End()
+	},
+}
+
+var funcsAddressCBOR = codecgen.Funcs[Address]{
+	Append: func(buf []byte, v Address) ([]byte, error) {
+		return appendAddressCBOR(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Address) error {
+		var r codecgen.CBORReader
+		r.Reset(data)
+		parseAddressCBOR(&r, v)
+		return r.End()
+	},
+}
+
+var funcsNodeJSON = codecgen.Funcs[Node]{
+	Append: func(buf []byte, v Node) ([]byte, error) {
+		return appendNodeJSON(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Node) error {
+		var r codecgen.JSONReader
+		r.Reset(data)
+		parseNodeJSON(&r, v)
+		return r.End()
+	},
+}
+
+var funcsNodeMsgPack = codecgen.Funcs[Node]{
+	Append: func(buf []byte, v Node) ([]byte, error) {
+		return appendNodeMsgPack(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Node) error {
+		var r codecgen.MsgPackReader
+		r.Reset(data)
+		parseNodeMsgPack(&r, v)
+		return r.End()
+	},
+}
+
+var funcsNodeCBOR = codecgen.Funcs[Node]{
+	Append: func(buf []byte, v Node) ([]byte, error) {
+		return appendNodeCBOR(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Node) error {
+		var r codecgen.CBORReader
+		r.Reset(data)
+		parseNodeCBOR(&r, v)
+		return r.End()
+	},
+}
+
+var funcsFlagJSON = codecgen.Funcs[Flag]{
+	Append: func(buf []byte, v Flag) ([]byte, error) {
+		return appendFlagJSON(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Flag) error {
+		var r codecgen.JSONReader
+		r.Reset(data)
+		parseFlagJSON(&r, v)
+		return r.End()
+	},
+}
+
+var funcsFlagMsgPack = codecgen.Funcs[Flag]{
+	Append: func(buf []byte, v Flag) ([]byte, error) {
+		return appendFlagMsgPack(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Flag) error {
+		var r codecgen.MsgPackReader
+		r.Reset(data)
+		parseFlagMsgPack(&r, v)
+		return r.End()
+	},
+}
+
+var funcsFlagCBOR = codecgen.Funcs[Flag]{
+	Append: func(buf []byte, v Flag) ([]byte, error) {
+		return appendFlagCBOR(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Flag) error {
+		var r codecgen.CBORReader
+		r.Reset(data)
+		parseFlagCBOR(&r, v)
+		return r.End()
+	},
+}
+
+var funcsEmptyJSON = codecgen.Funcs[Empty]{
+	Append: func(buf []byte, v Empty) ([]byte, error) {
+		return appendEmptyJSON(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Empty) error {
+		var r codecgen.JSONReader
+		r.Reset(data)
+		parseEmptyJSON(&r, v)
+		return r.End()
+	},
+}
+
+var funcsEmptyMsgPack = codecgen.Funcs[Empty]{
+	Append: func(buf []byte, v Empty) ([]byte, error) {
+		return appendEmptyMsgPack(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Empty) error {
+		var r codecgen.MsgPackReader
+		r.Reset(data)
+		parseEmptyMsgPack(&r, v)
+		return r.End()
+	},
+}
+
+var funcsEmptyCBOR = codecgen.Funcs[Empty]{
+	Append: func(buf []byte, v Empty) ([]byte, error) {
+		return appendEmptyCBOR(buf, &v, 0)
+	},
+	Parse: func(data []byte, v *Empty) error {
+		var r codecgen.CBORReader
+		r.Reset(data)
+		parseEmptyCBOR(&r, v)
+		return r.End()
+	},
+}
+
+func appendOrderJSON(buf []byte, v *Order, depth int) ([]byte, error) {
+	var err error
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	buf = append(buf, `{"id":`...)
+	buf = strconv.AppendInt(buf, v.ID, 10)
+	if v.Number != 0 {
+		buf = append(buf, `,"number":`...)
+		buf = strconv.AppendInt(buf, int64(v.Number), 10)
+	}
+	buf = append(buf, `,"customer":`...)
+	buf = codecgen.AppendJSONString(buf, v.Customer)
+	buf = append(buf, `,"status":`...)
+	buf = strconv.AppendInt(buf, int64(v.Status), 10)
+	if v.Paid {
+		buf = append(buf, `,"paid":`...)
+		buf = strconv.AppendBool(buf, v.Paid)
+	}
+	buf = append(buf, `,"total":`...)
+	if buf, err = codecgen.AppendJSONFloat(buf, v.Total, 64); err != nil {
+		return buf, err
+	}
+	if v.Discount != 0 {
+		buf = append(buf, `,"discount":`...)
+		if buf, err = codecgen.AppendJSONFloat(buf, float64(v.Discount), 32); err != nil {
+			return buf, err
+		}
+	}
+	buf = append(buf, `,"small":`...)
+	buf = strconv.AppendUint(buf, uint64(v.Small), 10)
+	buf = append(buf, `,"count":`...)
+	buf = strconv.AppendUint(buf, uint64(v.Count), 10)
+	buf = append(buf, `,"big":`...)
+	buf = strconv.AppendUint(buf, v.Big, 10)
+	buf = append(buf, `,"code":`...)
+	buf = strconv.AppendInt(buf, int64(v.Code), 10)
+	buf = append(buf, `,"rune":`...)
+	buf = strconv.AppendInt(buf, int64(v.Rune), 10)
+	if v.Note != nil {
+		buf = append(buf, `,"note":`...)
+		buf = codecgen.AppendJSONString(buf, (*v.Note))
+	}
+	buf = append(buf, `,"label":`...)
+	buf = codecgen.AppendJSONString(buf, string(v.Label))
+	buf = append(buf, `,"tags":`...)
+	if v.Tags == nil {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, '[')
+		for i1 := range v.Tags {
+			if i1 > 0 {
+				buf = append(buf, ',')
+			}
+			buf = codecgen.AppendJSONString(buf, v.Tags[i1])
+		}
+		buf = append(buf, ']')
+	}
+	buf = append(buf, `,"names":`...)
+	if v.Names == nil {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, '[')
+		for i2 := range v.Names {
+			if i2 > 0 {
+				buf = append(buf, ',')
+			}
+			buf = codecgen.AppendJSONString(buf, v.Names[i2])
+		}
+		buf = append(buf, ']')
+	}
+	buf = append(buf, `,"raw":`...)
+	buf = codecgen.AppendJSONBytes(buf, v.Raw)
+	buf = append(buf, `,"lines":`...)
+	if v.Lines == nil {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, '[')
+		for i3 := range v.Lines {
+			if i3 > 0 {
+				buf = append(buf, ',')
+			}
+			if buf, err = appendLineJSON(buf, &v.Lines[i3], depth+1); err != nil {
+				return buf, err
+			}
+		}
+		buf = append(buf, ']')
+	}
+	if v.Ship != nil {
+		buf = append(buf, `,"ship":`...)
+		if buf, err = appendAddressJSON(buf, v.Ship, depth+1); err != nil {
+			return buf, err
+		}
+	}
+	buf = append(buf, `,"bill":`...)
+	if buf, err = appendAddressJSON(buf, &v.Bill, depth+1); err != nil {
+		return buf, err
+	}
+	if len(v.Attrs) != 0 {
+		buf = append(buf, `,"attrs":`...)
+		if v.Attrs == nil {
+			buf = append(buf, "null"...)
+		} else {
+			buf = append(buf, '{')
+			for i4, k5 := range codecgen.SortedKeys(v.Attrs) {
+				if i4 > 0 {
+					buf = append(buf, ',')
+				}
+				buf = append(codecgen.AppendJSONString(buf, k5), ':')
+				e6 := v.Attrs[k5]
+				buf = codecgen.AppendJSONString(buf, e6)
+			}
+			buf = append(buf, '}')
+		}
+	}
+	buf = append(buf, `,"scores":`...)
+	if v.Scores == nil {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, '{')
+		for i7, k8 := range codecgen.SortedKeys(v.Scores) {
+			if i7 > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(codecgen.AppendJSONString(buf, k8), ':')
+			e9 := v.Scores[k8]
+			if e9 == nil {
+				buf = append(buf, "null"...)
+			} else {
+				buf = append(buf, '[')
+				for i10 := range e9 {
+					if i10 > 0 {
+						buf = append(buf, ',')
+					}
+					buf = strconv.AppendInt(buf, int64(e9[i10]), 10)
+				}
+				buf = append(buf, ']')
+			}
+		}
+		buf = append(buf, '}')
+	}
+	buf = append(buf, `,"extra":`...)
+	if v.Extra == nil {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, '{')
+		for i11, k12 := range codecgen.SortedKeys(v.Extra) {
+			if i11 > 0 {
+				buf = append(buf, ',')
+			}
+			buf = append(codecgen.AppendJSONString(buf, k12), ':')
+			e13 := v.Extra[k12]
+			if e13 == nil {
+				buf = append(buf, "null"...)
+			} else {
+				if buf, err = appendLineJSON(buf, e13, depth+1); err != nil {
+					return buf, err
+				}
+			}
+		}
+		buf = append(buf, '}')
+	}
+	buf = append(buf, `,"matrix":`...)
+	if v.Matrix == nil {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, '[')
+		for i14 := range v.Matrix {
+			if i14 > 0 {
+				buf = append(buf, ',')
+			}
+			if v.Matrix[i14] == nil {
+				buf = append(buf, "null"...)
+			} else {
+				buf = append(buf, '[')
+				for i15 := range v.Matrix[i14] {
+					if i15 > 0 {
+						buf = append(buf, ',')
+					}
+					if buf, err = codecgen.AppendJSONFloat(buf, v.Matrix[i14][i15], 64); err != nil {
+						return buf, err
+					}
+				}
+				buf = append(buf, ']')
+			}
+		}
+		buf = append(buf, ']')
+	}
+	buf = append(buf, '}')
+	return buf, nil
+}
+
+func parseOrderJSON(r *codecgen.JSONReader, v *Order) {
+	if r.Null() || !r.BeginObject() {
+		return
+	}
+	for r.NextMember() {
+		switch key := r.Key(); string(key) {
+		case "id":
+			if !r.Null() {
+				v.ID = r.Int(64)
+			}
+		case "number":
+			if !r.Null() {
+				v.Number = int(r.Int(0))
+			}
+		case "customer":
+			if !r.Null() {
+				v.Customer = r.String()
+			}
+		case "status":
+			if !r.Null() {
+				v.Status = Status(r.Int(8))
+			}
+		case "paid":
+			if !r.Null() {
+				v.Paid = r.Bool()
+			}
+		case "total":
+			if !r.Null() {
+				v.Total = r.Float(64)
+			}
+		case "discount":
+			if !r.Null() {
+				v.Discount = float32(r.Float(32))
+			}
+		case "small":
+			if !r.Null() {
+				v.Small = uint8(r.Uint(8))
+			}
+		case "count":
+			if !r.Null() {
+				v.Count = uint(r.Uint(0))
+			}
+		case "big":
+			if !r.Null() {
+				v.Big = r.Uint(64)
+			}
+		case "code":
+			if !r.Null() {
+				v.Code = int16(r.Int(16))
+			}
+		case "rune":
+			if !r.Null() {
+				v.Rune = int32(r.Int(32))
+			}
+		case "note":
+			if r.Null() {
+				v.Note = nil
+			} else {
+				if v.Note == nil {
+					v.Note = new(string)
+				}
+				if !r.Null() {
+					(*v.Note) = r.String()
+				}
+			}
+		case "label":
+			if !r.Null() {
+				v.Label = Label(r.String())
+			}
+		case "tags":
+			if r.Null() {
+				v.Tags = nil
+			} else if r.BeginArray() {
+				n1 := 0
+				for ; r.NextItem(); n1++ {
+					v.Tags = codecgen.ExtendJSON(v.Tags, n1)
+					if !r.Null() {
+						v.Tags[n1] = r.String()
+					}
+				}
+				v.Tags = codecgen.TruncateJSON(v.Tags, n1)
+			}
+		case "names":
+			if r.Null() {
+				v.Names = nil
+			} else if r.BeginArray() {
+				n2 := 0
+				for ; r.NextItem(); n2++ {
+					v.Names = codecgen.ExtendJSON(v.Names, n2)
+					if !r.Null() {
+						v.Names[n2] = r.String()
+					}
+				}
+				v.Names = codecgen.TruncateJSON(v.Names, n2)
+			}
+		case "raw":
+			if r.Null() {
+				v.Raw = nil
+			} else {
+				v.Raw = r.Bytes()
+			}
+		case "lines":
+			if r.Null() {
+				v.Lines = nil
+			} else if r.BeginArray() {
+				n3 := 0
+				for ; r.NextItem(); n3++ {
+					v.Lines = codecgen.ExtendJSON(v.Lines, n3)
+					parseLineJSON(r, &v.Lines[n3])
+				}
+				v.Lines = codecgen.TruncateJSON(v.Lines, n3)
+			}
+		case "ship":
+			if r.Null() {
+				v.Ship = nil
+			} else {
+				if v.Ship == nil {
+					v.Ship = new(Address)
+				}
+				parseAddressJSON(r, v.Ship)
+			}
+		case "bill":
+			parseAddressJSON(r, &v.Bill)
+		case "attrs":
+			if r.Null() {
+				v.Attrs = nil
+			} else if r.BeginObject() {
+				if v.Attrs == nil {
+					v.Attrs = make(map[string]string)
+				}
+				for r.NextMember() {
+					k4 := string(r.Key())
+					var e5 string
+					if !r.Null() {
+						e5 = r.String()
+					}
+					v.Attrs[k4] = e5
+				}
+			}
+		case "scores":
+			if r.Null() {
+				v.Scores = nil
+			} else if r.BeginObject() {
+				if v.Scores == nil {
+					v.Scores = make(map[string][]int32)
+				}
+				for r.NextMember() {
+					k6 := string(r.Key())
+					var e7 []int32
+					if r.Null() {
+						e7 = nil
+					} else if r.BeginArray() {
+						n8 := 0
+						for ; r.NextItem(); n8++ {
+							e7 = codecgen.ExtendJSON(e7, n8)
+							if !r.Null() {
+								e7[n8] = int32(r.Int(32))
+							}
+						}
+						e7 = codecgen.TruncateJSON(e7, n8)
+					}
+					v.Scores[k6] = e7
+				}
+			}
+		case "extra":
+			if r.Null() {
+				v.Extra = nil
+			} else if r.BeginObject() {
+				if v.Extra == nil {
+					v.Extra = make(map[string]*Line)
+				}
+				for r.NextMember() {
+					k9 := string(r.Key())
+					var e10 *Line
+					if r.Null() {
+						e10 = nil
+					} else {
+						if e10 == nil {
+							e10 = new(Line)
+						}
+						parseLineJSON(r, e10)
+					}
+					v.Extra[k9] = e10
+				}
+			}
+		case "matrix":
+			if r.Null() {
+				v.Matrix = nil
+			} else if r.BeginArray() {
+				n11 := 0
+				for ; r.NextItem(); n11++ {
+					v.Matrix = codecgen.ExtendJSON(v.Matrix, n11)
+					if r.Null() {
+						v.Matrix[n11] = nil
+					} else if r.BeginArray() {
+						n12 := 0
+						for ; r.NextItem(); n12++ {
+							v.Matrix[n11] = codecgen.ExtendJSON(v.Matrix[n11], n12)
+							if !r.Null() {
+								v.Matrix[n11][n12] = r.Float(64)
+							}
+						}
+						v.Matrix[n11] = codecgen.TruncateJSON(v.Matrix[n11], n12)
+					}
+				}
+				v.Matrix = codecgen.TruncateJSON(v.Matrix, n11)
+			}
+		default:
+			r.Unknown(key, "id", "number", "customer", "status", "paid", "total", "discount", "small", "count", "big", "code", "rune", "note", "label", "tags", "names", "raw", "lines", "ship", "bill", "attrs", "scores", "extra", "matrix")
+		}
+	}
+}
+
+func appendOrderMsgPack(buf []byte, v *Order, depth int) ([]byte, error) {
+	var err error
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	n := 25
+	if v.Number == 0 {
+		n--
+	}
+	if !v.Paid {
+		n--
+	}
+	if v.Note == nil {
+		n--
+	}
+	if len(v.Raw) == 0 {
+		n--
+	}
+	if len(v.Attrs) == 0 {
+		n--
+	}
+	buf = codecgen.AppendMsgPackMapLen(buf, n)
+	buf = append(buf, "\xa2id"...)
+	buf = codecgen.AppendMsgPackInt(buf, v.ID, 64)
+	if v.Number != 0 {
+		buf = append(buf, "\xa6number"...)
+		buf = codecgen.AppendMsgPackInt(buf, int64(v.Number), 0)
+	}
+	buf = append(buf, "\xa8Customer"...)
+	buf = codecgen.AppendMsgPackString(buf, v.Customer)
+	buf = append(buf, "\xa6Status"...)
+	buf = codecgen.AppendMsgPackInt(buf, int64(v.Status), 8)
+	if v.Paid {
+		buf = append(buf, "\xa4Paid"...)
+		buf = codecgen.AppendMsgPackBool(buf, v.Paid)
+	}
+	buf = append(buf, "\xa5Total"...)
+	buf = codecgen.AppendMsgPackFloat64(buf, v.Total)
+	buf = append(buf, "\xa8Discount"...)
+	buf = codecgen.AppendMsgPackFloat32(buf, v.Discount)
+	buf = append(buf, "\xa5Small"...)
+	buf = codecgen.AppendMsgPackUint(buf, uint64(v.Small), 8)
+	buf = append(buf, "\xa5Count"...)
+	buf = codecgen.AppendMsgPackUint(buf, uint64(v.Count), 0)
+	buf = append(buf, "\xa3Big"...)
+	buf = codecgen.AppendMsgPackUint(buf, v.Big, 64)
+	buf = append(buf, "\xa4Code"...)
+	buf = codecgen.AppendMsgPackInt(buf, int64(v.Code), 16)
+	buf = append(buf, "\xa4Rune"...)
+	buf = codecgen.AppendMsgPackInt(buf, int64(v.Rune), 32)
+	if v.Note != nil {
+		buf = append(buf, "\xa4note"...)
+		buf = codecgen.AppendMsgPackString(buf, (*v.Note))
+	}
+	buf = append(buf, "\xa5Label"...)
+	buf = codecgen.AppendMsgPackString(buf, string(v.Label))
+	buf = append(buf, "\xa4Tags"...)
+	if v.Tags == nil {
+		buf = codecgen.AppendMsgPackNil(buf)
+	} else {
+		buf = codecgen.AppendMsgPackArrayLen(buf, len(v.Tags))
+		for i1 := range v.Tags {
+			buf = codecgen.AppendMsgPackString(buf, v.Tags[i1])
+		}
+	}
+	buf = append(buf, "\xa5Names"...)
+	if v.Names == nil {
+		buf = codecgen.AppendMsgPackNil(buf)
+	} else {
+		buf = codecgen.AppendMsgPackArrayLen(buf, len(v.Names))
+		for i2 := range v.Names {
+			buf = codecgen.AppendMsgPackString(buf, v.Names[i2])
+		}
+	}
+	if len(v.Raw) != 0 {
+		buf = append(buf, "\xa3raw"...)
+		buf = codecgen.AppendMsgPackBytes(buf, v.Raw)
+	}
+	buf = append(buf, "\xa5Lines"...)
+	if v.Lines == nil {
+		buf = codecgen.AppendMsgPackNil(buf)
+	} else {
+		buf = codecgen.AppendMsgPackArrayLen(buf, len(v.Lines))
+		for i3 := range v.Lines {
+			if buf, err = appendLineMsgPack(buf, &v.Lines[i3], depth+1); err != nil {
+				return buf, err
+			}
+		}
+	}
+	buf = append(buf, "\xa4Ship"...)
+	if v.Ship == nil {
+		buf = codecgen.AppendMsgPackNil(buf)
+	} else {
+		if buf, err = appendAddressMsgPack(buf, v.Ship, depth+1); err != nil {
+			return buf, err
+		}
+	}
+	buf = append(buf, "\xa4Bill"...)
+	if buf, err = appendAddressMsgPack(buf, &v.Bill, depth+1); err != nil {
+		return buf, err
+	}
+	if len(v.Attrs) != 0 {
+		buf = append(buf, "\xa5attrs"...)
+		if v.Attrs == nil {
+			buf = codecgen.AppendMsgPackNil(buf)
+		} else {
+			buf = codecgen.AppendMsgPackMapLen(buf, len(v.Attrs))
+			for _, k4 := range codecgen.SortedKeys(v.Attrs) {
+				buf = codecgen.AppendMsgPackString(buf, k4)
+				e5 := v.Attrs[k4]
+				buf = codecgen.AppendMsgPackString(buf, e5)
+			}
+		}
+	}
+	buf = append(buf, "\xa6Scores"...)
+	if v.Scores == nil {
+		buf = codecgen.AppendMsgPackNil(buf)
+	} else {
+		buf = codecgen.AppendMsgPackMapLen(buf, len(v.Scores))
+		for _, k6 := range codecgen.SortedKeys(v.Scores) {
+			buf = codecgen.AppendMsgPackString(buf, k6)
+			e7 := v.Scores[k6]
+			if e7 == nil {
+				buf = codecgen.AppendMsgPackNil(buf)
+			} else {
+				buf = codecgen.AppendMsgPackArrayLen(buf, len(e7))
+				for i8 := range e7 {
+					buf = codecgen.AppendMsgPackInt(buf, int64(e7[i8]), 32)
+				}
+			}
+		}
+	}
+	buf = append(buf, "\xa5Extra"...)
+	if v.Extra == nil {
+		buf = codecgen.AppendMsgPackNil(buf)
+	} else {
+		buf = codecgen.AppendMsgPackMapLen(buf, len(v.Extra))
+		for _, k9 := range codecgen.SortedKeys(v.Extra) {
+			buf = codecgen.AppendMsgPackString(buf, k9)
+			e10 := v.Extra[k9]
+			if e10 == nil {
+				buf = codecgen.AppendMsgPackNil(buf)
+			} else {
+				if buf, err = appendLineMsgPack(buf, e10, depth+1); err != nil {
+					return buf, err
+				}
+			}
+		}
+	}
+	buf = append(buf, "\xa6Matrix"...)
+	if v.Matrix == nil {
+		buf = codecgen.AppendMsgPackNil(buf)
+	} else {
+		buf = codecgen.AppendMsgPackArrayLen(buf, len(v.Matrix))
+		for i11 := range v.Matrix {
+			if v.Matrix[i11] == nil {
+				buf = codecgen.AppendMsgPackNil(buf)
+			} else {
+				buf = codecgen.AppendMsgPackArrayLen(buf, len(v.Matrix[i11]))
+				for i12 := range v.Matrix[i11] {
+					buf = codecgen.AppendMsgPackFloat64(buf, v.Matrix[i11][i12])
+				}
+			}
+		}
+	}
+	buf = append(buf, "\xa7Skipped"...)
+	buf = codecgen.AppendMsgPackString(buf, v.Skipped)
+	return buf, nil
+}
+
+func parseOrderMsgPack(r *codecgen.MsgPackReader, v *Order) {
+	if r.Nil() {
+		*v = Order{}
+		return
+	}
+	for n := r.MapLen(); n > 0; n-- {
+		switch string(r.Key()) {
+		case "id":
+			v.ID = r.Int()
+		case "number":
+			v.Number = int(r.Int())
+		case "Customer":
+			v.Customer = r.String()
+		case "Status":
+			v.Status = Status(r.Int())
+		case "Paid":
+			v.Paid = r.Bool()
+		case "Total":
+			v.Total = r.Float64()
+		case "Discount":
+			v.Discount = r.Float32()
+		case "Small":
+			v.Small = uint8(r.Uint())
+		case "Count":
+			v.Count = uint(r.Uint())
+		case "Big":
+			v.Big = r.Uint()
+		case "Code":
+			v.Code = int16(r.Int())
+		case "Rune":
+			v.Rune = int32(r.Int())
+		case "note":
+			if r.Nil() {
+				v.Note = nil
+			} else {
+				if v.Note == nil {
+					v.Note = new(string)
+				}
+				(*v.Note) = r.String()
+			}
+		case "Label":
+			v.Label = Label(r.String())
+		case "Tags":
+			if !r.Nil() {
+				v.Tags = codecgen.ResizeMsgPackStrings(v.Tags, r.ArrayLen())
+				for i1 := range v.Tags {
+					v.Tags[i1] = r.String()
+				}
+			}
+		case "Names":
+			if !r.Nil() {
+				v.Names = codecgen.ResizeMsgPackStrings(v.Names, r.ArrayLen())
+				for i2 := range v.Names {
+					v.Names[i2] = r.String()
+				}
+			}
+		case "raw":
+			v.Raw = r.Bytes(v.Raw)
+		case "Lines":
+			if r.Nil() {
+				v.Lines = nil
+			} else {
+				v.Lines = codecgen.ResizeMsgPack(v.Lines, r.ArrayLen())
+				for i3 := range v.Lines {
+					parseLineMsgPack(r, &v.Lines[i3])
+				}
+			}
+		case "Ship":
+			if r.Nil() {
+				v.Ship = nil
+			} else {
+				if v.Ship == nil {
+					v.Ship = new(Address)
+				}
+				parseAddressMsgPack(r, v.Ship)
+			}
+		case "Bill":
+			parseAddressMsgPack(r, &v.Bill)
+		case "attrs":
+			if r.Nil() {
+				v.Attrs = nil
+			} else {
+				n4 := r.MapLen()
+				if v.Attrs == nil {
+					v.Attrs = make(map[string]string, n4)
+				}
+				for ; n4 > 0; n4-- {
+					k5 := r.String()
+					var e6 string
+					e6 = r.String()
+					v.Attrs[k5] = e6
+				}
+			}
+		case "Scores":
+			if r.Nil() {
+				v.Scores = nil
+			} else {
+				n7 := r.MapLen()
+				if v.Scores == nil {
+					v.Scores = make(map[string][]int32, n7)
+				}
+				for ; n7 > 0; n7-- {
+					k8 := r.String()
+					var e9 []int32
+					if r.Nil() {
+						e9 = nil
+					} else {
+						e9 = codecgen.ResizeMsgPack(e9, r.ArrayLen())
+						for i10 := range e9 {
+							e9[i10] = int32(r.Int())
+						}
+					}
+					v.Scores[k8] = e9
+				}
+			}
+		case "Extra":
+			if r.Nil() {
+				v.Extra = nil
+			} else {
+				n11 := r.MapLen()
+				if v.Extra == nil {
+					v.Extra = make(map[string]*Line, n11)
+				}
+				for ; n11 > 0; n11-- {
+					k12 := r.String()
+					var e13 *Line
+					if r.Nil() {
+						e13 = nil
+					} else {
+						if e13 == nil {
+							e13 = new(Line)
+						}
+						parseLineMsgPack(r, e13)
+					}
+					v.Extra[k12] = e13
+				}
+			}
+		case "Matrix":
+			if r.Nil() {
+				v.Matrix = nil
+			} else {
+				v.Matrix = codecgen.ResizeMsgPack(v.Matrix, r.ArrayLen())
+				for i14 := range v.Matrix {
+					if r.Nil() {
+						v.Matrix[i14] = nil
+					} else {
+						v.Matrix[i14] = codecgen.ResizeMsgPack(v.Matrix[i14], r.ArrayLen())
+						for i15 := range v.Matrix[i14] {
+							v.Matrix[i14][i15] = r.Float64()
+						}
+					}
+				}
+			}
+		case "Skipped":
+			v.Skipped = r.String()
+		default:
+			r.Skip()
+		}
+	}
+}
+
+func appendOrderCBOR(buf []byte, v *Order, depth int) ([]byte, error) {
+	var err error
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	n := 24
+	if v.Number == 0 {
+		n--
+	}
+	if !v.Paid {
+		n--
+	}
+	if v.Discount == 0 {
+		n--
+	}
+	if v.Note == nil {
+		n--
+	}
+	if v.Ship == nil {
+		n--
+	}
+	if len(v.Bill.Street) == 0 && len(v.Bill.City) == 0 {
+		n--
+	}
+	if len(v.Attrs) == 0 {
+		n--
+	}
+	buf = codecgen.AppendCBORMapLen(buf, n)
+	buf = append(buf, "\x62id"...)
+	buf = codecgen.AppendCBORInt(buf, v.ID)
+	if v.Number != 0 {
+		buf = append(buf, "\x66number"...)
+		buf = codecgen.AppendCBORInt(buf, int64(v.Number))
+	}
+	buf = append(buf, "\x68customer"...)
+	buf = codecgen.AppendCBORString(buf, v.Customer)
+	buf = append(buf, "\x66status"...)
+	buf = codecgen.AppendCBORInt(buf, int64(v.Status))
+	if v.Paid {
+		buf = append(buf, "\x64paid"...)
+		buf = codecgen.AppendCBORBool(buf, v.Paid)
+	}
+	buf = append(buf, "\x65total"...)
+	buf = codecgen.AppendCBORFloat64(buf, v.Total)
+	if v.Discount != 0 {
+		buf = append(buf, "\x68discount"...)
+		buf = codecgen.AppendCBORFloat32(buf, v.Discount)
+	}
+	buf = append(buf, "\x65small"...)
+	buf = codecgen.AppendCBORUint(buf, uint64(v.Small))
+	buf = append(buf, "\x65count"...)
+	buf = codecgen.AppendCBORUint(buf, uint64(v.Count))
+	buf = append(buf, "\x63big"...)
+	buf = codecgen.AppendCBORUint(buf, v.Big)
+	buf = append(buf, "\x64code"...)
+	buf = codecgen.AppendCBORInt(buf, int64(v.Code))
+	buf = append(buf, "\x64rune"...)
+	buf = codecgen.AppendCBORInt(buf, int64(v.Rune))
+	if v.Note != nil {
+		buf = append(buf, "\x64note"...)
+		buf = codecgen.AppendCBORString(buf, (*v.Note))
+	}
+	buf = append(buf, "\x65label"...)
+	buf = codecgen.AppendCBORString(buf, string(v.Label))
+	buf = append(buf, "\x64tags"...)
+	if v.Tags == nil {
+		buf = codecgen.AppendCBORNull(buf)
+	} else {
+		buf = codecgen.AppendCBORArrayLen(buf, len(v.Tags))
+		for i1 := range v.Tags {
+			buf = codecgen.AppendCBORString(buf, v.Tags[i1])
+		}
+	}
+	buf = append(buf, "\x65names"...)
+	if v.Names == nil {
+		buf = codecgen.AppendCBORNull(buf)
+	} else {
+		buf = codecgen.AppendCBORArrayLen(buf, len(v.Names))
+		for i2 := range v.Names {
+			buf = codecgen.AppendCBORString(buf, v.Names[i2])
+		}
+	}
+	buf = append(buf, "\x63raw"...)
+	buf = codecgen.AppendCBORBytes(buf, v.Raw)
+	buf = append(buf, "\x65lines"...)
+	if v.Lines == nil {
+		buf = codecgen.AppendCBORNull(buf)
+	} else {
+		buf = codecgen.AppendCBORArrayLen(buf, len(v.Lines))
+		for i3 := range v.Lines {
+			if buf, err = appendLineCBOR(buf, &v.Lines[i3], depth+1); err != nil {
+				return buf, err
+			}
+		}
+	}
+	if v.Ship != nil {
+		buf = append(buf, "\x64ship"...)
+		if buf, err = appendAddressCBOR(buf, v.Ship, depth+1); err != nil {
+			return buf, err
+		}
+	}
+	if len(v.Bill.Street) != 0 || len(v.Bill.City) != 0 {
+		buf = append(buf, "\x64bill"...)
+		if buf, err = appendAddressCBOR(buf, &v.Bill, depth+1); err != nil {
+			return buf, err
+		}
+	}
+	if len(v.Attrs) != 0 {
+		buf = append(buf, "\x65attrs"...)
+		if v.Attrs == nil {
+			buf = codecgen.AppendCBORNull(buf)
+		} else {
+			buf = codecgen.AppendCBORMapLen(buf, len(v.Attrs))
+			for _, k4 := range codecgen.SortedCBORKeys(v.Attrs) {
+				buf = codecgen.AppendCBORString(buf, k4)
+				e5 := v.Attrs[k4]
+				buf = codecgen.AppendCBORString(buf, e5)
+			}
+		}
+	}
+	buf = append(buf, "\x66scores"...)
+	if v.Scores == nil {
+		buf = codecgen.AppendCBORNull(buf)
+	} else {
+		buf = codecgen.AppendCBORMapLen(buf, len(v.Scores))
+		for _, k6 := range codecgen.SortedCBORKeys(v.Scores) {
+			buf = codecgen.AppendCBORString(buf, k6)
+			e7 := v.Scores[k6]
+			if e7 == nil {
+				buf = codecgen.AppendCBORNull(buf)
+			} else {
+				buf = codecgen.AppendCBORArrayLen(buf, len(e7))
+				for i8 := range e7 {
+					buf = codecgen.AppendCBORInt(buf, int64(e7[i8]))
+				}
+			}
+		}
+	}
+	buf = append(buf, "\x65extra"...)
+	if v.Extra == nil {
+		buf = codecgen.AppendCBORNull(buf)
+	} else {
+		buf = codecgen.AppendCBORMapLen(buf, len(v.Extra))
+		for _, k9 := range codecgen.SortedCBORKeys(v.Extra) {
+			buf = codecgen.AppendCBORString(buf, k9)
+			e10 := v.Extra[k9]
+			if e10 == nil {
+				buf = codecgen.AppendCBORNull(buf)
+			} else {
+				if buf, err = appendLineCBOR(buf, e10, depth+1); err != nil {
+					return buf, err
+				}
+			}
+		}
+	}
+	buf = append(buf, "\x66matrix"...)
+	if v.Matrix == nil {
+		buf = codecgen.AppendCBORNull(buf)
+	} else {
+		buf = codecgen.AppendCBORArrayLen(buf, len(v.Matrix))
+		for i11 := range v.Matrix {
+			if v.Matrix[i11] == nil {
+				buf = codecgen.AppendCBORNull(buf)
+			} else {
+				buf = codecgen.AppendCBORArrayLen(buf, len(v.Matrix[i11]))
+				for i12 := range v.Matrix[i11] {
+					buf = codecgen.AppendCBORFloat64(buf, v.Matrix[i11][i12])
+				}
+			}
+		}
+	}
+	return buf, nil
+}
+
+func parseOrderCBOR(r *codecgen.CBORReader, v *Order) {
+	if r.Null() {
+		return
+	}
+	var seen [24]bool
+	for n := r.MapLen(); n > 0; n-- {
+		switch key := r.Key(); string(key) {
+		case "id":
+			r.Field(&seen[0])
+			if !r.Null() {
+				v.ID = r.Int(64)
+			}
+		case "number":
+			r.Field(&seen[1])
+			if !r.Null() {
+				v.Number = int(r.Int(0))
+			}
+		case "customer":
+			r.Field(&seen[2])
+			if !r.Null() {
+				v.Customer = r.String()
+			}
+		case "status":
+			r.Field(&seen[3])
+			if !r.Null() {
+				v.Status = Status(r.Int(8))
+			}
+		case "paid":
+			r.Field(&seen[4])
+			if !r.Null() {
+				v.Paid = r.Bool()
+			}
+		case "total":
+			r.Field(&seen[5])
+			if !r.Null() {
+				v.Total = r.Float(64)
+			}
+		case "discount":
+			r.Field(&seen[6])
+			if !r.Null() {
+				v.Discount = float32(r.Float(32))
+			}
+		case "small":
+			r.Field(&seen[7])
+			if !r.Null() {
+				v.Small = uint8(r.Uint(8))
+			}
+		case "count":
+			r.Field(&seen[8])
+			if !r.Null() {
+				v.Count = uint(r.Uint(0))
+			}
+		case "big":
+			r.Field(&seen[9])
+			if !r.Null() {
+				v.Big = r.Uint(64)
+			}
+		case "code":
+			r.Field(&seen[10])
+			if !r.Null() {
+				v.Code = int16(r.Int(16))
+			}
+		case "rune":
+			r.Field(&seen[11])
+			if !r.Null() {
+				v.Rune = int32(r.Int(32))
+			}
+		case "note":
+			r.Field(&seen[12])
+			if r.Null() {
+				v.Note = nil
+			} else {
+				if v.Note == nil {
+					v.Note = new(string)
+				}
+				if !r.Null() {
+					(*v.Note) = r.String()
+				}
+			}
+		case "label":
+			r.Field(&seen[13])
+			if !r.Null() {
+				v.Label = Label(r.String())
+			}
+		case "tags":
+			r.Field(&seen[14])
+			if r.Null() {
+				v.Tags = nil
+			} else {
+				v.Tags = codecgen.ResizeCBOR(v.Tags, r.ArrayLen())
+				for i1 := range v.Tags {
+					if !r.Null() {
+						v.Tags[i1] = r.String()
+					}
+				}
+				r.Leave()
+			}
+		case "names":
+			r.Field(&seen[15])
+			if r.Null() {
+				v.Names = nil
+			} else {
+				v.Names = codecgen.ResizeCBOR(v.Names, r.ArrayLen())
+				for i2 := range v.Names {
+					if !r.Null() {
+						v.Names[i2] = r.String()
+					}
+				}
+				r.Leave()
+			}
+		case "raw":
+			r.Field(&seen[16])
+			if r.Null() {
+				v.Raw = nil
+			} else {
+				v.Raw = r.Bytes()
+			}
+		case "lines":
+			r.Field(&seen[17])
+			if r.Null() {
+				v.Lines = nil
+			} else {
+				v.Lines = codecgen.ResizeCBOR(v.Lines, r.ArrayLen())
+				for i3 := range v.Lines {
+					parseLineCBOR(r, &v.Lines[i3])
+				}
+				r.Leave()
+			}
+		case "ship":
+			r.Field(&seen[18])
+			if r.Null() {
+				v.Ship = nil
+			} else {
+				if v.Ship == nil {
+					v.Ship = new(Address)
+				}
+				parseAddressCBOR(r, v.Ship)
+			}
+		case "bill":
+			r.Field(&seen[19])
+			parseAddressCBOR(r, &v.Bill)
+		case "attrs":
+			r.Field(&seen[20])
+			if r.Null() {
+				v.Attrs = nil
+			} else {
+				n4 := r.MapLen()
+				if v.Attrs == nil {
+					v.Attrs = make(map[string]string, n4)
+				}
+				for ; n4 > 0; n4-- {
+					k5 := r.String()
+					var e6 string
+					if !r.Null() {
+						e6 = r.String()
+					}
+					v.Attrs[k5] = e6
+				}
+				r.Leave()
+			}
+		case "scores":
+			r.Field(&seen[21])
+			if r.Null() {
+				v.Scores = nil
+			} else {
+				n7 := r.MapLen()
+				if v.Scores == nil {
+					v.Scores = make(map[string][]int32, n7)
+				}
+				for ; n7 > 0; n7-- {
+					k8 := r.String()
+					var e9 []int32
+					if r.Null() {
+						e9 = nil
+					} else {
+						e9 = codecgen.ResizeCBOR(e9, r.ArrayLen())
+						for i10 := range e9 {
+							if !r.Null() {
+								e9[i10] = int32(r.Int(32))
+							}
+						}
+						r.Leave()
+					}
+					v.Scores[k8] = e9
+				}
+				r.Leave()
+			}
+		case "extra":
+			r.Field(&seen[22])
+			if r.Null() {
+				v.Extra = nil
+			} else {
+				n11 := r.MapLen()
+				if v.Extra == nil {
+					v.Extra = make(map[string]*Line, n11)
+				}
+				for ; n11 > 0; n11-- {
+					k12 := r.String()
+					var e13 *Line
+					if r.Null() {
+						e13 = nil
+					} else {
+						if e13 == nil {
+							e13 = new(Line)
+						}
+						parseLineCBOR(r, e13)
+					}
+					v.Extra[k12] = e13
+				}
+				r.Leave()
+			}
+		case "matrix":
+			r.Field(&seen[23])
+			if r.Null() {
+				v.Matrix = nil
+			} else {
+				v.Matrix = codecgen.ResizeCBOR(v.Matrix, r.ArrayLen())
+				for i14 := range v.Matrix {
+					if r.Null() {
+						v.Matrix[i14] = nil
+					} else {
+						v.Matrix[i14] = codecgen.ResizeCBOR(v.Matrix[i14], r.ArrayLen())
+						for i15 := range v.Matrix[i14] {
+							if !r.Null() {
+								v.Matrix[i14][i15] = r.Float(64)
+							}
+						}
+						r.Leave()
+					}
+				}
+				r.Leave()
+			}
+		default:
+			r.Unknown(key, "id", "number", "customer", "status", "paid", "total", "discount", "small", "count", "big", "code", "rune", "note", "label", "tags", "names", "raw", "lines", "ship", "bill", "attrs", "scores", "extra", "matrix")
+		}
+	}
+	r.Leave()
+}
+
+func appendLineJSON(buf []byte, v *Line, depth int) ([]byte, error) {
+	var err error
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	buf = append(buf, `{"sku":`...)
+	buf = codecgen.AppendJSONString(buf, v.SKU)
+	buf = append(buf, `,"qty":`...)
+	buf = strconv.AppendUint(buf, uint64(v.Qty), 10)
+	buf = append(buf, `,"price":`...)
+	if buf, err = codecgen.AppendJSONFloat(buf, v.Price, 64); err != nil {
+		return buf, err
+	}
+	buf = append(buf, '}')
+	return buf, nil
+}
+
+func parseLineJSON(r *codecgen.JSONReader, v *Line) {
+	if r.Null() || !r.BeginObject() {
+		return
+	}
+	for r.NextMember() {
+		switch key := r.Key(); string(key) {
+		case "sku":
+			if !r.Null() {
+				v.SKU = r.String()
+			}
+		case "qty":
+			if !r.Null() {
+				v.Qty = uint16(r.Uint(16))
+			}
+		case "price":
+			if !r.Null() {
+				v.Price = r.Float(64)
+			}
+		default:
+			r.Unknown(key, "sku", "qty", "price")
+		}
+	}
+}
+
+func appendLineMsgPack(buf []byte, v *Line, depth int) ([]byte, error) {
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	buf = append(buf, "\x83\xa3SKU"...)
+	buf = codecgen.AppendMsgPackString(buf, v.SKU)
+	buf = append(buf, "\xa3Qty"...)
+	buf = codecgen.AppendMsgPackUint(buf, uint64(v.Qty), 16)
+	buf = append(buf, "\xa5Price"...)
+	buf = codecgen.AppendMsgPackFloat64(buf, v.Price)
+	return buf, nil
+}
+
+func parseLineMsgPack(r *codecgen.MsgPackReader, v *Line) {
+	if r.Nil() {
+		*v = Line{}
+		return
+	}
+	for n := r.MapLen(); n > 0; n-- {
+		switch string(r.Key()) {
+		case "SKU":
+			v.SKU = r.String()
+		case "Qty":
+			v.Qty = uint16(r.Uint())
+		case "Price":
+			v.Price = r.Float64()
+		default:
+			r.Skip()
+		}
+	}
+}
+
+func appendLineCBOR(buf []byte, v *Line, depth int) ([]byte, error) {
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	buf = append(buf, "\xa3\x63sku"...)
+	buf = codecgen.AppendCBORString(buf, v.SKU)
+	buf = append(buf, "\x63qty"...)
+	buf = codecgen.AppendCBORUint(buf, uint64(v.Qty))
+	buf = append(buf, "\x65price"...)
+	buf = codecgen.AppendCBORFloat64(buf, v.Price)
+	return buf, nil
+}
+
+func parseLineCBOR(r *codecgen.CBORReader, v *Line) {
+	if r.Null() {
+		return
+	}
+	var seen [3]bool
+	for n := r.MapLen(); n > 0; n-- {
+		switch key := r.Key(); string(key) {
+		case "sku":
+			r.Field(&seen[0])
+			if !r.Null() {
+				v.SKU = r.String()
+			}
+		case "qty":
+			r.Field(&seen[1])
+			if !r.Null() {
+				v.Qty = uint16(r.Uint(16))
+			}
+		case "price":
+			r.Field(&seen[2])
+			if !r.Null() {
+				v.Price = r.Float(64)
+			}
+		default:
+			r.Unknown(key, "sku", "qty", "price")
+		}
+	}
+	r.Leave()
+}
+
+func appendAddressJSON(buf []byte, v *Address, depth int) ([]byte, error) {
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	buf = append(buf, '{')
+	start := len(buf)
+	if len(v.Street) != 0 {
+		buf = append(buf, `"street":`...)
+		buf = codecgen.AppendJSONString(buf, v.Street)
+	}
+	if len(v.City) != 0 {
+		if len(buf) > start {
+			buf = append(buf, ',')
+		}
+		buf = append(buf, `"city":`...)
+		buf = codecgen.AppendJSONString(buf, v.City)
+	}
+	buf = append(buf, '}')
+	return buf, nil
+}
+
+func parseAddressJSON(r *codecgen.JSONReader, v *Address) {
+	if r.Null() || !r.BeginObject() {
+		return
+	}
+	for r.NextMember() {
+		switch key := r.Key(); string(key) {
+		case "street":
+			if !r.Null() {
+				v.Street = r.String()
+			}
+		case "city":
+			if !r.Null() {
+				v.City = r.String()
+			}
+		default:
+			r.Unknown(key, "street", "city")
+		}
+	}
+}
+
+func appendAddressMsgPack(buf []byte, v *Address, depth int) ([]byte, error) {
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	n := 2
+	if len(v.Street) == 0 {
+		n--
+	}
+	if len(v.City) == 0 {
+		n--
+	}
+	buf = codecgen.AppendMsgPackMapLen(buf, n)
+	if len(v.Street) != 0 {
+		buf = append(buf, "\xa6Street"...)
+		buf = codecgen.AppendMsgPackString(buf, v.Street)
+	}
+	if len(v.City) != 0 {
+		buf = append(buf, "\xa4City"...)
+		buf = codecgen.AppendMsgPackString(buf, v.City)
+	}
+	return buf, nil
+}
+
+func parseAddressMsgPack(r *codecgen.MsgPackReader, v *Address) {
+	if r.Nil() {
+		*v = Address{}
+		return
+	}
+	for n := r.MapLen(); n > 0; n-- {
+		switch string(r.Key()) {
+		case "Street":
+			v.Street = r.String()
+		case "City":
+			v.City = r.String()
+		default:
+			r.Skip()
+		}
+	}
+}
+
+func appendAddressCBOR(buf []byte, v *Address, depth int) ([]byte, error) {
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	n := 2
+	if len(v.Street) == 0 {
+		n--
+	}
+	if len(v.City) == 0 {
+		n--
+	}
+	buf = codecgen.AppendCBORMapLen(buf, n)
+	if len(v.Street) != 0 {
+		buf = append(buf, "\x66street"...)
+		buf = codecgen.AppendCBORString(buf, v.Street)
+	}
+	if len(v.City) != 0 {
+		buf = append(buf, "\x64city"...)
+		buf = codecgen.AppendCBORString(buf, v.City)
+	}
+	return buf, nil
+}
+
+func parseAddressCBOR(r *codecgen.CBORReader, v *Address) {
+	if r.Null() {
+		return
+	}
+	var seen [2]bool
+	for n := r.MapLen(); n > 0; n-- {
+		switch key := r.Key(); string(key) {
+		case "street":
+			r.Field(&seen[0])
+			if !r.Null() {
+				v.Street = r.String()
+			}
+		case "city":
+			r.Field(&seen[1])
+			if !r.Null() {
+				v.City = r.String()
+			}
+		default:
+			r.Unknown(key, "street", "city")
+		}
+	}
+	r.Leave()
+}
+
+func appendNodeJSON(buf []byte, v *Node, depth int) ([]byte, error) {
+	var err error
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	buf = append(buf, `{"name":`...)
+	buf = codecgen.AppendJSONString(buf, v.Name)
+	buf = append(buf, `,"children":`...)
+	if v.Children == nil {
+		buf = append(buf, "null"...)
+	} else {
+		buf = append(buf, '[')
+		for i1 := range v.Children {
+			if i1 > 0 {
+				buf = append(buf, ',')
+			}
+			if buf, err = appendNodeJSON(buf, &v.Children[i1], depth+1); err != nil {
+				return buf, err
+			}
+		}
+		buf = append(buf, ']')
+	}
+	if v.Next != nil {
+		buf = append(buf, `,"next":`...)
+		if buf, err = appendNodeJSON(buf, v.Next, depth+1); err != nil {
+			return buf, err
+		}
+	}
+	buf = append(buf, '}')
+	return buf, nil
+}
+
+func parseNodeJSON(r *codecgen.JSONReader, v *Node) {
+	if r.Null() || !r.BeginObject() {
+		return
+	}
+	for r.NextMember() {
+		switch key := r.Key(); string(key) {
+		case "name":
+			if !r.Null() {
+				v.Name = r.String()
+			}
+		case "children":
+			if r.Null() {
+				v.Children = nil
+			} else if r.BeginArray() {
+				n1 := 0
+				for ; r.NextItem(); n1++ {
+					v.Children = codecgen.ExtendJSON(v.Children, n1)
+					parseNodeJSON(r, &v.Children[n1])
+				}
+				v.Children = codecgen.TruncateJSON(v.Children, n1)
+			}
+		case "next":
+			if r.Null() {
+				v.Next = nil
+			} else {
+				if v.Next == nil {
+					v.Next = new(Node)
+				}
+				parseNodeJSON(r, v.Next)
+			}
+		default:
+			r.Unknown(key, "name", "children", "next")
+		}
+	}
+}
+
+func appendNodeMsgPack(buf []byte, v *Node, depth int) ([]byte, error) {
+	var err error
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	buf = append(buf, "\x83\xa4Name"...)
+	buf = codecgen.AppendMsgPackString(buf, v.Name)
+	buf = append(buf, "\xa8Children"...)
+	if v.Children == nil {
+		buf = codecgen.AppendMsgPackNil(buf)
+	} else {
+		buf = codecgen.AppendMsgPackArrayLen(buf, len(v.Children))
+		for i1 := range v.Children {
+			if buf, err = appendNodeMsgPack(buf, &v.Children[i1], depth+1); err != nil {
+				return buf, err
+			}
+		}
+	}
+	buf = append(buf, "\xa4Next"...)
+	if v.Next == nil {
+		buf = codecgen.AppendMsgPackNil(buf)
+	} else {
+		if buf, err = appendNodeMsgPack(buf, v.Next, depth+1); err != nil {
+			return buf, err
+		}
+	}
+	return buf, nil
+}
+
+func parseNodeMsgPack(r *codecgen.MsgPackReader, v *Node) {
+	if r.Nil() {
+		*v = Node{}
+		return
+	}
+	for n := r.MapLen(); n > 0; n-- {
+		switch string(r.Key()) {
+		case "Name":
+			v.Name = r.String()
+		case "Children":
+			if r.Nil() {
+				v.Children = nil
+			} else {
+				v.Children = codecgen.ResizeMsgPack(v.Children, r.ArrayLen())
+				for i1 := range v.Children {
+					parseNodeMsgPack(r, &v.Children[i1])
+				}
+			}
+		case "Next":
+			if r.Nil() {
+				v.Next = nil
+			} else {
+				if v.Next == nil {
+					v.Next = new(Node)
+				}
+				parseNodeMsgPack(r, v.Next)
+			}
+		default:
+			r.Skip()
+		}
+	}
+}
+
+func appendNodeCBOR(buf []byte, v *Node, depth int) ([]byte, error) {
+	var err error
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	n := 3
+	if v.Next == nil {
+		n--
+	}
+	buf = codecgen.AppendCBORMapLen(buf, n)
+	buf = append(buf, "\x64name"...)
+	buf = codecgen.AppendCBORString(buf, v.Name)
+	buf = append(buf, "\x68children"...)
+	if v.Children == nil {
+		buf = codecgen.AppendCBORNull(buf)
+	} else {
+		buf = codecgen.AppendCBORArrayLen(buf, len(v.Children))
+		for i1 := range v.Children {
+			if buf, err = appendNodeCBOR(buf, &v.Children[i1], depth+1); err != nil {
+				return buf, err
+			}
+		}
+	}
+	if v.Next != nil {
+		buf = append(buf, "\x64next"...)
+		if buf, err = appendNodeCBOR(buf, v.Next, depth+1); err != nil {
+			return buf, err
+		}
+	}
+	return buf, nil
+}
+
+func parseNodeCBOR(r *codecgen.CBORReader, v *Node) {
+	if r.Null() {
+		return
+	}
+	var seen [3]bool
+	for n := r.MapLen(); n > 0; n-- {
+		switch key := r.Key(); string(key) {
+		case "name":
+			r.Field(&seen[0])
+			if !r.Null() {
+				v.Name = r.String()
+			}
+		case "children":
+			r.Field(&seen[1])
+			if r.Null() {
+				v.Children = nil
+			} else {
+				v.Children = codecgen.ResizeCBOR(v.Children, r.ArrayLen())
+				for i1 := range v.Children {
+					parseNodeCBOR(r, &v.Children[i1])
+				}
+				r.Leave()
+			}
+		case "next":
+			r.Field(&seen[2])
+			if r.Null() {
+				v.Next = nil
+			} else {
+				if v.Next == nil {
+					v.Next = new(Node)
+				}
+				parseNodeCBOR(r, v.Next)
+			}
+		default:
+			r.Unknown(key, "name", "children", "next")
+		}
+	}
+	r.Leave()
+}
+
+func appendFlagJSON(buf []byte, v *Flag, depth int) ([]byte, error) {
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	buf = append(buf, '{')
+	if v.On {
+		buf = append(buf, `"on":`...)
+		buf = strconv.AppendBool(buf, v.On)
+	}
+	buf = append(buf, '}')
+	return buf, nil
+}
+
+func parseFlagJSON(r *codecgen.JSONReader, v *Flag) {
+	if r.Null() || !r.BeginObject() {
+		return
+	}
+	for r.NextMember() {
+		switch key := r.Key(); string(key) {
+		case "on":
+			if !r.Null() {
+				v.On = r.Bool()
+			}
+		default:
+			r.Unknown(key, "on")
+		}
+	}
+}
+
+func appendFlagMsgPack(buf []byte, v *Flag, depth int) ([]byte, error) {
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	n := 1
+	if !v.On {
+		n--
+	}
+	buf = codecgen.AppendMsgPackMapLen(buf, n)
+	if v.On {
+		buf = append(buf, "\xa2on"...)
+		buf = codecgen.AppendMsgPackBool(buf, v.On)
+	}
+	return buf, nil
+}
+
+func parseFlagMsgPack(r *codecgen.MsgPackReader, v *Flag) {
+	if r.Nil() {
+		*v = Flag{}
+		return
+	}
+	for n := r.MapLen(); n > 0; n-- {
+		switch string(r.Key()) {
+		case "on":
+			v.On = r.Bool()
+		default:
+			r.Skip()
+		}
+	}
+}
+
+func appendFlagCBOR(buf []byte, v *Flag, depth int) ([]byte, error) {
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	n := 1
+	if !v.On {
+		n--
+	}
+	buf = codecgen.AppendCBORMapLen(buf, n)
+	if v.On {
+		buf = append(buf, "\x62on"...)
+		buf = codecgen.AppendCBORBool(buf, v.On)
+	}
+	return buf, nil
+}
+
+func parseFlagCBOR(r *codecgen.CBORReader, v *Flag) {
+	if r.Null() {
+		return
+	}
+	var seen [1]bool
+	for n := r.MapLen(); n > 0; n-- {
+		switch key := r.Key(); string(key) {
+		case "on":
+			r.Field(&seen[0])
+			if !r.Null() {
+				v.On = r.Bool()
+			}
+		default:
+			r.Unknown(key, "on")
+		}
+	}
+	r.Leave()
+}
+
+func appendEmptyJSON(buf []byte, v *Empty, depth int) ([]byte, error) {
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	buf = append(buf, `{}`...)
+	return buf, nil
+}
+
+func parseEmptyJSON(r *codecgen.JSONReader, v *Empty) {
+	if r.Null() || !r.BeginObject() {
+		return
+	}
+	for r.NextMember() {
+		switch key := r.Key(); string(key) {
+		default:
+			r.Unknown(key)
+		}
+	}
+}
+
+func appendEmptyMsgPack(buf []byte, v *Empty, depth int) ([]byte, error) {
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	buf = append(buf, "\x80"...)
+	return buf, nil
+}
+
+func parseEmptyMsgPack(r *codecgen.MsgPackReader, v *Empty) {
+	if r.Nil() {
+		*v = Empty{}
+		return
+	}
+	for n := r.MapLen(); n > 0; n-- {
+		switch string(r.Key()) {
+		default:
+			r.Skip()
+		}
+	}
+}
+
+func appendEmptyCBOR(buf []byte, v *Empty, depth int) ([]byte, error) {
+	if depth > codecgen.MaxDepth {
+		return buf, codecgen.ErrFallback
+	}
+	buf = append(buf, "\xa0"...)
+	return buf, nil
+}
+
+func parseEmptyCBOR(r *codecgen.CBORReader, v *Empty) {
+	if r.Null() {
+		return
+	}
+	for n := r.MapLen(); n > 0; n-- {
+		switch key := r.Key(); string(key) {
+		default:
+			r.Unknown(key)
+		}
+	}
+	r.Leave()
+}
@@ -0,0 +1,75 @@
+// Package testdata holds the types of the codecgen tests. Run go generate
+// after changing them; a test checks that the generated file is current.
+package testdata
+
+//go:generate go run github.com/jeremyhahn/go-codec/cmd/codecgen -type Order,Node,Flag,Empty
+
+// Status is a defined integer type
+type Status int8
+
+// Label is a defined string type
+type Label string
+
+// Tags is a defined slice of strings, which msgpack decodes on a path of
+// its own
+type Tags []string
+
+// Order exercises every supported kind of field and the tag rules of each
+// format
+type Order struct {
+	ID       int64              `json:"id" msgpack:"id" cbor:"id"`
+	Number   int                `json:"number,omitempty" msgpack:"number,omitempty"`
+	Customer string             `json:"customer"`
+	Status   Status             `json:"status"`
+	Paid     bool               `json:"paid,omitempty" msgpack:",omitempty"`
+	Total    float64            `json:"total"`
+	Discount float32            `json:"discount,omitempty" cbor:"discount,omitempty"`
+	Small    uint8              `json:"small"`
+	Count    uint               `json:"count"`
+	Big      uint64             `json:"big"`
+	Code     int16              `json:"code"`
+	Rune     rune               `json:"rune"`
+	Note     *string            `json:"note,omitempty" msgpack:"note,omitempty"`
+	Label    Label              `json:"label"`
+	Tags     Tags               `json:"tags"`
+	Names    []string           `json:"names"`
+	Raw      []byte             `json:"raw" msgpack:"raw,omitempty"`
+	Lines    []Line             `json:"lines"`
+	Ship     *Address           `json:"ship,omitempty" cbor:"ship,omitempty"`
+	Bill     Address            `json:"bill,omitempty"`
+	Attrs    map[string]string  `json:"attrs,omitempty" msgpack:"attrs,omitempty" cbor:"attrs,omitempty"`
+	Scores   map[string][]int32 `json:"scores"`
+	Extra    map[string]*Line   `json:"extra"`
+	Matrix   [][]float64        `json:"matrix"`
+	Ignored  string             `json:"-" msgpack:"-" cbor:"-"`
+	Skipped  string             `json:"-"`
+	internal int
+}
+
+// Line is a struct reached only through Order
+type Line struct {
+	SKU   string  `json:"sku"`
+	Qty   uint16  `json:"qty"`
+	Price float64 `json:"price"`
+}
+
+// Address has only omitempty fields
+type Address struct {
+	Street string `json:"street,omitempty" msgpack:",omitempty"`
+	City   string `json:"city,omitempty" msgpack:",omitempty"`
+}
+
+// Node is recursive
+type Node struct {
+	Name     string `json:"name"`
+	Children []Node `json:"children"`
+	Next     *Node  `json:"next,omitempty"`
+}
+
+// Flag has a single omitempty field
+type Flag struct {
+	On bool `json:"on,omitempty" msgpack:"on,omitempty" cbor:"on,omitempty"`
+}
+
+// Empty has no fields
+type Empty struct{}
@@ -0,0 +1,71 @@
+package main
+
+import (
+	"bytes"
+	"os"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// runConvert converts the value of a file to another format, refusing
+// lossy conversions unless -lossy is given
+func runConvert(e *env, args []string) int {
+	fs := e.flags("convert", "[-from fmt] -to fmt [-o file] [-lossy] [file]")
+	from := fs.String("from", "", "input `format` (default: from extension or content)")
+	to := fs.String("to", "", "output `format` (default: from the -o extension)")
+	output := fs.String("o", "", "write to `file` instead of standard output")
+	lossy := fs.Bool("lossy", false, "convert even if data is lost, reporting each loss")
+	strict := fs.Bool("strict", false, "reject duplicate keys and other ambiguous input")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	name, ok := oneFile(fs)
+	if !ok {
+		return exitError
+	}
+
+	dst := *to
+	if dst == "" {
+		t, ok := codec.FromExtension(*output)
+		if *output == "" || !ok {
+			fs.Usage()
+			return exitError
+		}
+		dst = string(t)
+	}
+	dstType, err := parseFormat(dst)
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+
+	in, err := e.read(name)
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+	srcType, err := in.format(*from)
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+
+	var opts []codec.Option
+	if *lossy {
+		opts = append(opts, codec.WithAllowLossy(e.warn))
+	}
+	if *strict {
+		opts = append(opts, codec.WithStrict())
+	}
+	var out bytes.Buffer
+	if err := codec.Transcode(dstType, srcType, bytes.NewReader(in.data), &out, opts...); err != nil {
+		return e.errorf("%s: %v", in.name, err)
+	}
+
+	if *output != "" {
+		err = os.WriteFile(*output, out.Bytes(), 0o644)
+	} else {
+		_, err = out.WriteTo(e.stdout)
+	}
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+	return exitOK
+}
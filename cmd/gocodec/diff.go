@@ -0,0 +1,146 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strconv"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// runDiff compares the values of two files, which may be in different
+// formats, and prints each path at which they differ. Map members are
+// matched by key regardless of order; array items by index.
+func runDiff(e *env, args []string) int {
+	fs := e.flags("diff", "[-from fmt] [-a fmt] [-b fmt] file1 file2")
+	from := fs.String("from", "", "`format` of both files (default: from extension or content)")
+	fromA := fs.String("a", "", "`format` of the first file, overriding -from")
+	fromB := fs.String("b", "", "`format` of the second file, overriding -from")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	if fs.NArg() != 2 {
+		fs.Usage()
+		return exitError
+	}
+
+	a, _, err := e.decode(fs.Arg(0), or(*fromA, *from))
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+	b, _, err := e.decode(fs.Arg(1), or(*fromB, *from))
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+	d := differ{w: e.stdout}
+	d.diff("", a, b)
+	if d.found {
+		return exitFailure
+	}
+	return exitOK
+}
+
+// or returns s, or def if s is empty
+func or(s, def string) string {
+	if s != "" {
+		return s
+	}
+	return def
+}
+
+// differ writes the differences between two values as lines of
+// "- path: value" for removals, "+ path: value" for additions and
+// "~ path: old -> new" for changes
+type differ struct {
+	w     io.Writer
+	found bool
+}
+
+func (d *differ) diff(path string, a, b codec.Value) {
+	switch {
+	case sameInteger(a, b):
+		return
+	case a.Kind() == codec.KindMap && b.Kind() == codec.KindMap:
+		d.diffMaps(path, a.Members(), b.Members())
+	case a.Kind() == codec.KindArray && b.Kind() == codec.KindArray:
+		d.diffArrays(path, a.Array(), b.Array())
+	case a.Kind() == codec.KindTagged && b.Kind() == codec.KindTagged && a.Tag() == b.Tag():
+		d.diff(path, a.Content(), b.Content())
+	case !a.Equal(b):
+		d.line('~', path, formatValue(a)+" -> "+formatValue(b))
+	}
+}
+
+func (d *differ) diffMaps(path string, a, b []codec.Member) {
+	inB := make(map[string]codec.Value, len(b))
+	for _, m := range b {
+		inB[m.Key.String()] = m.Value
+	}
+	inA := make(map[string]bool, len(a))
+	for _, m := range a {
+		key := m.Key.String()
+		inA[key] = true
+		if v, ok := inB[key]; ok {
+			d.diff(joinPath(path, key), m.Value, v)
+		} else {
+			d.line('-', joinPath(path, key), formatValue(m.Value))
+		}
+	}
+	for _, m := range b {
+		if key := m.Key.String(); !inA[key] {
+			d.line('+', joinPath(path, key), formatValue(m.Value))
+		}
+	}
+}
+
+func (d *differ) diffArrays(path string, a, b []codec.Value) {
+	for i := 0; i < max(len(a), len(b)); i++ {
+		itemPath := path + "[" + strconv.Itoa(i) + "]"
+		switch {
+		case i >= len(b):
+			d.line('-', itemPath, formatValue(a[i]))
+		case i >= len(a):
+			d.line('+', itemPath, formatValue(b[i]))
+		default:
+			d.diff(itemPath, a[i], b[i])
+		}
+	}
+}
+
+func (d *differ) line(op byte, path, text string) {
+	d.found = true
+	if path == "" {
+		path = "."
+	}
+	fmt.Fprintf(d.w, "%c %s: %s\n", op, path, text)
+}
+
+// sameInteger reports whether a and b are integers of equal value, which
+// formats may decode as signed or unsigned
+func sameInteger(a, b codec.Value) bool {
+	switch {
+	case a.Kind() == codec.KindInt && b.Kind() == codec.KindUint:
+		return a.Int() >= 0 && uint64(a.Int()) == b.Uint()
+	case a.Kind() == codec.KindUint && b.Kind() == codec.KindInt:
+		return sameInteger(b, a)
+	default:
+		return false
+	}
+}
+
+// formatValue returns v as text, quoting strings so they are told apart
+// from other kinds
+func formatValue(v codec.Value) string {
+	if v.Kind() == codec.KindString {
+		return strconv.Quote(v.String())
+	}
+	return v.String()
+}
+
+// joinPath appends key to a dotted path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,218 @@
+// Command gocodec inspects and converts data in any format supported by
+// go-codec without a Go type to decode into. It is meant for looking at
+// MessagePack, CBOR and BSON payloads pulled from queues and caches.
+//
+// Usage:
+//
+//	gocodec convert  [-from fmt] -to fmt [-o file] [-lossy] [file]
+//	gocodec validate [-from fmt] [-strict] [-json] [file...]
+//	gocodec pretty   [-from fmt] [-to json|yaml] [-color] [file]
+//	gocodec sniff    [file...]
+//	gocodec schema   [-from fmt] [file]
+//	gocodec diff     [-from fmt] [-a fmt] [-b fmt] file1 file2
+//
+// A file of "-", or no file, reads standard input. The input format is
+// taken from -from, then the file extension, then the leading bytes of the
+// data. Formats are named by codec type ("msgpack") or extension ("yml").
+// Only codecs compiled in with their build tags are available.
+package main
+
+import (
+	"bytes"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	codec "github.com/jeremyhahn/go-codec"
+	_ "github.com/jeremyhahn/go-codec/pkg/bson"
+	_ "github.com/jeremyhahn/go-codec/pkg/cbor"
+	_ "github.com/jeremyhahn/go-codec/pkg/json"
+	_ "github.com/jeremyhahn/go-codec/pkg/msgpack"
+	_ "github.com/jeremyhahn/go-codec/pkg/toml"
+	_ "github.com/jeremyhahn/go-codec/pkg/yaml"
+)
+
+// Exit codes follow diff(1): 1 reports a negative result such as invalid
+// input or differing files, 2 a usage error or failure
+const (
+	exitOK      = 0
+	exitFailure = 1
+	exitError   = 2
+)
+
+const usage = `usage: gocodec <command> [flags] [file...]
+
+Commands:
+  convert   convert a value to another format
+  validate  check that files decode and report where they do not
+  pretty    print a value as indented JSON or YAML
+  sniff     detect the format of files
+  schema    print the Avro schema inferred from a sample value
+  diff      compare the values of two files, in any formats
+
+Run "gocodec <command> -h" for the flags of a command.
+`
+
+// command runs a subcommand with its arguments
+type command func(env *env, args []string) int
+
+var commands = map[string]command{
+	"convert":  runConvert,
+	"validate": runValidate,
+	"pretty":   runPretty,
+	"sniff":    runSniff,
+	"schema":   runSchema,
+	"diff":     runDiff,
+}
+
+// env holds the standard streams of a run, so commands can be tested
+type env struct {
+	stdin  io.Reader
+	stdout io.Writer
+	stderr io.Writer
+}
+
+func main() {
+	os.Exit(run(os.Args[1:], &env{stdin: os.Stdin, stdout: os.Stdout, stderr: os.Stderr}))
+}
+
+// run dispatches args to a command and returns the exit code
+func run(args []string, e *env) int {
+	if len(args) == 0 {
+		fmt.Fprint(e.stderr, usage)
+		return exitError
+	}
+	if args[0] == "-h" || args[0] == "-help" || args[0] == "help" {
+		fmt.Fprint(e.stdout, usage)
+		return exitOK
+	}
+	cmd, ok := commands[args[0]]
+	if !ok {
+		fmt.Fprintf(e.stderr, "gocodec: unknown command %q\n\n%s", args[0], usage)
+		return exitError
+	}
+	return cmd(e, args[1:])
+}
+
+// flags returns a flag set for the named command that writes its usage to
+// stderr
+func (e *env) flags(name, synopsis string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(e.stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(e.stderr, "usage: gocodec %s %s\n", name, synopsis)
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+// parse parses args into fs, returning the exit code to stop with if
+// parsing failed or help was requested
+func parse(fs *flag.FlagSet, args []string) (int, bool) {
+	if err := fs.Parse(args); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return exitOK, false
+		}
+		return exitError, false
+	}
+	return exitOK, true
+}
+
+// errorf reports a failure of the command on stderr and returns exitError
+func (e *env) errorf(format string, args ...any) int {
+	fmt.Fprintf(e.stderr, "gocodec: "+format+"\n", args...)
+	return exitError
+}
+
+// warn reports each loss of a lossy conversion on stderr
+func (e *env) warn(l codec.Loss) {
+	fmt.Fprintf(e.stderr, "gocodec: warning: %s\n", l)
+}
+
+// input is a file, or standard input, read whole
+type input struct {
+	name string
+	data []byte
+}
+
+// read reads the named file, or standard input for "" or "-"
+func (e *env) read(name string) (input, error) {
+	if name == "" || name == "-" {
+		data, err := io.ReadAll(e.stdin)
+		return input{name: "<stdin>", data: data}, err
+	}
+	data, err := os.ReadFile(name)
+	return input{name: name, data: data}, err
+}
+
+// parseFormat resolves a format named by codec type or file extension
+func parseFormat(name string) (codec.Type, error) {
+	t := codec.Type(strings.ToLower(name))
+	if codec.IsSupported(t) {
+		return t, nil
+	}
+	if t, ok := codec.FromExtension(name); ok && codec.IsSupported(t) {
+		return t, nil
+	}
+	return "", fmt.Errorf("unknown or unsupported format %q (supported: %s)", name, supportedList())
+}
+
+// supportedList returns the codec types compiled in, comma separated
+func supportedList() string {
+	types := codec.SupportedCodecs()
+	names := make([]string, len(types))
+	for i, t := range types {
+		names[i] = string(t)
+	}
+	return strings.Join(names, ", ")
+}
+
+// format returns the format of in: the one named by flag if set, then the
+// one of its file extension, then the one detected from its data
+func (in input) format(flag string) (codec.Type, error) {
+	if flag != "" {
+		return parseFormat(flag)
+	}
+	if t, ok := codec.FromExtension(in.name); ok && codec.IsSupported(t) {
+		return t, nil
+	}
+	if t, c := codec.Detect(in.data); c != codec.ConfidenceNone && codec.IsSupported(t) {
+		return t, nil
+	}
+	return "", fmt.Errorf("%s: cannot detect the format; use -from", in.name)
+}
+
+// decode reads the value of the named file in the format chosen by flag,
+// reporting losses as warnings
+func (e *env) decode(name, flag string, opts ...codec.Option) (codec.Value, codec.Type, error) {
+	in, err := e.read(name)
+	if err != nil {
+		return codec.Value{}, "", err
+	}
+	t, err := in.format(flag)
+	if err != nil {
+		return codec.Value{}, "", err
+	}
+	opts = append(opts, codec.WithAllowLossy(e.warn))
+	v, err := codec.DecodeValue(t, bytes.NewReader(in.data), opts...)
+	if err != nil {
+		return codec.Value{}, t, fmt.Errorf("%s: %w", in.name, err)
+	}
+	return v, t, nil
+}
+
+// oneFile returns the single optional file argument of fs
+func oneFile(fs *flag.FlagSet) (string, bool) {
+	switch fs.NArg() {
+	case 0:
+		return "", true
+	case 1:
+		return fs.Arg(0), true
+	default:
+		fs.Usage()
+		return "", false
+	}
+}
@@ -0,0 +1,227 @@
+//go:build codec_json && codec_yaml && codec_toml && codec_msgpack && codec_bson && codec_cbor && codec_avro && codec_protobuf
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// gocodec runs the command with args and stdin, returning its exit code,
+// stdout and stderr
+func gocodec(t *testing.T, stdin string, args ...string) (int, string, string) {
+	t.Helper()
+	var stdout, stderr bytes.Buffer
+	code := run(args, &env{stdin: strings.NewReader(stdin), stdout: &stdout, stderr: &stderr})
+	return code, stdout.String(), stderr.String()
+}
+
+// writeFile writes data to name in a temporary directory and returns its path
+func writeFile(t *testing.T, name, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(data), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestRun_Usage(t *testing.T) {
+	if code, _, stderr := gocodec(t, ""); code != exitError || !strings.Contains(stderr, "Commands:") {
+		t.Errorf("No command: exit %d, stderr %q", code, stderr)
+	}
+	if code, _, stderr := gocodec(t, "", "frobnicate"); code != exitError || !strings.Contains(stderr, `unknown command "frobnicate"`) {
+		t.Errorf("Unknown command: exit %d, stderr %q", code, stderr)
+	}
+	if code, _, _ := gocodec(t, "", "convert", "-h"); code != exitOK {
+		t.Errorf("convert -h: exit %d", code)
+	}
+}
+
+func TestConvert_RoundTrip(t *testing.T) {
+	code, cbor, stderr := gocodec(t, `{"id":1,"tags":["a"]}`, "convert", "-to", "cbor")
+	if code != exitOK {
+		t.Fatalf("convert to cbor: exit %d: %s", code, stderr)
+	}
+	code, out, stderr := gocodec(t, cbor, "convert", "-from", "cbor", "-to", "json")
+	if code != exitOK {
+		t.Fatalf("convert to json: exit %d: %s", code, stderr)
+	}
+	if out != "{\"id\":1,\"tags\":[\"a\"]}\n" {
+		t.Errorf("Round trip = %q", out)
+	}
+}
+
+func TestConvert_OutputExtension(t *testing.T) {
+	in := writeFile(t, "in.json", `{"name":"x"}`)
+	out := filepath.Join(t.TempDir(), "out.yml")
+	if code, _, stderr := gocodec(t, "", "convert", "-o", out, in); code != exitOK {
+		t.Fatalf("convert: exit %d: %s", code, stderr)
+	}
+	data, err := os.ReadFile(out)
+	if err != nil || string(data) != "name: x\n" {
+		t.Errorf("Wrote %q, %v", data, err)
+	}
+}
+
+func TestConvert_Lossy(t *testing.T) {
+	code, out, stderr := gocodec(t, `{"a":null}`, "convert", "-from", "json", "-to", "toml")
+	if code != exitError || out != "" || !strings.Contains(stderr, "null omitted at a") {
+		t.Errorf("Lossy convert: exit %d, stdout %q, stderr %q", code, out, stderr)
+	}
+	code, _, stderr = gocodec(t, `{"a":null}`, "convert", "-lossy", "-from", "json", "-to", "toml")
+	if code != exitOK || !strings.Contains(stderr, "warning: toml: null omitted at a") {
+		t.Errorf("convert -lossy: exit %d, stderr %q", code, stderr)
+	}
+}
+
+func TestConvert_UnknownFormat(t *testing.T) {
+	code, _, stderr := gocodec(t, `{}`, "convert", "-to", "xml")
+	if code != exitError || !strings.Contains(stderr, `unknown or unsupported format "xml"`) {
+		t.Errorf("exit %d, stderr %q", code, stderr)
+	}
+	code, _, stderr = gocodec(t, "\x93\x01\x02\x03", "convert", "-to", "json")
+	if code != exitError || !strings.Contains(stderr, "cannot detect the format") {
+		t.Errorf("exit %d, stderr %q", code, stderr)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	good := writeFile(t, "good.yaml", "a: 1\n")
+	bad := writeFile(t, "bad.json", "{\"a\": tru}")
+
+	code, out, _ := gocodec(t, "", "validate", good, bad)
+	if code != exitFailure {
+		t.Errorf("validate: exit %d, want %d", code, exitFailure)
+	}
+	lines := strings.Split(strings.TrimSpace(out), "\n")
+	if len(lines) != 2 || lines[0] != good+": ok (yaml)" || !strings.HasPrefix(lines[1], bad+": json: syntax error") {
+		t.Errorf("validate printed %q", out)
+	}
+}
+
+func TestValidate_JSON(t *testing.T) {
+	_, out, _ := gocodec(t, "a: 1\na: 2\n", "validate", "-json", "-strict", "-from", "yaml")
+	var r report
+	if err := json.Unmarshal([]byte(out), &r); err != nil {
+		t.Fatalf("validate -json printed %q: %v", out, err)
+	}
+	if r.Valid || r.Codec != "yaml" || r.Kind != "duplicate key" || r.Line != 2 {
+		t.Errorf("Report = %+v", r)
+	}
+}
+
+func TestPretty(t *testing.T) {
+	code, out, _ := gocodec(t, `{"a":[1,true]}`, "pretty")
+	want := "{\n  \"a\": [\n    1,\n    true\n  ]\n}\n"
+	if code != exitOK || out != want {
+		t.Errorf("pretty: exit %d, printed %q, want %q", code, out, want)
+	}
+
+	_, out, _ = gocodec(t, `{"a":"b"}`, "pretty", "-to", "yaml")
+	if out != "a: b\n" {
+		t.Errorf("pretty -to yaml printed %q", out)
+	}
+
+	code, _, stderr := gocodec(t, `{}`, "pretty", "-to", "cbor")
+	if code != exitError || !strings.Contains(stderr, "not cbor") {
+		t.Errorf("pretty -to cbor: exit %d, stderr %q", code, stderr)
+	}
+}
+
+func TestColorize(t *testing.T) {
+	got := string(colorize([]byte(`{"k\"": "v", "n": -1.5e3, "t": null}`)))
+	want := `{` + colorKey + `"k\""` + colorReset + `: ` + colorString + `"v"` + colorReset +
+		`, ` + colorKey + `"n"` + colorReset + `: ` + colorNumber + `-1.5e3` + colorReset +
+		`, ` + colorKey + `"t"` + colorReset + `: ` + colorLit + `null` + colorReset + `}`
+	if got != want {
+		t.Errorf("colorize = %q, want %q", got, want)
+	}
+}
+
+func TestSniff(t *testing.T) {
+	code, out, _ := gocodec(t, "[table]\nkey = 1\n", "sniff")
+	if code != exitOK || out != "<stdin>: toml (medium confidence)\n" {
+		t.Errorf("sniff: exit %d, printed %q", code, out)
+	}
+	code, out, _ = gocodec(t, "\x93\x01\x02\x03", "sniff")
+	if code != exitFailure || out != "<stdin>: unknown\n" {
+		t.Errorf("sniff: exit %d, printed %q", code, out)
+	}
+}
+
+func TestSchema(t *testing.T) {
+	code, out, stderr := gocodec(t, `{"id":1,"user-name":"x","score":1.5,"tags":[null,"a"],"parent":null}`, "schema")
+	if code != exitOK {
+		t.Fatalf("schema: exit %d: %s", code, stderr)
+	}
+	var schema struct {
+		Type   string `json:"type"`
+		Fields []struct {
+			Name string `json:"name"`
+			Type any    `json:"type"`
+		} `json:"fields"`
+	}
+	if err := json.Unmarshal([]byte(out), &schema); err != nil {
+		t.Fatalf("schema printed %q: %v", out, err)
+	}
+	want := map[string]string{
+		"id":        `"long"`,
+		"user_name": `"string"`,
+		"score":     `"double"`,
+		"tags":      `{"items":"string","type":"array"}`,
+		"parent":    `["null","string"]`,
+	}
+	if schema.Type != "record" || len(schema.Fields) != len(want) {
+		t.Fatalf("schema = %s", out)
+	}
+	for _, f := range schema.Fields {
+		typ, _ := json.Marshal(f.Type)
+		if string(typ) != want[f.Name] {
+			t.Errorf("Field %s has type %s, want %s", f.Name, typ, want[f.Name])
+		}
+	}
+}
+
+func TestAvroName(t *testing.T) {
+	tests := map[string]string{"ok_1": "ok_1", "user-id": "user_id", "1st": "_1st", "": "_", "naïve": "na_ve"}
+	for key, want := range tests {
+		if got := avroName(key); got != want {
+			t.Errorf("avroName(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+func TestDiff(t *testing.T) {
+	a := writeFile(t, "a.json", `{"name":"Ann","age":41,"tags":["x","y"],"id":1}`)
+	code, packed, _ := gocodec(t, `{"age":41,"name":"Bob","tags":["x"],"new":true,"id":1}`, "convert", "-to", "msgpack")
+	if code != exitOK {
+		t.Fatalf("convert: exit %d", code)
+	}
+	b := writeFile(t, "b.bin", packed)
+
+	code, out, stderr := gocodec(t, "", "diff", "-b", "msgpack", a, b)
+	want := "~ name: \"Ann\" -> \"Bob\"\n- tags[1]: \"y\"\n+ new: true\n"
+	if code != exitFailure || out != want {
+		t.Errorf("diff: exit %d, printed %q, want %q (stderr %q)", code, out, want, stderr)
+	}
+
+	if code, out, _ := gocodec(t, "", "diff", a, a); code != exitOK || out != "" {
+		t.Errorf("diff of a file with itself: exit %d, printed %q", code, out)
+	}
+	if code, _, _ := gocodec(t, "", "diff", a); code != exitError {
+		t.Errorf("diff of one file: exit %d", code)
+	}
+}
+
+func TestDiff_Root(t *testing.T) {
+	a := writeFile(t, "a.json", `1`)
+	b := writeFile(t, "b.json", `"1"`)
+	if _, out, _ := gocodec(t, "", "diff", a, b); out != "~ .: 1 -> \"1\"\n" {
+		t.Errorf("diff printed %q", out)
+	}
+}
@@ -0,0 +1,115 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// ANSI colors of JSON tokens
+const (
+	colorReset  = "\x1b[0m"
+	colorKey    = "\x1b[34;1m"
+	colorString = "\x1b[32m"
+	colorNumber = "\x1b[36m"
+	colorLit    = "\x1b[33m"
+)
+
+// runPretty prints the value of a file as indented JSON or YAML. Data JSON
+// and YAML cannot hold, such as byte strings and tags, is written in its
+// closest form with a warning.
+func runPretty(e *env, args []string) int {
+	fs := e.flags("pretty", "[-from fmt] [-to json|yaml] [-color] [file]")
+	from := fs.String("from", "", "input `format` (default: from extension or content)")
+	to := fs.String("to", "json", "output `format`, json or yaml")
+	color := fs.Bool("color", false, "colorize JSON output")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	name, ok := oneFile(fs)
+	if !ok {
+		return exitError
+	}
+	dst, err := parseFormat(*to)
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+	if dst != codec.JSON && dst != codec.YAML {
+		return e.errorf("pretty writes json or yaml, not %s", dst)
+	}
+
+	v, _, err := e.decode(name, *from)
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+	var out bytes.Buffer
+	if err := codec.EncodeValue(dst, &out, v, codec.WithAllowLossy(e.warn)); err != nil {
+		return e.errorf("%v", err)
+	}
+	if dst == codec.JSON {
+		var indented bytes.Buffer
+		if err := json.Indent(&indented, out.Bytes(), "", "  "); err != nil {
+			return e.errorf("%v", err)
+		}
+		out = indented
+		if *color {
+			out = *bytes.NewBuffer(colorize(out.Bytes()))
+		}
+	}
+	if _, err := io.Copy(e.stdout, &out); err != nil {
+		return e.errorf("%v", err)
+	}
+	return exitOK
+}
+
+// colorize returns indented JSON with ANSI colors for keys, strings,
+// numbers and literals
+func colorize(data []byte) []byte {
+	var b []byte
+	for i := 0; i < len(data); {
+		c := data[i]
+		switch {
+		case c == '"':
+			end := stringEnd(data, i)
+			color := colorString
+			if rest := bytes.TrimLeft(data[end:], " "); len(rest) > 0 && rest[0] == ':' {
+				color = colorKey
+			}
+			b = append(append(append(b, color...), data[i:end]...), colorReset...)
+			i = end
+		case c == '-' || (c >= '0' && c <= '9'):
+			end := i + 1
+			for end < len(data) && bytes.IndexByte([]byte("0123456789.eE+-"), data[end]) >= 0 {
+				end++
+			}
+			b = append(append(append(b, colorNumber...), data[i:end]...), colorReset...)
+			i = end
+		case c == 't' || c == 'f' || c == 'n':
+			end := i + 1
+			for end < len(data) && data[end] >= 'a' && data[end] <= 'z' {
+				end++
+			}
+			b = append(append(append(b, colorLit...), data[i:end]...), colorReset...)
+			i = end
+		default:
+			b = append(b, c)
+			i++
+		}
+	}
+	return b
+}
+
+// stringEnd returns the offset just past the JSON string starting at i
+func stringEnd(data []byte, i int) int {
+	for i++; i < len(data); i++ {
+		switch data[i] {
+		case '\\':
+			i++
+		case '"':
+			return i + 1
+		}
+	}
+	return len(data)
+}
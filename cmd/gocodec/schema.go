@@ -0,0 +1,119 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"strings"
+	"time"
+	"unicode"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/avro"
+)
+
+var (
+	timeType  = reflect.TypeOf(time.Time{})
+	bytesType = reflect.TypeOf([]byte(nil))
+)
+
+// runSchema prints the Avro schema pkg/avro infers for the Go type of a
+// sample value. The type is built from the sample instead of loaded from
+// a Go package: maps become structs, null becomes an optional string and
+// arrays take the type of their first non-null item.
+func runSchema(e *env, args []string) int {
+	fs := e.flags("schema", "[-from fmt] [file]")
+	from := fs.String("from", "", "sample `format` (default: from extension or content)")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	name, ok := oneFile(fs)
+	if !ok {
+		return exitError
+	}
+
+	v, _, err := e.decode(name, *from)
+	if err != nil {
+		return e.errorf("%v", err)
+	}
+	schema := avro.SchemaJSONOf(sampleType(v))
+	if schema == "" {
+		return e.errorf("%v", codec.ErrCodecNotSupported{CodecType: codec.Avro})
+	}
+	var out bytes.Buffer
+	if err := json.Indent(&out, []byte(schema), "", "  "); err != nil {
+		return e.errorf("%v", err)
+	}
+	out.WriteByte('\n')
+	if _, err := out.WriteTo(e.stdout); err != nil {
+		return e.errorf("%v", err)
+	}
+	return exitOK
+}
+
+// sampleType returns the Go type of v as pkg/avro would see it
+func sampleType(v codec.Value) reflect.Type {
+	switch v.Kind() {
+	case codec.KindBool:
+		return reflect.TypeOf(false)
+	case codec.KindInt, codec.KindUint:
+		return reflect.TypeOf(int64(0))
+	case codec.KindFloat:
+		return reflect.TypeOf(float64(0))
+	case codec.KindBytes:
+		return bytesType
+	case codec.KindTime:
+		return timeType
+	case codec.KindArray:
+		for _, item := range v.Array() {
+			if !item.IsNull() {
+				return reflect.SliceOf(sampleType(item))
+			}
+		}
+		return reflect.TypeOf([]string(nil))
+	case codec.KindMap:
+		return structType(v.Members())
+	case codec.KindTagged:
+		return sampleType(v.Content())
+	case codec.KindNull:
+		return reflect.TypeOf((*string)(nil))
+	default:
+		return reflect.TypeOf("")
+	}
+}
+
+// structType returns a struct type with an exported field for each member,
+// named in Avro by its key
+func structType(members []codec.Member) reflect.Type {
+	fields := make([]reflect.StructField, 0, len(members))
+	seen := make(map[string]bool, len(members))
+	for i, m := range members {
+		name := avroName(m.Key.String())
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		fields = append(fields, reflect.StructField{
+			Name: fmt.Sprintf("F%d", i),
+			Type: sampleType(m.Value),
+			Tag:  reflect.StructTag(fmt.Sprintf(`avro:%q`, name)),
+		})
+	}
+	return reflect.StructOf(fields)
+}
+
+// avroName returns key as a valid Avro name, replacing characters other
+// than letters, digits and underscores with underscores
+func avroName(key string) string {
+	name := strings.Map(func(r rune) rune {
+		if r == '_' || (r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r))) {
+			return r
+		}
+		return '_'
+	}, key)
+	if name == "" || unicode.IsDigit(rune(name[0])) {
+		name = "_" + name
+	}
+	return name
+}
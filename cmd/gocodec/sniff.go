@@ -0,0 +1,37 @@
+package main
+
+import (
+	"fmt"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// runSniff prints the format detected from the leading bytes of each file
+// and how confident the detection is. The file extension is not consulted.
+func runSniff(e *env, args []string) int {
+	fs := e.flags("sniff", "[file...]")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	code := exitOK
+	for _, name := range files {
+		in, err := e.read(name)
+		if err != nil {
+			code = e.errorf("%v", err)
+			continue
+		}
+		t, c := codec.Detect(in.data)
+		if c == codec.ConfidenceNone {
+			fmt.Fprintf(e.stdout, "%s: unknown\n", in.name)
+			code = max(code, exitFailure)
+			continue
+		}
+		fmt.Fprintf(e.stdout, "%s: %s (%s confidence)\n", in.name, t, c)
+	}
+	return code
+}
@@ -0,0 +1,96 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// report is the outcome of validating one file, written as a JSON line by
+// validate -json
+type report struct {
+	File   string     `json:"file"`
+	Codec  codec.Type `json:"codec,omitempty"`
+	Valid  bool       `json:"valid"`
+	Kind   string     `json:"kind,omitempty"`
+	Offset *int64     `json:"offset,omitempty"`
+	Line   int        `json:"line,omitempty"`
+	Column int        `json:"column,omitempty"`
+	Path   string     `json:"path,omitempty"`
+	Error  string     `json:"error,omitempty"`
+}
+
+// runValidate decodes each file and reports whether it is well-formed,
+// with the kind and position of the error if it is not
+func runValidate(e *env, args []string) int {
+	fs := e.flags("validate", "[-from fmt] [-strict] [-json] [file...]")
+	from := fs.String("from", "", "input `format` (default: from extension or content)")
+	strict := fs.Bool("strict", false, "reject duplicate keys and lossy values")
+	asJSON := fs.Bool("json", false, "write one JSON report per file")
+	if code, ok := parse(fs, args); !ok {
+		return code
+	}
+	files := fs.Args()
+	if len(files) == 0 {
+		files = []string{"-"}
+	}
+
+	code := exitOK
+	for _, name := range files {
+		r := e.validate(name, *from, *strict)
+		if !r.Valid {
+			code = exitFailure
+		}
+		if *asJSON {
+			line, _ := json.Marshal(r)
+			fmt.Fprintf(e.stdout, "%s\n", line)
+			continue
+		}
+		switch {
+		case r.Valid:
+			fmt.Fprintf(e.stdout, "%s: ok (%s)\n", r.File, r.Codec)
+		default:
+			fmt.Fprintf(e.stdout, "%s: %s\n", r.File, r.Error)
+		}
+	}
+	return code
+}
+
+// validate decodes the named file and returns its report
+func (e *env) validate(name, from string, strict bool) report {
+	in, err := e.read(name)
+	if err != nil {
+		return report{File: name, Error: err.Error()}
+	}
+	r := report{File: in.name}
+	if r.Codec, err = in.format(from); err != nil {
+		r.Error = err.Error()
+		return r
+	}
+
+	var opts []codec.Option
+	if strict {
+		opts = append(opts, codec.WithStrict())
+	} else {
+		opts = append(opts, codec.WithAllowLossy(nil))
+	}
+	_, err = codec.DecodeValue(r.Codec, bytes.NewReader(in.data), opts...)
+	if err == nil {
+		r.Valid = true
+		return r
+	}
+
+	r.Error = err.Error()
+	var de codec.DecodeError
+	if errors.As(err, &de) {
+		r.Kind = de.Kind.String()
+		if de.Offset >= 0 {
+			r.Offset = &de.Offset
+		}
+		r.Line, r.Column, r.Path = de.Line, de.Column, de.Path
+	}
+	return r
+}
@@ -31,6 +31,33 @@ type Codec[T any] interface {
 	Unmarshal(data []byte, v *T) error
 }
 
+// Encoder writes a sequence of values to an underlying stream
+type Encoder[T any] interface {
+	// Encode serializes the next value to the stream
+	Encode(data T) error
+}
+
+// Decoder reads a sequence of values from an underlying stream
+type Decoder[T any] interface {
+	// Decode deserializes the next value from the stream into the provided type.
+	// It returns io.EOF once the stream holds no further values.
+	Decode(data *T) error
+}
+
+// StreamCodec extends Codec with encoder and decoder sessions. Unlike
+// Codec.Encode and Codec.Decode, a session keeps its state (including any
+// read-ahead buffering) across calls, so many values can be written to or
+// read from a single long-lived reader or writer.
+type StreamCodec[T any] interface {
+	Codec[T]
+
+	// NewEncoder returns an encoder session that writes successive values to w
+	NewEncoder(w io.Writer) Encoder[T]
+
+	// NewDecoder returns a decoder session that reads successive values from r
+	NewDecoder(r io.Reader) Decoder[T]
+}
+
 // OptimizedCodec extends Codec with zero-allocation methods
 type OptimizedCodec[T any] interface {
 	Codec[T]
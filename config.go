@@ -0,0 +1,49 @@
+package codec
+
+// Config holds the settings shared by every codec. Codec packages embed it
+// in their configuration so that the options defined here, such as
+// WithLimits and WithStrict, apply to all of them.
+type Config struct {
+	Limits Limits
+
+	// Strict rejects unknown fields, duplicate keys and lossy numeric
+	// conversions
+	Strict bool
+}
+
+func (c *Config) shared() *Config {
+	return c
+}
+
+// sharedConfig is implemented by configurations that embed Config
+type sharedConfig interface {
+	shared() *Config
+}
+
+// newSharedOption returns an Option named name that configures the Config
+// embedded in any codec's configuration
+func newSharedOption(name string, fn func(cfg *Config)) Option {
+	return Option{
+		name: name,
+		apply: func(cfg any) bool {
+			s, ok := cfg.(sharedConfig)
+			if ok {
+				fn(s.shared())
+			}
+			return ok
+		},
+	}
+}
+
+// WithStrict makes decoding reject input that would otherwise be accepted
+// with information silently dropped or altered: fields with no counterpart
+// in the destination type (ErrUnknownField), keys repeated within a map or
+// object (ErrDuplicateKey), and numbers that would be truncated, wrapped or
+// overflow to fit the destination (ErrTypeMismatch). Rounding a fractional
+// number to the precision of a float32 is not considered lossy. The option
+// applies to every codec.
+func WithStrict() Option {
+	return newSharedOption("codec.WithStrict", func(cfg *Config) {
+		cfg.Strict = true
+	})
+}
@@ -0,0 +1,201 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"unicode/utf8"
+)
+
+// Confidence ranks how certain Detect is of the format it reports
+type Confidence int
+
+const (
+	// ConfidenceNone reports that no format was recognized
+	ConfidenceNone Confidence = iota
+
+	// ConfidenceLow reports input that is plausibly of the format but
+	// carries no distinctive marker, such as text of "key: value" lines
+	ConfidenceLow
+
+	// ConfidenceMedium reports input that starts like the format, such as
+	// a YAML document marker or a TOML table header
+	ConfidenceMedium
+
+	// ConfidenceHigh reports input carrying a marker of the format, such
+	// as a magic number or a BSON length header matching the input, or
+	// that was verified to be well-formed
+	ConfidenceHigh
+)
+
+// String returns the name of the confidence level
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceNone:
+		return "none"
+	case ConfidenceLow:
+		return "low"
+	case ConfidenceMedium:
+		return "medium"
+	case ConfidenceHigh:
+		return "high"
+	default:
+		return "unknown"
+	}
+}
+
+var (
+	// cborSelfDescribe is CBOR tag 55799, which marks data as CBOR
+	cborSelfDescribe = []byte{0xd9, 0xd9, 0xf7}
+
+	// avroContainerMagic starts an Avro object container file
+	avroContainerMagic = []byte("Obj\x01")
+
+	// avroSingleObjectMagic starts an Avro single-object encoding
+	avroSingleObjectMagic = []byte{0xc3, 0x01}
+
+	// utf8BOM may start text formats
+	utf8BOM = []byte{0xef, 0xbb, 0xbf}
+)
+
+// Detect guesses the format of data from its leading bytes. It recognizes
+// the CBOR self-describe tag, Avro container files and single-object
+// encoding, BSON documents, JSON, YAML and TOML. Formats without a
+// distinctive marker, such as MessagePack, Protocol Buffers and plain CBOR
+// or Avro values, are not detected. It returns ConfidenceNone and an empty
+// Type if no format was recognized.
+func Detect(data []byte) (Type, Confidence) {
+	switch {
+	case bytes.HasPrefix(data, cborSelfDescribe):
+		return CBOR, ConfidenceHigh
+	case bytes.HasPrefix(data, avroContainerMagic):
+		return Avro, ConfidenceHigh
+	case bytes.HasPrefix(data, avroSingleObjectMagic) && len(data) >= 10:
+		return Avro, ConfidenceHigh
+	}
+	if c := detectBSON(data); c != ConfidenceNone {
+		return BSON, c
+	}
+	return detectText(bytes.TrimPrefix(data, utf8BOM))
+}
+
+// detectBSON recognizes a BSON document: a little-endian length header no
+// larger than data, a terminating zero byte at that length, and a valid
+// type for the first element. A length matching data exactly is certain;
+// a shorter one may start a stream of documents.
+func detectBSON(data []byte) Confidence {
+	if len(data) < 5 {
+		return ConfidenceNone
+	}
+	length := int64(binary.LittleEndian.Uint32(data))
+	if length < 5 || length > int64(len(data)) || data[length-1] != 0 {
+		return ConfidenceNone
+	}
+	if length > 5 {
+		switch typ := data[4]; {
+		case typ >= 0x01 && typ <= 0x13, typ == 0x7f, typ == 0xff:
+		default:
+			return ConfidenceNone
+		}
+	}
+	if length == int64(len(data)) {
+		return ConfidenceHigh
+	}
+	return ConfidenceMedium
+}
+
+// detectText recognizes the text formats, from the first line that is
+// neither blank nor a comment
+func detectText(data []byte) (Type, Confidence) {
+	if !utf8.Valid(data) {
+		return "", ConfidenceNone
+	}
+
+	trimmed := bytes.TrimLeft(data, " \t\r\n")
+	if len(trimmed) > 0 && (trimmed[0] == '{' || trimmed[0] == '[') {
+		if json.Valid(trimmed) {
+			return JSON, ConfidenceHigh
+		}
+		if line := firstLine(trimmed); trimmed[0] == '[' && isTOMLTable(line) {
+			return TOML, ConfidenceMedium
+		}
+		return JSON, ConfidenceMedium
+	}
+
+	for rest := data; len(rest) > 0; {
+		line := firstLine(rest)
+		rest = rest[min(len(rest), len(line)+1):]
+		line = bytes.TrimSpace(line)
+		if len(line) == 0 || line[0] == '#' {
+			continue
+		}
+
+		switch {
+		case bytes.HasPrefix(line, []byte("%YAML")):
+			return YAML, ConfidenceHigh
+		case bytes.Equal(line, []byte("---")), bytes.HasPrefix(line, []byte("--- ")):
+			return YAML, ConfidenceMedium
+		case isTOMLTable(line):
+			return TOML, ConfidenceMedium
+		case isTOMLKeyValue(line):
+			return TOML, ConfidenceMedium
+		case isYAMLKeyValue(line), bytes.HasPrefix(line, []byte("- ")):
+			return YAML, ConfidenceLow
+		}
+		return "", ConfidenceNone
+	}
+	return "", ConfidenceNone
+}
+
+// firstLine returns data up to its first newline
+func firstLine(data []byte) []byte {
+	if i := bytes.IndexByte(data, '\n'); i >= 0 {
+		return data[:i]
+	}
+	return data
+}
+
+// isTOMLTable reports whether line is a TOML table or array of tables
+// header, e.g. "[server]" or "[[servers]]"
+func isTOMLTable(line []byte) bool {
+	line = stripComment(line)
+	if !bytes.HasPrefix(line, []byte("[")) || !bytes.HasSuffix(line, []byte("]")) {
+		return false
+	}
+	name := bytes.Trim(line, "[]")
+	return len(name) > 0 && len(line)-len(name) <= 4 && isKey(name)
+}
+
+// isTOMLKeyValue reports whether line assigns a TOML key, e.g. "port = 80"
+func isTOMLKeyValue(line []byte) bool {
+	key, value, ok := bytes.Cut(line, []byte("="))
+	key = bytes.TrimSpace(key)
+	return ok && len(key) > 0 && isKey(key) && len(bytes.TrimSpace(value)) > 0
+}
+
+// isYAMLKeyValue reports whether line starts a YAML mapping, e.g. "port: 80"
+func isYAMLKeyValue(line []byte) bool {
+	key, _, ok := bytes.Cut(line, []byte(":"))
+	return ok && len(key) > 0 && isKey(bytes.TrimSpace(key))
+}
+
+// isKey reports whether name is a bare or dotted key, allowing quoted parts
+func isKey(name []byte) bool {
+	for _, b := range name {
+		switch {
+		case b >= 'a' && b <= 'z', b >= 'A' && b <= 'Z', b >= '0' && b <= '9':
+		case b == '_', b == '-', b == '.', b == ' ', b == '"', b == '\'':
+		default:
+			return false
+		}
+	}
+	return len(bytes.TrimSpace(name)) > 0
+}
+
+// stripComment removes a trailing "# comment" from line
+func stripComment(line []byte) []byte {
+	if i := bytes.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	return bytes.TrimSpace(line)
+}
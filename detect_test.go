@@ -0,0 +1,42 @@
+package codec
+
+import "testing"
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name       string
+		data       string
+		want       Type
+		confidence Confidence
+	}{
+		{"cbor self-describe", "\xd9\xd9\xf7\xa1\x61a\x01", CBOR, ConfidenceHigh},
+		{"avro container", "Obj\x01\x04\x14avro.codec", Avro, ConfidenceHigh},
+		{"avro single object", "\xc3\x01\x01\x02\x03\x04\x05\x06\x07\x08\x02", Avro, ConfidenceHigh},
+		{"bson", "\x0c\x00\x00\x00\x10a\x00\x01\x00\x00\x00\x00", BSON, ConfidenceHigh},
+		{"bson empty", "\x05\x00\x00\x00\x00", BSON, ConfidenceHigh},
+		{"bson stream", "\x05\x00\x00\x00\x00\x05\x00\x00\x00\x00", BSON, ConfidenceMedium},
+		{"json object", " {\"name\": \"test\"}\n", JSON, ConfidenceHigh},
+		{"json array", "[1, 2, 3]", JSON, ConfidenceHigh},
+		{"json with bom", "\xef\xbb\xbf{}", JSON, ConfidenceHigh},
+		{"json malformed", "{\"name\": ", JSON, ConfidenceMedium},
+		{"yaml directive", "%YAML 1.2\n---\na: 1\n", YAML, ConfidenceHigh},
+		{"yaml document", "# config\n---\na: 1\n", YAML, ConfidenceMedium},
+		{"yaml mapping", "name: test\nvalue: 42\n", YAML, ConfidenceLow},
+		{"yaml sequence", "- a\n- b\n", YAML, ConfidenceLow},
+		{"toml table", "# settings\n\n[server]\nport = 80\n", TOML, ConfidenceMedium},
+		{"toml array of tables", "[[servers]]\nname = \"a\"\n", TOML, ConfidenceMedium},
+		{"toml key", "name = \"test\"\n", TOML, ConfidenceMedium},
+		{"msgpack", "\x82\xa4name\xa4test", "", ConfidenceNone},
+		{"plain text", "hello, world", "", ConfidenceNone},
+		{"empty", "", "", ConfidenceNone},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, confidence := Detect([]byte(tt.data))
+			if got != tt.want || confidence != tt.confidence {
+				t.Errorf("Detect() = %q, %s; want %q, %s", got, confidence, tt.want, tt.confidence)
+			}
+		})
+	}
+}
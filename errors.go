@@ -0,0 +1,160 @@
+package codec
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// ErrorKind classifies why decoding failed. Every ErrorKind is itself an
+// error, so errors.Is(err, codec.ErrTruncated) reports whether err is a
+// DecodeError of that kind.
+type ErrorKind int
+
+const (
+	// ErrSyntax reports input that is not well-formed for the codec
+	ErrSyntax ErrorKind = iota + 1
+
+	// ErrTypeMismatch reports a well-formed value that cannot be stored in
+	// the destination type
+	ErrTypeMismatch
+
+	// ErrTruncated reports input that ends in the middle of a value
+	ErrTruncated
+
+	// ErrLimitExceeded reports input that exceeds a configured or built-in
+	// decoding limit such as nesting depth or collection size
+	ErrLimitExceeded
+
+	// ErrUnknownField reports a field that has no counterpart in the
+	// destination type while unknown fields are disallowed
+	ErrUnknownField
+
+	// ErrDuplicateKey reports a key that appears more than once in the same
+	// map or object while decoding strictly
+	ErrDuplicateKey
+)
+
+// String returns a short description of the kind
+func (k ErrorKind) String() string {
+	switch k {
+	case ErrSyntax:
+		return "syntax error"
+	case ErrTypeMismatch:
+		return "type mismatch"
+	case ErrTruncated:
+		return "truncated input"
+	case ErrLimitExceeded:
+		return "limit exceeded"
+	case ErrUnknownField:
+		return "unknown field"
+	case ErrDuplicateKey:
+		return "duplicate key"
+	default:
+		return fmt.Sprintf("error kind %d", int(k))
+	}
+}
+
+func (k ErrorKind) Error() string {
+	return k.String()
+}
+
+// DecodeError is returned by Unmarshal, Decode and decoder sessions of every
+// codec when the input cannot be decoded. It wraps the error reported by the
+// underlying library, which remains reachable through errors.As and
+// errors.Unwrap.
+//
+// The position fields are filled in as far as the codec can determine them:
+// Offset is -1 when unknown, and Line and Column are 0 for binary formats or
+// when the input is not available (such as a failing stream).
+type DecodeError struct {
+	// Codec is the codec that failed to decode
+	Codec Type
+
+	// Kind classifies the failure
+	Kind ErrorKind
+
+	// Offset is the byte offset in the input at which the error was
+	// detected, or -1 if unknown
+	Offset int64
+
+	// Line and Column give the 1-based position of the error in text formats
+	Line   int
+	Column int
+
+	// Path is the dotted path of the field being decoded, e.g. "user.tags[2]"
+	Path string
+
+	// Err is the error reported by the underlying library
+	Err error
+}
+
+func (e DecodeError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s", e.Codec, e.Kind)
+	if e.Path != "" {
+		fmt.Fprintf(&b, " at %s", e.Path)
+	}
+	switch {
+	case e.Line > 0:
+		fmt.Fprintf(&b, " (line %d, column %d)", e.Line, e.Column)
+	case e.Offset >= 0:
+		fmt.Fprintf(&b, " (offset %d)", e.Offset)
+	}
+	if e.Err != nil {
+		fmt.Fprintf(&b, ": %v", e.Err)
+	}
+	return b.String()
+}
+
+// Unwrap returns the underlying library error
+func (e DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the ErrorKind of e
+func (e DecodeError) Is(target error) bool {
+	kind, ok := target.(ErrorKind)
+	return ok && kind == e.Kind
+}
+
+// Position returns the 1-based line and column of the byte at offset in
+// data. Columns are counted in characters. Offsets past the end of data
+// report the position just after the last character.
+func Position(data []byte, offset int64) (line, column int) {
+	if offset < 0 {
+		return 0, 0
+	}
+	if offset > int64(len(data)) {
+		offset = int64(len(data))
+	}
+	prefix := data[:offset]
+	line = 1 + bytes.Count(prefix, []byte{'\n'})
+	if i := bytes.LastIndexByte(prefix, '\n'); i >= 0 {
+		prefix = prefix[i+1:]
+	}
+	return line, utf8.RuneCount(prefix) + 1
+}
+
+// Offset returns the byte offset in data of the 1-based line and column, the
+// inverse of Position. It returns -1 if data has fewer lines.
+func Offset(data []byte, line, column int) int64 {
+	if line < 1 {
+		return -1
+	}
+	start := 0
+	for l := 1; l < line; l++ {
+		i := bytes.IndexByte(data[start:], '\n')
+		if i < 0 {
+			return -1
+		}
+		start += i + 1
+	}
+	offset := start
+	for c := 1; c < column && offset < len(data) && data[offset] != '\n'; c++ {
+		_, size := utf8.DecodeRune(data[offset:])
+		offset += size
+	}
+	return int64(offset)
+}
@@ -0,0 +1,101 @@
+package codec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+func TestDecodeError_Error(t *testing.T) {
+	tests := []struct {
+		err      DecodeError
+		expected string
+	}{
+		{
+			DecodeError{Codec: JSON, Kind: ErrSyntax, Offset: 12, Line: 2, Column: 5, Err: errors.New("bad token")},
+			"json: syntax error (line 2, column 5): bad token",
+		},
+		{
+			DecodeError{Codec: CBOR, Kind: ErrTypeMismatch, Offset: 7, Path: "user.age"},
+			"cbor: type mismatch at user.age (offset 7)",
+		},
+		{
+			DecodeError{Codec: BSON, Kind: ErrTruncated, Offset: -1, Err: io.ErrUnexpectedEOF},
+			"bson: truncated input: unexpected EOF",
+		},
+	}
+
+	for _, tt := range tests {
+		if got := tt.err.Error(); got != tt.expected {
+			t.Errorf("expected %q, got %q", tt.expected, got)
+		}
+	}
+}
+
+func TestDecodeError_Is(t *testing.T) {
+	var err error = DecodeError{Codec: YAML, Kind: ErrUnknownField, Offset: -1, Err: io.ErrUnexpectedEOF}
+	err = fmt.Errorf("loading config: %w", err)
+
+	if !errors.Is(err, ErrUnknownField) {
+		t.Error("expected errors.Is to match the kind")
+	}
+	if errors.Is(err, ErrSyntax) {
+		t.Error("expected errors.Is not to match another kind")
+	}
+	if !errors.Is(err, io.ErrUnexpectedEOF) {
+		t.Error("expected errors.Is to match the wrapped error")
+	}
+
+	var de DecodeError
+	if !errors.As(err, &de) || de.Codec != YAML {
+		t.Errorf("expected errors.As to find the DecodeError, got %v", de)
+	}
+}
+
+func TestErrorKind_String(t *testing.T) {
+	kinds := map[ErrorKind]string{
+		ErrSyntax:        "syntax error",
+		ErrTypeMismatch:  "type mismatch",
+		ErrTruncated:     "truncated input",
+		ErrLimitExceeded: "limit exceeded",
+		ErrUnknownField:  "unknown field",
+		ErrorKind(0):     "error kind 0",
+	}
+	for kind, expected := range kinds {
+		if kind.String() != expected || kind.Error() != expected {
+			t.Errorf("expected %q, got %q", expected, kind.String())
+		}
+	}
+}
+
+func TestPosition(t *testing.T) {
+	data := []byte("a: 1\nbé: 2\n")
+
+	tests := []struct {
+		offset       int64
+		line, column int
+	}{
+		{0, 1, 1},
+		{3, 1, 4},
+		{5, 2, 1},
+		{8, 2, 3},
+		{100, 3, 1},
+		{-1, 0, 0},
+	}
+	for _, tt := range tests {
+		line, column := Position(data, tt.offset)
+		if line != tt.line || column != tt.column {
+			t.Errorf("Position(%d): expected %d:%d, got %d:%d", tt.offset, tt.line, tt.column, line, column)
+		}
+		if tt.offset >= 0 && tt.offset <= int64(len(data)) {
+			if offset := Offset(data, line, column); offset != tt.offset {
+				t.Errorf("Offset(%d, %d): expected %d, got %d", line, column, tt.offset, offset)
+			}
+		}
+	}
+
+	if offset := Offset(data, 5, 1); offset != -1 {
+		t.Errorf("expected -1 for a missing line, got %d", offset)
+	}
+}
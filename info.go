@@ -0,0 +1,168 @@
+//go:build !codec_none
+
+package codec
+
+import (
+	"slices"
+	"sort"
+)
+
+// CodecInfo describes a codec type and its capabilities
+type CodecInfo struct {
+	// Type is the codec type, e.g. "msgpack"
+	Type Type
+
+	// Name is the human-readable name of the format, e.g. "MessagePack"
+	Name string
+
+	// MediaTypes lists the MIME types of the format, the canonical one first
+	MediaTypes []string
+
+	// Extensions lists the file extensions of the format including the
+	// leading dot, the canonical one first
+	Extensions []string
+
+	// Binary reports whether the encoding is binary rather than text
+	Binary bool
+
+	// SelfDescribing reports whether encoded data can be decoded without a
+	// schema. Formats that are not self-describing need a schema, generated
+	// code or an inferred schema shared by both sides.
+	SelfDescribing bool
+
+	// Streaming reports whether the StreamCodec sessions of the codec read
+	// and write many values over a single stream. TOML has no document
+	// separator, so its sessions carry a single document.
+	Streaming bool
+
+	// OrderedMaps reports whether maps can be decoded into a type that keeps
+	// the order of their entries, such as a Value, bson.D or yaml.Node
+	OrderedMaps bool
+
+	// Optimized reports whether the codec package provides an
+	// OptimizedCodec
+	Optimized bool
+}
+
+// clone returns a copy of i that shares no slices with it
+func (i CodecInfo) clone() CodecInfo {
+	i.MediaTypes = slices.Clone(i.MediaTypes)
+	i.Extensions = slices.Clone(i.Extensions)
+	return i
+}
+
+// builtinInfo describes the codec types implemented by this module, whether
+// or not they are compiled into the build
+var builtinInfo = map[Type]CodecInfo{
+	JSON: {
+		Type:           JSON,
+		Name:           "JSON",
+		MediaTypes:     []string{"application/json", "text/json"},
+		Extensions:     []string{".json"},
+		SelfDescribing: true,
+		Streaming:      true,
+		OrderedMaps:    true,
+		Optimized:      true,
+	},
+	YAML: {
+		Type:           YAML,
+		Name:           "YAML",
+		MediaTypes:     []string{"application/yaml", "application/x-yaml", "text/yaml", "text/x-yaml"},
+		Extensions:     []string{".yaml", ".yml"},
+		SelfDescribing: true,
+		Streaming:      true,
+		OrderedMaps:    true,
+		Optimized:      true,
+	},
+	TOML: {
+		Type:           TOML,
+		Name:           "TOML",
+		MediaTypes:     []string{"application/toml"},
+		Extensions:     []string{".toml"},
+		SelfDescribing: true,
+		OrderedMaps:    true,
+		Optimized:      true,
+	},
+	MsgPack: {
+		Type:           MsgPack,
+		Name:           "MessagePack",
+		MediaTypes:     []string{"application/msgpack", "application/x-msgpack", "application/vnd.msgpack"},
+		Extensions:     []string{".msgpack", ".mpk"},
+		Binary:         true,
+		SelfDescribing: true,
+		Streaming:      true,
+		OrderedMaps:    true,
+		Optimized:      true,
+	},
+	ProtoBuf: {
+		Type:       ProtoBuf,
+		Name:       "Protocol Buffers",
+		MediaTypes: []string{"application/x-protobuf", "application/protobuf", "application/vnd.google.protobuf", "application/x-google-protobuf"},
+		Extensions: []string{".pb", ".binpb"},
+		Binary:     true,
+		Streaming:  true,
+		Optimized:  true,
+	},
+	BSON: {
+		Type:           BSON,
+		Name:           "BSON",
+		MediaTypes:     []string{"application/bson"},
+		Extensions:     []string{".bson"},
+		Binary:         true,
+		SelfDescribing: true,
+		Streaming:      true,
+		OrderedMaps:    true,
+		Optimized:      true,
+	},
+	CBOR: {
+		Type:           CBOR,
+		Name:           "CBOR",
+		MediaTypes:     []string{"application/cbor"},
+		Extensions:     []string{".cbor"},
+		Binary:         true,
+		SelfDescribing: true,
+		Streaming:      true,
+		OrderedMaps:    true,
+		Optimized:      true,
+	},
+	Avro: {
+		Type:       Avro,
+		Name:       "Avro",
+		MediaTypes: []string{"application/vnd.apache.avro+binary", "avro/binary", "application/avro", "application/x-avro-binary"},
+		Extensions: []string{".avro"},
+		Binary:     true,
+		Streaming:  true,
+		Optimized:  true,
+	},
+}
+
+// Info describes the codec type t. It reports false if t is neither one of
+// the codec types of this module nor registered with Register. The codec
+// types of this module are described even when they are not compiled in;
+// use IsSupported to tell.
+func Info(t Type) (CodecInfo, bool) {
+	codecMu.RLock()
+	r, ok := registry[t]
+	codecMu.RUnlock()
+	if ok {
+		return r.info.clone(), true
+	}
+	info, ok := builtinInfo[t]
+	return info.clone(), ok
+}
+
+// SupportedCodecInfo describes every codec that is compiled into the build
+// or registered with Register, sorted by type like SupportedCodecs
+func SupportedCodecInfo() []CodecInfo {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+
+	result := make([]CodecInfo, 0, len(registry))
+	for _, r := range registry {
+		result = append(result, r.info.clone())
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return string(result[i].Type) < string(result[j].Type)
+	})
+	return result
+}
@@ -0,0 +1,81 @@
+//go:build !codec_none
+
+package codec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestInfo_Builtin(t *testing.T) {
+	for _, typ := range []Type{JSON, YAML, TOML, MsgPack, ProtoBuf, BSON, CBOR, Avro} {
+		info, ok := Info(typ)
+		if !ok {
+			t.Errorf("Expected info for %q", typ)
+			continue
+		}
+		if info.Type != typ || info.Name == "" || len(info.MediaTypes) == 0 || len(info.Extensions) == 0 {
+			t.Errorf("Incomplete info for %q: %+v", typ, info)
+		}
+		if info.Streaming != (typ != TOML) {
+			t.Errorf("Unexpected Streaming %v for %q", info.Streaming, typ)
+		}
+		if !info.Optimized {
+			t.Errorf("Expected %q to provide an OptimizedCodec", typ)
+		}
+		if info.OrderedMaps != (typ != ProtoBuf && typ != Avro) {
+			t.Errorf("Unexpected OrderedMaps %v for %q", info.OrderedMaps, typ)
+		}
+	}
+
+	msgpack, _ := Info(MsgPack)
+	if !msgpack.Binary || !msgpack.SelfDescribing || msgpack.MediaTypes[0] != "application/msgpack" {
+		t.Errorf("Unexpected MessagePack info: %+v", msgpack)
+	}
+	avro, _ := Info(Avro)
+	if avro.SelfDescribing {
+		t.Error("Expected Avro to require a schema")
+	}
+}
+
+func TestInfo_Unknown(t *testing.T) {
+	if _, ok := Info("unknown"); ok {
+		t.Error("Expected no info for unknown codec")
+	}
+}
+
+func TestInfo_Registered(t *testing.T) {
+	mediaTypes := []string{"application/x-info-test"}
+	Register(CodecInfo{Type: "test_info", Name: "Info Test", MediaTypes: mediaTypes, Binary: true},
+		ProviderFunc(func(typ reflect.Type, opts ...Option) (Codec[any], error) {
+			return nil, nil
+		}))
+	mediaTypes[0] = "changed"
+
+	info, ok := Info("test_info")
+	if !ok || info.Name != "Info Test" || !info.Binary || info.MediaTypes[0] != "application/x-info-test" {
+		t.Errorf("Unexpected info: %+v", info)
+	}
+	info.MediaTypes[0] = "changed"
+	if info, _ := Info("test_info"); info.MediaTypes[0] != "application/x-info-test" {
+		t.Error("Info shares its slices with the registry")
+	}
+}
+
+func TestSupportedCodecInfo(t *testing.T) {
+	RegisterCodec(JSON)
+
+	types := SupportedCodecs()
+	infos := SupportedCodecInfo()
+	if len(infos) != len(types) {
+		t.Fatalf("Expected %d entries, got %d", len(types), len(infos))
+	}
+	for i, info := range infos {
+		if info.Type != types[i] {
+			t.Errorf("Entry %d: expected %q, got %q", i, types[i], info.Type)
+		}
+		if info.Type == JSON && !info.Optimized {
+			t.Errorf("Expected builtin info for JSON, got %+v", info)
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Package exact detects numbers that changed while being decoded into a Go
+// value, for codecs whose libraries convert numbers without checking that
+// the result is exact.
+package exact
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"slices"
+	"strconv"
+)
+
+// Compare walks input, a generic decoding of some encoded data, alongside
+// decoded, a generic decoding of the Go value that data was decoded into
+// after encoding it again. Both use the value model of the codec libraries:
+// maps, slices and scalars. It returns the path of the first number in
+// input that did not survive the conversion into the Go value, and the
+// number. Values present in only one of the two are ignored.
+func Compare(input, decoded any) (path string, value any, ok bool) {
+	return compare(input, decoded, "")
+}
+
+func compare(input, decoded any, path string) (string, any, bool) {
+	switch in := input.(type) {
+	case map[string]any:
+		for _, key := range sortedKeys(in) {
+			if out, found := lookup(decoded, key); found {
+				if p, v, lossy := compare(in[key], out, joinKey(path, key)); lossy {
+					return p, v, true
+				}
+			}
+		}
+	case map[any]any:
+		keys := make([]string, 0, len(in))
+		byName := make(map[string]any, len(in))
+		for key, v := range in {
+			name := fmt.Sprint(key)
+			keys = append(keys, name)
+			byName[name] = v
+		}
+		slices.Sort(keys)
+		for _, key := range keys {
+			if out, found := lookup(decoded, key); found {
+				if p, v, lossy := compare(byName[key], out, joinKey(path, key)); lossy {
+					return p, v, true
+				}
+			}
+		}
+	case []any:
+		out, isSlice := decoded.([]any)
+		for i := 0; isSlice && i < len(in) && i < len(out); i++ {
+			if p, v, lossy := compare(in[i], out[i], path+"["+strconv.Itoa(i)+"]"); lossy {
+				return p, v, true
+			}
+		}
+	default:
+		if !Equal(input, decoded) {
+			return path, input, true
+		}
+	}
+	return "", nil, false
+}
+
+// Equal reports whether the number decoded holds the value of the number
+// input. A float input may be rounded to float32 precision, but not
+// overflow to an infinity. Values that are not both numbers are equal.
+func Equal(input, decoded any) bool {
+	in, inFloat, ok := toBig(input)
+	if !ok {
+		return true
+	}
+	out, outFloat, ok := toBig(decoded)
+	if !ok {
+		return true
+	}
+	if in.Cmp(out) == 0 {
+		return true
+	}
+	if inFloat && outFloat && !out.IsInf() {
+		f, _ := in.Float64()
+		return float64(float32(f)) == toFloat64(decoded)
+	}
+	return false
+}
+
+// toBig converts v to a big.Float, reporting whether v is a floating-point
+// number and whether it is a number at all
+func toBig(v any) (*big.Float, bool, bool) {
+	switch n := v.(type) {
+	case int:
+		return new(big.Float).SetInt64(int64(n)), false, true
+	case int8:
+		return new(big.Float).SetInt64(int64(n)), false, true
+	case int16:
+		return new(big.Float).SetInt64(int64(n)), false, true
+	case int32:
+		return new(big.Float).SetInt64(int64(n)), false, true
+	case int64:
+		return new(big.Float).SetInt64(n), false, true
+	case uint:
+		return new(big.Float).SetUint64(uint64(n)), false, true
+	case uint8:
+		return new(big.Float).SetUint64(uint64(n)), false, true
+	case uint16:
+		return new(big.Float).SetUint64(uint64(n)), false, true
+	case uint32:
+		return new(big.Float).SetUint64(uint64(n)), false, true
+	case uint64:
+		return new(big.Float).SetUint64(n), false, true
+	case float32:
+		return bigFloat(float64(n)), true, !math.IsNaN(float64(n))
+	case float64:
+		return bigFloat(n), true, !math.IsNaN(n)
+	}
+	return nil, false, false
+}
+
+func bigFloat(f float64) *big.Float {
+	if math.IsNaN(f) {
+		return nil
+	}
+	return new(big.Float).SetFloat64(f)
+}
+
+func toFloat64(v any) float64 {
+	if f, ok := v.(float32); ok {
+		return float64(f)
+	}
+	f, _ := v.(float64)
+	return f
+}
+
+// lookup returns the value stored under key in m, a map of either kind
+func lookup(m any, key string) (any, bool) {
+	switch m := m.(type) {
+	case map[string]any:
+		v, ok := m[key]
+		return v, ok
+	case map[any]any:
+		for k, v := range m {
+			if fmt.Sprint(k) == key {
+				return v, true
+			}
+		}
+	}
+	return nil, false
+}
+
+func sortedKeys(m map[string]any) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	return keys
+}
+
+func joinKey(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,53 @@
+package exact
+
+import (
+	"math"
+	"testing"
+)
+
+func TestEqual(t *testing.T) {
+	tests := []struct {
+		input, decoded any
+		expected       bool
+	}{
+		{int64(2), int8(2), true},
+		{2.0, int64(2), true},
+		{1.5, int64(1), false},
+		{int64(300), int8(44), false},
+		{int64(-1), uint64(math.MaxUint64), false},
+		{0.1, float32(0.1), true},
+		{0.1, float64(float32(0.1)), true},
+		{1e300, float32(math.Inf(1)), false},
+		{int64(1<<53 + 1), float64(1 << 53), false},
+		{"text", int64(1), true},
+	}
+
+	for _, tt := range tests {
+		if got := Equal(tt.input, tt.decoded); got != tt.expected {
+			t.Errorf("Equal(%v, %v): expected %v, got %v", tt.input, tt.decoded, tt.expected, got)
+		}
+	}
+}
+
+func TestCompare(t *testing.T) {
+	input := map[string]any{
+		"name":  "John",
+		"ports": []any{int64(80), int64(70000)},
+		"inner": map[any]any{"ratio": 0.5},
+	}
+	decoded := map[string]any{
+		"name":  "John",
+		"ports": []any{int64(80), int64(4464)},
+		"inner": map[string]any{"ratio": 0.5},
+	}
+
+	path, value, lossy := Compare(input, decoded)
+	if !lossy || path != "ports[1]" || value != int64(70000) {
+		t.Errorf("unexpected result %q, %v, %v", path, value, lossy)
+	}
+
+	decoded["ports"] = []any{int64(80), int64(70000)}
+	if path, _, lossy := Compare(input, decoded); lossy {
+		t.Errorf("expected no loss, got %q", path)
+	}
+}
@@ -0,0 +1,160 @@
+package codec
+
+import (
+	"fmt"
+	"io"
+)
+
+// Limits bounds the resources a codec may spend decoding a single value, to
+// protect against hostile input. A zero field imposes no limit.
+type Limits struct {
+	// MaxBytes is the maximum size of an encoded value. Stream decoders
+	// apply it to each value read, and read no further than the limit.
+	MaxBytes int64
+
+	// MaxDepth is the maximum nesting depth of arrays, maps, objects and
+	// messages. A scalar at the top level has depth 0.
+	MaxDepth int
+
+	// MaxElements is the maximum number of elements in any single array,
+	// or of entries in any single map or object
+	MaxElements int
+
+	// MaxStringLength is the maximum length, in encoded bytes, of any
+	// string or byte string
+	MaxStringLength int
+
+	// MaxAliasExpansion is the maximum number of nodes that YAML aliases
+	// may expand to. Formats without aliases ignore it.
+	MaxAliasExpansion int
+}
+
+// Structural reports whether any limit requires the input to be scanned
+// before it is decoded, i.e. any limit other than MaxBytes
+func (l Limits) Structural() bool {
+	return l.MaxDepth > 0 || l.MaxElements > 0 || l.MaxStringLength > 0 || l.MaxAliasExpansion > 0
+}
+
+// CheckBytes returns a LimitError if n exceeds MaxBytes
+func (l Limits) CheckBytes(n int64) error {
+	if l.MaxBytes > 0 && n > l.MaxBytes {
+		return LimitError{Limit: "MaxBytes", Max: l.MaxBytes}
+	}
+	return nil
+}
+
+// CheckDepth returns a LimitError if depth exceeds MaxDepth
+func (l Limits) CheckDepth(depth int) error {
+	if l.MaxDepth > 0 && depth > l.MaxDepth {
+		return LimitError{Limit: "MaxDepth", Max: int64(l.MaxDepth)}
+	}
+	return nil
+}
+
+// CheckElements returns a LimitError if n exceeds MaxElements
+func (l Limits) CheckElements(n int64) error {
+	if l.MaxElements > 0 && n > int64(l.MaxElements) {
+		return LimitError{Limit: "MaxElements", Max: int64(l.MaxElements)}
+	}
+	return nil
+}
+
+// CheckStringLength returns a LimitError if n exceeds MaxStringLength
+func (l Limits) CheckStringLength(n int64) error {
+	if l.MaxStringLength > 0 && n > int64(l.MaxStringLength) {
+		return LimitError{Limit: "MaxStringLength", Max: int64(l.MaxStringLength)}
+	}
+	return nil
+}
+
+// CheckAliasExpansion returns a LimitError if n exceeds MaxAliasExpansion
+func (l Limits) CheckAliasExpansion(n int64) error {
+	if l.MaxAliasExpansion > 0 && n > int64(l.MaxAliasExpansion) {
+		return LimitError{Limit: "MaxAliasExpansion", Max: int64(l.MaxAliasExpansion)}
+	}
+	return nil
+}
+
+// LimitError describes a decoding limit that was exceeded. Codecs report it
+// wrapped in a DecodeError of kind ErrLimitExceeded.
+type LimitError struct {
+	// Limit is the name of the Limits field, e.g. "MaxDepth"
+	Limit string
+
+	// Max is the configured value of the limit
+	Max int64
+}
+
+func (e LimitError) Error() string {
+	return fmt.Sprintf("%s of %d exceeded", e.Limit, e.Max)
+}
+
+// WithLimits bounds the input a codec accepts while decoding. Input that
+// exceeds a limit is rejected with a DecodeError of kind ErrLimitExceeded
+// wrapping a LimitError. The option applies to every codec.
+func WithLimits(limits Limits) Option {
+	return newSharedOption("codec.WithLimits", func(cfg *Config) {
+		cfg.Limits = limits
+	})
+}
+
+// LimitedReader reads from R until Limits.MaxBytes bytes have been read,
+// after which it fails with a DecodeError of kind ErrLimitExceeded for
+// codec type Codec. Decoders reading many values call Reset before each.
+type LimitedReader struct {
+	R     io.Reader
+	Codec Type
+	Max   int64
+
+	read int64
+	err  error
+}
+
+// NewLimitedReader returns a LimitedReader over r, or r itself if max is
+// not positive
+func NewLimitedReader(r io.Reader, t Type, max int64) io.Reader {
+	if max <= 0 {
+		return r
+	}
+	return &LimitedReader{R: r, Codec: t, Max: max}
+}
+
+func (l *LimitedReader) Read(p []byte) (int, error) {
+	if l.err != nil {
+		return 0, l.err
+	}
+	if l.read >= l.Max {
+		// Distinguish input that ends exactly at the limit from input
+		// that continues beyond it
+		var probe [1]byte
+		n, err := l.R.Read(probe[:])
+		if n == 0 {
+			return 0, err
+		}
+		l.err = DecodeError{
+			Codec:  l.Codec,
+			Kind:   ErrLimitExceeded,
+			Offset: l.Max,
+			Err:    LimitError{Limit: "MaxBytes", Max: l.Max},
+		}
+		return 0, l.err
+	}
+	if remaining := l.Max - l.read; int64(len(p)) > remaining {
+		p = p[:remaining]
+	}
+	n, err := l.R.Read(p)
+	l.read += int64(n)
+	return n, err
+}
+
+// Reset starts counting toward the limit again, for the next value
+func (l *LimitedReader) Reset() {
+	l.read = 0
+}
+
+// Err returns the error reported once the limit was exceeded, or nil. It
+// lets codecs whose libraries do not propagate read errors intact recover
+// the limit error.
+func (l *LimitedReader) Err() error {
+	return l.err
+}
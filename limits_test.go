@@ -0,0 +1,110 @@
+package codec
+
+import (
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+type limitedConfig struct {
+	Config
+}
+
+func TestLimits_Check(t *testing.T) {
+	l := Limits{MaxBytes: 10, MaxDepth: 2, MaxElements: 3, MaxStringLength: 4, MaxAliasExpansion: 5}
+	tests := []struct {
+		err   error
+		limit string
+	}{
+		{l.CheckBytes(11), "MaxBytes"},
+		{l.CheckDepth(3), "MaxDepth"},
+		{l.CheckElements(4), "MaxElements"},
+		{l.CheckStringLength(5), "MaxStringLength"},
+		{l.CheckAliasExpansion(6), "MaxAliasExpansion"},
+	}
+	for _, tt := range tests {
+		var le LimitError
+		if !errors.As(tt.err, &le) || le.Limit != tt.limit {
+			t.Errorf("expected %s exceeded, got %v", tt.limit, tt.err)
+		}
+	}
+
+	if err := l.CheckBytes(10); err != nil {
+		t.Errorf("expected no error at the limit, got %v", err)
+	}
+	if err := (Limits{}).CheckDepth(1 << 20); err != nil {
+		t.Errorf("expected zero limit to be unbounded, got %v", err)
+	}
+	if got := (LimitError{Limit: "MaxDepth", Max: 2}).Error(); got != "MaxDepth of 2 exceeded" {
+		t.Errorf("unexpected message %q", got)
+	}
+}
+
+func TestLimits_Structural(t *testing.T) {
+	if (Limits{MaxBytes: 10}).Structural() {
+		t.Error("expected MaxBytes alone not to be structural")
+	}
+	if !(Limits{MaxElements: 10}).Structural() {
+		t.Error("expected MaxElements to be structural")
+	}
+}
+
+func TestWithLimits(t *testing.T) {
+	limits := Limits{MaxDepth: 8}
+
+	var cfg limitedConfig
+	if err := ApplyOptions(JSON, &cfg, []Option{WithLimits(limits)}); err != nil {
+		t.Fatalf("ApplyOptions failed: %v", err)
+	}
+	if cfg.Limits != limits {
+		t.Errorf("expected %+v, got %+v", limits, cfg.Limits)
+	}
+
+	var other testConfig
+	err := ApplyOptions(JSON, &other, []Option{WithLimits(limits)})
+	var notSupported ErrOptionNotSupported
+	if !errors.As(err, &notSupported) || notSupported.Option != "codec.WithLimits" {
+		t.Errorf("expected ErrOptionNotSupported, got %v", err)
+	}
+}
+
+func TestLimitedReader(t *testing.T) {
+	r := NewLimitedReader(strings.NewReader("0123456789"), JSON, 4)
+	buf := make([]byte, 8)
+	if n, err := r.Read(buf); n != 4 || err != nil {
+		t.Fatalf("expected 4 bytes, got %d, %v", n, err)
+	}
+
+	_, err := r.Read(buf)
+	var de DecodeError
+	if !errors.As(err, &de) || de.Kind != ErrLimitExceeded || de.Offset != 4 {
+		t.Fatalf("expected limit exceeded at offset 4, got %v", err)
+	}
+	if _, again := r.Read(buf); again != err || r.(*LimitedReader).Err() != err {
+		t.Errorf("expected the error to be sticky, got %v", again)
+	}
+}
+
+func TestLimitedReader_ExactLimit(t *testing.T) {
+	r := NewLimitedReader(strings.NewReader("0123"), JSON, 4)
+	data, err := io.ReadAll(r)
+	if err != nil || string(data) != "0123" {
+		t.Fatalf("expected input ending at the limit to be read, got %q, %v", data, err)
+	}
+
+	lr := NewLimitedReader(strings.NewReader("01234567"), JSON, 4).(*LimitedReader)
+	for i := 0; i < 2; i++ {
+		if n, err := io.ReadFull(lr, make([]byte, 4)); n != 4 || err != nil {
+			t.Fatalf("read %d: expected 4 bytes, got %d, %v", i, n, err)
+		}
+		lr.Reset()
+	}
+}
+
+func TestNewLimitedReader_Unlimited(t *testing.T) {
+	src := strings.NewReader("data")
+	if r := NewLimitedReader(src, JSON, 0); r != io.Reader(src) {
+		t.Error("expected the reader to be returned unchanged")
+	}
+}
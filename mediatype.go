@@ -0,0 +1,208 @@
+//go:build !codec_none
+
+package codec
+
+import (
+	"fmt"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// suffixes maps structured syntax suffixes (RFC 6838) to the codec whose
+// format they name, so that e.g. "application/problem+json" is read as JSON
+var suffixes = map[string]Type{
+	"+json": JSON,
+	"+yaml": YAML,
+	"+cbor": CBOR,
+}
+
+// ErrUnknownMediaType is returned by ParseMediaType for a media type that
+// no codec handles
+type ErrUnknownMediaType struct {
+	MediaType string
+}
+
+func (e ErrUnknownMediaType) Error() string {
+	return fmt.Sprintf("no codec for media type %q", e.MediaType)
+}
+
+// ParseMediaType returns the codec type for v, a media type as found in a
+// Content-Type header, e.g. "application/x-msgpack" or
+// "application/json; charset=utf-8". Parameters and case are ignored, and
+// types with a +json, +yaml or +cbor suffix map to that format. Every codec
+// type of this module is recognized, whether or not it is compiled in, as
+// are the media types of codecs registered with Register.
+func ParseMediaType(v string) (Type, error) {
+	mediaType, _, err := mime.ParseMediaType(v)
+	if err != nil {
+		return "", err
+	}
+	if t, ok := lookupInfo(func(info CodecInfo) bool {
+		return containsFold(info.MediaTypes, mediaType)
+	}); ok {
+		return t, nil
+	}
+	if i := strings.LastIndexByte(mediaType, '+'); i >= 0 {
+		if t, ok := suffixes[mediaType[i:]]; ok {
+			return t, nil
+		}
+	}
+	return "", ErrUnknownMediaType{MediaType: mediaType}
+}
+
+// FromExtension returns the codec type for a file extension such as ".yml".
+// ext may omit the leading dot, or be a file name or path, in which case its
+// extension is used. Case is ignored.
+func FromExtension(ext string) (Type, bool) {
+	if i := strings.LastIndexByte(ext, '.'); i > 0 {
+		ext = ext[i:]
+	}
+	if !strings.HasPrefix(ext, ".") {
+		ext = "." + ext
+	}
+	return lookupInfo(func(info CodecInfo) bool {
+		return containsFold(info.Extensions, ext)
+	})
+}
+
+// Negotiate chooses the codec type to respond with from supported, in
+// order of preference, given the Accept header of a request. It honors
+// q-values and prefers the most specific media range that matches each
+// codec: "application/json" over "application/*" over "*/*". Ties are
+// broken by the order of supported. An empty header accepts any type. It
+// reports false if no supported type is acceptable.
+func Negotiate(accept string, supported []Type) (Type, bool) {
+	if strings.TrimSpace(accept) == "" {
+		if len(supported) == 0 {
+			return "", false
+		}
+		return supported[0], true
+	}
+
+	ranges := parseAccept(accept)
+	best, bestQ := Type(""), 0.0
+	for _, t := range supported {
+		info, ok := Info(t)
+		if !ok {
+			continue
+		}
+		if q := acceptQuality(ranges, info.MediaTypes); q > bestQ {
+			best, bestQ = t, q
+		}
+	}
+	return best, bestQ > 0
+}
+
+// mediaRange is one entry of an Accept header
+type mediaRange struct {
+	typ, subtype string
+	q            float64
+}
+
+// specificity ranks r: 2 for a full media type, 1 for "type/*" and 0 for
+// "*/*"
+func (r mediaRange) specificity() int {
+	switch {
+	case r.typ == "*":
+		return 0
+	case r.subtype == "*":
+		return 1
+	}
+	return 2
+}
+
+// matches reports whether r includes mediaType
+func (r mediaRange) matches(mediaType string) bool {
+	typ, subtype, _ := strings.Cut(mediaType, "/")
+	return (r.typ == "*" || strings.EqualFold(r.typ, typ)) &&
+		(r.subtype == "*" || strings.EqualFold(r.subtype, subtype))
+}
+
+// parseAccept parses the media ranges of an Accept header, skipping
+// malformed entries
+func parseAccept(accept string) []mediaRange {
+	var ranges []mediaRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		typ, subtype, ok := strings.Cut(mediaType, "/")
+		if !ok {
+			continue
+		}
+		r := mediaRange{typ: typ, subtype: subtype, q: 1}
+		if v, ok := params["q"]; ok {
+			q, err := strconv.ParseFloat(v, 64)
+			if err != nil || q < 0 || q > 1 {
+				continue
+			}
+			r.q = q
+		}
+		ranges = append(ranges, r)
+	}
+	return ranges
+}
+
+// acceptQuality returns the q-value given by ranges to the best of
+// mediaTypes. For each media type, the most specific matching range
+// applies.
+func acceptQuality(ranges []mediaRange, mediaTypes []string) float64 {
+	best := 0.0
+	for _, mediaType := range mediaTypes {
+		specificity, q := -1, 0.0
+		for _, r := range ranges {
+			if !r.matches(mediaType) {
+				continue
+			}
+			if s := r.specificity(); s > specificity || (s == specificity && r.q > q) {
+				specificity, q = s, r.q
+			}
+		}
+		if q > best {
+			best = q
+		}
+	}
+	return best
+}
+
+// lookupInfo returns the first codec type whose info satisfies match,
+// trying this module's codec types before registered ones, each in order
+// of type
+func lookupInfo(match func(CodecInfo) bool) (Type, bool) {
+	for _, t := range sortedTypes(builtinInfo) {
+		if match(builtinInfo[t]) {
+			return t, true
+		}
+	}
+	for _, info := range SupportedCodecInfo() {
+		if match(info) {
+			return info.Type, true
+		}
+	}
+	return "", false
+}
+
+// sortedTypes returns the keys of m in order
+func sortedTypes(m map[Type]CodecInfo) []Type {
+	types := make([]Type, 0, len(m))
+	for t := range m {
+		types = append(types, t)
+	}
+	sort.Slice(types, func(i, j int) bool {
+		return types[i] < types[j]
+	})
+	return types
+}
+
+// containsFold reports whether list contains s, ignoring case
+func containsFold(list []string, s string) bool {
+	for _, v := range list {
+		if strings.EqualFold(v, s) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,115 @@
+//go:build !codec_none
+
+package codec
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestParseMediaType(t *testing.T) {
+	tests := []struct {
+		mediaType string
+		want      Type
+	}{
+		{"application/json", JSON},
+		{"application/json; charset=utf-8", JSON},
+		{"Application/JSON", JSON},
+		{"application/problem+json", JSON},
+		{"application/yaml", YAML},
+		{"text/x-yaml", YAML},
+		{"application/toml", TOML},
+		{"application/msgpack", MsgPack},
+		{"application/x-msgpack", MsgPack},
+		{"application/vnd.msgpack", MsgPack},
+		{"application/x-protobuf", ProtoBuf},
+		{"application/protobuf", ProtoBuf},
+		{"application/bson", BSON},
+		{"application/cbor", CBOR},
+		{"application/cose+cbor", CBOR},
+		{"application/vnd.apache.avro+binary", Avro},
+		{"avro/binary", Avro},
+	}
+
+	for _, tt := range tests {
+		got, err := ParseMediaType(tt.mediaType)
+		if err != nil || got != tt.want {
+			t.Errorf("ParseMediaType(%q) = %q, %v; want %q", tt.mediaType, got, err, tt.want)
+		}
+	}
+}
+
+func TestParseMediaType_Unknown(t *testing.T) {
+	_, err := ParseMediaType("text/html; charset=utf-8")
+	var unknown ErrUnknownMediaType
+	if !errors.As(err, &unknown) || unknown.MediaType != "text/html" {
+		t.Errorf("Expected ErrUnknownMediaType, got %v", err)
+	}
+
+	if _, err := ParseMediaType("application/json; charset"); err == nil {
+		t.Error("Expected error for malformed media type")
+	}
+}
+
+func TestFromExtension(t *testing.T) {
+	tests := []struct {
+		ext  string
+		want Type
+	}{
+		{".json", JSON},
+		{".yml", YAML},
+		{"yaml", YAML},
+		{".TOML", TOML},
+		{".msgpack", MsgPack},
+		{".pb", ProtoBuf},
+		{".bson", BSON},
+		{".cbor", CBOR},
+		{".avro", Avro},
+		{"config.yml", YAML},
+		{"/etc/app/settings.d/app.toml", TOML},
+	}
+
+	for _, tt := range tests {
+		got, ok := FromExtension(tt.ext)
+		if !ok || got != tt.want {
+			t.Errorf("FromExtension(%q) = %q, %t; want %q", tt.ext, got, ok, tt.want)
+		}
+	}
+
+	if _, ok := FromExtension(".txt"); ok {
+		t.Error("Expected no codec for .txt")
+	}
+}
+
+func TestNegotiate(t *testing.T) {
+	all := []Type{JSON, MsgPack, CBOR}
+
+	tests := []struct {
+		name      string
+		accept    string
+		supported []Type
+		want      Type
+		ok        bool
+	}{
+		{"empty header", "", all, JSON, true},
+		{"exact", "application/cbor", all, CBOR, true},
+		{"alias", "application/x-msgpack", all, MsgPack, true},
+		{"q-values", "application/json;q=0.5, application/msgpack;q=0.9", all, MsgPack, true},
+		{"server order breaks ties", "application/cbor, application/msgpack", all, MsgPack, true},
+		{"wildcard", "*/*", all, JSON, true},
+		{"type wildcard", "text/html, application/*;q=0.8", []Type{CBOR, JSON}, CBOR, true},
+		{"specific overrides wildcard", "application/*, application/cbor;q=0", []Type{CBOR, MsgPack}, MsgPack, true},
+		{"not acceptable", "text/html", all, "", false},
+		{"excluded", "application/json;q=0, text/json;q=0", []Type{JSON}, "", false},
+		{"malformed entries skipped", "application/json;q=x, application/cbor", all, CBOR, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := Negotiate(tt.accept, tt.supported)
+			if got != tt.want || ok != tt.ok {
+				t.Errorf("Negotiate(%q) = %q, %t; want %q, %t", tt.accept, got, ok, tt.want, tt.ok)
+			}
+		})
+	}
+}
@@ -0,0 +1,60 @@
+package codec
+
+import "fmt"
+
+// Option configures a codec at construction time. Options are created by the
+// With* functions of each codec package and are accepted by every New
+// constructor and by factory.New. Passing an option to a codec it was not
+// built for is reported as an ErrOptionNotSupported.
+type Option struct {
+	name  string
+	apply func(cfg any) bool
+}
+
+// NewOption returns an Option named name that configures codecs whose
+// configuration type is C. Codec packages use it to define their options;
+// the name is reported in errors and should be qualified with the package
+// name (e.g. "json.WithIndent").
+func NewOption[C any](name string, fn func(cfg *C)) Option {
+	return Option{
+		name: name,
+		apply: func(cfg any) bool {
+			c, ok := cfg.(*C)
+			if ok {
+				fn(c)
+			}
+			return ok
+		},
+	}
+}
+
+// Name returns the name of the option
+func (o Option) Name() string {
+	return o.name
+}
+
+// ApplyOptions applies opts to cfg, the configuration of a codec of type t.
+// It returns an ErrOptionNotSupported for the first option that does not
+// apply to cfg.
+func ApplyOptions[C any](t Type, cfg *C, opts []Option) error {
+	for _, opt := range opts {
+		if opt.apply == nil || !opt.apply(cfg) {
+			return ErrOptionNotSupported{Option: opt.name, CodecType: t}
+		}
+	}
+	return nil
+}
+
+// ErrOptionNotSupported is returned when an option is passed to a codec
+// that it does not apply to, such as a YAML option given to a JSON codec.
+type ErrOptionNotSupported struct {
+	Option    string
+	CodecType Type
+}
+
+func (e ErrOptionNotSupported) Error() string {
+	if e.Option == "" {
+		return fmt.Sprintf("invalid option for codec %q", e.CodecType)
+	}
+	return fmt.Sprintf("option %s is not supported by codec %q", e.Option, e.CodecType)
+}
@@ -0,0 +1,58 @@
+package codec
+
+import (
+	"errors"
+	"testing"
+)
+
+type testConfig struct {
+	indent int
+}
+
+type otherConfig struct{}
+
+func TestApplyOptions(t *testing.T) {
+	opt := NewOption("test.WithIndent", func(c *testConfig) {
+		c.indent = 4
+	})
+
+	var cfg testConfig
+	if err := ApplyOptions(JSON, &cfg, []Option{opt}); err != nil {
+		t.Fatalf("ApplyOptions failed: %v", err)
+	}
+	if cfg.indent != 4 {
+		t.Errorf("expected indent 4, got %d", cfg.indent)
+	}
+	if opt.Name() != "test.WithIndent" {
+		t.Errorf("expected name %q, got %q", "test.WithIndent", opt.Name())
+	}
+}
+
+func TestApplyOptions_NotSupported(t *testing.T) {
+	opt := NewOption("other.WithNothing", func(c *otherConfig) {})
+
+	var cfg testConfig
+	err := ApplyOptions(YAML, &cfg, []Option{opt})
+
+	var notSupported ErrOptionNotSupported
+	if !errors.As(err, &notSupported) {
+		t.Fatalf("expected ErrOptionNotSupported, got %v", err)
+	}
+	if notSupported.Option != "other.WithNothing" || notSupported.CodecType != YAML {
+		t.Errorf("unexpected error fields: %+v", notSupported)
+	}
+	if !contains(err.Error(), "other.WithNothing") {
+		t.Errorf("error message should contain option name, got: %s", err.Error())
+	}
+}
+
+func TestApplyOptions_ZeroOption(t *testing.T) {
+	var cfg testConfig
+	err := ApplyOptions(JSON, &cfg, []Option{{}})
+	if err == nil {
+		t.Fatal("expected error for zero option")
+	}
+	if !contains(err.Error(), string(JSON)) {
+		t.Errorf("error message should contain codec type, got: %s", err.Error())
+	}
+}
@@ -5,6 +5,7 @@ package avro
 import (
 	"io"
 	"reflect"
+	"sync"
 
 	"github.com/hamba/avro/v2"
 	codec "github.com/jeremyhahn/go-codec"
@@ -16,47 +17,145 @@ func init() {
 
 // Codec implements the codec.Codec interface for Avro serialization
 type Codec[T any] struct {
-	schema avro.Schema
+	schema  avro.Schema
+	api     avro.API
+	limits  codec.Limits
+	strict  bool
+	tagKey  string
+	readers sync.Pool
+	writers sync.Pool
+	err     error
 }
 
-// New creates a new Avro codec with automatic schema inference from the type parameter
-func New[T any]() *Codec[T] {
+// New creates a new Avro codec with automatic schema inference from the type
+// parameter, configured with the given options. If an option does not apply
+// to Avro, every operation returns the error, which is also reported by Err.
+func New[T any](opts ...codec.Option) *Codec[T] {
 	var zero T
 	schema := getOrCreateSchema(reflect.TypeOf(zero))
-	return &Codec[T]{
-		schema: schema,
-	}
+	c := &Codec[T]{schema: schema}
+	c.err = c.configure(opts)
+	return c
 }
 
-// NewWithSchema creates a new Avro codec with an explicit schema
-func NewWithSchema[T any](schemaJSON string) (*Codec[T], error) {
+// NewWithSchema creates a new Avro codec with an explicit schema, configured
+// with the given options
+func NewWithSchema[T any](schemaJSON string, opts ...codec.Option) (*Codec[T], error) {
 	schema, err := avro.Parse(schemaJSON)
 	if err != nil {
 		return nil, err
 	}
-	return &Codec[T]{schema: schema}, nil
+	c := &Codec[T]{schema: schema}
+	if err := c.configure(opts); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// configure applies opts to the codec
+func (c *Codec[T]) configure(opts []codec.Option) error {
+	var cfg config
+	if err := codec.ApplyOptions(codec.Avro, &cfg, opts); err != nil {
+		return err
+	}
+	c.api = cfg.api()
+	c.limits = cfg.Limits
+	c.strict = cfg.Strict
+	c.tagKey = "avro"
+	if cfg.avro != nil && cfg.avro.TagKey != "" {
+		c.tagKey = cfg.avro.TagKey
+	}
+	return nil
+}
+
+// Err returns the error, if any, caused by the options passed to New
+func (c *Codec[T]) Err() error {
+	return c.err
 }
 
 // Encode serializes the given data to the writer using Avro
 func (c *Codec[T]) Encode(w io.Writer, data T) error {
-	encoder := avro.NewEncoderForSchema(c.schema, w)
+	if c.err != nil {
+		return c.err
+	}
+	encoder := c.api.NewEncoder(c.schema, w)
 	return encoder.Encode(data)
 }
 
 // Decode deserializes Avro data from the reader into the provided type
 func (c *Codec[T]) Decode(r io.Reader, data *T) error {
-	decoder := avro.NewDecoderForSchema(c.schema, r)
-	return decoder.Decode(data)
+	if c.err != nil {
+		return c.err
+	}
+	limited := codec.NewLimitedReader(r, codec.Avro, c.limits.MaxBytes)
+	decoder := c.api.NewDecoder(c.schema, limited)
+	if c.strict {
+		return c.decodeStrict(limited, decoder, data)
+	}
+	return readError(limited, decoder.Decode(data))
 }
 
 // Marshal serializes the given data to Avro bytes
 func (c *Codec[T]) Marshal(data T) ([]byte, error) {
-	return avro.Marshal(c.schema, data)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.api.Marshal(c.schema, data)
 }
 
 // Unmarshal deserializes Avro bytes into the provided type
 func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
-	return avro.Unmarshal(c.schema, data, v)
+	if c.err != nil {
+		return c.err
+	}
+	return c.unmarshal(data, v, nil)
+}
+
+// unmarshal decodes data into v using a pooled reader. Strict decoding
+// encodes v again into scratch, which may be nil.
+func (c *Codec[T]) unmarshal(data []byte, v *T, scratch []byte) error {
+	if err := c.checkLimits(data); err != nil {
+		return err
+	}
+
+	// avro.API.Unmarshal treats running out of data as success, so the
+	// reader is driven directly to report truncated input
+	reader, _ := c.readers.Get().(*avro.Reader)
+	if reader == nil {
+		reader = avro.NewReader(nil, 0, avro.WithReaderConfig(c.api))
+	}
+	defer c.readers.Put(reader)
+
+	reader.Reset(data)
+	reader.Error = nil
+	reader.ReadVal(c.schema, v)
+	err := reader.Error
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	if err == nil && c.strict {
+		return c.checkExact(data, *v, scratch)
+	}
+	return decodeError(err)
+}
+
+// appendMarshal appends the encoding of data to buf. The value is written
+// by a pooled avro.Writer, whose buffer is reused across calls, and then
+// appended to buf.
+func (c *Codec[T]) appendMarshal(buf []byte, data T) ([]byte, error) {
+	writer, _ := c.writers.Get().(*avro.Writer)
+	if writer == nil {
+		writer = avro.NewWriter(nil, 512, avro.WithWriterConfig(c.api))
+	}
+	defer c.writers.Put(writer)
+
+	writer.Reset(nil)
+	writer.Error = nil
+	writer.WriteVal(c.schema, data)
+	if writer.Error != nil {
+		return buf, writer.Error
+	}
+	return append(buf, writer.Buffer()...), nil
 }
 
 // Schema returns the Avro schema used by this codec
@@ -0,0 +1,120 @@
+//go:build codec_avro
+
+package avro
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/hamba/avro/v2"
+	"github.com/hamba/avro/v2/ocf"
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// singleObjectHeader is the length of the marker and schema fingerprint
+// that start a single-object encoding
+const singleObjectHeader = 10
+
+// singleObjectMarker starts a single-object encoding
+var singleObjectMarker = []byte{0xc3, 0x01}
+
+// MarshalSingleObject serializes data using the Avro single-object
+// encoding: the marker C3 01, the CRC-64-AVRO fingerprint of the codec's
+// schema, and the encoded value. Readers use the fingerprint to find the
+// schema the value was written with.
+func (c *Codec[T]) MarshalSingleObject(data T) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	fingerprint, err := c.schema.FingerprintUsing(avro.CRC64AvroLE)
+	if err != nil {
+		return nil, err
+	}
+	body, err := c.api.Marshal(c.schema, data)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]byte, 0, singleObjectHeader+len(body))
+	out = append(out, singleObjectMarker...)
+	out = append(out, fingerprint...)
+	return append(out, body...), nil
+}
+
+// UnmarshalSingleObject deserializes a value in the Avro single-object
+// encoding. The fingerprint in the header must match the codec's schema;
+// a mismatch is reported as a codec.DecodeError of kind ErrTypeMismatch.
+func (c *Codec[T]) UnmarshalSingleObject(data []byte, v *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	if !bytes.HasPrefix(data, singleObjectMarker) {
+		return codec.DecodeError{Codec: codec.Avro, Kind: codec.ErrSyntax, Offset: 0, Err: errors.New("avro: missing single-object marker")}
+	}
+	if len(data) < singleObjectHeader {
+		return codec.DecodeError{Codec: codec.Avro, Kind: codec.ErrTruncated, Offset: int64(len(data)), Err: io.ErrUnexpectedEOF}
+	}
+	fingerprint, err := c.schema.FingerprintUsing(avro.CRC64AvroLE)
+	if err != nil {
+		return err
+	}
+	if !bytes.Equal(data[2:singleObjectHeader], fingerprint) {
+		return codec.DecodeError{
+			Codec:  codec.Avro,
+			Kind:   codec.ErrTypeMismatch,
+			Offset: 2,
+			Err:    fmt.Errorf("avro: schema fingerprint %x does not match the codec's schema %x", data[2:singleObjectHeader], fingerprint),
+		}
+	}
+
+	err = c.Unmarshal(data[singleObjectHeader:], v)
+	var de codec.DecodeError
+	if errors.As(err, &de) && de.Offset >= 0 {
+		de.Offset += singleObjectHeader
+		return de
+	}
+	return err
+}
+
+// UnmarshalContainer deserializes the single record of an Avro object
+// container file, decoding it with the schema stored in the file.
+// Containers holding no records or more than one are rejected.
+func (c *Codec[T]) UnmarshalContainer(data []byte, v *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	if err := c.limits.CheckBytes(int64(len(data))); err != nil {
+		return limitError(err, c.limits.MaxBytes, "")
+	}
+	decoder, err := ocf.NewDecoder(bytes.NewReader(data), ocf.WithDecoderConfig(c.api))
+	if err != nil {
+		return containerError(err)
+	}
+	if !decoder.HasNext() {
+		if err := decoder.Error(); err != nil {
+			return containerError(err)
+		}
+		return containerError(errors.New("avro: container holds no records"))
+	}
+	if err := decoder.Decode(v); err != nil {
+		return decodeError(err)
+	}
+	if decoder.HasNext() {
+		return containerError(errors.New("avro: container holds more than one record"))
+	}
+	if err := decoder.Error(); err != nil {
+		return containerError(err)
+	}
+	return nil
+}
+
+// containerError wraps err, reported while reading an object container
+// file, in a codec.DecodeError
+func containerError(err error) error {
+	kind := codec.ErrSyntax
+	if errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) {
+		kind = codec.ErrTruncated
+	}
+	return codec.DecodeError{Codec: codec.Avro, Kind: kind, Offset: -1, Err: err}
+}
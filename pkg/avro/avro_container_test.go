@@ -0,0 +1,106 @@
+//go:build codec_avro
+
+package avro
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestSingleObject_RoundTrip(t *testing.T) {
+	c := New[TestStruct]()
+	data := TestStruct{Name: "John Doe", Age: 30, Email: "john@example.com"}
+
+	encoded, err := c.MarshalSingleObject(data)
+	if err != nil {
+		t.Fatalf("MarshalSingleObject failed: %v", err)
+	}
+	if encoded[0] != 0xc3 || encoded[1] != 0x01 {
+		t.Errorf("Expected single-object marker, got % x", encoded[:2])
+	}
+
+	var decoded TestStruct
+	if err := c.UnmarshalSingleObject(encoded, &decoded); err != nil {
+		t.Fatalf("UnmarshalSingleObject failed: %v", err)
+	}
+	if decoded != data {
+		t.Errorf("Data mismatch: got %+v, want %+v", decoded, data)
+	}
+}
+
+func TestSingleObject_Errors(t *testing.T) {
+	c := New[TestStruct]()
+	encoded, err := c.MarshalSingleObject(TestStruct{Name: "John Doe"})
+	if err != nil {
+		t.Fatalf("MarshalSingleObject failed: %v", err)
+	}
+	other, err := New[map[string]string]().MarshalSingleObject(map[string]string{"a": "b"})
+	if err != nil {
+		t.Fatalf("MarshalSingleObject failed: %v", err)
+	}
+
+	tests := []struct {
+		name string
+		data []byte
+		kind codec.ErrorKind
+	}{
+		{"no marker", encoded[2:], codec.ErrSyntax},
+		{"short header", encoded[:6], codec.ErrTruncated},
+		{"other schema", other, codec.ErrTypeMismatch},
+		{"truncated body", encoded[:len(encoded)-1], codec.ErrTruncated},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v TestStruct
+			err := c.UnmarshalSingleObject(tt.data, &v)
+			if !errors.Is(err, tt.kind) {
+				t.Errorf("Expected %s, got %v", tt.kind, err)
+			}
+		})
+	}
+}
+
+func TestUnmarshalContainer(t *testing.T) {
+	c := New[TestStruct]()
+	container := func(values ...TestStruct) []byte {
+		t.Helper()
+		var buf bytes.Buffer
+		enc, err := ocf.NewEncoder(c.SchemaJSON(), &buf)
+		if err != nil {
+			t.Fatalf("NewEncoder failed: %v", err)
+		}
+		for _, v := range values {
+			if err := enc.Encode(v); err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+		}
+		if err := enc.Close(); err != nil {
+			t.Fatalf("Close failed: %v", err)
+		}
+		return buf.Bytes()
+	}
+	data := TestStruct{Name: "John Doe", Age: 30, Email: "john@example.com"}
+
+	var decoded TestStruct
+	if err := c.UnmarshalContainer(container(data), &decoded); err != nil {
+		t.Fatalf("UnmarshalContainer failed: %v", err)
+	}
+	if decoded != data {
+		t.Errorf("Data mismatch: got %+v, want %+v", decoded, data)
+	}
+
+	if err := c.UnmarshalContainer(container(), &decoded); !errors.Is(err, codec.ErrSyntax) {
+		t.Errorf("Expected error for empty container, got %v", err)
+	}
+	if err := c.UnmarshalContainer(container(data, data), &decoded); !errors.Is(err, codec.ErrSyntax) {
+		t.Errorf("Expected error for two records, got %v", err)
+	}
+	if err := c.UnmarshalContainer([]byte("Obj\x01"), &decoded); err == nil {
+		t.Error("Expected error for truncated container")
+	}
+}
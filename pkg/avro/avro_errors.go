@@ -0,0 +1,82 @@
+//go:build codec_avro
+
+package avro
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// decodeError converts an error returned by hamba/avro into a
+// codec.DecodeError. The library reports every failure as a plain error
+// prefixed with the names of the enclosing record fields, so failures are
+// classified by message and the path is rebuilt from the prefixes. A bare
+// io.EOF marks the clean end of a stream and is returned as is, as are read
+// errors.
+func decodeError(err error) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+
+	// Limit errors from a codec.LimitedReader are passed through intact
+	var de codec.DecodeError
+	if errors.As(err, &de) {
+		return de
+	}
+
+	de = codec.DecodeError{Codec: codec.Avro, Offset: -1, Err: err}
+	msg := err.Error()
+	var path []string
+	for {
+		i := strings.Index(msg, ": ")
+		if i < 0 || strings.HasPrefix(msg, "avro") {
+			break
+		}
+		if isFieldName(msg[:i]) {
+			path = append(path, msg[:i])
+		}
+		msg = msg[i+2:]
+	}
+	de.Path = strings.Join(path, ".")
+
+	switch {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF):
+		de.Kind = codec.ErrTruncated
+	case strings.Contains(msg, "is greater than `Config.Max"):
+		de.Kind = codec.ErrLimitExceeded
+	case strings.Contains(msg, " is unsupported for Avro "):
+		de.Kind = codec.ErrTypeMismatch
+	case strings.HasPrefix(msg, "avro: "):
+		de.Kind = codec.ErrSyntax
+	default:
+		return err
+	}
+	return de
+}
+
+// isFieldName reports whether s is a record field name as used by the
+// library in error prefixes
+func isFieldName(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r != '_' && (r < '0' || r > '9') && (r < 'a' || r > 'z') && (r < 'A' || r > 'Z') {
+			return false
+		}
+	}
+	return true
+}
+
+// readError converts an error returned by a decoder reading from r. The
+// library replaces read errors with io.EOF or io.ErrUnexpectedEOF, so an
+// exceeded limit is recovered from r.
+func readError(r io.Reader, err error) error {
+	if limit, ok := r.(*codec.LimitedReader); ok && err != nil && limit.Err() != nil {
+		return limit.Err()
+	}
+	return decodeError(err)
+}
@@ -0,0 +1,93 @@
+//go:build codec_avro
+
+package avro
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+type mismatchedStruct struct {
+	Name int `avro:"name"`
+}
+
+func TestUnmarshal_Truncated(t *testing.T) {
+	c := New[TestStruct]()
+	data, err := c.Marshal(TestStruct{Name: "John", Age: 30, Email: "john@example.com"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	for _, input := range [][]byte{nil, data[:1], data[:len(data)/2]} {
+		var v TestStruct
+		if err := c.Unmarshal(input, &v); !errors.Is(err, codec.ErrTruncated) {
+			t.Errorf("expected truncated error for %d bytes, got %v", len(input), err)
+		}
+	}
+
+	// The pooled reader is reset between calls
+	var v TestStruct
+	if err := c.Unmarshal(data, &v); err != nil || v.Name != "John" {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+}
+
+func TestUnmarshal_SyntaxError(t *testing.T) {
+	var v TestStruct
+	// A negative string length
+	err := New[TestStruct]().Unmarshal([]byte{0x7f, 0x01, 0x02}, &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Codec != codec.Avro || de.Kind != codec.ErrSyntax || de.Path != "Name" {
+		t.Errorf("unexpected error: %+v", de)
+	}
+}
+
+func TestUnmarshal_TypeMismatch(t *testing.T) {
+	source := New[TestStruct]()
+	data, err := source.Marshal(TestStruct{Name: "John"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	c, err := NewWithSchema[mismatchedStruct](source.SchemaJSON())
+	if err != nil {
+		t.Fatalf("NewWithSchema failed: %v", err)
+	}
+	var v mismatchedStruct
+	if err := c.Unmarshal(data, &v); !errors.Is(err, codec.ErrTypeMismatch) {
+		t.Fatalf("expected type mismatch, got %v", err)
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	c := New[TestStruct]()
+	data, err := c.Marshal(TestStruct{Name: "John", Age: 30})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	dec := c.NewDecoder(bytes.NewReader(append(append([]byte{}, data...), data[:2]...)))
+	var v TestStruct
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if err := dec.Decode(&v); !errors.Is(err, codec.ErrTruncated) {
+		t.Fatalf("expected truncated error, got %v", err)
+	}
+
+	dec = c.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if err := dec.Decode(&v); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
@@ -0,0 +1,234 @@
+//go:build codec_avro
+
+package avro
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+
+	"github.com/hamba/avro/v2"
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// checkLimits enforces the codec's limits on data, an Avro value encoded
+// with the codec's schema. When decoding strictly, the same scan rejects
+// duplicate map keys and record fields that T has no field for.
+func (c *Codec[T]) checkLimits(data []byte) error {
+	if err := c.limits.CheckBytes(int64(len(data))); err != nil {
+		return limitError(err, c.limits.MaxBytes, "")
+	}
+	if !c.limits.Structural() && !c.strict {
+		return nil
+	}
+	s := scanner{data: data, limits: c.limits}
+	var typ reflect.Type
+	if c.strict {
+		s.strict = &strictScan{tagKey: c.tagKey, fields: make(map[reflect.Type]map[string]reflect.Type)}
+		typ = reflect.TypeFor[T]()
+	}
+	if err := s.scan(c.schema, typ, 0, ""); err != nil && err != errMalformed {
+		return err
+	}
+	return nil
+}
+
+// scanner walks Avro binary data as described by a schema, checking nesting
+// depth, collection sizes and string lengths without decoding
+type scanner struct {
+	data   []byte
+	pos    int
+	limits codec.Limits
+	strict *strictScan
+}
+
+// errMalformed stops a scan of input that the decoder will reject
+var errMalformed = errors.New("malformed input")
+
+// scan checks the value at the current position, of the given schema, that
+// decodes into typ. Its depth is that of the enclosing record, array or
+// map; path names it using record field names, array indexes and map keys.
+// typ is only tracked when decoding strictly, and is nil when unknown.
+func (s *scanner) scan(schema avro.Schema, typ reflect.Type, depth int, path string) error {
+	start := int64(s.pos)
+	for typ != nil && typ.Kind() == reflect.Pointer {
+		typ = typ.Elem()
+	}
+	switch schema := schema.(type) {
+	case *avro.RefSchema:
+		return s.scan(schema.Schema(), typ, depth, path)
+
+	case *avro.RecordSchema:
+		if err := s.limits.CheckDepth(depth + 1); err != nil {
+			return limitError(err, start, path)
+		}
+		fields := s.strict.structFields(typ)
+		for _, field := range schema.Fields() {
+			fieldPath := joinPath(path, field.Name())
+			var fieldType reflect.Type
+			if fields != nil {
+				var ok bool
+				if fieldType, ok = lookupField(fields, field); !ok {
+					return strictError(codec.ErrUnknownField, int64(s.pos), fieldPath, fmt.Errorf("avro: unknown field %q", field.Name()))
+				}
+			}
+			if err := s.scan(field.Type(), fieldType, depth+1, fieldPath); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case *avro.ArraySchema:
+		return s.scanBlocks(schema.Items(), elemType(typ, reflect.Slice, reflect.Array), false, depth, path)
+
+	case *avro.MapSchema:
+		return s.scanBlocks(schema.Values(), elemType(typ, reflect.Map), true, depth, path)
+
+	case *avro.UnionSchema:
+		index, ok := s.long()
+		if !ok || index < 0 || index >= int64(len(schema.Types())) {
+			return errMalformed
+		}
+		return s.scan(schema.Types()[index], typ, depth, path)
+
+	case *avro.FixedSchema:
+		return s.skip(schema.Size())
+
+	case *avro.EnumSchema:
+		if _, ok := s.long(); !ok {
+			return errMalformed
+		}
+		return nil
+	}
+
+	switch schema.Type() {
+	case avro.Boolean:
+		return s.skip(1)
+	case avro.Int, avro.Long:
+		if _, ok := s.long(); !ok {
+			return errMalformed
+		}
+	case avro.Float:
+		return s.skip(4)
+	case avro.Double:
+		return s.skip(8)
+	case avro.Bytes, avro.String:
+		value, err := s.bytes()
+		if err != nil {
+			return err
+		}
+		if err := s.limits.CheckStringLength(int64(len(value))); err != nil {
+			return limitError(err, start, path)
+		}
+	}
+	return nil
+}
+
+// scanBlocks checks the blocks of an array, or of a map if isMap is set,
+// whose items are of the given schema and decode into typ
+func (s *scanner) scanBlocks(items avro.Schema, typ reflect.Type, isMap bool, depth int, path string) error {
+	if err := s.limits.CheckDepth(depth + 1); err != nil {
+		return limitError(err, int64(s.pos), path)
+	}
+	total := int64(0)
+	var seen map[string]bool
+	if isMap && s.strict != nil {
+		seen = make(map[string]bool)
+	}
+	for {
+		blockStart := int64(s.pos)
+		count, ok := s.long()
+		if !ok {
+			return errMalformed
+		}
+		if count == 0 {
+			return nil
+		}
+		if count < 0 {
+			// A negative count is followed by the block size in bytes
+			count = -count
+			if _, ok := s.long(); !ok {
+				return errMalformed
+			}
+		}
+		total += count
+		if err := s.limits.CheckElements(total); err != nil {
+			return limitError(err, blockStart, path)
+		}
+
+		for i := total - count; i < total; i++ {
+			itemPath := path + "[" + strconv.FormatInt(i, 10) + "]"
+			if isMap {
+				keyStart := int64(s.pos)
+				key, err := s.bytes()
+				if err != nil {
+					return err
+				}
+				itemPath = joinPath(path, string(key))
+				if err := s.limits.CheckStringLength(int64(len(key))); err != nil {
+					return limitError(err, keyStart, itemPath)
+				}
+				if seen[string(key)] {
+					return strictError(codec.ErrDuplicateKey, keyStart, itemPath, fmt.Errorf("avro: duplicate map key %q", key))
+				}
+				if seen != nil {
+					seen[string(key)] = true
+				}
+			}
+			if err := s.scan(items, typ, depth+1, itemPath); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// long reads a zig-zag encoded variable-length integer
+func (s *scanner) long() (int64, bool) {
+	var value uint64
+	for shift := uint(0); shift < 64; shift += 7 {
+		if s.pos >= len(s.data) {
+			return 0, false
+		}
+		b := s.data[s.pos]
+		s.pos++
+		value |= uint64(b&0x7f) << shift
+		if b&0x80 == 0 {
+			return int64(value>>1) ^ -int64(value&1), true
+		}
+	}
+	return 0, false
+}
+
+// bytes reads a length-prefixed byte or string value
+func (s *scanner) bytes() ([]byte, error) {
+	n, ok := s.long()
+	if !ok || n < 0 || n > int64(len(s.data)-s.pos) {
+		return nil, errMalformed
+	}
+	value := s.data[s.pos : s.pos+int(n)]
+	s.pos += int(n)
+	return value, nil
+}
+
+// skip advances past n bytes
+func (s *scanner) skip(n int) error {
+	if n > len(s.data)-s.pos {
+		return errMalformed
+	}
+	s.pos += n
+	return nil
+}
+
+// joinPath appends name to the dotted path
+func joinPath(path, name string) string {
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
+
+// limitError wraps err, a codec.LimitError, in a codec.DecodeError
+func limitError(err error, offset int64, path string) error {
+	return codec.DecodeError{Codec: codec.Avro, Kind: codec.ErrLimitExceeded, Offset: offset, Path: path, Err: err}
+}
@@ -0,0 +1,105 @@
+//go:build codec_avro
+
+package avro
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/jeremyhahn/go-codec"
+)
+
+const limitsSchema = `{"type":"record","name":"limits","fields":[
+	{"name":"name","type":"string"},
+	{"name":"tags","type":{"type":"array","items":"string"}},
+	{"name":"attrs","type":{"type":"map","values":{"type":"array","items":"long"}}},
+	{"name":"data","type":["null","bytes"]}
+]}`
+
+func TestLimits_Unmarshal(t *testing.T) {
+	value := map[string]any{
+		"name":  "John",
+		"tags":  []any{"a", "b", "c", "Johnny"},
+		"attrs": map[string]any{"k": []any{int64(1)}},
+		"data":  map[string]any{"bytes": []byte("binary")},
+	}
+	data, err := avro.Marshal(avro.MustParse(limitsSchema), value)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		limits codec.Limits
+		limit  string
+		path   string
+	}{
+		{"bytes", codec.Limits{MaxBytes: 8}, "MaxBytes", ""},
+		{"depth", codec.Limits{MaxDepth: 2}, "MaxDepth", "attrs.k"},
+		{"elements", codec.Limits{MaxElements: 3}, "MaxElements", "tags"},
+		{"string", codec.Limits{MaxStringLength: 5}, "MaxStringLength", "tags[3]"},
+		{"within limits", codec.Limits{MaxBytes: 64, MaxDepth: 3, MaxElements: 4, MaxStringLength: 6}, "", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewWithSchema[map[string]any](limitsSchema, codec.WithLimits(tt.limits))
+			if err != nil {
+				t.Fatalf("NewWithSchema failed: %v", err)
+			}
+			var v map[string]any
+			err = c.Unmarshal(data, &v)
+			if tt.limit == "" {
+				if err != nil {
+					t.Fatalf("Unmarshal failed: %v", err)
+				}
+				return
+			}
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			var le codec.LimitError
+			if de.Kind != codec.ErrLimitExceeded || !errors.As(err, &le) || le.Limit != tt.limit {
+				t.Fatalf("expected %s exceeded, got %v", tt.limit, err)
+			}
+			if de.Path != tt.path {
+				t.Errorf("expected path %q, got %q", tt.path, de.Path)
+			}
+		})
+	}
+}
+
+func TestLimits_Decoder(t *testing.T) {
+	c := New[TestStruct](codec.WithLimits(codec.Limits{MaxStringLength: 8}))
+	var buf bytes.Buffer
+	enc := c.NewEncoder(&buf)
+	for _, name := range []string{"John", "Jane", "Johnathan Doe"} {
+		if err := enc.Encode(TestStruct{Name: name}); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+	}
+
+	dec := c.NewDecoder(&buf)
+	var v TestStruct
+	for i := 0; i < 2; i++ {
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("Decode %d failed: %v", i, err)
+		}
+	}
+	if err := dec.Decode(&v); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+
+	data, _ := c.Marshal(TestStruct{Name: strings.Repeat("x", 200)})
+	c = New[TestStruct](codec.WithLimits(codec.Limits{MaxBytes: 64}))
+	err := c.Decode(bytes.NewReader(data), &v)
+	var le codec.LimitError
+	if !errors.Is(err, codec.ErrLimitExceeded) || !errors.As(err, &le) || le.Limit != "MaxBytes" {
+		t.Fatalf("expected MaxBytes exceeded, got %v", err)
+	}
+}
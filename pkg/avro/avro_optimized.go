@@ -0,0 +1,51 @@
+//go:build codec_avro
+
+package avro
+
+import (
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// OptimizedCodec implements zero-allocation Avro encoding/decoding
+type OptimizedCodec[T any] struct {
+	*Codec[T]
+}
+
+// NewPool creates a new optimized Avro codec, configured with the given
+// options
+func NewPool[T any](opts ...codec.Option) *OptimizedCodec[T] {
+	return &OptimizedCodec[T]{
+		Codec: New[T](opts...),
+	}
+}
+
+// MarshalTo marshals data into the provided buffer, reusing its capacity
+// and growing it only if the encoding does not fit
+func (c *OptimizedCodec[T]) MarshalTo(buf []byte, data T) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	out, err := c.appendMarshal(buf[:0], data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AppendMarshal appends marshaled data to the provided buffer
+func (c *OptimizedCodec[T]) AppendMarshal(buf []byte, data T) ([]byte, error) {
+	if c.err != nil {
+		return buf, c.err
+	}
+	return c.appendMarshal(buf, data)
+}
+
+// UnmarshalFrom unmarshals data into v, decoding straight from data with a
+// pooled reader. With strict decoding, the check for numbers that were not
+// stored exactly encodes v again into scratch.
+func (c *OptimizedCodec[T]) UnmarshalFrom(data []byte, v *T, scratch []byte) error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.unmarshal(data, v, scratch)
+}
@@ -0,0 +1,43 @@
+//go:build codec_avro
+
+package avro
+
+import (
+	"github.com/hamba/avro/v2"
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// config holds the settings applied by Avro options
+type config struct {
+	codec.Config
+
+	avro *avro.Config
+}
+
+// api returns the Avro library API for c. The codec's string and element
+// limits also bound the allocations the library makes, so they apply to
+// streams, which are not scanned before decoding.
+func (c config) api() avro.API {
+	if c.avro == nil && c.Limits.MaxStringLength <= 0 && c.Limits.MaxElements <= 0 {
+		return avro.DefaultConfig
+	}
+	var cfg avro.Config
+	if c.avro != nil {
+		cfg = *c.avro
+	}
+	if n := c.Limits.MaxStringLength; n > 0 && (cfg.MaxByteSliceSize <= 0 || n < cfg.MaxByteSliceSize) {
+		cfg.MaxByteSliceSize = n
+	}
+	if n := c.Limits.MaxElements; n > 0 && (cfg.MaxSliceAllocSize <= 0 || n < cfg.MaxSliceAllocSize) {
+		cfg.MaxSliceAllocSize = n
+	}
+	return cfg.Freeze()
+}
+
+// WithConfig encodes and decodes using the given Avro library configuration,
+// such as a custom struct tag key or block length, instead of the default
+func WithConfig(cfg avro.Config) codec.Option {
+	return codec.NewOption("avro.WithConfig", func(c *config) {
+		c.avro = &cfg
+	})
+}
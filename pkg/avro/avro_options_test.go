@@ -0,0 +1,105 @@
+//go:build codec_avro
+
+package avro
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/jeremyhahn/go-codec"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+)
+
+type jsonTagged struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+const jsonTaggedSchema = `{"type":"record","name":"jsonTagged","fields":[{"name":"name","type":"string"},{"name":"age","type":"long"}]}`
+
+func TestWithConfig(t *testing.T) {
+	c, err := NewWithSchema[jsonTagged](jsonTaggedSchema, WithConfig(avro.Config{TagKey: "json"}))
+	if err != nil {
+		t.Fatalf("NewWithSchema failed: %v", err)
+	}
+	data := jsonTagged{Name: "John", Age: 30}
+
+	result, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var decoded jsonTagged
+	if err := c.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded != data {
+		t.Errorf("expected %+v, got %+v", data, decoded)
+	}
+
+	// Without the json tag key the fields are not matched
+	plain, err := NewWithSchema[jsonTagged](jsonTaggedSchema)
+	if err != nil {
+		t.Fatalf("NewWithSchema failed: %v", err)
+	}
+	if _, err := plain.Marshal(data); err == nil {
+		t.Error("expected error marshaling without the json tag key")
+	}
+}
+
+func TestWithConfig_Stream(t *testing.T) {
+	c := New[TestStruct](WithConfig(avro.Config{TagKey: "avro", BlockLength: 10}))
+	data := TestStruct{Name: "John", Age: 30, Email: "john@example.com"}
+
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, data); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	var decoded TestStruct
+	if err := c.Decode(&buf, &decoded); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if decoded != data {
+		t.Errorf("expected %+v, got %+v", data, decoded)
+	}
+}
+
+func TestNewWithSchema_OptionNotSupported(t *testing.T) {
+	_, err := NewWithSchema[jsonTagged](jsonTaggedSchema, jsoncodec.WithUseNumber())
+
+	var notSupported codec.ErrOptionNotSupported
+	if !errors.As(err, &notSupported) {
+		t.Fatalf("expected ErrOptionNotSupported, got %v", err)
+	}
+}
+
+func TestNew_OptionNotSupported(t *testing.T) {
+	c := New[TestStruct](jsoncodec.WithUseNumber())
+
+	var notSupported codec.ErrOptionNotSupported
+	if !errors.As(c.Err(), &notSupported) {
+		t.Fatalf("expected ErrOptionNotSupported, got %v", c.Err())
+	}
+
+	var result TestStruct
+	if _, err := c.Marshal(TestStruct{}); err == nil {
+		t.Error("Marshal: expected option error, got nil")
+	}
+	if err := c.Unmarshal([]byte{0x00}, &result); err == nil {
+		t.Error("Unmarshal: expected option error, got nil")
+	}
+	if err := c.Encode(&bytes.Buffer{}, TestStruct{}); err == nil {
+		t.Error("Encode: expected option error, got nil")
+	}
+	if err := c.Decode(bytes.NewReader([]byte{0x00}), &result); err == nil {
+		t.Error("Decode: expected option error, got nil")
+	}
+	if err := c.NewEncoder(&bytes.Buffer{}).Encode(TestStruct{}); err == nil {
+		t.Error("Encoder.Encode: expected option error, got nil")
+	}
+	if err := c.NewDecoder(bytes.NewReader([]byte{0x00})).Decode(&result); err == nil {
+		t.Error("Decoder.Decode: expected option error, got nil")
+	}
+}
@@ -0,0 +1,65 @@
+//go:build codec_avro
+
+package avro
+
+import (
+	"io"
+
+	"github.com/hamba/avro/v2"
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// Encoder writes a sequence of Avro values to a stream using the codec's schema
+type Encoder[T any] struct {
+	enc *avro.Encoder
+	err error
+}
+
+// Decoder reads a sequence of Avro values from a stream using the codec's schema
+type Decoder[T any] struct {
+	codec *Codec[T]
+	dec   *avro.Decoder
+	r     io.Reader
+	limit *codec.LimitedReader
+	err   error
+}
+
+// NewEncoder returns an encoder session that writes successive Avro values to w
+func (c *Codec[T]) NewEncoder(w io.Writer) codec.Encoder[T] {
+	if c.err != nil {
+		return &Encoder[T]{err: c.err}
+	}
+	return &Encoder[T]{enc: c.api.NewEncoder(c.schema, w)}
+}
+
+// NewDecoder returns a decoder session that reads successive Avro values from r
+func (c *Codec[T]) NewDecoder(r io.Reader) codec.Decoder[T] {
+	if c.err != nil {
+		return &Decoder[T]{err: c.err}
+	}
+	r = codec.NewLimitedReader(r, codec.Avro, c.limits.MaxBytes)
+	limit, _ := r.(*codec.LimitedReader)
+	return &Decoder[T]{codec: c, dec: c.api.NewDecoder(c.schema, r), r: r, limit: limit}
+}
+
+// Encode writes the next Avro value to the stream
+func (e *Encoder[T]) Encode(data T) error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.enc.Encode(data)
+}
+
+// Decode reads the next Avro value from the stream
+func (d *Decoder[T]) Decode(data *T) error {
+	if d.err != nil {
+		return d.err
+	}
+	if d.limit != nil {
+		d.limit.Reset()
+	}
+	if d.codec.strict {
+		return d.codec.decodeStrict(d.r, d.dec, data)
+	}
+	return readError(d.r, d.dec.Decode(data))
+}
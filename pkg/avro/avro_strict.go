@@ -0,0 +1,137 @@
+//go:build codec_avro
+
+package avro
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+
+	"github.com/hamba/avro/v2"
+	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/internal/exact"
+)
+
+// strictScan holds the struct fields seen while scanning strictly
+type strictScan struct {
+	tagKey string
+	fields map[reflect.Type]map[string]reflect.Type
+}
+
+// structFields returns the fields of typ by Avro name, as the library
+// matches them to record fields, or nil if typ is not a struct and so
+// accepts any record field
+func (s *strictScan) structFields(typ reflect.Type) map[string]reflect.Type {
+	if s == nil || typ == nil || typ.Kind() != reflect.Struct {
+		return nil
+	}
+	if fields, ok := s.fields[typ]; ok {
+		return fields
+	}
+
+	// Fields of embedded structs are promoted, shallower ones first
+	fields := make(map[string]reflect.Type)
+	next := []reflect.Type{typ}
+	visited := make(map[reflect.Type]bool)
+	for len(next) > 0 {
+		curr := next
+		next = nil
+		for _, t := range curr {
+			if visited[t] {
+				continue
+			}
+			visited[t] = true
+			for i := 0; i < t.NumField(); i++ {
+				field := t.Field(i)
+				if field.Anonymous {
+					ft := field.Type
+					if ft.Kind() == reflect.Pointer {
+						ft = ft.Elem()
+					}
+					if ft.Kind() == reflect.Struct {
+						next = append(next, ft)
+					}
+					continue
+				}
+				if !field.IsExported() {
+					continue
+				}
+				name := field.Name
+				if tag, ok := field.Tag.Lookup(s.tagKey); ok {
+					name, _, _ = strings.Cut(tag, ",")
+				}
+				if _, ok := fields[name]; !ok {
+					fields[name] = field.Type
+				}
+			}
+		}
+	}
+	s.fields[typ] = fields
+	return fields
+}
+
+// lookupField returns the type of the struct field that field decodes into,
+// matched by name or alias
+func lookupField(fields map[string]reflect.Type, field *avro.Field) (reflect.Type, bool) {
+	if t, ok := fields[field.Name()]; ok {
+		return t, true
+	}
+	for _, alias := range field.Aliases() {
+		if t, ok := fields[alias]; ok {
+			return t, true
+		}
+	}
+	return nil, false
+}
+
+// elemType returns the element type of typ if it is of one of the given
+// kinds, and nil otherwise
+func elemType(typ reflect.Type, kinds ...reflect.Kind) reflect.Type {
+	if typ == nil || !slices.Contains(kinds, typ.Kind()) {
+		return nil
+	}
+	return typ.Elem()
+}
+
+// decodeStrict reads the next value from decoder, which reads from r, and
+// decodes it into v through Unmarshal so that it is checked like a single
+// value. The library decodes streams without exposing the bytes of each
+// value, so the value is decoded generically and encoded again.
+func (c *Codec[T]) decodeStrict(r io.Reader, decoder *avro.Decoder, v *T) error {
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		return readError(r, err)
+	}
+	data, err := c.api.Marshal(c.schema, value)
+	if err != nil {
+		return err
+	}
+	return c.Unmarshal(data, v)
+}
+
+// checkExact compares the numbers in data with those held by v, which data
+// was decoded into, encoding v again into scratch. The library lets
+// integers wrap around when decoded into smaller or unsigned types.
+func (c *Codec[T]) checkExact(data []byte, v T, scratch []byte) error {
+	var input, decoded any
+	if c.api.Unmarshal(c.schema, data, &input) != nil {
+		return nil
+	}
+	out, err := c.appendMarshal(scratch[:0], v)
+	if err != nil || c.api.Unmarshal(c.schema, out, &decoded) != nil {
+		// Values that cannot be encoded again are not checked
+		return nil
+	}
+	path, value, lossy := exact.Compare(input, decoded)
+	if !lossy {
+		return nil
+	}
+	return strictError(codec.ErrTypeMismatch, -1, path, fmt.Errorf("avro: %v cannot be stored exactly in the destination", value))
+}
+
+// strictError reports a strict decoding violation at offset and path
+func strictError(kind codec.ErrorKind, offset int64, path string, err error) error {
+	return codec.DecodeError{Codec: codec.Avro, Kind: kind, Offset: offset, Path: path, Err: err}
+}
@@ -0,0 +1,122 @@
+//go:build codec_avro
+
+package avro
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/hamba/avro/v2"
+	"github.com/jeremyhahn/go-codec"
+)
+
+const strictSchema = `{"type":"record","name":"server","fields":[
+	{"name":"name","type":"string"},
+	{"name":"port","type":"int"},
+	{"name":"host","type":"string"}
+]}`
+
+type strictServer struct {
+	Name string `avro:"name"`
+	Port int8   `avro:"port"`
+	Host string `avro:"host"`
+}
+
+type strictServerName struct {
+	Name string `avro:"name"`
+	Port int8   `avro:"port"`
+}
+
+func strictData(t *testing.T, port int) []byte {
+	t.Helper()
+	data, err := avro.Marshal(avro.MustParse(strictSchema), map[string]any{"name": "api", "port": port, "host": "localhost"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	return data
+}
+
+func assertDecodeError(t *testing.T, err error, kind codec.ErrorKind, path string) {
+	t.Helper()
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Kind != kind || de.Path != path {
+		t.Errorf("expected %s at %q, got %+v", kind, path, de)
+	}
+}
+
+func TestStrict_UnknownField(t *testing.T) {
+	c, err := NewWithSchema[strictServerName](strictSchema, codec.WithStrict())
+	if err != nil {
+		t.Fatalf("NewWithSchema failed: %v", err)
+	}
+
+	var v strictServerName
+	assertDecodeError(t, c.Unmarshal(strictData(t, 80), &v), codec.ErrUnknownField, "host")
+}
+
+func TestStrict_Overflow(t *testing.T) {
+	c, err := NewWithSchema[strictServer](strictSchema, codec.WithStrict())
+	if err != nil {
+		t.Fatalf("NewWithSchema failed: %v", err)
+	}
+
+	var v strictServer
+	assertDecodeError(t, c.Unmarshal(strictData(t, 300), &v), codec.ErrTypeMismatch, "port")
+
+	if err := c.Unmarshal(strictData(t, 80), &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if v.Port != 80 || v.Host != "localhost" {
+		t.Errorf("unexpected value: %+v", v)
+	}
+}
+
+func TestStrict_DuplicateKey(t *testing.T) {
+	// A map block of two entries, both with key "a"
+	data := []byte{0x04, 0x02, 'a', 0x02, 0x02, 'a', 0x04, 0x00}
+
+	var v map[string]int64
+	err := New[map[string]int64](codec.WithStrict()).Unmarshal(data, &v)
+	assertDecodeError(t, err, codec.ErrDuplicateKey, "a")
+
+	if err := New[map[string]int64]().Unmarshal(data, &v); err != nil {
+		t.Fatalf("lenient Unmarshal failed: %v", err)
+	}
+}
+
+func TestStrict_Decoder(t *testing.T) {
+	c, err := NewWithSchema[strictServer](strictSchema, codec.WithStrict())
+	if err != nil {
+		t.Fatalf("NewWithSchema failed: %v", err)
+	}
+	stream := append(strictData(t, 80), strictData(t, 300)...)
+	dec := c.NewDecoder(bytes.NewReader(stream))
+
+	var v strictServer
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if v.Port != 80 {
+		t.Errorf("unexpected value: %+v", v)
+	}
+	assertDecodeError(t, dec.Decode(&v), codec.ErrTypeMismatch, "port")
+}
+
+func TestStrict_Lenient(t *testing.T) {
+	c, err := NewWithSchema[strictServerName](strictSchema)
+	if err != nil {
+		t.Fatalf("NewWithSchema failed: %v", err)
+	}
+
+	var v strictServerName
+	if err := c.Unmarshal(strictData(t, 300), &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if v.Name != "api" {
+		t.Errorf("unexpected value: %+v", v)
+	}
+}
@@ -4,6 +4,7 @@ package avro
 
 import (
 	"io"
+	"reflect"
 
 	codec "github.com/jeremyhahn/go-codec"
 )
@@ -14,12 +15,17 @@ var errNotSupported = codec.ErrCodecNotSupported{CodecType: codec.Avro}
 type Codec[T any] struct{}
 
 // New returns an Avro codec stub that will error on all operations.
-func New[T any]() *Codec[T] {
+func New[T any](opts ...codec.Option) *Codec[T] {
 	return &Codec[T]{}
 }
 
+// Err returns an error indicating Avro codec is not supported.
+func (c *Codec[T]) Err() error {
+	return errNotSupported
+}
+
 // NewWithSchema returns an Avro codec stub that will error on all operations.
-func NewWithSchema[T any](schemaJSON string) (*Codec[T], error) {
+func NewWithSchema[T any](schemaJSON string, opts ...codec.Option) (*Codec[T], error) {
 	return nil, errNotSupported
 }
 
@@ -43,6 +49,59 @@ func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
 	return errNotSupported
 }
 
+// Encoder is a stub for the Avro encoder session.
+type Encoder[T any] struct{}
+
+// Decoder is a stub for the Avro decoder session.
+type Decoder[T any] struct{}
+
+// NewEncoder returns a Avro encoder stub that will error on all operations.
+func (c *Codec[T]) NewEncoder(w io.Writer) codec.Encoder[T] {
+	return &Encoder[T]{}
+}
+
+// NewDecoder returns a Avro decoder stub that will error on all operations.
+func (c *Codec[T]) NewDecoder(r io.Reader) codec.Decoder[T] {
+	return &Decoder[T]{}
+}
+
+// Encode returns an error indicating Avro codec is not supported.
+func (e *Encoder[T]) Encode(data T) error {
+	return errNotSupported
+}
+
+// Decode returns an error indicating Avro codec is not supported.
+func (d *Decoder[T]) Decode(data *T) error {
+	return errNotSupported
+}
+
+// OptimizedCodec is a stub for the optimized Avro codec.
+type OptimizedCodec[T any] struct {
+	*Codec[T]
+}
+
+// NewPool returns an optimized Avro codec stub that will error on all operations.
+func NewPool[T any](opts ...codec.Option) *OptimizedCodec[T] {
+	return &OptimizedCodec[T]{
+		Codec: New[T](opts...),
+	}
+}
+
+// MarshalTo returns an error indicating Avro codec is not supported.
+func (c *OptimizedCodec[T]) MarshalTo(buf []byte, data T) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+// AppendMarshal returns an error indicating Avro codec is not supported.
+func (c *OptimizedCodec[T]) AppendMarshal(buf []byte, data T) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+// UnmarshalFrom returns an error indicating Avro codec is not supported.
+func (c *OptimizedCodec[T]) UnmarshalFrom(data []byte, v *T, scratch []byte) error {
+	return errNotSupported
+}
+
 // Schema returns nil when Avro codec is not supported.
 func (c *Codec[T]) Schema() interface{} {
 	return nil
@@ -52,3 +111,31 @@ func (c *Codec[T]) Schema() interface{} {
 func (c *Codec[T]) SchemaJSON() string {
 	return ""
 }
+
+// SchemaJSONOf returns an empty string when Avro codec is not supported.
+func SchemaJSONOf(t reflect.Type) string {
+	return ""
+}
+
+// config is a stub for the Avro codec settings.
+type config struct{}
+
+// WithConfig returns an Avro option stub.
+func WithConfig(cfg interface{}) codec.Option {
+	return codec.NewOption("avro.WithConfig", func(c *config) {})
+}
+
+// MarshalSingleObject returns an error indicating Avro codec is not supported.
+func (c *Codec[T]) MarshalSingleObject(data T) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+// UnmarshalSingleObject returns an error indicating Avro codec is not supported.
+func (c *Codec[T]) UnmarshalSingleObject(data []byte, v *T) error {
+	return errNotSupported
+}
+
+// UnmarshalContainer returns an error indicating Avro codec is not supported.
+func (c *Codec[T]) UnmarshalContainer(data []byte, v *T) error {
+	return errNotSupported
+}
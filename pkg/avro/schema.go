@@ -58,6 +58,14 @@ func getOrCreateSchema(t reflect.Type) avro.Schema {
 	return schema
 }
 
+// SchemaJSONOf returns, as JSON, the Avro schema that New infers for values
+// of type t. Pointers become unions with null, time.Time a timestamp-micros
+// long, and structs records named after the type, or "AnonymousRecordN" in
+// the "go.codec.generated" namespace for unnamed structs.
+func SchemaJSONOf(t reflect.Type) string {
+	return getOrCreateSchema(t).String()
+}
+
 // generateSchema creates an Avro schema from a Go type
 func generateSchema(t reflect.Type) avro.Schema {
 	// Handle pointer types
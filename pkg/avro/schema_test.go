@@ -4,6 +4,7 @@ package avro
 
 import (
 	"reflect"
+	"strings"
 	"testing"
 	"time"
 )
@@ -315,3 +316,19 @@ func TestGetOrCreateSchema_ConcurrentAccess(t *testing.T) {
 		}
 	}
 }
+
+func TestSchemaJSONOf(t *testing.T) {
+	type Event struct {
+		ID   int64   `avro:"id"`
+		Note *string `avro:"note"`
+	}
+
+	got := SchemaJSONOf(reflect.TypeOf(Event{}))
+	want := New[Event]().SchemaJSON()
+	if got != want {
+		t.Errorf("SchemaJSONOf = %s, want %s", got, want)
+	}
+	if !strings.Contains(got, `"name":"note","type":["null","string"]`) {
+		t.Errorf("Expected a nullable note field, got %s", got)
+	}
+}
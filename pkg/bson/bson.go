@@ -3,48 +3,194 @@
 package bson
 
 import (
+	"bytes"
 	"io"
 
 	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/pool"
 	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
 )
 
 func init() {
 	codec.RegisterCodec(codec.BSON)
+	codec.RegisterValueCodec(codec.BSON, valueCodec{})
 }
 
 // Codec implements the codec.Codec interface for BSON serialization
-type Codec[T any] struct{}
+type Codec[T any] struct {
+	cfg config
+	err error
+}
+
+// New creates a new BSON codec configured with the given options. If an
+// option does not apply to BSON, every operation returns the error, which
+// is also reported by Err.
+func New[T any](opts ...codec.Option) *Codec[T] {
+	c := &Codec[T]{}
+	c.err = codec.ApplyOptions(codec.BSON, &c.cfg, opts)
+	return c
+}
 
-// New creates a new BSON codec
-func New[T any]() *Codec[T] {
-	return &Codec[T]{}
+// Err returns the error, if any, caused by the options passed to New
+func (c *Codec[T]) Err() error {
+	return c.err
 }
 
 // Encode serializes the given data to the writer using BSON
 func (c *Codec[T]) Encode(w io.Writer, data T) error {
-	bytes, err := bson.Marshal(data)
+	bytes, err := c.Marshal(data)
 	if err == nil {
 		_, err = w.Write(bytes)
 	}
 	return err
 }
 
-// Decode deserializes BSON data from the reader into the provided type
+// Decode deserializes a single BSON document from the reader into the provided
+// type. Only the bytes of that document are consumed from the reader.
 func (c *Codec[T]) Decode(r io.Reader, data *T) error {
-	bytes, err := io.ReadAll(r)
+	if c.err != nil {
+		return c.err
+	}
+	bytes, err := readDocument(r, c.cfg.Limits.MaxBytes)
 	if err != nil {
-		return err
+		return readError(err)
 	}
-	return bson.Unmarshal(bytes, data)
+	return c.Unmarshal(bytes, data)
 }
 
 // Marshal serializes the given data to BSON bytes
 func (c *Codec[T]) Marshal(data T) ([]byte, error) {
-	return bson.Marshal(data)
+	if c.err != nil {
+		return nil, c.err
+	}
+	if v, ok := any(data).(codec.Value); ok {
+		return c.marshalValue(v)
+	}
+	if c.cfg.driverDefaults() {
+		return bson.Marshal(data)
+	}
+
+	var buf bytes.Buffer
+	if err := c.encode(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 // Unmarshal deserializes BSON bytes into the provided type
 func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
-	return bson.Unmarshal(data, v)
+	if c.err != nil {
+		return c.err
+	}
+	if value, ok := any(v).(*codec.Value); ok {
+		return c.unmarshalValue(data, value)
+	}
+	if err := c.checkLimits(data); err != nil {
+		return err
+	}
+	if c.cfg.Strict {
+		if err := c.checkStrict(data); err != nil {
+			return err
+		}
+	}
+	if c.cfg.driverDefaults() {
+		return decodeError(bson.Unmarshal(data, v), data)
+	}
+
+	decoder, err := bson.NewDecoder(bsonrw.NewBSONDocumentReader(data))
+	if err != nil {
+		return err
+	}
+	if err := c.configureDecoder(decoder); err != nil {
+		return err
+	}
+	return decodeError(decoder.Decode(v), data)
+}
+
+// appendMarshal appends the encoding of data to buf, writing directly into
+// buf while it has room
+func (c *Codec[T]) appendMarshal(buf []byte, data T) ([]byte, error) {
+	if v, ok := any(data).(codec.Value); ok {
+		raw, err := c.marshalValue(v)
+		if err != nil {
+			return buf, err
+		}
+		return append(buf, raw...), nil
+	}
+	if c.cfg.driverDefaults() {
+		out, err := bson.MarshalAppend(buf, data)
+		if err != nil {
+			return buf, err
+		}
+		return out, nil
+	}
+
+	out := pool.GetAppendBuffer(buf)
+	defer pool.PutAppendBuffer(out)
+	if err := c.encode(out, data); err != nil {
+		return buf, err
+	}
+	return out.Bytes(), nil
+}
+
+// encode writes data to w with an encoder configured with the codec's
+// settings
+func (c *Codec[T]) encode(w io.Writer, data T) error {
+	vw, err := bsonrw.NewBSONValueWriter(w)
+	if err != nil {
+		return err
+	}
+	encoder, err := bson.NewEncoder(vw)
+	if err != nil {
+		return err
+	}
+	if err := c.configureEncoder(encoder); err != nil {
+		return err
+	}
+	return encoder.Encode(data)
+}
+
+// configureEncoder applies the codec's settings to encoder
+func (c *Codec[T]) configureEncoder(encoder *bson.Encoder) error {
+	if c.cfg.registry != nil {
+		if err := encoder.SetRegistry(c.cfg.registry); err != nil {
+			return err
+		}
+	}
+	if c.cfg.jsonStructTags {
+		encoder.UseJSONStructTags()
+	}
+	if c.cfg.nilSliceAsEmpty {
+		encoder.NilSliceAsEmpty()
+	}
+	if c.cfg.nilMapAsEmpty {
+		encoder.NilMapAsEmpty()
+	}
+	if c.cfg.omitZeroStruct {
+		encoder.OmitZeroStruct()
+	}
+	if c.cfg.intMinSize {
+		encoder.IntMinSize()
+	}
+	return nil
+}
+
+// configureDecoder applies the codec's settings to decoder
+func (c *Codec[T]) configureDecoder(decoder *bson.Decoder) error {
+	if c.cfg.registry != nil {
+		if err := decoder.SetRegistry(c.cfg.registry); err != nil {
+			return err
+		}
+	}
+	if c.cfg.jsonStructTags {
+		decoder.UseJSONStructTags()
+	}
+	if c.cfg.defaultDocumentM {
+		decoder.DefaultDocumentM()
+	}
+	if c.cfg.allowTruncatingDoubles && !c.cfg.Strict {
+		decoder.AllowTruncatingDoubles()
+	}
+	return nil
 }
@@ -0,0 +1,66 @@
+//go:build codec_bson
+
+package bson
+
+import (
+	"encoding/binary"
+	"errors"
+	"io"
+	"strings"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+)
+
+// errInvalidLength is returned when a document header holds an impossible length
+var errInvalidLength = errors.New("bson: invalid document length")
+
+// decodeError converts an error returned while unmarshaling data, a BSON
+// document, into a codec.DecodeError. The driver reports most failures as
+// plain errors, so they are classified by message; the key path is taken
+// from bsoncodec.DecodeError when present. Misuse of the API, such as a
+// destination type without a decoder, is returned as is.
+func decodeError(err error, data []byte) error {
+	if err == nil {
+		return err
+	}
+	if errors.Is(err, bson.ErrDecodeToNil) || errors.As(err, new(bsoncodec.ErrNoDecoder)) {
+		return err
+	}
+
+	de := codec.DecodeError{Codec: codec.BSON, Offset: -1, Err: err}
+	var keyErr *bsoncodec.DecodeError
+	if errors.As(err, &keyErr) {
+		de.Path = strings.Join(keyErr.Keys(), ".")
+	}
+
+	var valueErr bsoncodec.ValueDecoderError
+	switch msg := err.Error(); {
+	case errors.Is(err, io.EOF), errors.Is(err, io.ErrUnexpectedEOF),
+		len(data) >= 4 && int64(binary.LittleEndian.Uint32(data)) > int64(len(data)):
+		de.Kind = codec.ErrTruncated
+	case errors.As(err, &valueErr),
+		strings.Contains(msg, "cannot decode "),
+		strings.Contains(msg, " can only "),
+		strings.Contains(msg, " overflows "),
+		strings.Contains(msg, "truncat"):
+		de.Kind = codec.ErrTypeMismatch
+	default:
+		de.Kind = codec.ErrSyntax
+	}
+	return de
+}
+
+// readError converts an error returned by readDocument. A clean end of
+// stream and failures of the underlying reader are returned as is.
+func readError(err error) error {
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		return codec.DecodeError{Codec: codec.BSON, Kind: codec.ErrTruncated, Offset: -1, Err: err}
+	case errors.Is(err, errInvalidLength):
+		return codec.DecodeError{Codec: codec.BSON, Kind: codec.ErrSyntax, Offset: 0, Err: err}
+	default:
+		return err
+	}
+}
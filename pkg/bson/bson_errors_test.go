@@ -0,0 +1,89 @@
+//go:build codec_bson
+
+package bson
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type userRecord struct {
+	User struct {
+		Name string `bson:"name"`
+		Age  int    `bson:"age"`
+	} `bson:"user"`
+}
+
+func TestUnmarshal_TypeMismatch(t *testing.T) {
+	data, err := bson.Marshal(bson.M{"user": bson.M{"name": "John", "age": "thirty"}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	for _, opts := range [][]codec.Option{nil, {WithAllowTruncatingDoubles()}} {
+		var v userRecord
+		err = New[userRecord](opts...).Unmarshal(data, &v)
+
+		var de codec.DecodeError
+		if !errors.As(err, &de) {
+			t.Fatalf("expected DecodeError, got %v", err)
+		}
+		if de.Codec != codec.BSON || de.Kind != codec.ErrTypeMismatch || de.Path != "user.age" {
+			t.Errorf("unexpected error: %+v", de)
+		}
+	}
+}
+
+func TestUnmarshal_Truncated(t *testing.T) {
+	data, err := New[TestStruct]().Marshal(TestStruct{Name: "John", Age: 30})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var v TestStruct
+	if err := New[TestStruct]().Unmarshal(data[:len(data)/2], &v); !errors.Is(err, codec.ErrTruncated) {
+		t.Fatalf("expected truncated error, got %v", err)
+	}
+
+	if err := New[TestStruct]().Decode(bytes.NewReader(data[:len(data)/2]), &v); !errors.Is(err, codec.ErrTruncated) {
+		t.Fatalf("expected truncated error, got %v", err)
+	}
+}
+
+func TestDecode_InvalidLength(t *testing.T) {
+	var v TestStruct
+	err := New[TestStruct]().Decode(bytes.NewReader([]byte{0x01, 0x00, 0x00, 0x00}), &v)
+	if !errors.Is(err, codec.ErrSyntax) {
+		t.Fatalf("expected syntax error, got %v", err)
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	c := New[TestStruct]()
+	data, err := c.Marshal(TestStruct{Name: "John"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	dec := c.NewDecoder(bytes.NewReader(append(append([]byte{}, data...), data[:3]...)))
+	var v TestStruct
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if err := dec.Decode(&v); !errors.Is(err, codec.ErrTruncated) {
+		t.Fatalf("expected truncated error, got %v", err)
+	}
+
+	dec = c.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if err := dec.Decode(&v); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
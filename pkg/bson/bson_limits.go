@@ -0,0 +1,80 @@
+//go:build codec_bson
+
+package bson
+
+import (
+	codec "github.com/jeremyhahn/go-codec"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// checkLimits enforces the codec's limits on data, a BSON document
+func (c *Codec[T]) checkLimits(data []byte) error {
+	limits := c.cfg.Limits
+	if err := limits.CheckBytes(int64(len(data))); err != nil {
+		return limitError(err, limits.MaxBytes, "")
+	}
+	if !limits.Structural() {
+		return nil
+	}
+	return checkDocument(data, 0, 1, "", limits)
+}
+
+// checkDocument checks doc, an embedded document or array found at offset
+// in the input with the given nesting depth, and everything it contains.
+// The error path names keys as the driver does, joined with dots. Malformed
+// documents are left for the decoder to report.
+func checkDocument(doc []byte, offset int64, depth int, path string, limits codec.Limits) error {
+	if err := limits.CheckDepth(depth); err != nil {
+		return limitError(err, offset, path)
+	}
+	length, rem, ok := bsoncore.ReadLength(doc)
+	if !ok || int(length) > len(doc) {
+		return nil
+	}
+	rem = rem[:length-4]
+
+	count := int64(0)
+	for len(rem) > 1 {
+		start := offset + int64(len(doc)-len(rem))
+		elem, next, ok := bsoncore.ReadElement(rem)
+		if !ok {
+			return nil
+		}
+		rem = next
+
+		count++
+		key := elem.Key()
+		if path != "" {
+			key = path + "." + key
+		}
+		if err := limits.CheckElements(count); err != nil {
+			return limitError(err, start, key)
+		}
+
+		value := elem.Value()
+		valueOffset := start + int64(len(elem)-len(value.Data))
+		switch value.Type {
+		case bsontype.EmbeddedDocument, bsontype.Array:
+			if err := checkDocument(value.Data, valueOffset, depth+1, key, limits); err != nil {
+				return err
+			}
+		case bsontype.String, bsontype.Symbol, bsontype.JavaScript:
+			if err := limits.CheckStringLength(int64(len(value.Data) - 5)); err != nil {
+				return limitError(err, valueOffset, key)
+			}
+		case bsontype.Binary:
+			if _, data, ok := value.BinaryOK(); ok {
+				if err := limits.CheckStringLength(int64(len(data))); err != nil {
+					return limitError(err, valueOffset, key)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// limitError wraps err, a codec.LimitError, in a codec.DecodeError
+func limitError(err error, offset int64, path string) error {
+	return codec.DecodeError{Codec: codec.BSON, Kind: codec.ErrLimitExceeded, Offset: offset, Path: path, Err: err}
+}
@@ -0,0 +1,94 @@
+//go:build codec_bson
+
+package bson
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+func TestLimits_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits codec.Limits
+		value  any
+		limit  string
+		path   string
+	}{
+		{"bytes", codec.Limits{MaxBytes: 8}, bson.M{"name": "John Doe"}, "MaxBytes", ""},
+		{"depth", codec.Limits{MaxDepth: 2}, bson.D{{Key: "a", Value: bson.A{bson.A{1}}}}, "MaxDepth", "a.0"},
+		{"elements", codec.Limits{MaxElements: 3}, bson.M{"list": bson.A{1, 2, 3, 4}}, "MaxElements", "list.3"},
+		{"fields", codec.Limits{MaxElements: 1}, bson.D{{Key: "a", Value: 1}, {Key: "b", Value: 2}}, "MaxElements", "b"},
+		{"string", codec.Limits{MaxStringLength: 4}, bson.M{"tags": bson.A{"ok", "Johnny"}}, "MaxStringLength", "tags.1"},
+		{"binary", codec.Limits{MaxStringLength: 4}, bson.M{"data": []byte("binary")}, "MaxStringLength", "data"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := bson.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+
+			var v bson.M
+			err = New[bson.M](codec.WithLimits(tt.limits)).Unmarshal(data, &v)
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			var le codec.LimitError
+			if de.Kind != codec.ErrLimitExceeded || !errors.As(err, &le) || le.Limit != tt.limit {
+				t.Fatalf("expected %s exceeded, got %v", tt.limit, err)
+			}
+			if de.Path != tt.path {
+				t.Errorf("expected path %q, got %q", tt.path, de.Path)
+			}
+		})
+	}
+}
+
+func TestLimits_WithinLimits(t *testing.T) {
+	limits := codec.Limits{MaxBytes: 256, MaxDepth: 2, MaxElements: 3, MaxStringLength: 16}
+	data, err := bson.Marshal(TestStruct{Name: "John Doe", Age: 30, Email: "john@example.com"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var v TestStruct
+	if err := New[TestStruct](codec.WithLimits(limits), WithIntMinSize()).Unmarshal(data, &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if v.Email != "john@example.com" {
+		t.Errorf("unexpected value %+v", v)
+	}
+}
+
+func TestLimits_Decoder(t *testing.T) {
+	c := New[TestStruct](codec.WithLimits(codec.Limits{MaxBytes: 64}))
+	var buf bytes.Buffer
+	enc := c.NewEncoder(&buf)
+	for _, name := range []string{"John", "Jane", strings.Repeat("x", 64)} {
+		if err := enc.Encode(TestStruct{Name: name}); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+	}
+
+	dec := c.NewDecoder(&buf)
+	var v TestStruct
+	for i := 0; i < 2; i++ {
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("Decode %d failed: %v", i, err)
+		}
+	}
+	err := dec.Decode(&v)
+	var le codec.LimitError
+	if !errors.Is(err, codec.ErrLimitExceeded) || !errors.As(err, &le) || le.Limit != "MaxBytes" {
+		t.Fatalf("expected MaxBytes exceeded, got %v", err)
+	}
+}
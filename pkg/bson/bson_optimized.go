@@ -0,0 +1,47 @@
+//go:build codec_bson
+
+package bson
+
+import (
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// OptimizedCodec implements zero-allocation BSON encoding/decoding
+type OptimizedCodec[T any] struct {
+	*Codec[T]
+}
+
+// NewPool creates a new optimized BSON codec, configured with the given
+// options
+func NewPool[T any](opts ...codec.Option) *OptimizedCodec[T] {
+	return &OptimizedCodec[T]{
+		Codec: New[T](opts...),
+	}
+}
+
+// MarshalTo marshals data into the provided buffer, reusing its capacity
+// and growing it only if the encoding does not fit
+func (c *OptimizedCodec[T]) MarshalTo(buf []byte, data T) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	out, err := c.appendMarshal(buf[:0], data)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AppendMarshal appends marshaled data to the provided buffer
+func (c *OptimizedCodec[T]) AppendMarshal(buf []byte, data T) ([]byte, error) {
+	if c.err != nil {
+		return buf, c.err
+	}
+	return c.appendMarshal(buf, data)
+}
+
+// UnmarshalFrom unmarshals data into v. Documents are decoded straight from
+// data, so scratch is not used.
+func (c *OptimizedCodec[T]) UnmarshalFrom(data []byte, v *T, scratch []byte) error {
+	return c.Unmarshal(data, v)
+}
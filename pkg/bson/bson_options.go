@@ -0,0 +1,89 @@
+//go:build codec_bson
+
+package bson
+
+import (
+	codec "github.com/jeremyhahn/go-codec"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+)
+
+// config holds the settings applied by BSON options
+type config struct {
+	codec.Config
+
+	registry               *bsoncodec.Registry
+	jsonStructTags         bool
+	nilSliceAsEmpty        bool
+	nilMapAsEmpty          bool
+	omitZeroStruct         bool
+	intMinSize             bool
+	defaultDocumentM       bool
+	allowTruncatingDoubles bool
+}
+
+// driverDefaults reports whether c leaves every driver setting at its
+// default, so the driver's package-level functions can be used
+func (c config) driverDefaults() bool {
+	return c == config{Config: c.Config}
+}
+
+// WithRegistry encodes and decodes using registry instead of the driver's
+// default registry, enabling custom type encoders and decoders
+func WithRegistry(registry *bsoncodec.Registry) codec.Option {
+	return codec.NewOption("bson.WithRegistry", func(c *config) {
+		c.registry = registry
+	})
+}
+
+// WithJSONStructTags reads field names from the json tag when the bson tag
+// is absent
+func WithJSONStructTags() codec.Option {
+	return codec.NewOption("bson.WithJSONStructTags", func(c *config) {
+		c.jsonStructTags = true
+	})
+}
+
+// WithNilSliceAsEmpty encodes nil slices as empty BSON arrays instead of null
+func WithNilSliceAsEmpty() codec.Option {
+	return codec.NewOption("bson.WithNilSliceAsEmpty", func(c *config) {
+		c.nilSliceAsEmpty = true
+	})
+}
+
+// WithNilMapAsEmpty encodes nil maps as empty BSON documents instead of null
+func WithNilMapAsEmpty() codec.Option {
+	return codec.NewOption("bson.WithNilMapAsEmpty", func(c *config) {
+		c.nilMapAsEmpty = true
+	})
+}
+
+// WithOmitZeroStruct treats zero-valued structs as empty for omitempty fields
+func WithOmitZeroStruct() codec.Option {
+	return codec.NewOption("bson.WithOmitZeroStruct", func(c *config) {
+		c.omitZeroStruct = true
+	})
+}
+
+// WithIntMinSize encodes Go int and int64 values as BSON int32 when they fit
+func WithIntMinSize() codec.Option {
+	return codec.NewOption("bson.WithIntMinSize", func(c *config) {
+		c.intMinSize = true
+	})
+}
+
+// WithDefaultDocumentM decodes embedded documents into interface{} values as
+// bson.M instead of bson.D
+func WithDefaultDocumentM() codec.Option {
+	return codec.NewOption("bson.WithDefaultDocumentM", func(c *config) {
+		c.defaultDocumentM = true
+	})
+}
+
+// WithAllowTruncatingDoubles allows BSON doubles with a fractional part to
+// be truncated when decoding into integer fields. codec.WithStrict takes
+// precedence.
+func WithAllowTruncatingDoubles() codec.Option {
+	return codec.NewOption("bson.WithAllowTruncatingDoubles", func(c *config) {
+		c.allowTruncatingDoubles = true
+	})
+}
@@ -0,0 +1,180 @@
+//go:build codec_bson
+
+package bson
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsonrw"
+)
+
+type jsonTagged struct {
+	Name string `json:"full_name"`
+}
+
+type withCollections struct {
+	Tags  []string          `bson:"tags"`
+	Attrs map[string]string `bson:"attrs"`
+}
+
+type withInner struct {
+	Inner TestStruct `bson:"inner,omitempty"`
+}
+
+type withDouble struct {
+	Value int `bson:"value"`
+}
+
+type temperature float64
+
+func TestWithJSONStructTags(t *testing.T) {
+	c := New[jsonTagged](WithJSONStructTags())
+	data := jsonTagged{Name: "John"}
+
+	result, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if _, err := bson.Raw(result).LookupErr("full_name"); err != nil {
+		t.Errorf("expected json tag name in document: %v", err)
+	}
+
+	var decoded jsonTagged
+	if err := c.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded != data {
+		t.Errorf("expected %+v, got %+v", data, decoded)
+	}
+}
+
+func TestWithNilCollectionsAsEmpty(t *testing.T) {
+	c := New[withCollections](WithNilSliceAsEmpty(), WithNilMapAsEmpty())
+
+	result, err := c.Marshal(withCollections{})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	raw := bson.Raw(result)
+	if raw.Lookup("tags").Type != bson.TypeArray {
+		t.Errorf("expected nil slice encoded as array, got %v", raw.Lookup("tags").Type)
+	}
+	if raw.Lookup("attrs").Type != bson.TypeEmbeddedDocument {
+		t.Errorf("expected nil map encoded as document, got %v", raw.Lookup("attrs").Type)
+	}
+}
+
+func TestWithOmitZeroStruct(t *testing.T) {
+	result, err := New[withInner](WithOmitZeroStruct()).Marshal(withInner{})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if _, err := bson.Raw(result).LookupErr("inner"); err == nil {
+		t.Error("expected zero struct to be omitted")
+	}
+}
+
+func TestWithIntMinSize(t *testing.T) {
+	result, err := New[TestStruct](WithIntMinSize()).Marshal(TestStruct{Age: 30})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if bson.Raw(result).Lookup("age").Type != bson.TypeInt32 {
+		t.Errorf("expected int32, got %v", bson.Raw(result).Lookup("age").Type)
+	}
+}
+
+func TestWithDefaultDocumentM(t *testing.T) {
+	doc, err := bson.Marshal(bson.D{{Key: "inner", Value: bson.D{{Key: "a", Value: 1}}}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var result map[string]any
+	if err := New[map[string]any](WithDefaultDocumentM()).Unmarshal(doc, &result); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if _, ok := result["inner"].(bson.M); !ok {
+		t.Errorf("expected bson.M, got %T", result["inner"])
+	}
+}
+
+func TestWithAllowTruncatingDoubles(t *testing.T) {
+	doc, err := bson.Marshal(bson.D{{Key: "value", Value: 2.5}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var result withDouble
+	if err := New[withDouble]().Unmarshal(doc, &result); err == nil {
+		t.Fatal("expected error truncating double by default, got nil")
+	}
+	if err := New[withDouble](WithAllowTruncatingDoubles()).Unmarshal(doc, &result); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if result.Value != 2 {
+		t.Errorf("expected 2, got %d", result.Value)
+	}
+}
+
+func TestWithRegistry(t *testing.T) {
+	registry := bson.NewRegistry()
+	registry.RegisterTypeEncoder(reflect.TypeOf(temperature(0)), bsoncodec.ValueEncoderFunc(
+		func(ec bsoncodec.EncodeContext, vw bsonrw.ValueWriter, val reflect.Value) error {
+			return vw.WriteString("warm")
+		}))
+
+	c := New[map[string]temperature](WithRegistry(registry))
+	result, err := c.Marshal(map[string]temperature{"t": 21.5})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if got := bson.Raw(result).Lookup("t").StringValue(); got != "warm" {
+		t.Errorf("expected custom encoder output, got %q", got)
+	}
+
+	var decoded map[string]string
+	if err := New[map[string]string](WithRegistry(registry)).Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded["t"] != "warm" {
+		t.Errorf("expected warm, got %q", decoded["t"])
+	}
+}
+
+func TestNew_OptionNotSupported(t *testing.T) {
+	c := New[TestStruct](jsoncodec.WithUseNumber())
+
+	var notSupported codec.ErrOptionNotSupported
+	if !errors.As(c.Err(), &notSupported) {
+		t.Fatalf("expected ErrOptionNotSupported, got %v", c.Err())
+	}
+
+	doc, _ := New[TestStruct]().Marshal(TestStruct{})
+	var result TestStruct
+	if _, err := c.Marshal(TestStruct{}); err == nil {
+		t.Error("Marshal: expected option error, got nil")
+	}
+	if err := c.Unmarshal(doc, &result); err == nil {
+		t.Error("Unmarshal: expected option error, got nil")
+	}
+	if err := c.Encode(&bytes.Buffer{}, TestStruct{}); err == nil {
+		t.Error("Encode: expected option error, got nil")
+	}
+	if err := c.Decode(bytes.NewReader(doc), &result); err == nil {
+		t.Error("Decode: expected option error, got nil")
+	}
+	if err := c.NewEncoder(&bytes.Buffer{}).Encode(TestStruct{}); err == nil {
+		t.Error("Encoder.Encode: expected option error, got nil")
+	}
+	if err := c.NewDecoder(bytes.NewReader(doc)).Decode(&result); err == nil {
+		t.Error("Decoder.Decode: expected option error, got nil")
+	}
+}
@@ -0,0 +1,79 @@
+//go:build codec_bson
+
+package bson
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// minDocumentSize is the size of an empty BSON document: a 4-byte length
+// header followed by the terminating null byte
+const minDocumentSize = 5
+
+// Encoder writes a sequence of BSON documents to a stream
+type Encoder[T any] struct {
+	codec *Codec[T]
+	w     io.Writer
+}
+
+// Decoder reads a sequence of BSON documents from a stream. Each document is
+// read exactly using its length header, so no bytes beyond the current
+// document are consumed from the underlying reader.
+type Decoder[T any] struct {
+	codec *Codec[T]
+	r     io.Reader
+}
+
+// NewEncoder returns an encoder session that writes successive BSON documents to w
+func (c *Codec[T]) NewEncoder(w io.Writer) codec.Encoder[T] {
+	return &Encoder[T]{codec: c, w: w}
+}
+
+// NewDecoder returns a decoder session that reads successive BSON documents from r
+func (c *Codec[T]) NewDecoder(r io.Reader) codec.Decoder[T] {
+	return &Decoder[T]{codec: c, r: r}
+}
+
+// Encode writes the next BSON document to the stream
+func (e *Encoder[T]) Encode(data T) error {
+	return e.codec.Encode(e.w, data)
+}
+
+// Decode reads the next BSON document from the stream
+func (d *Decoder[T]) Decode(data *T) error {
+	return d.codec.Decode(d.r, data)
+}
+
+// readDocument reads a single length-prefixed BSON document from r. It
+// returns io.EOF if r is exhausted before the first byte of the header.
+// Documents larger than maxBytes, if positive, are rejected before they are
+// read.
+func readDocument(r io.Reader, maxBytes int64) ([]byte, error) {
+	var header [4]byte
+	if _, err := io.ReadFull(r, header[:]); err != nil {
+		return nil, err
+	}
+
+	size := int32(binary.LittleEndian.Uint32(header[:]))
+	if size < minDocumentSize {
+		return nil, fmt.Errorf("%w %d", errInvalidLength, size)
+	}
+	if maxBytes > 0 && int64(size) > maxBytes {
+		return nil, limitError(codec.LimitError{Limit: "MaxBytes", Max: maxBytes}, maxBytes, "")
+	}
+
+	doc := make([]byte, size)
+	copy(doc, header[:])
+	if _, err := io.ReadFull(r, doc[len(header):]); err != nil {
+		if errors.Is(err, io.EOF) {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return doc, nil
+}
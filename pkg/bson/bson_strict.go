@@ -0,0 +1,173 @@
+//go:build codec_bson
+
+package bson
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"go.mongodb.org/mongo-driver/bson"
+	"go.mongodb.org/mongo-driver/bson/bsoncodec"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+var (
+	unmarshalerType      = reflect.TypeOf((*bson.Unmarshaler)(nil)).Elem()
+	valueUnmarshalerType = reflect.TypeOf((*bson.ValueUnmarshaler)(nil)).Elem()
+)
+
+// checkStrict rejects duplicate keys anywhere in data, and keys that match
+// no field of the struct they would be decoded into
+func (c *Codec[T]) checkStrict(data []byte) error {
+	parser := bsoncodec.DefaultStructTagParser
+	if c.cfg.jsonStructTags {
+		parser = bsoncodec.JSONFallbackStructTagParser
+	}
+	s := strictChecker{parser: parser, fields: make(map[reflect.Type]structFields)}
+	return s.document(data, 0, "", reflect.TypeFor[T](), false)
+}
+
+// structFields describes the keys a struct accepts when decoding
+type structFields struct {
+	// byKey maps each key to the type of its field
+	byKey map[string]reflect.Type
+
+	// inlineMap is set when an inline map accepts every other key
+	inlineMap bool
+}
+
+// strictChecker walks a document alongside the Go type it decodes into
+type strictChecker struct {
+	parser bsoncodec.StructTagParser
+	fields map[reflect.Type]structFields
+}
+
+// document checks doc, an embedded document or array found at offset in the
+// input that decodes into t. A nil t accepts any key. Malformed documents
+// are left for the decoder to report.
+func (s *strictChecker) document(doc []byte, offset int64, path string, t reflect.Type, array bool) error {
+	for t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t != nil && (reflect.PointerTo(t).Implements(unmarshalerType) || reflect.PointerTo(t).Implements(valueUnmarshalerType)) {
+		t = nil
+	}
+
+	var fields *structFields
+	if t != nil && t.Kind() == reflect.Struct && !array {
+		f, err := s.structFields(t)
+		if err != nil {
+			return nil
+		}
+		fields = &f
+	}
+
+	length, rem, ok := bsoncore.ReadLength(doc)
+	if !ok || int(length) > len(doc) {
+		return nil
+	}
+	rem = rem[:length-4]
+
+	seen := make(map[string]bool)
+	for len(rem) > 1 {
+		start := offset + int64(len(doc)-len(rem))
+		elem, next, ok := bsoncore.ReadElement(rem)
+		if !ok {
+			return nil
+		}
+		rem = next
+
+		name := elem.Key()
+		key := name
+		if path != "" {
+			key = path + "." + name
+		}
+		if seen[name] {
+			return strictError(codec.ErrDuplicateKey, start, key, fmt.Errorf("duplicate key %q", name))
+		}
+		seen[name] = true
+
+		var child reflect.Type
+		switch {
+		case fields != nil:
+			ft, ok := fields.byKey[name]
+			if !ok {
+				ft, ok = fields.byKey[strings.ToLower(name)]
+			}
+			if !ok && !fields.inlineMap {
+				return strictError(codec.ErrUnknownField, start, key, fmt.Errorf("unknown field %q", name))
+			}
+			child = ft
+		case t == nil:
+		case t.Kind() == reflect.Map && !array:
+			child = t.Elem()
+		case (t.Kind() == reflect.Slice || t.Kind() == reflect.Array) && array:
+			child = t.Elem()
+		}
+
+		value := elem.Value()
+		valueOffset := start + int64(len(elem)-len(value.Data))
+		switch value.Type {
+		case bsontype.EmbeddedDocument, bsontype.Array:
+			if err := s.document(value.Data, valueOffset, key, child, value.Type == bsontype.Array); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// structFields returns the keys t accepts, parsing its tags as the driver's
+// struct codec does, including inline structs and maps
+func (s *strictChecker) structFields(t reflect.Type) (structFields, error) {
+	if f, ok := s.fields[t]; ok {
+		return f, nil
+	}
+	f := structFields{byKey: make(map[string]reflect.Type)}
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if !sf.IsExported() {
+			continue
+		}
+		tags, err := s.parser.ParseStructTags(sf)
+		if err != nil {
+			return structFields{}, err
+		}
+		if tags.Skip {
+			continue
+		}
+		if !tags.Inline {
+			f.byKey[tags.Name] = sf.Type
+			continue
+		}
+
+		ft := sf.Type
+		for ft.Kind() == reflect.Pointer {
+			ft = ft.Elem()
+		}
+		switch ft.Kind() {
+		case reflect.Map:
+			f.inlineMap = true
+		case reflect.Struct:
+			inline, err := s.structFields(ft)
+			if err != nil {
+				return structFields{}, err
+			}
+			for name, typ := range inline.byKey {
+				if _, ok := f.byKey[name]; !ok {
+					f.byKey[name] = typ
+				}
+			}
+		}
+	}
+	s.fields[t] = f
+	return f, nil
+}
+
+// strictError reports a strict decoding violation at offset and path
+func strictError(kind codec.ErrorKind, offset int64, path string, err error) error {
+	return codec.DecodeError{Codec: codec.BSON, Kind: kind, Offset: offset, Path: path, Err: err}
+}
@@ -0,0 +1,89 @@
+//go:build codec_bson
+
+package bson
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	"go.mongodb.org/mongo-driver/bson"
+)
+
+type strictRecord struct {
+	Name  string         `bson:"name"`
+	Port  int8           `bson:"port"`
+	Retry int            `bson:"retry"`
+	Tags  []strictTag    `bson:"tags"`
+	Extra map[string]any `bson:"extra"`
+}
+
+type strictTag struct {
+	Key string `bson:"key"`
+}
+
+func TestStrict_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		value bson.D
+		kind  codec.ErrorKind
+		path  string
+	}{
+		{"unknown field", bson.D{{Key: "name", Value: "api"}, {Key: "host", Value: "localhost"}}, codec.ErrUnknownField, "host"},
+		{"nested unknown field", bson.D{{Key: "tags", Value: bson.A{bson.D{{Key: "key", Value: "a"}}, bson.D{{Key: "value", Value: "b"}}}}}, codec.ErrUnknownField, "tags.1.value"},
+		{"duplicate key", bson.D{{Key: "name", Value: "api"}, {Key: "name", Value: "web"}}, codec.ErrDuplicateKey, "name"},
+		{"nested duplicate key", bson.D{{Key: "extra", Value: bson.D{{Key: "a", Value: 1}, {Key: "a", Value: 2}}}}, codec.ErrDuplicateKey, "extra.a"},
+		{"overflow", bson.D{{Key: "port", Value: 300}}, codec.ErrTypeMismatch, "port"},
+		{"truncated double", bson.D{{Key: "retry", Value: 1.5}}, codec.ErrTypeMismatch, "retry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := bson.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+
+			var v strictRecord
+			err = New[strictRecord](codec.WithStrict(), WithAllowTruncatingDoubles()).Unmarshal(data, &v)
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			if de.Kind != tt.kind || de.Path != tt.path {
+				t.Errorf("expected %s at %q, got %+v", tt.kind, tt.path, de)
+			}
+		})
+	}
+}
+
+func TestStrict_MapAcceptsAnyKey(t *testing.T) {
+	data, err := bson.Marshal(bson.D{{Key: "extra", Value: bson.D{{Key: "anything", Value: 1}}}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var v strictRecord
+	if err := New[strictRecord](codec.WithStrict()).Unmarshal(data, &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if v.Extra["anything"] != int32(1) {
+		t.Errorf("unexpected value: %+v", v.Extra)
+	}
+}
+
+func TestStrict_Lenient(t *testing.T) {
+	data, err := bson.Marshal(bson.D{{Key: "name", Value: "api"}, {Key: "host", Value: "localhost"}, {Key: "retry", Value: 1.5}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var v strictRecord
+	if err := New[strictRecord](WithAllowTruncatingDoubles()).Unmarshal(data, &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if v.Name != "api" || v.Retry != 1 {
+		t.Errorf("unexpected value: %+v", v)
+	}
+}
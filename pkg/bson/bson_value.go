@@ -0,0 +1,436 @@
+//go:build codec_bson
+
+package bson
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"go.mongodb.org/mongo-driver/bson/bsontype"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+// Binary subtypes holding plain bytes
+const (
+	binaryGeneric = 0x00
+	binaryOld     = 0x02
+)
+
+// Names of the BSON types decoded as tagged Values
+var tagNames = map[bsontype.Type]string{
+	bsontype.Binary:        "Binary",
+	bsontype.Undefined:     "Undefined",
+	bsontype.ObjectID:      "ObjectID",
+	bsontype.Regex:         "Regex",
+	bsontype.DBPointer:     "DBPointer",
+	bsontype.JavaScript:    "JavaScript",
+	bsontype.Symbol:        "Symbol",
+	bsontype.CodeWithScope: "CodeWithScope",
+	bsontype.Timestamp:     "Timestamp",
+	bsontype.Decimal128:    "Decimal128",
+	bsontype.MinKey:        "MinKey",
+	bsontype.MaxKey:        "MaxKey",
+}
+
+// valueCodec converts between BSON and codec.Value
+type valueCodec struct{}
+
+// DecodeValue reads the next BSON document from r. Types without a
+// counterpart in codec.Value decode as Values tagged with the name of the
+// type, holding:
+//
+//   - ObjectID: its hex text
+//   - Decimal128: its text
+//   - Timestamp: a map of "t" and "i"
+//   - Regex: a map of "pattern" and "options"
+//   - JavaScript and Symbol: their text
+//   - CodeWithScope: a map of "code" and "scope"
+//   - DBPointer: a map of "ref" and the hex text of "id"
+//   - Binary of a subtype other than generic: a map of "subtype" and "data"
+//   - Undefined, MinKey and MaxKey: null
+func (valueCodec) DecodeValue(r io.Reader, cfg codec.Config, lossy func(codec.Loss)) (codec.Value, error) {
+	data, err := readDocument(r, cfg.Limits.MaxBytes)
+	if err != nil {
+		return codec.Value{}, readError(err)
+	}
+	c := &Codec[codec.Value]{cfg: config{Config: cfg}}
+	return c.convertValue(data, lossy)
+}
+
+// convertValue checks data, a single BSON document, against the codec's
+// limits and converts it
+func (c *Codec[T]) convertValue(data []byte, lossy func(codec.Loss)) (codec.Value, error) {
+	if err := c.checkLimits(data); err != nil {
+		return codec.Value{}, err
+	}
+	if err := bsoncore.Document(data).Validate(); err != nil {
+		return codec.Value{}, decodeError(err, data)
+	}
+	d := valueDecoder{strict: c.cfg.Strict, lossy: lossy}
+	return d.document(data, 0, "", false)
+}
+
+// unmarshalValue decodes data into v for a Codec[codec.Value], failing on
+// lossy conversions if the codec is strict
+func (c *Codec[T]) unmarshalValue(data []byte, v *codec.Value) error {
+	lossy, check := codec.CollectLosses(codec.BSON, c.cfg.Strict)
+	value, err := c.convertValue(data, lossy)
+	if err == nil {
+		err = check()
+	}
+	if err != nil {
+		return err
+	}
+	*v = value
+	return nil
+}
+
+// marshalValue returns the encoding of v for a Codec[codec.Value], failing
+// on lossy conversions if the codec is strict
+func (c *Codec[T]) marshalValue(v codec.Value) ([]byte, error) {
+	lossy, check := codec.CollectLosses(codec.BSON, c.cfg.Strict)
+	var buf bytes.Buffer
+	err := valueCodec{}.EncodeValue(&buf, v, lossy)
+	if err == nil {
+		err = check()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// valueDecoder builds a codec.Value from a validated BSON document
+type valueDecoder struct {
+	strict bool
+	lossy  func(codec.Loss)
+}
+
+// document decodes doc, found at offset in the input, as a map or, if
+// array is set, as an array
+func (d valueDecoder) document(doc []byte, offset int64, path string, array bool) (codec.Value, error) {
+	var items []codec.Value
+	var members []codec.Member
+	index := make(map[string]int)
+
+	_, rem, _ := bsoncore.ReadLength(doc)
+	for len(rem) > 1 {
+		start := offset + int64(len(doc)-len(rem))
+		elem, next, _ := bsoncore.ReadElement(rem)
+		rem = next
+
+		key := elem.Key()
+		elemPath := joinPath(path, key)
+		if array {
+			elemPath = path + "[" + strconv.Itoa(len(items)) + "]"
+		}
+		value := elem.Value()
+		v, err := d.value(value, start+int64(len(elem)-len(value.Data)), elemPath)
+		if err != nil {
+			return codec.Value{}, err
+		}
+		if array {
+			items = append(items, v)
+			continue
+		}
+		if i, ok := index[key]; ok {
+			if d.strict {
+				return codec.Value{}, strictError(codec.ErrDuplicateKey, start, elemPath, fmt.Errorf("bson: duplicate key %q", key))
+			}
+			members[i].Value = v
+			continue
+		}
+		index[key] = len(members)
+		members = append(members, codec.Member{Key: codec.StringValue(key), Value: v})
+	}
+	if array {
+		return codec.ArrayValue(items...), nil
+	}
+	return codec.MapValue(members...), nil
+}
+
+// value decodes the element value v found at offset
+func (d valueDecoder) value(v bsoncore.Value, offset int64, path string) (codec.Value, error) {
+	switch v.Type {
+	case bsontype.Double:
+		return codec.FloatValue(v.Double()), nil
+	case bsontype.String:
+		return codec.StringValue(v.StringValue()), nil
+	case bsontype.EmbeddedDocument:
+		return d.document(v.Data, offset, path, false)
+	case bsontype.Array:
+		return d.document(v.Data, offset, path, true)
+	case bsontype.Binary:
+		subtype, data := v.Binary()
+		if subtype == binaryGeneric || subtype == binaryOld {
+			return codec.BytesValue(data), nil
+		}
+		return tagValue(v.Type, codec.MapValue(
+			field("subtype", codec.IntValue(int64(subtype))),
+			field("data", codec.BytesValue(data)),
+		)), nil
+	case bsontype.Boolean:
+		return codec.BoolValue(v.Boolean()), nil
+	case bsontype.DateTime:
+		return codec.TimeValue(time.UnixMilli(v.DateTime()).UTC()), nil
+	case bsontype.Null:
+		return codec.NullValue(), nil
+	case bsontype.Int32:
+		return codec.IntValue(int64(v.Int32())), nil
+	case bsontype.Int64:
+		return codec.IntValue(v.Int64()), nil
+	case bsontype.ObjectID:
+		return tagValue(v.Type, codec.StringValue(v.ObjectID().Hex())), nil
+	case bsontype.Decimal128:
+		return tagValue(v.Type, codec.StringValue(v.Decimal128().String())), nil
+	case bsontype.Timestamp:
+		t, i := v.Timestamp()
+		return tagValue(v.Type, codec.MapValue(
+			field("t", codec.IntValue(int64(t))),
+			field("i", codec.IntValue(int64(i))),
+		)), nil
+	case bsontype.Regex:
+		pattern, options := v.Regex()
+		return tagValue(v.Type, codec.MapValue(
+			field("pattern", codec.StringValue(pattern)),
+			field("options", codec.StringValue(options)),
+		)), nil
+	case bsontype.JavaScript, bsontype.Symbol:
+		s, _, _ := bsoncore.ReadString(v.Data)
+		return tagValue(v.Type, codec.StringValue(s)), nil
+	case bsontype.CodeWithScope:
+		code, scope := v.CodeWithScope()
+		scopeValue, err := d.document(scope, offset+int64(len(v.Data)-len(scope)), joinPath(path, "scope"), false)
+		if err != nil {
+			return codec.Value{}, err
+		}
+		return tagValue(v.Type, codec.MapValue(
+			field("code", codec.StringValue(code)),
+			field("scope", scopeValue),
+		)), nil
+	case bsontype.DBPointer:
+		ref, id := v.DBPointer()
+		return tagValue(v.Type, codec.MapValue(
+			field("ref", codec.StringValue(ref)),
+			field("id", codec.StringValue(id.Hex())),
+		)), nil
+	default:
+		// Undefined, MinKey and MaxKey
+		return tagValue(v.Type, codec.NullValue()), nil
+	}
+}
+
+// tagValue returns content tagged with the BSON type t
+func tagValue(t bsontype.Type, content codec.Value) codec.Value {
+	return codec.TaggedValue(codec.Tag{Codec: codec.BSON, Number: int64(t), Name: tagNames[t]}, content)
+}
+
+// field returns the member of a map of a tagged Value with the key name
+func field(name string, v codec.Value) codec.Member {
+	return codec.Member{Key: codec.StringValue(name), Value: v}
+}
+
+// EncodeValue writes v, which must be a map, to w as a BSON document.
+// Integers are written as int32 where they fit and as int64 otherwise;
+// unsigned integers beyond the range of an int64 are written as doubles,
+// keys that are not strings as their text and times with more than
+// millisecond precision truncated and outside UTC converted to it, and
+// tags of other codecs dropped in favor of their content, each reported to
+// lossy.
+func (valueCodec) EncodeValue(w io.Writer, v codec.Value, lossy func(codec.Loss)) error {
+	if v.Kind() != codec.KindMap {
+		return fmt.Errorf("bson: cannot encode %s value as a document", v.Kind())
+	}
+	e := valueEncoder{lossy: lossy}
+	doc, err := e.document(nil, v, "")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(doc)
+	return err
+}
+
+// valueEncoder appends the BSON encoding of a codec.Value to a buffer
+type valueEncoder struct {
+	lossy func(codec.Loss)
+}
+
+// errKeyNull is returned for keys holding a null byte, which BSON cannot
+// represent
+var errKeyNull = errors.New("bson: key contains a null byte")
+
+// document appends v, a map or array, as a document
+func (e valueEncoder) document(dst []byte, v codec.Value, path string) ([]byte, error) {
+	idx, dst := bsoncore.AppendDocumentStart(dst)
+	var err error
+	if v.Kind() == codec.KindArray {
+		for i, item := range v.Array() {
+			key := strconv.Itoa(i)
+			if dst, err = e.element(dst, key, item, path+"["+key+"]"); err != nil {
+				return nil, err
+			}
+		}
+	} else {
+		for _, m := range v.Members() {
+			key := m.Key.String()
+			if m.Key.Kind() != codec.KindString {
+				e.lossy(codec.Loss{Path: joinPath(path, key), Reason: m.Key.Kind().String() + " key written as text"})
+			}
+			if strings.IndexByte(key, 0) >= 0 {
+				return nil, errKeyNull
+			}
+			if dst, err = e.element(dst, key, m.Value, joinPath(path, key)); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return bsoncore.AppendDocumentEnd(dst, idx)
+}
+
+// element appends v as the element named key
+func (e valueEncoder) element(dst []byte, key string, v codec.Value, path string) ([]byte, error) {
+	switch v.Kind() {
+	case codec.KindBool:
+		return bsoncore.AppendBooleanElement(dst, key, v.Bool()), nil
+	case codec.KindInt:
+		if i := v.Int(); i >= math.MinInt32 && i <= math.MaxInt32 {
+			return bsoncore.AppendInt32Element(dst, key, int32(i)), nil
+		}
+		return bsoncore.AppendInt64Element(dst, key, v.Int()), nil
+	case codec.KindUint:
+		if u := v.Uint(); u <= math.MaxInt64 {
+			return bsoncore.AppendInt64Element(dst, key, int64(u)), nil
+		}
+		e.lossy(codec.Loss{Path: path, Reason: "integer beyond int64 written as double"})
+		return bsoncore.AppendDoubleElement(dst, key, float64(v.Uint())), nil
+	case codec.KindFloat:
+		return bsoncore.AppendDoubleElement(dst, key, v.Float()), nil
+	case codec.KindString:
+		return bsoncore.AppendStringElement(dst, key, v.String()), nil
+	case codec.KindBytes:
+		return bsoncore.AppendBinaryElement(dst, key, binaryGeneric, v.Bytes()), nil
+	case codec.KindTime:
+		t := v.Time()
+		if t.Nanosecond()%int(time.Millisecond) != 0 {
+			e.lossy(codec.Loss{Path: path, Reason: "time truncated to milliseconds"})
+		}
+		if t.Location() != time.UTC {
+			e.lossy(codec.Loss{Path: path, Reason: "time zone dropped"})
+		}
+		return bsoncore.AppendDateTimeElement(dst, key, t.UnixMilli()), nil
+	case codec.KindArray:
+		dst = bsoncore.AppendHeader(dst, bsontype.Array, key)
+		return e.document(dst, v, path)
+	case codec.KindMap:
+		dst = bsoncore.AppendHeader(dst, bsontype.EmbeddedDocument, key)
+		return e.document(dst, v, path)
+	case codec.KindTagged:
+		return e.tagged(dst, key, v, path)
+	default:
+		return bsoncore.AppendNullElement(dst, key), nil
+	}
+}
+
+// tagged appends a tagged Value as the element named key
+func (e valueEncoder) tagged(dst []byte, key string, v codec.Value, path string) ([]byte, error) {
+	tag, content := v.Tag(), v.Content()
+	if tag.Codec != codec.BSON {
+		e.lossy(codec.Loss{Path: path, Reason: "tag " + tag.String() + " dropped"})
+		return e.element(dst, key, content, path)
+	}
+
+	text := func(name string) (string, bool) {
+		f, ok := content.Lookup(name)
+		if !ok || f.Kind() != codec.KindString {
+			return "", false
+		}
+		return f.String(), true
+	}
+	number := func(name string, max int64) (int64, bool) {
+		f, ok := content.Lookup(name)
+		if !ok || f.Kind() != codec.KindInt || f.Int() < 0 || f.Int() > max {
+			return 0, false
+		}
+		return f.Int(), true
+	}
+
+	switch t := bsontype.Type(tag.Number); t {
+	case bsontype.ObjectID:
+		if content.Kind() == codec.KindString {
+			if id, err := primitive.ObjectIDFromHex(content.String()); err == nil {
+				return bsoncore.AppendObjectIDElement(dst, key, id), nil
+			}
+		}
+	case bsontype.Decimal128:
+		if content.Kind() == codec.KindString {
+			if d, err := primitive.ParseDecimal128(content.String()); err == nil {
+				return bsoncore.AppendDecimal128Element(dst, key, d), nil
+			}
+		}
+	case bsontype.Timestamp:
+		ts, ok1 := number("t", math.MaxUint32)
+		i, ok2 := number("i", math.MaxUint32)
+		if ok1 && ok2 {
+			return bsoncore.AppendTimestampElement(dst, key, uint32(ts), uint32(i)), nil
+		}
+	case bsontype.Regex:
+		pattern, ok1 := text("pattern")
+		options, ok2 := text("options")
+		if ok1 && ok2 {
+			return bsoncore.AppendRegexElement(dst, key, pattern, options), nil
+		}
+	case bsontype.JavaScript:
+		if content.Kind() == codec.KindString {
+			return bsoncore.AppendJavaScriptElement(dst, key, content.String()), nil
+		}
+	case bsontype.Symbol:
+		if content.Kind() == codec.KindString {
+			return bsoncore.AppendSymbolElement(dst, key, content.String()), nil
+		}
+	case bsontype.CodeWithScope:
+		code, ok := text("code")
+		scope, _ := content.Lookup("scope")
+		if ok && scope.Kind() == codec.KindMap {
+			doc, err := e.document(nil, scope, joinPath(path, "scope"))
+			if err != nil {
+				return nil, err
+			}
+			return bsoncore.AppendCodeWithScopeElement(dst, key, code, doc), nil
+		}
+	case bsontype.DBPointer:
+		ref, ok1 := text("ref")
+		hex, ok2 := text("id")
+		if id, err := primitive.ObjectIDFromHex(hex); ok1 && ok2 && err == nil {
+			return bsoncore.AppendDBPointerElement(dst, key, ref, id), nil
+		}
+	case bsontype.Binary:
+		subtype, ok := number("subtype", math.MaxUint8)
+		data, _ := content.Lookup("data")
+		if ok && data.Kind() == codec.KindBytes {
+			return bsoncore.AppendBinaryElement(dst, key, byte(subtype), data.Bytes()), nil
+		}
+	case bsontype.Undefined:
+		return bsoncore.AppendUndefinedElement(dst, key), nil
+	case bsontype.MinKey:
+		return bsoncore.AppendMinKeyElement(dst, key), nil
+	case bsontype.MaxKey:
+		return bsoncore.AppendMaxKeyElement(dst, key), nil
+	}
+	return nil, fmt.Errorf("bson: invalid %s value", v)
+}
+
+// joinPath appends key to the dotted path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,116 @@
+//go:build codec_bson
+
+package bson
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-codec"
+	"go.mongodb.org/mongo-driver/bson/primitive"
+	"go.mongodb.org/mongo-driver/x/bsonx/bsoncore"
+)
+
+func TestValue_RoundTrip(t *testing.T) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, int(6*time.Millisecond), time.UTC)
+	v := codec.MapValue(
+		codec.Member{Key: codec.StringValue("z"), Value: codec.IntValue(math.MinInt64)},
+		codec.Member{Key: codec.StringValue("small"), Value: codec.IntValue(1)},
+		codec.Member{Key: codec.StringValue("f"), Value: codec.FloatValue(2)},
+		codec.Member{Key: codec.StringValue("b"), Value: codec.BytesValue([]byte{1, 2})},
+		codec.Member{Key: codec.StringValue("t"), Value: codec.TimeValue(when)},
+		codec.Member{Key: codec.StringValue("a"), Value: codec.ArrayValue(codec.NullValue(), codec.BoolValue(true))},
+	)
+
+	var buf bytes.Buffer
+	if err := codec.EncodeValue(codec.BSON, &buf, v); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	got, err := codec.DecodeValue(codec.BSON, &buf)
+	if err != nil {
+		t.Fatalf("DecodeValue failed: %v", err)
+	}
+	if got.String() != v.String() {
+		t.Errorf("Round trip = %s, want %s", got, v)
+	}
+}
+
+func TestValue_ObjectID(t *testing.T) {
+	id := primitive.NewObjectID()
+	doc := bsoncore.NewDocumentBuilder().
+		AppendObjectID("_id", id).
+		AppendTimestamp("ts", 1, 2).
+		AppendRegex("re", "^a", "i").
+		AppendBinary("uuid", 0x04, []byte{1, 2}).
+		Build()
+
+	v, err := codec.DecodeValue(codec.BSON, bytes.NewReader(doc))
+	if err != nil {
+		t.Fatalf("DecodeValue failed: %v", err)
+	}
+	want := "{_id:bson:ObjectID(" + id.Hex() + ") ts:bson:Timestamp({t:1 i:2}) re:bson:Regex({pattern:^a options:i}) uuid:bson:Binary({subtype:4 data:AQI=})}"
+	if got := v.String(); got != want {
+		t.Errorf("DecodeValue = %s, want %s", got, want)
+	}
+
+	var buf bytes.Buffer
+	if err := codec.EncodeValue(codec.BSON, &buf, v); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), doc) {
+		t.Errorf("EncodeValue = %x, want %x", buf.Bytes(), doc)
+	}
+}
+
+func TestValue_InvalidTag(t *testing.T) {
+	tag := codec.Tag{Codec: codec.BSON, Number: 0x07, Name: "ObjectID"}
+	v := codec.MapValue(codec.Member{Key: codec.StringValue("_id"), Value: codec.TaggedValue(tag, codec.IntValue(1))})
+	var buf bytes.Buffer
+	if err := codec.EncodeValue(codec.BSON, &buf, v); err == nil {
+		t.Error("Expected an error encoding an ObjectID holding an int")
+	}
+}
+
+func TestCodec_Value(t *testing.T) {
+	d, _ := primitive.ParseDecimal128("1.5")
+	doc := bsoncore.NewDocumentBuilder().
+		AppendString("b", "x").
+		AppendDecimal128("a", d).
+		Build()
+
+	c := New[codec.Value]()
+	var v codec.Value
+	if err := c.Unmarshal(doc, &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if got := v.String(); got != "{b:x a:bson:Decimal128(1.5)}" {
+		t.Errorf("Unmarshal = %s", got)
+	}
+	data, err := c.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !bytes.Equal(data, doc) {
+		t.Errorf("Marshal = %x, want %x", data, doc)
+	}
+}
+
+func TestValue_EncodeLosses(t *testing.T) {
+	v := codec.MapValue(
+		codec.Member{Key: codec.StringValue("u"), Value: codec.UintValue(math.MaxUint64)},
+		codec.Member{Key: codec.StringValue("t"), Value: codec.TimeValue(time.Date(2024, 1, 2, 3, 4, 5, 1, time.UTC))},
+	)
+	var buf bytes.Buffer
+	err := codec.EncodeValue(codec.BSON, &buf, v)
+	var lossErr *codec.LossError
+	if !errors.As(err, &lossErr) || len(lossErr.Losses) != 2 {
+		t.Errorf("Expected *LossError with 2 losses, got %v", err)
+	}
+
+	if err := codec.EncodeValue(codec.BSON, &buf, codec.IntValue(1)); err == nil {
+		t.Error("Expected an error encoding an int as a document")
+	}
+}
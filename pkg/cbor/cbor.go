@@ -7,38 +7,161 @@ import (
 
 	"github.com/fxamacker/cbor/v2"
 	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/codecgen"
+	"github.com/jeremyhahn/go-codec/pkg/pool"
 )
 
 func init() {
 	codec.RegisterCodec(codec.CBOR)
+	codec.RegisterValueCodec(codec.CBOR, valueCodec{})
 }
 
 // Codec implements the codec.Codec interface for CBOR serialization
-type Codec[T any] struct{}
+type Codec[T any] struct {
+	cfg     config
+	encMode cbor.UserBufferEncMode
+	decMode cbor.DecMode
+	gen     codecgen.Cache[T]
+	err     error
+}
+
+// New creates a new CBOR codec configured with the given options. If an
+// option does not apply to CBOR or describes an invalid mode, every
+// operation returns the error, which is also reported by Err.
+func New[T any](opts ...codec.Option) *Codec[T] {
+	c := &Codec[T]{}
+	if c.err = codec.ApplyOptions(codec.CBOR, &c.cfg, opts); c.err != nil {
+		return c
+	}
+	if c.encMode, c.err = c.cfg.encOpts.UserBufferEncMode(); c.err != nil {
+		return c
+	}
+	decOpts := c.cfg.decOpts
+	if c.cfg.Strict {
+		decOpts.DupMapKey = cbor.DupMapKeyEnforcedAPF
+		decOpts.ExtraReturnErrors |= cbor.ExtraDecErrorUnknownField
+	}
+	c.decMode, c.err = decOpts.DecMode()
+	return c
+}
 
-// New creates a new CBOR codec
-func New[T any]() *Codec[T] {
-	return &Codec[T]{}
+// Err returns the error, if any, caused by the options passed to New
+func (c *Codec[T]) Err() error {
+	return c.err
 }
 
 // Encode serializes the given data to the writer using CBOR
 func (c *Codec[T]) Encode(w io.Writer, data T) error {
-	encoder := cbor.NewEncoder(w)
-	return encoder.Encode(data)
+	if c.err != nil {
+		return c.err
+	}
+	if v, ok := any(data).(codec.Value); ok {
+		raw, err := c.marshalValue(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(raw)
+		return err
+	}
+	return c.newEncoder(w).Encode(data)
 }
 
 // Decode deserializes CBOR data from the reader into the provided type
 func (c *Codec[T]) Decode(r io.Reader, data *T) error {
-	decoder := cbor.NewDecoder(r)
-	return decoder.Decode(data)
+	if c.err != nil {
+		return c.err
+	}
+	r = codec.NewLimitedReader(r, codec.CBOR, c.cfg.Limits.MaxBytes)
+	if v, ok := any(data).(*codec.Value); ok {
+		return c.decodeValue(c.newDecoder(r), v)
+	}
+	return c.decodeNext(c.newDecoder(r), data)
 }
 
 // Marshal serializes the given data to CBOR bytes
 func (c *Codec[T]) Marshal(data T) ([]byte, error) {
-	return cbor.Marshal(data)
+	if c.err != nil {
+		return nil, c.err
+	}
+	if v, ok := any(data).(codec.Value); ok {
+		return c.marshalValue(v)
+	}
+	if c.cfg.encodesDefault() {
+		if b, ok := c.appendGenerated(nil, data); ok {
+			return b, nil
+		}
+	}
+	return c.encMode.Marshal(data)
 }
 
 // Unmarshal deserializes CBOR bytes into the provided type
 func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
-	return cbor.Unmarshal(data, v)
+	if c.err != nil {
+		return c.err
+	}
+	if value, ok := any(v).(*codec.Value); ok {
+		return c.unmarshalValue(data, value)
+	}
+	if err := c.checkLimits(data); err != nil {
+		return err
+	}
+	if c.cfg.decodesDefault() && c.parseGenerated(data, v) {
+		return nil
+	}
+	err := c.decMode.Unmarshal(data, v)
+	if err == io.EOF {
+		err = io.ErrUnexpectedEOF
+	}
+	return decodeError(err, -1)
+}
+
+// appendMarshal appends the encoding of data to buf, writing directly into
+// buf while it has room
+func (c *Codec[T]) appendMarshal(buf []byte, data T) ([]byte, error) {
+	if v, ok := any(data).(codec.Value); ok {
+		raw, err := c.marshalValue(v)
+		if err != nil {
+			return buf, err
+		}
+		return append(buf, raw...), nil
+	}
+	if c.cfg.encodesDefault() {
+		if b, ok := c.appendGenerated(buf, data); ok {
+			return b, nil
+		}
+	}
+	out := pool.GetAppendBuffer(buf)
+	defer pool.PutAppendBuffer(out)
+	if err := c.encMode.MarshalToBuffer(data, out); err != nil {
+		return buf, err
+	}
+	return out.Bytes(), nil
+}
+
+// appendGenerated appends data encoded by code generated with codecgen,
+// reporting false if T has none or it left data to reflection
+func (c *Codec[T]) appendGenerated(buf []byte, data T) ([]byte, bool) {
+	f, ok := c.gen.Get(codec.CBOR)
+	if !ok {
+		return nil, false
+	}
+	out, err := f.Append(buf, data)
+	return out, err == nil
+}
+
+// parseGenerated decodes data with code generated with codecgen, reporting
+// false if T has none or it left data to reflection
+func (c *Codec[T]) parseGenerated(data []byte, v *T) bool {
+	f, ok := c.gen.Get(codec.CBOR)
+	return ok && f.Parse(data, v) == nil
+}
+
+// newEncoder returns a cbor.Encoder writing to w with the codec's settings
+func (c *Codec[T]) newEncoder(w io.Writer) *cbor.Encoder {
+	return c.encMode.NewEncoder(w)
+}
+
+// newDecoder returns a cbor.Decoder reading from r with the codec's settings
+func (c *Codec[T]) newDecoder(r io.Reader) *cbor.Decoder {
+	return c.decMode.NewDecoder(r)
 }
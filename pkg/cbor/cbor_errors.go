@@ -0,0 +1,94 @@
+//go:build codec_cbor
+
+package cbor
+
+import (
+	"errors"
+	"io"
+	"strings"
+
+	"github.com/fxamacker/cbor/v2"
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// decodeError converts an error returned by fxamacker/cbor into a
+// codec.DecodeError. offset is the position of the data item being decoded,
+// or -1 if unknown. Read errors, io.EOF at the end of a stream and misuse of
+// the API are returned as is.
+func decodeError(err error, offset int64) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+
+	de := codec.DecodeError{Codec: codec.CBOR, Offset: offset, Err: err}
+	var (
+		typeErr    *cbor.UnmarshalTypeError
+		syntaxErr  *cbor.SyntaxError
+		semantic   *cbor.SemanticError
+		indefinite *cbor.IndefiniteLengthError
+		tags       *cbor.TagsMdError
+		extraneous *cbor.ExtraneousDataError
+		dupKey     *cbor.DupMapKeyError
+		wrongTag   *cbor.WrongTagError
+		tagContent *cbor.InadmissibleTagContentTypeError
+		nested     *cbor.MaxNestedLevelError
+		elements   *cbor.MaxArrayElementsError
+		pairs      *cbor.MaxMapPairsError
+		mapKey     *cbor.InvalidMapKeyTypeError
+		dataItem   *cbor.UnacceptableDataItemError
+		byteString *cbor.ByteStringExpectedFormatError
+		unknown    *cbor.UnknownFieldError
+	)
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		de.Kind = codec.ErrTruncated
+	case errors.As(err, &typeErr):
+		de.Kind = codec.ErrTypeMismatch
+		// StructFieldName is qualified with the Go type, e.g. "pkg.User.age"
+		de.Path = typeErr.StructFieldName[strings.LastIndexByte(typeErr.StructFieldName, '.')+1:]
+	case errors.As(err, &mapKey), errors.As(err, &dataItem), errors.As(err, &byteString):
+		de.Kind = codec.ErrTypeMismatch
+	case errors.As(err, &syntaxErr), errors.As(err, &semantic), errors.As(err, &indefinite),
+		errors.As(err, &tags), errors.As(err, &extraneous),
+		errors.As(err, &wrongTag), errors.As(err, &tagContent):
+		de.Kind = codec.ErrSyntax
+	case errors.As(err, &dupKey):
+		de.Kind = codec.ErrDuplicateKey
+		if key, ok := dupKey.Key.(string); ok {
+			de.Path = key
+		}
+	case errors.As(err, &nested), errors.As(err, &elements), errors.As(err, &pairs):
+		de.Kind = codec.ErrLimitExceeded
+	case errors.As(err, &unknown):
+		de.Kind = codec.ErrUnknownField
+	default:
+		return err
+	}
+	return de
+}
+
+// decodeNext decodes the next data item from decoder, reporting errors at
+// the offset where the item starts. When structural limits are set the
+// item is read whole and checked before it is decoded.
+func (c *Codec[T]) decodeNext(decoder *cbor.Decoder, v *T) error {
+	offset := int64(decoder.NumBytesRead())
+	if !c.cfg.Limits.Structural() {
+		return decodeError(decoder.Decode(v), offset)
+	}
+	var raw cbor.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		return decodeError(err, offset)
+	}
+	return c.Unmarshal(raw, v)
+}
+
+// decodeValue reads the next data item from decoder into v for a
+// Codec[codec.Value]
+func (c *Codec[T]) decodeValue(decoder *cbor.Decoder, v *codec.Value) error {
+	offset := int64(decoder.NumBytesRead())
+	var raw cbor.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		return decodeError(err, offset)
+	}
+	return c.unmarshalValue(raw, v)
+}
@@ -0,0 +1,116 @@
+//go:build codec_cbor
+
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestUnmarshal_TypeMismatch(t *testing.T) {
+	data, err := cbor.Marshal(map[string]any{"name": "John", "age": "thirty"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var v TestStruct
+	err = New[TestStruct]().Unmarshal(data, &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Codec != codec.CBOR || de.Kind != codec.ErrTypeMismatch || de.Path != "age" {
+		t.Errorf("unexpected error: %+v", de)
+	}
+
+	var typeErr *cbor.UnmarshalTypeError
+	if !errors.As(err, &typeErr) {
+		t.Error("expected the library error to remain reachable")
+	}
+}
+
+func TestUnmarshal_Truncated(t *testing.T) {
+	data, err := New[TestStruct]().Marshal(TestStruct{Name: "John", Age: 30})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	for _, input := range [][]byte{nil, data[:len(data)/2]} {
+		var v TestStruct
+		err := New[TestStruct]().Unmarshal(input, &v)
+		if !errors.Is(err, codec.ErrTruncated) {
+			t.Errorf("expected truncated error for %d bytes, got %v", len(input), err)
+		}
+	}
+}
+
+func TestUnmarshal_SyntaxError(t *testing.T) {
+	var v TestStruct
+	// 0x1c is a reserved additional information value
+	err := New[TestStruct]().Unmarshal([]byte{0x1c}, &v)
+	if !errors.Is(err, codec.ErrSyntax) {
+		t.Fatalf("expected syntax error, got %v", err)
+	}
+}
+
+func TestUnmarshal_LimitExceeded(t *testing.T) {
+	c := New[[]int](WithDecOptions(cbor.DecOptions{MaxArrayElements: 16}))
+	data, err := cbor.Marshal(make([]int, 32))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var v []int
+	if err := c.Unmarshal(data, &v); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded error, got %v", err)
+	}
+}
+
+func TestUnmarshal_UnknownField(t *testing.T) {
+	c := New[TestStruct](WithDecOptions(cbor.DecOptions{ExtraReturnErrors: cbor.ExtraDecErrorUnknownField}))
+	data, err := cbor.Marshal(map[string]any{"name": "John", "phone": "555"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var v TestStruct
+	if err := c.Unmarshal(data, &v); !errors.Is(err, codec.ErrUnknownField) {
+		t.Fatalf("expected unknown field error, got %v", err)
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	c := New[TestStruct]()
+	good, err := c.Marshal(TestStruct{Name: "John"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	bad, err := cbor.Marshal(map[string]any{"age": "thirty"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	dec := c.NewDecoder(bytes.NewReader(append(append([]byte{}, good...), bad...)))
+	var v TestStruct
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	err = dec.Decode(&v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) || de.Kind != codec.ErrTypeMismatch {
+		t.Fatalf("expected type mismatch, got %v", err)
+	}
+	if de.Offset != int64(len(good)) {
+		t.Errorf("expected offset %d, got %d", len(good), de.Offset)
+	}
+	if err := dec.Decode(&v); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+//go:build codec_cbor
+
+package cbor
+
+import (
+	"encoding/binary"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// checkLimits enforces the codec's limits on data, an encoded CBOR data item
+func (c *Codec[T]) checkLimits(data []byte) error {
+	limits := c.cfg.Limits
+	if err := limits.CheckBytes(int64(len(data))); err != nil {
+		return codec.DecodeError{Codec: codec.CBOR, Kind: codec.ErrLimitExceeded, Offset: limits.MaxBytes, Err: err}
+	}
+	if !limits.Structural() {
+		return nil
+	}
+	if offset, err := scanLimits(data, limits); err != nil {
+		return codec.DecodeError{Codec: codec.CBOR, Kind: codec.ErrLimitExceeded, Offset: offset, Err: err}
+	}
+	return nil
+}
+
+// CBOR major types
+const (
+	majorBytes = 2
+	majorText  = 3
+	majorArray = 4
+	majorMap   = 5
+	majorTag   = 6
+	majorOther = 7
+)
+
+// frame is an open array, map, tag or indefinite-length string
+type frame struct {
+	indefinite bool
+	remaining  uint64 // items left, for definite lengths
+	seen       uint64 // items seen, for indefinite lengths
+	isMap      bool
+	isString   bool   // chunks of an indefinite-length string
+	length     uint64 // accumulated length of those chunks
+}
+
+// scanLimits walks the headers of the CBOR data item at the start of data,
+// checking nesting depth, container sizes and string lengths against limits
+// without decoding or allocating. Tags count as a nesting level, as they do
+// for the decoder. It returns the offset of the first violation. Malformed
+// or truncated input is left for the decoder to report.
+func scanLimits(data []byte, limits codec.Limits) (int64, error) {
+	var stack []frame
+	for offset := 0; offset < len(data); {
+		start := offset
+		major, info := data[offset]>>5, data[offset]&0x1f
+		offset++
+
+		if data[start] == 0xff {
+			// Break: closes the innermost indefinite-length item
+			if len(stack) == 0 || !stack[len(stack)-1].indefinite {
+				return 0, nil
+			}
+			stack = stack[:len(stack)-1]
+		} else {
+			var arg uint64
+			indefinite := info == 31
+			switch {
+			case info < 24:
+				arg = uint64(info)
+			case info <= 27:
+				n := 1 << (info - 24)
+				if offset+n > len(data) {
+					return 0, nil
+				}
+				arg = readUint(data[offset : offset+n])
+				offset += n
+			case indefinite && major >= majorBytes && major <= majorMap:
+			default:
+				return 0, nil
+			}
+
+			switch major {
+			case majorBytes, majorText:
+				if indefinite {
+					if err := limits.CheckDepth(len(stack) + 1); err != nil {
+						return int64(start), err
+					}
+					stack = append(stack, frame{indefinite: true, isString: true})
+					continue
+				}
+				length := arg
+				if len(stack) > 0 && stack[len(stack)-1].isString {
+					stack[len(stack)-1].length += arg
+					length = stack[len(stack)-1].length
+				}
+				if err := limits.CheckStringLength(int64(length)); err != nil {
+					return int64(start), err
+				}
+				if arg > uint64(len(data)-offset) {
+					return 0, nil
+				}
+				offset += int(arg)
+			case majorArray, majorMap, majorTag:
+				if err := limits.CheckDepth(len(stack) + 1); err != nil {
+					return int64(start), err
+				}
+				f := frame{indefinite: indefinite, remaining: 1, isMap: major == majorMap}
+				if major != majorTag {
+					if err := limits.CheckElements(int64(arg)); err != nil {
+						return int64(start), err
+					}
+					f.remaining = arg
+					if f.isMap {
+						f.remaining *= 2
+					}
+				}
+				if indefinite || f.remaining > 0 {
+					stack = append(stack, f)
+					continue
+				}
+			}
+		}
+
+		// The item is complete; close every definite container it completes
+		for {
+			if len(stack) == 0 {
+				return 0, nil
+			}
+			top := &stack[len(stack)-1]
+			if top.indefinite {
+				top.seen++
+				entries := top.seen
+				if top.isMap {
+					entries = (entries + 1) / 2
+				}
+				if err := limits.CheckElements(int64(entries)); err != nil {
+					return int64(start), err
+				}
+				break
+			}
+			top.remaining--
+			if top.remaining > 0 {
+				break
+			}
+			stack = stack[:len(stack)-1]
+		}
+	}
+	return 0, nil
+}
+
+// readUint decodes a big-endian unsigned integer of 1, 2, 4 or 8 bytes
+func readUint(b []byte) uint64 {
+	switch len(b) {
+	case 1:
+		return uint64(b[0])
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b))
+	case 4:
+		return uint64(binary.BigEndian.Uint32(b))
+	default:
+		return binary.BigEndian.Uint64(b)
+	}
+}
@@ -0,0 +1,146 @@
+//go:build codec_cbor
+
+package cbor
+
+import (
+	"bytes"
+	"encoding/hex"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestLimits_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits codec.Limits
+		value  any
+		limit  string
+	}{
+		{"bytes", codec.Limits{MaxBytes: 8}, map[string]any{"name": "John Doe"}, "MaxBytes"},
+		{"depth", codec.Limits{MaxDepth: 2}, map[string]any{"a": []any{[]any{1}}}, "MaxDepth"},
+		{"elements", codec.Limits{MaxElements: 3}, []int{1, 2, 3, 4}, "MaxElements"},
+		{"map entries", codec.Limits{MaxElements: 1}, map[string]int{"a": 1, "b": 2}, "MaxElements"},
+		{"string", codec.Limits{MaxStringLength: 4}, []string{"ok", "Johnny"}, "MaxStringLength"},
+		{"binary", codec.Limits{MaxStringLength: 4}, []byte("binary"), "MaxStringLength"},
+		{"tag depth", codec.Limits{MaxDepth: 1}, []cbor.Tag{{Number: 100, Content: 1}}, "MaxDepth"},
+		{"large array", codec.Limits{MaxElements: 100}, make([]int, 70000), "MaxElements"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := cbor.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+
+			var v any
+			err = New[any](codec.WithLimits(tt.limits)).Unmarshal(data, &v)
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			var le codec.LimitError
+			if de.Kind != codec.ErrLimitExceeded || !errors.As(err, &le) || le.Limit != tt.limit {
+				t.Fatalf("expected %s exceeded, got %v", tt.limit, err)
+			}
+		})
+	}
+}
+
+func TestLimits_Indefinite(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits codec.Limits
+		input  string
+		limit  string
+		offset int64
+	}{
+		// [_ 1, 2, 3]
+		{"array", codec.Limits{MaxElements: 2}, "9f010203ff", "MaxElements", 3},
+		// {_ "a": 1, "b": 2}
+		{"map", codec.Limits{MaxElements: 1}, "bf616101616202ff", "MaxElements", 4},
+		// (_ "ab", "cd")
+		{"string chunks", codec.Limits{MaxStringLength: 3}, "7f626162626364ff", "MaxStringLength", 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, _ := hex.DecodeString(tt.input)
+			var v any
+			err := New[any](codec.WithLimits(tt.limits)).Unmarshal(data, &v)
+
+			var de codec.DecodeError
+			var le codec.LimitError
+			if !errors.As(err, &de) || !errors.As(err, &le) || le.Limit != tt.limit {
+				t.Fatalf("expected %s exceeded, got %v", tt.limit, err)
+			}
+			if de.Offset != tt.offset {
+				t.Errorf("expected offset %d, got %d", tt.offset, de.Offset)
+			}
+		})
+	}
+}
+
+func TestLimits_WithinLimits(t *testing.T) {
+	limits := codec.Limits{MaxBytes: 256, MaxDepth: 3, MaxElements: 4, MaxStringLength: 16}
+	value := map[string]any{
+		"name":  "John",
+		"tags":  []any{1, 2.5, int64(-70000), true},
+		"inner": map[string]any{"data": []byte{1, 2}, "none": nil},
+	}
+	data, err := cbor.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var v map[string]any
+	if err := New[map[string]any](codec.WithLimits(limits)).Unmarshal(data, &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if v["name"] != "John" {
+		t.Errorf("unexpected value %v", v)
+	}
+
+	// [_ "a", (_ "b", "c")]
+	data, _ = hex.DecodeString("9f61617f61626163ffff")
+	var list []string
+	if err := New[[]string](codec.WithLimits(limits)).Unmarshal(data, &list); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(list) != 2 || list[1] != "bc" {
+		t.Errorf("unexpected value %v", list)
+	}
+}
+
+func TestLimits_Decoder(t *testing.T) {
+	c := New[TestStruct](codec.WithLimits(codec.Limits{MaxStringLength: 8}))
+	var buf bytes.Buffer
+	enc := c.NewEncoder(&buf)
+	for _, name := range []string{"John", "Jane", "Johnathan Doe"} {
+		if err := enc.Encode(TestStruct{Name: name}); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+	}
+
+	dec := c.NewDecoder(&buf)
+	var v TestStruct
+	for i := 0; i < 2; i++ {
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("Decode %d failed: %v", i, err)
+		}
+	}
+	if err := dec.Decode(&v); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+
+	data, _ := c.Marshal(TestStruct{Name: strings.Repeat("x", 200)})
+	c = New[TestStruct](codec.WithLimits(codec.Limits{MaxBytes: 64}))
+	if err := c.Decode(bytes.NewReader(data), &v); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,142 @@
+//go:build codec_cbor
+
+package cbor
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+var _ codec.OptimizedCodec[TestStruct] = NewPool[TestStruct]()
+
+func TestOptimizedCodec_MarshalTo(t *testing.T) {
+	c := NewPool[TestStruct]()
+	data := TestStruct{Name: "John Doe", Age: 30, Email: "john@example.com"}
+
+	buf := make([]byte, 0, 256)
+	result, err := c.MarshalTo(buf, data)
+	if err != nil {
+		t.Fatalf("MarshalTo failed: %v", err)
+	}
+	if cap(result) != cap(buf) {
+		t.Errorf("expected result to use provided buffer, got different capacity")
+	}
+	want, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !bytes.Equal(result, want) {
+		t.Errorf("MarshalTo = %x, want %x", result, want)
+	}
+}
+
+func TestOptimizedCodec_MarshalTo_SmallBuffer(t *testing.T) {
+	c := NewPool[TestStruct]()
+	data := TestStruct{Name: "John Doe", Age: 30, Email: "john@example.com"}
+
+	result, err := c.MarshalTo(make([]byte, 0, 1), data)
+	if err != nil {
+		t.Fatalf("MarshalTo failed: %v", err)
+	}
+	var decoded TestStruct
+	if err := c.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("Unmarshal verification failed: %v", err)
+	}
+	if decoded != data {
+		t.Errorf("expected %+v, got %+v", data, decoded)
+	}
+}
+
+func TestOptimizedCodec_AppendMarshal(t *testing.T) {
+	c := NewPool[TestStruct]()
+	data := TestStruct{Name: "John Doe", Age: 30, Email: "john@example.com"}
+
+	result, err := c.AppendMarshal([]byte("prefix:"), data)
+	if err != nil {
+		t.Fatalf("AppendMarshal failed: %v", err)
+	}
+	if string(result[:7]) != "prefix:" {
+		t.Errorf("expected prefix to be preserved, got %s", string(result[:7]))
+	}
+	var decoded TestStruct
+	if err := c.Unmarshal(result[7:], &decoded); err != nil {
+		t.Fatalf("Unmarshal verification failed: %v", err)
+	}
+	if decoded != data {
+		t.Errorf("expected %+v, got %+v", data, decoded)
+	}
+}
+
+func TestOptimizedCodec_UnmarshalFrom(t *testing.T) {
+	c := NewPool[TestStruct]()
+	data := TestStruct{Name: "Jane Doe", Age: 25, Email: "jane@example.com"}
+
+	marshaled, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var result TestStruct
+	if err := c.UnmarshalFrom(marshaled, &result, make([]byte, 256)); err != nil {
+		t.Fatalf("UnmarshalFrom failed: %v", err)
+	}
+	if result != data {
+		t.Errorf("expected %+v, got %+v", data, result)
+	}
+	if err := c.UnmarshalFrom([]byte{0xff}, &result, nil); err == nil {
+		t.Fatal("expected error for invalid CBOR, got nil")
+	}
+}
+
+func TestOptimizedCodec_OptionError(t *testing.T) {
+	c := NewPool[TestStruct](WithEncOptions(cbor.EncOptions{Sort: cbor.SortMode(99)}))
+	if _, err := c.MarshalTo(nil, TestStruct{}); err == nil {
+		t.Error("expected option error from MarshalTo")
+	}
+	if _, err := c.AppendMarshal(nil, TestStruct{}); err == nil {
+		t.Error("expected option error from AppendMarshal")
+	}
+}
+
+func TestOptimizedCodec_WritesIntoBuffer(t *testing.T) {
+	for _, opts := range [][]codec.Option{nil, {WithCanonical()}} {
+		c := NewPool[TestStruct](opts...)
+		data := TestStruct{Name: "John Doe", Age: 30, Email: "john@example.com"}
+		want, err := c.Marshal(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buf := make([]byte, 0, 256)
+		result, err := c.MarshalTo(buf, data)
+		if err != nil || !bytes.Equal(result, want) {
+			t.Fatalf("MarshalTo = %x, %v; want %x", result, err, want)
+		}
+		if &result[0] != &buf[:1][0] {
+			t.Error("MarshalTo did not write into the provided buffer")
+		}
+
+		buf = append(buf[:0], "prefix"...)
+		result, err = c.AppendMarshal(buf, data)
+		if err != nil || !bytes.Equal(result[6:], want) {
+			t.Fatalf("AppendMarshal = %x, %v", result, err)
+		}
+		if &result[0] != &buf[0] {
+			t.Error("AppendMarshal did not write into the provided buffer")
+		}
+	}
+}
+
+func TestOptimizedCodec_AppendMarshal_Error(t *testing.T) {
+	c := NewPool[any]()
+	buf := []byte("prefix")
+	result, err := c.AppendMarshal(buf, make(chan int))
+	if err == nil {
+		t.Fatal("expected an error for a channel")
+	}
+	if string(result) != "prefix" {
+		t.Errorf("expected original buffer on error, got %s", result)
+	}
+}
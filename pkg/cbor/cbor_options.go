@@ -0,0 +1,58 @@
+//go:build codec_cbor
+
+package cbor
+
+import (
+	"github.com/fxamacker/cbor/v2"
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// config holds the settings applied by CBOR options
+type config struct {
+	codec.Config
+
+	encOpts cbor.EncOptions
+	decOpts cbor.DecOptions
+}
+
+// encodesDefault reports whether encoding uses the cbor library's default
+// options
+func (c *config) encodesDefault() bool {
+	return c.encOpts == cbor.EncOptions{}
+}
+
+// decodesDefault reports whether decoding uses the cbor library's default
+// options
+func (c *config) decodesDefault() bool {
+	return !c.Strict && c.decOpts == cbor.DecOptions{}
+}
+
+// WithCanonical encodes using the Canonical CBOR rules of RFC 7049:
+// shortest-form integers and lengths and length-first sorted map keys
+func WithCanonical() codec.Option {
+	return codec.NewOption("cbor.WithCanonical", func(c *config) {
+		c.encOpts = cbor.CanonicalEncOptions()
+	})
+}
+
+// WithCoreDeterministic encodes using the Core Deterministic Encoding
+// requirements of RFC 8949 section 4.2.1
+func WithCoreDeterministic() codec.Option {
+	return codec.NewOption("cbor.WithCoreDeterministic", func(c *config) {
+		c.encOpts = cbor.CoreDetEncOptions()
+	})
+}
+
+// WithEncOptions replaces the encoding options passed to the CBOR library
+func WithEncOptions(opts cbor.EncOptions) codec.Option {
+	return codec.NewOption("cbor.WithEncOptions", func(c *config) {
+		c.encOpts = opts
+	})
+}
+
+// WithDecOptions replaces the decoding options passed to the CBOR library
+func WithDecOptions(opts cbor.DecOptions) codec.Option {
+	return codec.NewOption("cbor.WithDecOptions", func(c *config) {
+		c.decOpts = opts
+	})
+}
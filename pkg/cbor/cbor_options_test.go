@@ -0,0 +1,108 @@
+//go:build codec_cbor
+
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/jeremyhahn/go-codec"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+)
+
+func TestWithCanonical(t *testing.T) {
+	c := New[map[string]int](WithCanonical())
+	data := map[string]int{"bb": 2, "a": 1, "ccc": 3}
+
+	result, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	// Canonical CBOR sorts keys by length first, then bytewise
+	expected := []byte{0xa3, 0x61, 'a', 0x01, 0x62, 'b', 'b', 0x02, 0x63, 'c', 'c', 'c', 0x03}
+	if !bytes.Equal(result, expected) {
+		t.Errorf("expected % x, got % x", expected, result)
+	}
+}
+
+func TestWithCoreDeterministic(t *testing.T) {
+	c := New[map[string]int](WithCoreDeterministic())
+	data := map[string]int{"b": 2, "a": 1}
+
+	result, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	expected := []byte{0xa2, 0x61, 'a', 0x01, 0x61, 'b', 0x02}
+	if !bytes.Equal(result, expected) {
+		t.Errorf("expected % x, got % x", expected, result)
+	}
+}
+
+func TestWithEncOptions(t *testing.T) {
+	c := New[[]byte](WithEncOptions(cbor.EncOptions{ByteSliceLaterFormat: cbor.ByteSliceLaterFormatBase64}))
+	result, err := c.Marshal([]byte{0x01})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	// Tag 22 (expected base64 conversion) precedes the byte string
+	if result[0] != 0xd6 {
+		t.Errorf("expected tag 22 prefix, got % x", result)
+	}
+
+	invalid := New[TestStruct](WithEncOptions(cbor.EncOptions{Sort: cbor.SortMode(99)}))
+	if invalid.Err() == nil {
+		t.Error("expected error for invalid encoding options")
+	}
+}
+
+func TestWithDecOptions(t *testing.T) {
+	c := New[map[string]int](WithDecOptions(cbor.DecOptions{DupMapKey: cbor.DupMapKeyEnforcedAPF}))
+
+	// {"a": 1, "a": 2}
+	duplicate := []byte{0xa2, 0x61, 'a', 0x01, 0x61, 'a', 0x02}
+	var result map[string]int
+	if err := c.Unmarshal(duplicate, &result); err == nil {
+		t.Fatal("expected error for duplicate map key, got nil")
+	}
+	if err := c.Decode(bytes.NewReader(duplicate), &result); err == nil {
+		t.Fatal("expected error for duplicate map key, got nil")
+	}
+
+	invalid := New[TestStruct](WithDecOptions(cbor.DecOptions{DupMapKey: cbor.DupMapKeyMode(99)}))
+	if invalid.Err() == nil {
+		t.Error("expected error for invalid decoding options")
+	}
+}
+
+func TestNew_OptionNotSupported(t *testing.T) {
+	c := New[TestStruct](jsoncodec.WithUseNumber())
+
+	var notSupported codec.ErrOptionNotSupported
+	if !errors.As(c.Err(), &notSupported) {
+		t.Fatalf("expected ErrOptionNotSupported, got %v", c.Err())
+	}
+
+	var result TestStruct
+	if _, err := c.Marshal(TestStruct{}); err == nil {
+		t.Error("Marshal: expected option error, got nil")
+	}
+	if err := c.Unmarshal([]byte{0xa0}, &result); err == nil {
+		t.Error("Unmarshal: expected option error, got nil")
+	}
+	if err := c.Encode(&bytes.Buffer{}, TestStruct{}); err == nil {
+		t.Error("Encode: expected option error, got nil")
+	}
+	if err := c.Decode(bytes.NewReader([]byte{0xa0}), &result); err == nil {
+		t.Error("Decode: expected option error, got nil")
+	}
+	if err := c.NewEncoder(&bytes.Buffer{}).Encode(TestStruct{}); err == nil {
+		t.Error("Encoder.Encode: expected option error, got nil")
+	}
+	if err := c.NewDecoder(bytes.NewReader([]byte{0xa0})).Decode(&result); err == nil {
+		t.Error("Decoder.Decode: expected option error, got nil")
+	}
+}
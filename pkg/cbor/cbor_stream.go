@@ -0,0 +1,72 @@
+//go:build codec_cbor
+
+package cbor
+
+import (
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// Encoder writes a sequence of CBOR data items to a stream
+type Encoder[T any] struct {
+	codec *Codec[T]
+	enc   *cbor.Encoder
+	err   error
+}
+
+// Decoder reads a sequence of CBOR data items from a stream
+type Decoder[T any] struct {
+	codec *Codec[T]
+	dec   *cbor.Decoder
+	limit *codec.LimitedReader
+	err   error
+}
+
+// NewEncoder returns an encoder session that writes successive CBOR data items to w
+func (c *Codec[T]) NewEncoder(w io.Writer) codec.Encoder[T] {
+	if c.err != nil {
+		return &Encoder[T]{err: c.err}
+	}
+	return &Encoder[T]{codec: c, enc: c.newEncoder(w)}
+}
+
+// NewDecoder returns a decoder session that reads successive CBOR data items from r
+func (c *Codec[T]) NewDecoder(r io.Reader) codec.Decoder[T] {
+	if c.err != nil {
+		return &Decoder[T]{err: c.err}
+	}
+	r = codec.NewLimitedReader(r, codec.CBOR, c.cfg.Limits.MaxBytes)
+	limit, _ := r.(*codec.LimitedReader)
+	return &Decoder[T]{codec: c, dec: c.newDecoder(r), limit: limit}
+}
+
+// Encode writes the next CBOR data item to the stream
+func (e *Encoder[T]) Encode(data T) error {
+	if e.err != nil {
+		return e.err
+	}
+	if v, ok := any(data).(codec.Value); ok {
+		raw, err := e.codec.marshalValue(v)
+		if err != nil {
+			return err
+		}
+		return e.enc.Encode(cbor.RawMessage(raw))
+	}
+	return e.enc.Encode(data)
+}
+
+// Decode reads the next CBOR data item from the stream
+func (d *Decoder[T]) Decode(data *T) error {
+	if d.err != nil {
+		return d.err
+	}
+	if d.limit != nil {
+		d.limit.Reset()
+	}
+	if v, ok := any(data).(*codec.Value); ok {
+		return d.codec.decodeValue(d.dec, v)
+	}
+	return d.codec.decodeNext(d.dec, data)
+}
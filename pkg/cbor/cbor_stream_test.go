@@ -0,0 +1,59 @@
+//go:build codec_cbor
+
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestCodec_StreamCodec(t *testing.T) {
+	var _ codec.StreamCodec[TestStruct] = New[TestStruct]()
+}
+
+func TestStream_RoundTrip(t *testing.T) {
+	c := New[TestStruct]()
+	values := []TestStruct{
+		{Name: "Alice", Age: 30, Email: "alice@example.com"},
+		{Name: "Bob", Age: 25, Email: "bob@example.com"},
+		{Name: "Carol", Age: 41, Email: "carol@example.com"},
+	}
+
+	var buf bytes.Buffer
+	enc := c.NewEncoder(&buf)
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+	}
+
+	dec := c.NewDecoder(&buf)
+	for i, want := range values {
+		var got TestStruct
+		if err := dec.Decode(&got); err != nil {
+			t.Fatalf("Decode %d failed: %v", i, err)
+		}
+		if got != want {
+			t.Errorf("value %d: expected %+v, got %+v", i, want, got)
+		}
+	}
+
+	var extra TestStruct
+	if err := dec.Decode(&extra); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF after last value, got %v", err)
+	}
+}
+
+func TestStream_Decode_Invalid(t *testing.T) {
+	c := New[TestStruct]()
+	dec := c.NewDecoder(bytes.NewReader([]byte{0xc1, 0xff, 0xff, 0xff, 0xff}))
+
+	var result TestStruct
+	if err := dec.Decode(&result); err == nil {
+		t.Fatal("expected error for invalid CBOR stream, got nil")
+	}
+}
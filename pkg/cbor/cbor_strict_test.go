@@ -0,0 +1,63 @@
+//go:build codec_cbor
+
+package cbor
+
+import (
+	"encoding/hex"
+	"errors"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestStrict_Unmarshal(t *testing.T) {
+	unknown, _ := cbor.Marshal(map[string]any{"name": "John", "nmae": "Jane"})
+	// {"name": "John", "name": "Jane"}
+	duplicate, _ := hex.DecodeString("a2646e616d65644a6f686e646e616d65644a616e65")
+	lossy, _ := cbor.Marshal(map[string]any{"age": 30.5})
+
+	tests := []struct {
+		name  string
+		input []byte
+		kind  codec.ErrorKind
+		path  string
+	}{
+		{"unknown field", unknown, codec.ErrUnknownField, ""},
+		{"duplicate key", duplicate, codec.ErrDuplicateKey, "name"},
+		{"lossy number", lossy, codec.ErrTypeMismatch, "age"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v TestStruct
+			err := New[TestStruct](codec.WithStrict()).Unmarshal(tt.input, &v)
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			if de.Kind != tt.kind || de.Path != tt.path {
+				t.Errorf("expected %v at %q, got %v", tt.kind, tt.path, err)
+			}
+		})
+	}
+
+	// Without Strict duplicates and unknown fields are accepted
+	var v TestStruct
+	for _, input := range [][]byte{unknown, duplicate} {
+		if err := New[TestStruct]().Unmarshal(input, &v); err != nil {
+			t.Errorf("expected lenient decoding, got %v", err)
+		}
+	}
+}
+
+func TestStrict_KeepsDecOptions(t *testing.T) {
+	c := New[map[string]any](codec.WithStrict(), WithDecOptions(cbor.DecOptions{MaxArrayElements: 16}))
+	data, _ := cbor.Marshal(map[string]any{"list": make([]int, 32)})
+
+	var v map[string]any
+	if err := c.Unmarshal(data, &v); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,420 @@
+//go:build codec_cbor
+
+package cbor
+
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"time"
+
+	"github.com/fxamacker/cbor/v2"
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// CBOR tags with a counterpart in codec.Value
+const (
+	tagDateTime     = 0
+	tagEpochTime    = 1
+	tagPosBignum    = 2
+	tagNegBignum    = 3
+	tagSelfDescribe = 55799
+)
+
+// valueCodec converts between CBOR and codec.Value
+type valueCodec struct{}
+
+// DecodeValue reads the next CBOR data item from r. Unsigned integers
+// decode as signed unless they exceed the range of an int64. Date/time
+// tags decode as times and bignums as integers; other tags decode as tagged
+// Values.
+func (valueCodec) DecodeValue(r io.Reader, cfg codec.Config, lossy func(codec.Loss)) (codec.Value, error) {
+	decoder := cbor.NewDecoder(codec.NewLimitedReader(r, codec.CBOR, cfg.Limits.MaxBytes))
+	// Decoding into a RawMessage checks that the data item is well-formed
+	var raw cbor.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		return codec.Value{}, decodeError(err, 0)
+	}
+	c := &Codec[codec.Value]{cfg: config{Config: cfg}}
+	if err := c.checkLimits(raw); err != nil {
+		return codec.Value{}, err
+	}
+	d := valueDecoder{data: raw, strict: cfg.Strict, lossy: lossy}
+	return d.decode("")
+}
+
+// unmarshalValue decodes data, a single CBOR data item, into v for a
+// Codec[codec.Value], failing on lossy conversions if the codec is strict
+func (c *Codec[T]) unmarshalValue(data []byte, v *codec.Value) error {
+	if err := c.checkLimits(data); err != nil {
+		return err
+	}
+	if err := c.decMode.Wellformed(data); err != nil {
+		return decodeError(err, -1)
+	}
+	lossy, check := codec.CollectLosses(codec.CBOR, c.cfg.Strict)
+	d := valueDecoder{data: data, strict: c.cfg.Strict, lossy: lossy}
+	value, err := d.decode("")
+	if err == nil {
+		err = check()
+	}
+	if err != nil {
+		return err
+	}
+	*v = value
+	return nil
+}
+
+// marshalValue returns the encoding of v for a Codec[codec.Value], failing
+// on lossy conversions if the codec is strict
+func (c *Codec[T]) marshalValue(v codec.Value) ([]byte, error) {
+	lossy, check := codec.CollectLosses(codec.CBOR, c.cfg.Strict)
+	e := valueEncoder{lossy: lossy}
+	data, err := e.append(nil, v, "")
+	if err == nil {
+		err = check()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// valueDecoder builds a codec.Value from a well-formed CBOR data item
+type valueDecoder struct {
+	data   []byte
+	offset int
+	strict bool
+	lossy  func(codec.Loss)
+}
+
+// head reads the initial byte and argument of the next data item. The
+// argument is 0 for items of indefinite length, reported by info 31.
+func (d *valueDecoder) head() (major, info byte, arg uint64) {
+	major, info = d.data[d.offset]>>5, d.data[d.offset]&0x1f
+	d.offset++
+	switch {
+	case info < 24:
+		arg = uint64(info)
+	case info == 24:
+		arg = uint64(d.data[d.offset])
+		d.offset++
+	case info == 25:
+		arg = uint64(binary.BigEndian.Uint16(d.data[d.offset:]))
+		d.offset += 2
+	case info == 26:
+		arg = uint64(binary.BigEndian.Uint32(d.data[d.offset:]))
+		d.offset += 4
+	case info == 27:
+		arg = binary.BigEndian.Uint64(d.data[d.offset:])
+		d.offset += 8
+	}
+	return major, info, arg
+}
+
+// more reports whether a container of count items, or of indefinite length
+// if count is negative, holds another item after the i items read, and
+// consumes the break that ends an indefinite-length container
+func (d *valueDecoder) more(i int, count int64) bool {
+	if count >= 0 {
+		return int64(i) < count
+	}
+	if d.data[d.offset] == 0xff {
+		d.offset++
+		return false
+	}
+	return true
+}
+
+func (d *valueDecoder) decode(path string) (codec.Value, error) {
+	major, info, arg := d.head()
+	count := int64(arg)
+	if info == 31 {
+		count = -1
+	}
+
+	switch major {
+	case 0:
+		if arg > math.MaxInt64 {
+			return codec.UintValue(arg), nil
+		}
+		return codec.IntValue(int64(arg)), nil
+	case 1:
+		if arg > math.MaxInt64 {
+			d.lossy(codec.Loss{Path: path, Reason: "integer beyond 64 bits decoded as float"})
+			return codec.FloatValue(-1 - float64(arg)), nil
+		}
+		return codec.IntValue(-1 - int64(arg)), nil
+	case majorBytes, majorText:
+		var b []byte
+		if count < 0 {
+			b = []byte{}
+			for i := 0; d.more(i, count); i++ {
+				_, _, n := d.head()
+				b = append(b, d.data[d.offset:d.offset+int(n)]...)
+				d.offset += int(n)
+			}
+		} else {
+			b = d.data[d.offset : d.offset+int(arg) : d.offset+int(arg)]
+			d.offset += int(arg)
+		}
+		if major == majorText {
+			return codec.StringValue(string(b)), nil
+		}
+		return codec.BytesValue(b), nil
+	case majorArray:
+		var items []codec.Value
+		for i := 0; d.more(i, count); i++ {
+			item, err := d.decode(path + "[" + strconv.Itoa(i) + "]")
+			if err != nil {
+				return codec.Value{}, err
+			}
+			items = append(items, item)
+		}
+		return codec.ArrayValue(items...), nil
+	case majorMap:
+		var members []codec.Member
+		index := make(map[string]int)
+		for i := 0; d.more(i, count); i++ {
+			keyStart := d.offset
+			key, err := d.decode(path)
+			if err != nil {
+				return codec.Value{}, err
+			}
+			encoded := string(d.data[keyStart:d.offset])
+			value, err := d.decode(joinPath(path, key.String()))
+			if err != nil {
+				return codec.Value{}, err
+			}
+			if j, ok := index[encoded]; ok {
+				if d.strict {
+					return codec.Value{}, codec.DecodeError{
+						Codec:  codec.CBOR,
+						Kind:   codec.ErrDuplicateKey,
+						Offset: int64(keyStart),
+						Path:   path,
+						Err:    fmt.Errorf("cbor: duplicate map key %s", key),
+					}
+				}
+				members[j].Value = value
+				continue
+			}
+			index[encoded] = len(members)
+			members = append(members, codec.Member{Key: key, Value: value})
+		}
+		return codec.MapValue(members...), nil
+	case majorTag:
+		return d.tagged(arg, path)
+	default:
+		return d.simple(info, arg, path)
+	}
+}
+
+// tagged decodes the content of a tag with number tag
+func (d *valueDecoder) tagged(tag uint64, path string) (codec.Value, error) {
+	content, err := d.decode(path)
+	if err != nil {
+		return codec.Value{}, err
+	}
+	switch tag {
+	case tagSelfDescribe:
+		return content, nil
+	case tagDateTime:
+		if content.Kind() == codec.KindString {
+			if t, err := time.Parse(time.RFC3339Nano, content.String()); err == nil {
+				return codec.TimeValue(t), nil
+			}
+		}
+	case tagEpochTime:
+		switch content.Kind() {
+		case codec.KindInt:
+			return codec.TimeValue(time.Unix(content.Int(), 0).UTC()), nil
+		case codec.KindFloat:
+			sec, frac := math.Modf(content.Float())
+			return codec.TimeValue(time.Unix(int64(sec), int64(frac*1e9)).UTC()), nil
+		}
+	case tagPosBignum, tagNegBignum:
+		if content.Kind() == codec.KindBytes {
+			return d.bignum(new(big.Int).SetBytes(content.Bytes()), tag == tagNegBignum, path), nil
+		}
+	}
+	if tag > math.MaxInt64 {
+		d.lossy(codec.Loss{Path: path, Reason: fmt.Sprintf("tag %d dropped", tag)})
+		return content, nil
+	}
+	return codec.TaggedValue(codec.Tag{Codec: codec.CBOR, Number: int64(tag)}, content), nil
+}
+
+// bignum converts the magnitude n of a bignum, which represents -1-n if
+// negative is set
+func (d *valueDecoder) bignum(n *big.Int, negative bool, path string) codec.Value {
+	if negative {
+		n.Neg(n).Sub(n, big.NewInt(1))
+	}
+	switch {
+	case n.IsInt64():
+		return codec.IntValue(n.Int64())
+	case n.IsUint64():
+		return codec.UintValue(n.Uint64())
+	}
+	d.lossy(codec.Loss{Path: path, Reason: "integer beyond 64 bits decoded as float"})
+	f, _ := n.Float64()
+	return codec.FloatValue(f)
+}
+
+// simple decodes a data item of major type 7 with the given info and
+// argument
+func (d *valueDecoder) simple(info byte, arg uint64, path string) (codec.Value, error) {
+	switch info {
+	case 20:
+		return codec.BoolValue(false), nil
+	case 21:
+		return codec.BoolValue(true), nil
+	case 22:
+		return codec.NullValue(), nil
+	case 23:
+		d.lossy(codec.Loss{Path: path, Reason: "undefined decoded as null"})
+		return codec.NullValue(), nil
+	case 25:
+		return codec.FloatValue(halfFloat(uint16(arg))), nil
+	case 26:
+		return codec.FloatValue(float64(math.Float32frombits(uint32(arg)))), nil
+	case 27:
+		return codec.FloatValue(math.Float64frombits(arg)), nil
+	}
+	d.lossy(codec.Loss{Path: path, Reason: fmt.Sprintf("simple value %d decoded as integer", arg)})
+	return codec.IntValue(int64(arg)), nil
+}
+
+// halfFloat converts an IEEE 754 half-precision float
+func halfFloat(h uint16) float64 {
+	exp, mant := int(h>>10&0x1f), float64(h&0x3ff)
+	var f float64
+	switch exp {
+	case 0:
+		f = math.Ldexp(mant, -24)
+	case 0x1f:
+		f = math.Inf(1)
+		if mant != 0 {
+			f = math.NaN()
+		}
+	default:
+		f = math.Ldexp(mant+0x400, exp-25)
+	}
+	if h&0x8000 != 0 {
+		f = -f
+	}
+	return f
+}
+
+// EncodeValue writes v to w as CBOR, using the smallest encoding of each
+// integer and length, and tag 0 with RFC 3339 text for times, which keeps
+// their time zone. Tags of other codecs are dropped in favor of their
+// content and reported to lossy.
+func (valueCodec) EncodeValue(w io.Writer, v codec.Value, lossy func(codec.Loss)) error {
+	e := valueEncoder{lossy: lossy}
+	data, err := e.append(nil, v, "")
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(data)
+	return err
+}
+
+// valueEncoder appends the CBOR encoding of a codec.Value to a buffer
+type valueEncoder struct {
+	lossy func(codec.Loss)
+}
+
+// append appends the CBOR encoding of v to buf
+func (e valueEncoder) append(buf []byte, v codec.Value, path string) ([]byte, error) {
+	var err error
+	switch v.Kind() {
+	case codec.KindBool:
+		if v.Bool() {
+			return append(buf, 0xf5), nil
+		}
+		return append(buf, 0xf4), nil
+	case codec.KindInt:
+		if i := v.Int(); i < 0 {
+			return appendHead(buf, 1, uint64(-1-i)), nil
+		}
+		return appendHead(buf, 0, uint64(v.Int())), nil
+	case codec.KindUint:
+		return appendHead(buf, 0, v.Uint()), nil
+	case codec.KindFloat:
+		return binary.BigEndian.AppendUint64(append(buf, 0xfb), math.Float64bits(v.Float())), nil
+	case codec.KindString:
+		buf = appendHead(buf, majorText, uint64(len(v.String())))
+		return append(buf, v.String()...), nil
+	case codec.KindBytes:
+		buf = appendHead(buf, majorBytes, uint64(len(v.Bytes())))
+		return append(buf, v.Bytes()...), nil
+	case codec.KindTime:
+		text := v.Time().Format(time.RFC3339Nano)
+		buf = appendHead(buf, majorTag, tagDateTime)
+		buf = appendHead(buf, majorText, uint64(len(text)))
+		return append(buf, text...), nil
+	case codec.KindArray:
+		buf = appendHead(buf, majorArray, uint64(len(v.Array())))
+		for i, item := range v.Array() {
+			if buf, err = e.append(buf, item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case codec.KindMap:
+		buf = appendHead(buf, majorMap, uint64(len(v.Members())))
+		for _, m := range v.Members() {
+			if buf, err = e.append(buf, m.Key, path); err != nil {
+				return nil, err
+			}
+			if buf, err = e.append(buf, m.Value, joinPath(path, m.Key.String())); err != nil {
+				return nil, err
+			}
+		}
+		return buf, nil
+	case codec.KindTagged:
+		tag := v.Tag()
+		if tag.Codec != codec.CBOR {
+			e.lossy(codec.Loss{Path: path, Reason: "tag " + tag.String() + " dropped"})
+			return e.append(buf, v.Content(), path)
+		}
+		if tag.Number < 0 {
+			return nil, fmt.Errorf("cbor: invalid tag number %d", tag.Number)
+		}
+		buf = appendHead(buf, majorTag, uint64(tag.Number))
+		return e.append(buf, v.Content(), path)
+	default:
+		return append(buf, 0xf6), nil
+	}
+}
+
+// appendHead appends the initial byte of major type major with argument n,
+// in its shortest form
+func appendHead(buf []byte, major byte, n uint64) []byte {
+	major <<= 5
+	switch {
+	case n < 24:
+		return append(buf, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(buf, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|27), n)
+	}
+}
+
+// joinPath appends key to the dotted path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
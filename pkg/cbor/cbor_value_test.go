@@ -0,0 +1,145 @@
+//go:build codec_cbor
+
+package cbor
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestValue_RoundTrip(t *testing.T) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, 6, time.FixedZone("", 7200))
+	v := codec.MapValue(
+		codec.Member{Key: codec.StringValue("z"), Value: codec.IntValue(math.MinInt64)},
+		codec.Member{Key: codec.IntValue(-7), Value: codec.UintValue(math.MaxUint64)},
+		codec.Member{Key: codec.StringValue("f"), Value: codec.FloatValue(2)},
+		codec.Member{Key: codec.StringValue("b"), Value: codec.BytesValue([]byte{1, 2})},
+		codec.Member{Key: codec.StringValue("t"), Value: codec.TimeValue(when)},
+		codec.Member{Key: codec.StringValue("a"), Value: codec.ArrayValue(codec.NullValue(), codec.BoolValue(true))},
+	)
+
+	var buf bytes.Buffer
+	if err := codec.EncodeValue(codec.CBOR, &buf, v); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	got, err := codec.DecodeValue(codec.CBOR, &buf)
+	if err != nil {
+		t.Fatalf("DecodeValue failed: %v", err)
+	}
+	if got.String() != v.String() {
+		t.Errorf("Round trip = %s, want %s", got, v)
+	}
+	for i, m := range got.Members() {
+		if want := v.Members()[i]; m.Key.Kind() != want.Key.Kind() || m.Value.Kind() != want.Value.Kind() {
+			t.Errorf("Member %d is %s:%s, want %s:%s", i, m.Key.Kind(), m.Value.Kind(), want.Key.Kind(), want.Value.Kind())
+		}
+	}
+}
+
+func TestValue_Decode(t *testing.T) {
+	tests := []struct {
+		name  string
+		input []byte
+		want  string
+		kind  codec.Kind
+	}{
+		{"half float", []byte{0xf9, 0x3c, 0x00}, "1", codec.KindFloat},
+		{"epoch time", []byte{0xc1, 0x1a, 0x65, 0x93, 0x7c, 0x30}, "2024-01-02T03:00:00Z", codec.KindTime},
+		{"bignum", []byte{0xc2, 0x42, 0x01, 0x00}, "256", codec.KindInt},
+		{"indefinite text", []byte{0x7f, 0x61, 'a', 0x61, 'b', 0xff}, "ab", codec.KindString},
+		{"indefinite map", []byte{0xbf, 0x61, 'k', 0x01, 0xff}, "{k:1}", codec.KindMap},
+		{"self-described", []byte{0xd9, 0xd9, 0xf7, 0x05}, "5", codec.KindInt},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := codec.DecodeValue(codec.CBOR, bytes.NewReader(tt.input))
+			if err != nil {
+				t.Fatalf("DecodeValue failed: %v", err)
+			}
+			if v.Kind() != tt.kind || v.String() != tt.want {
+				t.Errorf("DecodeValue = %s %s, want %s %s", v.Kind(), v, tt.kind, tt.want)
+			}
+		})
+	}
+}
+
+func TestValue_Tag(t *testing.T) {
+	input := []byte{0xd8, 0x25, 0x50, 1, 2, 3, 4, 5, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15, 16}
+	v, err := codec.DecodeValue(codec.CBOR, bytes.NewReader(input))
+	if err != nil {
+		t.Fatalf("DecodeValue failed: %v", err)
+	}
+	if v.Kind() != codec.KindTagged || v.Tag() != (codec.Tag{Codec: codec.CBOR, Number: 37}) {
+		t.Fatalf("Expected tag 37, got %s", v)
+	}
+
+	var buf bytes.Buffer
+	if err := codec.EncodeValue(codec.CBOR, &buf, v); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), input) {
+		t.Errorf("EncodeValue = %x, want %x", buf.Bytes(), input)
+	}
+
+	if !codec.IsSupported(codec.JSON) {
+		t.Skip("JSON is not compiled in")
+	}
+	err = codec.Transcode(codec.JSON, codec.CBOR, bytes.NewReader(input), &buf)
+	var lossErr *codec.LossError
+	if !errors.As(err, &lossErr) || lossErr.Losses[0].Reason != "tag cbor:37 dropped" {
+		t.Errorf("Expected a dropped tag loss, got %v", err)
+	}
+}
+
+func TestCodec_Value(t *testing.T) {
+	c := New[codec.Value]()
+	v := codec.MapValue(
+		codec.Member{Key: codec.IntValue(2), Value: codec.StringValue("b")},
+		codec.Member{Key: codec.IntValue(1), Value: codec.TaggedValue(codec.Tag{Codec: codec.CBOR, Number: 32}, codec.StringValue("https://example.com"))},
+	)
+	data, err := c.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var got codec.Value
+	if err := c.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !got.Equal(v) {
+		t.Errorf("Round trip = %s, want %s", got, v)
+	}
+
+	var buf bytes.Buffer
+	encoder := c.NewEncoder(&buf)
+	for _, item := range []codec.Value{codec.IntValue(1), v} {
+		if err := encoder.Encode(item); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+	}
+	decoder := c.NewDecoder(&buf)
+	for _, want := range []codec.Value{codec.IntValue(1), v} {
+		if err := decoder.Decode(&got); err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		if !got.Equal(want) {
+			t.Errorf("Decode = %s, want %s", got, want)
+		}
+	}
+	if err := c.Unmarshal(append(data, 0x01), &got); err == nil {
+		t.Error("Expected an error for trailing data")
+	}
+}
+
+func TestValue_DuplicateKey(t *testing.T) {
+	input := []byte{0xa2, 0x61, 'a', 0x01, 0x61, 'a', 0x02}
+	_, err := codec.DecodeValue(codec.CBOR, bytes.NewReader(input), codec.WithStrict())
+	if !errors.Is(err, codec.ErrDuplicateKey) {
+		t.Errorf("Expected ErrDuplicateKey, got %v", err)
+	}
+}
@@ -0,0 +1,93 @@
+// Package cose signs and encrypts typed payloads as RFC 9052 CBOR Object
+// Signing and Encryption structures. Sign1 produces and verifies
+// COSE_Sign1 messages with ES256 or EdDSA, and Encrypt0 produces and opens
+// COSE_Encrypt0 messages with A256GCM. Both encode the payload with another
+// codec, usually the CBOR codec, and implement codec.Codec[T]:
+//
+//	keys := cose.NewKeyring("device-1", cose.ES256PrivateKey(priv))
+//	c := cose.NewSign1[Claims](cbor.New[Claims](), keys)
+//	msg, err := c.Marshal(claims)
+//
+// The algorithm and the key ID are written to the protected header. When
+// verifying or decrypting, the key is looked up by the key ID and must be
+// of the algorithm named in the header, so a key is never used with another
+// algorithm than its own.
+//
+// Sign1 and Encrypt0 require the codec_cbor build tag; without it every
+// operation returns codec.ErrCodecNotSupported.
+package cose
+
+import (
+	"errors"
+	"fmt"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// errorCodec names the package in the errors it reports
+const errorCodec codec.Type = "cose"
+
+// Algorithm is a COSE algorithm identifier from the IANA "COSE Algorithms"
+// registry
+type Algorithm int64
+
+const (
+	// ES256 is ECDSA with P-256 and SHA-256
+	ES256 Algorithm = -7
+	// EdDSA is Ed25519
+	EdDSA Algorithm = -8
+	// A256GCM is AES-GCM with a 256-bit key and a 128-bit tag
+	A256GCM Algorithm = 3
+)
+
+// String returns the name of the algorithm, e.g. "ES256"
+func (a Algorithm) String() string {
+	switch a {
+	case ES256:
+		return "ES256"
+	case EdDSA:
+		return "EdDSA"
+	case A256GCM:
+		return "A256GCM"
+	default:
+		return fmt.Sprintf("Algorithm(%d)", int64(a))
+	}
+}
+
+// ErrAlgorithmNotSupported is returned for an algorithm this package does
+// not implement, or that does not apply to the operation
+type ErrAlgorithmNotSupported struct {
+	Algorithm Algorithm
+}
+
+func (e ErrAlgorithmNotSupported) Error() string {
+	return fmt.Sprintf("cose: algorithm %s is not supported", e.Algorithm)
+}
+
+// ErrInvalidSignature is reported, wrapped in a codec.DecodeError, for a
+// COSE_Sign1 message whose headers, payload or signature were modified, or
+// that was signed with another key or algorithm than the key named by
+// KeyID
+type ErrInvalidSignature struct {
+	KeyID     string
+	Algorithm Algorithm
+}
+
+func (e ErrInvalidSignature) Error() string {
+	return fmt.Sprintf("cose: invalid %s signature with key %q", e.Algorithm, e.KeyID)
+}
+
+// ErrAuthenticationFailed is reported, wrapped in a codec.DecodeError, for
+// a COSE_Encrypt0 message whose headers or ciphertext were modified, or
+// that was encrypted with another key or external additional data
+type ErrAuthenticationFailed struct {
+	KeyID string
+}
+
+func (e ErrAuthenticationFailed) Error() string {
+	return fmt.Sprintf("cose: message authentication failed with key %q", e.KeyID)
+}
+
+// errCriticalHeaders is reported for messages whose protected header lists
+// critical parameters, none of which this package understands
+var errCriticalHeaders = errors.New("cose: unsupported critical header parameters")
@@ -0,0 +1,87 @@
+//go:build !codec_cbor
+
+package cose
+
+import (
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+var errNotSupported = codec.ErrCodecNotSupported{CodecType: codec.CBOR}
+
+// Sign1 is a stub that returns errors when CBOR codec is not compiled in.
+type Sign1[T any] struct{}
+
+// NewSign1 returns a COSE_Sign1 codec stub that will error on all operations.
+func NewSign1[T any](payload codec.Codec[T], keys KeyResolver, opts ...codec.Option) *Sign1[T] {
+	return &Sign1[T]{}
+}
+
+// Err returns an error indicating CBOR codec is not supported.
+func (c *Sign1[T]) Err() error {
+	return errNotSupported
+}
+
+// Encode returns an error indicating CBOR codec is not supported.
+func (c *Sign1[T]) Encode(w io.Writer, data T) error {
+	return errNotSupported
+}
+
+// Decode returns an error indicating CBOR codec is not supported.
+func (c *Sign1[T]) Decode(r io.Reader, data *T) error {
+	return errNotSupported
+}
+
+// Marshal returns an error indicating CBOR codec is not supported.
+func (c *Sign1[T]) Marshal(data T) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+// Unmarshal returns an error indicating CBOR codec is not supported.
+func (c *Sign1[T]) Unmarshal(data []byte, v *T) error {
+	return errNotSupported
+}
+
+// Verify returns an error indicating CBOR codec is not supported.
+func (c *Sign1[T]) Verify(data []byte) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+// Encrypt0 is a stub that returns errors when CBOR codec is not compiled in.
+type Encrypt0[T any] struct{}
+
+// NewEncrypt0 returns a COSE_Encrypt0 codec stub that will error on all operations.
+func NewEncrypt0[T any](payload codec.Codec[T], keys KeyResolver, opts ...codec.Option) *Encrypt0[T] {
+	return &Encrypt0[T]{}
+}
+
+// Err returns an error indicating CBOR codec is not supported.
+func (c *Encrypt0[T]) Err() error {
+	return errNotSupported
+}
+
+// Encode returns an error indicating CBOR codec is not supported.
+func (c *Encrypt0[T]) Encode(w io.Writer, data T) error {
+	return errNotSupported
+}
+
+// Decode returns an error indicating CBOR codec is not supported.
+func (c *Encrypt0[T]) Decode(r io.Reader, data *T) error {
+	return errNotSupported
+}
+
+// Marshal returns an error indicating CBOR codec is not supported.
+func (c *Encrypt0[T]) Marshal(data T) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+// Unmarshal returns an error indicating CBOR codec is not supported.
+func (c *Encrypt0[T]) Unmarshal(data []byte, v *T) error {
+	return errNotSupported
+}
+
+// Decrypt returns an error indicating CBOR codec is not supported.
+func (c *Encrypt0[T]) Decrypt(data []byte) ([]byte, error) {
+	return nil, errNotSupported
+}
@@ -0,0 +1,302 @@
+//go:build codec_cbor
+
+package cose
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"io"
+	"math/big"
+	"testing"
+
+	codec "github.com/jeremyhahn/go-codec"
+	cborcodec "github.com/jeremyhahn/go-codec/pkg/cbor"
+)
+
+type Claims struct {
+	Issuer  string `cbor:"1,keyasint"`
+	Subject string `cbor:"2,keyasint"`
+	Expiry  int64  `cbor:"4,keyasint"`
+}
+
+var testClaims = Claims{Issuer: "coap://as.example.com", Subject: "erikw", Expiry: 1444064944}
+
+// rawCodec passes payload bytes through unchanged
+type rawCodec struct{}
+
+func (rawCodec) Encode(w io.Writer, data []byte) error { _, err := w.Write(data); return err }
+func (rawCodec) Decode(r io.Reader, data *[]byte) error {
+	var err error
+	*data, err = io.ReadAll(r)
+	return err
+}
+func (rawCodec) Marshal(data []byte) ([]byte, error)    { return data, nil }
+func (rawCodec) Unmarshal(data []byte, v *[]byte) error { *v = data; return nil }
+
+func signingKeys(t testing.TB) map[string]Key {
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ed, _ := ed25519.GenerateKey(nil)
+	return map[string]Key{"ES256": ES256PrivateKey(ec), "EdDSA": EdDSAPrivateKey(ed)}
+}
+
+func TestSign1_RoundTrip(t *testing.T) {
+	for name, key := range signingKeys(t) {
+		t.Run(name, func(t *testing.T) {
+			c := NewSign1[Claims](cborcodec.New[Claims](), NewKeyring("11", key))
+			if err := c.Err(); err != nil {
+				t.Fatalf("NewSign1 failed: %v", err)
+			}
+			msg, err := c.Marshal(testClaims)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if msg[0] != 0xd2 {
+				t.Errorf("message starts with %#x, want tag 18 (0xd2)", msg[0])
+			}
+			var got Claims
+			if err := c.Unmarshal(msg, &got); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if got != testClaims {
+				t.Errorf("Unmarshal = %+v, want %+v", got, testClaims)
+			}
+
+			var buf bytes.Buffer
+			if err := c.Encode(&buf, testClaims); err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+			got = Claims{}
+			if err := c.Decode(&buf, &got); err != nil || got != testClaims {
+				t.Errorf("Decode = %+v, %v", got, err)
+			}
+		})
+	}
+}
+
+// TestSign1_RFC9052Example verifies the COSE_Sign1 example of RFC 9052
+// appendix C.2.1, signed with the P-256 key "11" of appendix C.7.1
+func TestSign1_RFC9052Example(t *testing.T) {
+	msg, _ := hex.DecodeString("d28443a10126a10442313154546869732069732074686520636f6e74656e742e" +
+		"58408eb33e4ca31d1c465ab05aac34cc6b23d58fef5c083106c4d25a91aef0b0117e" +
+		"2af9a291aa32e14ab834dc56ed2a223444547e01f11d3b0916e5a4c345cacb36")
+	x, _ := new(big.Int).SetString("bac5b11cad8f99f9c72b05cf4b9e26d244dc189f745228255a219a86d6a09eff", 16)
+	y, _ := new(big.Int).SetString("20138bf82dc1b6d562be0fa54ab7804a3a64b6d72ccfed6b6fb6ed28bbfc117e", 16)
+	pub := &ecdsa.PublicKey{Curve: elliptic.P256(), X: x, Y: y}
+
+	c := NewSign1[[]byte](rawCodec{}, NewKeyring("11", ES256PublicKey(pub)))
+	var payload []byte
+	if err := c.Unmarshal(msg, &payload); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if string(payload) != "This is the content." {
+		t.Errorf("payload = %q", payload)
+	}
+
+	msg[len(msg)-1] ^= 1
+	if err := c.Unmarshal(msg, &payload); !errors.As(err, new(ErrInvalidSignature)) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestSign1_Tampered(t *testing.T) {
+	for name, key := range signingKeys(t) {
+		t.Run(name, func(t *testing.T) {
+			c := NewSign1[[]byte](rawCodec{}, NewKeyring("k", key))
+			msg, _ := c.Marshal([]byte("payload"))
+
+			// Flip a bit of the algorithm-independent protected header and
+			// of the payload
+			for _, i := range []int{bytes.Index(msg, []byte("k")), bytes.Index(msg, []byte("payload"))} {
+				tampered := bytes.Clone(msg)
+				tampered[i] ^= 0x01
+				var v []byte
+				if err := c.Unmarshal(tampered, &v); err == nil {
+					t.Errorf("byte %d: tampered message verified", i)
+				}
+			}
+			tampered := bytes.Clone(msg)
+			tampered[len(tampered)-1] ^= 0x01
+			var v []byte
+			var sigErr ErrInvalidSignature
+			if err := c.Unmarshal(tampered, &v); !errors.As(err, &sigErr) || sigErr.KeyID != "k" {
+				t.Errorf("expected ErrInvalidSignature, got %v", err)
+			}
+		})
+	}
+}
+
+func TestSign1_AlgorithmMismatch(t *testing.T) {
+	keys := signingKeys(t)
+	msg, _ := NewSign1[Claims](cborcodec.New[Claims](), NewKeyring("k", keys["EdDSA"])).Marshal(testClaims)
+
+	// The key named by the message is an ES256 key
+	c := NewSign1[Claims](cborcodec.New[Claims](), NewKeyring("k", keys["ES256"]))
+	var v Claims
+	if err := c.Unmarshal(msg, &v); !errors.As(err, new(ErrInvalidSignature)) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	// Content encryption keys do not sign
+	c = NewSign1[Claims](cborcodec.New[Claims](), NewKeyring("k", A256GCMKey(make([]byte, 32))))
+	if _, err := c.Marshal(testClaims); !errors.As(err, new(ErrAlgorithmNotSupported)) {
+		t.Errorf("expected ErrAlgorithmNotSupported, got %v", err)
+	}
+
+	// Public keys only verify
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	c = NewSign1[Claims](cborcodec.New[Claims](), NewKeyring("k", ES256PublicKey(&ec.PublicKey)))
+	if _, err := c.Marshal(testClaims); err == nil {
+		t.Error("expected an error signing with a public key")
+	}
+}
+
+func TestEncrypt0_RoundTrip(t *testing.T) {
+	keys := NewKeyring("our-secret", A256GCMKey(bytes.Repeat([]byte{7}, 32)))
+	c := NewEncrypt0[Claims](cborcodec.New[Claims](), keys)
+	msg, err := c.Marshal(testClaims)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if msg[0] != 0xd0 {
+		t.Errorf("message starts with %#x, want tag 16 (0xd0)", msg[0])
+	}
+	if bytes.Contains(msg, []byte("erikw")) {
+		t.Error("message contains the plaintext")
+	}
+	var got Claims
+	if err := c.Unmarshal(msg, &got); err != nil || got != testClaims {
+		t.Errorf("Unmarshal = %+v, %v", got, err)
+	}
+
+	keys.Rotate("next", A256GCMKey(bytes.Repeat([]byte{8}, 32)))
+	next, _ := c.Marshal(testClaims)
+	for _, m := range [][]byte{msg, next} {
+		got = Claims{}
+		if err := c.Unmarshal(m, &got); err != nil || got != testClaims {
+			t.Errorf("Unmarshal after rotation = %+v, %v", got, err)
+		}
+	}
+
+	tampered := bytes.Clone(msg)
+	tampered[len(tampered)-1] ^= 0x01
+	var authErr ErrAuthenticationFailed
+	if err := c.Unmarshal(tampered, &got); !errors.As(err, &authErr) || authErr.KeyID != "our-secret" {
+		t.Errorf("expected ErrAuthenticationFailed, got %v", err)
+	}
+
+	// Signing keys do not encrypt
+	c = NewEncrypt0[Claims](cborcodec.New[Claims](), NewKeyring("k", signingKeys(t)["EdDSA"]))
+	if _, err := c.Marshal(testClaims); !errors.As(err, new(ErrAlgorithmNotSupported)) {
+		t.Errorf("expected ErrAlgorithmNotSupported, got %v", err)
+	}
+	c = NewEncrypt0[Claims](cborcodec.New[Claims](), NewKeyring("k", A256GCMKey(make([]byte, 16))))
+	if _, err := c.Marshal(testClaims); err == nil {
+		t.Error("expected an error for a 16-byte key")
+	}
+}
+
+func TestOptions(t *testing.T) {
+	keys := NewKeyring("", A256GCMKey(bytes.Repeat([]byte{7}, 32)))
+	c := NewEncrypt0[Claims](cborcodec.New[Claims](), keys, WithExternalAAD([]byte("context")), WithUntagged())
+	msg, err := c.Marshal(testClaims)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if msg[0] != 0x83 {
+		t.Errorf("untagged message starts with %#x, want an array of 3 (0x83)", msg[0])
+	}
+	var got Claims
+	if err := c.Unmarshal(msg, &got); err != nil || got != testClaims {
+		t.Errorf("Unmarshal = %+v, %v", got, err)
+	}
+	other := NewEncrypt0[Claims](cborcodec.New[Claims](), keys)
+	if err := other.Unmarshal(msg, &got); !errors.As(err, new(ErrAuthenticationFailed)) {
+		t.Errorf("expected ErrAuthenticationFailed without the external AAD, got %v", err)
+	}
+
+	signKeys := NewKeyring("k", signingKeys(t)["EdDSA"])
+	sign := NewSign1[Claims](cborcodec.New[Claims](), signKeys, WithExternalAAD([]byte("context")))
+	signed, _ := sign.Marshal(testClaims)
+	if err := sign.Unmarshal(signed, &got); err != nil {
+		t.Errorf("Unmarshal failed: %v", err)
+	}
+	otherSign := NewSign1[Claims](cborcodec.New[Claims](), signKeys)
+	if err := otherSign.Unmarshal(signed, &got); !errors.As(err, new(ErrInvalidSignature)) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+}
+
+func TestInvalidMessages(t *testing.T) {
+	keys := NewKeyring("k", signingKeys(t)["EdDSA"])
+	sign := NewSign1[Claims](cborcodec.New[Claims](), keys)
+	valid, _ := sign.Marshal(testClaims)
+	encrypt := NewEncrypt0[Claims](cborcodec.New[Claims](), NewKeyring("k", A256GCMKey(make([]byte, 32))))
+	encrypted, _ := encrypt.Marshal(testClaims)
+
+	var v Claims
+	for _, tt := range []struct {
+		name string
+		data []byte
+		kind codec.ErrorKind
+	}{
+		{"not CBOR", []byte{0xff}, codec.ErrSyntax},
+		{"truncated", valid[:len(valid)-3], codec.ErrTruncated},
+		{"wrong tag", encrypted, codec.ErrSyntax},
+		{"trailing data", append(bytes.Clone(valid), 0), codec.ErrSyntax},
+		// [h'a1 02 81 01', {}, h'', h''] lists label 1 as critical
+		{"critical header", []byte{0x84, 0x44, 0xa1, 0x02, 0x81, 0x01, 0xa0, 0x40, 0x40}, codec.ErrSyntax},
+		// [h'', {}, h'', h''] has no algorithm
+		{"no algorithm", []byte{0x84, 0x40, 0xa0, 0x40, 0x40}, codec.ErrSyntax},
+		// [h'a1 01 26', {}, null, h''] has a detached payload
+		{"detached payload", []byte{0x84, 0x43, 0xa1, 0x01, 0x26, 0xa0, 0xf6, 0x40}, codec.ErrSyntax},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var de codec.DecodeError
+			if err := sign.Unmarshal(tt.data, &v); !errors.As(err, &de) || de.Kind != tt.kind {
+				t.Errorf("Unmarshal error = %v, want %s", err, tt.kind)
+			}
+		})
+	}
+
+	// [h'a1 01 03', {5: h'00'}, h''] has a short IV
+	var de codec.DecodeError
+	if err := encrypt.Unmarshal([]byte{0x83, 0x43, 0xa1, 0x01, 0x03, 0xa1, 0x05, 0x41, 0x00, 0x40}, &v); !errors.As(err, &de) {
+		t.Errorf("expected a DecodeError for a short IV, got %v", err)
+	}
+	if err := sign.Decode(bytes.NewReader(nil), &v); err != io.EOF {
+		t.Errorf("Decode of empty input = %v, want io.EOF", err)
+	}
+
+	keys.Remove("k")
+	if err := sign.Unmarshal(valid, &v); !errors.As(err, new(ErrUnknownKey)) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	keys := NewKeyring("k", A256GCMKey(make([]byte, 32)))
+
+	var notSupported codec.ErrOptionNotSupported
+	c := NewEncrypt0[Claims](cborcodec.New[Claims](), keys, codec.WithStrict())
+	if !errors.As(c.Err(), &notSupported) {
+		t.Errorf("expected ErrOptionNotSupported, got %v", c.Err())
+	}
+	if _, err := c.Marshal(testClaims); err != c.Err() {
+		t.Errorf("Marshal error = %v, want %v", err, c.Err())
+	}
+	s := NewSign1[Claims](cborcodec.New[Claims](WithUntagged()), keys)
+	if !errors.As(s.Err(), &notSupported) {
+		t.Errorf("expected the payload codec's error, got %v", s.Err())
+	}
+	if s = NewSign1[Claims](cborcodec.New[Claims](), nil); s.Err() == nil {
+		t.Error("expected an error for a nil KeyResolver")
+	}
+}
@@ -0,0 +1,168 @@
+//go:build codec_cbor
+
+package cose
+
+import (
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// ivSize is the nonce length of A256GCM
+const ivSize = 12
+
+// Encrypt0 encodes values as COSE_Encrypt0 messages encrypted with A256GCM
+type Encrypt0[T any] struct {
+	payload codec.Codec[T]
+	keys    KeyResolver
+	cfg     config
+	err     error
+}
+
+// NewEncrypt0 returns a codec that encodes values with payload and
+// encrypts them with the current key of keys, which must be created with
+// A256GCMKey. If payload reports an error through an Err method, an option
+// does not apply to COSE or keys is nil, every operation returns the error,
+// which is also reported by Err.
+func NewEncrypt0[T any](payload codec.Codec[T], keys KeyResolver, opts ...codec.Option) *Encrypt0[T] {
+	c := &Encrypt0[T]{payload: payload, keys: keys}
+	c.err = newError(payload, keys, &c.cfg, opts)
+	return c
+}
+
+// Err returns the error, if any, caused by the arguments passed to
+// NewEncrypt0
+func (c *Encrypt0[T]) Err() error {
+	return c.err
+}
+
+// Encode writes data to w as a COSE_Encrypt0 message
+func (c *Encrypt0[T]) Encode(w io.Writer, data T) error {
+	out, err := c.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// Decode reads a COSE_Encrypt0 message from r, decrypts it and decodes its
+// payload into data. It may read past the end of the message.
+func (c *Encrypt0[T]) Decode(r io.Reader, data *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	msg, err := readMessage(r)
+	if err != nil {
+		return err
+	}
+	return c.Unmarshal(msg, data)
+}
+
+// Marshal encodes data with the payload codec and returns it encrypted as
+// a COSE_Encrypt0 message with a random IV
+func (c *Encrypt0[T]) Marshal(data T) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	plaintext, err := c.payload.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	id, key, err := c.keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := key.aead()
+	if err != nil {
+		return nil, err
+	}
+	protected, err := protectedHeader(key.algo, id)
+	if err != nil {
+		return nil, err
+	}
+	aad, err := toBeSigned(contextEncrypt0, protected, c.cfg.externalAAD)
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, ivSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+	msg := encrypt0Message{
+		Protected:   protected,
+		Unprotected: headers{IV: iv},
+		Ciphertext:  aead.Seal(nil, iv, plaintext, aad),
+	}
+	return marshalMessage(tagEncrypt0, msg, c.cfg.untagged)
+}
+
+// Unmarshal decrypts the COSE_Encrypt0 message in data with the key named
+// by its key ID and decodes its payload into v
+func (c *Encrypt0[T]) Unmarshal(data []byte, v *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	plaintext, err := c.Decrypt(data)
+	if err != nil {
+		return err
+	}
+	return c.payload.Unmarshal(plaintext, v)
+}
+
+// Decrypt authenticates and decrypts the COSE_Encrypt0 message in data and
+// returns its payload without decoding it
+func (c *Encrypt0[T]) Decrypt(data []byte) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	var msg encrypt0Message
+	if err := unmarshalMessage(data, tagEncrypt0, &msg); err != nil {
+		return nil, err
+	}
+	protected, err := parseProtected(msg.Protected)
+	if err != nil {
+		return nil, err
+	}
+	if protected.Alg != A256GCM {
+		return nil, codec.DecodeError{Codec: errorCodec, Kind: codec.ErrSyntax, Offset: -1, Err: ErrAlgorithmNotSupported{Algorithm: protected.Alg}}
+	}
+	iv := msg.Unprotected.IV
+	if protected.IV != nil {
+		iv = protected.IV
+	}
+	if len(iv) != ivSize {
+		return nil, codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: -1,
+			Err:    fmt.Errorf("cose: IV is %d bytes, want %d", len(iv), ivSize),
+		}
+	}
+
+	id := keyID(protected, msg.Unprotected)
+	key, err := c.keys.Key(id)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := key.aead()
+	if err != nil {
+		return nil, err
+	}
+	aad, err := toBeSigned(contextEncrypt0, msg.Protected, c.cfg.externalAAD)
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, iv, msg.Ciphertext, aad)
+	if err != nil {
+		return nil, codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: -1,
+			Err:    ErrAuthenticationFailed{KeyID: id},
+		}
+	}
+	return plaintext, nil
+}
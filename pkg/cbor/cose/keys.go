@@ -0,0 +1,210 @@
+package cose
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+)
+
+// Key is a signing, verification or content encryption key together with
+// its algorithm. Create one with ES256PrivateKey, ES256PublicKey,
+// EdDSAPrivateKey, EdDSAPublicKey or A256GCMKey.
+type Key struct {
+	algo      Algorithm
+	ecPrivate *ecdsa.PrivateKey
+	ecPublic  *ecdsa.PublicKey
+	edPrivate ed25519.PrivateKey
+	edPublic  ed25519.PublicKey
+	secret    []byte
+}
+
+// ES256PrivateKey returns a key that signs with priv, which must be on the
+// P-256 curve, and verifies with its public key
+func ES256PrivateKey(priv *ecdsa.PrivateKey) Key {
+	k := Key{algo: ES256, ecPrivate: priv}
+	if priv != nil {
+		k.ecPublic = &priv.PublicKey
+	}
+	return k
+}
+
+// ES256PublicKey returns a key that only verifies ES256 signatures
+func ES256PublicKey(pub *ecdsa.PublicKey) Key {
+	return Key{algo: ES256, ecPublic: pub}
+}
+
+// EdDSAPrivateKey returns a key that signs with priv and verifies with its
+// public key
+func EdDSAPrivateKey(priv ed25519.PrivateKey) Key {
+	k := Key{algo: EdDSA, edPrivate: priv}
+	if len(priv) == ed25519.PrivateKeySize {
+		k.edPublic = priv.Public().(ed25519.PublicKey)
+	}
+	return k
+}
+
+// EdDSAPublicKey returns a key that only verifies EdDSA signatures
+func EdDSAPublicKey(pub ed25519.PublicKey) Key {
+	return Key{algo: EdDSA, edPublic: pub}
+}
+
+// A256GCMKey returns a 32-byte content encryption key for A256GCM
+func A256GCMKey(key []byte) Key {
+	return Key{algo: A256GCM, secret: key}
+}
+
+// Algorithm returns the algorithm of the key
+func (k Key) Algorithm() Algorithm {
+	return k.algo
+}
+
+// sign returns the signature of the ToBeSigned bytes tbs
+func (k Key) sign(tbs []byte) ([]byte, error) {
+	switch k.algo {
+	case ES256:
+		if k.ecPrivate == nil || k.ecPrivate.Curve != elliptic.P256() {
+			return nil, errors.New("cose: key cannot sign without a P-256 private key")
+		}
+		digest := sha256.Sum256(tbs)
+		r, s, err := ecdsa.Sign(rand.Reader, k.ecPrivate, digest[:])
+		if err != nil {
+			return nil, err
+		}
+		// RFC 9053 section 2.1: the signature is r and s as 32-byte
+		// big-endian integers, not an ASN.1 structure
+		sig := make([]byte, 64)
+		r.FillBytes(sig[:32])
+		s.FillBytes(sig[32:])
+		return sig, nil
+	case EdDSA:
+		if len(k.edPrivate) != ed25519.PrivateKeySize {
+			return nil, errors.New("cose: key cannot sign without an Ed25519 private key")
+		}
+		return ed25519.Sign(k.edPrivate, tbs), nil
+	default:
+		return nil, ErrAlgorithmNotSupported{Algorithm: k.algo}
+	}
+}
+
+// verify reports whether sig is a valid signature of the ToBeSigned bytes
+// tbs
+func (k Key) verify(tbs, sig []byte) bool {
+	switch k.algo {
+	case ES256:
+		if k.ecPublic == nil || k.ecPublic.Curve != elliptic.P256() || len(sig) != 64 {
+			return false
+		}
+		digest := sha256.Sum256(tbs)
+		r := new(big.Int).SetBytes(sig[:32])
+		s := new(big.Int).SetBytes(sig[32:])
+		return ecdsa.Verify(k.ecPublic, digest[:], r, s)
+	case EdDSA:
+		return len(k.edPublic) == ed25519.PublicKeySize && ed25519.Verify(k.edPublic, tbs, sig)
+	default:
+		return false
+	}
+}
+
+// aead returns the content encryption cipher of the key
+func (k Key) aead() (cipher.AEAD, error) {
+	if k.algo != A256GCM {
+		return nil, ErrAlgorithmNotSupported{Algorithm: k.algo}
+	}
+	if len(k.secret) != 32 {
+		return nil, fmt.Errorf("cose: A256GCM key is %d bytes, want 32", len(k.secret))
+	}
+	block, err := aes.NewCipher(k.secret)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// KeyResolver supplies the keys used to produce and consume messages. New
+// messages use the current key, and received messages are verified or
+// decrypted with the key named by their key ID, so keys can be rotated
+// while earlier messages remain readable. A key ID of "" is not written to
+// messages, and is looked up for messages without one.
+type KeyResolver interface {
+	// CurrentKey returns the ID and the key used for new messages
+	CurrentKey() (id string, key Key, err error)
+
+	// Key returns the key with the given ID
+	Key(id string) (Key, error)
+}
+
+// ErrUnknownKey is returned by a Keyring for a key ID it does not hold
+type ErrUnknownKey struct {
+	KeyID string
+}
+
+func (e ErrUnknownKey) Error() string {
+	return fmt.Sprintf("cose: unknown key %q", e.KeyID)
+}
+
+// Keyring is a KeyResolver holding keys in memory. It is safe for
+// concurrent use.
+type Keyring struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string]Key
+}
+
+// NewKeyring returns a Keyring whose current key is key, identified by id
+func NewKeyring(id string, key Key) *Keyring {
+	return &Keyring{current: id, keys: map[string]Key{id: key}}
+}
+
+// Add adds a key that reads messages without becoming the current key
+func (k *Keyring) Add(id string, key Key) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[id] = key
+}
+
+// Rotate adds a key and makes it the current key. Earlier keys are kept to
+// read the messages produced with them until they are removed.
+func (k *Keyring) Rotate(id string, key Key) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[id] = key
+	k.current = id
+}
+
+// Remove removes a key. Removing the current key leaves the Keyring unable
+// to produce messages until another key is rotated in.
+func (k *Keyring) Remove(id string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.keys, id)
+}
+
+// CurrentKey returns the ID and the key used for new messages
+func (k *Keyring) CurrentKey() (string, Key, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[k.current]
+	if !ok {
+		return "", Key{}, ErrUnknownKey{KeyID: k.current}
+	}
+	return k.current, key, nil
+}
+
+// Key returns the key with the given ID
+func (k *Keyring) Key(id string) (Key, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[id]
+	if !ok {
+		return Key{}, ErrUnknownKey{KeyID: id}
+	}
+	return key, nil
+}
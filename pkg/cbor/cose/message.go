@@ -0,0 +1,189 @@
+//go:build codec_cbor
+
+package cose
+
+import (
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/fxamacker/cbor/v2"
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// CBOR tags of the message types, from RFC 9052 section 2
+const (
+	tagEncrypt0 = 16
+	tagSign1    = 18
+)
+
+// Context strings of the structures that are signed or authenticated
+const (
+	contextSignature1 = "Signature1"
+	contextEncrypt0   = "Encrypt0"
+)
+
+// encMode encodes headers and the structures to be signed with the core
+// deterministic encoding of RFC 8949 section 4.2.1, so that they are
+// reproducible by any implementation
+var encMode, _ = cbor.CoreDetEncOptions().EncMode()
+
+// decMode rejects duplicate header labels as required by RFC 9052 section 3
+var decMode, _ = cbor.DecOptions{DupMapKey: cbor.DupMapKeyEnforcedAPF}.DecMode()
+
+// headers holds the header parameters this package reads and writes.
+// Parameters with other labels are ignored.
+type headers struct {
+	Alg  Algorithm `cbor:"1,keyasint,omitempty"`
+	Crit []any     `cbor:"2,keyasint,omitempty"`
+	Kid  []byte    `cbor:"4,keyasint,omitempty"`
+	IV   []byte    `cbor:"5,keyasint,omitempty"`
+}
+
+// sign1Message is the COSE_Sign1 structure
+type sign1Message struct {
+	_           struct{} `cbor:",toarray"`
+	Protected   []byte
+	Unprotected headers
+	Payload     []byte
+	Signature   []byte
+}
+
+// encrypt0Message is the COSE_Encrypt0 structure
+type encrypt0Message struct {
+	_           struct{} `cbor:",toarray"`
+	Protected   []byte
+	Unprotected headers
+	Ciphertext  []byte
+}
+
+// newError returns the error, if any, caused by the arguments of NewSign1
+// or NewEncrypt0
+func newError(payload any, keys KeyResolver, cfg *config, opts []codec.Option) error {
+	if e, ok := payload.(interface{ Err() error }); ok {
+		if err := e.Err(); err != nil {
+			return err
+		}
+	}
+	if err := codec.ApplyOptions(errorCodec, cfg, opts); err != nil {
+		return err
+	}
+	if keys == nil {
+		return errors.New("cose: nil KeyResolver")
+	}
+	return nil
+}
+
+// protectedHeader returns the encoded protected header for algo and id
+func protectedHeader(algo Algorithm, id string) ([]byte, error) {
+	h := headers{Alg: algo}
+	if id != "" {
+		h.Kid = []byte(id)
+	}
+	return encMode.Marshal(h)
+}
+
+// parseProtected decodes a protected header, rejecting critical parameters
+// and a missing algorithm
+func parseProtected(protected []byte) (headers, error) {
+	var h headers
+	if len(protected) > 0 {
+		if err := decMode.Unmarshal(protected, &h); err != nil {
+			return headers{}, decodeError(err)
+		}
+	}
+	if len(h.Crit) > 0 {
+		return headers{}, codec.DecodeError{Codec: errorCodec, Kind: codec.ErrSyntax, Offset: -1, Err: errCriticalHeaders}
+	}
+	if h.Alg == 0 {
+		return headers{}, codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: -1,
+			Err:    errors.New("cose: protected header has no algorithm"),
+		}
+	}
+	return h, nil
+}
+
+// keyID returns the key ID of a message, preferring the protected header
+func keyID(protected, unprotected headers) string {
+	if protected.Kid != nil {
+		return string(protected.Kid)
+	}
+	return string(unprotected.Kid)
+}
+
+// toBeSigned returns the encoded Sig_structure or Enc_structure of
+// RFC 9052 sections 4.4 and 5.3. Absent byte strings are encoded empty.
+func toBeSigned(context string, fields ...[]byte) ([]byte, error) {
+	structure := make([]any, 0, 1+len(fields))
+	structure = append(structure, context)
+	for _, f := range fields {
+		if f == nil {
+			f = []byte{}
+		}
+		structure = append(structure, f)
+	}
+	return encMode.Marshal(structure)
+}
+
+// marshalMessage encodes msg, wrapped in tag unless untagged is set
+func marshalMessage(tag uint64, msg any, untagged bool) ([]byte, error) {
+	if untagged {
+		return encMode.Marshal(msg)
+	}
+	return encMode.Marshal(cbor.Tag{Number: tag, Content: msg})
+}
+
+// unmarshalMessage decodes a message that is either untagged or wrapped in
+// tag into msg
+func unmarshalMessage(data []byte, tag uint64, msg any) error {
+	content := data
+	if len(data) > 0 && data[0]>>5 == 6 {
+		var raw cbor.RawTag
+		if err := decMode.Unmarshal(data, &raw); err != nil {
+			return decodeError(err)
+		}
+		if raw.Number != tag {
+			return codec.DecodeError{
+				Codec:  errorCodec,
+				Kind:   codec.ErrSyntax,
+				Offset: 0,
+				Err:    fmt.Errorf("cose: CBOR tag %d, want %d", raw.Number, tag),
+			}
+		}
+		content = raw.Content
+	}
+	if err := decMode.Unmarshal(content, msg); err != nil {
+		return decodeError(err)
+	}
+	return nil
+}
+
+// readMessage reads one CBOR data item from r. The decoder buffers its
+// input, so it may read past the end of the message.
+func readMessage(r io.Reader) ([]byte, error) {
+	var raw cbor.RawMessage
+	if err := decMode.NewDecoder(r).Decode(&raw); err != nil {
+		if err == io.EOF {
+			return nil, err
+		}
+		return nil, decodeError(err)
+	}
+	return raw, nil
+}
+
+// decodeError wraps a CBOR decoding failure in a codec.DecodeError, leaving
+// errors that already are one unchanged
+func decodeError(err error) error {
+	var de codec.DecodeError
+	if errors.As(err, &de) {
+		return err
+	}
+	kind := codec.ErrSyntax
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		kind = codec.ErrTruncated
+	}
+	return codec.DecodeError{Codec: errorCodec, Kind: kind, Offset: -1, Err: err}
+}
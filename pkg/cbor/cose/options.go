@@ -0,0 +1,27 @@
+package cose
+
+import codec "github.com/jeremyhahn/go-codec"
+
+// config holds the settings applied by COSE options
+type config struct {
+	externalAAD []byte
+	untagged    bool
+}
+
+// WithExternalAAD authenticates data supplied by the application alongside
+// every message without including it, as described in RFC 9052 section 4.3.
+// Messages only verify or decrypt with the same external data.
+func WithExternalAAD(aad []byte) codec.Option {
+	return codec.NewOption("cose.WithExternalAAD", func(c *config) {
+		c.externalAAD = aad
+	})
+}
+
+// WithUntagged writes messages without the COSE_Sign1 or COSE_Encrypt0 CBOR
+// tag, for protocols that identify the message type by context. Tagged and
+// untagged messages are both accepted when decoding.
+func WithUntagged() codec.Option {
+	return codec.NewOption("cose.WithUntagged", func(c *config) {
+		c.untagged = true
+	})
+}
@@ -0,0 +1,145 @@
+//go:build codec_cbor
+
+package cose
+
+import (
+	"errors"
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// Sign1 encodes values as COSE_Sign1 messages signed with ES256 or EdDSA
+type Sign1[T any] struct {
+	payload codec.Codec[T]
+	keys    KeyResolver
+	cfg     config
+	err     error
+}
+
+// NewSign1 returns a codec that encodes values with payload and signs them
+// with the current key of keys. If payload reports an error through an Err
+// method, an option does not apply to COSE or keys is nil, every operation
+// returns the error, which is also reported by Err.
+func NewSign1[T any](payload codec.Codec[T], keys KeyResolver, opts ...codec.Option) *Sign1[T] {
+	c := &Sign1[T]{payload: payload, keys: keys}
+	c.err = newError(payload, keys, &c.cfg, opts)
+	return c
+}
+
+// Err returns the error, if any, caused by the arguments passed to NewSign1
+func (c *Sign1[T]) Err() error {
+	return c.err
+}
+
+// Encode writes data to w as a COSE_Sign1 message
+func (c *Sign1[T]) Encode(w io.Writer, data T) error {
+	out, err := c.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// Decode reads a COSE_Sign1 message from r, verifies it and decodes its
+// payload into data. It may read past the end of the message.
+func (c *Sign1[T]) Decode(r io.Reader, data *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	msg, err := readMessage(r)
+	if err != nil {
+		return err
+	}
+	return c.Unmarshal(msg, data)
+}
+
+// Marshal encodes data with the payload codec and returns it signed as a
+// COSE_Sign1 message
+func (c *Sign1[T]) Marshal(data T) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	payload, err := c.payload.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	id, key, err := c.keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if key.algo != ES256 && key.algo != EdDSA {
+		return nil, ErrAlgorithmNotSupported{Algorithm: key.algo}
+	}
+	protected, err := protectedHeader(key.algo, id)
+	if err != nil {
+		return nil, err
+	}
+	tbs, err := toBeSigned(contextSignature1, protected, c.cfg.externalAAD, payload)
+	if err != nil {
+		return nil, err
+	}
+	sig, err := key.sign(tbs)
+	if err != nil {
+		return nil, err
+	}
+	msg := sign1Message{Protected: protected, Payload: payload, Signature: sig}
+	return marshalMessage(tagSign1, msg, c.cfg.untagged)
+}
+
+// Unmarshal verifies the COSE_Sign1 message in data with the key named by
+// its key ID and decodes its payload into v
+func (c *Sign1[T]) Unmarshal(data []byte, v *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	payload, err := c.Verify(data)
+	if err != nil {
+		return err
+	}
+	return c.payload.Unmarshal(payload, v)
+}
+
+// Verify checks the COSE_Sign1 message in data and returns its payload
+// without decoding it
+func (c *Sign1[T]) Verify(data []byte) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	var msg sign1Message
+	if err := unmarshalMessage(data, tagSign1, &msg); err != nil {
+		return nil, err
+	}
+	protected, err := parseProtected(msg.Protected)
+	if err != nil {
+		return nil, err
+	}
+	if msg.Payload == nil {
+		return nil, codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: -1,
+			Err:    errors.New("cose: detached payloads are not supported"),
+		}
+	}
+
+	id := keyID(protected, msg.Unprotected)
+	key, err := c.keys.Key(id)
+	if err != nil {
+		return nil, err
+	}
+	tbs, err := toBeSigned(contextSignature1, msg.Protected, c.cfg.externalAAD, msg.Payload)
+	if err != nil {
+		return nil, err
+	}
+	if key.algo != protected.Alg || !key.verify(tbs, msg.Signature) {
+		return nil, codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: -1,
+			Err:    ErrInvalidSignature{KeyID: id, Algorithm: protected.Alg},
+		}
+	}
+	return msg.Payload, nil
+}
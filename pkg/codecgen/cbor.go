@@ -0,0 +1,430 @@
+package codecgen
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/binary"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// Decoding limits of the cbor library's default options
+const (
+	maxCBORDepth = 32
+	maxCBORItems = 131072
+)
+
+// CBOR major types
+const (
+	cborUint   = 0 << 5
+	cborNegInt = 1 << 5
+	cborBytes  = 2 << 5
+	cborText   = 3 << 5
+	cborArray  = 4 << 5
+	cborMap    = 5 << 5
+	cborSimple = 7 << 5
+)
+
+// appendCBORHead appends the head of a data item of the given major type
+// and argument in its shortest form
+func appendCBORHead(buf []byte, major byte, n uint64) []byte {
+	switch {
+	case n < 24:
+		return append(buf, major|byte(n))
+	case n <= math.MaxUint8:
+		return append(buf, major|24, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, major|25), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, major|26), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, major|27), n)
+	}
+}
+
+// AppendCBORNull appends null
+func AppendCBORNull(buf []byte) []byte {
+	return append(buf, 0xf6)
+}
+
+// AppendCBORBool appends b
+func AppendCBORBool(buf []byte, b bool) []byte {
+	if b {
+		return append(buf, 0xf5)
+	}
+	return append(buf, 0xf4)
+}
+
+// AppendCBORInt appends n in its shortest form
+func AppendCBORInt(buf []byte, n int64) []byte {
+	if n < 0 {
+		return appendCBORHead(buf, cborNegInt, uint64(-1-n))
+	}
+	return appendCBORHead(buf, cborUint, uint64(n))
+}
+
+// AppendCBORUint appends n in its shortest form
+func AppendCBORUint(buf []byte, n uint64) []byte {
+	return appendCBORHead(buf, cborUint, n)
+}
+
+// AppendCBORFloat32 appends f as a single-precision float, writing NaN and
+// infinities as half-precision floats as the cbor library does by default
+func AppendCBORFloat32(buf []byte, f float32) []byte {
+	if special, ok := cborSpecialFloat(float64(f)); ok {
+		return append(buf, special...)
+	}
+	return binary.BigEndian.AppendUint32(append(buf, 0xfa), math.Float32bits(f))
+}
+
+// AppendCBORFloat64 appends f as a double-precision float, writing NaN and
+// infinities as half-precision floats as the cbor library does by default
+func AppendCBORFloat64(buf []byte, f float64) []byte {
+	if special, ok := cborSpecialFloat(f); ok {
+		return append(buf, special...)
+	}
+	return binary.BigEndian.AppendUint64(append(buf, 0xfb), math.Float64bits(f))
+}
+
+// cborSpecialFloat returns the encoding of NaN and the infinities
+func cborSpecialFloat(f float64) (string, bool) {
+	switch {
+	case math.IsNaN(f):
+		return "\xf9\x7e\x00", true
+	case math.IsInf(f, 1):
+		return "\xf9\x7c\x00", true
+	case math.IsInf(f, -1):
+		return "\xf9\xfc\x00", true
+	}
+	return "", false
+}
+
+// AppendCBORString appends s as a text string
+func AppendCBORString(buf []byte, s string) []byte {
+	return append(appendCBORHead(buf, cborText, uint64(len(s))), s...)
+}
+
+// AppendCBORBytes appends b as a byte string, or null if b is nil
+func AppendCBORBytes(buf, b []byte) []byte {
+	if b == nil {
+		return AppendCBORNull(buf)
+	}
+	return append(appendCBORHead(buf, cborBytes, uint64(len(b))), b...)
+}
+
+// AppendCBORArrayLen appends the head of an array of n items
+func AppendCBORArrayLen(buf []byte, n int) []byte {
+	return appendCBORHead(buf, cborArray, uint64(n))
+}
+
+// AppendCBORMapLen appends the head of a map of n pairs
+func AppendCBORMapLen(buf []byte, n int) []byte {
+	return appendCBORHead(buf, cborMap, uint64(n))
+}
+
+// SortedCBORKeys returns the keys of m in length-first order, the order in
+// which the cbor library writes them when it sorts map keys. By default it
+// writes map entries in random order, of which generated code uses this
+// one.
+func SortedCBORKeys[M ~map[string]V, V any](m M) []string {
+	return slices.SortedFunc(maps.Keys(m), func(a, b string) int {
+		if n := cmp.Compare(len(a), len(b)); n != 0 {
+			return n
+		}
+		return strings.Compare(a, b)
+	})
+}
+
+// ResizeCBOR returns s with length n, reusing it as the cbor library does
+// when it decodes an array into an existing slice
+func ResizeCBOR[S ~[]E, E any](s S, n int) S {
+	if s == nil || cap(s) < n || n == 0 {
+		return make(S, n)
+	}
+	return s[:n]
+}
+
+// CBORReader reads CBOR for generated code with the semantics of the cbor
+// library's default decoding options. Indefinite lengths, tags and
+// half-precision floats are left to the library. Its methods record the
+// first error and return zero values after it, so generated code checks
+// for an error only at the end. Reset sets the input.
+type CBORReader struct {
+	data  []byte
+	off   int
+	depth int
+	err   error
+}
+
+// Reset makes r read data from the beginning
+func (r *CBORReader) Reset(data []byte) {
+	*r = CBORReader{data: data}
+}
+
+// End returns the first error, or an error if input follows the value
+// that was read
+func (r *CBORReader) End() error {
+	if r.err == nil && r.off != len(r.data) {
+		r.fail()
+	}
+	return r.err
+}
+
+// Null reads null if it is next and reports whether it did
+func (r *CBORReader) Null() bool {
+	if r.err != nil || r.off >= len(r.data) || r.data[r.off] != 0xf6 {
+		return false
+	}
+	r.off++
+	return true
+}
+
+// MapLen reads the head of a map and returns its number of pairs. Leave
+// must follow the pairs.
+func (r *CBORReader) MapLen() int {
+	return r.container(cborMap, 2)
+}
+
+// ArrayLen reads the head of an array and returns its number of items.
+// Leave must follow the items.
+func (r *CBORReader) ArrayLen() int {
+	return r.container(cborArray, 1)
+}
+
+// Leave ends the map or array entered by MapLen or ArrayLen
+func (r *CBORReader) Leave() {
+	r.depth--
+}
+
+func (r *CBORReader) container(major byte, size int) int {
+	n := r.head(major)
+	if r.depth++; r.depth > maxCBORDepth || n > maxCBORItems || n > uint64(len(r.data)-r.off)/uint64(size) {
+		r.fail()
+		return 0
+	}
+	return int(n)
+}
+
+// Key reads a text string map key. The result refers to the input.
+func (r *CBORReader) Key() []byte {
+	n := r.head(cborText)
+	b := r.read(n)
+	if r.err == nil && !utf8.Valid(b) {
+		r.fail()
+		return nil
+	}
+	return b
+}
+
+// Field records that the struct field marked by seen has been read. The
+// cbor library decodes only the first of duplicate keys, which generated
+// code leaves to it by failing.
+func (r *CBORReader) Field(seen *bool) {
+	if *seen {
+		r.fail()
+	}
+	*seen = true
+}
+
+// Unknown skips the value of a map key that matched no field exactly. The
+// cbor library would decode a key matching one of names without regard to
+// case, which generated code leaves to it by failing.
+func (r *CBORReader) Unknown(key []byte, names ...string) {
+	for _, name := range names {
+		if bytes.EqualFold(key, []byte(name)) {
+			r.fail()
+			return
+		}
+	}
+	r.Skip()
+}
+
+// String reads a text string
+func (r *CBORReader) String() string {
+	return string(r.Key())
+}
+
+// Bytes reads a byte string into a new slice
+func (r *CBORReader) Bytes() []byte {
+	n := r.head(cborBytes)
+	b := r.read(n)
+	if r.err != nil {
+		return nil
+	}
+	return append(make([]byte, 0, len(b)), b...)
+}
+
+// Bool reads true or false
+func (r *CBORReader) Bool() bool {
+	switch r.next() {
+	case 0xf4:
+		return false
+	case 0xf5:
+		return true
+	}
+	r.fail()
+	return false
+}
+
+// Int reads an integer into a signed integer of the given bit size, where
+// 0 is the size of int
+func (r *CBORReader) Int(bits int) int64 {
+	if bits == 0 {
+		bits = strconv.IntSize
+	}
+	c := r.peek()
+	n := r.head(c & 0xe0)
+	if r.err != nil || n > math.MaxInt64 || c&0xe0 > cborNegInt {
+		r.fail()
+		return 0
+	}
+	v := int64(n)
+	if c&0xe0 == cborNegInt {
+		v = -1 - v
+	}
+	if shift := 64 - uint(bits); v<<shift>>shift != v {
+		r.fail()
+		return 0
+	}
+	return v
+}
+
+// Uint reads an unsigned integer into an unsigned integer of the given bit
+// size, where 0 is the size of uint
+func (r *CBORReader) Uint(bits int) uint64 {
+	if bits == 0 {
+		bits = strconv.IntSize
+	}
+	n := r.head(cborUint)
+	if r.err != nil || bits < 64 && n>>uint(bits) != 0 {
+		r.fail()
+		return 0
+	}
+	return n
+}
+
+// Float reads a single- or double-precision float into a float of the
+// given bit size
+func (r *CBORReader) Float(bits int) float64 {
+	var f float64
+	switch r.next() {
+	case 0xfa:
+		f = float64(math.Float32frombits(uint32(r.uint(4))))
+	case 0xfb:
+		f = math.Float64frombits(r.uint(8))
+	default:
+		r.fail()
+		return 0
+	}
+	if abs := math.Abs(f); bits == 32 && abs > math.MaxFloat32 && abs <= math.MaxFloat64 {
+		r.fail()
+		return 0
+	}
+	return f
+}
+
+// Skip reads and discards a value
+func (r *CBORReader) Skip() {
+	c := r.peek()
+	if r.err != nil {
+		return
+	}
+	switch major := c & 0xe0; major {
+	case cborUint, cborNegInt:
+		r.head(major)
+	case cborBytes, cborText:
+		r.read(r.head(major))
+	case cborArray:
+		r.skipItems(r.ArrayLen())
+	case cborMap:
+		r.skipItems(2 * r.MapLen())
+	case cborSimple:
+		r.off++
+		switch ai := c & 0x1f; {
+		case ai < 24:
+		case ai == 24:
+			if r.uint(1) < 32 {
+				r.fail()
+			}
+		case ai <= 27:
+			r.read(1 << (ai - 24))
+		default:
+			r.fail()
+		}
+	default:
+		r.fail()
+	}
+}
+
+func (r *CBORReader) skipItems(n int) {
+	for i := 0; i < n && r.err == nil; i++ {
+		r.Skip()
+	}
+	r.Leave()
+}
+
+func (r *CBORReader) fail() {
+	if r.err == nil {
+		r.err = ErrFallback
+	}
+}
+
+// peek returns the initial byte of the next data item without reading it
+func (r *CBORReader) peek() byte {
+	if r.err != nil || r.off >= len(r.data) {
+		r.fail()
+		return 0
+	}
+	return r.data[r.off]
+}
+
+// next reads the initial byte of the next data item
+func (r *CBORReader) next() byte {
+	c := r.peek()
+	if r.err == nil {
+		r.off++
+	}
+	return c
+}
+
+// head reads the head of a data item of the given major type with a
+// definite argument and returns the argument
+func (r *CBORReader) head(major byte) uint64 {
+	c := r.next()
+	if r.err != nil || c&0xe0 != major {
+		r.fail()
+		return 0
+	}
+	switch ai := c & 0x1f; {
+	case ai < 24:
+		return uint64(ai)
+	case ai <= 27:
+		return r.uint(1 << (ai - 24))
+	}
+	r.fail()
+	return 0
+}
+
+// read returns the next n bytes of input
+func (r *CBORReader) read(n uint64) []byte {
+	if r.err != nil || n > uint64(len(r.data)-r.off) {
+		r.fail()
+		return nil
+	}
+	b := r.data[r.off : r.off+int(n)]
+	r.off += int(n)
+	return b
+}
+
+// uint reads a big-endian unsigned integer of n bytes
+func (r *CBORReader) uint(n int) uint64 {
+	var v uint64
+	for _, c := range r.read(uint64(n)) {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
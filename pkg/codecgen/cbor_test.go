@@ -0,0 +1,145 @@
+package codecgen
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/fxamacker/cbor/v2"
+)
+
+// cborOf returns the encoding of v by the cbor library
+func cborOf(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := cbor.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestAppendCBOR(t *testing.T) {
+	for _, n := range testInts {
+		if got, want := AppendCBORInt(nil, n), cborOf(t, n); !bytes.Equal(got, want) {
+			t.Errorf("int %d: got %x, want %x", n, got, want)
+		}
+		if got, want := AppendCBORUint(nil, uint64(n)), cborOf(t, uint64(n)); !bytes.Equal(got, want) {
+			t.Errorf("uint %d: got %x, want %x", uint64(n), got, want)
+		}
+	}
+	for _, f := range []float64{
+		0, math.Copysign(0, -1), 1.5, 65504, 1e-7, math.MaxFloat64, math.SmallestNonzeroFloat64,
+		math.Inf(1), math.Inf(-1), math.NaN(),
+	} {
+		if got, want := AppendCBORFloat64(nil, f), cborOf(t, f); !bytes.Equal(got, want) {
+			t.Errorf("float64 %v: got %x, want %x", f, got, want)
+		}
+		if got, want := AppendCBORFloat32(nil, float32(f)), cborOf(t, float32(f)); !bytes.Equal(got, want) {
+			t.Errorf("float32 %v: got %x, want %x", f, got, want)
+		}
+	}
+	for _, n := range []int{0, 23, 24, 255, 256, 65535, 65536} {
+		s := string(bytes.Repeat([]byte("s"), n))
+		if got, want := AppendCBORString(nil, s), cborOf(t, s); !bytes.Equal(got, want) {
+			t.Errorf("string of %d: got %x, want %x", n, got[:min(len(got), 8)], want[:min(len(want), 8)])
+		}
+		b := []byte(s)
+		if got, want := AppendCBORBytes(nil, b), cborOf(t, b); !bytes.Equal(got, want) {
+			t.Errorf("bytes of %d: got %x, want %x", n, got[:min(len(got), 8)], want[:min(len(want), 8)])
+		}
+		if got, want := AppendCBORArrayLen(nil, n), cborOf(t, make([]bool, n)); !bytes.HasPrefix(want, got) {
+			t.Errorf("array of %d: got %x", n, got)
+		}
+	}
+	if got, want := AppendCBORBytes(nil, nil), cborOf(t, []byte(nil)); !bytes.Equal(got, want) {
+		t.Errorf("nil bytes: got %x, want %x", got, want)
+	}
+	if got := AppendCBORBool(AppendCBORNull(nil), false); !bytes.Equal(got, []byte{0xf6, 0xf4}) {
+		t.Errorf("null, false: got %x", got)
+	}
+}
+
+func TestSortedCBORKeys(t *testing.T) {
+	m := map[string]int{"bb": 1, "a": 2, "c": 3, "": 4, "aaa": 5}
+	got := SortedCBORKeys(m)
+	if want := []string{"", "a", "c", "bb", "aaa"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedCBORKeys = %q", got)
+	}
+
+	// The order is the library's length-first sort
+	var want []byte
+	want = AppendCBORMapLen(want, len(m))
+	for _, k := range got {
+		want = AppendCBORInt(AppendCBORString(want, k), int64(m[k]))
+	}
+	mode, err := cbor.EncOptions{Sort: cbor.SortLengthFirst}.EncMode()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if enc, _ := mode.Marshal(m); !bytes.Equal(enc, want) {
+		t.Errorf("sorted map encodes to %x, want %x", enc, want)
+	}
+}
+
+func TestCBORReader_Values(t *testing.T) {
+	for _, n := range testInts {
+		var r CBORReader
+		r.Reset(cborOf(t, n))
+		if got := r.Int(64); r.End() != nil || got != n {
+			t.Errorf("Int(%d) = %d, %v", n, got, r.End())
+		}
+	}
+
+	data := cborOf(t, map[string]any{"a": []any{"x", 1.5, nil, true, []byte("b"), uint64(7)}})
+	var r CBORReader
+	r.Reset(data)
+	if r.Null() || r.MapLen() != 1 || string(r.Key()) != "a" || r.ArrayLen() != 6 {
+		t.Fatalf("reading map: %v", r.End())
+	}
+	s, f, null, b, raw, u := r.String(), r.Float(64), r.Null(), r.Bool(), r.Bytes(), r.Uint(8)
+	r.Leave()
+	r.Leave()
+	if err := r.End(); err != nil || s != "x" || f != 1.5 || !null || !b || string(raw) != "b" || u != 7 {
+		t.Errorf("read %q %v %v %v %q %v, %v", s, f, null, b, raw, u, err)
+	}
+
+	r.Reset(cborOf(t, map[string]any{"k": []any{map[string]int{"x": 1}, "y"}}))
+	r.Skip()
+	if err := r.End(); err != nil {
+		t.Errorf("Skip: %v", err)
+	}
+}
+
+func TestCBORReader_Fails(t *testing.T) {
+	for _, tc := range []struct {
+		data []byte
+		read func(r *CBORReader)
+	}{
+		{cborOf(t, 300), func(r *CBORReader) { r.Int(8) }},
+		{cborOf(t, -1), func(r *CBORReader) { r.Uint(64) }},
+		{cborOf(t, 1.5), func(r *CBORReader) { r.Int(64) }},
+		{cborOf(t, 1), func(r *CBORReader) { r.Float(64) }},
+		{cborOf(t, 1e300), func(r *CBORReader) { r.Float(32) }},
+		{[]byte{0xf9, 0x3c, 0x00}, func(r *CBORReader) { r.Float(64) }},
+		{[]byte{0x62, 0xff, 0xfe}, func(r *CBORReader) { _ = r.String() }},
+		{[]byte{0x7f, 0x61, 'a', 0xff}, func(r *CBORReader) { _ = r.String() }},
+		{[]byte{0xc1, 0x01}, func(r *CBORReader) { r.Skip() }},
+		{[]byte{0x9b, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff, 0xff}, func(r *CBORReader) { r.ArrayLen() }},
+		{cborOf(t, "s"), func(r *CBORReader) { r.Bytes() }},
+		{[]byte{0x01, 0x02}, func(r *CBORReader) { r.Int(64) }},
+		{cborOf(t, map[string]int{"ID": 1}), func(r *CBORReader) { r.MapLen(); r.Unknown(r.Key(), "id") }},
+		{nil, func(r *CBORReader) {
+			var seen bool
+			r.Field(&seen)
+			r.Field(&seen)
+		}},
+	} {
+		var r CBORReader
+		r.Reset(tc.data)
+		tc.read(&r)
+		if err := r.End(); err != ErrFallback {
+			t.Errorf("%x: End = %v", tc.data, err)
+		}
+	}
+}
@@ -0,0 +1,95 @@
+package codecgen
+
+import (
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// Codec is a codec.OptimizedCodec that calls the generated functions of T
+// directly and the reflection-based codec it wraps for values and input
+// they do not handle. Generated files declare a constructor for each type
+// and format, so that code using it only compiles while the generated code
+// is present. Encode and Decode are those of the wrapped codec, which
+// finds the generated functions through Register.
+type Codec[T any] struct {
+	inner codec.OptimizedCodec[T]
+	funcs Funcs[T]
+	err   error
+}
+
+// NewCodec returns a Codec calling f and falling back to inner. If inner
+// reports an error through an Err method, every operation returns the
+// error, which is also reported by Err.
+func NewCodec[T any](inner codec.OptimizedCodec[T], f Funcs[T]) *Codec[T] {
+	c := &Codec[T]{inner: inner, funcs: f}
+	if e, ok := inner.(interface{ Err() error }); ok {
+		c.err = e.Err()
+	}
+	return c
+}
+
+// Err returns the error, if any, reported by the wrapped codec
+func (c *Codec[T]) Err() error {
+	return c.err
+}
+
+// Encode writes the encoding of data to w with the wrapped codec
+func (c *Codec[T]) Encode(w io.Writer, data T) error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.inner.Encode(w, data)
+}
+
+// Decode reads a value from r into data with the wrapped codec
+func (c *Codec[T]) Decode(r io.Reader, data *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	return c.inner.Decode(r, data)
+}
+
+// Marshal returns the encoding of data
+func (c *Codec[T]) Marshal(data T) ([]byte, error) {
+	return c.AppendMarshal(nil, data)
+}
+
+// MarshalTo encodes data into buf, reusing its capacity
+func (c *Codec[T]) MarshalTo(buf []byte, data T) ([]byte, error) {
+	return c.AppendMarshal(buf[:0], data)
+}
+
+// AppendMarshal appends the encoding of data to buf
+func (c *Codec[T]) AppendMarshal(buf []byte, data T) ([]byte, error) {
+	if c.err != nil {
+		return buf, c.err
+	}
+	if out, err := c.funcs.Append(buf, data); err == nil {
+		return out, nil
+	}
+	return c.inner.AppendMarshal(buf, data)
+}
+
+// Unmarshal decodes data into v
+func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.funcs.Parse(data, v) == nil {
+		return nil
+	}
+	return c.inner.Unmarshal(data, v)
+}
+
+// UnmarshalFrom decodes data into v. Generated code decodes straight from
+// data, so scratch is passed on only to the wrapped codec.
+func (c *Codec[T]) UnmarshalFrom(data []byte, v *T, scratch []byte) error {
+	if c.err != nil {
+		return c.err
+	}
+	if c.funcs.Parse(data, v) == nil {
+		return nil
+	}
+	return c.inner.UnmarshalFrom(data, v, scratch)
+}
@@ -0,0 +1,97 @@
+// Package codecgen is the runtime support for code written by the codecgen
+// command. Generated files register type-specific marshaling functions
+// here, and the JSON, MessagePack and CBOR codecs use them in place of
+// reflection when they are configured with default settings.
+//
+// Generated functions accept only input they decode exactly as the
+// reflection-based library would. Anything else, including malformed
+// input, makes them return an error, and the codec then repeats the
+// operation with reflection, so results and error messages are the same
+// whether or not generated code is present. On such a fallback the
+// destination may already hold part of the input, as it may after any
+// failed decode.
+package codecgen
+
+import (
+	"errors"
+	"reflect"
+	"sync"
+	"sync/atomic"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// ErrFallback is returned by generated code for values and input it does
+// not handle, so that the caller falls back to reflection
+var ErrFallback = errors.New("codecgen: not handled by generated code")
+
+// MaxDepth bounds the nesting of structs that generated code encodes.
+// Deeper values, which are normally cyclic, are left to reflection, which
+// reports cycles where the library detects them.
+const MaxDepth = 1000
+
+// Funcs holds the generated functions of a type for one codec type
+type Funcs[T any] struct {
+	// Append appends the encoding of v to buf
+	Append func(buf []byte, v T) ([]byte, error)
+
+	// Parse decodes data into v
+	Parse func(data []byte, v *T) error
+}
+
+// key identifies registered functions by codec type and Go type
+type key struct {
+	codec codec.Type
+	typ   reflect.Type
+}
+
+var (
+	registryMu sync.Mutex
+	registry   = map[key]any{}
+
+	// version is incremented by every Register so that Cache can tell
+	// when to look up again
+	version atomic.Uint64
+)
+
+// Register makes f the generated functions of T for codec type t. It is
+// called from the init function of generated files.
+func Register[T any](t codec.Type, f Funcs[T]) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry[key{t, reflect.TypeFor[T]()}] = f
+	version.Add(1)
+}
+
+// Lookup returns the generated functions of T for codec type t
+func Lookup[T any](t codec.Type) (Funcs[T], bool) {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	f, ok := registry[key{t, reflect.TypeFor[T]()}].(Funcs[T])
+	return f, ok
+}
+
+// Cache remembers the result of Lookup for a codec. It looks up again
+// only after another Register, so a codec created before the init
+// function of a generated file has run still finds its functions. The
+// zero value is ready to use.
+type Cache[T any] struct {
+	entry atomic.Pointer[cacheEntry[T]]
+}
+
+type cacheEntry[T any] struct {
+	version uint64
+	funcs   Funcs[T]
+	ok      bool
+}
+
+// Get returns the generated functions of T for codec type t
+func (c *Cache[T]) Get(t codec.Type) (Funcs[T], bool) {
+	current := version.Load()
+	if e := c.entry.Load(); e != nil && e.version == current {
+		return e.funcs, e.ok
+	}
+	f, ok := Lookup[T](t)
+	c.entry.Store(&cacheEntry[T]{version: current, funcs: f, ok: ok})
+	return f, ok
+}
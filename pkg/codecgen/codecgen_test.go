@@ -0,0 +1,127 @@
+package codecgen
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+type registered struct{ N int }
+
+func TestRegister_Lookup(t *testing.T) {
+	if _, ok := Lookup[registered](codec.JSON); ok {
+		t.Fatal("Lookup found functions before Register")
+	}
+	var cache Cache[registered]
+	if _, ok := cache.Get(codec.JSON); ok {
+		t.Fatal("Cache found functions before Register")
+	}
+
+	Register(codec.JSON, Funcs[registered]{
+		Append: func(buf []byte, v registered) ([]byte, error) { return append(buf, 'x'), nil },
+	})
+	f, ok := Lookup[registered](codec.JSON)
+	if !ok {
+		t.Fatal("Lookup did not find registered functions")
+	}
+	if out, _ := f.Append(nil, registered{}); string(out) != "x" {
+		t.Errorf("Append = %q", out)
+	}
+	if _, ok := Lookup[registered](codec.CBOR); ok {
+		t.Error("Lookup found functions of another codec type")
+	}
+	if _, ok := Lookup[*registered](codec.JSON); ok {
+		t.Error("Lookup found functions of another Go type")
+	}
+
+	// The cache looks up again after Register
+	if _, ok := cache.Get(codec.JSON); !ok {
+		t.Error("Cache did not see a later Register")
+	}
+}
+
+// fallback is a codec.OptimizedCodec standing in for reflection
+type fallback struct {
+	err error
+}
+
+func (f fallback) Err() error                               { return f.err }
+func (fallback) Encode(w io.Writer, v registered) error     { _, err := w.Write([]byte("enc")); return err }
+func (fallback) Decode(r io.Reader, v *registered) error    { v.N = -1; return nil }
+func (fallback) Marshal(v registered) ([]byte, error)       { return []byte("reflect"), nil }
+func (fallback) Unmarshal(data []byte, v *registered) error { v.N = -1; return nil }
+func (fallback) MarshalTo(buf []byte, v registered) ([]byte, error) {
+	return append(buf[:0], "reflect"...), nil
+}
+func (fallback) AppendMarshal(buf []byte, v registered) ([]byte, error) {
+	return append(buf, "reflect"...), nil
+}
+func (fallback) UnmarshalFrom(data []byte, v *registered, scratch []byte) error {
+	v.N = -1
+	return nil
+}
+
+func TestCodec_Fallback(t *testing.T) {
+	c := NewCodec[registered](fallback{}, Funcs[registered]{
+		Append: func(buf []byte, v registered) ([]byte, error) {
+			if v.N < 0 {
+				return append(buf, "partial"...), ErrFallback
+			}
+			return append(buf, 'g'), nil
+		},
+		Parse: func(data []byte, v *registered) error {
+			if string(data) != "g" {
+				return ErrFallback
+			}
+			v.N = 1
+			return nil
+		},
+	})
+	var _ codec.OptimizedCodec[registered] = c
+
+	if out, err := c.AppendMarshal([]byte("x"), registered{}); err != nil || string(out) != "xg" {
+		t.Errorf("AppendMarshal = %q, %v", out, err)
+	}
+	if out, err := c.AppendMarshal([]byte("x"), registered{N: -1}); err != nil || string(out) != "xreflect" {
+		t.Errorf("AppendMarshal fallback = %q, %v", out, err)
+	}
+	if out, err := c.MarshalTo([]byte("old"), registered{}); err != nil || string(out) != "g" {
+		t.Errorf("MarshalTo = %q, %v", out, err)
+	}
+	if out, err := c.Marshal(registered{N: -1}); err != nil || string(out) != "reflect" {
+		t.Errorf("Marshal fallback = %q, %v", out, err)
+	}
+
+	var v registered
+	if err := c.Unmarshal([]byte("g"), &v); err != nil || v.N != 1 {
+		t.Errorf("Unmarshal = %+v, %v", v, err)
+	}
+	if err := c.UnmarshalFrom([]byte("other"), &v, nil); err != nil || v.N != -1 {
+		t.Errorf("UnmarshalFrom fallback = %+v, %v", v, err)
+	}
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, registered{}); err != nil || buf.String() != "enc" {
+		t.Errorf("Encode = %q, %v", buf.String(), err)
+	}
+}
+
+func TestCodec_Err(t *testing.T) {
+	errInner := errors.New("inner codec error")
+	c := NewCodec[registered](fallback{err: errInner}, Funcs[registered]{})
+	if c.Err() != errInner {
+		t.Fatalf("Err = %v", c.Err())
+	}
+	if _, err := c.Marshal(registered{}); err != errInner {
+		t.Errorf("Marshal error = %v", err)
+	}
+	var v registered
+	if err := c.Unmarshal(nil, &v); err != errInner {
+		t.Errorf("Unmarshal error = %v", err)
+	}
+	if err := c.Decode(bytes.NewReader(nil), &v); err != errInner {
+		t.Errorf("Decode error = %v", err)
+	}
+}
@@ -0,0 +1,552 @@
+package codecgen
+
+import (
+	"bytes"
+	"encoding/base64"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"unicode"
+	"unicode/utf16"
+	"unicode/utf8"
+)
+
+// maxJSONDepth is the nesting limit of encoding/json
+const maxJSONDepth = 10000
+
+const hex = "0123456789abcdef"
+
+// htmlSafe reports the ASCII characters encoding/json writes unescaped
+var htmlSafe = func() (safe [utf8.RuneSelf]bool) {
+	for c := ' '; c < utf8.RuneSelf; c++ {
+		safe[c] = !strings.ContainsRune(`"\<>&`, c)
+	}
+	return safe
+}()
+
+// AppendJSONString appends s as a JSON string, escaped as encoding/json
+// escapes it by default
+func AppendJSONString(buf []byte, s string) []byte {
+	buf = append(buf, '"')
+	start := 0
+	for i := 0; i < len(s); {
+		if c := s[i]; c < utf8.RuneSelf {
+			if htmlSafe[c] {
+				i++
+				continue
+			}
+			buf = append(buf, s[start:i]...)
+			switch c {
+			case '\\', '"':
+				buf = append(buf, '\\', c)
+			case '\b':
+				buf = append(buf, '\\', 'b')
+			case '\f':
+				buf = append(buf, '\\', 'f')
+			case '\n':
+				buf = append(buf, '\\', 'n')
+			case '\r':
+				buf = append(buf, '\\', 'r')
+			case '\t':
+				buf = append(buf, '\\', 't')
+			default:
+				buf = append(buf, '\\', 'u', '0', '0', hex[c>>4], hex[c&0xF])
+			}
+			i++
+			start = i
+			continue
+		}
+		r, size := utf8.DecodeRuneInString(s[i:])
+		switch {
+		case r == utf8.RuneError && size == 1:
+			buf = append(append(buf, s[start:i]...), "\ufffd"...)
+		case r == '\u2028' || r == '\u2029':
+			buf = append(append(buf, s[start:i]...), '\\', 'u', '2', '0', '2', hex[r&0xF])
+		default:
+			i += size
+			continue
+		}
+		i += size
+		start = i
+	}
+	buf = append(buf, s[start:]...)
+	return append(buf, '"')
+}
+
+// AppendJSONBytes appends b as a base64 JSON string, or null if b is nil
+func AppendJSONBytes(buf, b []byte) []byte {
+	if b == nil {
+		return append(buf, "null"...)
+	}
+	buf = append(buf, '"')
+	buf = base64.StdEncoding.AppendEncode(buf, b)
+	return append(buf, '"')
+}
+
+// AppendJSONFloat appends f, a float of the given bit size, in the format
+// of encoding/json. NaN and infinities have no JSON form and return an
+// error.
+func AppendJSONFloat(buf []byte, f float64, bits int) ([]byte, error) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return buf, ErrFallback
+	}
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 {
+		if bits == 64 && (abs < 1e-6 || abs >= 1e21) ||
+			bits == 32 && (float32(abs) < 1e-6 || float32(abs) >= 1e21) {
+			format = 'e'
+		}
+	}
+	buf = strconv.AppendFloat(buf, f, format, -1, bits)
+	if format == 'e' {
+		// Shorten e-09 to e-9
+		if n := len(buf); n >= 4 && buf[n-4] == 'e' && buf[n-3] == '-' && buf[n-2] == '0' {
+			buf[n-2] = buf[n-1]
+			buf = buf[:n-1]
+		}
+	}
+	return buf, nil
+}
+
+// SortedKeys returns the keys of m in increasing order, the order in which
+// encoding/json writes them. msgpack writes map entries in random order,
+// of which generated code uses this one.
+func SortedKeys[M ~map[string]V, V any](m M) []string {
+	return slices.Sorted(maps.Keys(m))
+}
+
+// ExtendJSON returns s long enough to hold index i, growing it as
+// encoding/json does when it decodes an array into an existing slice
+func ExtendJSON[S ~[]E, E any](s S, i int) S {
+	if i >= cap(s) {
+		s = slices.Grow(s, 1)
+	}
+	if i >= len(s) {
+		s = s[:i+1]
+	}
+	return s
+}
+
+// TruncateJSON returns s cut to the n items decoded into it. An empty array
+// leaves a new empty slice, as it does in encoding/json.
+func TruncateJSON[S ~[]E, E any](s S, n int) S {
+	if n == 0 {
+		return S{}
+	}
+	return s[:n]
+}
+
+// JSONReader reads JSON for generated code with the semantics of
+// encoding/json.Unmarshal. Its methods record the first error and return
+// zero values after it, so generated code checks for an error only at the
+// end. The zero value reads empty input; Reset sets the input.
+type JSONReader struct {
+	data  []byte
+	off   int
+	depth int
+	first bool
+	err   error
+}
+
+// Reset makes r read data from the beginning
+func (r *JSONReader) Reset(data []byte) {
+	*r = JSONReader{data: data}
+}
+
+// End returns the first error, or an error if anything but whitespace
+// follows the value that was read
+func (r *JSONReader) End() error {
+	if r.err == nil && r.peek() != 0 {
+		r.fail()
+	}
+	return r.err
+}
+
+// Null reads null if it is next and reports whether it did
+func (r *JSONReader) Null() bool {
+	if r.err != nil || r.peek() != 'n' {
+		return false
+	}
+	r.literal("null")
+	return r.err == nil
+}
+
+// BeginObject reads the opening brace of an object
+func (r *JSONReader) BeginObject() bool {
+	return r.begin('{')
+}
+
+// BeginArray reads the opening bracket of an array
+func (r *JSONReader) BeginArray() bool {
+	return r.begin('[')
+}
+
+func (r *JSONReader) begin(c byte) bool {
+	if r.err != nil || r.peek() != c {
+		r.fail()
+		return false
+	}
+	r.off++
+	if r.depth++; r.depth > maxJSONDepth {
+		r.fail()
+		return false
+	}
+	r.first = true
+	return true
+}
+
+// NextMember reports whether another member of the object follows,
+// reading the separating comma or the closing brace
+func (r *JSONReader) NextMember() bool {
+	return r.next('}')
+}
+
+// NextItem reports whether another item of the array follows, reading the
+// separating comma or the closing bracket
+func (r *JSONReader) NextItem() bool {
+	return r.next(']')
+}
+
+func (r *JSONReader) next(end byte) bool {
+	if r.err != nil {
+		return false
+	}
+	c := r.peek()
+	if c == end {
+		r.off++
+		r.depth--
+		r.first = false
+		return false
+	}
+	if r.first {
+		r.first = false
+		return true
+	}
+	if c != ',' {
+		r.fail()
+		return false
+	}
+	r.off++
+	return true
+}
+
+// Key reads a member name and the colon after it. The result is valid
+// until the next call.
+func (r *JSONReader) Key() []byte {
+	key := r.str()
+	if r.err != nil || r.peek() != ':' {
+		r.fail()
+		return nil
+	}
+	r.off++
+	return key
+}
+
+// Unknown skips the value of a member whose key matched no field exactly.
+// encoding/json would decode a key matching one of names without regard to
+// case, which generated code leaves to it by failing.
+func (r *JSONReader) Unknown(key []byte, names ...string) {
+	for _, name := range names {
+		if bytes.EqualFold(key, []byte(name)) {
+			r.fail()
+			return
+		}
+	}
+	r.Skip()
+}
+
+// String reads a string
+func (r *JSONReader) String() string {
+	return string(r.str())
+}
+
+// Bytes reads a base64 string as encoding/json decodes a []byte
+func (r *JSONReader) Bytes() []byte {
+	s := r.str()
+	if r.err != nil {
+		return nil
+	}
+	b := make([]byte, base64.StdEncoding.DecodedLen(len(s)))
+	n, err := base64.StdEncoding.Decode(b, s)
+	if err != nil {
+		r.fail()
+		return nil
+	}
+	return b[:n]
+}
+
+// Bool reads true or false
+func (r *JSONReader) Bool() bool {
+	if r.err != nil {
+		return false
+	}
+	switch r.peek() {
+	case 't':
+		r.literal("true")
+		return r.err == nil
+	case 'f':
+		r.literal("false")
+	default:
+		r.fail()
+	}
+	return false
+}
+
+// Int reads a number into a signed integer of the given bit size, where 0
+// is the size of int
+func (r *JSONReader) Int(bits int) int64 {
+	num := r.number()
+	if r.err != nil {
+		return 0
+	}
+	n, err := strconv.ParseInt(string(num), 10, bits)
+	if err != nil {
+		r.fail()
+	}
+	return n
+}
+
+// Uint reads a number into an unsigned integer of the given bit size,
+// where 0 is the size of uint
+func (r *JSONReader) Uint(bits int) uint64 {
+	num := r.number()
+	if r.err != nil {
+		return 0
+	}
+	n, err := strconv.ParseUint(string(num), 10, bits)
+	if err != nil {
+		r.fail()
+	}
+	return n
+}
+
+// Float reads a number into a float of the given bit size
+func (r *JSONReader) Float(bits int) float64 {
+	num := r.number()
+	if r.err != nil {
+		return 0
+	}
+	f, err := strconv.ParseFloat(string(num), bits)
+	if err != nil {
+		r.fail()
+	}
+	return f
+}
+
+// Skip reads and discards a value
+func (r *JSONReader) Skip() {
+	if r.err != nil {
+		return
+	}
+	switch c := r.peek(); {
+	case c == '{' || c == '[':
+		r.begin(c)
+		for r.next(c + 2) {
+			if c == '{' {
+				r.Key()
+			}
+			r.Skip()
+		}
+	case c == '"':
+		r.scanString()
+	case c == 't':
+		r.literal("true")
+	case c == 'f':
+		r.literal("false")
+	case c == 'n':
+		r.literal("null")
+	default:
+		r.number()
+	}
+}
+
+func (r *JSONReader) fail() {
+	if r.err == nil {
+		r.err = ErrFallback
+	}
+}
+
+// peek skips whitespace and returns the next byte, or 0 at the end of input
+func (r *JSONReader) peek() byte {
+	for ; r.off < len(r.data); r.off++ {
+		switch c := r.data[r.off]; c {
+		case ' ', '\t', '\n', '\r':
+		default:
+			return c
+		}
+	}
+	return 0
+}
+
+func (r *JSONReader) literal(lit string) {
+	if !bytes.HasPrefix(r.data[r.off:], []byte(lit)) {
+		r.fail()
+		return
+	}
+	r.off += len(lit)
+}
+
+// number reads a number, checking it against the JSON grammar
+func (r *JSONReader) number() []byte {
+	if r.err != nil {
+		return nil
+	}
+	r.peek()
+	d, start, i := r.data, r.off, r.off
+	digits := func() bool {
+		n := i
+		for i < len(d) && d[i] >= '0' && d[i] <= '9' {
+			i++
+		}
+		return i > n
+	}
+	if i < len(d) && d[i] == '-' {
+		i++
+	}
+	switch {
+	case i < len(d) && d[i] == '0':
+		i++
+	case !digits():
+		r.fail()
+		return nil
+	}
+	if i < len(d) && d[i] == '.' {
+		i++
+		if !digits() {
+			r.fail()
+			return nil
+		}
+	}
+	if i < len(d) && (d[i] == 'e' || d[i] == 'E') {
+		i++
+		if i < len(d) && (d[i] == '+' || d[i] == '-') {
+			i++
+		}
+		if !digits() {
+			r.fail()
+			return nil
+		}
+	}
+	r.off = i
+	return d[start:i]
+}
+
+// str reads a string and returns its unquoted content, which refers to the
+// input unless the string holds escapes or invalid UTF-8
+func (r *JSONReader) str() []byte {
+	if r.err != nil || r.peek() != '"' {
+		r.fail()
+		return nil
+	}
+	start := r.off + 1
+	plain := r.scanString()
+	if r.err != nil {
+		return nil
+	}
+	s := r.data[start : r.off-1]
+	if plain && utf8.Valid(s) {
+		return s
+	}
+	return unquote(s)
+}
+
+// scanString reads a string, checking its escapes and characters, and
+// reports whether it has no escapes
+func (r *JSONReader) scanString() bool {
+	plain := true
+	d := r.data
+	for i := r.off + 1; i < len(d); i++ {
+		switch c := d[i]; {
+		case c == '"':
+			r.off = i + 1
+			return plain
+		case c < ' ':
+			r.fail()
+			return false
+		case c == '\\':
+			plain = false
+			if i++; i == len(d) {
+				break
+			}
+			switch d[i] {
+			case '"', '\\', '/', 'b', 'f', 'n', 'r', 't':
+			case 'u':
+				if i+4 >= len(d) || !isHex(d[i+1]) || !isHex(d[i+2]) || !isHex(d[i+3]) || !isHex(d[i+4]) {
+					r.fail()
+					return false
+				}
+				i += 4
+			default:
+				r.fail()
+				return false
+			}
+		}
+	}
+	r.fail()
+	return false
+}
+
+func isHex(c byte) bool {
+	return c >= '0' && c <= '9' || c >= 'a' && c <= 'f' || c >= 'A' && c <= 'F'
+}
+
+// unquote returns the content of a checked string with its escapes
+// replaced and invalid UTF-8 coerced to U+FFFD, as encoding/json does
+func unquote(s []byte) []byte {
+	b := make([]byte, 0, len(s)+2*utf8.UTFMax)
+	for i := 0; i < len(s); {
+		switch c := s[i]; {
+		case c == '\\':
+			switch s[i+1] {
+			case 'b':
+				b = append(b, '\b')
+			case 'f':
+				b = append(b, '\f')
+			case 'n':
+				b = append(b, '\n')
+			case 'r':
+				b = append(b, '\r')
+			case 't':
+				b = append(b, '\t')
+			case 'u':
+				rr := getu4(s[i:])
+				i += 6
+				if utf16.IsSurrogate(rr) {
+					if dec := utf16.DecodeRune(rr, getu4(s[i:])); dec != unicode.ReplacementChar {
+						b = utf8.AppendRune(b, dec)
+						i += 6
+						continue
+					}
+					rr = unicode.ReplacementChar
+				}
+				b = utf8.AppendRune(b, rr)
+				continue
+			default:
+				b = append(b, s[i+1])
+			}
+			i += 2
+		case c < utf8.RuneSelf:
+			b = append(b, c)
+			i++
+		default:
+			rr, size := utf8.DecodeRune(s[i:])
+			b = utf8.AppendRune(b, rr)
+			i += size
+		}
+	}
+	return b
+}
+
+// getu4 decodes the \uXXXX escape at the start of s, or returns -1
+func getu4(s []byte) rune {
+	if len(s) < 6 || s[0] != '\\' || s[1] != 'u' {
+		return -1
+	}
+	n, err := strconv.ParseUint(string(s[2:6]), 16, 32)
+	if err != nil {
+		return -1
+	}
+	return rune(n)
+}
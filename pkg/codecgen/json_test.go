@@ -0,0 +1,151 @@
+package codecgen
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+var testStrings = []string{
+	"", "plain", `quote " and \ backslash`, "<html> & 'entities'", "\t\n\r\b\f",
+	"\x00\x01\x1f\x7f", "é ü 日本語 😀", "  ", "\xff invalid \xc3", "\xed\xa0\x80",
+	strings.Repeat("long ", 100),
+}
+
+func TestAppendJSONString(t *testing.T) {
+	for _, s := range testStrings {
+		want, _ := json.Marshal(s)
+		if got := AppendJSONString([]byte("x"), s); string(got) != "x"+string(want) {
+			t.Errorf("%q: got %s, want %s", s, got[1:], want)
+		}
+	}
+}
+
+func TestAppendJSONBytes(t *testing.T) {
+	for _, b := range [][]byte{nil, {}, {0}, []byte("some bytes\xff")} {
+		want, _ := json.Marshal(b)
+		if got := AppendJSONBytes(nil, b); string(got) != string(want) {
+			t.Errorf("%q: got %s, want %s", b, got, want)
+		}
+	}
+}
+
+func TestAppendJSONFloat(t *testing.T) {
+	for _, f := range []float64{
+		0, math.Copysign(0, -1), 1, -1.5, 1e-6, 9.99e-7, 1e20, 1e21, 123456789e-15,
+		math.MaxFloat64, math.SmallestNonzeroFloat64, math.MaxFloat32, math.SmallestNonzeroFloat32,
+	} {
+		want, _ := json.Marshal(f)
+		if got, err := AppendJSONFloat(nil, f, 64); err != nil || string(got) != string(want) {
+			t.Errorf("%v: got %s, %v, want %s", f, got, err, want)
+		}
+		if math.IsInf(float64(float32(f)), 0) {
+			continue
+		}
+		want, _ = json.Marshal(float32(f))
+		if got, err := AppendJSONFloat(nil, float64(float32(f)), 32); err != nil || string(got) != string(want) {
+			t.Errorf("float32 %v: got %s, %v, want %s", f, got, err, want)
+		}
+	}
+	for _, f := range []float64{math.NaN(), math.Inf(1), math.Inf(-1)} {
+		if _, err := AppendJSONFloat(nil, f, 64); err != ErrFallback {
+			t.Errorf("%v: err = %v", f, err)
+		}
+	}
+}
+
+func TestSortedKeys(t *testing.T) {
+	got := SortedKeys(map[string]int{"b": 1, "a": 2, "aa": 3, "": 4})
+	if want := []string{"", "a", "aa", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("SortedKeys = %q", got)
+	}
+}
+
+func TestJSONReader_Values(t *testing.T) {
+	for _, s := range testStrings {
+		data, _ := json.Marshal(s)
+		var want string
+		_ = json.Unmarshal(data, &want)
+		var r JSONReader
+		r.Reset(data)
+		if got := r.String(); r.End() != nil || got != want {
+			t.Errorf("String(%s) = %q, %v", data, got, r.End())
+		}
+	}
+
+	var r JSONReader
+	r.Reset([]byte(` {"a": [1, -2.5e3, true, null, "xé"], "b": {"c": {}}} `))
+	if r.Null() || !r.BeginObject() || !r.NextMember() || string(r.Key()) != "a" || !r.BeginArray() {
+		t.Fatalf("reading object: %v", r.End())
+	}
+	r.NextItem()
+	i := r.Int(8)
+	r.NextItem()
+	f := r.Float(64)
+	r.NextItem()
+	b := r.Bool()
+	r.NextItem()
+	null := r.Null()
+	r.NextItem()
+	s := r.String()
+	if r.NextItem() || !r.NextMember() || string(r.Key()) != "b" {
+		t.Fatalf("reading array: %v", r.End())
+	}
+	r.Skip()
+	if r.NextMember() || r.End() != nil {
+		t.Fatalf("End = %v", r.End())
+	}
+	if i != 1 || f != -2500 || !b || !null || s != "xé" {
+		t.Errorf("read %v %v %v %v %q", i, f, b, null, s)
+	}
+}
+
+func TestJSONReader_Fails(t *testing.T) {
+	for _, tc := range []struct {
+		data string
+		read func(r *JSONReader)
+	}{
+		{`300`, func(r *JSONReader) { r.Int(8) }},
+		{`-1`, func(r *JSONReader) { r.Uint(64) }},
+		{`1.0`, func(r *JSONReader) { r.Int(64) }},
+		{`1e400`, func(r *JSONReader) { r.Float(64) }},
+		{`1e39`, func(r *JSONReader) { r.Float(32) }},
+		{`01`, func(r *JSONReader) { r.Int(64) }},
+		{`"1"`, func(r *JSONReader) { r.Int(64) }},
+		{`"a`, func(r *JSONReader) { _ = r.String() }},
+		{`"\x"`, func(r *JSONReader) { _ = r.String() }},
+		{`"not base64"`, func(r *JSONReader) { r.Bytes() }},
+		{`tru`, func(r *JSONReader) { r.Bool() }},
+		{`1 2`, func(r *JSONReader) { r.Int(64) }},
+		{`{"A":1}`, func(r *JSONReader) { r.BeginObject(); r.NextMember(); r.Unknown(r.Key(), "a") }},
+		{`[1,]`, func(r *JSONReader) { r.Skip() }},
+		{strings.Repeat("[", 10001) + strings.Repeat("]", 10001), func(r *JSONReader) { r.Skip() }},
+	} {
+		var r JSONReader
+		r.Reset([]byte(tc.data))
+		tc.read(&r)
+		if err := r.End(); err != ErrFallback {
+			t.Errorf("%.20s: End = %v", tc.data, err)
+		}
+	}
+}
+
+func TestExtendTruncateJSON(t *testing.T) {
+	s := make([]int, 2, 3)
+	s[0], s[1] = 1, 2
+	s = ExtendJSON(s, 0)
+	s = ExtendJSON(s, 1)
+	s = ExtendJSON(s, 2)
+	s = ExtendJSON(s, 3)
+	if len(s) != 4 || s[0] != 1 || s[2] != 0 {
+		t.Errorf("ExtendJSON = %v", s)
+	}
+	if s = TruncateJSON(s, 1); len(s) != 1 {
+		t.Errorf("TruncateJSON = %v", s)
+	}
+	if s := TruncateJSON([]int(nil), 0); s == nil {
+		t.Error("TruncateJSON of nil to zero items returned nil")
+	}
+}
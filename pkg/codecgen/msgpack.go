@@ -0,0 +1,458 @@
+package codecgen
+
+import (
+	"encoding/binary"
+	"math"
+)
+
+// maxSkipDepth bounds the nesting of values skipped by MsgPackReader
+const maxSkipDepth = 10000
+
+// AppendMsgPackNil appends nil
+func AppendMsgPackNil(buf []byte) []byte {
+	return append(buf, 0xc0)
+}
+
+// AppendMsgPackBool appends b
+func AppendMsgPackBool(buf []byte, b bool) []byte {
+	if b {
+		return append(buf, 0xc3)
+	}
+	return append(buf, 0xc2)
+}
+
+// AppendMsgPackInt appends n as msgpack.Marshal encodes a signed integer of
+// the given bit size: int8 to int64 in their own width and int, given as
+// 0, in the smallest form that holds it
+func AppendMsgPackInt(buf []byte, n int64, bits int) []byte {
+	switch bits {
+	case 8:
+		return append(buf, 0xd0, byte(n))
+	case 16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xd1), uint16(n))
+	case 32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xd2), uint32(n))
+	case 64:
+		return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(n))
+	}
+	switch {
+	case n >= 0:
+		return AppendMsgPackUint(buf, uint64(n), 0)
+	case n >= -32:
+		return append(buf, byte(n))
+	case n >= math.MinInt8:
+		return append(buf, 0xd0, byte(n))
+	case n >= math.MinInt16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xd1), uint16(n))
+	case n >= math.MinInt32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xd2), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xd3), uint64(n))
+	}
+}
+
+// AppendMsgPackUint appends n as msgpack.Marshal encodes an unsigned
+// integer of the given bit size: uint8 to uint64 in their own width and
+// uint, given as 0, in the smallest form that holds it
+func AppendMsgPackUint(buf []byte, n uint64, bits int) []byte {
+	switch bits {
+	case 8:
+		return append(buf, 0xcc, byte(n))
+	case 16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xcd), uint16(n))
+	case 32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xce), uint32(n))
+	case 64:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcf), n)
+	}
+	switch {
+	case n <= math.MaxInt8:
+		return append(buf, byte(n))
+	case n <= math.MaxUint8:
+		return append(buf, 0xcc, byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, 0xcd), uint16(n))
+	case n <= math.MaxUint32:
+		return binary.BigEndian.AppendUint32(append(buf, 0xce), uint32(n))
+	default:
+		return binary.BigEndian.AppendUint64(append(buf, 0xcf), n)
+	}
+}
+
+// AppendMsgPackFloat32 appends f as a float 32
+func AppendMsgPackFloat32(buf []byte, f float32) []byte {
+	return binary.BigEndian.AppendUint32(append(buf, 0xca), math.Float32bits(f))
+}
+
+// AppendMsgPackFloat64 appends f as a float 64
+func AppendMsgPackFloat64(buf []byte, f float64) []byte {
+	return binary.BigEndian.AppendUint64(append(buf, 0xcb), math.Float64bits(f))
+}
+
+// AppendMsgPackString appends s as a str
+func AppendMsgPackString(buf []byte, s string) []byte {
+	n := len(s)
+	switch {
+	case n < 32:
+		buf = append(buf, 0xa0|byte(n))
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xd9, byte(n))
+	case n <= math.MaxUint16:
+		buf = binary.BigEndian.AppendUint16(append(buf, 0xda), uint16(n))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, 0xdb), uint32(n))
+	}
+	return append(buf, s...)
+}
+
+// AppendMsgPackBytes appends b as a bin, or nil if b is nil
+func AppendMsgPackBytes(buf, b []byte) []byte {
+	if b == nil {
+		return AppendMsgPackNil(buf)
+	}
+	n := len(b)
+	switch {
+	case n <= math.MaxUint8:
+		buf = append(buf, 0xc4, byte(n))
+	case n <= math.MaxUint16:
+		buf = binary.BigEndian.AppendUint16(append(buf, 0xc5), uint16(n))
+	default:
+		buf = binary.BigEndian.AppendUint32(append(buf, 0xc6), uint32(n))
+	}
+	return append(buf, b...)
+}
+
+// AppendMsgPackArrayLen appends the header of an array of n items
+func AppendMsgPackArrayLen(buf []byte, n int) []byte {
+	return appendMsgPackLen(buf, 0x90, 0xdc, n)
+}
+
+// AppendMsgPackMapLen appends the header of a map of n entries
+func AppendMsgPackMapLen(buf []byte, n int) []byte {
+	return appendMsgPackLen(buf, 0x80, 0xde, n)
+}
+
+// appendMsgPackLen appends a container header with the given fix and 16
+// bit codes; the 32 bit code follows the 16 bit one
+func appendMsgPackLen(buf []byte, fix, code16 byte, n int) []byte {
+	switch {
+	case n < 16:
+		return append(buf, fix|byte(n))
+	case n <= math.MaxUint16:
+		return binary.BigEndian.AppendUint16(append(buf, code16), uint16(n))
+	default:
+		return binary.BigEndian.AppendUint32(append(buf, code16+1), uint32(n))
+	}
+}
+
+// ResizeMsgPack returns s with length n, reusing and growing it as msgpack
+// does when it decodes an array into an existing slice
+func ResizeMsgPack[S ~[]E, E any](s S, n int) S {
+	switch {
+	case n == 0 && s == nil:
+		return S{}
+	case cap(s) >= n:
+		return s[:n]
+	}
+	s = s[:cap(s)]
+	return append(s, make(S, n-len(s))...)
+}
+
+// ResizeMsgPackStrings is ResizeMsgPack for slices of string, which msgpack
+// decodes on a path of their own
+func ResizeMsgPackStrings[S ~[]string](s S, n int) S {
+	if s == nil {
+		return make(S, n)
+	}
+	return ResizeMsgPack(s, n)
+}
+
+// MsgPackReader reads MessagePack for generated code with the semantics of
+// msgpack.Unmarshal, except that input must hold exactly one value. Its
+// methods record the first error and return zero values after it, so
+// generated code checks for an error only at the end. Reset sets the
+// input.
+type MsgPackReader struct {
+	data  []byte
+	off   int
+	depth int
+	err   error
+}
+
+// Reset makes r read data from the beginning
+func (r *MsgPackReader) Reset(data []byte) {
+	*r = MsgPackReader{data: data}
+}
+
+// End returns the first error, or an error if input follows the value
+// that was read
+func (r *MsgPackReader) End() error {
+	if r.err == nil && r.off != len(r.data) {
+		r.fail()
+	}
+	return r.err
+}
+
+// Nil reads nil if it is next and reports whether it did
+func (r *MsgPackReader) Nil() bool {
+	if r.err != nil || r.off >= len(r.data) || r.data[r.off] != 0xc0 {
+		return false
+	}
+	r.off++
+	return true
+}
+
+// MapLen reads the header of a map and returns its number of entries
+func (r *MsgPackReader) MapLen() int {
+	return r.containerLen(0x80, 0xde, 2)
+}
+
+// ArrayLen reads the header of an array and returns its number of items
+func (r *MsgPackReader) ArrayLen() int {
+	return r.containerLen(0x90, 0xdc, 1)
+}
+
+// containerLen reads a container header with the given fix and 16 bit
+// codes. A count the rest of the input cannot hold, at size bytes per
+// item, is an error rather than an allocation.
+func (r *MsgPackReader) containerLen(fix, code16 byte, size int) int {
+	c := r.code()
+	var n int
+	switch {
+	case r.err != nil:
+		return 0
+	case c&0xf0 == fix:
+		n = int(c & 0x0f)
+	case c == code16:
+		n = int(r.uint(2))
+	case c == code16+1:
+		n = int(r.uint(4))
+	default:
+		r.fail()
+		return 0
+	}
+	if n > (len(r.data)-r.off)/size {
+		r.fail()
+		return 0
+	}
+	return n
+}
+
+// Key reads a map key as msgpack decodes a struct field name, which may be
+// a str, a bin or nil. The result refers to the input.
+func (r *MsgPackReader) Key() []byte {
+	n := r.bytesLen(r.code())
+	if n < 0 {
+		return nil
+	}
+	return r.read(n)
+}
+
+// String reads a str or bin as a string
+func (r *MsgPackReader) String() string {
+	return string(r.Key())
+}
+
+// Bytes reads a str or bin into dst, reusing its storage as msgpack does,
+// or returns nil for nil
+func (r *MsgPackReader) Bytes(dst []byte) []byte {
+	n := r.bytesLen(r.code())
+	if n < 0 || r.err != nil {
+		return nil
+	}
+	b := r.read(n)
+	if r.err != nil {
+		return nil
+	}
+	switch {
+	case dst == nil:
+		dst = make([]byte, n)
+	case n > cap(dst):
+		dst = append(dst, make([]byte, n-len(dst))...)
+	default:
+		dst = dst[:n]
+	}
+	copy(dst, b)
+	return dst
+}
+
+// Bool reads a bool or nil, which is false
+func (r *MsgPackReader) Bool() bool {
+	switch r.code() {
+	case 0xc2, 0xc0:
+		return false
+	case 0xc3:
+		return true
+	}
+	r.fail()
+	return false
+}
+
+// Int reads any integer or nil as an int64, which msgpack truncates to the
+// size of the destination
+func (r *MsgPackReader) Int() int64 {
+	c := r.code()
+	switch {
+	case r.err != nil:
+		return 0
+	case c <= 0x7f || c >= 0xe0:
+		return int64(int8(c))
+	}
+	switch c {
+	case 0xc0:
+		return 0
+	case 0xcc:
+		return int64(r.uint(1))
+	case 0xcd:
+		return int64(r.uint(2))
+	case 0xce:
+		return int64(r.uint(4))
+	case 0xd0:
+		return int64(int8(r.uint(1)))
+	case 0xd1:
+		return int64(int16(r.uint(2)))
+	case 0xd2:
+		return int64(int32(r.uint(4)))
+	case 0xcf, 0xd3:
+		return int64(r.uint(8))
+	}
+	r.fail()
+	return 0
+}
+
+// Uint reads any integer or nil as a uint64, which msgpack truncates to
+// the size of the destination
+func (r *MsgPackReader) Uint() uint64 {
+	return uint64(r.Int())
+}
+
+// Float32 reads a float 32 or nil, which is 0
+func (r *MsgPackReader) Float32() float32 {
+	switch r.code() {
+	case 0xca:
+		return math.Float32frombits(uint32(r.uint(4)))
+	case 0xc0:
+		return 0
+	}
+	r.fail()
+	return 0
+}
+
+// Float64 reads a float 32, a float 64 or nil, which is 0
+func (r *MsgPackReader) Float64() float64 {
+	switch r.code() {
+	case 0xc0:
+		return 0
+	case 0xca:
+		return float64(math.Float32frombits(uint32(r.uint(4))))
+	case 0xcb:
+		return math.Float64frombits(r.uint(8))
+	}
+	r.fail()
+	return 0
+}
+
+// Skip reads and discards a value
+func (r *MsgPackReader) Skip() {
+	c := r.code()
+	if r.err != nil {
+		return
+	}
+	switch {
+	case c <= 0x7f || c >= 0xe0, c == 0xc0, c == 0xc2, c == 0xc3:
+	case c&0xf0 == 0x80, c == 0xde, c == 0xdf:
+		r.off--
+		r.skipItems(2 * r.MapLen())
+	case c&0xf0 == 0x90, c == 0xdc, c == 0xdd:
+		r.off--
+		r.skipItems(r.ArrayLen())
+	case c&0xe0 == 0xa0, c >= 0xc4 && c <= 0xc6, c >= 0xd9 && c <= 0xdb:
+		if n := r.bytesLen(c); n > 0 {
+			r.read(n)
+		}
+	case c >= 0xc7 && c <= 0xc9:
+		n := r.uint(1 << (c - 0xc7))
+		r.read(1 + int(min(n, math.MaxInt32)))
+	case c == 0xca, c == 0xce, c == 0xd2:
+		r.read(4)
+	case c == 0xcb, c == 0xcf, c == 0xd3:
+		r.read(8)
+	case c == 0xcc, c == 0xd0:
+		r.read(1)
+	case c == 0xcd, c == 0xd1:
+		r.read(2)
+	case c >= 0xd4 && c <= 0xd8:
+		r.read(1 + 1<<(c-0xd4))
+	default:
+		r.fail()
+	}
+}
+
+func (r *MsgPackReader) skipItems(n int) {
+	if r.depth++; r.depth > maxSkipDepth {
+		r.fail()
+		return
+	}
+	for i := 0; i < n && r.err == nil; i++ {
+		r.Skip()
+	}
+	r.depth--
+}
+
+func (r *MsgPackReader) fail() {
+	if r.err == nil {
+		r.err = ErrFallback
+	}
+}
+
+// code reads the next format byte
+func (r *MsgPackReader) code() byte {
+	if r.err != nil || r.off >= len(r.data) {
+		r.fail()
+		return 0
+	}
+	c := r.data[r.off]
+	r.off++
+	return c
+}
+
+// read returns the next n bytes of input
+func (r *MsgPackReader) read(n int) []byte {
+	if r.err != nil || n > len(r.data)-r.off {
+		r.fail()
+		return nil
+	}
+	b := r.data[r.off : r.off+n]
+	r.off += n
+	return b
+}
+
+// uint reads a big-endian unsigned integer of n bytes
+func (r *MsgPackReader) uint(n int) uint64 {
+	var v uint64
+	for _, c := range r.read(n) {
+		v = v<<8 | uint64(c)
+	}
+	return v
+}
+
+// bytesLen returns the length given by the header of a str or bin, or -1
+// for nil
+func (r *MsgPackReader) bytesLen(c byte) int {
+	switch {
+	case r.err != nil:
+		return -1
+	case c == 0xc0:
+		return -1
+	case c&0xe0 == 0xa0:
+		return int(c & 0x1f)
+	case c == 0xc4 || c == 0xd9:
+		return int(r.uint(1))
+	case c == 0xc5 || c == 0xda:
+		return int(r.uint(2))
+	case c == 0xc6 || c == 0xdb:
+		return int(r.uint(4))
+	}
+	r.fail()
+	return -1
+}
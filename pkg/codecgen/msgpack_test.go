@@ -0,0 +1,157 @@
+package codecgen
+
+import (
+	"bytes"
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+var testInts = []int64{
+	0, 1, -1, 127, 128, -32, -33, 255, 256, -128, -129, 32767, 32768, -32768, -32769,
+	65535, 65536, math.MaxInt32, math.MaxInt32 + 1, math.MinInt32, math.MinInt32 - 1,
+	math.MaxInt64, math.MinInt64,
+}
+
+// msgpackOf returns the encoding of v by the msgpack library
+func msgpackOf(t *testing.T, v any) []byte {
+	t.Helper()
+	b, err := msgpack.Marshal(v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return b
+}
+
+func TestAppendMsgPack(t *testing.T) {
+	for _, n := range testInts {
+		if got, want := AppendMsgPackInt(nil, n, 64), msgpackOf(t, n); !bytes.Equal(got, want) {
+			t.Errorf("int64 %d: got %x, want %x", n, got, want)
+		}
+		if n == int64(int8(n)) {
+			if got, want := AppendMsgPackInt(nil, n, 8), msgpackOf(t, int8(n)); !bytes.Equal(got, want) {
+				t.Errorf("int8 %d: got %x, want %x", n, got, want)
+			}
+		}
+		u := uint64(n)
+		if got, want := AppendMsgPackUint(nil, u, 64), msgpackOf(t, u); !bytes.Equal(got, want) {
+			t.Errorf("uint64 %d: got %x, want %x", u, got, want)
+		}
+		if u == uint64(uint16(u)) {
+			if got, want := AppendMsgPackUint(nil, u, 16), msgpackOf(t, uint16(u)); !bytes.Equal(got, want) {
+				t.Errorf("uint16 %d: got %x, want %x", u, got, want)
+			}
+		}
+	}
+	for _, f := range []float64{0, -1.5, math.MaxFloat64, math.Inf(-1)} {
+		if got, want := AppendMsgPackFloat64(nil, f), msgpackOf(t, f); !bytes.Equal(got, want) {
+			t.Errorf("float64 %v: got %x, want %x", f, got, want)
+		}
+		if got, want := AppendMsgPackFloat32(nil, float32(f)), msgpackOf(t, float32(f)); !bytes.Equal(got, want) {
+			t.Errorf("float32 %v: got %x, want %x", f, got, want)
+		}
+	}
+	for _, n := range []int{0, 31, 32, 255, 256, 65535, 65536} {
+		s := string(bytes.Repeat([]byte("s"), n))
+		if got, want := AppendMsgPackString(nil, s), msgpackOf(t, s); !bytes.Equal(got, want) {
+			t.Errorf("string of %d: got %x, want %x", n, got[:min(len(got), 8)], want[:min(len(want), 8)])
+		}
+		b := []byte(s)
+		if got, want := AppendMsgPackBytes(nil, b), msgpackOf(t, b); !bytes.Equal(got, want) {
+			t.Errorf("bytes of %d: got %x, want %x", n, got[:min(len(got), 8)], want[:min(len(want), 8)])
+		}
+		items := make([]bool, n)
+		if got, want := AppendMsgPackArrayLen(nil, n), msgpackOf(t, items); !bytes.HasPrefix(want, got) {
+			t.Errorf("array of %d: got %x", n, got)
+		}
+	}
+	if got, want := AppendMsgPackBytes(nil, nil), msgpackOf(t, []byte(nil)); !bytes.Equal(got, want) {
+		t.Errorf("nil bytes: got %x, want %x", got, want)
+	}
+	if got := AppendMsgPackBool(AppendMsgPackNil(nil), true); !bytes.Equal(got, []byte{0xc0, 0xc3}) {
+		t.Errorf("nil, true: got %x", got)
+	}
+}
+
+func TestMsgPackReader_Values(t *testing.T) {
+	for _, n := range testInts {
+		var r MsgPackReader
+		r.Reset(msgpackOf(t, n))
+		if got := r.Int(); r.End() != nil || got != n {
+			t.Errorf("Int(%d) = %d, %v", n, got, r.End())
+		}
+		r.Reset(msgpackOf(t, uint64(n)))
+		if got := r.Uint(); r.End() != nil || got != uint64(n) {
+			t.Errorf("Uint(%d) = %d, %v", uint64(n), got, r.End())
+		}
+	}
+
+	data := msgpackOf(t, map[string]any{"a": []any{"x", 1.5, float32(2), nil, true, []byte("b")}})
+	var r MsgPackReader
+	r.Reset(data)
+	if r.Nil() || r.MapLen() != 1 || string(r.Key()) != "a" || r.ArrayLen() != 6 {
+		t.Fatalf("reading map: %v", r.End())
+	}
+	s, f, f32, null, b, raw := r.String(), r.Float64(), r.Float32(), r.Nil(), r.Bool(), r.Bytes(nil)
+	if err := r.End(); err != nil || s != "x" || f != 1.5 || f32 != 2 || !null || !b || string(raw) != "b" {
+		t.Errorf("read %q %v %v %v %v %q, %v", s, f, f32, null, b, raw, err)
+	}
+
+	r.Reset(append(data, data...))
+	r.Skip()
+	r.Skip()
+	if err := r.End(); err != nil {
+		t.Errorf("Skip: %v", err)
+	}
+}
+
+func TestMsgPackReader_Fails(t *testing.T) {
+	for _, tc := range []struct {
+		data []byte
+		read func(r *MsgPackReader)
+	}{
+		{msgpackOf(t, 1.5), func(r *MsgPackReader) { r.Int() }},
+		{msgpackOf(t, 1), func(r *MsgPackReader) { r.Float64() }},
+		{msgpackOf(t, 1.5), func(r *MsgPackReader) { r.Float32() }},
+		{msgpackOf(t, "s"), func(r *MsgPackReader) { r.Bool() }},
+		{msgpackOf(t, []int{1}), func(r *MsgPackReader) { r.MapLen() }},
+		{msgpackOf(t, "s")[:1], func(r *MsgPackReader) { _ = r.String() }},
+		{[]byte{0xdd, 0xff, 0xff, 0xff, 0xff}, func(r *MsgPackReader) { r.ArrayLen() }},
+		{[]byte{0xc0, 0xc0}, func(r *MsgPackReader) { r.Nil() }},
+	} {
+		var r MsgPackReader
+		r.Reset(tc.data)
+		tc.read(&r)
+		if err := r.End(); err != ErrFallback {
+			t.Errorf("%x: End = %v", tc.data, err)
+		}
+	}
+}
+
+func TestResizeMsgPack(t *testing.T) {
+	s := []int{1, 2, 3}
+	if got := ResizeMsgPack(s, 2); len(got) != 2 || &got[0] != &s[0] {
+		t.Errorf("ResizeMsgPack shrink = %v", got)
+	}
+	if got := ResizeMsgPack(s, 5); len(got) != 5 {
+		t.Errorf("ResizeMsgPack grow = %v", got)
+	}
+
+	// ResizeMsgPackStrings matches the library's decoding of []string
+	for _, prior := range [][]string{nil, {}, {"a", "b", "c"}} {
+		want := append([]string(nil), prior...)
+		if prior == nil {
+			want = nil
+		}
+		if err := msgpack.Unmarshal(msgpackOf(t, []string{"x"}), &want); err != nil {
+			t.Fatal(err)
+		}
+		got := ResizeMsgPackStrings(append([]string(nil), prior...), 1)
+		got[0] = "x"
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("prior %q: got %q, want %q", prior, got, want)
+		}
+	}
+}
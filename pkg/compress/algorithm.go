@@ -0,0 +1,160 @@
+package compress
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"sync"
+)
+
+// Algorithm identifies a compression algorithm. Its value is stored in the
+// header written before compressed data.
+type Algorithm byte
+
+const (
+	// None stores data raw. Codecs use it for data below the minimum size.
+	None Algorithm = iota
+	// Gzip compresses with compress/gzip
+	Gzip
+	// Zlib compresses with compress/zlib
+	Zlib
+	// Flate compresses with compress/flate
+	Flate
+	// Zstd compresses with Zstandard; requires the compress_zstd build tag
+	Zstd
+	// Snappy compresses with the Snappy framing format; requires the
+	// compress_snappy build tag
+	Snappy
+	// LZ4 compresses with the LZ4 frame format; requires the compress_lz4
+	// build tag
+	LZ4
+)
+
+// String returns the lowercase name of the algorithm, e.g. "gzip"
+func (a Algorithm) String() string {
+	switch a {
+	case None:
+		return "none"
+	case Gzip:
+		return "gzip"
+	case Zlib:
+		return "zlib"
+	case Flate:
+		return "flate"
+	case Zstd:
+		return "zstd"
+	case Snappy:
+		return "snappy"
+	case LZ4:
+		return "lz4"
+	default:
+		return fmt.Sprintf("Algorithm(%d)", byte(a))
+	}
+}
+
+// ErrAlgorithmNotSupported is returned for an algorithm that is unknown or
+// not compiled in
+type ErrAlgorithmNotSupported struct {
+	Algorithm Algorithm
+}
+
+func (e ErrAlgorithmNotSupported) Error() string {
+	switch e.Algorithm {
+	case Zstd, Snappy, LZ4:
+		return fmt.Sprintf("compression algorithm %q is not supported in this build; rebuild with -tags compress_%s", e.Algorithm, e.Algorithm)
+	default:
+		return fmt.Sprintf("compression algorithm %q is not supported", e.Algorithm)
+	}
+}
+
+// IsSupported reports whether data can be compressed with a
+func IsSupported(a Algorithm) bool {
+	_, ok := algorithms[a]
+	return ok || a == None
+}
+
+// writer is a compressing writer that can be reused for another stream
+type writer interface {
+	io.WriteCloser
+	Reset(w io.Writer)
+}
+
+// reader is a decompressing reader that can be reused for another stream
+type reader interface {
+	io.Reader
+	Reset(r io.Reader) error
+}
+
+// algorithm creates the writers and readers of one compression algorithm
+type algorithm struct {
+	// newWriter returns a writer compressing to w at level, which is
+	// DefaultLevel for the library's default
+	newWriter func(w io.Writer, level int) (writer, error)
+
+	// newReader returns a reader decompressing from r
+	newReader func(r io.Reader) (reader, error)
+
+	readers sync.Pool
+}
+
+// algorithms holds the algorithms compiled in, other than None
+var algorithms = map[Algorithm]*algorithm{}
+
+// register adds an algorithm. Files built with the tag of an optional
+// algorithm call it from init.
+func register(a Algorithm, newWriter func(io.Writer, int) (writer, error), newReader func(io.Reader) (reader, error)) {
+	algorithms[a] = &algorithm{newWriter: newWriter, newReader: newReader}
+}
+
+// getReader returns a pooled reader decompressing from r
+func (a *algorithm) getReader(r io.Reader) (reader, error) {
+	if rd, _ := a.readers.Get().(reader); rd != nil {
+		if err := rd.Reset(r); err != nil {
+			a.readers.Put(rd)
+			return nil, err
+		}
+		return rd, nil
+	}
+	return a.newReader(r)
+}
+
+// putReader returns rd to the pool
+func (a *algorithm) putReader(rd reader) {
+	a.readers.Put(rd)
+}
+
+// dictReader adapts the readers of compress/flate and compress/zlib, which
+// are reset with an optional dictionary
+type dictReader struct {
+	io.ReadCloser
+}
+
+func (d dictReader) Reset(r io.Reader) error {
+	return d.ReadCloser.(interface {
+		Reset(r io.Reader, dict []byte) error
+	}).Reset(r, nil)
+}
+
+func init() {
+	register(Gzip, func(w io.Writer, level int) (writer, error) {
+		return gzip.NewWriterLevel(w, level)
+	}, func(r io.Reader) (reader, error) {
+		return gzip.NewReader(r)
+	})
+	register(Zlib, func(w io.Writer, level int) (writer, error) {
+		return zlib.NewWriterLevel(w, level)
+	}, func(r io.Reader) (reader, error) {
+		rc, err := zlib.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return dictReader{rc}, nil
+	})
+	register(Flate, func(w io.Writer, level int) (writer, error) {
+		return flate.NewWriter(w, level)
+	}, func(r io.Reader) (reader, error) {
+		return dictReader{flate.NewReader(r)}, nil
+	})
+}
@@ -0,0 +1,302 @@
+// Package compress wraps a codec so that its output is compressed. Every
+// value is written after an eight-byte header naming the algorithm and the
+// length of the body, so Unmarshal and Decode read data compressed with any
+// algorithm compiled in, as well as values stored raw because they were
+// below the minimum size:
+//
+//	c := compress.Wrap[Event](json.New[Event](), compress.Gzip, compress.WithMinSize(256))
+//	data, err := c.Marshal(event)
+//
+// The header is laid out as:
+//
+//	magic "GCZ" | algorithm | body length (uint32 big-endian)
+//
+// Decode reads no further than the body, so values written one after
+// another with Encode are read back by successive calls.
+//
+// Gzip, Zlib and Flate use the standard library. Zstd, Snappy and LZ4 are
+// compiled in with the compress_zstd, compress_snappy and compress_lz4
+// build tags.
+package compress
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sync"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// headerSize is the length of the header written before every value: the
+// magic bytes, the Algorithm and the length of the body
+const headerSize = 8
+
+// magic identifies data written by a Codec
+var magic = [3]byte{'G', 'C', 'Z'}
+
+// errorCodec names the wrapper in the errors it reports
+const errorCodec codec.Type = "compress"
+
+// ErrInvalidHeader is reported, wrapped in a codec.DecodeError, for input
+// that does not start with the header written by a Codec
+var ErrInvalidHeader = errors.New("compress: missing or invalid header")
+
+// errTrailingData is reported for input that continues after the body
+// announced by its header
+var errTrailingData = errors.New("compress: invalid data after value")
+
+// Codec compresses the output of another codec and decompresses its input
+type Codec[T any] struct {
+	inner   codec.Codec[T]
+	algo    Algorithm
+	alg     *algorithm
+	cfg     config
+	writers sync.Pool
+	err     error
+}
+
+// Wrap returns a codec that encodes values with inner and compresses the
+// result with algo. Decoding detects the algorithm from the header, so any
+// algorithm compiled in is accepted regardless of algo. If inner reports an
+// error through an Err method, an option does not apply to compression, or
+// algo is not compiled in, every operation returns the error, which is also
+// reported by Err.
+func Wrap[T any](inner codec.Codec[T], algo Algorithm, opts ...codec.Option) *Codec[T] {
+	c := &Codec[T]{inner: inner, algo: algo, cfg: config{level: DefaultLevel}}
+	if e, ok := inner.(interface{ Err() error }); ok {
+		if c.err = e.Err(); c.err != nil {
+			return c
+		}
+	}
+	if c.err = codec.ApplyOptions(errorCodec, &c.cfg, opts); c.err != nil {
+		return c
+	}
+	if algo == None {
+		return c
+	}
+	alg, ok := algorithms[algo]
+	if !ok {
+		c.err = ErrAlgorithmNotSupported{Algorithm: algo}
+		return c
+	}
+	c.alg = alg
+
+	// Creating the first writer checks the level
+	w, err := alg.newWriter(io.Discard, c.cfg.level)
+	if err != nil {
+		c.err = err
+		return c
+	}
+	c.writers.Put(w)
+	return c
+}
+
+// Err returns the error, if any, caused by the arguments passed to Wrap
+func (c *Codec[T]) Err() error {
+	return c.err
+}
+
+// Algorithm returns the algorithm used to compress values
+func (c *Codec[T]) Algorithm() Algorithm {
+	return c.algo
+}
+
+// Encode writes the compressed encoding of data to w. The value is encoded
+// and compressed in full first, as the header holds the length of the body.
+func (c *Codec[T]) Encode(w io.Writer, data T) error {
+	out, err := c.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// Decode reads a compressed value from r and decodes it into data with the
+// inner codec. It reads no further than the end of the value, so values
+// written one after another with Encode are read back by successive calls.
+func (c *Codec[T]) Decode(r io.Reader, data *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	var hdr [headerSize]byte
+	read, err := io.ReadFull(r, hdr[:])
+	if err == io.EOF {
+		return err
+	}
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return decodeError(err)
+	}
+	algo, n, err := parseHeader(hdr[:read])
+	if err != nil {
+		return err
+	}
+	if algo == None && c.cfg.maxSize > 0 && int64(n) > c.cfg.maxSize {
+		return limitError(c.cfg.maxSize)
+	}
+
+	// The buffer grows as the body arrives, so a length in the header alone
+	// cannot make Decode allocate it
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(io.LimitReader(r, int64(n))); err != nil {
+		return decodeError(err)
+	}
+	if body.Len() < n {
+		return decodeError(io.ErrUnexpectedEOF)
+	}
+	return c.unmarshalBody(algo, body.Bytes(), data)
+}
+
+// Marshal encodes data with the inner codec and compresses the result,
+// storing it raw if it is shorter than the minimum size
+func (c *Codec[T]) Marshal(data T) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	raw, err := c.inner.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	if c.alg == nil || len(raw) < c.cfg.minSize {
+		out := make([]byte, 0, headerSize+len(raw))
+		out = append(out, header(None, 0)...)
+		return setLength(append(out, raw...))
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(headerSize + len(raw)/2)
+	buf.Write(header(c.algo, 0))
+	w, err := c.getWriter(&buf)
+	if err != nil {
+		return nil, err
+	}
+	_, err = w.Write(raw)
+	if cerr := w.Close(); err == nil {
+		err = cerr
+	}
+	c.writers.Put(w)
+	if err != nil {
+		return nil, err
+	}
+	return setLength(buf.Bytes())
+}
+
+// Unmarshal decompresses data with the algorithm named by its header and
+// decodes the result into v with the inner codec
+func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	algo, n, err := parseHeader(data)
+	if err != nil {
+		return err
+	}
+	body := data[headerSize:]
+	if len(body) < n {
+		return decodeError(io.ErrUnexpectedEOF)
+	}
+	if len(body) > n {
+		return codec.DecodeError{Codec: errorCodec, Kind: codec.ErrSyntax, Offset: int64(headerSize + n), Err: errTrailingData}
+	}
+	return c.unmarshalBody(algo, body, v)
+}
+
+// unmarshalBody decompresses body with algo and decodes the result into v
+// with the inner codec
+func (c *Codec[T]) unmarshalBody(algo Algorithm, body []byte, v *T) error {
+	if algo == None {
+		if c.cfg.maxSize > 0 && int64(len(body)) > c.cfg.maxSize {
+			return limitError(c.cfg.maxSize)
+		}
+		return c.inner.Unmarshal(body, v)
+	}
+
+	alg, ok := algorithms[algo]
+	if !ok {
+		return ErrAlgorithmNotSupported{Algorithm: algo}
+	}
+	rd, err := alg.getReader(bytes.NewReader(body))
+	if err != nil {
+		return decodeError(err)
+	}
+	raw, err := io.ReadAll(codec.NewLimitedReader(rd, errorCodec, c.cfg.maxSize))
+	alg.putReader(rd)
+	if err != nil {
+		return decodeError(err)
+	}
+	return c.inner.Unmarshal(raw, v)
+}
+
+// Detect reports the algorithm named by the header of data, and false if
+// data does not start with a header written by a Codec
+func Detect(data []byte) (Algorithm, bool) {
+	algo, _, err := parseHeader(data)
+	return algo, err == nil
+}
+
+// getWriter returns a pooled writer compressing to w
+func (c *Codec[T]) getWriter(w io.Writer) (writer, error) {
+	if cw, _ := c.writers.Get().(writer); cw != nil {
+		cw.Reset(w)
+		return cw, nil
+	}
+	return c.alg.newWriter(w, c.cfg.level)
+}
+
+// header returns the header for a body of n bytes compressed with algo
+func header(algo Algorithm, n int) []byte {
+	hdr := []byte{magic[0], magic[1], magic[2], byte(algo), 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(hdr[len(magic)+1:], uint32(n))
+	return hdr
+}
+
+// setLength stores the length of the body of out in its header
+func setLength(out []byte) ([]byte, error) {
+	n := len(out) - headerSize
+	if n > math.MaxUint32 {
+		return nil, fmt.Errorf("compress: value of %d bytes does not fit the header", n)
+	}
+	binary.BigEndian.PutUint32(out[len(magic)+1:], uint32(n))
+	return out, nil
+}
+
+// parseHeader returns the algorithm and the body length named by the header
+// of data
+func parseHeader(data []byte) (Algorithm, int, error) {
+	n := min(len(data), len(magic))
+	if !bytes.Equal(data[:n], magic[:n]) {
+		return None, 0, codec.DecodeError{Codec: errorCodec, Kind: codec.ErrSyntax, Offset: 0, Err: ErrInvalidHeader}
+	}
+	if len(data) < headerSize {
+		return None, 0, decodeError(io.ErrUnexpectedEOF)
+	}
+	return Algorithm(data[len(magic)]), int(binary.BigEndian.Uint32(data[len(magic)+1:])), nil
+}
+
+// limitError reports a value that decompresses to more than max bytes
+func limitError(max int64) error {
+	return codec.DecodeError{
+		Codec:  errorCodec,
+		Kind:   codec.ErrLimitExceeded,
+		Offset: -1,
+		Err:    codec.LimitError{Limit: "MaxBytes", Max: max},
+	}
+}
+
+// decodeError wraps a decompression failure in a codec.DecodeError, leaving
+// errors that already are one unchanged
+func decodeError(err error) error {
+	var de codec.DecodeError
+	if errors.As(err, &de) {
+		return err
+	}
+	kind := codec.ErrSyntax
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		kind = codec.ErrTruncated
+	}
+	return codec.DecodeError{Codec: errorCodec, Kind: kind, Offset: -1, Err: err}
+}
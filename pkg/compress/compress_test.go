@@ -0,0 +1,264 @@
+//go:build codec_json
+
+package compress
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	codec "github.com/jeremyhahn/go-codec"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+)
+
+type TestStruct struct {
+	Name  string   `json:"name"`
+	Tags  []string `json:"tags"`
+	Notes string   `json:"notes"`
+}
+
+var testData = TestStruct{
+	Name:  "John Doe",
+	Tags:  []string{"a", "b", "c"},
+	Notes: strings.Repeat("compressible text ", 64),
+}
+
+var allAlgorithms = []Algorithm{None, Gzip, Zlib, Flate, Zstd, Snappy, LZ4}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	for _, algo := range allAlgorithms {
+		t.Run(algo.String(), func(t *testing.T) {
+			if !IsSupported(algo) {
+				t.Skipf("%s is not compiled in", algo)
+			}
+			c := Wrap[TestStruct](jsoncodec.New[TestStruct](), algo)
+			if err := c.Err(); err != nil {
+				t.Fatalf("Wrap failed: %v", err)
+			}
+
+			data, err := c.Marshal(testData)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if got, ok := Detect(data); !ok || got != algo {
+				t.Errorf("Detect = %s, %v; want %s", got, ok, algo)
+			}
+			if raw, _ := jsoncodec.New[TestStruct]().Marshal(testData); algo != None && len(data) >= len(raw) {
+				t.Errorf("compressed %d bytes to %d", len(raw), len(data))
+			}
+			var decoded TestStruct
+			if err := c.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if decoded.Notes != testData.Notes || decoded.Name != testData.Name {
+				t.Errorf("Unmarshal = %+v", decoded)
+			}
+
+			var buf bytes.Buffer
+			if err := c.Encode(&buf, testData); err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+			decoded = TestStruct{}
+			if err := c.Decode(&buf, &decoded); err != nil {
+				t.Fatalf("Decode failed: %v", err)
+			}
+			if decoded.Notes != testData.Notes {
+				t.Errorf("Decode = %+v", decoded)
+			}
+		})
+	}
+}
+
+func TestCodec_DetectsAlgorithm(t *testing.T) {
+	gzipData, err := Wrap[TestStruct](jsoncodec.New[TestStruct](), Gzip).Marshal(testData)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	// A codec using another algorithm still reads gzip data
+	var decoded TestStruct
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), Flate)
+	if err := c.Unmarshal(gzipData, &decoded); err != nil || decoded.Notes != testData.Notes {
+		t.Errorf("Unmarshal = %+v, %v", decoded, err)
+	}
+	decoded = TestStruct{}
+	if err := c.Decode(bytes.NewReader(gzipData), &decoded); err != nil || decoded.Notes != testData.Notes {
+		t.Errorf("Decode = %+v, %v", decoded, err)
+	}
+}
+
+func TestCodec_MinSize(t *testing.T) {
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), Gzip, WithMinSize(4096))
+	small := TestStruct{Name: "small"}
+
+	data, err := c.Marshal(small)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if algo, _ := Detect(data); algo != None {
+		t.Errorf("a small value was stored with %s", algo)
+	}
+	if !bytes.Contains(data, []byte(`"small"`)) {
+		t.Errorf("raw value not found in %q", data)
+	}
+
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, small); err != nil || !bytes.Equal(buf.Bytes(), data) {
+		t.Errorf("Encode = %q, %v; want %q", buf.Bytes(), err, data)
+	}
+
+	large := TestStruct{Notes: strings.Repeat("x", 8192)}
+	if data, _ := c.Marshal(large); data[3] != byte(Gzip) {
+		t.Errorf("a large value was stored with %s", Algorithm(data[3]))
+	}
+}
+
+func TestCodec_MaxDecompressedSize(t *testing.T) {
+	bomb := TestStruct{Notes: strings.Repeat("0", 1<<20)}
+	for _, algo := range []Algorithm{None, Gzip} {
+		data, err := Wrap[TestStruct](jsoncodec.New[TestStruct](), algo).Marshal(bomb)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+
+		c := Wrap[TestStruct](jsoncodec.New[TestStruct](), Gzip, WithMaxDecompressedSize(64*1024))
+		var v TestStruct
+		if err := c.Unmarshal(data, &v); !errors.Is(err, codec.ErrLimitExceeded) {
+			t.Errorf("%s: Unmarshal error = %v, want ErrLimitExceeded", algo, err)
+		}
+		if err := c.Decode(bytes.NewReader(data), &v); !errors.Is(err, codec.ErrLimitExceeded) {
+			t.Errorf("%s: Decode error = %v, want ErrLimitExceeded", algo, err)
+		}
+	}
+}
+
+func TestCodec_DecodeConsecutive(t *testing.T) {
+	second := TestStruct{Name: "Jane Doe", Tags: []string{"d"}}
+	for _, algo := range allAlgorithms {
+		t.Run(algo.String(), func(t *testing.T) {
+			if !IsSupported(algo) {
+				t.Skipf("%s is not compiled in", algo)
+			}
+			// The second value is below the minimum size and stored raw
+			c := Wrap[TestStruct](jsoncodec.New[TestStruct](), algo, WithMinSize(100))
+
+			var buf bytes.Buffer
+			for _, v := range []TestStruct{testData, second} {
+				if err := c.Encode(&buf, v); err != nil {
+					t.Fatalf("Encode failed: %v", err)
+				}
+			}
+			var first, next TestStruct
+			if err := c.Decode(&buf, &first); err != nil {
+				t.Fatalf("Decode of the first value failed: %v", err)
+			}
+			if err := c.Decode(&buf, &next); err != nil {
+				t.Fatalf("Decode of the second value failed: %v", err)
+			}
+			if first.Notes != testData.Notes || next.Name != second.Name {
+				t.Errorf("Decode = %+v, %+v", first, next)
+			}
+			if err := c.Decode(&buf, &next); err != io.EOF {
+				t.Errorf("Decode at the end = %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestCodec_InvalidInput(t *testing.T) {
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), Gzip)
+	valid, _ := c.Marshal(testData)
+
+	for _, tt := range []struct {
+		name string
+		data []byte
+		kind codec.ErrorKind
+	}{
+		{"no header", []byte(`{"name":"x"}`), codec.ErrSyntax},
+		{"short header", []byte("GC"), codec.ErrTruncated},
+		{"truncated body", valid[:len(valid)/2], codec.ErrTruncated},
+		{"corrupt body", append(header(Gzip, 13), "not gzip data"...), codec.ErrSyntax},
+		{"trailing data", append(valid[:len(valid):len(valid)], 0), codec.ErrSyntax},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var v TestStruct
+			err := c.Unmarshal(tt.data, &v)
+			var de codec.DecodeError
+			if !errors.As(err, &de) || de.Kind != tt.kind {
+				t.Errorf("Unmarshal error = %v, want %s", err, tt.kind)
+			}
+			if tt.name == "trailing data" {
+				return
+			}
+			err = c.Decode(bytes.NewReader(tt.data), &v)
+			if !errors.As(err, &de) || de.Kind != tt.kind {
+				t.Errorf("Decode error = %v, want %s", err, tt.kind)
+			}
+		})
+	}
+
+	var v TestStruct
+	if err := c.Unmarshal([]byte("xyz1"), &v); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("expected ErrInvalidHeader, got %v", err)
+	}
+	if err := c.Unmarshal(append(header(Algorithm(200), 1), 0), &v); !errors.As(err, new(ErrAlgorithmNotSupported)) {
+		t.Errorf("expected ErrAlgorithmNotSupported, got %v", err)
+	}
+	if err := c.Decode(bytes.NewReader(nil), &v); err != io.EOF {
+		t.Errorf("Decode of empty input = %v, want io.EOF", err)
+	}
+}
+
+func TestWrap_Errors(t *testing.T) {
+	var notSupported codec.ErrOptionNotSupported
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), Gzip, jsoncodec.WithIndent("", "  "))
+	if !errors.As(c.Err(), &notSupported) {
+		t.Errorf("expected ErrOptionNotSupported, got %v", c.Err())
+	}
+
+	c = Wrap[TestStruct](jsoncodec.New[TestStruct](codec.NewOption("other.WithThing", func(*struct{}) {})), Gzip)
+	if !errors.As(c.Err(), &notSupported) {
+		t.Errorf("expected the inner codec's error, got %v", c.Err())
+	}
+
+	c = Wrap[TestStruct](jsoncodec.New[TestStruct](), Gzip, WithLevel(42))
+	if c.Err() == nil {
+		t.Error("expected an error for an invalid level")
+	}
+	if _, err := c.Marshal(testData); err == nil {
+		t.Error("expected Marshal to return the error")
+	}
+
+	c = Wrap[TestStruct](jsoncodec.New[TestStruct](), Algorithm(200))
+	if !errors.As(c.Err(), new(ErrAlgorithmNotSupported)) {
+		t.Errorf("expected ErrAlgorithmNotSupported, got %v", c.Err())
+	}
+}
+
+func TestCodec_Levels(t *testing.T) {
+	fast, _ := Wrap[TestStruct](jsoncodec.New[TestStruct](), Gzip, WithLevel(1)).Marshal(testData)
+	best, _ := Wrap[TestStruct](jsoncodec.New[TestStruct](), Gzip, WithLevel(9)).Marshal(testData)
+	stored, _ := Wrap[TestStruct](jsoncodec.New[TestStruct](), Gzip, WithLevel(0)).Marshal(testData)
+	if len(best) > len(fast) || len(stored) <= len(fast) {
+		t.Errorf("sizes at levels 0, 1 and 9: %d, %d, %d", len(stored), len(fast), len(best))
+	}
+}
+
+func BenchmarkCodec_Marshal(b *testing.B) {
+	for _, algo := range allAlgorithms {
+		if !IsSupported(algo) {
+			continue
+		}
+		b.Run(algo.String(), func(b *testing.B) {
+			c := Wrap[TestStruct](jsoncodec.New[TestStruct](), algo)
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.Marshal(testData); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
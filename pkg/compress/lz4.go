@@ -0,0 +1,48 @@
+//go:build compress_lz4
+
+package compress
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/pierrec/lz4/v4"
+)
+
+// lz4Reader adapts lz4.Reader, whose Reset does not fail
+type lz4Reader struct {
+	*lz4.Reader
+}
+
+func (l lz4Reader) Reset(r io.Reader) error {
+	l.Reader.Reset(r)
+	return nil
+}
+
+// lz4Level returns the lz4 compression level for level 0 to 9
+func lz4Level(level int) (lz4.CompressionLevel, error) {
+	switch {
+	case level == DefaultLevel || level == 0:
+		return lz4.Fast, nil
+	case level >= 1 && level <= 9:
+		return lz4.CompressionLevel(1 << (8 + level)), nil
+	default:
+		return 0, fmt.Errorf("compress: invalid lz4 level %d", level)
+	}
+}
+
+func init() {
+	register(LZ4, func(w io.Writer, level int) (writer, error) {
+		l, err := lz4Level(level)
+		if err != nil {
+			return nil, err
+		}
+		zw := lz4.NewWriter(w)
+		if err := zw.Apply(lz4.CompressionLevelOption(l)); err != nil {
+			return nil, err
+		}
+		return zw, nil
+	}, func(r io.Reader) (reader, error) {
+		return lz4Reader{lz4.NewReader(r)}, nil
+	})
+}
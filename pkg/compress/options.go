@@ -0,0 +1,40 @@
+package compress
+
+import codec "github.com/jeremyhahn/go-codec"
+
+// DefaultLevel selects the default compression level of each algorithm
+const DefaultLevel = -1
+
+// config holds the settings applied by compression options
+type config struct {
+	level   int
+	minSize int
+	maxSize int64
+}
+
+// WithLevel sets the compression level. Gzip, Zlib and Flate accept the
+// levels of compress/flate, from flate.HuffmanOnly to flate.BestCompression;
+// Zstd accepts the zstd levels 1 to 22 and LZ4 the levels 0 (fast) to 9.
+// Snappy has no levels and ignores it.
+func WithLevel(level int) codec.Option {
+	return codec.NewOption("compress.WithLevel", func(c *config) {
+		c.level = level
+	})
+}
+
+// WithMinSize stores encoded values shorter than n bytes raw, as
+// compressing them costs more than it saves
+func WithMinSize(n int) codec.Option {
+	return codec.NewOption("compress.WithMinSize", func(c *config) {
+		c.minSize = n
+	})
+}
+
+// WithMaxDecompressedSize rejects compressed input that expands to more
+// than n bytes, guarding against decompression bombs. Exceeding it is
+// reported as a codec.DecodeError of kind codec.ErrLimitExceeded.
+func WithMaxDecompressedSize(n int64) codec.Option {
+	return codec.NewOption("compress.WithMaxDecompressedSize", func(c *config) {
+		c.maxSize = n
+	})
+}
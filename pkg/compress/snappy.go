@@ -0,0 +1,27 @@
+//go:build compress_snappy
+
+package compress
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/snappy"
+)
+
+// snappyReader adapts snappy.Reader, whose Reset does not fail
+type snappyReader struct {
+	*snappy.Reader
+}
+
+func (s snappyReader) Reset(r io.Reader) error {
+	s.Reader.Reset(r)
+	return nil
+}
+
+func init() {
+	register(Snappy, func(w io.Writer, level int) (writer, error) {
+		return snappy.NewBufferedWriter(w), nil
+	}, func(r io.Reader) (reader, error) {
+		return snappyReader{snappy.NewReader(r)}, nil
+	})
+}
@@ -0,0 +1,23 @@
+//go:build compress_zstd
+
+package compress
+
+import (
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+func init() {
+	register(Zstd, func(w io.Writer, level int) (writer, error) {
+		opts := []zstd.EOption{zstd.WithEncoderConcurrency(1)}
+		if level != DefaultLevel {
+			opts = append(opts, zstd.WithEncoderLevel(zstd.EncoderLevelFromZstd(level)))
+		}
+		return zstd.NewWriter(w, opts...)
+	}, func(r io.Reader) (reader, error) {
+		// A single goroutine decodes synchronously, so pooled decoders
+		// need not be closed
+		return zstd.NewReader(r, zstd.WithDecoderConcurrency(1))
+	})
+}
@@ -0,0 +1,96 @@
+package factory
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	"github.com/jeremyhahn/go-codec"
+	avrocodec "github.com/jeremyhahn/go-codec/pkg/avro"
+)
+
+// ErrUnrecognizedFormat is returned by AutoDecoder for input whose format
+// codec.Detect does not recognize
+var ErrUnrecognizedFormat = errors.New("factory: unrecognized input format")
+
+// detectable lists the codec types that codec.Detect can report
+var detectable = []codec.Type{codec.JSON, codec.YAML, codec.TOML, codec.BSON, codec.CBOR, codec.Avro}
+
+// AutoDecoder decodes input of unknown format, choosing the codec from the
+// leading bytes of each input with codec.Detect. Avro input must be an
+// object container file holding one record, or use the single-object
+// encoding with the schema inferred for T.
+type AutoDecoder[T any] struct {
+	codecs   map[codec.Type]codec.Codec[T]
+	avro     *avrocodec.Codec[T]
+	maxBytes int64
+}
+
+// autoConfig collects the shared options given to NewAutoDecoder
+type autoConfig struct {
+	codec.Config
+}
+
+// NewAutoDecoder creates a decoder for every format that codec.Detect
+// recognizes and that is compiled in. The options are given to each of
+// those codecs, so only options that apply to all codecs, such as
+// codec.WithLimits and codec.WithStrict, may be used.
+func NewAutoDecoder[T any](opts ...codec.Option) (*AutoDecoder[T], error) {
+	a := &AutoDecoder[T]{codecs: make(map[codec.Type]codec.Codec[T])}
+	for _, t := range detectable {
+		if !codec.IsSupported(t) {
+			continue
+		}
+		if t == codec.Avro {
+			a.avro = avrocodec.New[T](opts...)
+			if err := a.avro.Err(); err != nil {
+				return nil, err
+			}
+			continue
+		}
+		c, err := New[T](t, opts...)
+		if err != nil {
+			return nil, err
+		}
+		a.codecs[t] = c
+	}
+
+	var cfg autoConfig
+	for _, opt := range opts {
+		// Options that do not apply to every codec were rejected above
+		_ = codec.ApplyOptions("", &cfg, []codec.Option{opt})
+	}
+	a.maxBytes = cfg.Limits.MaxBytes
+	return a, nil
+}
+
+// Unmarshal detects the format of data and deserializes it into v. It
+// returns ErrUnrecognizedFormat if the format is not recognized, and
+// codec.ErrCodecNotSupported if the codec for it is not compiled in.
+func (a *AutoDecoder[T]) Unmarshal(data []byte, v *T) error {
+	t, confidence := codec.Detect(data)
+	if confidence == codec.ConfidenceNone {
+		return ErrUnrecognizedFormat
+	}
+	if t == codec.Avro && a.avro != nil {
+		if bytes.HasPrefix(data, []byte("Obj\x01")) {
+			return a.avro.UnmarshalContainer(data, v)
+		}
+		return a.avro.UnmarshalSingleObject(data, v)
+	}
+	c, ok := a.codecs[t]
+	if !ok {
+		return codec.ErrCodecNotSupported{CodecType: t}
+	}
+	return c.Unmarshal(data, v)
+}
+
+// Decode reads r to the end and deserializes it into v as Unmarshal does.
+// With codec.WithLimits, no more than MaxBytes bytes are read.
+func (a *AutoDecoder[T]) Decode(r io.Reader, v *T) error {
+	data, err := io.ReadAll(codec.NewLimitedReader(r, "", a.maxBytes))
+	if err != nil {
+		return err
+	}
+	return a.Unmarshal(data, v)
+}
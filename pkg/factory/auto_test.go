@@ -0,0 +1,112 @@
+//go:build codec_json && codec_yaml && codec_toml && codec_msgpack && codec_bson && codec_cbor && codec_avro && codec_protobuf
+
+package factory
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/hamba/avro/v2/ocf"
+	"github.com/jeremyhahn/go-codec"
+	avrocodec "github.com/jeremyhahn/go-codec/pkg/avro"
+	bsoncodec "github.com/jeremyhahn/go-codec/pkg/bson"
+	cborcodec "github.com/jeremyhahn/go-codec/pkg/cbor"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+	msgpackcodec "github.com/jeremyhahn/go-codec/pkg/msgpack"
+	tomlcodec "github.com/jeremyhahn/go-codec/pkg/toml"
+	yamlcodec "github.com/jeremyhahn/go-codec/pkg/yaml"
+)
+
+func TestAutoDecoder(t *testing.T) {
+	data := TestData{Name: "test", Value: 42}
+	marshal := func(c codec.Codec[TestData]) []byte {
+		t.Helper()
+		out, err := c.Marshal(data)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		return out
+	}
+
+	avroCodec := avrocodec.New[TestData]()
+	singleObject, err := avroCodec.MarshalSingleObject(data)
+	if err != nil {
+		t.Fatalf("MarshalSingleObject failed: %v", err)
+	}
+	var container bytes.Buffer
+	enc, err := ocf.NewEncoder(avroCodec.SchemaJSON(), &container)
+	if err != nil {
+		t.Fatalf("NewEncoder failed: %v", err)
+	}
+	if err := enc.Encode(data); err != nil || enc.Close() != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	inputs := map[string][]byte{
+		"json":              marshal(jsoncodec.New[TestData]()),
+		"yaml":              marshal(yamlcodec.New[TestData]()),
+		"toml":              marshal(tomlcodec.New[TestData]()),
+		"bson":              marshal(bsoncodec.New[TestData]()),
+		"cbor":              append([]byte{0xd9, 0xd9, 0xf7}, marshal(cborcodec.New[TestData]())...),
+		"avro single":       singleObject,
+		"avro container":    container.Bytes(),
+		"yaml with marker":  append([]byte("---\n"), marshal(yamlcodec.New[TestData]())...),
+		"toml with comment": append([]byte("# partner export\n"), marshal(tomlcodec.New[TestData]())...),
+	}
+
+	auto, err := NewAutoDecoder[TestData](codec.WithStrict())
+	if err != nil {
+		t.Fatalf("NewAutoDecoder failed: %v", err)
+	}
+	for name, input := range inputs {
+		t.Run(name, func(t *testing.T) {
+			var decoded TestData
+			if err := auto.Unmarshal(input, &decoded); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if decoded != data {
+				t.Errorf("Data mismatch: got %+v, want %+v", decoded, data)
+			}
+
+			decoded = TestData{}
+			if err := auto.Decode(bytes.NewReader(input), &decoded); err != nil || decoded != data {
+				t.Errorf("Decode: got %+v, %v", decoded, err)
+			}
+		})
+	}
+}
+
+func TestAutoDecoder_Unrecognized(t *testing.T) {
+	auto, err := NewAutoDecoder[TestData]()
+	if err != nil {
+		t.Fatalf("NewAutoDecoder failed: %v", err)
+	}
+
+	data, _ := msgpackcodec.New[TestData]().Marshal(TestData{Name: "test"})
+	var v TestData
+	if err := auto.Unmarshal(data, &v); !errors.Is(err, ErrUnrecognizedFormat) {
+		t.Errorf("Expected ErrUnrecognizedFormat, got %v", err)
+	}
+}
+
+func TestAutoDecoder_Limits(t *testing.T) {
+	auto, err := NewAutoDecoder[TestData](codec.WithLimits(codec.Limits{MaxBytes: 8}))
+	if err != nil {
+		t.Fatalf("NewAutoDecoder failed: %v", err)
+	}
+
+	var v TestData
+	err = auto.Decode(bytes.NewReader([]byte(`{"name": "test", "value": 42}`)), &v)
+	if !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Errorf("Expected ErrLimitExceeded, got %v", err)
+	}
+}
+
+func TestAutoDecoder_OptionNotSupported(t *testing.T) {
+	_, err := NewAutoDecoder[TestData](jsoncodec.WithDisallowUnknownFields())
+	var notSupported codec.ErrOptionNotSupported
+	if !errors.As(err, &notSupported) {
+		t.Errorf("Expected ErrOptionNotSupported, got %v", err)
+	}
+}
@@ -14,48 +14,150 @@ import (
 	yamlcodec "github.com/jeremyhahn/go-codec/pkg/yaml"
 )
 
-// New creates a new codec of the specified type.
-// Returns an error if the codec type is not supported or not compiled in.
+// configuredCodec is a codec that reports errors caused by its constructor options
+type configuredCodec[T any] interface {
+	codec.Codec[T]
+	Err() error
+}
+
+// New creates a new codec of the specified type configured with the given
+// options. Returns an error if the codec type is not supported or not
+// compiled in, or if an option does not apply to the codec type. Codec
+// types registered by other packages with codec.Register are created by
+// their Provider.
 //
 // Note: For Protocol Buffers, use NewProtoBuf instead as it requires
 // types that implement proto.Message.
 //
 // Use codec.IsSupported() to check if a codec is available before calling this.
 // Use codec.SupportedCodecs() to get a list of all available codecs.
-func New[T any](codecType codec.Type) (codec.Codec[T], error) {
+func New[T any](codecType codec.Type, opts ...codec.Option) (codec.Codec[T], error) {
+	if p, ok := codec.ProviderFor(codecType); ok {
+		return newFromProvider[T](p, opts)
+	}
+
 	// Check if the codec is compiled in
 	if !codec.IsSupported(codecType) {
 		return nil, codec.ErrCodecNotSupported{CodecType: codecType}
 	}
 
+	var c configuredCodec[T]
 	switch codecType {
 	case codec.JSON:
-		return jsoncodec.New[T](), nil
+		c = jsoncodec.New[T](opts...)
 	case codec.YAML:
-		return yamlcodec.New[T](), nil
+		c = yamlcodec.New[T](opts...)
 	case codec.TOML:
-		return tomlcodec.New[T](), nil
+		c = tomlcodec.New[T](opts...)
 	case codec.MsgPack:
-		return msgpackcodec.New[T](), nil
+		c = msgpackcodec.New[T](opts...)
 	case codec.BSON:
-		return bsoncodec.New[T](), nil
+		c = bsoncodec.New[T](opts...)
 	case codec.CBOR:
-		return cborcodec.New[T](), nil
+		c = cborcodec.New[T](opts...)
 	case codec.Avro:
-		return avrocodec.New[T](), nil
+		c = avrocodec.New[T](opts...)
 	case codec.ProtoBuf:
 		return nil, fmt.Errorf("use NewProtoBuf for Protocol Buffers (requires proto.Message)")
 	default:
 		return nil, fmt.Errorf("unsupported codec type: %s", codecType)
 	}
+
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
 }
 
-// NewProtoBuf creates a new Protocol Buffers codec.
+// NewStream creates a new codec of the specified type that also supports
+// encoder and decoder sessions for reading and writing many values over a
+// single stream. It accepts the same codec types as New.
+func NewStream[T any](codecType codec.Type, opts ...codec.Option) (codec.StreamCodec[T], error) {
+	c, err := New[T](codecType, opts...)
+	if err != nil {
+		return nil, err
+	}
+	sc, ok := c.(codec.StreamCodec[T])
+	if !ok {
+		return nil, fmt.Errorf("codec %q does not support streaming", codecType)
+	}
+	return sc, nil
+}
+
+// configuredOptimizedCodec is an optimized codec that reports errors caused
+// by its constructor options
+type configuredOptimizedCodec[T any] interface {
+	codec.OptimizedCodec[T]
+	Err() error
+}
+
+// NewOptimized creates a new codec of the specified type that also supports
+// encoding into and decoding with caller-provided buffers. It accepts the
+// same built-in codec types as New. Codec types registered with
+// codec.Register are not supported.
+//
+// Note: For Protocol Buffers, use NewOptimizedProtoBuf instead as it
+// requires types that implement proto.Message.
+func NewOptimized[T any](codecType codec.Type, opts ...codec.Option) (codec.OptimizedCodec[T], error) {
+	if _, ok := codec.ProviderFor(codecType); ok {
+		return nil, fmt.Errorf("codec %q does not support buffer reuse", codecType)
+	}
+	if !codec.IsSupported(codecType) {
+		return nil, codec.ErrCodecNotSupported{CodecType: codecType}
+	}
+
+	var c configuredOptimizedCodec[T]
+	switch codecType {
+	case codec.JSON:
+		c = jsoncodec.NewPool[T](opts...)
+	case codec.YAML:
+		c = yamlcodec.NewPool[T](opts...)
+	case codec.TOML:
+		c = tomlcodec.NewPool[T](opts...)
+	case codec.MsgPack:
+		c = msgpackcodec.NewPool[T](opts...)
+	case codec.BSON:
+		c = bsoncodec.NewPool[T](opts...)
+	case codec.CBOR:
+		c = cborcodec.NewPool[T](opts...)
+	case codec.Avro:
+		c = avrocodec.NewPool[T](opts...)
+	case codec.ProtoBuf:
+		return nil, fmt.Errorf("use NewOptimizedProtoBuf for Protocol Buffers (requires proto.Message)")
+	default:
+		return nil, fmt.Errorf("unsupported codec type: %s", codecType)
+	}
+
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewProtoBuf creates a new Protocol Buffers codec configured with the given options.
 // T must be a protobuf-generated type that implements proto.Message.
-// Returns an error if protobuf codec is not compiled in.
-func NewProtoBuf[T protobufcodec.ProtoMessage]() (codec.Codec[T], error) {
+// Returns an error if protobuf codec is not compiled in or an option does not apply.
+func NewProtoBuf[T protobufcodec.ProtoMessage](opts ...codec.Option) (codec.Codec[T], error) {
+	if !codec.IsSupported(codec.ProtoBuf) {
+		return nil, codec.ErrCodecNotSupported{CodecType: codec.ProtoBuf}
+	}
+	c := protobufcodec.New[T](opts...)
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+// NewOptimizedProtoBuf creates a new optimized Protocol Buffers codec
+// configured with the given options. T must be a protobuf-generated type
+// that implements proto.Message.
+func NewOptimizedProtoBuf[T protobufcodec.ProtoMessage](opts ...codec.Option) (codec.OptimizedCodec[T], error) {
 	if !codec.IsSupported(codec.ProtoBuf) {
 		return nil, codec.ErrCodecNotSupported{CodecType: codec.ProtoBuf}
 	}
-	return protobufcodec.New[T](), nil
+	c := protobufcodec.NewPool[T](opts...)
+	if err := c.Err(); err != nil {
+		return nil, err
+	}
+	return c, nil
 }
@@ -4,9 +4,12 @@ package factory
 
 import (
 	"bytes"
+	"errors"
 	"testing"
 
 	"github.com/jeremyhahn/go-codec"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+	protobufcodec "github.com/jeremyhahn/go-codec/pkg/protobuf"
 	"github.com/jeremyhahn/go-codec/pkg/protobuf/testdata"
 )
 
@@ -284,3 +287,138 @@ func TestNew_RegisteredButUnhandledCodec(t *testing.T) {
 		t.Errorf("Expected error containing %q, got %q", expectedMsg, err.Error())
 	}
 }
+
+func TestNewStream(t *testing.T) {
+	for _, codecType := range []codec.Type{codec.JSON, codec.YAML, codec.MsgPack, codec.BSON, codec.CBOR, codec.Avro} {
+		t.Run(string(codecType), func(t *testing.T) {
+			c, err := NewStream[TestData](codecType)
+			if err != nil {
+				t.Fatalf("Failed to create %s stream codec: %v", codecType, err)
+			}
+
+			values := []TestData{{Name: "first", Value: 1}, {Name: "second", Value: 2}}
+
+			var buf bytes.Buffer
+			enc := c.NewEncoder(&buf)
+			for _, v := range values {
+				if err := enc.Encode(v); err != nil {
+					t.Fatalf("Failed to encode: %v", err)
+				}
+			}
+
+			dec := c.NewDecoder(&buf)
+			for _, want := range values {
+				var got TestData
+				if err := dec.Decode(&got); err != nil {
+					t.Fatalf("Failed to decode: %v", err)
+				}
+				if got != want {
+					t.Errorf("Data mismatch: got %+v, want %+v", got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestNewStream_Unsupported(t *testing.T) {
+	if _, err := NewStream[TestData]("unsupported"); err == nil {
+		t.Fatal("Expected error for unsupported codec type")
+	}
+}
+
+func TestNewOptimized(t *testing.T) {
+	for _, codecType := range []codec.Type{codec.JSON, codec.YAML, codec.TOML, codec.MsgPack, codec.BSON, codec.CBOR, codec.Avro} {
+		t.Run(string(codecType), func(t *testing.T) {
+			c, err := NewOptimized[TestData](codecType)
+			if err != nil {
+				t.Fatalf("Failed to create %s optimized codec: %v", codecType, err)
+			}
+
+			data := TestData{Name: "test", Value: 42}
+			encoded, err := c.AppendMarshal([]byte("prefix"), data)
+			if err != nil {
+				t.Fatalf("Failed to marshal: %v", err)
+			}
+
+			var decoded TestData
+			if err := c.UnmarshalFrom(encoded[6:], &decoded, make([]byte, 0, 64)); err != nil {
+				t.Fatalf("Failed to unmarshal: %v", err)
+			}
+			if decoded != data {
+				t.Errorf("Data mismatch: got %+v, want %+v", decoded, data)
+			}
+		})
+	}
+}
+
+func TestNewOptimized_Errors(t *testing.T) {
+	if _, err := NewOptimized[TestData]("unsupported"); err == nil {
+		t.Error("Expected error for unsupported codec type")
+	}
+	if _, err := NewOptimized[TestData](codec.ProtoBuf); err == nil {
+		t.Error("Expected error for ProtoBuf codec type")
+	}
+	var notSupported codec.ErrOptionNotSupported
+	if _, err := NewOptimized[TestData](codec.YAML, jsoncodec.WithIndent("", "  ")); !errors.As(err, &notSupported) {
+		t.Errorf("Expected ErrOptionNotSupported, got %v", err)
+	}
+}
+
+func TestNewOptimizedProtoBuf(t *testing.T) {
+	c, err := NewOptimizedProtoBuf[*testdata.TestMessage](protobufcodec.WithDeterministic())
+	if err != nil {
+		t.Fatalf("Failed to create ProtoBuf codec: %v", err)
+	}
+
+	data := &testdata.TestMessage{Name: "John Doe", Age: 30}
+	encoded, err := c.MarshalTo(make([]byte, 0, 64), data)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	decoded := &testdata.TestMessage{}
+	if err := c.UnmarshalFrom(encoded, &decoded, nil); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if decoded.Name != data.Name || decoded.Age != data.Age {
+		t.Errorf("Data mismatch: got %+v, want %+v", decoded, data)
+	}
+}
+
+func TestNew_WithOptions(t *testing.T) {
+	c, err := New[TestData](codec.JSON, jsoncodec.WithIndent("", "  "))
+	if err != nil {
+		t.Fatalf("Failed to create JSON codec: %v", err)
+	}
+
+	encoded, err := c.Marshal(TestData{Name: "test", Value: 42})
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if !bytes.Contains(encoded, []byte("\n  \"name\"")) {
+		t.Errorf("Expected indented output, got %q", encoded)
+	}
+}
+
+func TestNew_OptionNotSupported(t *testing.T) {
+	_, err := New[TestData](codec.YAML, jsoncodec.WithIndent("", "  "))
+
+	var notSupported codec.ErrOptionNotSupported
+	if !errors.As(err, &notSupported) {
+		t.Fatalf("Expected ErrOptionNotSupported, got %v", err)
+	}
+	if notSupported.CodecType != codec.YAML {
+		t.Errorf("Expected codec type %q, got %q", codec.YAML, notSupported.CodecType)
+	}
+}
+
+func TestNewProtoBuf_WithOptions(t *testing.T) {
+	if _, err := NewProtoBuf[*testdata.TestMessage](protobufcodec.WithDeterministic()); err != nil {
+		t.Fatalf("Failed to create ProtoBuf codec: %v", err)
+	}
+
+	_, err := NewProtoBuf[*testdata.TestMessage](jsoncodec.WithIndent("", "  "))
+	var notSupported codec.ErrOptionNotSupported
+	if !errors.As(err, &notSupported) {
+		t.Fatalf("Expected ErrOptionNotSupported, got %v", err)
+	}
+}
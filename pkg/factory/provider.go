@@ -0,0 +1,76 @@
+package factory
+
+import (
+	"io"
+	"reflect"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+// newFromProvider creates a codec for T from a registered provider
+func newFromProvider[T any](p codec.Provider, opts []codec.Option) (codec.Codec[T], error) {
+	c, err := p.New(reflect.TypeFor[T](), opts...)
+	if err != nil {
+		return nil, err
+	}
+	if sc, ok := c.(codec.StreamCodec[any]); ok {
+		return &streamAdapter[T]{adapter[T]{c}, sc}, nil
+	}
+	return &adapter[T]{c}, nil
+}
+
+// adapter exposes a provider's Codec[any] as a Codec[T]
+type adapter[T any] struct {
+	c codec.Codec[any]
+}
+
+func (a *adapter[T]) Encode(w io.Writer, data T) error {
+	return a.c.Encode(w, data)
+}
+
+func (a *adapter[T]) Decode(r io.Reader, data *T) error {
+	var target any = data
+	return a.c.Decode(r, &target)
+}
+
+func (a *adapter[T]) Marshal(data T) ([]byte, error) {
+	return a.c.Marshal(data)
+}
+
+func (a *adapter[T]) Unmarshal(data []byte, v *T) error {
+	var target any = v
+	return a.c.Unmarshal(data, &target)
+}
+
+// streamAdapter exposes a provider's StreamCodec[any] as a StreamCodec[T]
+type streamAdapter[T any] struct {
+	adapter[T]
+	sc codec.StreamCodec[any]
+}
+
+func (a *streamAdapter[T]) NewEncoder(w io.Writer) codec.Encoder[T] {
+	return encoderAdapter[T]{a.sc.NewEncoder(w)}
+}
+
+func (a *streamAdapter[T]) NewDecoder(r io.Reader) codec.Decoder[T] {
+	return decoderAdapter[T]{a.sc.NewDecoder(r)}
+}
+
+// encoderAdapter exposes an Encoder[any] as an Encoder[T]
+type encoderAdapter[T any] struct {
+	enc codec.Encoder[any]
+}
+
+func (e encoderAdapter[T]) Encode(data T) error {
+	return e.enc.Encode(data)
+}
+
+// decoderAdapter exposes a Decoder[any] as a Decoder[T]
+type decoderAdapter[T any] struct {
+	dec codec.Decoder[any]
+}
+
+func (d decoderAdapter[T]) Decode(data *T) error {
+	var target any = data
+	return d.dec.Decode(&target)
+}
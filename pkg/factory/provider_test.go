@@ -0,0 +1,172 @@
+package factory
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"reflect"
+	"slices"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+const testType codec.Type = "test+json"
+
+type testConfig struct {
+	codec.Config
+	indent bool
+}
+
+func withTestIndent() codec.Option {
+	return codec.NewOption("test.WithIndent", func(c *testConfig) {
+		c.indent = true
+	})
+}
+
+// testCodec is an in-house format built on encoding/json, written the way
+// an external package would provide one
+type testCodec struct {
+	cfg testConfig
+}
+
+func (c *testCodec) Encode(w io.Writer, data any) error {
+	return c.NewEncoder(w).Encode(data)
+}
+
+func (c *testCodec) Decode(r io.Reader, data *any) error {
+	return c.NewDecoder(r).Decode(data)
+}
+
+func (c *testCodec) Marshal(data any) ([]byte, error) {
+	if c.cfg.indent {
+		return json.MarshalIndent(data, "", "  ")
+	}
+	return json.Marshal(data)
+}
+
+func (c *testCodec) Unmarshal(data []byte, v *any) error {
+	return json.Unmarshal(data, *v)
+}
+
+func (c *testCodec) NewEncoder(w io.Writer) codec.Encoder[any] {
+	return json.NewEncoder(w)
+}
+
+func (c *testCodec) NewDecoder(r io.Reader) codec.Decoder[any] {
+	return testDecoder{json.NewDecoder(r)}
+}
+
+type testDecoder struct {
+	dec *json.Decoder
+}
+
+func (d testDecoder) Decode(data *any) error {
+	return d.dec.Decode(*data)
+}
+
+func init() {
+	codec.Register(codec.CodecInfo{
+		Type:       testType,
+		Name:       "Test JSON",
+		MediaTypes: []string{"application/x-test+json"},
+		Extensions: []string{".tjson"},
+	}, codec.ProviderFunc(func(typ reflect.Type, opts ...codec.Option) (codec.Codec[any], error) {
+		c := &testCodec{}
+		if err := codec.ApplyOptions(testType, &c.cfg, opts); err != nil {
+			return nil, err
+		}
+		return c, nil
+	}))
+}
+
+type testRecord struct {
+	Name  string `json:"name"`
+	Value int    `json:"value"`
+}
+
+func TestNew_Provider(t *testing.T) {
+	if !codec.IsSupported(testType) || !slices.Contains(codec.SupportedCodecs(), testType) {
+		t.Fatalf("Expected %q to be supported", testType)
+	}
+
+	c, err := New[testRecord](testType, withTestIndent())
+	if err != nil {
+		t.Fatalf("Failed to create codec: %v", err)
+	}
+
+	data := testRecord{Name: "test", Value: 42}
+	encoded, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Failed to marshal: %v", err)
+	}
+	if !bytes.Contains(encoded, []byte("\n  \"name\"")) {
+		t.Errorf("Expected indented output, got %s", encoded)
+	}
+
+	var decoded testRecord
+	if err := c.Unmarshal(encoded, &decoded); err != nil {
+		t.Fatalf("Failed to unmarshal: %v", err)
+	}
+	if decoded != data {
+		t.Errorf("Data mismatch: got %+v, want %+v", decoded, data)
+	}
+
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, data); err != nil {
+		t.Fatalf("Failed to encode: %v", err)
+	}
+	decoded = testRecord{}
+	if err := c.Decode(&buf, &decoded); err != nil || decoded != data {
+		t.Errorf("Decode: got %+v, %v", decoded, err)
+	}
+}
+
+func TestNewStream_Provider(t *testing.T) {
+	c, err := NewStream[testRecord](testType)
+	if err != nil {
+		t.Fatalf("Failed to create stream codec: %v", err)
+	}
+
+	var buf bytes.Buffer
+	enc := c.NewEncoder(&buf)
+	for i := range 3 {
+		if err := enc.Encode(testRecord{Name: "test", Value: i}); err != nil {
+			t.Fatalf("Failed to encode: %v", err)
+		}
+	}
+
+	dec := c.NewDecoder(&buf)
+	for i := range 3 {
+		var v testRecord
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("Failed to decode: %v", err)
+		}
+		if v.Value != i {
+			t.Errorf("Expected value %d, got %d", i, v.Value)
+		}
+	}
+	var v testRecord
+	if err := dec.Decode(&v); err != io.EOF {
+		t.Errorf("Expected io.EOF, got %v", err)
+	}
+}
+
+func TestNewOptimized_Provider(t *testing.T) {
+	if _, err := NewOptimized[testRecord](testType); err == nil {
+		t.Error("Expected error for a registered codec type")
+	}
+}
+
+func TestNew_ProviderOptionError(t *testing.T) {
+	_, err := New[testRecord](testType, codec.NewOption("other.WithThing", func(*struct{}) {}))
+	var notSupported codec.ErrOptionNotSupported
+	if !errors.As(err, &notSupported) || notSupported.CodecType != testType {
+		t.Errorf("Expected ErrOptionNotSupported, got %v", err)
+	}
+
+	if _, err := New[testRecord](testType, codec.WithStrict()); err != nil {
+		t.Errorf("Expected shared option to apply, got %v", err)
+	}
+}
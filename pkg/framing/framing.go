@@ -0,0 +1,128 @@
+// Package framing delimits a stream of encoded messages, such as a TCP
+// connection, so that each can be read back on its own. A Writer encodes
+// values with any codec and writes each as a frame, and a Reader reads
+// frames and decodes them:
+//
+//	w := framing.NewWriter[Event](conn, json.New[Event]())
+//	err := w.Write(event)
+//
+//	r := framing.NewReader[Event](conn, json.New[Event]())
+//	for r.Next() {
+//		handle(r.Value())
+//	}
+//	err := r.Err()
+//
+// A frame is a length prefix followed by the payload and, with
+// WithChecksum, its CRC-32C:
+//
+//	length (uint32 big-endian, or uvarint) | payload | CRC-32C (uint32 big-endian)
+//
+// The uvarint prefix without checksums is the format of
+// google.golang.org/protobuf/encoding/protodelim. Readers never read past
+// the end of the frame they return, so the underlying connection can be
+// handed to other code between frames.
+package framing
+
+import (
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io"
+	"math"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// errorCodec names the package in the errors it reports
+const errorCodec codec.Type = "framing"
+
+// DefaultMaxFrameSize is the longest frame payload accepted unless
+// WithMaxFrameSize sets another limit
+const DefaultMaxFrameSize = 4 << 20
+
+// checksumSize is the length of the CRC-32C that follows a payload
+const checksumSize = 4
+
+// Prefix selects how the length of each frame is encoded
+type Prefix byte
+
+const (
+	// Fixed32 prefixes each frame with its length as a 4-byte big-endian
+	// integer. It is the default.
+	Fixed32 Prefix = iota + 1
+
+	// Uvarint prefixes each frame with its length as an unsigned varint,
+	// as protobuf does for delimited messages
+	Uvarint
+)
+
+// String returns the name of the prefix
+func (p Prefix) String() string {
+	switch p {
+	case Fixed32:
+		return "fixed32"
+	case Uvarint:
+		return "uvarint"
+	default:
+		return fmt.Sprintf("Prefix(%d)", byte(p))
+	}
+}
+
+// ErrChecksumMismatch is reported, wrapped in a codec.DecodeError, for a
+// frame whose payload does not match its CRC-32C. The frame boundaries are
+// intact, so reading can continue with the next frame.
+type ErrChecksumMismatch struct {
+	Want uint32
+	Got  uint32
+}
+
+func (e ErrChecksumMismatch) Error() string {
+	return fmt.Sprintf("framing: checksum %#08x, want %#08x", e.Got, e.Want)
+}
+
+// castagnoli is the CRC-32C table used for frame checksums
+var castagnoli = crc32.MakeTable(crc32.Castagnoli)
+
+// newError returns the error, if any, caused by the arguments of NewWriter
+// or NewReader
+func newError(stream any, payload any, cfg *config, opts []codec.Option) error {
+	if e, ok := payload.(interface{ Err() error }); ok {
+		if err := e.Err(); err != nil {
+			return err
+		}
+	}
+	*cfg = config{prefix: Fixed32, maxSize: DefaultMaxFrameSize}
+	if err := codec.ApplyOptions(errorCodec, cfg, opts); err != nil {
+		return err
+	}
+	switch {
+	case stream == nil:
+		return errors.New("framing: nil stream")
+	case cfg.prefix != Fixed32 && cfg.prefix != Uvarint:
+		return fmt.Errorf("framing: unknown prefix %s", cfg.prefix)
+	case cfg.maxSize <= 0:
+		return fmt.Errorf("framing: maximum frame size %d is not positive", cfg.maxSize)
+	case cfg.prefix == Fixed32 && cfg.maxSize > math.MaxUint32:
+		return fmt.Errorf("framing: maximum frame size %d does not fit a %s prefix", cfg.maxSize, cfg.prefix)
+	}
+	return nil
+}
+
+// limitError reports a frame longer than the maximum frame size
+func limitError(max, offset int64) error {
+	return codec.DecodeError{
+		Codec:  errorCodec,
+		Kind:   codec.ErrLimitExceeded,
+		Offset: offset,
+		Err:    codec.LimitError{Limit: "MaxFrameSize", Max: max},
+	}
+}
+
+// decodeError wraps a read failure at offset in a codec.DecodeError
+func decodeError(err error, offset int64) error {
+	kind := codec.ErrSyntax
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		kind, err = codec.ErrTruncated, io.ErrUnexpectedEOF
+	}
+	return codec.DecodeError{Codec: errorCodec, Kind: kind, Offset: offset, Err: err}
+}
@@ -0,0 +1,309 @@
+//go:build codec_json
+
+package framing
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"testing"
+
+	codec "github.com/jeremyhahn/go-codec"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type TestStruct struct {
+	Name  string `json:"name"`
+	Age   int    `json:"age"`
+	Email string `json:"email"`
+}
+
+var testData = []TestStruct{
+	{Name: "John Doe", Age: 30, Email: "john@example.com"},
+	{Name: "Jane Doe", Age: 28},
+	{},
+}
+
+// oneByteReader returns at most one byte per Read, as a slow connection may
+type oneByteReader struct {
+	r io.Reader
+}
+
+func (o oneByteReader) Read(p []byte) (int, error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+	return o.r.Read(p[:1])
+}
+
+func TestWriterReader_RoundTrip(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []codec.Option
+	}{
+		{"Fixed32", nil},
+		{"Uvarint", []codec.Option{WithPrefix(Uvarint)}},
+		{"Fixed32 checksum", []codec.Option{WithChecksum()}},
+		{"Uvarint checksum", []codec.Option{WithPrefix(Uvarint), WithChecksum()}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			w := NewWriter[TestStruct](&buf, jsoncodec.New[TestStruct](), tt.opts...)
+			if err := w.Err(); err != nil {
+				t.Fatalf("NewWriter failed: %v", err)
+			}
+			for _, v := range testData {
+				if err := w.Write(v); err != nil {
+					t.Fatalf("Write failed: %v", err)
+				}
+			}
+			size := int64(buf.Len())
+
+			r := NewReader[TestStruct](oneByteReader{&buf}, jsoncodec.New[TestStruct](), tt.opts...)
+			var got []TestStruct
+			for r.Next() {
+				got = append(got, r.Value())
+			}
+			if err := r.Err(); err != nil {
+				t.Fatalf("Err = %v", err)
+			}
+			if len(got) != len(testData) {
+				t.Fatalf("read %d values, want %d", len(got), len(testData))
+			}
+			for i := range got {
+				if got[i] != testData[i] {
+					t.Errorf("value %d = %+v, want %+v", i, got[i], testData[i])
+				}
+			}
+			if r.Offset() != size {
+				t.Errorf("Offset = %d, want %d", r.Offset(), size)
+			}
+		})
+	}
+}
+
+func TestWriter_Format(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter[[]byte](&buf, rawCodec{}, WithChecksum())
+	if err := w.WriteFrame([]byte("abc")); err != nil {
+		t.Fatal(err)
+	}
+	// CRC-32C of "abc" is 0x364b3fb7
+	want := []byte{0, 0, 0, 3, 'a', 'b', 'c', 0x36, 0x4b, 0x3f, 0xb7}
+	if !bytes.Equal(buf.Bytes(), want) {
+		t.Errorf("frame = %x, want %x", buf.Bytes(), want)
+	}
+
+	buf.Reset()
+	w = NewWriter[[]byte](&buf, rawCodec{}, WithPrefix(Uvarint))
+	if err := w.WriteFrame(bytes.Repeat([]byte{'x'}, 300)); err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.HasPrefix(buf.Bytes(), []byte{0xac, 0x02}) || buf.Len() != 302 {
+		t.Errorf("frame starts with %x and has %d bytes", buf.Bytes()[:2], buf.Len())
+	}
+}
+
+func TestReader_NoOverRead(t *testing.T) {
+	var buf bytes.Buffer
+	for _, prefix := range []Prefix{Fixed32, Uvarint} {
+		buf.Reset()
+		w := NewWriter[TestStruct](&buf, jsoncodec.New[TestStruct](), WithPrefix(prefix), WithChecksum())
+		if err := w.Write(testData[0]); err != nil {
+			t.Fatal(err)
+		}
+		buf.WriteString("trailing protocol data")
+
+		r := NewReader[TestStruct](&buf, jsoncodec.New[TestStruct](), WithPrefix(prefix), WithChecksum())
+		if !r.Next() || r.Value() != testData[0] {
+			t.Fatalf("%s: Next = %+v, %v", prefix, r.Value(), r.Err())
+		}
+		if rest := buf.String(); rest != "trailing protocol data" {
+			t.Errorf("%s: stream left with %q", prefix, rest)
+		}
+	}
+}
+
+func TestProtodelim(t *testing.T) {
+	msgs := []*wrapperspb.StringValue{wrapperspb.String("hello"), wrapperspb.String(string(bytes.Repeat([]byte{'x'}, 200)))}
+
+	var buf bytes.Buffer
+	w := NewWriter[[]byte](&buf, rawCodec{}, WithPrefix(Uvarint))
+	for _, m := range msgs {
+		data, err := proto.Marshal(m)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if err := w.WriteFrame(data); err != nil {
+			t.Fatal(err)
+		}
+	}
+	br := bufio.NewReader(&buf)
+	for _, want := range msgs {
+		got := &wrapperspb.StringValue{}
+		if err := protodelim.UnmarshalFrom(br, got); err != nil || got.GetValue() != want.GetValue() {
+			t.Errorf("protodelim.UnmarshalFrom = %q, %v", got.GetValue(), err)
+		}
+	}
+
+	buf.Reset()
+	for _, m := range msgs {
+		if _, err := protodelim.MarshalTo(&buf, m); err != nil {
+			t.Fatal(err)
+		}
+	}
+	r := NewReader[[]byte](&buf, rawCodec{}, WithPrefix(Uvarint))
+	for _, want := range msgs {
+		data, err := r.ReadFrame()
+		if err != nil {
+			t.Fatalf("ReadFrame failed: %v", err)
+		}
+		got := &wrapperspb.StringValue{}
+		if err := proto.Unmarshal(data, got); err != nil || got.GetValue() != want.GetValue() {
+			t.Errorf("proto.Unmarshal = %q, %v", got.GetValue(), err)
+		}
+	}
+	if _, err := r.ReadFrame(); err != io.EOF {
+		t.Errorf("ReadFrame at the end = %v, want io.EOF", err)
+	}
+}
+
+func TestReader_Checksum(t *testing.T) {
+	var buf bytes.Buffer
+	w := NewWriter[[]byte](&buf, rawCodec{}, WithChecksum())
+	w.WriteFrame([]byte("first"))
+	w.WriteFrame([]byte("second"))
+	data := buf.Bytes()
+	data[5] ^= 1
+
+	r := NewReader[[]byte](bytes.NewReader(data), rawCodec{}, WithChecksum())
+	_, err := r.ReadFrame()
+	var mismatch ErrChecksumMismatch
+	var decodeErr codec.DecodeError
+	if !errors.As(err, &mismatch) || !errors.As(err, &decodeErr) || decodeErr.Offset != 0 {
+		t.Fatalf("ReadFrame of a corrupted frame = %v, want ErrChecksumMismatch", err)
+	}
+	payload, err := r.ReadFrame()
+	if err != nil || string(payload) != "second" {
+		t.Errorf("ReadFrame after a mismatch = %q, %v", payload, err)
+	}
+
+	r = NewReader[[]byte](bytes.NewReader(data), rawCodec{}, WithChecksum())
+	if r.Next() || !errors.As(r.Err(), &mismatch) {
+		t.Errorf("Next = %v, want ErrChecksumMismatch", r.Err())
+	}
+}
+
+func TestReader_Errors(t *testing.T) {
+	frame := func(prefix ...byte) []byte { return append(prefix, "payload"...) }
+	tests := []struct {
+		name string
+		data []byte
+		opts []codec.Option
+		kind codec.ErrorKind
+	}{
+		{"truncated prefix", []byte{0, 0}, nil, codec.ErrTruncated},
+		{"truncated payload", frame(0, 0, 0, 10), nil, codec.ErrTruncated},
+		{"truncated checksum", frame(0, 0, 0, 7), []codec.Option{WithChecksum()}, codec.ErrTruncated},
+		{"truncated uvarint", []byte{0x80}, []codec.Option{WithPrefix(Uvarint)}, codec.ErrTruncated},
+		{"uvarint overflow", bytes.Repeat([]byte{0xff}, 11), []codec.Option{WithPrefix(Uvarint)}, codec.ErrSyntax},
+		{"too large", frame(0, 0, 0, 7), []codec.Option{WithMaxFrameSize(6)}, codec.ErrLimitExceeded},
+		{"too large uvarint", binary.AppendUvarint(nil, 1<<40), []codec.Option{WithPrefix(Uvarint)}, codec.ErrLimitExceeded},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := NewReader[[]byte](bytes.NewReader(tt.data), rawCodec{}, tt.opts...)
+			_, err := r.ReadFrame()
+			var decodeErr codec.DecodeError
+			if !errors.As(err, &decodeErr) || decodeErr.Kind != tt.kind || decodeErr.Codec != "framing" {
+				t.Fatalf("ReadFrame = %v, want a framing DecodeError of kind %v", err, tt.kind)
+			}
+			if _, again := r.ReadFrame(); again != err {
+				t.Errorf("second ReadFrame = %v, want the same error", again)
+			}
+		})
+	}
+
+	r := NewReader[[]byte](bytes.NewReader(nil), rawCodec{})
+	if r.Next() || r.Err() != nil {
+		t.Errorf("Next on an empty stream = true or %v", r.Err())
+	}
+
+	var buf bytes.Buffer
+	NewWriter[[]byte](&buf, rawCodec{}).WriteFrame([]byte("not json"))
+	jr := NewReader[TestStruct](&buf, jsoncodec.New[TestStruct]())
+	if jr.Next() || jr.Err() == nil {
+		t.Error("Next decoded an invalid payload")
+	}
+}
+
+func TestWriter_Errors(t *testing.T) {
+	w := NewWriter[[]byte](io.Discard, rawCodec{}, WithMaxFrameSize(4))
+	var limit codec.LimitError
+	if err := w.WriteFrame([]byte("payload")); !errors.As(err, &limit) || limit.Limit != "MaxFrameSize" {
+		t.Errorf("WriteFrame of a large payload = %v, want LimitError", err)
+	}
+	if err := w.WriteFrame([]byte("four")); err != nil {
+		t.Errorf("WriteFrame after a rejected payload failed: %v", err)
+	}
+
+	failing := NewWriter[[]byte](failingWriter{}, rawCodec{})
+	if err := failing.WriteFrame([]byte("a")); err != io.ErrClosedPipe {
+		t.Errorf("WriteFrame = %v, want io.ErrClosedPipe", err)
+	}
+	if failing.Err() != io.ErrClosedPipe || failing.WriteFrame(nil) != io.ErrClosedPipe {
+		t.Error("write error is not sticky")
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []codec.Option
+	}{
+		{"unknown prefix", []codec.Option{WithPrefix(0)}},
+		{"zero max size", []codec.Option{WithMaxFrameSize(0)}},
+		{"max size over fixed32", []codec.Option{WithMaxFrameSize(1 << 32)}},
+		{"foreign option", []codec.Option{jsoncodec.WithIndent("", "  ")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := NewWriter[TestStruct](io.Discard, jsoncodec.New[TestStruct](), tt.opts...)
+			if w.Err() == nil || w.Write(testData[0]) == nil {
+				t.Error("NewWriter succeeded")
+			}
+			r := NewReader[TestStruct](bytes.NewReader(nil), jsoncodec.New[TestStruct](), tt.opts...)
+			if r.Next() || r.Err() == nil {
+				t.Error("NewReader succeeded")
+			}
+		})
+	}
+	if NewWriter[TestStruct](nil, jsoncodec.New[TestStruct]()).Err() == nil {
+		t.Error("NewWriter with a nil writer succeeded")
+	}
+	if err := NewWriter[TestStruct](io.Discard, jsoncodec.New[TestStruct](), WithPrefix(Uvarint), WithMaxFrameSize(1<<32)).Err(); err != nil {
+		t.Errorf("NewWriter with a large uvarint limit failed: %v", err)
+	}
+}
+
+// rawCodec passes payload bytes through unchanged
+type rawCodec struct{}
+
+func (rawCodec) Encode(w io.Writer, data []byte) error { _, err := w.Write(data); return err }
+func (rawCodec) Decode(r io.Reader, data *[]byte) error {
+	var err error
+	*data, err = io.ReadAll(r)
+	return err
+}
+func (rawCodec) Marshal(data []byte) ([]byte, error)    { return data, nil }
+func (rawCodec) Unmarshal(data []byte, v *[]byte) error { *v = data; return nil }
+
+type failingWriter struct{}
+
+func (failingWriter) Write([]byte) (int, error) { return 0, io.ErrClosedPipe }
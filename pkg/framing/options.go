@@ -0,0 +1,36 @@
+package framing
+
+import codec "github.com/jeremyhahn/go-codec"
+
+// config holds the settings applied by framing options
+type config struct {
+	prefix   Prefix
+	checksum bool
+	maxSize  int64
+}
+
+// WithPrefix selects how frame lengths are encoded, Fixed32 by default.
+// Both ends of a stream must use the same prefix.
+func WithPrefix(p Prefix) codec.Option {
+	return codec.NewOption("framing.WithPrefix", func(c *config) {
+		c.prefix = p
+	})
+}
+
+// WithChecksum appends the CRC-32C of each payload to its frame and
+// verifies it when reading. Both ends of a stream must enable it.
+func WithChecksum() codec.Option {
+	return codec.NewOption("framing.WithChecksum", func(c *config) {
+		c.checksum = true
+	})
+}
+
+// WithMaxFrameSize sets the longest payload, in bytes, that is written or
+// read, DefaultMaxFrameSize by default. A Reader rejects a longer frame from
+// its length prefix, before reading or allocating the payload, with a
+// codec.DecodeError of kind codec.ErrLimitExceeded.
+func WithMaxFrameSize(n int64) codec.Option {
+	return codec.NewOption("framing.WithMaxFrameSize", func(c *config) {
+		c.maxSize = n
+	})
+}
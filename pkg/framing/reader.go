@@ -0,0 +1,174 @@
+package framing
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"hash/crc32"
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// Reader reads frames from a stream and decodes them. It reads the length
+// prefix a byte at a time and the payload with a reader limited to its
+// length, so it never consumes bytes beyond the current frame. It is not
+// safe for concurrent use.
+type Reader[T any] struct {
+	src   countingReader
+	codec codec.Codec[T]
+	cfg   config
+	buf   bytes.Buffer
+	value T
+	err   error
+}
+
+// NewReader returns a Reader that reads frames from r and decodes them with
+// c. If c reports an error through an Err method, an option does not apply
+// to framing or the options conflict, Next returns false and Err reports
+// the error.
+func NewReader[T any](r io.Reader, c codec.Codec[T], opts ...codec.Option) *Reader[T] {
+	fr := &Reader[T]{src: countingReader{r: r}, codec: c}
+	fr.err = newError(r, c, &fr.cfg, opts)
+	return fr
+}
+
+// Next reads and decodes the next frame, which is then returned by Value.
+// It returns false at the end of the stream or on the first error, which
+// is reported by Err. To skip frames that fail to decode or whose checksum
+// does not match, use ReadFrame instead.
+func (r *Reader[T]) Next() bool {
+	payload, err := r.ReadFrame()
+	if err != nil {
+		if err != io.EOF {
+			r.err = err
+		}
+		return false
+	}
+	var v T
+	if err := r.codec.Unmarshal(payload, &v); err != nil {
+		r.err = err
+		return false
+	}
+	r.value = v
+	return true
+}
+
+// Value returns the value decoded by the last successful call to Next
+func (r *Reader[T]) Value() T {
+	return r.value
+}
+
+// Err returns the error that stopped Next, or nil if it reached the end of
+// the stream
+func (r *Reader[T]) Err() error {
+	return r.err
+}
+
+// Offset returns the number of bytes read from the stream
+func (r *Reader[T]) Offset() int64 {
+	return r.src.n
+}
+
+// ReadFrame reads the next frame and returns its payload without decoding
+// it. The payload is only valid until the next call. It returns io.EOF if
+// the stream ends before a frame starts, and a codec.DecodeError of kind
+// codec.ErrTruncated if it ends within one. After an error other than a
+// checksum mismatch the frame boundaries are lost, and every later call
+// returns the same error.
+func (r *Reader[T]) ReadFrame() ([]byte, error) {
+	if r.err != nil {
+		return nil, r.err
+	}
+	payload, err := r.readFrame()
+	var mismatch ErrChecksumMismatch
+	if err != nil && err != io.EOF && !errors.As(err, &mismatch) {
+		r.err = err
+	}
+	return payload, err
+}
+
+// readFrame reads the length prefix, payload and checksum of a frame
+func (r *Reader[T]) readFrame() ([]byte, error) {
+	start := r.src.n
+	length, err := r.readLength()
+	if err != nil {
+		if err == io.EOF {
+			return nil, io.EOF
+		}
+		return nil, decodeError(err, start)
+	}
+	if length > uint64(r.cfg.maxSize) {
+		return nil, limitError(r.cfg.maxSize, start)
+	}
+
+	// The buffer grows as the payload arrives, so a length prefix alone
+	// cannot make the reader allocate up to the maximum frame size
+	n := int64(length)
+	if r.cfg.checksum {
+		n += checksumSize
+	}
+	r.buf.Reset()
+	if _, err := r.buf.ReadFrom(io.LimitReader(&r.src, n)); err != nil {
+		return nil, decodeError(err, start)
+	}
+	if int64(r.buf.Len()) < n {
+		return nil, decodeError(io.ErrUnexpectedEOF, r.src.n)
+	}
+
+	data := r.buf.Bytes()
+	payload := data[:length]
+	if r.cfg.checksum {
+		want := binary.BigEndian.Uint32(data[length:])
+		if got := crc32.Checksum(payload, castagnoli); got != want {
+			return nil, codec.DecodeError{
+				Codec:  errorCodec,
+				Kind:   codec.ErrSyntax,
+				Offset: start,
+				Err:    ErrChecksumMismatch{Want: want, Got: got},
+			}
+		}
+	}
+	return payload, nil
+}
+
+// readLength reads a length prefix. It returns io.EOF only if the stream
+// ends before the first byte of the prefix.
+func (r *Reader[T]) readLength() (uint64, error) {
+	if r.cfg.prefix == Uvarint {
+		return binary.ReadUvarint(&r.src)
+	}
+	var prefix [4]byte
+	if _, err := io.ReadFull(&r.src, prefix[:]); err != nil {
+		return 0, err
+	}
+	return uint64(binary.BigEndian.Uint32(prefix[:])), nil
+}
+
+// countingReader counts the bytes read from r and reads single bytes
+// without buffering
+type countingReader struct {
+	r   io.Reader
+	n   int64
+	one [1]byte
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
+
+func (c *countingReader) ReadByte() (byte, error) {
+	if br, ok := c.r.(io.ByteReader); ok {
+		b, err := br.ReadByte()
+		if err == nil {
+			c.n++
+		}
+		return b, err
+	}
+	if _, err := io.ReadFull(c, c.one[:]); err != nil {
+		return 0, err
+	}
+	return c.one[0], nil
+}
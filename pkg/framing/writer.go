@@ -0,0 +1,77 @@
+package framing
+
+import (
+	"encoding/binary"
+	"hash/crc32"
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// Writer writes values to a stream as frames. It is not safe for
+// concurrent use.
+type Writer[T any] struct {
+	w     io.Writer
+	codec codec.Codec[T]
+	cfg   config
+	buf   []byte
+	err   error
+}
+
+// NewWriter returns a Writer that encodes values with c and writes them to
+// w. If c reports an error through an Err method, an option does not apply
+// to framing or the options conflict, every write returns the error, which
+// is also reported by Err.
+func NewWriter[T any](w io.Writer, c codec.Codec[T], opts ...codec.Option) *Writer[T] {
+	fw := &Writer[T]{w: w, codec: c}
+	fw.err = newError(w, c, &fw.cfg, opts)
+	return fw
+}
+
+// Err returns the error caused by the arguments passed to NewWriter, or the
+// first error writing to the stream, after which the frame boundaries are
+// lost and every write fails
+func (w *Writer[T]) Err() error {
+	return w.err
+}
+
+// Write encodes v and writes it as one frame
+func (w *Writer[T]) Write(v T) error {
+	if w.err != nil {
+		return w.err
+	}
+	payload, err := w.codec.Marshal(v)
+	if err != nil {
+		return err
+	}
+	return w.WriteFrame(payload)
+}
+
+// WriteFrame writes payload, which is already encoded, as one frame. The
+// frame is passed to the stream in a single Write call.
+func (w *Writer[T]) WriteFrame(payload []byte) error {
+	if w.err != nil {
+		return w.err
+	}
+	if int64(len(payload)) > w.cfg.maxSize {
+		return codec.LimitError{Limit: "MaxFrameSize", Max: w.cfg.maxSize}
+	}
+
+	buf := w.buf[:0]
+	if w.cfg.prefix == Uvarint {
+		buf = binary.AppendUvarint(buf, uint64(len(payload)))
+	} else {
+		buf = binary.BigEndian.AppendUint32(buf, uint32(len(payload)))
+	}
+	buf = append(buf, payload...)
+	if w.cfg.checksum {
+		buf = binary.BigEndian.AppendUint32(buf, crc32.Checksum(payload, castagnoli))
+	}
+	w.buf = buf
+
+	if _, err := w.w.Write(buf); err != nil {
+		w.err = err
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,144 @@
+// Package grpccodec adapts the codecs of this module to gRPC, so that
+// services can exchange JSON, CBOR, MessagePack or any other supported
+// format instead of Protocol Buffers.
+//
+// gRPC selects a codec by the content-subtype of a call, e.g.
+// "application/grpc+cbor", and hands it messages of any type, while the
+// codecs of this module are typed. A Codec therefore holds one typed codec
+// per message type, added with Add or Use before the Codec is registered:
+//
+//	c := grpccodec.New(codec.CBOR)
+//	grpccodec.Add[EchoRequest](c)
+//	grpccodec.Add[EchoReply](c)
+//	encoding.RegisterCodec(c)
+//
+// Clients then select it per call with grpc.CallContentSubtype("cbor").
+package grpccodec
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+
+	"github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/factory"
+	"google.golang.org/grpc/encoding"
+)
+
+// Codec implements encoding.Codec for the message types added to it
+type Codec struct {
+	codecType codec.Type
+	name      string
+	opts      []codec.Option
+
+	mu    sync.RWMutex
+	types map[reflect.Type]messageCodec
+}
+
+// Codec implements the gRPC codec interface
+var _ encoding.Codec = (*Codec)(nil)
+
+// messageCodec encodes messages of one type
+type messageCodec interface {
+	marshal(v any) ([]byte, error)
+	unmarshal(data []byte, v any) error
+}
+
+// New returns a Codec for the codec type t, registered with gRPC under the
+// content-subtype named by t, e.g. "json". Message types added with Add
+// are created with opts.
+func New(t codec.Type, opts ...codec.Option) *Codec {
+	return NewNamed(string(t), t, opts...)
+}
+
+// NewNamed returns a Codec like New, registered with gRPC under the given
+// content-subtype instead. gRPC expects it to be lowercase.
+func NewNamed(name string, t codec.Type, opts ...codec.Option) *Codec {
+	return &Codec{codecType: t, name: name, opts: opts, types: make(map[reflect.Type]messageCodec)}
+}
+
+// Add creates a codec for messages of type T with factory.New and adds it
+// to c. It returns the error from factory.New, e.g. for a codec type that
+// is not compiled in. Use Use for codecs that factory.New cannot create,
+// such as Protocol Buffers or Avro with an explicit schema.
+func Add[T any](c *Codec) error {
+	tc, err := factory.New[T](c.codecType, c.opts...)
+	if err != nil {
+		return err
+	}
+	Use(c, tc)
+	return nil
+}
+
+// Use adds tc to c as the codec for messages of type T. It replaces any
+// codec previously added for T.
+func Use[T any](c *Codec, tc codec.Codec[T]) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.types[reflect.TypeFor[T]()] = typed[T]{tc}
+}
+
+// Name returns the content-subtype of c
+func (c *Codec) Name() string {
+	return c.name
+}
+
+// Marshal encodes v, a message of a type added to c or a pointer to one
+func (c *Codec) Marshal(v any) ([]byte, error) {
+	mc, err := c.lookup(v, true)
+	if err != nil {
+		return nil, err
+	}
+	return mc.marshal(v)
+}
+
+// Unmarshal decodes data into v, a pointer to a message of a type added
+// to c
+func (c *Codec) Unmarshal(data []byte, v any) error {
+	mc, err := c.lookup(v, false)
+	if err != nil {
+		return err
+	}
+	return mc.unmarshal(data, v)
+}
+
+// lookup returns the codec for v. Messages may be encoded from a value or a
+// pointer, and are decoded through a pointer: either a pointer to a type
+// added to c, or a pointer type such as a generated protobuf message that
+// was itself added.
+func (c *Codec) lookup(v any, encoding bool) (messageCodec, error) {
+	t := reflect.TypeOf(v)
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if t != nil && (encoding || t.Kind() == reflect.Pointer) {
+		if mc, ok := c.types[t]; ok {
+			return mc, nil
+		}
+	}
+	if t != nil && t.Kind() == reflect.Pointer {
+		if mc, ok := c.types[t.Elem()]; ok {
+			return mc, nil
+		}
+	}
+	return nil, fmt.Errorf("grpccodec: no %s codec added for message type %v", c.name, t)
+}
+
+// typed adapts a codec.Codec[T] to messageCodec
+type typed[T any] struct {
+	c codec.Codec[T]
+}
+
+func (t typed[T]) marshal(v any) ([]byte, error) {
+	if m, ok := v.(T); ok {
+		return t.c.Marshal(m)
+	}
+	return t.c.Marshal(*v.(*T))
+}
+
+func (t typed[T]) unmarshal(data []byte, v any) error {
+	if m, ok := v.(T); ok {
+		// T is a pointer type, decoded in place
+		return t.c.Unmarshal(data, &m)
+	}
+	return t.c.Unmarshal(data, v.(*T))
+}
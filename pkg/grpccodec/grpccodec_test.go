@@ -0,0 +1,158 @@
+//go:build codec_json && codec_yaml && codec_toml && codec_msgpack && codec_bson && codec_cbor && codec_avro && codec_protobuf
+
+package grpccodec
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/protobuf"
+	"github.com/jeremyhahn/go-codec/pkg/protobuf/testdata"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/encoding"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+type echoRequest struct {
+	Message string   `json:"message" yaml:"message" msgpack:"message" bson:"message" cbor:"message" avro:"message"`
+	Tags    []string `json:"tags" yaml:"tags" msgpack:"tags" bson:"tags" cbor:"tags" avro:"tags"`
+}
+
+type echoReply struct {
+	Message string `json:"message" yaml:"message" msgpack:"message" bson:"message" cbor:"message" avro:"message"`
+	Count   int    `json:"count" yaml:"count" msgpack:"count" bson:"count" cbor:"count" avro:"count"`
+}
+
+// echoServer is the service behind echoDesc
+type echoServer interface {
+	Echo(ctx context.Context, req *echoRequest) (*echoReply, error)
+}
+
+type echoService struct{}
+
+func (echoService) Echo(_ context.Context, req *echoRequest) (*echoReply, error) {
+	return &echoReply{Message: strings.ToUpper(req.Message), Count: len(req.Tags)}, nil
+}
+
+// echoDesc describes the service by hand, as protoc-gen-go-grpc would, with
+// plain Go structs as messages
+var echoDesc = grpc.ServiceDesc{
+	ServiceName: "test.Echo",
+	HandlerType: (*echoServer)(nil),
+	Methods: []grpc.MethodDesc{{
+		MethodName: "Echo",
+		Handler: func(srv any, ctx context.Context, dec func(any) error, _ grpc.UnaryServerInterceptor) (any, error) {
+			req := new(echoRequest)
+			if err := dec(req); err != nil {
+				return nil, err
+			}
+			return srv.(echoServer).Echo(ctx, req)
+		},
+	}},
+}
+
+// dial starts an in-process server on a bufconn listener and returns a
+// client connection to it
+func dial(t *testing.T) *grpc.ClientConn {
+	t.Helper()
+	lis := bufconn.Listen(1 << 20)
+	srv := grpc.NewServer()
+	srv.RegisterService(&echoDesc, echoService{})
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return lis.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatalf("NewClient failed: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+func TestRoundTrip(t *testing.T) {
+	types := []codec.Type{codec.JSON, codec.YAML, codec.MsgPack, codec.BSON, codec.CBOR, codec.Avro}
+	for _, typ := range types {
+		c := New(typ)
+		if err := Add[echoRequest](c); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		if err := Add[echoReply](c); err != nil {
+			t.Fatalf("Add failed: %v", err)
+		}
+		encoding.RegisterCodec(c)
+	}
+
+	conn := dial(t)
+	for _, typ := range types {
+		t.Run(string(typ), func(t *testing.T) {
+			req := &echoRequest{Message: "hello", Tags: []string{"a", "b"}}
+			reply := new(echoReply)
+			err := conn.Invoke(context.Background(), "/test.Echo/Echo", req, reply, grpc.CallContentSubtype(string(typ)))
+			if err != nil {
+				t.Fatalf("Invoke failed: %v", err)
+			}
+			if *reply != (echoReply{Message: "HELLO", Count: 2}) {
+				t.Errorf("Unexpected reply: %+v", reply)
+			}
+		})
+	}
+}
+
+func TestUnknownMessageType(t *testing.T) {
+	c := New(codec.JSON)
+	if err := Add[echoRequest](c); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	if _, err := c.Marshal(echoReply{}); err == nil || !strings.Contains(err.Error(), "echoReply") {
+		t.Errorf("Expected error naming echoReply, got %v", err)
+	}
+	if err := c.Unmarshal([]byte(`{}`), echoRequest{}); err == nil {
+		t.Error("Expected error decoding into a non-pointer")
+	}
+}
+
+func TestValuesAndPointers(t *testing.T) {
+	c := New(codec.JSON)
+	if err := Add[echoReply](c); err != nil {
+		t.Fatalf("Add failed: %v", err)
+	}
+	byValue, err := c.Marshal(echoReply{Message: "hi", Count: 1})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	byPointer, err := c.Marshal(&echoReply{Message: "hi", Count: 1})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(byValue) != string(byPointer) {
+		t.Errorf("Expected equal encodings, got %s and %s", byValue, byPointer)
+	}
+}
+
+func TestUse_Protobuf(t *testing.T) {
+	c := NewNamed("pb", codec.ProtoBuf)
+	Use(c, protobuf.New[*testdata.TestMessage]())
+	if c.Name() != "pb" {
+		t.Errorf("Expected name pb, got %q", c.Name())
+	}
+
+	data, err := c.Marshal(&testdata.TestMessage{Name: "proto", Age: 7})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	got := new(testdata.TestMessage)
+	if err := c.Unmarshal(data, got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if got.Name != "proto" || got.Age != 7 {
+		t.Errorf("Unexpected message: %v", got)
+	}
+}
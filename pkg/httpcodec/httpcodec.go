@@ -0,0 +1,168 @@
+// Package httpcodec binds HTTP request bodies to Go values and writes
+// responses, choosing the codec from the Content-Type and Accept headers.
+//
+// Options given to Bind and Respond are passed to whichever codec is
+// chosen, so only options that apply to every codec, such as
+// codec.WithLimits and codec.WithStrict, should be used.
+package httpcodec
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"slices"
+	"strconv"
+
+	"github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/factory"
+)
+
+// DefaultMaxBodyBytes is the largest request body Bind reads unless
+// codec.WithLimits sets Limits.MaxBytes
+const DefaultMaxBodyBytes = 1 << 20
+
+// config collects the shared options given to Bind
+type config struct {
+	codec.Config
+}
+
+// Bind decodes the body of r into a new T with the codec named by the
+// request's Content-Type header. Failures are returned as a *Problem, to be
+// written with WriteProblem:
+//
+//   - 415 Unsupported Media Type if Content-Type is missing or names a
+//     codec that is unknown or not compiled in
+//   - 413 Content Too Large if the body exceeds the limit
+//   - 400 Bad Request if the body is malformed
+//   - 422 Unprocessable Content if it is well-formed but does not fit T
+//
+// Errors caused by opts are returned as is.
+func Bind[T any](r *http.Request, opts ...codec.Option) (T, error) {
+	var v T
+	contentType := r.Header.Get("Content-Type")
+	if contentType == "" {
+		return v, NewProblem(http.StatusUnsupportedMediaType, "Content-Type header is required")
+	}
+	t, err := codec.ParseMediaType(contentType)
+	if err != nil || !slices.Contains(Supported(), t) {
+		return v, NewProblem(http.StatusUnsupportedMediaType, fmt.Sprintf("unsupported Content-Type %q", contentType))
+	}
+
+	c, err := factory.New[T](t, opts...)
+	if err != nil {
+		return v, err
+	}
+
+	maxBytes := maxBodyBytes(opts)
+	if r.ContentLength > maxBytes {
+		return v, tooLarge(maxBytes)
+	}
+	body, err := io.ReadAll(io.LimitReader(r.Body, maxBytes+1))
+	if err != nil {
+		var maxErr *http.MaxBytesError
+		if errors.As(err, &maxErr) {
+			return v, tooLarge(maxErr.Limit)
+		}
+		p := NewProblem(http.StatusBadRequest, "reading request body failed")
+		p.err = err
+		return v, p
+	}
+	if int64(len(body)) > maxBytes {
+		return v, tooLarge(maxBytes)
+	}
+
+	if err := c.Unmarshal(body, &v); err != nil {
+		return v, decodeProblem(err)
+	}
+	return v, nil
+}
+
+// Respond encodes value with the codec that best matches the Accept header
+// of r and writes it with the given status. The response carries the
+// codec's canonical media type and "Vary: Accept". If no supported codec is
+// acceptable, Respond writes a 406 Not Acceptable problem; if encoding
+// fails, a 500 problem. It returns the error in either case, and any error
+// from writing the body.
+func Respond[T any](w http.ResponseWriter, r *http.Request, status int, value T, opts ...codec.Option) error {
+	w.Header().Add("Vary", "Accept")
+	t, ok := codec.Negotiate(r.Header.Get("Accept"), Supported())
+	if !ok {
+		p := NewProblem(http.StatusNotAcceptable, "no supported media type is acceptable")
+		WriteProblem(w, p)
+		return p
+	}
+
+	c, err := factory.New[T](t, opts...)
+	if err != nil {
+		WriteProblem(w, err)
+		return err
+	}
+	body, err := c.Marshal(value)
+	if err != nil {
+		WriteProblem(w, err)
+		return err
+	}
+
+	w.Header().Set("Content-Type", contentType(t))
+	if bodyAllowed(status) {
+		w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	}
+	w.WriteHeader(status)
+	if r.Method == http.MethodHead || !bodyAllowed(status) {
+		return nil
+	}
+	_, err = w.Write(body)
+	return err
+}
+
+// Supported returns the codec types Bind and Respond use, in order of
+// preference: JSON first, then the other compiled-in and registered codecs.
+// Protocol Buffers is excluded as it cannot encode arbitrary types.
+func Supported() []codec.Type {
+	types := make([]codec.Type, 0, 8)
+	if codec.IsSupported(codec.JSON) {
+		types = append(types, codec.JSON)
+	}
+	for _, t := range codec.SupportedCodecs() {
+		if t != codec.JSON && t != codec.ProtoBuf {
+			types = append(types, t)
+		}
+	}
+	return types
+}
+
+// contentType returns the Content-Type of a response encoded by t
+func contentType(t codec.Type) string {
+	info, ok := codec.Info(t)
+	if !ok || len(info.MediaTypes) == 0 {
+		return "application/octet-stream"
+	}
+	if !info.Binary {
+		return info.MediaTypes[0] + "; charset=utf-8"
+	}
+	return info.MediaTypes[0]
+}
+
+// bodyAllowed reports whether a response with status may have a body
+func bodyAllowed(status int) bool {
+	return status >= 200 && status != http.StatusNoContent && status != http.StatusNotModified
+}
+
+// maxBodyBytes returns the body limit set by opts, or DefaultMaxBodyBytes
+func maxBodyBytes(opts []codec.Option) int64 {
+	var cfg config
+	for _, opt := range opts {
+		// Options that do not apply to every codec are reported by factory.New
+		_ = codec.ApplyOptions("", &cfg, []codec.Option{opt})
+	}
+	if cfg.Limits.MaxBytes > 0 {
+		return cfg.Limits.MaxBytes
+	}
+	return DefaultMaxBodyBytes
+}
+
+// tooLarge reports a body over maxBytes
+func tooLarge(maxBytes int64) *Problem {
+	return NewProblem(http.StatusRequestEntityTooLarge, fmt.Sprintf("request body exceeds %d bytes", maxBytes))
+}
@@ -0,0 +1,232 @@
+//go:build codec_json && codec_yaml && codec_toml && codec_msgpack && codec_bson && codec_cbor && codec_avro && codec_protobuf
+
+package httpcodec
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	msgpackcodec "github.com/jeremyhahn/go-codec/pkg/msgpack"
+)
+
+type order struct {
+	ID    string `json:"id" yaml:"id" toml:"id" msgpack:"id" bson:"id" cbor:"id" avro:"id"`
+	Count int    `json:"count" yaml:"count" toml:"count" msgpack:"count" bson:"count" cbor:"count" avro:"count"`
+}
+
+func newRequest(body []byte, contentType string) *http.Request {
+	r := httptest.NewRequest(http.MethodPost, "/orders", bytes.NewReader(body))
+	if contentType != "" {
+		r.Header.Set("Content-Type", contentType)
+	}
+	return r
+}
+
+func TestBind(t *testing.T) {
+	packed, err := msgpackcodec.New[order]().Marshal(order{ID: "a1", Count: 2})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	tests := []struct {
+		name        string
+		body        []byte
+		contentType string
+	}{
+		{"json", []byte(`{"id": "a1", "count": 2}`), "application/json; charset=utf-8"},
+		{"yaml", []byte("id: a1\ncount: 2\n"), "application/yaml"},
+		{"msgpack alias", packed, "application/x-msgpack"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := Bind[order](newRequest(tt.body, tt.contentType))
+			if err != nil {
+				t.Fatalf("Bind failed: %v", err)
+			}
+			if v != (order{ID: "a1", Count: 2}) {
+				t.Errorf("Unexpected value: %+v", v)
+			}
+		})
+	}
+}
+
+func TestBind_Problems(t *testing.T) {
+	large := []byte(`{"id": "` + strings.Repeat("x", 64) + `"}`)
+	chunked := newRequest(large, "application/json")
+	chunked.ContentLength = -1
+
+	tests := []struct {
+		name   string
+		r      *http.Request
+		opts   []codec.Option
+		status int
+		path   string
+	}{
+		{"missing content type", newRequest([]byte(`{}`), ""), nil, http.StatusUnsupportedMediaType, ""},
+		{"unknown content type", newRequest([]byte(`<order/>`), "application/xml"), nil, http.StatusUnsupportedMediaType, ""},
+		{"protobuf", newRequest(nil, "application/x-protobuf"), nil, http.StatusUnsupportedMediaType, ""},
+		{"too large", newRequest(large, "application/json"), []codec.Option{codec.WithLimits(codec.Limits{MaxBytes: 32})}, http.StatusRequestEntityTooLarge, ""},
+		{"too large chunked", chunked, []codec.Option{codec.WithLimits(codec.Limits{MaxBytes: 32})}, http.StatusRequestEntityTooLarge, ""},
+		{"malformed", newRequest([]byte(`{"id": `), "application/json"), nil, http.StatusBadRequest, ""},
+		{"type mismatch", newRequest([]byte(`{"count": "two"}`), "application/json"), nil, http.StatusUnprocessableEntity, "count"},
+		{"unknown field", newRequest([]byte(`{"id": "a1", "price": 3}`), "application/json"), []codec.Option{codec.WithStrict()}, http.StatusUnprocessableEntity, "price"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Bind[order](tt.r, tt.opts...)
+			var p *Problem
+			if !errors.As(err, &p) {
+				t.Fatalf("Expected *Problem, got %v", err)
+			}
+			if p.Status != tt.status || p.Path != tt.path {
+				t.Errorf("Expected status %d at %q, got %+v", tt.status, tt.path, p)
+			}
+		})
+	}
+}
+
+func TestBind_DecodeErrorReachable(t *testing.T) {
+	_, err := Bind[order](newRequest([]byte("{\n  \"count\": \"two\"\n}"), "application/json"))
+	var de codec.DecodeError
+	if !errors.As(err, &de) || de.Line != 2 {
+		t.Errorf("Expected DecodeError on line 2, got %v", err)
+	}
+}
+
+func TestRespond(t *testing.T) {
+	tests := []struct {
+		name        string
+		accept      string
+		contentType string
+	}{
+		{"no accept", "", "application/json; charset=utf-8"},
+		{"wildcard", "*/*", "application/json; charset=utf-8"},
+		{"msgpack", "application/x-msgpack", "application/msgpack"},
+		{"q-values", "application/json;q=0.1, application/cbor", "application/cbor"},
+		{"yaml", "application/yaml", "application/yaml; charset=utf-8"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest(http.MethodGet, "/orders/a1", nil)
+			r.Header.Set("Accept", tt.accept)
+			w := httptest.NewRecorder()
+
+			if err := Respond(w, r, http.StatusCreated, order{ID: "a1", Count: 2}); err != nil {
+				t.Fatalf("Respond failed: %v", err)
+			}
+			if w.Code != http.StatusCreated {
+				t.Errorf("Expected status 201, got %d", w.Code)
+			}
+			if got := w.Header().Get("Content-Type"); got != tt.contentType {
+				t.Errorf("Expected Content-Type %q, got %q", tt.contentType, got)
+			}
+			if w.Header().Get("Vary") != "Accept" {
+				t.Errorf("Expected Vary: Accept, got %q", w.Header().Get("Vary"))
+			}
+
+			// The response binds back into the same value
+			v, err := Bind[order](newRequest(w.Body.Bytes(), tt.contentType))
+			if err != nil || v != (order{ID: "a1", Count: 2}) {
+				t.Errorf("Round trip: got %+v, %v", v, err)
+			}
+		})
+	}
+}
+
+func TestRespond_NotAcceptable(t *testing.T) {
+	r := httptest.NewRequest(http.MethodGet, "/orders/a1", nil)
+	r.Header.Set("Accept", "text/html")
+	w := httptest.NewRecorder()
+
+	if err := Respond(w, r, http.StatusOK, order{}); err == nil {
+		t.Error("Expected error")
+	}
+	if w.Code != http.StatusNotAcceptable || w.Header().Get("Content-Type") != ProblemMediaType {
+		t.Errorf("Expected 406 problem, got %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+}
+
+func TestRespond_NoBody(t *testing.T) {
+	for _, tt := range []struct {
+		method string
+		status int
+	}{
+		{http.MethodHead, http.StatusOK},
+		{http.MethodDelete, http.StatusNoContent},
+	} {
+		r := httptest.NewRequest(tt.method, "/orders/a1", nil)
+		w := httptest.NewRecorder()
+		if err := Respond(w, r, tt.status, order{ID: "a1"}); err != nil {
+			t.Fatalf("Respond failed: %v", err)
+		}
+		if w.Code != tt.status || w.Body.Len() != 0 {
+			t.Errorf("%s: expected %d without body, got %d with %q", tt.method, tt.status, w.Code, w.Body)
+		}
+	}
+}
+
+func TestWriteProblem(t *testing.T) {
+	_, err := Bind[order](newRequest([]byte(`{"count": "two"}`), "application/json"))
+	w := httptest.NewRecorder()
+	WriteProblem(w, err)
+
+	if w.Code != http.StatusUnprocessableEntity || w.Header().Get("Content-Type") != ProblemMediaType {
+		t.Errorf("Unexpected response: %d %q", w.Code, w.Header().Get("Content-Type"))
+	}
+	var body map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+		t.Fatalf("Invalid problem body: %v", err)
+	}
+	if body["status"] != float64(422) || body["title"] != "Unprocessable Entity" || body["kind"] != "type mismatch" || body["path"] != "count" {
+		t.Errorf("Unexpected problem: %v", body)
+	}
+	if _, ok := body["type"]; ok {
+		t.Errorf("Expected type to be omitted for about:blank, got %v", body["type"])
+	}
+}
+
+func TestWriteProblem_InternalError(t *testing.T) {
+	w := httptest.NewRecorder()
+	WriteProblem(w, errors.New("database password is hunter2"))
+
+	if w.Code != http.StatusInternalServerError || strings.Contains(w.Body.String(), "hunter2") {
+		t.Errorf("Unexpected response: %d %s", w.Code, w.Body)
+	}
+}
+
+func TestServer(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		o, err := Bind[order](r)
+		if err != nil {
+			WriteProblem(w, err)
+			return
+		}
+		o.Count++
+		_ = Respond(w, r, http.StatusOK, o)
+	}))
+	defer srv.Close()
+
+	req, _ := http.NewRequest(http.MethodPost, srv.URL, strings.NewReader("id: a1\ncount: 1\n"))
+	req.Header.Set("Content-Type", "application/x-yaml")
+	req.Header.Set("Accept", "application/json")
+	resp, err := srv.Client().Do(req)
+	if err != nil {
+		t.Fatalf("Request failed: %v", err)
+	}
+	defer resp.Body.Close()
+	body, _ := io.ReadAll(resp.Body)
+
+	if resp.StatusCode != http.StatusOK || strings.TrimSpace(string(body)) != `{"id":"a1","count":2}` {
+		t.Errorf("Unexpected response: %d %s", resp.StatusCode, body)
+	}
+}
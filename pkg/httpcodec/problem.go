@@ -0,0 +1,106 @@
+package httpcodec
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+// ProblemMediaType is the media type of problem details (RFC 9457)
+const ProblemMediaType = "application/problem+json"
+
+// Problem is an RFC 9457 problem details object. Bind returns failures as
+// a *Problem, and WriteProblem writes it as application/problem+json.
+type Problem struct {
+	// Type is a URI identifying the problem type. It is omitted for
+	// "about:blank", which means the problem is described by Status.
+	Type string `json:"type,omitempty"`
+
+	// Title is a short summary of the problem type
+	Title string `json:"title"`
+
+	// Status is the HTTP status code
+	Status int `json:"status"`
+
+	// Detail explains this occurrence of the problem
+	Detail string `json:"detail,omitempty"`
+
+	// Instance is a URI identifying this occurrence of the problem
+	Instance string `json:"instance,omitempty"`
+
+	// Kind, Path, Line and Column locate a decode failure in the request
+	// body, from the codec.DecodeError that caused it
+	Kind   string `json:"kind,omitempty"`
+	Path   string `json:"path,omitempty"`
+	Line   int    `json:"line,omitempty"`
+	Column int    `json:"column,omitempty"`
+
+	err error
+}
+
+// NewProblem returns a Problem with the given status, titled with the
+// status text
+func NewProblem(status int, detail string) *Problem {
+	return &Problem{Title: http.StatusText(status), Status: status, Detail: detail}
+}
+
+func (p *Problem) Error() string {
+	if p.Detail == "" {
+		return p.Title
+	}
+	return p.Title + ": " + p.Detail
+}
+
+// Unwrap returns the error that caused the problem, such as a
+// codec.DecodeError
+func (p *Problem) Unwrap() error {
+	return p.err
+}
+
+// decodeProblem describes err, returned while decoding a request body
+func decodeProblem(err error) *Problem {
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		p := NewProblem(http.StatusBadRequest, err.Error())
+		p.err = err
+		return p
+	}
+
+	status := http.StatusBadRequest
+	switch de.Kind {
+	case codec.ErrLimitExceeded:
+		status = http.StatusRequestEntityTooLarge
+	case codec.ErrTypeMismatch, codec.ErrUnknownField, codec.ErrDuplicateKey:
+		status = http.StatusUnprocessableEntity
+	}
+	p := NewProblem(status, de.Error())
+	p.Kind = de.Kind.String()
+	p.Path = de.Path
+	p.Line = de.Line
+	p.Column = de.Column
+	p.err = err
+	return p
+}
+
+// WriteProblem writes err as an application/problem+json response. A
+// *Problem in err's chain is written as is; any other error is written as
+// 500 Internal Server Error without revealing its message.
+func WriteProblem(w http.ResponseWriter, err error) {
+	var p *Problem
+	if !errors.As(err, &p) {
+		p = NewProblem(http.StatusInternalServerError, "")
+	}
+	body, merr := json.Marshal(p)
+	if merr != nil {
+		http.Error(w, http.StatusText(http.StatusInternalServerError), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", ProblemMediaType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(body)))
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(p.Status)
+	_, _ = w.Write(body)
+}
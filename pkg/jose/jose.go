@@ -0,0 +1,225 @@
+// Package jose encodes typed claim sets as JWS and JWE compact
+// serializations. JWS signs tokens with HS256, ES256 or EdDSA as defined in
+// RFC 7515, 7518 and 8037, and JWE encrypts them with direct key agreement
+// and A256GCM as defined in RFC 7516. The claims are encoded with a payload
+// codec, usually JSON, and both implement codec.Codec[T]:
+//
+//	keys := jose.NewKeyring("2024-01", jose.HS256Key(secret))
+//	c := jose.NewJWS[Claims](json.New[Claims](), keys, jose.WithType("JWT"))
+//	token, err := c.Marshal(claims)
+//
+// Verification only accepts the algorithms allowed by WithAllowedAlgorithms,
+// all supported signature algorithms by default and never "none", and the
+// key named by the "kid" header must be of the algorithm named by the
+// "alg" header. A token therefore cannot have a public key used as an HMAC
+// secret or switch to an algorithm the application does not expect.
+// Keys embedded in or referenced by the header ("jwk", "jku", "x5c", "x5u")
+// are ignored.
+package jose
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// errorCodec names the package in the errors it reports
+const errorCodec codec.Type = "jose"
+
+// Algorithm is the "alg" header parameter of a JWS or JWE
+type Algorithm string
+
+const (
+	// HS256 is HMAC with SHA-256
+	HS256 Algorithm = "HS256"
+	// ES256 is ECDSA with P-256 and SHA-256
+	ES256 Algorithm = "ES256"
+	// EdDSA is Ed25519 as defined in RFC 8037
+	EdDSA Algorithm = "EdDSA"
+	// Dir uses a shared symmetric key directly as the content encryption
+	// key of a JWE
+	Dir Algorithm = "dir"
+)
+
+// A256GCM is the "enc" header parameter of JWE tokens, AES-GCM with a
+// 256-bit key
+const A256GCM = "A256GCM"
+
+// signatureAlgorithms lists the algorithms a JWS accepts by default
+var signatureAlgorithms = []Algorithm{HS256, ES256, EdDSA}
+
+// ErrAlgorithmNotSupported is returned for an algorithm this package does
+// not implement, or that does not apply to the operation
+type ErrAlgorithmNotSupported struct {
+	Algorithm Algorithm
+}
+
+func (e ErrAlgorithmNotSupported) Error() string {
+	return fmt.Sprintf("jose: algorithm %q is not supported", string(e.Algorithm))
+}
+
+// ErrAlgorithmNotAllowed is reported, wrapped in a codec.DecodeError, for a
+// token whose "alg" header names an algorithm outside the allow-list
+type ErrAlgorithmNotAllowed struct {
+	Algorithm Algorithm
+}
+
+func (e ErrAlgorithmNotAllowed) Error() string {
+	return fmt.Sprintf("jose: algorithm %q is not allowed", string(e.Algorithm))
+}
+
+// ErrInvalidSignature is reported, wrapped in a codec.DecodeError, for a JWS
+// whose header, payload or signature were modified, or that was signed
+// with another key or algorithm than the key named by KeyID
+type ErrInvalidSignature struct {
+	KeyID     string
+	Algorithm Algorithm
+}
+
+func (e ErrInvalidSignature) Error() string {
+	return fmt.Sprintf("jose: invalid %s signature with key %q", string(e.Algorithm), e.KeyID)
+}
+
+// ErrAuthenticationFailed is reported, wrapped in a codec.DecodeError, for
+// a JWE whose header, IV, ciphertext or tag were modified, or that was
+// encrypted with another key
+type ErrAuthenticationFailed struct {
+	KeyID string
+}
+
+func (e ErrAuthenticationFailed) Error() string {
+	return fmt.Sprintf("jose: message authentication failed with key %q", e.KeyID)
+}
+
+// header holds the JOSE header parameters this package reads and writes.
+// Other parameters are ignored.
+type header struct {
+	Alg  Algorithm `json:"alg"`
+	Enc  string    `json:"enc,omitempty"`
+	Kid  string    `json:"kid,omitempty"`
+	Typ  string    `json:"typ,omitempty"`
+	Crit []string  `json:"crit,omitempty"`
+}
+
+// encoding is the base64url encoding without padding used by compact
+// serializations
+var encoding = base64.RawURLEncoding
+
+// encodeHeader returns the base64url encoding of h
+func encodeHeader(h header) ([]byte, error) {
+	data, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	return appendEncoded(nil, data), nil
+}
+
+// parseHeader decodes the base64url-encoded header of a token, rejecting
+// critical parameters, none of which this package understands
+func parseHeader(encoded []byte) (header, error) {
+	data, err := decodePart(encoded, 0)
+	if err != nil {
+		return header{}, err
+	}
+	var h header
+	if err := json.Unmarshal(data, &h); err != nil {
+		return header{}, codec.DecodeError{Codec: errorCodec, Kind: codec.ErrSyntax, Offset: 0, Err: err}
+	}
+	if len(h.Crit) > 0 {
+		return header{}, codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: 0,
+			Err:    fmt.Errorf("jose: unsupported critical header parameters %q", h.Crit),
+		}
+	}
+	return h, nil
+}
+
+// checkType rejects a token whose "typ" header differs from the expected
+// type, if one is configured
+func checkType(h header, typ string) error {
+	if typ == "" || h.Typ == typ {
+		return nil
+	}
+	return codec.DecodeError{
+		Codec:  errorCodec,
+		Kind:   codec.ErrSyntax,
+		Offset: 0,
+		Err:    fmt.Errorf("jose: token type %q, want %q", h.Typ, typ),
+	}
+}
+
+// appendEncoded appends the base64url encoding of data to dst
+func appendEncoded(dst, data []byte) []byte {
+	return encoding.AppendEncode(dst, data)
+}
+
+// decodePart decodes a base64url-encoded part of a token that starts at
+// offset
+func decodePart(part []byte, offset int) ([]byte, error) {
+	data, err := encoding.AppendDecode(nil, part)
+	if err != nil {
+		return nil, codec.DecodeError{Codec: errorCodec, Kind: codec.ErrSyntax, Offset: int64(offset), Err: err}
+	}
+	return data, nil
+}
+
+// splitToken splits a compact serialization into its n dot-separated parts
+// and returns them with the offset of each
+func splitToken(token []byte, n int) ([][]byte, []int, error) {
+	parts := make([][]byte, 0, n)
+	offsets := make([]int, 0, n)
+	start := 0
+	for i, b := range token {
+		if b == '.' {
+			parts = append(parts, token[start:i])
+			offsets = append(offsets, start)
+			start = i + 1
+		}
+	}
+	parts = append(parts, token[start:])
+	offsets = append(offsets, start)
+	if len(parts) != n {
+		return nil, nil, codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: -1,
+			Err:    fmt.Errorf("jose: token has %d parts, want %d", len(parts), n),
+		}
+	}
+	return parts, offsets, nil
+}
+
+// readToken reads a whole token from r, such as a request body
+func readToken(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return nil, io.EOF
+	}
+	return data, nil
+}
+
+// newError returns the error, if any, caused by the arguments of NewJWS or
+// NewJWE
+func newError(payload any, keys KeyResolver, cfg *config, opts []codec.Option) error {
+	if e, ok := payload.(interface{ Err() error }); ok {
+		if err := e.Err(); err != nil {
+			return err
+		}
+	}
+	if err := codec.ApplyOptions(errorCodec, cfg, opts); err != nil {
+		return err
+	}
+	if keys == nil {
+		return errors.New("jose: nil KeyResolver")
+	}
+	return nil
+}
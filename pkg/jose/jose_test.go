@@ -0,0 +1,410 @@
+//go:build codec_json
+
+package jose
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/factory"
+	"github.com/jeremyhahn/go-codec/pkg/httpcodec"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+)
+
+type Claims struct {
+	Issuer  string `json:"iss"`
+	Subject string `json:"sub"`
+	Expiry  int64  `json:"exp"`
+}
+
+var testClaims = Claims{Issuer: "https://issuer.example.com", Subject: "john", Expiry: 1300819380}
+
+var testSecret = bytes.Repeat([]byte{1}, MinHMACKeySize)
+
+// rawCodec passes payload bytes through unchanged
+type rawCodec struct{}
+
+func (rawCodec) Encode(w io.Writer, data []byte) error { _, err := w.Write(data); return err }
+func (rawCodec) Decode(r io.Reader, data *[]byte) error {
+	var err error
+	*data, err = io.ReadAll(r)
+	return err
+}
+func (rawCodec) Marshal(data []byte) ([]byte, error)    { return data, nil }
+func (rawCodec) Unmarshal(data []byte, v *[]byte) error { *v = data; return nil }
+
+func signingKeys(t testing.TB) map[string]Key {
+	ec, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, ed, _ := ed25519.GenerateKey(nil)
+	return map[string]Key{"HS256": HS256Key(testSecret), "ES256": ES256PrivateKey(ec), "EdDSA": EdDSAPrivateKey(ed)}
+}
+
+func mustDecode(t testing.TB, s string) []byte {
+	data, err := encoding.DecodeString(s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func TestJWS_RoundTrip(t *testing.T) {
+	for name, key := range signingKeys(t) {
+		t.Run(name, func(t *testing.T) {
+			c := NewJWS[Claims](jsoncodec.New[Claims](), NewKeyring("k1", key))
+			if err := c.Err(); err != nil {
+				t.Fatalf("NewJWS failed: %v", err)
+			}
+			token, err := c.Marshal(testClaims)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if n := bytes.Count(token, []byte(".")); n != 2 {
+				t.Fatalf("token %s has %d dots, want 2", token, n)
+			}
+			h, err := parseHeader(token[:bytes.IndexByte(token, '.')])
+			if err != nil || h.Alg != key.Algorithm() || h.Kid != "k1" {
+				t.Errorf("header = %+v, %v", h, err)
+			}
+
+			var got Claims
+			if err := c.Unmarshal(append(token, '\n'), &got); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if got != testClaims {
+				t.Errorf("Unmarshal = %+v, want %+v", got, testClaims)
+			}
+
+			var buf bytes.Buffer
+			if err := c.Encode(&buf, testClaims); err != nil {
+				t.Fatalf("Encode failed: %v", err)
+			}
+			got = Claims{}
+			if err := c.Decode(&buf, &got); err != nil || got != testClaims {
+				t.Errorf("Decode = %+v, %v", got, err)
+			}
+		})
+	}
+}
+
+func TestJWS_RFC7515Example(t *testing.T) {
+	// RFC 7515 appendix A.1
+	token := "eyJ0eXAiOiJKV1QiLA0KICJhbGciOiJIUzI1NiJ9" +
+		".eyJpc3MiOiJqb2UiLA0KICJleHAiOjEzMDA4MTkzODAsDQogImh0dHA6Ly9leGFtcGxlLmNvbS9pc19yb290Ijp0cnVlfQ" +
+		".dBjftJeZ4CVP-mB92K27uhbUJU1p1r_wW1gFWFOEjXk"
+	secret := mustDecode(t, "AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow")
+
+	c := NewJWS[Claims](jsoncodec.New[Claims](), NewKeyring("", HS256Key(secret)), WithType("JWT"))
+	var got Claims
+	if err := c.Unmarshal([]byte(token), &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if got.Issuer != "joe" || got.Expiry != 1300819380 {
+		t.Errorf("Unmarshal = %+v", got)
+	}
+
+	tampered := strings.Replace(token, ".dBjf", ".dBjg", 1)
+	var invalid ErrInvalidSignature
+	if err := c.Unmarshal([]byte(tampered), &got); !errors.As(err, &invalid) {
+		t.Errorf("Unmarshal of a modified signature = %v, want ErrInvalidSignature", err)
+	}
+}
+
+func TestJWS_RFC8037Example(t *testing.T) {
+	// RFC 8037 appendix A.4; Ed25519 signatures are deterministic
+	seed := mustDecode(t, "nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A")
+	want := "eyJhbGciOiJFZERTQSJ9.RXhhbXBsZSBvZiBFZDI1NTE5IHNpZ25pbmc" +
+		".hgyY0il_MGCjP0JzlnLWG1PPOt7-09PGcvMg3AIbQR6dWbhijcNR4ki4iylGjg5BhVsPt9g7sVvpAr_MuM0KAg"
+
+	c := NewJWS[[]byte](rawCodec{}, NewKeyring("", EdDSAPrivateKey(ed25519.NewKeyFromSeed(seed))))
+	token, err := c.Marshal([]byte("Example of Ed25519 signing"))
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(token) != want {
+		t.Errorf("Marshal = %s, want %s", token, want)
+	}
+}
+
+func TestJWS_AlgorithmConfusion(t *testing.T) {
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	verifier := NewJWS[Claims](jsoncodec.New[Claims](), NewKeyring("k1", ES256PublicKey(&ec.PublicKey)))
+
+	// An attacker who knows the public key signs HS256 with it as the secret
+	der, _ := x509.MarshalPKIXPublicKey(&ec.PublicKey)
+	forger := NewJWS[Claims](jsoncodec.New[Claims](), NewKeyring("k1", HS256Key(der)))
+	token, err := forger.Marshal(testClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got Claims
+	var invalid ErrInvalidSignature
+	if err := verifier.Unmarshal(token, &got); !errors.As(err, &invalid) || invalid.Algorithm != HS256 {
+		t.Errorf("Unmarshal of an HS256 token for an ES256 key = %v, want ErrInvalidSignature", err)
+	}
+
+	// Unsigned tokens are never accepted
+	none := appendEncoded(nil, []byte(`{"alg":"none"}`))
+	none = append(none, '.')
+	none = appendEncoded(none, []byte(`{"sub":"admin"}`))
+	none = append(none, '.')
+	var notAllowed ErrAlgorithmNotAllowed
+	if err := verifier.Unmarshal(none, &got); !errors.As(err, &notAllowed) || notAllowed.Algorithm != "none" {
+		t.Errorf("Unmarshal of an unsigned token = %v, want ErrAlgorithmNotAllowed", err)
+	}
+}
+
+func TestJWS_AllowedAlgorithms(t *testing.T) {
+	keys := NewKeyring("k1", HS256Key(testSecret))
+	signer := NewJWS[Claims](jsoncodec.New[Claims](), keys)
+	token, err := signer.Marshal(testClaims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strict := NewJWS[Claims](jsoncodec.New[Claims](), keys, WithAllowedAlgorithms(EdDSA))
+	var got Claims
+	var notAllowed ErrAlgorithmNotAllowed
+	var decodeErr codec.DecodeError
+	err = strict.Unmarshal(token, &got)
+	if !errors.As(err, &notAllowed) || notAllowed.Algorithm != HS256 || !errors.As(err, &decodeErr) {
+		t.Errorf("Unmarshal = %v, want ErrAlgorithmNotAllowed in a DecodeError", err)
+	}
+	if _, err := strict.Marshal(testClaims); !errors.As(err, &notAllowed) {
+		t.Errorf("Marshal with a disallowed key = %v, want ErrAlgorithmNotAllowed", err)
+	}
+}
+
+func TestJWS_KeyRotation(t *testing.T) {
+	keys := NewKeyring("old", HS256Key(testSecret))
+	c := NewJWS[Claims](jsoncodec.New[Claims](), keys)
+	old, _ := c.Marshal(testClaims)
+
+	_, ed, _ := ed25519.GenerateKey(nil)
+	keys.Rotate("new", EdDSAPrivateKey(ed))
+	current, _ := c.Marshal(testClaims)
+	for _, token := range [][]byte{old, current} {
+		var got Claims
+		if err := c.Unmarshal(token, &got); err != nil || got != testClaims {
+			t.Errorf("Unmarshal = %+v, %v", got, err)
+		}
+	}
+
+	keys.Remove("old")
+	var got Claims
+	var unknown ErrUnknownKey
+	if err := c.Unmarshal(old, &got); !errors.As(err, &unknown) || unknown.KeyID != "old" {
+		t.Errorf("Unmarshal with a removed key = %v, want ErrUnknownKey", err)
+	}
+}
+
+func TestJWE_RoundTrip(t *testing.T) {
+	keys := NewKeyring("k1", DirectKey(bytes.Repeat([]byte{2}, 32)))
+	c := NewJWE[Claims](jsoncodec.New[Claims](), keys, WithType("JWT"))
+	if err := c.Err(); err != nil {
+		t.Fatalf("NewJWE failed: %v", err)
+	}
+	token, err := c.Marshal(testClaims)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	parts := strings.Split(string(token), ".")
+	if len(parts) != 5 || parts[1] != "" {
+		t.Fatalf("token %s is not a dir JWE", token)
+	}
+	if bytes.Contains(token, appendEncoded(nil, []byte("john"))) {
+		t.Error("token contains the plaintext")
+	}
+	again, _ := c.Marshal(testClaims)
+	if bytes.Equal(token, again) {
+		t.Error("two tokens of the same claims are equal")
+	}
+
+	var got Claims
+	if err := c.Unmarshal(token, &got); err != nil || got != testClaims {
+		t.Errorf("Unmarshal = %+v, %v", got, err)
+	}
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, testClaims); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	got = Claims{}
+	if err := c.Decode(&buf, &got); err != nil || got != testClaims {
+		t.Errorf("Decode = %+v, %v", got, err)
+	}
+
+	keys.Rotate("k2", DirectKey(bytes.Repeat([]byte{3}, 32)))
+	if err := c.Unmarshal(token, &got); err != nil {
+		t.Errorf("Unmarshal after rotation failed: %v", err)
+	}
+
+	// A token encrypted with another key under the same ID fails
+	other := NewJWE[Claims](jsoncodec.New[Claims](), NewKeyring("k1", DirectKey(bytes.Repeat([]byte{4}, 32))))
+	var auth ErrAuthenticationFailed
+	if err := other.Unmarshal(token, &got); !errors.As(err, &auth) || auth.KeyID != "k1" {
+		t.Errorf("Unmarshal with the wrong key = %v, want ErrAuthenticationFailed", err)
+	}
+}
+
+func TestJWE_Tampered(t *testing.T) {
+	c := NewJWE[[]byte](rawCodec{}, NewKeyring("k1", DirectKey(bytes.Repeat([]byte{2}, 32))))
+	token, err := c.Marshal([]byte("secret payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	parts := strings.Split(string(token), ".")
+	header, _ := encodeHeader(header{Alg: Dir, Enc: A256GCM, Kid: "k1", Typ: "JWT"})
+
+	flip := func(part string) string {
+		data := mustDecode(t, part)
+		data[0] ^= 1
+		return string(appendEncoded(nil, data))
+	}
+	for name, modified := range map[string][]string{
+		"header":     {string(header), "", parts[2], parts[3], parts[4]},
+		"IV":         {parts[0], "", flip(parts[2]), parts[3], parts[4]},
+		"ciphertext": {parts[0], "", parts[2], flip(parts[3]), parts[4]},
+		"tag":        {parts[0], "", parts[2], parts[3], flip(parts[4])},
+	} {
+		t.Run(name, func(t *testing.T) {
+			var got []byte
+			var auth ErrAuthenticationFailed
+			if err := c.Unmarshal([]byte(strings.Join(modified, ".")), &got); !errors.As(err, &auth) {
+				t.Errorf("Unmarshal = %v, want ErrAuthenticationFailed", err)
+			}
+		})
+	}
+}
+
+func TestInvalidTokens(t *testing.T) {
+	jws := NewJWS[[]byte](rawCodec{}, NewKeyring("k1", HS256Key(testSecret)), WithType("JWT"))
+	jwe := NewJWE[[]byte](rawCodec{}, NewKeyring("k1", DirectKey(bytes.Repeat([]byte{2}, 32))))
+	signed, _ := jws.Marshal([]byte("payload"))
+	encrypted, _ := jwe.Marshal([]byte("payload"))
+	hdr := func(s string) string { return string(appendEncoded(nil, []byte(s))) }
+
+	tests := []struct {
+		name  string
+		c     codec.Codec[[]byte]
+		token string
+	}{
+		{"JWS empty", jws, ""},
+		{"JWS two parts", jws, "a.b"},
+		{"JWS bad base64", jws, "!!!.b.c"},
+		{"JWS bad header", jws, hdr("not json") + ".b.c"},
+		{"JWS critical header", jws, hdr(`{"alg":"HS256","crit":["exp"]}`) + ".b.c"},
+		{"JWS wrong type", jws, hdr(`{"alg":"HS256","kid":"k1","typ":"at+jwt"}`) + ".b.c"},
+		{"JWS as JWE", jwe, string(signed)},
+		{"JWE as JWS", jws, string(encrypted)},
+		{"JWE unknown enc", jwe, hdr(`{"alg":"dir","enc":"A128GCM"}`) + "...."},
+		{"JWE encrypted key", jwe, strings.Replace(string(encrypted), "..", ".AAAA.", 1)},
+		{"JWE short IV", jwe, hdr(`{"alg":"dir","enc":"A256GCM","kid":"k1"}`) + "..AAAA.AAAA." + hdr(strings.Repeat("x", 16))},
+		{"JWE short tag", jwe, hdr(`{"alg":"dir","enc":"A256GCM","kid":"k1"}`) + ".." + hdr(strings.Repeat("x", 12)) + ".AAAA.AAAA"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []byte
+			err := tt.c.Unmarshal([]byte(tt.token), &got)
+			var decodeErr codec.DecodeError
+			if !errors.As(err, &decodeErr) || decodeErr.Codec != "jose" {
+				t.Errorf("Unmarshal = %v, want a jose DecodeError", err)
+			}
+		})
+	}
+
+	var got []byte
+	if err := jws.Decode(strings.NewReader(""), &got); err != io.EOF {
+		t.Errorf("Decode of an empty stream = %v, want io.EOF", err)
+	}
+}
+
+func TestNew_Errors(t *testing.T) {
+	keys := NewKeyring("k1", HS256Key(testSecret))
+	var notSupported ErrAlgorithmNotSupported
+	if err := NewJWS[Claims](jsoncodec.New[Claims](), keys, WithAllowedAlgorithms(HS256, "none")).Err(); !errors.As(err, &notSupported) {
+		t.Errorf("NewJWS allowing none = %v, want ErrAlgorithmNotSupported", err)
+	}
+	if err := NewJWS[Claims](jsoncodec.New[Claims](), nil).Err(); err == nil {
+		t.Error("NewJWS with nil keys succeeded")
+	}
+	var unsupported codec.ErrOptionNotSupported
+	if err := NewJWE[Claims](jsoncodec.New[Claims](), keys, jsoncodec.WithIndent("", "  ")).Err(); !errors.As(err, &unsupported) {
+		t.Errorf("NewJWE with a JSON option = %v, want ErrOptionNotSupported", err)
+	}
+	if _, err := NewJWS[Claims](jsoncodec.New[Claims](), keys, WithAllowedAlgorithms(Dir)).Marshal(testClaims); !errors.As(err, &notSupported) {
+		t.Errorf("Marshal = %v, want the constructor error", err)
+	}
+
+	if _, err := NewJWE[Claims](jsoncodec.New[Claims](), keys).Marshal(testClaims); !errors.As(err, &notSupported) {
+		t.Errorf("JWE Marshal with an HS256 key = %v, want ErrAlgorithmNotSupported", err)
+	}
+	short := NewJWS[Claims](jsoncodec.New[Claims](), NewKeyring("k1", HS256Key([]byte("short"))))
+	if _, err := short.Marshal(testClaims); err == nil {
+		t.Error("Marshal with a short HS256 key succeeded")
+	}
+	ec, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	public := NewJWS[Claims](jsoncodec.New[Claims](), NewKeyring("k1", ES256PublicKey(&ec.PublicKey)))
+	if _, err := public.Marshal(testClaims); err == nil {
+		t.Error("Marshal with a public key succeeded")
+	}
+}
+
+func TestProvider(t *testing.T) {
+	const jwtType codec.Type = "jwt"
+	keys := NewKeyring("k1", HS256Key(testSecret))
+	codec.Register(codec.CodecInfo{
+		Type:       jwtType,
+		Name:       "JSON Web Token",
+		MediaTypes: []string{"application/jwt"},
+	}, NewProvider(codec.JSON, keys, WithType("JWT")))
+
+	c, err := factory.New[Claims](jwtType)
+	if err != nil {
+		t.Fatalf("factory.New failed: %v", err)
+	}
+	token, err := c.Marshal(testClaims)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var got Claims
+	if err := NewJWS[Claims](jsoncodec.New[Claims](), keys, WithType("JWT")).Unmarshal(token, &got); err != nil || got != testClaims {
+		t.Errorf("Unmarshal of the factory token = %+v, %v", got, err)
+	}
+
+	if _, err := factory.New[Claims](jwtType, WithAllowedAlgorithms("none")); err == nil {
+		t.Error("factory.New with an unsupported algorithm succeeded")
+	}
+
+	r := httptest.NewRequest(http.MethodPost, "/", bytes.NewReader(token))
+	r.Header.Set("Content-Type", "application/jwt")
+	got, err = httpcodec.Bind[Claims](r)
+	if err != nil || got != testClaims {
+		t.Errorf("Bind = %+v, %v", got, err)
+	}
+
+	r.Header.Set("Accept", "application/jwt")
+	w := httptest.NewRecorder()
+	if err := httpcodec.Respond(w, r, http.StatusOK, testClaims); err != nil {
+		t.Fatalf("Respond failed: %v", err)
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "application/jwt") {
+		t.Errorf("Content-Type = %q", ct)
+	}
+	got = Claims{}
+	if err := c.Unmarshal(w.Body.Bytes(), &got); err != nil || got != testClaims {
+		t.Errorf("Unmarshal of the response = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,200 @@
+package jose
+
+import (
+	"bytes"
+	"crypto/rand"
+	"fmt"
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+const (
+	// ivSize is the IV length of A256GCM
+	ivSize = 12
+
+	// tagSize is the authentication tag length of A256GCM
+	tagSize = 16
+)
+
+// JWE encodes values as JWE compact serializations encrypted with direct
+// key agreement and A256GCM
+type JWE[T any] struct {
+	payload codec.Codec[T]
+	keys    KeyResolver
+	cfg     config
+	err     error
+}
+
+// NewJWE returns a codec that encodes values with payload and encrypts them
+// with the current key of keys, which must be created with DirectKey. If
+// payload reports an error through an Err method, an option does not apply
+// to JOSE or keys is nil, every operation returns the error, which is also
+// reported by Err.
+func NewJWE[T any](payload codec.Codec[T], keys KeyResolver, opts ...codec.Option) *JWE[T] {
+	c := &JWE[T]{payload: payload, keys: keys}
+	c.err = newError(payload, keys, &c.cfg, opts)
+	return c
+}
+
+// Err returns the error, if any, caused by the arguments passed to NewJWE
+func (c *JWE[T]) Err() error {
+	return c.err
+}
+
+// Encode writes data to w as a JWE compact serialization
+func (c *JWE[T]) Encode(w io.Writer, data T) error {
+	out, err := c.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// Decode reads a JWE compact serialization from r, which is read to the
+// end, decrypts it and decodes its payload into data
+func (c *JWE[T]) Decode(r io.Reader, data *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	token, err := readToken(r)
+	if err != nil {
+		return err
+	}
+	return c.Unmarshal(token, data)
+}
+
+// Marshal encodes data with the payload codec and returns it encrypted as a
+// JWE compact serialization with a random IV
+func (c *JWE[T]) Marshal(data T) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	plaintext, err := c.payload.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.Encrypt(plaintext)
+}
+
+// Unmarshal decrypts the JWE compact serialization in data with the key
+// named by its "kid" header and decodes its payload into v. Surrounding
+// whitespace is ignored.
+func (c *JWE[T]) Unmarshal(data []byte, v *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	plaintext, err := c.Decrypt(data)
+	if err != nil {
+		return err
+	}
+	return c.payload.Unmarshal(plaintext, v)
+}
+
+// Encrypt returns plaintext encrypted with the current key as a JWE compact
+// serialization, without encoding it with the payload codec
+func (c *JWE[T]) Encrypt(plaintext []byte) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	id, key, err := c.keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	aead, err := key.aead()
+	if err != nil {
+		return nil, err
+	}
+	token, err := encodeHeader(header{Alg: Dir, Enc: A256GCM, Kid: id, Typ: c.cfg.typ})
+	if err != nil {
+		return nil, err
+	}
+	iv := make([]byte, ivSize)
+	if _, err := rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	// The additional authenticated data is the encoded protected header
+	sealed := aead.Seal(nil, iv, plaintext, token)
+	ciphertext, tag := sealed[:len(sealed)-tagSize], sealed[len(sealed)-tagSize:]
+
+	// Direct key agreement leaves the encrypted key empty
+	token = append(token, '.', '.')
+	token = appendEncoded(token, iv)
+	token = append(token, '.')
+	token = appendEncoded(token, ciphertext)
+	token = append(token, '.')
+	return appendEncoded(token, tag), nil
+}
+
+// Decrypt authenticates and decrypts the JWE compact serialization in token
+// and returns its payload without decoding it
+func (c *JWE[T]) Decrypt(token []byte) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	token = bytes.TrimSpace(token)
+	parts, offsets, err := splitToken(token, 5)
+	if err != nil {
+		return nil, err
+	}
+	h, err := parseHeader(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	if h.Alg != Dir {
+		return nil, codec.DecodeError{Codec: errorCodec, Kind: codec.ErrSyntax, Offset: 0, Err: ErrAlgorithmNotAllowed{Algorithm: h.Alg}}
+	}
+	if h.Enc != A256GCM {
+		return nil, syntaxError(0, fmt.Errorf("jose: content encryption %q is not supported", h.Enc))
+	}
+	if err := checkType(h, c.cfg.typ); err != nil {
+		return nil, err
+	}
+	if len(parts[1]) != 0 {
+		return nil, syntaxError(offsets[1], fmt.Errorf("jose: encrypted key must be empty for %q", string(Dir)))
+	}
+	iv, err := decodePart(parts[2], offsets[2])
+	if err != nil {
+		return nil, err
+	}
+	if len(iv) != ivSize {
+		return nil, syntaxError(offsets[2], fmt.Errorf("jose: IV is %d bytes, want %d", len(iv), ivSize))
+	}
+	sealed, err := decodePart(parts[3], offsets[3])
+	if err != nil {
+		return nil, err
+	}
+	tag, err := decodePart(parts[4], offsets[4])
+	if err != nil {
+		return nil, err
+	}
+	if len(tag) != tagSize {
+		return nil, syntaxError(offsets[4], fmt.Errorf("jose: authentication tag is %d bytes, want %d", len(tag), tagSize))
+	}
+
+	key, err := c.keys.Key(h.Kid)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := key.aead()
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aead.Open(nil, iv, append(sealed, tag...), parts[0])
+	if err != nil {
+		return nil, codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: int64(offsets[3]),
+			Err:    ErrAuthenticationFailed{KeyID: h.Kid},
+		}
+	}
+	return plaintext, nil
+}
+
+// syntaxError reports a malformed part of a token starting at offset
+func syntaxError(offset int, err error) error {
+	return codec.DecodeError{Codec: errorCodec, Kind: codec.ErrSyntax, Offset: int64(offset), Err: err}
+}
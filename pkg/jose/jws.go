@@ -0,0 +1,167 @@
+package jose
+
+import (
+	"bytes"
+	"io"
+	"slices"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// JWS encodes values as JWS compact serializations signed with HS256,
+// ES256 or EdDSA
+type JWS[T any] struct {
+	payload codec.Codec[T]
+	keys    KeyResolver
+	cfg     config
+	err     error
+}
+
+// NewJWS returns a codec that encodes values with payload and signs them
+// with the current key of keys. If payload reports an error through an Err
+// method, an option does not apply to JOSE, the allow-list names an
+// unsupported algorithm or keys is nil, every operation returns the error,
+// which is also reported by Err.
+func NewJWS[T any](payload codec.Codec[T], keys KeyResolver, opts ...codec.Option) *JWS[T] {
+	c := &JWS[T]{payload: payload, keys: keys}
+	if c.err = newError(payload, keys, &c.cfg, opts); c.err != nil {
+		return c
+	}
+	if c.cfg.allowed == nil {
+		c.cfg.allowed = signatureAlgorithms
+	}
+	for _, alg := range c.cfg.allowed {
+		if !slices.Contains(signatureAlgorithms, alg) {
+			c.err = ErrAlgorithmNotSupported{Algorithm: alg}
+			break
+		}
+	}
+	return c
+}
+
+// Err returns the error, if any, caused by the arguments passed to NewJWS
+func (c *JWS[T]) Err() error {
+	return c.err
+}
+
+// Encode writes data to w as a JWS compact serialization
+func (c *JWS[T]) Encode(w io.Writer, data T) error {
+	out, err := c.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// Decode reads a JWS compact serialization from r, which is read to the
+// end, verifies it and decodes its payload into data
+func (c *JWS[T]) Decode(r io.Reader, data *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	token, err := readToken(r)
+	if err != nil {
+		return err
+	}
+	return c.Unmarshal(token, data)
+}
+
+// Marshal encodes data with the payload codec and returns it signed as a
+// JWS compact serialization
+func (c *JWS[T]) Marshal(data T) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	payload, err := c.payload.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.Sign(payload)
+}
+
+// Unmarshal verifies the JWS compact serialization in data with the key
+// named by its "kid" header and decodes its payload into v. Surrounding
+// whitespace is ignored.
+func (c *JWS[T]) Unmarshal(data []byte, v *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	payload, err := c.Verify(data)
+	if err != nil {
+		return err
+	}
+	return c.payload.Unmarshal(payload, v)
+}
+
+// Sign returns payload signed with the current key as a JWS compact
+// serialization, without encoding it with the payload codec
+func (c *JWS[T]) Sign(payload []byte) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	id, key, err := c.keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(c.cfg.allowed, key.algo) {
+		return nil, ErrAlgorithmNotAllowed{Algorithm: key.algo}
+	}
+	token, err := encodeHeader(header{Alg: key.algo, Kid: id, Typ: c.cfg.typ})
+	if err != nil {
+		return nil, err
+	}
+	token = append(token, '.')
+	token = appendEncoded(token, payload)
+	sig, err := key.sign(token)
+	if err != nil {
+		return nil, err
+	}
+	token = append(token, '.')
+	return appendEncoded(token, sig), nil
+}
+
+// Verify checks the JWS compact serialization in token and returns its
+// payload without decoding it
+func (c *JWS[T]) Verify(token []byte) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	token = bytes.TrimSpace(token)
+	parts, offsets, err := splitToken(token, 3)
+	if err != nil {
+		return nil, err
+	}
+	h, err := parseHeader(parts[0])
+	if err != nil {
+		return nil, err
+	}
+	if !slices.Contains(c.cfg.allowed, h.Alg) {
+		return nil, codec.DecodeError{Codec: errorCodec, Kind: codec.ErrSyntax, Offset: 0, Err: ErrAlgorithmNotAllowed{Algorithm: h.Alg}}
+	}
+	if err := checkType(h, c.cfg.typ); err != nil {
+		return nil, err
+	}
+	payload, err := decodePart(parts[1], offsets[1])
+	if err != nil {
+		return nil, err
+	}
+	sig, err := decodePart(parts[2], offsets[2])
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := c.keys.Key(h.Kid)
+	if err != nil {
+		return nil, err
+	}
+	if key.algo != h.Alg || !key.verify(token[:offsets[2]-1], sig) {
+		return nil, codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: int64(offsets[2]),
+			Err:    ErrInvalidSignature{KeyID: h.Kid, Algorithm: h.Alg},
+		}
+	}
+	return payload, nil
+}
@@ -0,0 +1,28 @@
+package jose
+
+import codec "github.com/jeremyhahn/go-codec"
+
+// config holds the settings applied by JOSE options
+type config struct {
+	allowed []Algorithm
+	typ     string
+}
+
+// WithAllowedAlgorithms restricts the "alg" header values a JWS accepts, and
+// the algorithms its keys may sign with. By default every supported
+// signature algorithm is allowed, as it is when algs is empty; "none" never
+// is. JWE tokens always use "dir" and ignore it.
+func WithAllowedAlgorithms(algs ...Algorithm) codec.Option {
+	return codec.NewOption("jose.WithAllowedAlgorithms", func(c *config) {
+		c.allowed = algs
+	})
+}
+
+// WithType writes typ, such as "JWT" or "at+jwt", as the "typ" header of
+// every token and rejects tokens with another type, following the explicit
+// typing of RFC 8725 section 3.11
+func WithType(typ string) codec.Option {
+	return codec.NewOption("jose.WithType", func(c *config) {
+		c.typ = typ
+	})
+}
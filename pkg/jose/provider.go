@@ -0,0 +1,34 @@
+package jose
+
+import (
+	"reflect"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/factory"
+)
+
+// NewProvider returns a codec.Provider of JWS codecs whose payloads are
+// encoded with the payload codec type, usually codec.JSON, and signed with
+// keys. Registering it makes JWS available to factory.New and the HTTP
+// helpers of pkg/httpcodec:
+//
+//	codec.Register(codec.CodecInfo{
+//		Type:       "jwt",
+//		Name:       "JSON Web Token",
+//		MediaTypes: []string{"application/jwt"},
+//	}, jose.NewProvider(codec.JSON, keys, jose.WithType("JWT")))
+//
+// Options passed to factory.New are applied after opts.
+func NewProvider(payload codec.Type, keys KeyResolver, opts ...codec.Option) codec.Provider {
+	return codec.ProviderFunc(func(typ reflect.Type, more ...codec.Option) (codec.Codec[any], error) {
+		p, err := factory.New[any](payload)
+		if err != nil {
+			return nil, err
+		}
+		c := NewJWS[any](p, keys, append(opts[:len(opts):len(opts)], more...)...)
+		if err := c.Err(); err != nil {
+			return nil, err
+		}
+		return c, nil
+	})
+}
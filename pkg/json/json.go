@@ -3,42 +3,233 @@
 package json
 
 import (
+	"bytes"
 	"encoding/json"
+	"errors"
 	"io"
+	"sync"
 
 	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/codecgen"
 )
 
 func init() {
 	codec.RegisterCodec(codec.JSON)
+	codec.RegisterValueCodec(codec.JSON, valueCodec{})
 }
 
+// errTrailingData is returned by Unmarshal when data holds more than one value
+var errTrailingData = errors.New("json: invalid data after top-level value")
+
 // Codec implements the codec.Codec interface for JSON serialization
-type Codec[T any] struct{}
+type Codec[T any] struct {
+	cfg      config
+	gen      codecgen.Cache[T]
+	encoders sync.Pool
+	err      error
+}
+
+// New creates a new JSON codec configured with the given options. If an
+// option does not apply to JSON, every operation returns the error, which
+// is also reported by Err.
+func New[T any](opts ...codec.Option) *Codec[T] {
+	c := &Codec[T]{}
+	c.err = codec.ApplyOptions(codec.JSON, &c.cfg, opts)
+	return c
+}
 
-// New creates a new JSON codec
-func New[T any]() *Codec[T] {
-	return &Codec[T]{}
+// Err returns the error, if any, caused by the options passed to New
+func (c *Codec[T]) Err() error {
+	return c.err
 }
 
 // Encode serializes the given data to the writer using JSON
 func (c *Codec[T]) Encode(w io.Writer, data T) error {
-	encoder := json.NewEncoder(w)
-	return encoder.Encode(data)
+	if c.err != nil {
+		return c.err
+	}
+	if v, ok := any(data).(codec.Value); ok {
+		raw, err := c.marshalValue(v)
+		if err != nil {
+			return err
+		}
+		return c.newEncoder(w).Encode(raw)
+	}
+	return c.newEncoder(w).Encode(data)
 }
 
 // Decode deserializes JSON data from the reader into the provided type
 func (c *Codec[T]) Decode(r io.Reader, data *T) error {
-	decoder := json.NewDecoder(r)
-	return decoder.Decode(data)
+	if c.err != nil {
+		return c.err
+	}
+	if v, ok := any(data).(*codec.Value); ok {
+		var raw json.RawMessage
+		if err := c.raw().Decode(r, &raw); err != nil {
+			return err
+		}
+		return c.convertValue(raw, v)
+	}
+	r = codec.NewLimitedReader(r, codec.JSON, c.cfg.Limits.MaxBytes)
+	return c.decodeNext(c.newDecoder(r), data)
 }
 
 // Marshal serializes the given data to JSON bytes
 func (c *Codec[T]) Marshal(data T) ([]byte, error) {
-	return json.Marshal(data)
+	if c.err != nil {
+		return nil, c.err
+	}
+	b, err := c.appendMarshal(nil, data)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
 }
 
 // Unmarshal deserializes JSON bytes into the provided type
 func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
-	return json.Unmarshal(data, v)
+	if c.err != nil {
+		return c.err
+	}
+	if value, ok := any(v).(*codec.Value); ok {
+		return c.unmarshalValue(data, value)
+	}
+	if err := c.checkLimits(data); err != nil {
+		return err
+	}
+	if c.cfg.Strict {
+		if err := checkDuplicateKeys(data); err != nil {
+			return err
+		}
+	}
+	if c.cfg.decodesDefault() {
+		if c.parseGenerated(data, v) {
+			return nil
+		}
+		return decodeError(json.Unmarshal(data, v), data)
+	}
+
+	decoder := c.newDecoder(bytes.NewReader(data))
+	if err := decoder.Decode(v); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return decodeError(err, data)
+	}
+	if offset := decoder.InputOffset(); len(bytes.TrimSpace(data[offset:])) > 0 {
+		return trailingDataError(data, offset)
+	}
+	return nil
+}
+
+// appendMarshal appends the encoding of data to buf. Code generated with
+// codecgen writes directly into buf; other values are encoded by
+// encoding/json and copied into it.
+func (c *Codec[T]) appendMarshal(buf []byte, data T) ([]byte, error) {
+	var value any = data
+	if v, ok := value.(codec.Value); ok {
+		raw, err := c.marshalValue(v)
+		if err != nil {
+			return buf, err
+		}
+		value = raw
+	} else if c.cfg.encodesDefault() {
+		if b, ok := c.appendGenerated(buf, data); ok {
+			return b, nil
+		}
+	}
+	return c.appendEncoded(buf, value)
+}
+
+// sliceWriter appends everything written to it to buf
+type sliceWriter struct {
+	buf []byte
+}
+
+func (w *sliceWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+// sliceEncoder is a json.Encoder with the codec's settings writing to w
+type sliceEncoder struct {
+	w       sliceWriter
+	encoder *json.Encoder
+}
+
+// appendEncoded appends the encoding of value to buf with a pooled
+// encoder. json.Encoder marshals into a pooled buffer of its own and
+// writes the result in a single call, so the output is copied once.
+func (c *Codec[T]) appendEncoded(buf []byte, value any) ([]byte, error) {
+	e, _ := c.encoders.Get().(*sliceEncoder)
+	if e == nil {
+		e = &sliceEncoder{}
+		e.encoder = c.newEncoder(&e.w)
+	}
+	e.w.buf = buf
+	err := e.encoder.Encode(value)
+	out := e.w.buf
+	e.w.buf = nil
+	c.encoders.Put(e)
+	if err != nil {
+		return buf, err
+	}
+	// Drop the newline that Encode writes after each value
+	return out[:len(out)-1], nil
+}
+
+// appendGenerated appends data encoded by code generated with codecgen,
+// reporting false if T has none or it left data to reflection
+func (c *Codec[T]) appendGenerated(buf []byte, data T) ([]byte, bool) {
+	f, ok := c.gen.Get(codec.JSON)
+	if !ok {
+		return nil, false
+	}
+	out, err := f.Append(buf, data)
+	return out, err == nil
+}
+
+// parseGenerated decodes data with code generated with codecgen, reporting
+// false if T has none or it left data to reflection
+func (c *Codec[T]) parseGenerated(data []byte, v *T) bool {
+	f, ok := c.gen.Get(codec.JSON)
+	return ok && f.Parse(data, v) == nil
+}
+
+// decodeNext decodes the next value from decoder. When structural limits
+// or strict decoding are set the value is read whole and checked before it
+// is decoded.
+func (c *Codec[T]) decodeNext(decoder *json.Decoder, v *T) error {
+	if !c.cfg.prescans() {
+		return decodeError(decoder.Decode(v), nil)
+	}
+	var raw json.RawMessage
+	if err := decoder.Decode(&raw); err != nil {
+		return decodeError(err, nil)
+	}
+	return c.Unmarshal(raw, v)
+}
+
+// newEncoder returns a json.Encoder writing to w with the codec's settings
+func (c *Codec[T]) newEncoder(w io.Writer) *json.Encoder {
+	encoder := json.NewEncoder(w)
+	if c.cfg.prefix != "" || c.cfg.indent != "" {
+		encoder.SetIndent(c.cfg.prefix, c.cfg.indent)
+	}
+	if c.cfg.disableHTMLEscape {
+		encoder.SetEscapeHTML(false)
+	}
+	return encoder
+}
+
+// newDecoder returns a json.Decoder reading from r with the codec's settings
+func (c *Codec[T]) newDecoder(r io.Reader) *json.Decoder {
+	decoder := json.NewDecoder(r)
+	if c.cfg.useNumber {
+		decoder.UseNumber()
+	}
+	if c.cfg.disallowUnknownFields || c.cfg.Strict {
+		decoder.DisallowUnknownFields()
+	}
+	return decoder
 }
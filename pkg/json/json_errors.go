@@ -0,0 +1,71 @@
+//go:build codec_json
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// unknownFieldPrefix starts the message of the error encoding/json reports
+// for unknown fields; the library has no dedicated type for it
+const unknownFieldPrefix = "json: unknown field "
+
+// decodeError converts an error returned by encoding/json into a
+// codec.DecodeError. data is the complete input when it is available and is
+// used to compute line and column. Errors that do not describe the input,
+// such as read errors and io.EOF at the end of a stream, are returned as is.
+func decodeError(err error, data []byte) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+
+	de := codec.DecodeError{Codec: codec.JSON, Offset: -1, Err: err}
+	var syntaxErr *json.SyntaxError
+	var typeErr *json.UnmarshalTypeError
+	switch {
+	case errors.As(err, &syntaxErr):
+		// The library reports the offset just past the offending byte
+		de.Kind, de.Offset = codec.ErrSyntax, syntaxErr.Offset-1
+		if syntaxErr.Error() == "unexpected end of JSON input" {
+			de.Kind, de.Offset = codec.ErrTruncated, syntaxErr.Offset
+		}
+	case errors.As(err, &typeErr):
+		de.Kind = codec.ErrTypeMismatch
+		de.Offset = typeErr.Offset
+		de.Path = typeErr.Field
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		de.Kind = codec.ErrTruncated
+		if data != nil {
+			de.Offset = int64(len(data))
+		}
+	case strings.HasPrefix(err.Error(), unknownFieldPrefix):
+		de.Kind = codec.ErrUnknownField
+		de.Path = strings.TrimPrefix(err.Error(), unknownFieldPrefix)
+		if name, uerr := strconv.Unquote(de.Path); uerr == nil {
+			de.Path = name
+		}
+	default:
+		return err
+	}
+
+	if data != nil && de.Offset >= 0 {
+		de.Line, de.Column = codec.Position(data, de.Offset)
+	}
+	return de
+}
+
+// trailingDataError returns the error for data holding more than one value,
+// where the first value ends at offset
+func trailingDataError(data []byte, offset int64) error {
+	offset += int64(len(data[offset:]) - len(bytes.TrimLeft(data[offset:], " \t\r\n")))
+	de := codec.DecodeError{Codec: codec.JSON, Kind: codec.ErrSyntax, Offset: offset, Err: errTrailingData}
+	de.Line, de.Column = codec.Position(data, offset)
+	return de
+}
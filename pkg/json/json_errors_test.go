@@ -0,0 +1,141 @@
+//go:build codec_json
+
+package json
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+type nested struct {
+	User struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	} `json:"user"`
+}
+
+func TestUnmarshal_SyntaxError(t *testing.T) {
+	var v TestStruct
+	err := New[TestStruct]().Unmarshal([]byte("{\n  \"name\": \"John\",\n  \"age\" 30\n}"), &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Codec != codec.JSON || de.Kind != codec.ErrSyntax {
+		t.Errorf("unexpected codec/kind: %s/%s", de.Codec, de.Kind)
+	}
+	if de.Line != 3 || de.Column != 9 {
+		t.Errorf("expected line 3 column 9, got line %d column %d", de.Line, de.Column)
+	}
+	if !errors.Is(err, codec.ErrSyntax) {
+		t.Error("expected errors.Is(err, codec.ErrSyntax)")
+	}
+
+	var syntaxErr *json.SyntaxError
+	if !errors.As(err, &syntaxErr) {
+		t.Error("expected the library error to remain reachable")
+	}
+}
+
+func TestUnmarshal_TypeMismatch(t *testing.T) {
+	var v nested
+	err := New[nested]().Unmarshal([]byte(`{"user":{"name":"John","age":"thirty"}}`), &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Kind != codec.ErrTypeMismatch {
+		t.Errorf("expected type mismatch, got %s", de.Kind)
+	}
+	if de.Path != "user.age" {
+		t.Errorf("expected path user.age, got %q", de.Path)
+	}
+	if de.Offset <= 0 || de.Line != 1 {
+		t.Errorf("expected a position, got offset %d line %d", de.Offset, de.Line)
+	}
+}
+
+func TestUnmarshal_Truncated(t *testing.T) {
+	inputs := []string{``, `{"name":"Jo`}
+	for _, opts := range [][]codec.Option{nil, {WithUseNumber()}} {
+		for _, input := range inputs {
+			var v TestStruct
+			err := New[TestStruct](opts...).Unmarshal([]byte(input), &v)
+			if !errors.Is(err, codec.ErrTruncated) {
+				t.Errorf("%q: expected truncated error, got %v", input, err)
+			}
+		}
+	}
+}
+
+func TestUnmarshal_UnknownField(t *testing.T) {
+	var v TestStruct
+	err := New[TestStruct](WithDisallowUnknownFields()).Unmarshal([]byte(`{"name":"John","phone":"555"}`), &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Kind != codec.ErrUnknownField || de.Path != "phone" {
+		t.Errorf("expected unknown field phone, got %s at %q", de.Kind, de.Path)
+	}
+}
+
+func TestUnmarshal_TrailingDataError(t *testing.T) {
+	var v TestStruct
+	err := New[TestStruct](WithUseNumber()).Unmarshal([]byte("{}\n  {}"), &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Kind != codec.ErrSyntax || de.Offset != 5 || de.Line != 2 || de.Column != 3 {
+		t.Errorf("unexpected error: %+v", de)
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	dec := New[TestStruct]().NewDecoder(strings.NewReader(`{"name":"John"} {"name":`))
+
+	var v TestStruct
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	err := dec.Decode(&v)
+	if !errors.Is(err, codec.ErrTruncated) {
+		t.Fatalf("expected truncated error, got %v", err)
+	}
+
+	// A clean end of stream is not a decode error
+	dec = New[TestStruct]().NewDecoder(strings.NewReader(`{"name":"John"}`))
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if err := dec.Decode(&v); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
+
+func TestDecode_ReadErrorNotWrapped(t *testing.T) {
+	readErr := errors.New("connection reset")
+	var v TestStruct
+	err := New[TestStruct]().Decode(io.MultiReader(strings.NewReader(`{"name"`), &failingReader{err: readErr}), &v)
+	if err != readErr {
+		t.Fatalf("expected read error, got %v", err)
+	}
+}
+
+type failingReader struct {
+	err error
+}
+
+func (r *failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,68 @@
+//go:build codec_json
+
+package json
+
+import codec "github.com/jeremyhahn/go-codec"
+
+// checkLimits enforces the codec's limits on data, a complete JSON value
+func (c *Codec[T]) checkLimits(data []byte) error {
+	limits := c.cfg.Limits
+	if err := limits.CheckBytes(int64(len(data))); err != nil {
+		return limitError(err, data, limits.MaxBytes)
+	}
+	if !limits.Structural() {
+		return nil
+	}
+	if offset, err := scanLimits(data, limits); err != nil {
+		return limitError(err, data, offset)
+	}
+	return nil
+}
+
+// scanLimits checks the nesting depth, container sizes and string lengths
+// of data against limits without decoding it. It returns the offset of the
+// first violation. Malformed input is left for the decoder to report.
+func scanLimits(data []byte, limits codec.Limits) (int64, error) {
+	// counts holds the number of separators seen in each open container
+	var counts []int64
+	for i := 0; i < len(data); i++ {
+		switch data[i] {
+		case '"':
+			start := i
+			for i++; i < len(data) && data[i] != '"'; i++ {
+				if data[i] == '\\' {
+					i++
+				}
+			}
+			if err := limits.CheckStringLength(int64(i - start - 1)); err != nil {
+				return int64(start), err
+			}
+		case '{', '[':
+			counts = append(counts, 0)
+			if err := limits.CheckDepth(len(counts)); err != nil {
+				return int64(i), err
+			}
+		case '}', ']':
+			if len(counts) > 0 {
+				counts = counts[:len(counts)-1]
+			}
+		case ',':
+			if len(counts) > 0 {
+				top := len(counts) - 1
+				counts[top]++
+				if err := limits.CheckElements(counts[top] + 1); err != nil {
+					return int64(i), err
+				}
+			}
+		}
+	}
+	return 0, nil
+}
+
+// limitError returns the DecodeError for err, a codec.LimitError detected
+// at offset in data
+func limitError(err error, data []byte, offset int64) error {
+	de := codec.DecodeError{Codec: codec.JSON, Kind: codec.ErrLimitExceeded, Offset: offset, Err: err}
+	de.Line, de.Column = codec.Position(data, offset)
+	return de
+}
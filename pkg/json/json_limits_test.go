@@ -0,0 +1,82 @@
+//go:build codec_json
+
+package json
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestLimits_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits codec.Limits
+		input  string
+		limit  string
+		offset int64
+	}{
+		{"bytes", codec.Limits{MaxBytes: 8}, `{"name":"John"}`, "MaxBytes", 8},
+		{"depth", codec.Limits{MaxDepth: 2}, `{"a":[{"b":1}]}`, "MaxDepth", 6},
+		{"elements", codec.Limits{MaxElements: 3}, `[1,2,3,4]`, "MaxElements", 6},
+		{"object entries", codec.Limits{MaxElements: 1}, `{"a":1,"b":2}`, "MaxElements", 6},
+		{"string", codec.Limits{MaxStringLength: 4}, `{"name":"Johnny"}`, "MaxStringLength", 8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			err := New[any](codec.WithLimits(tt.limits)).Unmarshal([]byte(tt.input), &v)
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			var le codec.LimitError
+			if de.Kind != codec.ErrLimitExceeded || !errors.As(err, &le) || le.Limit != tt.limit {
+				t.Fatalf("expected %s exceeded, got %v", tt.limit, err)
+			}
+			if de.Offset != tt.offset {
+				t.Errorf("expected offset %d, got %d", tt.offset, de.Offset)
+			}
+		})
+	}
+}
+
+func TestLimits_WithinLimits(t *testing.T) {
+	c := New[TestStruct](codec.WithLimits(codec.Limits{
+		MaxBytes: 64, MaxDepth: 1, MaxElements: 3, MaxStringLength: 16,
+	}), WithUseNumber())
+
+	input := `{"name":"John \"J\" Doe","age":30,"email":"john@example.com"}`
+	var v TestStruct
+	if err := c.Unmarshal([]byte(input), &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if v.Name != `John "J" Doe` {
+		t.Errorf("unexpected name %q", v.Name)
+	}
+}
+
+func TestLimits_Decoder(t *testing.T) {
+	c := New[TestStruct](codec.WithLimits(codec.Limits{MaxBytes: 24}))
+	dec := c.NewDecoder(strings.NewReader(`{"name":"John"}` + "\n" + `{"name":"Jane"}` + "\n" + `{"name":"Johnathan Doe","email":"johnathan.doe@example.com"}`))
+
+	var v TestStruct
+	for i := 0; i < 2; i++ {
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("Decode %d failed: %v", i, err)
+		}
+	}
+	if err := dec.Decode(&v); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+
+	c = New[TestStruct](codec.WithLimits(codec.Limits{MaxDepth: 1}))
+	err := c.Decode(strings.NewReader(`{"name":{"first":"John"}}`), &v)
+	if !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+}
@@ -3,9 +3,7 @@
 package json
 
 import (
-	"encoding/json"
-
-	"github.com/jeremyhahn/go-codec/pkg/pool"
+	codec "github.com/jeremyhahn/go-codec"
 )
 
 // OptimizedCodec implements zero-allocation JSON encoding/decoding
@@ -13,67 +11,37 @@ type OptimizedCodec[T any] struct {
 	*Codec[T]
 }
 
-// NewPool creates a new optimized JSON codec with buffer pooling
-func NewPool[T any]() *OptimizedCodec[T] {
+// NewPool creates a new optimized JSON codec with pooled encoders,
+// configured with the given options
+func NewPool[T any](opts ...codec.Option) *OptimizedCodec[T] {
 	return &OptimizedCodec[T]{
-		Codec: New[T](),
+		Codec: New[T](opts...),
 	}
 }
 
-// MarshalTo marshals data into the provided buffer
+// MarshalTo marshals data into the provided buffer, reusing its capacity
+// and growing it only if the encoding does not fit
 func (c *OptimizedCodec[T]) MarshalTo(buf []byte, data T) ([]byte, error) {
-	// Use a bytes.Buffer from pool for marshaling
-	byteBuf := pool.GetBytesBuffer()
-	defer pool.PutBytesBuffer(byteBuf)
-
-	encoder := json.NewEncoder(byteBuf)
-	if err := encoder.Encode(data); err != nil {
-		return nil, err
+	if c.err != nil {
+		return nil, c.err
 	}
-
-	// Remove the trailing newline that json.Encoder adds
-	encoded := byteBuf.Bytes()
-	if len(encoded) > 0 && encoded[len(encoded)-1] == '\n' {
-		encoded = encoded[:len(encoded)-1]
-	}
-
-	// If provided buffer is large enough, copy into it
-	if cap(buf) >= len(encoded) {
-		buf = buf[:len(encoded)]
-		copy(buf, encoded)
-		return buf, nil
+	out, err := c.appendMarshal(buf[:0], data)
+	if err != nil {
+		return nil, err
 	}
-
-	// Otherwise allocate new buffer
-	result := make([]byte, len(encoded))
-	copy(result, encoded)
-	return result, nil
+	return out, nil
 }
 
 // AppendMarshal appends marshaled data to the provided buffer
 func (c *OptimizedCodec[T]) AppendMarshal(buf []byte, data T) ([]byte, error) {
-	// Use a bytes.Buffer from pool for marshaling
-	byteBuf := pool.GetBytesBuffer()
-	defer pool.PutBytesBuffer(byteBuf)
-
-	encoder := json.NewEncoder(byteBuf)
-	if err := encoder.Encode(data); err != nil {
-		return buf, err
+	if c.err != nil {
+		return buf, c.err
 	}
-
-	// Remove the trailing newline that json.Encoder adds
-	encoded := byteBuf.Bytes()
-	if len(encoded) > 0 && encoded[len(encoded)-1] == '\n' {
-		encoded = encoded[:len(encoded)-1]
-	}
-
-	// Append to existing buffer
-	return append(buf, encoded...), nil
+	return c.appendMarshal(buf, data)
 }
 
-// UnmarshalFrom unmarshals data with optional scratch buffer
+// UnmarshalFrom unmarshals data into v. encoding/json decodes straight from
+// data, so scratch is not used.
 func (c *OptimizedCodec[T]) UnmarshalFrom(data []byte, v *T, scratch []byte) error {
-	// For JSON, we don't need scratch buffer as json.Unmarshal doesn't benefit from it
-	// But we keep the signature for interface compatibility
-	return json.Unmarshal(data, v)
+	return c.Unmarshal(data, v)
 }
@@ -4,6 +4,8 @@ package json
 
 import (
 	"testing"
+
+	"github.com/jeremyhahn/go-codec"
 )
 
 // Optimized benchmarks using the new zero-allocation APIs
@@ -139,3 +141,53 @@ func BenchmarkComparison_AllocateEachTime(b *testing.B) {
 		}
 	}
 }
+
+// Encoding writes into the caller's buffer and allocates only when it is
+// too small
+
+func BenchmarkOptimizedCodec_MarshalToSmallBuffer(b *testing.B) {
+	codec := NewPool[BenchStruct]()
+	buf := make([]byte, 0, 16)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_, err := codec.MarshalTo(buf, benchData)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkOptimizedCodec_AppendMarshalBatch(b *testing.B) {
+	codec := NewPool[BenchStruct]()
+	var buf []byte
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf = buf[:0]
+		for j := 0; j < 100; j++ {
+			var err error
+			buf, err = codec.AppendMarshal(buf, benchData)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkOptimizedCodec_UnmarshalFromValue(b *testing.B) {
+	c := NewPool[codec.Value]()
+	data, _ := NewPool[BenchStruct]().Marshal(benchData)
+	var result codec.Value
+
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		if err := c.UnmarshalFrom(data, &result, nil); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
@@ -3,7 +3,10 @@
 package json
 
 import (
+	"bytes"
 	"testing"
+
+	"github.com/jeremyhahn/go-codec"
 )
 
 func TestOptimizedCodec_MarshalTo(t *testing.T) {
@@ -202,3 +205,59 @@ func TestOptimizedCodec_AppendMarshal_Error(t *testing.T) {
 		t.Errorf("expected original buffer on error, got %s", string(result))
 	}
 }
+
+func TestOptimizedCodec_WritesIntoBuffer(t *testing.T) {
+	for _, opts := range [][]codec.Option{nil, {WithIndent("", "  ")}, {WithEscapeHTML(false)}} {
+		c := NewPool[TestStruct](opts...)
+		data := TestStruct{Name: "<John Doe>", Age: 30, Email: "john@example.com"}
+		want, err := c.Marshal(data)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buf := make([]byte, 0, 256)
+		result, err := c.MarshalTo(buf, data)
+		if err != nil || !bytes.Equal(result, want) {
+			t.Fatalf("MarshalTo = %s, %v; want %s", result, err, want)
+		}
+		if &result[0] != &buf[:1][0] {
+			t.Error("MarshalTo did not write into the provided buffer")
+		}
+
+		buf = append(buf[:0], "prefix"...)
+		result, err = c.AppendMarshal(buf, data)
+		if err != nil || !bytes.Equal(result[6:], want) {
+			t.Fatalf("AppendMarshal = %s, %v", result, err)
+		}
+		if &result[0] != &buf[0] {
+			t.Error("AppendMarshal did not write into the provided buffer")
+		}
+	}
+}
+
+func TestOptimizedCodec_ReuseAfterError(t *testing.T) {
+	c := NewPool[any]()
+	if _, err := c.MarshalTo(nil, make(chan int)); err == nil {
+		t.Fatal("expected an error for a channel")
+	}
+	if result, err := c.MarshalTo(nil, map[string]int{"a": 1}); err != nil || string(result) != `{"a":1}` {
+		t.Errorf("MarshalTo after an error = %s, %v", result, err)
+	}
+}
+
+func TestOptimizedCodec_UnmarshalFrom_Value(t *testing.T) {
+	c := NewPool[codec.Value]()
+	data := []byte(`{"a": [1, "x"]}`)
+	scratch := make([]byte, 0, 64)
+
+	var v codec.Value
+	if err := c.UnmarshalFrom(data, &v, scratch); err != nil {
+		t.Fatal(err)
+	}
+	if got, _ := c.Marshal(v); string(got) != `{"a":[1,"x"]}` {
+		t.Errorf("UnmarshalFrom decoded %s", got)
+	}
+	if err := c.UnmarshalFrom([]byte(`{"a":`), &v, nil); err == nil {
+		t.Error("expected an error for truncated input")
+	}
+}
@@ -0,0 +1,76 @@
+package json
+
+import codec "github.com/jeremyhahn/go-codec"
+
+// config holds the settings applied by JSON options
+type config struct {
+	codec.Config
+
+	prefix                string
+	indent                string
+	disableHTMLEscape     bool
+	useNumber             bool
+	disallowUnknownFields bool
+	skipInvalid           func(error)
+}
+
+// encodesDefault reports whether encoding matches encoding/json.Marshal
+func (c *config) encodesDefault() bool {
+	return c.prefix == "" && c.indent == "" && !c.disableHTMLEscape
+}
+
+// decodesDefault reports whether decoding matches encoding/json.Unmarshal
+func (c *config) decodesDefault() bool {
+	return !c.useNumber && !c.disallowUnknownFields && !c.Strict
+}
+
+// prescans reports whether input is checked in full before it is decoded
+func (c *config) prescans() bool {
+	return c.Strict || c.Limits.Structural()
+}
+
+// WithIndent formats encoded output with each element on a new line
+// beginning with prefix followed by copies of indent per nesting level
+func WithIndent(prefix, indent string) codec.Option {
+	return codec.NewOption("json.WithIndent", func(c *config) {
+		c.prefix = prefix
+		c.indent = indent
+	})
+}
+
+// WithEscapeHTML controls whether <, > and & are escaped inside JSON
+// strings. HTML escaping is enabled by default.
+func WithEscapeHTML(escape bool) codec.Option {
+	return codec.NewOption("json.WithEscapeHTML", func(c *config) {
+		c.disableHTMLEscape = !escape
+	})
+}
+
+// WithUseNumber decodes numbers into an interface{} as json.Number
+// instead of float64
+func WithUseNumber() codec.Option {
+	return codec.NewOption("json.WithUseNumber", func(c *config) {
+		c.useNumber = true
+	})
+}
+
+// WithDisallowUnknownFields returns an error when decoding an object with a
+// key that does not match any non-ignored, exported field of the destination
+func WithDisallowUnknownFields() codec.Option {
+	return codec.NewOption("json.WithDisallowUnknownFields", func(c *config) {
+		c.disallowUnknownFields = true
+	})
+}
+
+// WithSkipInvalid makes record readers skip records that are malformed or
+// do not fit the destination type, passing each error, positioned in the
+// whole stream, to report instead of returning it. report may be nil.
+// Other operations ignore it.
+func WithSkipInvalid(report func(err error)) codec.Option {
+	return codec.NewOption("json.WithSkipInvalid", func(c *config) {
+		c.skipInvalid = report
+		if report == nil {
+			c.skipInvalid = func(error) {}
+		}
+	})
+}
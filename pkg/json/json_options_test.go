@@ -0,0 +1,152 @@
+//go:build codec_json
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	yamlcodec "github.com/jeremyhahn/go-codec/pkg/yaml"
+)
+
+func TestWithIndent(t *testing.T) {
+	c := New[TestStruct](WithIndent("", "  "))
+	result, err := c.Marshal(TestStruct{Name: "John", Age: 30})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	expected := "{\n  \"name\": \"John\",\n  \"age\": 30,\n  \"email\": \"\"\n}"
+	if string(result) != expected {
+		t.Errorf("expected %q, got %q", expected, result)
+	}
+
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, TestStruct{Name: "John", Age: 30}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if buf.String() != expected+"\n" {
+		t.Errorf("expected %q, got %q", expected+"\n", buf.String())
+	}
+}
+
+func TestWithEscapeHTML(t *testing.T) {
+	data := TestStruct{Name: "<b>&</b>"}
+
+	escaped, err := New[TestStruct]().Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if strings.Contains(string(escaped), "<b>") {
+		t.Errorf("expected HTML to be escaped by default, got %s", escaped)
+	}
+
+	raw, err := New[TestStruct](WithEscapeHTML(false)).Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(raw), "<b>&</b>") {
+		t.Errorf("expected HTML to be left unescaped, got %s", raw)
+	}
+}
+
+func TestWithUseNumber(t *testing.T) {
+	c := New[map[string]any](WithUseNumber())
+
+	var result map[string]any
+	if err := c.Unmarshal([]byte(`{"id": 9007199254740993}`), &result); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if n, ok := result["id"].(json.Number); !ok || n.String() != "9007199254740993" {
+		t.Errorf("expected json.Number 9007199254740993, got %#v", result["id"])
+	}
+
+	if err := c.Decode(strings.NewReader(`{"id": 1}`), &result); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if _, ok := result["id"].(json.Number); !ok {
+		t.Errorf("expected json.Number, got %T", result["id"])
+	}
+}
+
+func TestWithDisallowUnknownFields(t *testing.T) {
+	c := New[TestStruct](WithDisallowUnknownFields())
+
+	var result TestStruct
+	if err := c.Unmarshal([]byte(`{"name": "John", "nickname": "Johnny"}`), &result); err == nil {
+		t.Fatal("expected error for unknown field, got nil")
+	}
+	if err := c.Unmarshal([]byte(`{"name": "John"}`), &result); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+}
+
+func TestUnmarshal_TrailingData(t *testing.T) {
+	c := New[TestStruct](WithDisallowUnknownFields())
+
+	var result TestStruct
+	if err := c.Unmarshal([]byte(`{"name": "John"} {"name": "Jane"}`), &result); err == nil {
+		t.Fatal("expected error for trailing data, got nil")
+	}
+	if err := c.Unmarshal([]byte(`{"name": "John"}`+"\n\t "), &result); err != nil {
+		t.Fatalf("expected trailing whitespace to be accepted, got %v", err)
+	}
+	if err := c.Unmarshal([]byte(`{"name": `), &result); err == nil {
+		t.Fatal("expected error for truncated data, got nil")
+	}
+}
+
+func TestNew_OptionNotSupported(t *testing.T) {
+	c := New[TestStruct](yamlcodec.WithIndent(2))
+
+	var notSupported codec.ErrOptionNotSupported
+	if !errors.As(c.Err(), &notSupported) {
+		t.Fatalf("expected ErrOptionNotSupported, got %v", c.Err())
+	}
+
+	if _, err := c.Marshal(TestStruct{}); !errors.Is(err, c.Err()) {
+		t.Errorf("Marshal: expected option error, got %v", err)
+	}
+	var result TestStruct
+	if err := c.Unmarshal([]byte(`{}`), &result); !errors.Is(err, c.Err()) {
+		t.Errorf("Unmarshal: expected option error, got %v", err)
+	}
+	if err := c.Encode(&bytes.Buffer{}, TestStruct{}); !errors.Is(err, c.Err()) {
+		t.Errorf("Encode: expected option error, got %v", err)
+	}
+	if err := c.Decode(strings.NewReader(`{}`), &result); !errors.Is(err, c.Err()) {
+		t.Errorf("Decode: expected option error, got %v", err)
+	}
+	if err := c.NewEncoder(&bytes.Buffer{}).Encode(TestStruct{}); !errors.Is(err, c.Err()) {
+		t.Errorf("Encoder.Encode: expected option error, got %v", err)
+	}
+	if err := c.NewDecoder(strings.NewReader(`{}`)).Decode(&result); !errors.Is(err, c.Err()) {
+		t.Errorf("Decoder.Decode: expected option error, got %v", err)
+	}
+
+	p := NewPool[TestStruct](yamlcodec.WithIndent(2))
+	if _, err := p.MarshalTo(nil, TestStruct{}); err == nil {
+		t.Error("MarshalTo: expected option error, got nil")
+	}
+	if _, err := p.AppendMarshal(nil, TestStruct{}); err == nil {
+		t.Error("AppendMarshal: expected option error, got nil")
+	}
+	if err := p.UnmarshalFrom([]byte(`{}`), &result, nil); err == nil {
+		t.Error("UnmarshalFrom: expected option error, got nil")
+	}
+}
+
+func TestNewPool_WithIndent(t *testing.T) {
+	c := NewPool[TestStruct](WithIndent("", "\t"))
+	result, err := c.MarshalTo(nil, TestStruct{Name: "John"})
+	if err != nil {
+		t.Fatalf("MarshalTo failed: %v", err)
+	}
+	if !strings.Contains(string(result), "\n\t\"name\"") {
+		t.Errorf("expected indented output, got %q", result)
+	}
+}
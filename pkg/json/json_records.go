@@ -0,0 +1,310 @@
+//go:build codec_json
+
+package json
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"strings"
+	"unicode/utf8"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// RecordFormat selects how a sequence of JSON records is delimited
+type RecordFormat int
+
+const (
+	// NDJSON writes one compact JSON value per line, as newline-delimited
+	// JSON and JSON Lines do. Blank lines are skipped when reading.
+	NDJSON RecordFormat = iota + 1
+
+	// JSONSeq starts each JSON value with an ASCII record separator (0x1E)
+	// and ends it with a newline, as RFC 7464 JSON text sequences
+	// (application/json-seq) do. Values may span several lines.
+	JSONSeq
+)
+
+// recordSeparator starts every record of a JSON text sequence
+const recordSeparator = 0x1E
+
+// String returns the name of the format
+func (f RecordFormat) String() string {
+	switch f {
+	case NDJSON:
+		return "NDJSON"
+	case JSONSeq:
+		return "JSON text sequence"
+	default:
+		return fmt.Sprintf("RecordFormat(%d)", int(f))
+	}
+}
+
+// delimiter returns the byte that separates records in the format
+func (f RecordFormat) delimiter() byte {
+	if f == JSONSeq {
+		return recordSeparator
+	}
+	return '\n'
+}
+
+// errNoRecordSeparator is reported for a JSON text sequence with data
+// before its first record separator
+var errNoRecordSeparator = errors.New("json: text sequence does not start with a record separator")
+
+// errTruncatedRecord is reported for a top-level number, true, false or
+// null in a JSON text sequence that is not followed by whitespace, which
+// RFC 7464 section 2.4 requires to be treated as truncated
+var errTruncatedRecord = errors.New("json: top-level value is not followed by whitespace and may be truncated")
+
+// RecordWriter writes a sequence of JSON records to a stream. It is not
+// safe for concurrent use.
+type RecordWriter[T any] struct {
+	codec  *Codec[T]
+	w      io.Writer
+	format RecordFormat
+	buf    []byte
+	err    error
+}
+
+// NewRecordWriter returns a writer of JSON records in the given format to
+// w. NDJSON records are always compact, so WithIndent is rejected for it.
+func (c *Codec[T]) NewRecordWriter(w io.Writer, format RecordFormat) *RecordWriter[T] {
+	rw := &RecordWriter[T]{codec: c, w: w, format: format, err: c.err}
+	switch {
+	case rw.err != nil:
+	case format != NDJSON && format != JSONSeq:
+		rw.err = fmt.Errorf("json: unknown record format %s", format)
+	case format == NDJSON && (c.cfg.prefix != "" || c.cfg.indent != ""):
+		rw.err = errors.New("json: NDJSON records cannot be indented")
+	}
+	return rw
+}
+
+// Err returns the error, if any, caused by the codec options or format
+// passed to NewRecordWriter
+func (w *RecordWriter[T]) Err() error {
+	return w.err
+}
+
+// Write encodes data and writes it as one record in a single Write call
+func (w *RecordWriter[T]) Write(data T) error {
+	if w.err != nil {
+		return w.err
+	}
+	buf := w.buf[:0]
+	if w.format == JSONSeq {
+		buf = append(buf, recordSeparator)
+	}
+	buf, err := w.codec.appendMarshal(buf, data)
+	if err != nil {
+		return err
+	}
+	buf = append(buf, '\n')
+	w.buf = buf
+	_, err = w.w.Write(buf)
+	return err
+}
+
+// position is a location in a record stream
+type position struct {
+	offset int64
+	line   int
+	column int
+}
+
+// advance moves p past data
+func (p *position) advance(data []byte) {
+	p.offset += int64(len(data))
+	if i := bytes.LastIndexByte(data, '\n'); i >= 0 {
+		p.line += bytes.Count(data, []byte{'\n'})
+		p.column = utf8.RuneCount(data[i+1:]) + 1
+		return
+	}
+	p.column += utf8.RuneCount(data)
+}
+
+// RecordReader reads a sequence of JSON records from a stream. A record
+// that is malformed or does not fit T is reported as a codec.DecodeError
+// whose offset, line and column are positions in the whole stream, and
+// reading continues with the next record. It is not safe for concurrent
+// use.
+type RecordReader[T any] struct {
+	codec   *Codec[T]
+	br      *bufio.Reader
+	format  RecordFormat
+	buf     []byte
+	pos     position
+	line    int
+	started bool
+	err     error
+}
+
+// NewRecordReader returns a reader of JSON records in the given format from
+// r. Codec limits apply to each record, so MaxBytes bounds the memory used
+// for a single line or text however long the stream is.
+func (c *Codec[T]) NewRecordReader(r io.Reader, format RecordFormat) *RecordReader[T] {
+	rr := &RecordReader[T]{codec: c, br: bufio.NewReader(r), format: format, pos: position{line: 1, column: 1}, err: c.err}
+	if rr.err == nil && format != NDJSON && format != JSONSeq {
+		rr.err = fmt.Errorf("json: unknown record format %s", format)
+	}
+	return rr
+}
+
+// Err returns the error that ended the sequence, such as one caused by the
+// codec options or format passed to NewRecordReader, or nil if it ended
+// with the stream
+func (r *RecordReader[T]) Err() error {
+	if errors.Is(r.err, io.EOF) {
+		return nil
+	}
+	return r.err
+}
+
+// Line returns the line on which the last record read started
+func (r *RecordReader[T]) Line() int {
+	return r.line
+}
+
+// Read decodes the next record into data. It returns io.EOF at the end of
+// the stream. Invalid records are returned as a codec.DecodeError, or
+// passed to the handler set with WithSkipInvalid and skipped; either way
+// the next call continues with the following record. An error reading the
+// stream ends the sequence and is returned by every later call.
+func (r *RecordReader[T]) Read(data *T) error {
+	for {
+		if r.err != nil {
+			return r.err
+		}
+		start := r.pos
+		record, err := r.readRecord()
+		switch {
+		case err != nil:
+			if _, ok := err.(codec.DecodeError); !ok {
+				r.err = err
+				return err
+			}
+		case r.format == JSONSeq && !r.started:
+			// Only whitespace may precede the first record separator
+			r.started = true
+			if len(bytes.TrimSpace(record)) == 0 {
+				continue
+			}
+			err = codec.DecodeError{Codec: codec.JSON, Kind: codec.ErrSyntax, Err: errNoRecordSeparator}
+		case len(bytes.TrimSpace(record)) == 0:
+			continue
+		default:
+			r.line = start.line
+			if err = r.decode(record, data); err == nil {
+				return nil
+			}
+		}
+
+		err = recordError(err, start)
+		if r.codec.cfg.skipInvalid == nil {
+			return err
+		}
+		r.codec.cfg.skipInvalid(err)
+	}
+}
+
+// All returns an iterator over the remaining records. Invalid records are
+// yielded with their error, and iteration continues unless the loop
+// breaks; an error reading the stream is yielded last.
+//
+//	for event, err := range c.NewRecordReader(f, json.NDJSON).All() {
+//		if err != nil {
+//			log.Print(err)
+//			continue
+//		}
+//		handle(event)
+//	}
+func (r *RecordReader[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		for {
+			var v T
+			err := r.Read(&v)
+			if err == io.EOF || !yield(v, err) || r.err != nil {
+				return
+			}
+		}
+	}
+}
+
+// decode decodes a single record into data
+func (r *RecordReader[T]) decode(record []byte, data *T) error {
+	if r.format == JSONSeq {
+		first := bytes.TrimLeft(record, " \t\r\n")[0]
+		last := record[len(record)-1]
+		if strings.IndexByte("-0123456789tfn", first) >= 0 && strings.IndexByte(" \t\r\n", last) < 0 {
+			return codec.DecodeError{Codec: codec.JSON, Kind: codec.ErrTruncated, Offset: int64(len(record)), Err: errTruncatedRecord}
+		}
+	}
+	return r.codec.Unmarshal(record, data)
+}
+
+// readRecord returns the bytes up to the next delimiter, or to the end of
+// the stream, and moves past the delimiter. A record longer than MaxBytes
+// is skipped and reported as a codec.DecodeError. It returns io.EOF if the
+// stream ends before the record starts.
+func (r *RecordReader[T]) readRecord() ([]byte, error) {
+	delim := r.format.delimiter()
+	maxBytes := r.codec.cfg.Limits.MaxBytes
+	r.buf = r.buf[:0]
+	read, tooLong := false, false
+	for {
+		chunk, err := r.br.ReadSlice(delim)
+		r.pos.advance(chunk)
+		read = read || len(chunk) > 0
+		if !tooLong {
+			r.buf = append(r.buf, chunk...)
+			if n := len(bytes.TrimSuffix(r.buf, []byte{delim})); maxBytes > 0 && int64(n) > maxBytes {
+				tooLong, r.buf = true, r.buf[:0]
+			}
+		}
+		switch {
+		case err == bufio.ErrBufferFull:
+			continue
+		case err == io.EOF && !read:
+			return nil, io.EOF
+		case err != nil && err != io.EOF:
+			return nil, err
+		}
+		if tooLong {
+			return nil, codec.DecodeError{
+				Codec:  codec.JSON,
+				Kind:   codec.ErrLimitExceeded,
+				Offset: maxBytes,
+				Err:    codec.LimitError{Limit: "MaxBytes", Max: maxBytes},
+			}
+		}
+		return bytes.TrimSuffix(r.buf, []byte{delim}), nil
+	}
+}
+
+// recordError converts an error decoding the record at start into a
+// codec.DecodeError positioned in the whole stream
+func recordError(err error, start position) error {
+	de, ok := err.(codec.DecodeError)
+	if !ok {
+		// Errors from UnmarshalJSON methods do not describe the input
+		de = codec.DecodeError{Codec: codec.JSON, Kind: codec.ErrTypeMismatch, Offset: 0, Err: err}
+	}
+	if de.Offset >= 0 {
+		de.Offset += start.offset
+	}
+	switch {
+	case de.Line == 0:
+		de.Line, de.Column = start.line, start.column
+	case de.Line == 1:
+		de.Column += start.column - 1
+		de.Line = start.line
+	default:
+		de.Line += start.line - 1
+	}
+	return de
+}
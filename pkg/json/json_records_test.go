@@ -0,0 +1,224 @@
+//go:build codec_json
+
+package json
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+var recordValues = []TestStruct{
+	{Name: "Alice", Age: 30, Email: "alice@example.com"},
+	{Name: "Bob\nBuilder", Age: 25},
+	{Name: "Carol", Age: 41, Email: "carol@example.com"},
+}
+
+func TestRecords_RoundTrip(t *testing.T) {
+	tests := []struct {
+		format RecordFormat
+		opts   []codec.Option
+		prefix string
+	}{
+		{NDJSON, nil, `{"name":"Alice"`},
+		{JSONSeq, nil, "\x1e{\"name\":\"Alice\""},
+		{JSONSeq, []codec.Option{WithIndent("", "  ")}, "\x1e{\n  \"name\": \"Alice\""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.format.String(), func(t *testing.T) {
+			c := New[TestStruct](tt.opts...)
+			var buf bytes.Buffer
+			w := c.NewRecordWriter(&buf, tt.format)
+			if err := w.Err(); err != nil {
+				t.Fatalf("NewRecordWriter failed: %v", err)
+			}
+			for _, v := range recordValues {
+				if err := w.Write(v); err != nil {
+					t.Fatalf("Write failed: %v", err)
+				}
+			}
+			if !strings.HasPrefix(buf.String(), tt.prefix) {
+				t.Errorf("output starts with %q, want %q", buf.String(), tt.prefix)
+			}
+			if tt.format == NDJSON && strings.Count(buf.String(), "\n") != len(recordValues) {
+				t.Errorf("NDJSON output has %d lines, want %d", strings.Count(buf.String(), "\n"), len(recordValues))
+			}
+
+			var got []TestStruct
+			for v, err := range c.NewRecordReader(&buf, tt.format).All() {
+				if err != nil {
+					t.Fatalf("All yielded %v", err)
+				}
+				got = append(got, v)
+			}
+			if len(got) != len(recordValues) {
+				t.Fatalf("read %d records, want %d", len(got), len(recordValues))
+			}
+			for i := range got {
+				if got[i] != recordValues[i] {
+					t.Errorf("record %d = %+v, want %+v", i, got[i], recordValues[i])
+				}
+			}
+		})
+	}
+}
+
+func TestRecordReader_NDJSONErrors(t *testing.T) {
+	input := `{"name":"Alice","age":30}` + "\n" +
+		"\r\n" +
+		`{"name":"Bob","age":"old"}` + "\n" +
+		`{"name":` + "\n" +
+		`{"name":"Carol","age":41}` // no trailing newline
+
+	r := New[TestStruct]().NewRecordReader(strings.NewReader(input), NDJSON)
+	var got TestStruct
+	if err := r.Read(&got); err != nil || got.Name != "Alice" || r.Line() != 1 {
+		t.Fatalf("Read = %+v, %v on line %d", got, err, r.Line())
+	}
+
+	var de codec.DecodeError
+	err := r.Read(&got)
+	if !errors.As(err, &de) || de.Kind != codec.ErrTypeMismatch || de.Line != 3 || de.Path != "age" {
+		t.Errorf("Read of a mistyped record = %v, want a type mismatch on line 3", err)
+	}
+	if de.Offset < int64(strings.Index(input, `{"name":"Bob"`)) || de.Offset > int64(strings.Index(input, `{"name":`+"\n")) {
+		t.Errorf("Offset = %d, want the offset in the stream", de.Offset)
+	}
+
+	err = r.Read(&got)
+	if !errors.As(err, &de) || de.Kind != codec.ErrTruncated || de.Line != 4 {
+		t.Errorf("Read of a truncated record = %v, want truncated on line 4", err)
+	}
+
+	got = TestStruct{}
+	if err := r.Read(&got); err != nil || got.Name != "Carol" || r.Line() != 5 {
+		t.Errorf("Read after errors = %+v, %v on line %d", got, err, r.Line())
+	}
+	if err := r.Read(&got); err != io.EOF {
+		t.Errorf("Read at the end = %v, want io.EOF", err)
+	}
+	if err := r.Err(); err != nil {
+		t.Errorf("Err = %v", err)
+	}
+}
+
+func TestRecordReader_SkipInvalid(t *testing.T) {
+	input := "{\"name\":\"Alice\"}\nnot json\n{\"name\":\"Bob\"}\n[1,2]\n"
+	var lines []int
+	c := New[TestStruct](WithSkipInvalid(func(err error) {
+		var de codec.DecodeError
+		if errors.As(err, &de) {
+			lines = append(lines, de.Line)
+		}
+	}))
+
+	var names []string
+	for v, err := range c.NewRecordReader(strings.NewReader(input), NDJSON).All() {
+		if err != nil {
+			t.Fatalf("All yielded %v", err)
+		}
+		names = append(names, v.Name)
+	}
+	if strings.Join(names, ",") != "Alice,Bob" {
+		t.Errorf("records = %v, want Alice and Bob", names)
+	}
+	if len(lines) != 2 || lines[0] != 2 || lines[1] != 4 {
+		t.Errorf("skipped lines = %v, want [2 4]", lines)
+	}
+
+	var count int
+	for range New[TestStruct](WithSkipInvalid(nil)).NewRecordReader(strings.NewReader(input), NDJSON).All() {
+		count++
+	}
+	if count != 2 {
+		t.Errorf("read %d records with a nil handler, want 2", count)
+	}
+}
+
+func TestRecordReader_JSONSeq(t *testing.T) {
+	input := "\x1e{\"name\":\"Alice\"}\n" +
+		"\x1e\x1e\n" + // empty records are skipped
+		"\x1e{\n  \"name\": \"Bob\",\n  \"age\": x\n}\n" +
+		"\x1e42" + // a number without trailing whitespace may be truncated
+		"\x1e{\"name\":\"Carol\"}\n"
+
+	r := New[TestStruct]().NewRecordReader(strings.NewReader(input), JSONSeq)
+	var got TestStruct
+	if err := r.Read(&got); err != nil || got.Name != "Alice" {
+		t.Fatalf("Read = %+v, %v", got, err)
+	}
+	var de codec.DecodeError
+	if err := r.Read(&got); !errors.As(err, &de) || de.Kind != codec.ErrSyntax || de.Line != 5 || de.Column != 10 {
+		t.Errorf("Read of a malformed record = %v, want a syntax error at line 5, column 10", err)
+	}
+	if err := r.Read(&got); !errors.As(err, &de) || de.Kind != codec.ErrTruncated {
+		t.Errorf("Read of a bare number = %v, want truncated", err)
+	}
+	if err := r.Read(&got); err != nil || got.Name != "Carol" {
+		t.Errorf("Read = %+v, %v", got, err)
+	}
+
+	r = New[TestStruct]().NewRecordReader(strings.NewReader("{\"name\":\"Alice\"}\n\x1e{\"name\":\"Bob\"}\n"), JSONSeq)
+	if err := r.Read(&got); !errors.Is(err, errNoRecordSeparator) {
+		t.Errorf("Read without a leading separator = %v, want errNoRecordSeparator", err)
+	}
+	if err := r.Read(&got); err != nil || got.Name != "Bob" {
+		t.Errorf("Read after the preamble = %+v, %v", got, err)
+	}
+}
+
+func TestRecordReader_MaxBytes(t *testing.T) {
+	long := `{"name":"` + strings.Repeat("x", 10000) + `"}`
+	input := long + "\n" + `{"name":"Alice"}` + "\n"
+	c := New[TestStruct](codec.WithLimits(codec.Limits{MaxBytes: 100}))
+	r := c.NewRecordReader(strings.NewReader(input), NDJSON)
+
+	var got TestStruct
+	var de codec.DecodeError
+	if err := r.Read(&got); !errors.As(err, &de) || de.Kind != codec.ErrLimitExceeded || de.Line != 1 {
+		t.Errorf("Read of a long line = %v, want a limit error on line 1", err)
+	}
+	if err := r.Read(&got); err != nil || got.Name != "Alice" || r.Line() != 2 {
+		t.Errorf("Read after a long line = %+v, %v on line %d", got, err, r.Line())
+	}
+}
+
+func TestRecordReader_ReadError(t *testing.T) {
+	r := New[TestStruct]().NewRecordReader(io.MultiReader(strings.NewReader("{\"name\":\"Alice\"}\n"), errReader{}), NDJSON)
+	var results []error
+	for _, err := range r.All() {
+		results = append(results, err)
+	}
+	if len(results) != 2 || results[0] != nil || results[1] != io.ErrClosedPipe {
+		t.Errorf("All yielded %v, want a record and io.ErrClosedPipe", results)
+	}
+	if r.Err() != io.ErrClosedPipe {
+		t.Errorf("Err = %v, want io.ErrClosedPipe", r.Err())
+	}
+}
+
+func TestRecords_Errors(t *testing.T) {
+	if err := New[TestStruct](WithIndent("", "  ")).NewRecordWriter(io.Discard, NDJSON).Err(); err == nil {
+		t.Error("NewRecordWriter accepted indented NDJSON")
+	}
+	if err := New[TestStruct]().NewRecordWriter(io.Discard, 0).Write(TestStruct{}); err == nil {
+		t.Error("Write with an unknown format succeeded")
+	}
+	r := New[TestStruct]().NewRecordReader(strings.NewReader("{}\n"), 0)
+	for _, err := range r.All() {
+		if err == nil {
+			t.Error("All with an unknown format yielded a record")
+		}
+	}
+	if r.Err() == nil {
+		t.Error("Err is nil for an unknown format")
+	}
+}
+
+type errReader struct{}
+
+func (errReader) Read([]byte) (int, error) { return 0, io.ErrClosedPipe }
@@ -0,0 +1,76 @@
+//go:build codec_json
+
+package json
+
+import (
+	"encoding/json"
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// Encoder writes a sequence of newline-terminated JSON values to a stream
+type Encoder[T any] struct {
+	codec *Codec[T]
+	enc   *json.Encoder
+	err   error
+}
+
+// Decoder reads a sequence of JSON values from a stream
+type Decoder[T any] struct {
+	codec *Codec[T]
+	dec   *json.Decoder
+	limit *codec.LimitedReader
+	err   error
+}
+
+// NewEncoder returns an encoder session that writes successive JSON values to w
+func (c *Codec[T]) NewEncoder(w io.Writer) codec.Encoder[T] {
+	if c.err != nil {
+		return &Encoder[T]{err: c.err}
+	}
+	return &Encoder[T]{codec: c, enc: c.newEncoder(w)}
+}
+
+// NewDecoder returns a decoder session that reads successive JSON values from r
+func (c *Codec[T]) NewDecoder(r io.Reader) codec.Decoder[T] {
+	if c.err != nil {
+		return &Decoder[T]{err: c.err}
+	}
+	r = codec.NewLimitedReader(r, codec.JSON, c.cfg.Limits.MaxBytes)
+	limit, _ := r.(*codec.LimitedReader)
+	return &Decoder[T]{codec: c, dec: c.newDecoder(r), limit: limit}
+}
+
+// Encode writes the next JSON value to the stream
+func (e *Encoder[T]) Encode(data T) error {
+	if e.err != nil {
+		return e.err
+	}
+	if v, ok := any(data).(codec.Value); ok {
+		raw, err := e.codec.marshalValue(v)
+		if err != nil {
+			return err
+		}
+		return e.enc.Encode(raw)
+	}
+	return e.enc.Encode(data)
+}
+
+// Decode reads the next JSON value from the stream
+func (d *Decoder[T]) Decode(data *T) error {
+	if d.err != nil {
+		return d.err
+	}
+	if d.limit != nil {
+		d.limit.Reset()
+	}
+	if v, ok := any(data).(*codec.Value); ok {
+		var raw json.RawMessage
+		if err := d.codec.raw().decodeNext(d.dec, &raw); err != nil {
+			return err
+		}
+		return d.codec.convertValue(raw, v)
+	}
+	return d.codec.decodeNext(d.dec, data)
+}
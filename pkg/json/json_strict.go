@@ -0,0 +1,111 @@
+//go:build codec_json
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"strconv"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// frame is an open object or array while checking for duplicate keys
+type frame struct {
+	keys      map[string]bool // nil for arrays
+	path      string
+	key       string // the current key of an object
+	expectKey bool
+	index     int // the current index of an array
+}
+
+// checkDuplicateKeys returns a DecodeError of kind ErrDuplicateKey for the
+// first key that appears twice in the same object of data, a complete JSON
+// value; encoding/json silently keeps the last value. Malformed input is
+// left for the decoder to report.
+func checkDuplicateKeys(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var stack []*frame
+	for {
+		offset := decoder.InputOffset()
+		token, err := decoder.Token()
+		if err != nil {
+			return nil
+		}
+
+		var top *frame
+		if len(stack) > 0 {
+			top = stack[len(stack)-1]
+		}
+		if top != nil && top.expectKey && token != json.Delim('}') {
+			key, _ := token.(string)
+			if top.keys[key] {
+				return duplicateKeyError(data, offset, joinPath(top.path, key), key)
+			}
+			top.keys[key] = true
+			top.key, top.expectKey = key, false
+			continue
+		}
+
+		switch token {
+		case json.Delim('{'), json.Delim('['):
+			f := &frame{path: elementPath(top)}
+			if token == json.Delim('{') {
+				f.keys, f.expectKey = make(map[string]bool), true
+			}
+			stack = append(stack, f)
+			continue
+		case json.Delim('}'), json.Delim(']'):
+			stack = stack[:len(stack)-1]
+		}
+
+		// A value is complete
+		if len(stack) == 0 {
+			return nil
+		}
+		if top = stack[len(stack)-1]; top.keys != nil {
+			top.expectKey = true
+		} else {
+			top.index++
+		}
+	}
+}
+
+// elementPath returns the path of the current element of f, or "" at the
+// top level
+func elementPath(f *frame) string {
+	switch {
+	case f == nil:
+		return ""
+	case f.keys != nil:
+		return joinPath(f.path, f.key)
+	default:
+		return f.path + "[" + strconv.Itoa(f.index) + "]"
+	}
+}
+
+// joinPath appends key to the dotted path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// duplicateKeyError returns the error for key, repeated at path, whose
+// token follows offset in data
+func duplicateKeyError(data []byte, offset int64, path, key string) error {
+	offset += int64(len(data[offset:]) - len(bytes.TrimLeft(data[offset:], " \t\r\n,")))
+	de := codec.DecodeError{
+		Codec:  codec.JSON,
+		Kind:   codec.ErrDuplicateKey,
+		Offset: offset,
+		Path:   path,
+		Err:    fmt.Errorf("json: duplicate key %q", key),
+	}
+	de.Line, de.Column = codec.Position(data, offset)
+	return de
+}
@@ -0,0 +1,65 @@
+//go:build codec_json
+
+package json
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestStrict_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		kind  codec.ErrorKind
+		path  string
+		line  int
+	}{
+		{"unknown field", `{"name":"John","nmae":"Jane"}`, codec.ErrUnknownField, "nmae", 0},
+		{"duplicate key", `{"name":"John","age":30,"name":"Jane"}`, codec.ErrDuplicateKey, "name", 1},
+		{"nested duplicate", "{\"tags\":[{\"a\":1},\n {\"a\":1,\n \"a\":2}]}", codec.ErrDuplicateKey, "tags[1].a", 3},
+		{"lossy number", `{"age":30.5}`, codec.ErrTypeMismatch, "age", 1},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v TestStruct
+			err := New[TestStruct](codec.WithStrict()).Unmarshal([]byte(tt.input), &v)
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			if de.Kind != tt.kind || de.Path != tt.path || de.Line != tt.line {
+				t.Errorf("expected %v at %s (line %d), got %v", tt.kind, tt.path, tt.line, err)
+			}
+		})
+	}
+}
+
+func TestStrict_AllowsDuplicatesAcrossObjects(t *testing.T) {
+	c := New[[]map[string]any](codec.WithStrict())
+	var v []map[string]any
+	if err := c.Unmarshal([]byte(`[{"a":1,"b":{"a":2}},{"a":3}]`), &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(v) != 2 {
+		t.Errorf("unexpected value %v", v)
+	}
+}
+
+func TestStrict_Decoder(t *testing.T) {
+	c := New[TestStruct](codec.WithStrict())
+	dec := c.NewDecoder(strings.NewReader(`{"name":"John"} {"name":"Jane","name":"Doe"}`))
+
+	var v TestStruct
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if err := dec.Decode(&v); !errors.Is(err, codec.ErrDuplicateKey) {
+		t.Fatalf("expected duplicate key, got %v", err)
+	}
+}
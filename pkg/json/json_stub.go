@@ -4,6 +4,7 @@ package json
 
 import (
 	"io"
+	"iter"
 
 	codec "github.com/jeremyhahn/go-codec"
 )
@@ -14,10 +15,15 @@ var errNotSupported = codec.ErrCodecNotSupported{CodecType: codec.JSON}
 type Codec[T any] struct{}
 
 // New returns a JSON codec stub that will error on all operations.
-func New[T any]() *Codec[T] {
+func New[T any](opts ...codec.Option) *Codec[T] {
 	return &Codec[T]{}
 }
 
+// Err returns an error indicating JSON codec is not supported.
+func (c *Codec[T]) Err() error {
+	return errNotSupported
+}
+
 // Encode returns an error indicating JSON codec is not supported.
 func (c *Codec[T]) Encode(w io.Writer, data T) error {
 	return errNotSupported
@@ -38,15 +44,41 @@ func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
 	return errNotSupported
 }
 
+// Encoder is a stub for the JSON encoder session.
+type Encoder[T any] struct{}
+
+// Decoder is a stub for the JSON decoder session.
+type Decoder[T any] struct{}
+
+// NewEncoder returns a JSON encoder stub that will error on all operations.
+func (c *Codec[T]) NewEncoder(w io.Writer) codec.Encoder[T] {
+	return &Encoder[T]{}
+}
+
+// NewDecoder returns a JSON decoder stub that will error on all operations.
+func (c *Codec[T]) NewDecoder(r io.Reader) codec.Decoder[T] {
+	return &Decoder[T]{}
+}
+
+// Encode returns an error indicating JSON codec is not supported.
+func (e *Encoder[T]) Encode(data T) error {
+	return errNotSupported
+}
+
+// Decode returns an error indicating JSON codec is not supported.
+func (d *Decoder[T]) Decode(data *T) error {
+	return errNotSupported
+}
+
 // OptimizedCodec is a stub for the optimized JSON codec.
 type OptimizedCodec[T any] struct {
 	*Codec[T]
 }
 
 // NewPool returns an optimized JSON codec stub that will error on all operations.
-func NewPool[T any]() *OptimizedCodec[T] {
+func NewPool[T any](opts ...codec.Option) *OptimizedCodec[T] {
 	return &OptimizedCodec[T]{
-		Codec: New[T](),
+		Codec: New[T](opts...),
 	}
 }
 
@@ -64,3 +96,63 @@ func (c *OptimizedCodec[T]) AppendMarshal(buf []byte, data T) ([]byte, error) {
 func (c *OptimizedCodec[T]) UnmarshalFrom(data []byte, v *T, scratch []byte) error {
 	return errNotSupported
 }
+
+// RecordFormat selects how a sequence of JSON records is delimited.
+type RecordFormat int
+
+const (
+	// NDJSON writes one compact JSON value per line.
+	NDJSON RecordFormat = iota + 1
+
+	// JSONSeq writes RFC 7464 JSON text sequences.
+	JSONSeq
+)
+
+// RecordWriter is a stub for the JSON record writer.
+type RecordWriter[T any] struct{}
+
+// RecordReader is a stub for the JSON record reader.
+type RecordReader[T any] struct{}
+
+// NewRecordWriter returns a JSON record writer stub that will error on all operations.
+func (c *Codec[T]) NewRecordWriter(w io.Writer, format RecordFormat) *RecordWriter[T] {
+	return &RecordWriter[T]{}
+}
+
+// NewRecordReader returns a JSON record reader stub that will error on all operations.
+func (c *Codec[T]) NewRecordReader(r io.Reader, format RecordFormat) *RecordReader[T] {
+	return &RecordReader[T]{}
+}
+
+// Err returns an error indicating JSON codec is not supported.
+func (w *RecordWriter[T]) Err() error {
+	return errNotSupported
+}
+
+// Write returns an error indicating JSON codec is not supported.
+func (w *RecordWriter[T]) Write(data T) error {
+	return errNotSupported
+}
+
+// Err returns an error indicating JSON codec is not supported.
+func (r *RecordReader[T]) Err() error {
+	return errNotSupported
+}
+
+// Line returns 0, as the stub reads no records.
+func (r *RecordReader[T]) Line() int {
+	return 0
+}
+
+// Read returns an error indicating JSON codec is not supported.
+func (r *RecordReader[T]) Read(data *T) error {
+	return errNotSupported
+}
+
+// All returns an iterator that yields an error indicating JSON codec is not supported.
+func (r *RecordReader[T]) All() iter.Seq2[T, error] {
+	return func(yield func(T, error) bool) {
+		var zero T
+		yield(zero, errNotSupported)
+	}
+}
@@ -0,0 +1,289 @@
+//go:build codec_json
+
+package json
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// valueCodec converts between JSON and codec.Value
+type valueCodec struct{}
+
+// DecodeValue reads the next JSON value from r. Numbers with a fraction or
+// exponent decode as floats and others as integers; integers beyond 64 bits
+// decode as floats and are reported to lossy.
+func (valueCodec) DecodeValue(r io.Reader, cfg codec.Config, lossy func(codec.Loss)) (codec.Value, error) {
+	c := &Codec[json.RawMessage]{cfg: config{Config: cfg}}
+	var raw json.RawMessage
+	if err := c.Decode(r, &raw); err != nil {
+		return codec.Value{}, err
+	}
+	return decodeValue(raw, lossy)
+}
+
+// decodeValue converts raw, a single well-formed JSON value
+func decodeValue(raw []byte, lossy func(codec.Loss)) (codec.Value, error) {
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	return valueDecoder{decoder: decoder, data: raw, lossy: lossy}.decode("")
+}
+
+// raw returns a codec reading the raw JSON of values with the settings of c
+func (c *Codec[T]) raw() *Codec[json.RawMessage] {
+	return &Codec[json.RawMessage]{cfg: c.cfg}
+}
+
+// validDocument is decoded in place of a codec.Value to check the document
+// with the settings of the codec. It keeps nothing, so unlike
+// json.RawMessage the input is not copied.
+type validDocument struct{}
+
+func (*validDocument) UnmarshalJSON([]byte) error {
+	return nil
+}
+
+// unmarshalValue decodes data into v for a Codec[codec.Value], converting
+// data in place once it is checked
+func (c *Codec[T]) unmarshalValue(data []byte, v *codec.Value) error {
+	var valid validDocument
+	if err := (&Codec[validDocument]{cfg: c.cfg}).Unmarshal(data, &valid); err != nil {
+		return err
+	}
+	return c.convertValue(data, v)
+}
+
+// convertValue decodes raw into v for a Codec[codec.Value], failing on
+// lossy conversions if the codec is strict
+func (c *Codec[T]) convertValue(raw []byte, v *codec.Value) error {
+	lossy, check := codec.CollectLosses(codec.JSON, c.cfg.Strict)
+	value, err := decodeValue(raw, lossy)
+	if err == nil {
+		err = check()
+	}
+	if err != nil {
+		return err
+	}
+	*v = value
+	return nil
+}
+
+// marshalValue returns the compact encoding of v for a Codec[codec.Value],
+// failing on lossy conversions if the codec is strict
+func (c *Codec[T]) marshalValue(v codec.Value) (json.RawMessage, error) {
+	lossy, check := codec.CollectLosses(codec.JSON, c.cfg.Strict)
+	e := valueEncoder{lossy: lossy}
+	e.encode(v, "")
+	if err := check(); err != nil {
+		return nil, err
+	}
+	return e.buf, nil
+}
+
+// valueDecoder builds a codec.Value from the tokens of a well-formed value
+type valueDecoder struct {
+	decoder *json.Decoder
+	data    []byte
+	lossy   func(codec.Loss)
+}
+
+func (d valueDecoder) decode(path string) (codec.Value, error) {
+	offset := d.decoder.InputOffset()
+	token, err := d.decoder.Token()
+	if err != nil {
+		return codec.Value{}, decodeError(err, d.data)
+	}
+	switch t := token.(type) {
+	case nil:
+		return codec.NullValue(), nil
+	case bool:
+		return codec.BoolValue(t), nil
+	case string:
+		return codec.StringValue(t), nil
+	case json.Number:
+		return d.number(t, offset, path)
+	case json.Delim:
+		if t == '[' {
+			var items []codec.Value
+			for i := 0; d.decoder.More(); i++ {
+				item, err := d.decode(path + "[" + strconv.Itoa(i) + "]")
+				if err != nil {
+					return codec.Value{}, err
+				}
+				items = append(items, item)
+			}
+			_, err := d.decoder.Token()
+			return codec.ArrayValue(items...), err
+		}
+		var members []codec.Member
+		index := make(map[string]int)
+		for d.decoder.More() {
+			token, err := d.decoder.Token()
+			if err != nil {
+				return codec.Value{}, decodeError(err, d.data)
+			}
+			key := token.(string)
+			value, err := d.decode(joinPath(path, key))
+			if err != nil {
+				return codec.Value{}, err
+			}
+			// A repeated key replaces the earlier value, as it does when
+			// decoding into a map
+			if i, ok := index[key]; ok {
+				members[i].Value = value
+				continue
+			}
+			index[key] = len(members)
+			members = append(members, codec.Member{Key: codec.StringValue(key), Value: value})
+		}
+		_, err := d.decoder.Token()
+		return codec.MapValue(members...), err
+	}
+	return codec.Value{}, nil
+}
+
+// number converts a JSON number token found at offset
+func (d valueDecoder) number(n json.Number, offset int64, path string) (codec.Value, error) {
+	s := string(n)
+	if !strings.ContainsAny(s, ".eE") {
+		if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+			return codec.IntValue(i), nil
+		}
+		if u, err := strconv.ParseUint(s, 10, 64); err == nil {
+			return codec.UintValue(u), nil
+		}
+	}
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		offset += int64(len(d.data[offset:]) - len(bytes.TrimLeft(d.data[offset:], " \t\r\n,:")))
+		de := codec.DecodeError{Codec: codec.JSON, Kind: codec.ErrTypeMismatch, Offset: offset, Path: path, Err: err}
+		de.Line, de.Column = codec.Position(d.data, offset)
+		return codec.Value{}, de
+	}
+	if !strings.ContainsAny(s, ".eE") {
+		d.lossy(codec.Loss{Path: path, Reason: "integer beyond 64 bits decoded as float"})
+	}
+	return codec.FloatValue(f), nil
+}
+
+// EncodeValue writes v to w as compact JSON followed by a newline, as
+// Encode does. Byte strings are written as base64 text, times as RFC 3339
+// text and keys that are not strings as their text; each is reported to
+// lossy, as are NaN and infinite floats, which are written as null, and
+// tags, which are dropped in favor of their content.
+func (valueCodec) EncodeValue(w io.Writer, v codec.Value, lossy func(codec.Loss)) error {
+	e := valueEncoder{lossy: lossy}
+	e.encode(v, "")
+	e.buf = append(e.buf, '\n')
+	_, err := w.Write(e.buf)
+	return err
+}
+
+// valueEncoder appends the JSON encoding of a codec.Value to buf
+type valueEncoder struct {
+	buf   []byte
+	lossy func(codec.Loss)
+}
+
+func (e *valueEncoder) encode(v codec.Value, path string) {
+	switch v.Kind() {
+	case codec.KindNull:
+		e.buf = append(e.buf, "null"...)
+	case codec.KindBool:
+		e.buf = strconv.AppendBool(e.buf, v.Bool())
+	case codec.KindInt:
+		e.buf = strconv.AppendInt(e.buf, v.Int(), 10)
+	case codec.KindUint:
+		e.buf = strconv.AppendUint(e.buf, v.Uint(), 10)
+	case codec.KindFloat:
+		f := v.Float()
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			e.lossy(codec.Loss{Path: path, Reason: strconv.FormatFloat(f, 'g', -1, 64) + " written as null"})
+			e.buf = append(e.buf, "null"...)
+			return
+		}
+		e.buf = appendFloat(e.buf, f)
+	case codec.KindString:
+		e.buf = appendString(e.buf, v.String())
+	case codec.KindBytes:
+		e.lossy(codec.Loss{Path: path, Reason: "byte string written as base64 text"})
+		e.buf = appendString(e.buf, v.String())
+	case codec.KindTime:
+		e.lossy(codec.Loss{Path: path, Reason: "time written as RFC 3339 text"})
+		e.buf = appendString(e.buf, v.Time().Format(time.RFC3339Nano))
+	case codec.KindArray:
+		e.buf = append(e.buf, '[')
+		for i, item := range v.Array() {
+			if i > 0 {
+				e.buf = append(e.buf, ',')
+			}
+			e.encode(item, path+"["+strconv.Itoa(i)+"]")
+		}
+		e.buf = append(e.buf, ']')
+	case codec.KindMap:
+		e.buf = append(e.buf, '{')
+		for i, m := range v.Members() {
+			if i > 0 {
+				e.buf = append(e.buf, ',')
+			}
+			key := m.Key.String()
+			if m.Key.Kind() != codec.KindString {
+				e.lossy(codec.Loss{Path: joinPath(path, key), Reason: m.Key.Kind().String() + " key written as text"})
+			}
+			e.buf = appendString(e.buf, key)
+			e.buf = append(e.buf, ':')
+			e.encode(m.Value, joinPath(path, key))
+		}
+		e.buf = append(e.buf, '}')
+	case codec.KindTagged:
+		e.lossy(codec.Loss{Path: path, Reason: "tag " + v.Tag().String() + " dropped"})
+		e.encode(v.Content(), path)
+	}
+}
+
+// appendFloat appends f in the format encoding/json uses, keeping a
+// fraction on integral values so that they decode as floats again
+func appendFloat(buf []byte, f float64) []byte {
+	format := byte('f')
+	if abs := math.Abs(f); abs != 0 && (abs < 1e-6 || abs >= 1e21) {
+		format = 'e'
+	}
+	start := len(buf)
+	buf = strconv.AppendFloat(buf, f, format, -1, 64)
+	if !bytes.ContainsAny(buf[start:], ".e") {
+		buf = append(buf, ".0"...)
+	}
+	return buf
+}
+
+// appendString appends s as a JSON string. Invalid UTF-8 is replaced with
+// U+FFFD, as encoding/json does.
+func appendString(buf []byte, s string) []byte {
+	const hex = "0123456789abcdef"
+	buf = append(buf, '"')
+	for _, r := range s {
+		switch {
+		case r == '"' || r == '\\':
+			buf = append(buf, '\\', byte(r))
+		case r == '\n':
+			buf = append(buf, '\\', 'n')
+		case r == '\r':
+			buf = append(buf, '\\', 'r')
+		case r == '\t':
+			buf = append(buf, '\\', 't')
+		case r < 0x20:
+			buf = append(buf, '\\', 'u', '0', '0', hex[r>>4], hex[r&0xf])
+		default:
+			buf = utf8.AppendRune(buf, r)
+		}
+	}
+	return append(buf, '"')
+}
@@ -0,0 +1,126 @@
+//go:build codec_json
+
+package json
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestValue_RoundTrip(t *testing.T) {
+	input := `{"b":1,"a":1.0,"big":18446744073709551615,"neg":-9223372036854775808,"s":"x\u0001\"","n":null,"list":[true,{}]}` + "\n"
+	v, err := codec.DecodeValue(codec.JSON, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("DecodeValue failed: %v", err)
+	}
+
+	members := v.Members()
+	if members[0].Key.String() != "b" || members[1].Key.String() != "a" {
+		t.Errorf("Expected key order b, a; got %s", v)
+	}
+	if members[0].Value.Kind() != codec.KindInt || members[1].Value.Kind() != codec.KindFloat {
+		t.Errorf("Expected int and float, got %s and %s", members[0].Value.Kind(), members[1].Value.Kind())
+	}
+	if members[2].Value.Kind() != codec.KindUint || members[2].Value.Uint() != math.MaxUint64 {
+		t.Errorf("Expected max uint64, got %s %s", members[2].Value.Kind(), members[2].Value)
+	}
+
+	var out bytes.Buffer
+	if err := codec.EncodeValue(codec.JSON, &out, v); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	if out.String() != input {
+		t.Errorf("EncodeValue = %s, want %s", out.String(), input)
+	}
+}
+
+func TestValue_DuplicateKey(t *testing.T) {
+	v, err := codec.DecodeValue(codec.JSON, strings.NewReader(`{"a":1,"b":2,"a":3}`))
+	if err != nil {
+		t.Fatalf("DecodeValue failed: %v", err)
+	}
+	if got := v.String(); got != "{a:3 b:2}" {
+		t.Errorf("DecodeValue = %s, want {a:3 b:2}", got)
+	}
+
+	_, err = codec.DecodeValue(codec.JSON, strings.NewReader(`{"a":1,"a":3}`), codec.WithStrict())
+	if !errors.Is(err, codec.ErrDuplicateKey) {
+		t.Errorf("Expected ErrDuplicateKey, got %v", err)
+	}
+}
+
+func TestValue_EncodeLosses(t *testing.T) {
+	v := codec.MapValue(
+		codec.Member{Key: codec.StringValue("blob"), Value: codec.BytesValue([]byte("hi"))},
+		codec.Member{Key: codec.IntValue(1), Value: codec.FloatValue(math.NaN())},
+	)
+
+	var out bytes.Buffer
+	err := codec.EncodeValue(codec.JSON, &out, v)
+	var lossErr *codec.LossError
+	if !errors.As(err, &lossErr) {
+		t.Fatalf("Expected *LossError, got %v", err)
+	}
+	if len(lossErr.Losses) != 3 {
+		t.Errorf("Expected 3 losses, got %v", lossErr.Losses)
+	}
+
+	if err := codec.EncodeValue(codec.JSON, &out, v, codec.WithAllowLossy(nil)); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	if want := `{"blob":"aGk=","1":null}` + "\n"; out.String() != want {
+		t.Errorf("EncodeValue = %s, want %s", out.String(), want)
+	}
+}
+
+func TestCodec_Value(t *testing.T) {
+	c := New[codec.Value](WithIndent("", "  "))
+	var v codec.Value
+	if err := c.Unmarshal([]byte(`{"z":1,"a":[1.5,"x"]}`), &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	data, err := c.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	want := "{\n  \"z\": 1,\n  \"a\": [\n    1.5,\n    \"x\"\n  ]\n}"
+	if string(data) != want {
+		t.Errorf("Marshal = %s, want %s", data, want)
+	}
+
+	if err := c.Unmarshal([]byte(`{} {}`), &v); err == nil {
+		t.Error("Expected an error for trailing data")
+	}
+
+	decoder := c.NewDecoder(strings.NewReader(`1 "two" [3]`))
+	for _, want := range []string{"1", "two", "[3]"} {
+		if err := decoder.Decode(&v); err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		if v.String() != want {
+			t.Errorf("Decode = %s, want %s", v, want)
+		}
+	}
+}
+
+func TestCodec_ValueStrict(t *testing.T) {
+	v := codec.BytesValue([]byte("hi"))
+	_, err := New[codec.Value](codec.WithStrict()).Marshal(v)
+	var lossErr *codec.LossError
+	if !errors.As(err, &lossErr) {
+		t.Errorf("Expected *LossError from a strict codec, got %v", err)
+	}
+
+	data, err := New[codec.Value]().Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != `"aGk="` {
+		t.Errorf("Marshal = %s", data)
+	}
+}
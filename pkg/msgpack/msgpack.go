@@ -3,42 +3,227 @@
 package msgpack
 
 import (
+	"bytes"
 	"io"
+	"sync"
 
 	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/codecgen"
 	"github.com/vmihailenco/msgpack/v5"
 )
 
 func init() {
 	codec.RegisterCodec(codec.MsgPack)
+	codec.RegisterValueCodec(codec.MsgPack, valueCodec{})
 }
 
 // Codec implements the codec.Codec interface for MessagePack serialization
-type Codec[T any] struct{}
+type Codec[T any] struct {
+	cfg config
+	gen codecgen.Cache[T]
+	err error
+}
+
+// New creates a new MessagePack codec configured with the given options. If
+// an option does not apply to MessagePack, every operation returns the
+// error, which is also reported by Err.
+func New[T any](opts ...codec.Option) *Codec[T] {
+	c := &Codec[T]{}
+	c.err = codec.ApplyOptions(codec.MsgPack, &c.cfg, opts)
+	return c
+}
 
-// New creates a new MessagePack codec
-func New[T any]() *Codec[T] {
-	return &Codec[T]{}
+// Err returns the error, if any, caused by the options passed to New
+func (c *Codec[T]) Err() error {
+	return c.err
 }
 
 // Encode serializes the given data to the writer using MessagePack
 func (c *Codec[T]) Encode(w io.Writer, data T) error {
-	encoder := msgpack.NewEncoder(w)
-	return encoder.Encode(data)
+	if c.err != nil {
+		return c.err
+	}
+	if v, ok := any(data).(codec.Value); ok {
+		raw, err := c.marshalValue(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(raw)
+		return err
+	}
+	return c.newEncoder(w).Encode(data)
 }
 
 // Decode deserializes MessagePack data from the reader into the provided type
 func (c *Codec[T]) Decode(r io.Reader, data *T) error {
-	decoder := msgpack.NewDecoder(r)
-	return decoder.Decode(data)
+	if c.err != nil {
+		return c.err
+	}
+	r = codec.NewLimitedReader(r, codec.MsgPack, c.cfg.Limits.MaxBytes)
+	if v, ok := any(data).(*codec.Value); ok {
+		return c.decodeValue(c.newDecoder(r), v)
+	}
+	return c.decodeNext(c.newDecoder(r), data)
 }
 
 // Marshal serializes the given data to MessagePack bytes
 func (c *Codec[T]) Marshal(data T) ([]byte, error) {
-	return msgpack.Marshal(data)
+	if c.err != nil {
+		return nil, c.err
+	}
+	b, err := c.appendMarshal(nil, data)
+	if err != nil {
+		return nil, err
+	}
+	return b, nil
 }
 
 // Unmarshal deserializes MessagePack bytes into the provided type
 func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
-	return msgpack.Unmarshal(data, v)
+	if c.err != nil {
+		return c.err
+	}
+	if value, ok := any(v).(*codec.Value); ok {
+		return c.unmarshalValue(data, value)
+	}
+	return c.unmarshal(data, v, nil)
+}
+
+// unmarshal decodes data into v using a pooled decoder, reporting the
+// offset of any decoding error. Strict decoding encodes v again into
+// scratch, which may be nil.
+func (c *Codec[T]) unmarshal(data []byte, v *T, scratch []byte) error {
+	if err := c.checkLimits(data); err != nil {
+		return err
+	}
+	if c.cfg.Strict {
+		if err := checkDuplicateKeys(data); err != nil {
+			return err
+		}
+	}
+	if c.cfg.decodesDefault() && c.parseGenerated(data, v) {
+		return nil
+	}
+
+	reader := readerPool.Get().(*bytes.Reader)
+	reader.Reset(data)
+	defer readerPool.Put(reader)
+	decoder := msgpack.GetDecoder()
+	defer msgpack.PutDecoder(decoder)
+	decoder.Reset(reader)
+	c.configureDecoder(decoder)
+
+	if err := decoder.Decode(v); err != nil {
+		return decodeError(truncated(err), int64(len(data)-reader.Len()))
+	}
+	if c.cfg.Strict {
+		return c.checkExact(data, *v, scratch)
+	}
+	return nil
+}
+
+// appendMarshal appends the encoding of data to buf, writing directly into
+// buf while it has room
+func (c *Codec[T]) appendMarshal(buf []byte, data T) ([]byte, error) {
+	if v, ok := any(data).(codec.Value); ok {
+		raw, err := c.marshalValue(v)
+		if err != nil {
+			return buf, err
+		}
+		return append(buf, raw...), nil
+	}
+	if c.cfg.encodesDefault() {
+		if b, ok := c.appendGenerated(buf, data); ok {
+			return b, nil
+		}
+	}
+	return c.appendEncoded(buf, data)
+}
+
+// sliceWriter appends everything written to it to buf. It implements
+// io.ByteWriter so that msgpack.Encoder writes to it without a wrapper.
+type sliceWriter struct {
+	buf []byte
+}
+
+func (w *sliceWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	return len(p), nil
+}
+
+func (w *sliceWriter) WriteByte(b byte) error {
+	w.buf = append(w.buf, b)
+	return nil
+}
+
+var (
+	writerPool = sync.Pool{New: func() any { return new(sliceWriter) }}
+	readerPool = sync.Pool{New: func() any { return new(bytes.Reader) }}
+)
+
+// appendEncoded appends the encoding of data to buf with a pooled encoder,
+// which writes each item straight into the slice
+func (c *Codec[T]) appendEncoded(buf []byte, data T) ([]byte, error) {
+	w := writerPool.Get().(*sliceWriter)
+	w.buf = buf
+	encoder := msgpack.GetEncoder()
+	encoder.Reset(w)
+	c.configureEncoder(encoder)
+	err := encoder.Encode(data)
+	msgpack.PutEncoder(encoder)
+	out := w.buf
+	w.buf = nil
+	writerPool.Put(w)
+	if err != nil {
+		return buf, err
+	}
+	return out, nil
+}
+
+// appendGenerated appends data encoded by code generated with codecgen,
+// reporting false if T has none or it left data to reflection
+func (c *Codec[T]) appendGenerated(buf []byte, data T) ([]byte, bool) {
+	f, ok := c.gen.Get(codec.MsgPack)
+	if !ok {
+		return nil, false
+	}
+	out, err := f.Append(buf, data)
+	return out, err == nil
+}
+
+// parseGenerated decodes data with code generated with codecgen, reporting
+// false if T has none or it left data to reflection
+func (c *Codec[T]) parseGenerated(data []byte, v *T) bool {
+	f, ok := c.gen.Get(codec.MsgPack)
+	return ok && f.Parse(data, v) == nil
+}
+
+// configureEncoder applies the codec's settings to encoder
+func (c *Codec[T]) configureEncoder(encoder *msgpack.Encoder) {
+	encoder.UseCompactInts(c.cfg.compactInts)
+	encoder.UseCompactFloats(c.cfg.compactFloats)
+	encoder.SetSortMapKeys(c.cfg.sortMapKeys)
+	encoder.SetOmitEmpty(c.cfg.omitEmpty)
+	encoder.UseArrayEncodedStructs(c.cfg.arrayStructs)
+	encoder.SetCustomStructTag(c.cfg.structTag)
+}
+
+// newEncoder returns a msgpack.Encoder writing to w with the codec's settings
+func (c *Codec[T]) newEncoder(w io.Writer) *msgpack.Encoder {
+	encoder := msgpack.NewEncoder(w)
+	c.configureEncoder(encoder)
+	return encoder
+}
+
+// newDecoder returns a msgpack.Decoder reading from r with the codec's settings
+func (c *Codec[T]) newDecoder(r io.Reader) *msgpack.Decoder {
+	decoder := msgpack.NewDecoder(r)
+	c.configureDecoder(decoder)
+	return decoder
+}
+
+// configureDecoder applies the codec's settings to decoder
+func (c *Codec[T]) configureDecoder(decoder *msgpack.Decoder) {
+	decoder.SetCustomStructTag(c.cfg.structTag)
+	decoder.DisallowUnknownFields(c.cfg.Strict)
 }
@@ -0,0 +1,73 @@
+//go:build codec_msgpack
+
+package msgpack
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// decodeError converts an error returned by vmihailenco/msgpack into a
+// codec.DecodeError. offset is the number of input bytes consumed when the
+// error occurred, or -1 if unknown. The library reports every failure as
+// a plain error, so they are classified by message; read errors and
+// misuse of the API such as decoding into a non-pointer are returned as is.
+func decodeError(err error, offset int64) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+
+	de := codec.DecodeError{Codec: codec.MsgPack, Offset: offset, Err: err}
+	switch msg := err.Error(); {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		de.Kind = codec.ErrTruncated
+	case strings.HasPrefix(msg, "msgpack: unknown field "):
+		de.Kind = codec.ErrUnknownField
+		de.Path = strings.TrimPrefix(msg, "msgpack: unknown field ")
+		if name, uerr := strconv.Unquote(de.Path); uerr == nil {
+			de.Path = name
+		}
+	case strings.HasPrefix(msg, "msgpack: invalid code="),
+		strings.HasPrefix(msg, "msgpack: unsupported map key"),
+		strings.Contains(msg, " len is ") && strings.Contains(msg, ", but msgpack has "):
+		de.Kind = codec.ErrTypeMismatch
+	case strings.HasPrefix(msg, "msgpack: Decode("):
+		return err
+	case strings.HasPrefix(msg, "msgpack: "):
+		de.Kind = codec.ErrSyntax
+	default:
+		return err
+	}
+	return de
+}
+
+// decodeNext decodes the next value from decoder. It returns io.EOF only
+// when the stream ends before the value starts; a stream that ends inside
+// the value is reported as truncated. When limits or strict decoding are
+// set the value is read whole and checked before it is decoded.
+func (c *Codec[T]) decodeNext(decoder *msgpack.Decoder, v *T) error {
+	if _, err := decoder.PeekCode(); err != nil {
+		return err
+	}
+	if !c.cfg.prescans() {
+		return decodeError(truncated(decoder.Decode(v)), -1)
+	}
+	raw, err := decoder.DecodeRaw()
+	if err != nil {
+		return decodeError(truncated(err), -1)
+	}
+	return c.unmarshal(raw, v, nil)
+}
+
+// truncated reports io.EOF inside a value as io.ErrUnexpectedEOF
+func truncated(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+//go:build codec_msgpack
+
+package msgpack
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestUnmarshal_TypeMismatch(t *testing.T) {
+	data, err := msgpack.Marshal(map[string]any{"name": "John", "age": "thirty"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var v TestStruct
+	err = New[TestStruct]().Unmarshal(data, &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Codec != codec.MsgPack || de.Kind != codec.ErrTypeMismatch {
+		t.Errorf("unexpected codec/kind: %s/%s", de.Codec, de.Kind)
+	}
+	if de.Offset <= 0 || de.Offset > int64(len(data)) {
+		t.Errorf("unexpected offset %d", de.Offset)
+	}
+	if de.Line != 0 {
+		t.Errorf("expected no line for a binary format, got %d", de.Line)
+	}
+}
+
+func TestUnmarshal_Truncated(t *testing.T) {
+	data, err := New[TestStruct]().Marshal(TestStruct{Name: "John", Age: 30})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	for _, input := range [][]byte{nil, data[:len(data)/2]} {
+		var v TestStruct
+		err := New[TestStruct]().Unmarshal(input, &v)
+		if !errors.Is(err, codec.ErrTruncated) {
+			t.Errorf("expected truncated error for %d bytes, got %v", len(input), err)
+		}
+	}
+}
+
+func TestDecodeError_UnknownField(t *testing.T) {
+	data, err := msgpack.Marshal(map[string]any{"name": "John", "phone": "555"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields(true)
+
+	var v TestStruct
+	err = New[TestStruct]().decodeNext(decoder, &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) || de.Kind != codec.ErrUnknownField || de.Path != "phone" {
+		t.Fatalf("expected unknown field phone, got %v", err)
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	c := New[TestStruct]()
+	data, err := c.Marshal(TestStruct{Name: "John"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	dec := c.NewDecoder(bytes.NewReader(append(data, data[:4]...)))
+	var v TestStruct
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if err := dec.Decode(&v); !errors.Is(err, codec.ErrTruncated) {
+		t.Fatalf("expected truncated error, got %v", err)
+	}
+
+	dec = c.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if err := dec.Decode(&v); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+	if err := c.Decode(bytes.NewReader(nil), &v); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
@@ -0,0 +1,163 @@
+//go:build codec_msgpack
+
+package msgpack
+
+import (
+	"encoding/binary"
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// checkLimits enforces the codec's limits on data, an encoded MessagePack
+// value. When any limit is set the headers are scanned even if only
+// MaxBytes is, because vmihailenco/msgpack allocates for the length a header
+// declares before reading the contents.
+func (c *Codec[T]) checkLimits(data []byte) error {
+	limits := c.cfg.Limits
+	if limits == (codec.Limits{}) {
+		return nil
+	}
+	if err := limits.CheckBytes(int64(len(data))); err != nil {
+		return limitError(err, limits.MaxBytes)
+	}
+	return scanLimits(data, limits)
+}
+
+// limitError reports a limit exceeded at offset
+func limitError(err error, offset int64) error {
+	return codec.DecodeError{Codec: codec.MsgPack, Kind: codec.ErrLimitExceeded, Offset: offset, Err: err}
+}
+
+// scanLimits walks the headers of the MessagePack value at the start of
+// data, checking nesting depth, container sizes and string lengths against
+// limits without decoding or allocating. It reports the first violation,
+// and a header whose declared length cannot fit in the rest of data as
+// truncated. Other malformed input is left for the decoder to report.
+func scanLimits(data []byte, limits codec.Limits) error {
+	// pending holds the number of items left in each open container
+	var pending []uint64
+	for offset := 0; offset < len(data); {
+		start := offset
+		code := data[offset]
+		offset++
+
+		// lenSize is the size of an explicit length field and extra the
+		// number of bytes between it and the payload (the ext type)
+		var size, items, entries uint64
+		var lenSize, extra int
+		isString, isContainer := false, false
+		switch {
+		case code <= 0x7f || code >= 0xe0 || code == 0xc0 || code == 0xc2 || code == 0xc3:
+		case code <= 0x8f:
+			isContainer, entries = true, uint64(code&0x0f)
+			items = 2 * entries
+		case code <= 0x9f:
+			isContainer, entries = true, uint64(code&0x0f)
+			items = entries
+		case code <= 0xbf:
+			isString, size = true, uint64(code&0x1f)
+		case code == 0xc4 || code == 0xd9:
+			isString, lenSize = true, 1
+		case code == 0xc5 || code == 0xda:
+			isString, lenSize = true, 2
+		case code == 0xc6 || code == 0xdb:
+			isString, lenSize = true, 4
+		case code == 0xc7:
+			isString, lenSize, extra = true, 1, 1
+		case code == 0xc8:
+			isString, lenSize, extra = true, 2, 1
+		case code == 0xc9:
+			isString, lenSize, extra = true, 4, 1
+		case code == 0xcc || code == 0xd0:
+			size = 1
+		case code == 0xcd || code == 0xd1:
+			size = 2
+		case code == 0xca || code == 0xce || code == 0xd2:
+			size = 4
+		case code == 0xcb || code == 0xcf || code == 0xd3:
+			size = 8
+		case code >= 0xd4 && code <= 0xd8:
+			// fixext: type byte followed by 1, 2, 4, 8 or 16 bytes
+			size = 1 + 1<<(code-0xd4)
+		case code == 0xdc || code == 0xde:
+			isContainer, lenSize = true, 2
+		case code == 0xdd || code == 0xdf:
+			isContainer, lenSize = true, 4
+		default:
+			// 0xc1 is never used
+			return nil
+		}
+
+		if lenSize > 0 {
+			if offset+lenSize+extra > len(data) {
+				return decodeError(io.ErrUnexpectedEOF, int64(start))
+			}
+			n := readUint(data[offset : offset+lenSize])
+			offset += lenSize + extra
+			if isString {
+				size = n
+			} else {
+				entries, items = n, n
+				if code == 0xde || code == 0xdf {
+					items = 2 * n
+				}
+			}
+		}
+
+		if isString {
+			if err := limits.CheckStringLength(int64(size)); err != nil {
+				return limitError(err, int64(start))
+			}
+		}
+		if isContainer {
+			if err := limits.CheckDepth(len(pending) + 1); err != nil {
+				return limitError(err, int64(start))
+			}
+			if err := limits.CheckElements(int64(entries)); err != nil {
+				return limitError(err, int64(start))
+			}
+			// Every item takes at least one byte
+			if items > uint64(len(data)-offset) {
+				return decodeError(io.ErrUnexpectedEOF, int64(start))
+			}
+			if items > 0 {
+				pending = append(pending, items)
+				continue
+			}
+		}
+		if size > uint64(len(data)-offset) {
+			if isString {
+				return decodeError(io.ErrUnexpectedEOF, int64(start))
+			}
+			return nil
+		}
+		offset += int(size)
+
+		// The value is complete; close every container it completes
+		for {
+			if len(pending) == 0 {
+				return nil
+			}
+			top := len(pending) - 1
+			pending[top]--
+			if pending[top] > 0 {
+				break
+			}
+			pending = pending[:top]
+		}
+	}
+	return nil
+}
+
+// readUint decodes a big-endian unsigned integer of 1, 2 or 4 bytes
+func readUint(b []byte) uint64 {
+	switch len(b) {
+	case 1:
+		return uint64(b[0])
+	case 2:
+		return uint64(binary.BigEndian.Uint16(b))
+	default:
+		return uint64(binary.BigEndian.Uint32(b))
+	}
+}
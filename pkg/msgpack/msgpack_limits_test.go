@@ -0,0 +1,136 @@
+//go:build codec_msgpack
+
+package msgpack
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestLimits_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits codec.Limits
+		value  any
+		limit  string
+	}{
+		{"bytes", codec.Limits{MaxBytes: 8}, map[string]any{"name": "John Doe"}, "MaxBytes"},
+		{"depth", codec.Limits{MaxDepth: 2}, map[string]any{"a": []any{[]any{1}}}, "MaxDepth"},
+		{"elements", codec.Limits{MaxElements: 3}, []int{1, 2, 3, 4}, "MaxElements"},
+		{"map entries", codec.Limits{MaxElements: 1}, map[string]int{"a": 1, "b": 2}, "MaxElements"},
+		{"string", codec.Limits{MaxStringLength: 4}, []string{"ok", "Johnny"}, "MaxStringLength"},
+		{"long string", codec.Limits{MaxStringLength: 40}, strings.Repeat("x", 300), "MaxStringLength"},
+		{"binary", codec.Limits{MaxStringLength: 4}, []byte("binary"), "MaxStringLength"},
+		{"large array", codec.Limits{MaxElements: 100}, make([]int, 70000), "MaxElements"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, err := msgpack.Marshal(tt.value)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+
+			var v any
+			err = New[any](codec.WithLimits(tt.limits)).Unmarshal(data, &v)
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			var le codec.LimitError
+			if de.Kind != codec.ErrLimitExceeded || !errors.As(err, &le) || le.Limit != tt.limit {
+				t.Fatalf("expected %s exceeded, got %v", tt.limit, err)
+			}
+		})
+	}
+}
+
+func TestLimits_OversizedHeader(t *testing.T) {
+	inputs := map[string][]byte{
+		"array32": {0xdd, 0xff, 0xff, 0xff, 0xff},
+		"map32":   {0xdf, 0xff, 0xff, 0xff, 0xff},
+		"str32":   {0xdb, 0xff, 0xff, 0xff, 0xff},
+		"bin32":   {0xc6, 0xff, 0xff, 0xff, 0xff},
+		"ext32":   {0xc9, 0xff, 0xff, 0xff, 0xff, 0x01},
+		"nested":  {0x91, 0xdc, 0xff, 0xff},
+	}
+	limits := []codec.Limits{
+		{MaxBytes: 1000},
+		{MaxDepth: 10},
+		{MaxElements: math.MaxInt},
+		{MaxStringLength: math.MaxInt},
+	}
+
+	for name, data := range inputs {
+		for _, l := range limits {
+			c := New[any](codec.WithLimits(l))
+			var v any
+			if err := c.Unmarshal(data, &v); !errors.Is(err, codec.ErrTruncated) {
+				t.Errorf("%s with %+v: Unmarshal error = %v, want ErrTruncated", name, l, err)
+			}
+			var value codec.Value
+			if err := New[codec.Value](codec.WithLimits(l)).Unmarshal(data, &value); !errors.Is(err, codec.ErrTruncated) {
+				t.Errorf("%s with %+v: Unmarshal into Value error = %v, want ErrTruncated", name, l, err)
+			}
+			if err := c.NewDecoder(bytes.NewReader(data)).Decode(&v); !errors.Is(err, codec.ErrTruncated) {
+				t.Errorf("%s with %+v: Decode error = %v, want ErrTruncated", name, l, err)
+			}
+		}
+	}
+}
+
+func TestLimits_WithinLimits(t *testing.T) {
+	limits := codec.Limits{MaxBytes: 256, MaxDepth: 3, MaxElements: 4, MaxStringLength: 16}
+	value := map[string]any{
+		"name":  "John",
+		"tags":  []any{1, 2.5, int64(-70000), true},
+		"inner": map[string]any{"data": []byte{1, 2}, "none": nil},
+	}
+	data, err := msgpack.Marshal(value)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var v map[string]any
+	if err := New[map[string]any](codec.WithLimits(limits)).Unmarshal(data, &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if v["name"] != "John" {
+		t.Errorf("unexpected value %v", v)
+	}
+}
+
+func TestLimits_Decoder(t *testing.T) {
+	c := New[TestStruct](codec.WithLimits(codec.Limits{MaxStringLength: 8}))
+	var buf bytes.Buffer
+	enc := c.NewEncoder(&buf)
+	for _, name := range []string{"John", "Jane", "Johnathan Doe"} {
+		if err := enc.Encode(TestStruct{Name: name}); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+	}
+
+	dec := c.NewDecoder(&buf)
+	var v TestStruct
+	for i := 0; i < 2; i++ {
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("Decode %d failed: %v", i, err)
+		}
+	}
+	if err := dec.Decode(&v); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+
+	data, _ := c.Marshal(TestStruct{Name: strings.Repeat("x", 200)})
+	c = New[TestStruct](codec.WithLimits(codec.Limits{MaxBytes: 64}))
+	if err := c.Decode(bytes.NewReader(data), &v); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+}
@@ -3,10 +3,7 @@
 package msgpack
 
 import (
-	"bytes"
-
-	"github.com/jeremyhahn/go-codec/pkg/pool"
-	"github.com/vmihailenco/msgpack/v5"
+	codec "github.com/jeremyhahn/go-codec"
 )
 
 // OptimizedCodec implements zero-allocation MessagePack encoding/decoding
@@ -14,58 +11,44 @@ type OptimizedCodec[T any] struct {
 	*Codec[T]
 }
 
-// NewPool creates a new optimized MessagePack codec with buffer pooling
-func NewPool[T any]() *OptimizedCodec[T] {
+// NewPool creates a new optimized MessagePack codec with pooled encoders,
+// configured with the given options
+func NewPool[T any](opts ...codec.Option) *OptimizedCodec[T] {
 	return &OptimizedCodec[T]{
-		Codec: New[T](),
+		Codec: New[T](opts...),
 	}
 }
 
-// MarshalTo marshals data into the provided buffer
+// MarshalTo marshals data into the provided buffer, reusing its capacity
+// and growing it only if the encoding does not fit
 func (c *OptimizedCodec[T]) MarshalTo(buf []byte, data T) ([]byte, error) {
-	// Use a bytes.Buffer from pool for marshaling
-	byteBuf := pool.GetBytesBuffer()
-	defer pool.PutBytesBuffer(byteBuf)
-
-	encoder := msgpack.NewEncoder(byteBuf)
-	if err := encoder.Encode(data); err != nil {
-		return nil, err
+	if c.err != nil {
+		return nil, c.err
 	}
-
-	encoded := byteBuf.Bytes()
-
-	// If provided buffer is large enough, copy into it
-	if cap(buf) >= len(encoded) {
-		buf = buf[:len(encoded)]
-		copy(buf, encoded)
-		return buf, nil
+	out, err := c.appendMarshal(buf[:0], data)
+	if err != nil {
+		return nil, err
 	}
-
-	// Otherwise allocate new buffer
-	result := make([]byte, len(encoded))
-	copy(result, encoded)
-	return result, nil
+	return out, nil
 }
 
 // AppendMarshal appends marshaled data to the provided buffer
 func (c *OptimizedCodec[T]) AppendMarshal(buf []byte, data T) ([]byte, error) {
-	// Use a bytes.Buffer from pool for marshaling
-	byteBuf := pool.GetBytesBuffer()
-	defer pool.PutBytesBuffer(byteBuf)
-
-	encoder := msgpack.NewEncoder(byteBuf)
-	if err := encoder.Encode(data); err != nil {
-		return buf, err
+	if c.err != nil {
+		return buf, c.err
 	}
-
-	// Append to existing buffer
-	return append(buf, byteBuf.Bytes()...), nil
+	return c.appendMarshal(buf, data)
 }
 
-// UnmarshalFrom unmarshals data with optional scratch buffer
+// UnmarshalFrom unmarshals data into v, decoding straight from data with a
+// pooled decoder. With strict decoding, the check for numbers that were
+// not stored exactly encodes v again into scratch.
 func (c *OptimizedCodec[T]) UnmarshalFrom(data []byte, v *T, scratch []byte) error {
-	// Use bytes.Reader from the data directly to avoid allocation
-	reader := bytes.NewReader(data)
-	decoder := msgpack.NewDecoder(reader)
-	return decoder.Decode(v)
+	if c.err != nil {
+		return c.err
+	}
+	if value, ok := any(v).(*codec.Value); ok {
+		return c.unmarshalValue(data, value)
+	}
+	return c.unmarshal(data, v, scratch)
 }
@@ -4,6 +4,8 @@ package msgpack
 
 import (
 	"testing"
+
+	"github.com/jeremyhahn/go-codec"
 )
 
 // Optimized benchmarks using the new zero-allocation APIs
@@ -139,3 +141,66 @@ func BenchmarkComparison_AllocateEachTime(b *testing.B) {
 		}
 	}
 }
+
+// Encoding writes into the caller's buffer and allocates only when it is
+// too small
+
+func BenchmarkOptimizedCodec_MarshalToSmallBuffer(b *testing.B) {
+	codec := NewPool[BenchStruct]()
+	buf := make([]byte, 0, 16)
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		_, err := codec.MarshalTo(buf, benchData)
+		if err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkOptimizedCodec_AppendMarshalBatch(b *testing.B) {
+	codec := NewPool[BenchStruct]()
+	var buf []byte
+
+	b.ResetTimer()
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf = buf[:0]
+		for j := 0; j < 100; j++ {
+			var err error
+			buf, err = codec.AppendMarshal(buf, benchData)
+			if err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+// Strict decoding encodes the value again into scratch instead of a new
+// slice
+
+func BenchmarkOptimizedCodec_UnmarshalFromStrict(b *testing.B) {
+	c := NewPool[BenchStruct](codec.WithStrict())
+	data, _ := c.Marshal(benchData)
+
+	for _, bc := range []struct {
+		name    string
+		scratch []byte
+	}{
+		{"NoScratch", nil},
+		{"Scratch", make([]byte, 0, 256)},
+	} {
+		b.Run(bc.name, func(b *testing.B) {
+			var result BenchStruct
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if err := c.UnmarshalFrom(data, &result, bc.scratch); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
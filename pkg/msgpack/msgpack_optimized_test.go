@@ -3,7 +3,12 @@
 package msgpack
 
 import (
+	"bytes"
+	"errors"
 	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	"github.com/vmihailenco/msgpack/v5"
 )
 
 func TestOptimizedCodec_MarshalTo(t *testing.T) {
@@ -202,3 +207,71 @@ func TestOptimizedCodec_AppendMarshal_Error(t *testing.T) {
 		t.Errorf("expected original buffer on error, got %s", string(result))
 	}
 }
+
+func TestOptimizedCodec_WritesIntoBuffer(t *testing.T) {
+	c := NewPool[TestStruct]()
+	data := TestStruct{Name: "John Doe", Age: 30, Email: "john@example.com"}
+	want, err := c.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buf := make([]byte, 0, 256)
+	result, err := c.MarshalTo(buf, data)
+	if err != nil || !bytes.Equal(result, want) {
+		t.Fatalf("MarshalTo = %x, %v", result, err)
+	}
+	if &result[0] != &buf[:1][0] {
+		t.Error("MarshalTo did not write into the provided buffer")
+	}
+
+	buf = append(buf[:0], "prefix"...)
+	result, err = c.AppendMarshal(buf, data)
+	if err != nil || !bytes.Equal(result[6:], want) {
+		t.Fatalf("AppendMarshal = %x, %v", result, err)
+	}
+	if &result[0] != &buf[0] {
+		t.Error("AppendMarshal did not write into the provided buffer")
+	}
+}
+
+func TestOptimizedCodec_UnmarshalFrom_Strict(t *testing.T) {
+	c := NewPool[portConfig](codec.WithStrict())
+	valid, _ := msgpack.Marshal(map[string]any{"name": "a", "port": 8080})
+	overflow, _ := msgpack.Marshal(map[string]any{"port": 70000})
+
+	for _, scratch := range [][]byte{nil, make([]byte, 0, 2), make([]byte, 0, 256)} {
+		var cfg portConfig
+		if err := c.UnmarshalFrom(valid, &cfg, scratch); err != nil || cfg.Port != 8080 {
+			t.Errorf("scratch of %d: UnmarshalFrom = %+v, %v", cap(scratch), cfg, err)
+		}
+		err := c.UnmarshalFrom(overflow, &cfg, scratch)
+		var de codec.DecodeError
+		if !errors.As(err, &de) || de.Kind != codec.ErrTypeMismatch || de.Path != "port" {
+			t.Errorf("scratch of %d: overflow error = %v", cap(scratch), err)
+		}
+	}
+
+	// The value is encoded again into scratch when it fits
+	scratch := make([]byte, 0, 256)
+	var cfg portConfig
+	if err := c.UnmarshalFrom(valid, &cfg, scratch); err != nil {
+		t.Fatal(err)
+	}
+	if again, _ := c.Marshal(cfg); !bytes.Equal(scratch[:len(again)], again) {
+		t.Error("strict decoding did not use scratch")
+	}
+}
+
+func TestOptimizedCodec_Value(t *testing.T) {
+	c := NewPool[codec.Value]()
+	v := codec.MapValue(codec.Member{Key: codec.StringValue("a"), Value: codec.IntValue(1)})
+	data, err := c.MarshalTo(make([]byte, 0, 64), v)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got codec.Value
+	if err := c.UnmarshalFrom(data, &got, nil); err != nil || !got.Equal(v) {
+		t.Errorf("UnmarshalFrom = %v, %v", got, err)
+	}
+}
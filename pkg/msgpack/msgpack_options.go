@@ -0,0 +1,79 @@
+package msgpack
+
+import codec "github.com/jeremyhahn/go-codec"
+
+// config holds the settings applied by MessagePack options
+type config struct {
+	codec.Config
+
+	compactInts   bool
+	compactFloats bool
+	sortMapKeys   bool
+	omitEmpty     bool
+	arrayStructs  bool
+	structTag     string
+}
+
+// encodesDefault reports whether encoding matches msgpack.Marshal
+func (c *config) encodesDefault() bool {
+	encoding := *c
+	encoding.Config = codec.Config{}
+	return encoding == config{}
+}
+
+// decodesDefault reports whether decoding matches msgpack.Unmarshal
+func (c *config) decodesDefault() bool {
+	return !c.Strict && c.structTag == ""
+}
+
+// prescans reports whether input is checked in full before it is decoded
+func (c *config) prescans() bool {
+	return c.Strict || c.Limits != (codec.Limits{})
+}
+
+// WithCompactInts encodes integers using the smallest representation that
+// holds their value
+func WithCompactInts(on bool) codec.Option {
+	return codec.NewOption("msgpack.WithCompactInts", func(c *config) {
+		c.compactInts = on
+	})
+}
+
+// WithCompactFloats encodes floats holding integral values (such as 2.0)
+// using the compact integer encoding
+func WithCompactFloats(on bool) codec.Option {
+	return codec.NewOption("msgpack.WithCompactFloats", func(c *config) {
+		c.compactFloats = on
+	})
+}
+
+// WithSortMapKeys encodes the keys of string-keyed maps in increasing order
+// for deterministic output
+func WithSortMapKeys(on bool) codec.Option {
+	return codec.NewOption("msgpack.WithSortMapKeys", func(c *config) {
+		c.sortMapKeys = on
+	})
+}
+
+// WithOmitEmpty omits empty struct fields as if they were tagged omitempty
+func WithOmitEmpty(on bool) codec.Option {
+	return codec.NewOption("msgpack.WithOmitEmpty", func(c *config) {
+		c.omitEmpty = on
+	})
+}
+
+// WithArrayEncodedStructs encodes structs as arrays of field values instead
+// of maps keyed by field name
+func WithArrayEncodedStructs(on bool) codec.Option {
+	return codec.NewOption("msgpack.WithArrayEncodedStructs", func(c *config) {
+		c.arrayStructs = on
+	})
+}
+
+// WithStructTag reads field names from tag (e.g. "json") when the msgpack
+// tag is absent
+func WithStructTag(tag string) codec.Option {
+	return codec.NewOption("msgpack.WithStructTag", func(c *config) {
+		c.structTag = tag
+	})
+}
@@ -0,0 +1,172 @@
+//go:build codec_msgpack
+
+package msgpack
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+)
+
+type jsonTagged struct {
+	Name string `json:"full_name"`
+	Age  int64  `json:"age"`
+}
+
+func TestWithCompactInts(t *testing.T) {
+	data := map[string]int64{"n": 1}
+
+	standard, err := New[map[string]int64]().Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	compact, err := New[map[string]int64](WithCompactInts(true)).Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if len(compact) >= len(standard) {
+		t.Errorf("expected compact encoding (%d bytes) to be smaller than standard (%d bytes)", len(compact), len(standard))
+	}
+}
+
+func TestWithCompactFloats(t *testing.T) {
+	data := map[string]float64{"f": 2.0}
+
+	standard, err := New[map[string]float64]().Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	compact, err := New[map[string]float64](WithCompactFloats(true)).Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if len(compact) >= len(standard) {
+		t.Errorf("expected compact encoding (%d bytes) to be smaller than standard (%d bytes)", len(compact), len(standard))
+	}
+}
+
+func TestWithSortMapKeys(t *testing.T) {
+	c := New[map[string]any](WithSortMapKeys(true))
+	data := map[string]any{"c": 3, "a": 1, "b": 2, "d": 4, "e": 5}
+
+	first, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		again, err := c.Marshal(data)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if !bytes.Equal(first, again) {
+			t.Fatal("expected deterministic output with sorted map keys")
+		}
+	}
+}
+
+func TestWithOmitEmpty(t *testing.T) {
+	standard, err := New[TestStruct]().Marshal(TestStruct{Name: "John"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	omitted, err := New[TestStruct](WithOmitEmpty(true)).Marshal(TestStruct{Name: "John"})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if len(omitted) >= len(standard) {
+		t.Errorf("expected empty fields to be omitted")
+	}
+}
+
+func TestWithArrayEncodedStructs(t *testing.T) {
+	c := New[TestStruct](WithArrayEncodedStructs(true))
+	data := TestStruct{Name: "John", Age: 30, Email: "john@example.com"}
+
+	result, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if result[0]&0xf0 != 0x90 {
+		t.Errorf("expected fixarray header, got 0x%x", result[0])
+	}
+
+	var decoded TestStruct
+	if err := c.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded != data {
+		t.Errorf("expected %+v, got %+v", data, decoded)
+	}
+}
+
+func TestWithStructTag(t *testing.T) {
+	c := New[jsonTagged](WithStructTag("json"))
+	data := jsonTagged{Name: "John", Age: 30}
+
+	result, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !bytes.Contains(result, []byte("full_name")) {
+		t.Errorf("expected json tag name in output, got %q", result)
+	}
+
+	var decoded jsonTagged
+	if err := c.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded != data {
+		t.Errorf("expected %+v, got %+v", data, decoded)
+	}
+
+	var streamed jsonTagged
+	if err := c.Decode(bytes.NewReader(result), &streamed); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if streamed != data {
+		t.Errorf("expected %+v, got %+v", data, streamed)
+	}
+}
+
+func TestNew_OptionNotSupported(t *testing.T) {
+	c := New[TestStruct](jsoncodec.WithUseNumber())
+
+	var notSupported codec.ErrOptionNotSupported
+	if !errors.As(c.Err(), &notSupported) {
+		t.Fatalf("expected ErrOptionNotSupported, got %v", c.Err())
+	}
+
+	var result TestStruct
+	if _, err := c.Marshal(TestStruct{}); err == nil {
+		t.Error("Marshal: expected option error, got nil")
+	}
+	if err := c.Unmarshal([]byte{0x80}, &result); err == nil {
+		t.Error("Unmarshal: expected option error, got nil")
+	}
+	if err := c.Encode(&bytes.Buffer{}, TestStruct{}); err == nil {
+		t.Error("Encode: expected option error, got nil")
+	}
+	if err := c.Decode(bytes.NewReader([]byte{0x80}), &result); err == nil {
+		t.Error("Decode: expected option error, got nil")
+	}
+	if err := c.NewEncoder(&bytes.Buffer{}).Encode(TestStruct{}); err == nil {
+		t.Error("Encoder.Encode: expected option error, got nil")
+	}
+	if err := c.NewDecoder(bytes.NewReader([]byte{0x80})).Decode(&result); err == nil {
+		t.Error("Decoder.Decode: expected option error, got nil")
+	}
+
+	p := NewPool[TestStruct](jsoncodec.WithUseNumber())
+	if _, err := p.MarshalTo(nil, TestStruct{}); err == nil {
+		t.Error("MarshalTo: expected option error, got nil")
+	}
+	if _, err := p.AppendMarshal(nil, TestStruct{}); err == nil {
+		t.Error("AppendMarshal: expected option error, got nil")
+	}
+	if err := p.UnmarshalFrom([]byte{0x80}, &result, nil); err == nil {
+		t.Error("UnmarshalFrom: expected option error, got nil")
+	}
+}
@@ -0,0 +1,72 @@
+//go:build codec_msgpack
+
+package msgpack
+
+import (
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Encoder writes a sequence of MessagePack values to a stream
+type Encoder[T any] struct {
+	codec *Codec[T]
+	enc   *msgpack.Encoder
+	err   error
+}
+
+// Decoder reads a sequence of MessagePack values from a stream
+type Decoder[T any] struct {
+	codec *Codec[T]
+	dec   *msgpack.Decoder
+	limit *codec.LimitedReader
+	err   error
+}
+
+// NewEncoder returns an encoder session that writes successive MessagePack values to w
+func (c *Codec[T]) NewEncoder(w io.Writer) codec.Encoder[T] {
+	if c.err != nil {
+		return &Encoder[T]{err: c.err}
+	}
+	return &Encoder[T]{codec: c, enc: c.newEncoder(w)}
+}
+
+// NewDecoder returns a decoder session that reads successive MessagePack values from r
+func (c *Codec[T]) NewDecoder(r io.Reader) codec.Decoder[T] {
+	if c.err != nil {
+		return &Decoder[T]{err: c.err}
+	}
+	r = codec.NewLimitedReader(r, codec.MsgPack, c.cfg.Limits.MaxBytes)
+	limit, _ := r.(*codec.LimitedReader)
+	return &Decoder[T]{codec: c, dec: c.newDecoder(r), limit: limit}
+}
+
+// Encode writes the next MessagePack value to the stream
+func (e *Encoder[T]) Encode(data T) error {
+	if e.err != nil {
+		return e.err
+	}
+	if v, ok := any(data).(codec.Value); ok {
+		raw, err := e.codec.marshalValue(v)
+		if err != nil {
+			return err
+		}
+		return e.enc.Encode(msgpack.RawMessage(raw))
+	}
+	return e.enc.Encode(data)
+}
+
+// Decode reads the next MessagePack value from the stream
+func (d *Decoder[T]) Decode(data *T) error {
+	if d.err != nil {
+		return d.err
+	}
+	if d.limit != nil {
+		d.limit.Reset()
+	}
+	if v, ok := any(data).(*codec.Value); ok {
+		return d.codec.decodeValue(d.dec, v)
+	}
+	return d.codec.decodeNext(d.dec, data)
+}
@@ -0,0 +1,144 @@
+//go:build codec_msgpack
+
+package msgpack
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"strconv"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/internal/exact"
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+)
+
+// checkDuplicateKeys returns a DecodeError of kind ErrDuplicateKey for the
+// first key that appears twice in the same map of data, an encoded
+// MessagePack value; the library silently keeps the last value. Keys are
+// compared by their encoding. Malformed input is left for the decoder to
+// report.
+func checkDuplicateKeys(data []byte) error {
+	reader := bytes.NewReader(data)
+	s := keyScanner{decoder: msgpack.NewDecoder(reader), reader: reader, size: len(data)}
+	if err := s.scan(""); err != nil && err != errMalformed {
+		return err
+	}
+	return nil
+}
+
+// errMalformed stops a scan of input that the decoder will reject
+var errMalformed = errors.New("msgpack: malformed input")
+
+// keyScanner walks the maps and arrays of a MessagePack value
+type keyScanner struct {
+	decoder *msgpack.Decoder
+	reader  *bytes.Reader
+	size    int
+}
+
+// scan checks the value at the current position, found at path
+func (s *keyScanner) scan(path string) error {
+	code, err := s.decoder.PeekCode()
+	if err != nil {
+		return errMalformed
+	}
+
+	switch {
+	case msgpcode.IsFixedMap(code) || code == msgpcode.Map16 || code == msgpcode.Map32:
+		n, err := s.decoder.DecodeMapLen()
+		if err != nil {
+			return errMalformed
+		}
+		seen := make(map[string]bool, n)
+		for i := 0; i < n; i++ {
+			offset := int64(s.size - s.reader.Len())
+			key, err := s.decoder.DecodeRaw()
+			if err != nil {
+				return errMalformed
+			}
+			keyPath := joinPath(path, keyName(key))
+			if seen[string(key)] {
+				return codec.DecodeError{
+					Codec:  codec.MsgPack,
+					Kind:   codec.ErrDuplicateKey,
+					Offset: offset,
+					Path:   keyPath,
+					Err:    fmt.Errorf("msgpack: duplicate key %s", keyName(key)),
+				}
+			}
+			seen[string(key)] = true
+			if err := s.scan(keyPath); err != nil {
+				return err
+			}
+		}
+	case msgpcode.IsFixedArray(code) || code == msgpcode.Array16 || code == msgpcode.Array32:
+		n, err := s.decoder.DecodeArrayLen()
+		if err != nil {
+			return errMalformed
+		}
+		for i := 0; i < n; i++ {
+			if err := s.scan(path + "[" + strconv.Itoa(i) + "]"); err != nil {
+				return err
+			}
+		}
+	default:
+		if s.decoder.Skip() != nil {
+			return errMalformed
+		}
+	}
+	return nil
+}
+
+// keyName returns the text of key, an encoded map key, for use in paths
+func keyName(key msgpack.RawMessage) string {
+	var v any
+	if msgpack.Unmarshal(key, &v) != nil {
+		return fmt.Sprintf("%x", []byte(key))
+	}
+	return fmt.Sprint(v)
+}
+
+// joinPath appends key to the dotted path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
+
+// checkExact compares the numbers in data with those held by v, which data
+// was decoded into, encoding v again into scratch. The library lets
+// integers wrap around when decoded into smaller or unsigned types.
+func (c *Codec[T]) checkExact(data []byte, v T, scratch []byte) error {
+	var input, decoded any
+	if c.decodeAny(data, &input) != nil {
+		return nil
+	}
+	out, err := c.appendMarshal(scratch[:0], v)
+	if err != nil || c.decodeAny(out, &decoded) != nil {
+		// Types that cannot be encoded again are not checked
+		return nil
+	}
+	path, value, lossy := exact.Compare(input, decoded)
+	if !lossy {
+		return nil
+	}
+	return codec.DecodeError{
+		Codec:  codec.MsgPack,
+		Kind:   codec.ErrTypeMismatch,
+		Offset: -1,
+		Path:   path,
+		Err:    fmt.Errorf("msgpack: %v cannot be stored exactly in the destination", value),
+	}
+}
+
+// decodeAny decodes data into the library's generic value model
+func (c *Codec[T]) decodeAny(data []byte, v *any) error {
+	decoder := msgpack.GetDecoder()
+	defer msgpack.PutDecoder(decoder)
+	decoder.Reset(bytes.NewReader(data))
+	decoder.SetCustomStructTag(c.cfg.structTag)
+	return decoder.Decode(v)
+}
@@ -0,0 +1,98 @@
+//go:build codec_msgpack
+
+package msgpack
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+type portConfig struct {
+	Name  string  `msgpack:"name"`
+	Port  uint16  `msgpack:"port"`
+	Ports []int8  `msgpack:"ports"`
+	Ratio float32 `msgpack:"ratio"`
+}
+
+func TestStrict_Unmarshal(t *testing.T) {
+	// {"name": "a", "name": "b"}
+	duplicate := []byte{0x82, 0xa4, 'n', 'a', 'm', 'e', 0xa1, 'a', 0xa4, 'n', 'a', 'm', 'e', 0xa1, 'b'}
+
+	tests := []struct {
+		name  string
+		value any
+		kind  codec.ErrorKind
+		path  string
+	}{
+		{"unknown field", map[string]any{"name": "web", "prot": 80}, codec.ErrUnknownField, "prot"},
+		{"duplicate key", duplicate, codec.ErrDuplicateKey, "name"},
+		{"overflow", map[string]any{"port": 70000}, codec.ErrTypeMismatch, "port"},
+		{"negative unsigned", map[string]any{"port": -1}, codec.ErrTypeMismatch, "port"},
+		{"list item", map[string]any{"ports": []int{1, 300}}, codec.ErrTypeMismatch, "ports[1]"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			data, ok := tt.value.([]byte)
+			if !ok {
+				var err error
+				if data, err = msgpack.Marshal(tt.value); err != nil {
+					t.Fatalf("Marshal failed: %v", err)
+				}
+			}
+
+			var v portConfig
+			err := New[portConfig](codec.WithStrict()).Unmarshal(data, &v)
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			if de.Kind != tt.kind || de.Path != tt.path {
+				t.Errorf("expected %v at %s, got %v", tt.kind, tt.path, err)
+			}
+
+			// Without Strict the input is accepted
+			if err := New[portConfig]().Unmarshal(data, &v); err != nil {
+				t.Errorf("expected lenient decoding, got %v", err)
+			}
+		})
+	}
+}
+
+func TestStrict_Valid(t *testing.T) {
+	c := New[portConfig](codec.WithStrict())
+	data, err := msgpack.Marshal(map[string]any{"name": "web", "port": 8080, "ports": []int{1, -2}, "ratio": float32(0.1)})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var v portConfig
+	if err := c.Unmarshal(data, &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if v.Port != 8080 || v.Ports[1] != -2 {
+		t.Errorf("unexpected value %+v", v)
+	}
+}
+
+func TestStrict_Decoder(t *testing.T) {
+	c := New[portConfig](codec.WithStrict())
+	var buf bytes.Buffer
+	enc := msgpack.NewEncoder(&buf)
+	_ = enc.Encode(map[string]any{"port": 80})
+	_ = enc.Encode(map[string]any{"port": 70000})
+
+	dec := c.NewDecoder(&buf)
+	var v portConfig
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if err := dec.Decode(&v); !errors.Is(err, codec.ErrTypeMismatch) {
+		t.Fatalf("expected type mismatch, got %v", err)
+	}
+}
@@ -0,0 +1,299 @@
+//go:build codec_msgpack
+
+package msgpack
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"time"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"github.com/vmihailenco/msgpack/v5"
+	"github.com/vmihailenco/msgpack/v5/msgpcode"
+)
+
+// timeExtID is the extension type of MessagePack timestamps
+const timeExtID = -1
+
+// valueCodec converts between MessagePack and codec.Value
+type valueCodec struct{}
+
+// DecodeValue reads the next MessagePack value from r. Integers decode as
+// signed unless they exceed the range of an int64. Extension types other
+// than timestamps decode as tagged byte strings.
+func (valueCodec) DecodeValue(r io.Reader, cfg codec.Config, lossy func(codec.Loss)) (codec.Value, error) {
+	c := &Codec[codec.Value]{cfg: config{Config: cfg}}
+	decoder := msgpack.NewDecoder(codec.NewLimitedReader(r, codec.MsgPack, cfg.Limits.MaxBytes))
+	if _, err := decoder.PeekCode(); err != nil {
+		return codec.Value{}, err
+	}
+	raw, err := decoder.DecodeRaw()
+	if err != nil {
+		return codec.Value{}, decodeError(truncated(err), -1)
+	}
+	return c.convertValue(raw, lossy)
+}
+
+// convertValue checks raw, a single MessagePack value, against the codec's
+// limits and converts it
+func (c *Codec[T]) convertValue(raw []byte, lossy func(codec.Loss)) (codec.Value, error) {
+	if err := c.checkLimits(raw); err != nil {
+		return codec.Value{}, err
+	}
+	if c.cfg.Strict {
+		if err := checkDuplicateKeys(raw); err != nil {
+			return codec.Value{}, err
+		}
+	}
+
+	reader := bytes.NewReader(raw)
+	d := valueDecoder{decoder: msgpack.NewDecoder(reader), lossy: lossy}
+	v, err := d.decode("")
+	if err != nil {
+		return codec.Value{}, decodeError(truncated(err), int64(len(raw)-reader.Len()))
+	}
+	return v, nil
+}
+
+// unmarshalValue decodes data into v for a Codec[codec.Value], failing on
+// lossy conversions if the codec is strict
+func (c *Codec[T]) unmarshalValue(data []byte, v *codec.Value) error {
+	lossy, check := codec.CollectLosses(codec.MsgPack, c.cfg.Strict)
+	value, err := c.convertValue(data, lossy)
+	if err == nil {
+		err = check()
+	}
+	if err != nil {
+		return err
+	}
+	*v = value
+	return nil
+}
+
+// decodeValue reads the next value from decoder into v for a
+// Codec[codec.Value]. Like decodeNext, it returns io.EOF only when the
+// stream ends before the value starts.
+func (c *Codec[T]) decodeValue(decoder *msgpack.Decoder, v *codec.Value) error {
+	if _, err := decoder.PeekCode(); err != nil {
+		return err
+	}
+	raw, err := decoder.DecodeRaw()
+	if err != nil {
+		return decodeError(truncated(err), -1)
+	}
+	return c.unmarshalValue(raw, v)
+}
+
+// marshalValue returns the encoding of v for a Codec[codec.Value], failing
+// on lossy conversions if the codec is strict
+func (c *Codec[T]) marshalValue(v codec.Value) ([]byte, error) {
+	lossy, check := codec.CollectLosses(codec.MsgPack, c.cfg.Strict)
+	var buf bytes.Buffer
+	e := valueEncoder{encoder: msgpack.NewEncoder(&buf), lossy: lossy}
+	err := e.encode(v, "")
+	if err == nil {
+		err = check()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// valueDecoder builds a codec.Value from a single MessagePack value
+type valueDecoder struct {
+	decoder *msgpack.Decoder
+	lossy   func(codec.Loss)
+}
+
+func (d valueDecoder) decode(path string) (codec.Value, error) {
+	c, err := d.decoder.PeekCode()
+	if err != nil {
+		return codec.Value{}, err
+	}
+	switch {
+	case c == msgpcode.Nil:
+		return codec.NullValue(), d.decoder.DecodeNil()
+	case c == msgpcode.False || c == msgpcode.True:
+		b, err := d.decoder.DecodeBool()
+		return codec.BoolValue(b), err
+	case c == msgpcode.Uint64:
+		u, err := d.decoder.DecodeUint64()
+		if u > math.MaxInt64 {
+			return codec.UintValue(u), err
+		}
+		return codec.IntValue(int64(u)), err
+	case msgpcode.IsFixedNum(c) || c >= msgpcode.Uint8 && c <= msgpcode.Int64:
+		i, err := d.decoder.DecodeInt64()
+		return codec.IntValue(i), err
+	case c == msgpcode.Float || c == msgpcode.Double:
+		f, err := d.decoder.DecodeFloat64()
+		return codec.FloatValue(f), err
+	case msgpcode.IsString(c):
+		s, err := d.decoder.DecodeString()
+		return codec.StringValue(s), err
+	case msgpcode.IsBin(c):
+		b, err := d.decoder.DecodeBytes()
+		return codec.BytesValue(b), err
+	case msgpcode.IsExt(c):
+		return d.ext(path)
+	case msgpcode.IsFixedArray(c) || c == msgpcode.Array16 || c == msgpcode.Array32:
+		n, err := d.decoder.DecodeArrayLen()
+		if err != nil {
+			return codec.Value{}, err
+		}
+		items := make([]codec.Value, 0, n)
+		for i := 0; i < n; i++ {
+			item, err := d.decode(path + "[" + strconv.Itoa(i) + "]")
+			if err != nil {
+				return codec.Value{}, err
+			}
+			items = append(items, item)
+		}
+		return codec.ArrayValue(items...), nil
+	case msgpcode.IsFixedMap(c) || c == msgpcode.Map16 || c == msgpcode.Map32:
+		n, err := d.decoder.DecodeMapLen()
+		if err != nil {
+			return codec.Value{}, err
+		}
+		members := make([]codec.Member, 0, n)
+		for i := 0; i < n; i++ {
+			key, err := d.decode(path)
+			if err != nil {
+				return codec.Value{}, err
+			}
+			value, err := d.decode(joinPath(path, key.String()))
+			if err != nil {
+				return codec.Value{}, err
+			}
+			members = append(members, codec.Member{Key: key, Value: value})
+		}
+		return codec.MapValue(members...), nil
+	default:
+		return codec.Value{}, fmt.Errorf("msgpack: invalid code=%x decoding value", c)
+	}
+}
+
+// ext decodes an extension value
+func (d valueDecoder) ext(path string) (codec.Value, error) {
+	id, n, err := d.decoder.DecodeExtHeader()
+	if err != nil {
+		return codec.Value{}, err
+	}
+	payload := make([]byte, n)
+	if err := d.decoder.ReadFull(payload); err != nil {
+		return codec.Value{}, err
+	}
+	if id == timeExtID {
+		if t, ok := decodeTime(payload); ok {
+			return codec.TimeValue(t), nil
+		}
+	}
+	return codec.TaggedValue(codec.Tag{Codec: codec.MsgPack, Number: int64(id)}, codec.BytesValue(payload)), nil
+}
+
+// decodeTime decodes the payload of a timestamp extension
+func decodeTime(b []byte) (time.Time, bool) {
+	switch len(b) {
+	case 4:
+		return time.Unix(int64(binary.BigEndian.Uint32(b)), 0).UTC(), true
+	case 8:
+		n := binary.BigEndian.Uint64(b)
+		return time.Unix(int64(n&0x3ffffffff), int64(n>>34)).UTC(), true
+	case 12:
+		nsec := binary.BigEndian.Uint32(b)
+		return time.Unix(int64(binary.BigEndian.Uint64(b[4:])), int64(nsec)).UTC(), true
+	default:
+		return time.Time{}, false
+	}
+}
+
+// EncodeValue writes v to w as MessagePack, using the smallest encoding
+// of each integer. Times keep their instant but are read back in UTC, so
+// those in other time zones are reported to lossy, as are tags of other
+// codecs, which are dropped in favor of their content.
+func (valueCodec) EncodeValue(w io.Writer, v codec.Value, lossy func(codec.Loss)) error {
+	e := valueEncoder{encoder: msgpack.NewEncoder(w), lossy: lossy}
+	return e.encode(v, "")
+}
+
+// valueEncoder writes a codec.Value to a MessagePack encoder
+type valueEncoder struct {
+	encoder *msgpack.Encoder
+	lossy   func(codec.Loss)
+}
+
+func (e valueEncoder) encode(v codec.Value, path string) error {
+	switch v.Kind() {
+	case codec.KindBool:
+		return e.encoder.EncodeBool(v.Bool())
+	case codec.KindInt:
+		return e.encoder.EncodeInt(v.Int())
+	case codec.KindUint:
+		return e.encoder.EncodeUint(v.Uint())
+	case codec.KindFloat:
+		return e.encoder.EncodeFloat64(v.Float())
+	case codec.KindString:
+		return e.encoder.EncodeString(v.String())
+	case codec.KindBytes:
+		// The library encodes a nil slice as nil
+		b := v.Bytes()
+		if b == nil {
+			b = []byte{}
+		}
+		return e.encoder.EncodeBytes(b)
+	case codec.KindTime:
+		if v.Time().Location() != time.UTC {
+			e.lossy(codec.Loss{Path: path, Reason: "time zone dropped"})
+		}
+		return e.encoder.EncodeTime(v.Time())
+	case codec.KindArray:
+		if err := e.encoder.EncodeArrayLen(len(v.Array())); err != nil {
+			return err
+		}
+		for i, item := range v.Array() {
+			if err := e.encode(item, path+"["+strconv.Itoa(i)+"]"); err != nil {
+				return err
+			}
+		}
+		return nil
+	case codec.KindMap:
+		if err := e.encoder.EncodeMapLen(len(v.Members())); err != nil {
+			return err
+		}
+		for _, m := range v.Members() {
+			if err := e.encode(m.Key, path); err != nil {
+				return err
+			}
+			if err := e.encode(m.Value, joinPath(path, m.Key.String())); err != nil {
+				return err
+			}
+		}
+		return nil
+	case codec.KindTagged:
+		return e.ext(v, path)
+	default:
+		return e.encoder.EncodeNil()
+	}
+}
+
+// ext writes a tagged Value as an extension
+func (e valueEncoder) ext(v codec.Value, path string) error {
+	tag, content := v.Tag(), v.Content()
+	if tag.Codec != codec.MsgPack {
+		e.lossy(codec.Loss{Path: path, Reason: "tag " + tag.String() + " dropped"})
+		return e.encode(content, path)
+	}
+	if tag.Number < math.MinInt8 || tag.Number > math.MaxInt8 || content.Kind() != codec.KindBytes {
+		return fmt.Errorf("msgpack: invalid extension %s", v)
+	}
+	if err := e.encoder.EncodeExtHeader(int8(tag.Number), len(content.Bytes())); err != nil {
+		return err
+	}
+	_, err := e.encoder.Writer().Write(content.Bytes())
+	return err
+}
@@ -0,0 +1,106 @@
+//go:build codec_msgpack
+
+package msgpack
+
+import (
+	"bytes"
+	"errors"
+	"math"
+	"testing"
+	"time"
+
+	"github.com/jeremyhahn/go-codec"
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+func TestValue_RoundTrip(t *testing.T) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)
+	v := codec.MapValue(
+		codec.Member{Key: codec.StringValue("z"), Value: codec.IntValue(-1)},
+		codec.Member{Key: codec.IntValue(7), Value: codec.UintValue(math.MaxUint64)},
+		codec.Member{Key: codec.StringValue("f"), Value: codec.FloatValue(2)},
+		codec.Member{Key: codec.StringValue("b"), Value: codec.BytesValue(nil)},
+		codec.Member{Key: codec.StringValue("t"), Value: codec.TimeValue(when)},
+		codec.Member{Key: codec.StringValue("a"), Value: codec.ArrayValue(codec.NullValue(), codec.BoolValue(true))},
+	)
+
+	var buf bytes.Buffer
+	if err := codec.EncodeValue(codec.MsgPack, &buf, v); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	got, err := codec.DecodeValue(codec.MsgPack, &buf)
+	if err != nil {
+		t.Fatalf("DecodeValue failed: %v", err)
+	}
+	if got.String() != v.String() {
+		t.Errorf("Round trip = %s, want %s", got, v)
+	}
+	for i, m := range got.Members() {
+		if want := v.Members()[i]; m.Key.Kind() != want.Key.Kind() || m.Value.Kind() != want.Value.Kind() {
+			t.Errorf("Member %d is %s:%s, want %s:%s", i, m.Key.Kind(), m.Value.Kind(), want.Key.Kind(), want.Value.Kind())
+		}
+	}
+}
+
+func TestValue_Extension(t *testing.T) {
+	var buf bytes.Buffer
+	e := msgpack.NewEncoder(&buf)
+	_ = e.EncodeExtHeader(5, 2)
+	buf.Write([]byte{0xca, 0xfe})
+	input := bytes.Clone(buf.Bytes())
+
+	v, err := codec.DecodeValue(codec.MsgPack, bytes.NewReader(input))
+	if err != nil {
+		t.Fatalf("DecodeValue failed: %v", err)
+	}
+	if v.Kind() != codec.KindTagged || v.Tag().Number != 5 || !bytes.Equal(v.Content().Bytes(), []byte{0xca, 0xfe}) {
+		t.Fatalf("Expected extension 5, got %s", v)
+	}
+
+	buf.Reset()
+	if err := codec.EncodeValue(codec.MsgPack, &buf, v); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	if !bytes.Equal(buf.Bytes(), input) {
+		t.Errorf("EncodeValue = %x, want %x", buf.Bytes(), input)
+	}
+}
+
+func TestCodec_Value(t *testing.T) {
+	c := New[codec.Value](codec.WithStrict())
+	v := codec.MapValue(
+		codec.Member{Key: codec.StringValue("b"), Value: codec.IntValue(-1)},
+		codec.Member{Key: codec.StringValue("a"), Value: codec.TaggedValue(codec.Tag{Codec: codec.MsgPack, Number: 1}, codec.BytesValue([]byte{1}))},
+	)
+	data, err := c.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var got codec.Value
+	if err := c.Unmarshal(data, &got); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !got.Equal(v) {
+		t.Errorf("Round trip = %s, want %s", got, v)
+	}
+
+	lossy := codec.TaggedValue(codec.Tag{Codec: codec.CBOR, Number: 32}, codec.StringValue("x"))
+	_, err = c.Marshal(lossy)
+	var lossErr *codec.LossError
+	if !errors.As(err, &lossErr) {
+		t.Errorf("Expected *LossError from a strict codec, got %v", err)
+	}
+	if _, err := New[codec.Value]().Marshal(lossy); err != nil {
+		t.Errorf("Expected lossy conversions without WithStrict, got %v", err)
+	}
+}
+
+func TestValue_TimeZone(t *testing.T) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.FixedZone("", 3600))
+	var buf bytes.Buffer
+	err := codec.EncodeValue(codec.MsgPack, &buf, codec.TimeValue(when))
+	var lossErr *codec.LossError
+	if !errors.As(err, &lossErr) || lossErr.Losses[0].Reason != "time zone dropped" {
+		t.Errorf("Expected a time zone loss, got %v", err)
+	}
+}
@@ -3,38 +3,95 @@ package pool
 import (
 	"bytes"
 	"sync"
+	"sync/atomic"
 )
 
 const (
-	// Size64K maximum buffer size to pool (prevent memory bloat)
+	// Size64K is 64 KiB, the largest buffer pooled before size classes
+	// were introduced
 	Size64K = 65536
 )
 
 var (
-	// bytesBufferPool is a pool for bytes.Buffer objects
-	bytesBufferPool = &sync.Pool{
+	// defaultPool serves the package-level functions
+	defaultPool atomic.Pointer[Pool]
+
+	// appendBufferPool is a pool for bytes.Buffer objects wrapping a
+	// caller's slice
+	appendBufferPool = &sync.Pool{
 		New: func() interface{} {
 			return new(bytes.Buffer)
 		},
 	}
 )
 
+func init() {
+	defaultPool.Store(New(Config{}))
+}
+
+// Default returns the Pool used by the package-level functions
+func Default() *Pool {
+	return defaultPool.Load()
+}
+
+// SetDefault replaces the Pool used by the package-level functions, such as
+// with one retaining larger buffers. Buffers taken from the previous pool
+// may still be returned; they are kept by the new one.
+func SetDefault(p *Pool) {
+	if p != nil {
+		defaultPool.Store(p)
+	}
+}
+
 // GetBytesBuffer retrieves a bytes.Buffer from the pool
 func GetBytesBuffer() *bytes.Buffer {
-	buf := bytesBufferPool.Get().(*bytes.Buffer)
-	buf.Reset()
-	return buf
+	return Default().GetBuffer(0)
 }
 
-// PutBytesBuffer returns a bytes.Buffer to the pool
+// GetBytesBufferSize retrieves a bytes.Buffer with a capacity of at least
+// size from the pool
+func GetBytesBufferSize(size int) *bytes.Buffer {
+	return Default().GetBuffer(size)
+}
+
+// PutBytesBuffer returns a bytes.Buffer to the pool. Buffers larger than
+// the pool's MaxSize are discarded to prevent memory bloat.
 func PutBytesBuffer(buf *bytes.Buffer) {
+	Default().PutBuffer(buf)
+}
+
+// GetBytes retrieves an empty byte slice with a capacity of at least size
+// from the pool
+func GetBytes(size int) []byte {
+	return Default().Get(size)
+}
+
+// PutBytes returns a byte slice to the pool. Slices larger than the pool's
+// MaxSize are discarded to prevent memory bloat.
+func PutBytes(b []byte) {
+	Default().Put(b)
+}
+
+// GetStats returns the counters of the pool used by the package-level
+// functions
+func GetStats() Stats {
+	return Default().Stats()
+}
+
+// GetAppendBuffer retrieves a bytes.Buffer from the pool whose contents are
+// buf, so that writes append to buf in place until it runs out of capacity
+func GetAppendBuffer(buf []byte) *bytes.Buffer {
+	b := appendBufferPool.Get().(*bytes.Buffer)
+	*b = *bytes.NewBuffer(buf)
+	return b
+}
+
+// PutAppendBuffer returns a bytes.Buffer retrieved with GetAppendBuffer to
+// the pool. It drops the wrapped slice, which the pool never reuses.
+func PutAppendBuffer(buf *bytes.Buffer) {
 	if buf == nil {
 		return
 	}
-	// Only pool buffers under a reasonable size to prevent memory bloat
-	if buf.Cap() > Size64K {
-		return
-	}
-	buf.Reset()
-	bytesBufferPool.Put(buf)
+	*buf = bytes.Buffer{}
+	appendBufferPool.Put(buf)
 }
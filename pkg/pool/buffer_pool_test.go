@@ -26,9 +26,20 @@ func TestGetBytesBuffer(t *testing.T) {
 }
 
 func TestPutBytesBuffer_LargeBuffer(t *testing.T) {
-	buf := bytes.NewBuffer(make([]byte, 0, Size64K*2))
+	discarded := GetStats().Buffers.DiscardedOversize
+	buf := bytes.NewBuffer(make([]byte, 0, DefaultMaxSize*2))
 	PutBytesBuffer(buf)
 	// Should not panic, but won't actually pool the buffer
+	if got := GetStats().Buffers.DiscardedOversize; got != discarded+1 {
+		t.Errorf("DiscardedOversize = %d, want %d", got, discarded+1)
+	}
+
+	// Buffers above the former 64 KiB limit are now retained
+	discarded = GetStats().Buffers.DiscardedOversize
+	PutBytesBuffer(bytes.NewBuffer(make([]byte, 0, Size64K*2)))
+	if got := GetStats().Buffers.DiscardedOversize; got != discarded {
+		t.Errorf("a %d byte buffer was discarded", Size64K*2)
+	}
 }
 
 func TestPutBytesBuffer_Nil(t *testing.T) {
@@ -36,6 +47,31 @@ func TestPutBytesBuffer_Nil(t *testing.T) {
 	PutBytesBuffer(nil)
 }
 
+func TestGetAppendBuffer(t *testing.T) {
+	dst := make([]byte, 0, 64)
+	dst = append(dst, "prefix:"...)
+
+	buf := GetAppendBuffer(dst)
+	buf.WriteString("data")
+	out := buf.Bytes()
+	PutAppendBuffer(buf)
+
+	if string(out) != "prefix:data" {
+		t.Errorf("Bytes() = %q, want %q", out, "prefix:data")
+	}
+	if &out[0] != &dst[0] {
+		t.Error("Buffer did not write into the wrapped slice")
+	}
+
+	// A buffer from the pool no longer holds the previous slice
+	buf2 := GetAppendBuffer(nil)
+	if buf2.Len() != 0 {
+		t.Errorf("Buffer length = %d, want 0", buf2.Len())
+	}
+	PutAppendBuffer(buf2)
+	PutAppendBuffer(nil)
+}
+
 func BenchmarkBytesBufferPool(b *testing.B) {
 	b.ResetTimer()
 	b.ReportAllocs()
@@ -0,0 +1,222 @@
+package pool
+
+import (
+	"bytes"
+	"math/bits"
+	"sync"
+	"sync/atomic"
+)
+
+const (
+	// DefaultMinSize is the capacity of the smallest size class of a Pool
+	// created without a MinSize
+	DefaultMinSize = 512
+
+	// DefaultMaxSize is the largest capacity retained by a Pool created
+	// without a MaxSize
+	DefaultMaxSize = 1 << 20
+)
+
+// Config sets the size classes of a Pool
+type Config struct {
+	// MinSize is the capacity of the smallest size class, rounded up to a
+	// power of two. Smaller buffers returned to the pool are dropped.
+	MinSize int
+
+	// MaxSize is the largest capacity the pool retains, rounded up to a
+	// power of two. Size classes are the powers of two from MinSize up to
+	// MaxSize, and larger buffers returned to the pool are discarded.
+	MaxSize int
+}
+
+// Counters reports the activity of one kind of pooled buffer
+type Counters struct {
+	// Gets is the number of buffers requested
+	Gets uint64
+
+	// Puts is the number of buffers returned, including discarded ones
+	Puts uint64
+
+	// Misses is the number of requests that allocated a new buffer
+	Misses uint64
+
+	// DiscardedOversize is the number of returned buffers dropped for
+	// exceeding MaxSize
+	DiscardedOversize uint64
+
+	// DiscardedUndersize is the number of returned buffers dropped for
+	// being smaller than MinSize
+	DiscardedUndersize uint64
+}
+
+// Stats reports the activity of a Pool
+type Stats struct {
+	// Buffers counts bytes.Buffer values
+	Buffers Counters
+
+	// Bytes counts byte slices
+	Bytes Counters
+}
+
+// counters holds the live values reported as Counters
+type counters struct {
+	gets, puts, misses, oversize, undersize atomic.Uint64
+}
+
+func (c *counters) snapshot() Counters {
+	return Counters{
+		Gets:               c.gets.Load(),
+		Puts:               c.puts.Load(),
+		Misses:             c.misses.Load(),
+		DiscardedOversize:  c.oversize.Load(),
+		DiscardedUndersize: c.undersize.Load(),
+	}
+}
+
+// Pool keeps bytes.Buffer values and byte slices in size classes, so that a
+// request for a large buffer is served by a large one returned earlier
+// without small requests holding on to it. It is safe for concurrent use.
+type Pool struct {
+	minShift int
+	maxSize  int
+	buffers  []sync.Pool
+	slices   []sync.Pool
+	headers  sync.Pool
+
+	bufferStats counters
+	sliceStats  counters
+}
+
+// New creates a Pool with the size classes described by cfg
+func New(cfg Config) *Pool {
+	minSize := cfg.MinSize
+	if minSize <= 0 {
+		minSize = DefaultMinSize
+	}
+	maxSize := cfg.MaxSize
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+
+	// MaxSize is rounded up to a class boundary, so that every buffer the
+	// pool retains can be handed out again
+	p := &Pool{minShift: bits.Len(uint(minSize - 1))}
+	p.maxSize = 1 << max(bits.Len(uint(maxSize-1)), p.minShift)
+	classes := 1
+	for p.classSize(classes) <= p.maxSize {
+		classes++
+	}
+	p.buffers = make([]sync.Pool, classes)
+	p.slices = make([]sync.Pool, classes)
+	return p
+}
+
+// MaxSize returns the largest capacity the pool retains
+func (p *Pool) MaxSize() int {
+	return p.maxSize
+}
+
+// Stats returns the pool's counters
+func (p *Pool) Stats() Stats {
+	return Stats{Buffers: p.bufferStats.snapshot(), Bytes: p.sliceStats.snapshot()}
+}
+
+// GetBuffer retrieves an empty bytes.Buffer with a capacity of at least
+// size from the pool
+func (p *Pool) GetBuffer(size int) *bytes.Buffer {
+	p.bufferStats.gets.Add(1)
+	class, ok := p.getClass(size)
+	if !ok {
+		p.bufferStats.misses.Add(1)
+		return bytes.NewBuffer(make([]byte, 0, size))
+	}
+	if buf, _ := p.buffers[class].Get().(*bytes.Buffer); buf != nil {
+		return buf
+	}
+	p.bufferStats.misses.Add(1)
+	return bytes.NewBuffer(make([]byte, 0, p.classSize(class)))
+}
+
+// PutBuffer returns a bytes.Buffer to the pool. The buffer must not be used
+// afterwards.
+func (p *Pool) PutBuffer(buf *bytes.Buffer) {
+	if buf == nil {
+		return
+	}
+	p.bufferStats.puts.Add(1)
+	class, ok := p.putClass(buf.Cap(), &p.bufferStats)
+	if !ok {
+		return
+	}
+	buf.Reset()
+	p.buffers[class].Put(buf)
+}
+
+// Get retrieves an empty byte slice with a capacity of at least size from
+// the pool
+func (p *Pool) Get(size int) []byte {
+	p.sliceStats.gets.Add(1)
+	class, ok := p.getClass(size)
+	if !ok {
+		p.sliceStats.misses.Add(1)
+		return make([]byte, 0, size)
+	}
+	if h, _ := p.slices[class].Get().(*[]byte); h != nil {
+		b := *h
+		*h = nil
+		p.headers.Put(h)
+		return b[:0]
+	}
+	p.sliceStats.misses.Add(1)
+	return make([]byte, 0, p.classSize(class))
+}
+
+// Put returns a byte slice to the pool. The slice must not be used
+// afterwards.
+func (p *Pool) Put(b []byte) {
+	if b == nil {
+		return
+	}
+	p.sliceStats.puts.Add(1)
+	class, ok := p.putClass(cap(b), &p.sliceStats)
+	if !ok {
+		return
+	}
+
+	// Slices are held through reused headers so that Put does not allocate
+	h, _ := p.headers.Get().(*[]byte)
+	if h == nil {
+		h = new([]byte)
+	}
+	*h = b[:0]
+	p.slices[class].Put(h)
+}
+
+// classSize returns the capacity of buffers in class
+func (p *Pool) classSize(class int) int {
+	return 1 << (p.minShift + class)
+}
+
+// getClass returns the smallest class whose buffers hold size bytes,
+// reporting false if size exceeds every class
+func (p *Pool) getClass(size int) (int, bool) {
+	if size <= 1<<p.minShift {
+		return 0, true
+	}
+	class := bits.Len(uint(size-1)) - p.minShift
+	return class, class < len(p.buffers)
+}
+
+// putClass returns the largest class whose capacity does not exceed
+// capacity, reporting false for buffers the pool does not retain
+func (p *Pool) putClass(capacity int, stats *counters) (int, bool) {
+	if capacity > p.maxSize {
+		stats.oversize.Add(1)
+		return 0, false
+	}
+	if capacity < 1<<p.minShift {
+		stats.undersize.Add(1)
+		return 0, false
+	}
+	return min(bits.Len(uint(capacity))-1-p.minShift, len(p.buffers)-1), true
+}
@@ -0,0 +1,132 @@
+package pool
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestPool_SizeClasses(t *testing.T) {
+	p := New(Config{MinSize: 100, MaxSize: 4096})
+	for _, tt := range []struct {
+		size    int
+		wantCap int
+	}{
+		{0, 128},
+		{128, 128},
+		{129, 256},
+		{3000, 4096},
+		{5000, 5000},
+	} {
+		if b := p.Get(tt.size); len(b) != 0 || cap(b) != tt.wantCap {
+			t.Errorf("Get(%d) = len %d cap %d, want len 0 cap %d", tt.size, len(b), cap(b), tt.wantCap)
+		}
+		if buf := p.GetBuffer(tt.size); buf.Len() != 0 || buf.Cap() != tt.wantCap {
+			t.Errorf("GetBuffer(%d) = len %d cap %d, want len 0 cap %d", tt.size, buf.Len(), buf.Cap(), tt.wantCap)
+		}
+	}
+	if p.MaxSize() != 4096 {
+		t.Errorf("MaxSize() = %d, want 4096", p.MaxSize())
+	}
+}
+
+func TestPool_RoundsMaxSize(t *testing.T) {
+	p := New(Config{MaxSize: 200 << 10})
+	if p.MaxSize() != 256<<10 {
+		t.Errorf("MaxSize() = %d, want %d", p.MaxSize(), 256<<10)
+	}
+	if b := p.Get(150 << 10); cap(b) != 256<<10 {
+		t.Errorf("Get(150 KiB) cap = %d, want the 256 KiB class", cap(b))
+	}
+	if b := p.Get(300 << 10); cap(b) != 300<<10 {
+		t.Errorf("Get(300 KiB) cap = %d, want an unpooled slice", cap(b))
+	}
+}
+
+func TestPool_ReusesLargeBuffers(t *testing.T) {
+	p := New(Config{})
+	const size = 200 * 1024
+
+	// sync.Pool may drop any item, so look for reuse over a few rounds
+	reused := false
+	for i := 0; i < 10 && !reused; i++ {
+		b := p.Get(size)
+		b = append(b, "data"...)
+		p.Put(b)
+		if got := p.Get(size); cap(got) >= size && len(got) == 0 && &got[:1][0] == &b[0] {
+			reused = true
+		}
+	}
+	if !reused {
+		t.Error("a 200 KB slice was never reused")
+	}
+
+	// Small requests are not served by the large class
+	if b := p.Get(10); cap(b) != DefaultMinSize {
+		t.Errorf("Get(10) cap = %d, want %d", cap(b), DefaultMinSize)
+	}
+}
+
+func TestPool_Stats(t *testing.T) {
+	p := New(Config{MinSize: 64, MaxSize: 1024})
+
+	b := p.Get(100)
+	p.Put(b)
+	p.Put(make([]byte, 0, 2048))
+	p.Put(make([]byte, 0, 8))
+	p.Put(nil)
+
+	buf := p.GetBuffer(0)
+	buf.WriteString("data")
+	p.PutBuffer(buf)
+	p.PutBuffer(bytes.NewBuffer(make([]byte, 0, 4096)))
+	p.PutBuffer(bytes.NewBuffer(make([]byte, 0, 16)))
+	p.PutBuffer(nil)
+
+	stats := p.Stats()
+	if got := stats.Bytes; got.Gets != 1 || got.Puts != 3 || got.Misses != 1 || got.DiscardedOversize != 1 || got.DiscardedUndersize != 1 {
+		t.Errorf("Bytes = %+v", got)
+	}
+	if got := stats.Buffers; got.Gets != 1 || got.Puts != 3 || got.Misses != 1 || got.DiscardedOversize != 1 || got.DiscardedUndersize != 1 {
+		t.Errorf("Buffers = %+v", got)
+	}
+}
+
+func TestPool_GetBufferIsEmpty(t *testing.T) {
+	p := New(Config{})
+	for i := 0; i < 10; i++ {
+		buf := p.GetBuffer(0)
+		if buf.Len() != 0 {
+			t.Fatalf("GetBuffer returned %d bytes of previous data", buf.Len())
+		}
+		buf.WriteString("previous data")
+		p.PutBuffer(buf)
+	}
+}
+
+func TestSetDefault(t *testing.T) {
+	old := Default()
+	defer SetDefault(old)
+
+	p := New(Config{MaxSize: 4 << 20})
+	SetDefault(p)
+	SetDefault(nil)
+	if Default() != p {
+		t.Fatal("SetDefault did not replace the default pool")
+	}
+
+	PutBytes(GetBytes(3 << 20))
+	PutBytesBuffer(GetBytesBufferSize(3 << 20))
+	if stats := GetStats(); stats.Bytes.Gets != 1 || stats.Buffers.Gets != 1 || stats.Bytes.DiscardedOversize != 0 {
+		t.Errorf("GetStats() = %+v", stats)
+	}
+}
+
+func BenchmarkPool_GetPutLarge(b *testing.B) {
+	p := New(Config{})
+	b.ReportAllocs()
+
+	for i := 0; i < b.N; i++ {
+		buf := p.Get(200 * 1024)
+		p.Put(buf)
+	}
+}
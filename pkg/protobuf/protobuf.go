@@ -19,16 +19,42 @@ type ProtoMessage interface {
 }
 
 // Codec implements the codec.Codec interface for Protocol Buffers serialization
-type Codec[T ProtoMessage] struct{}
+type Codec[T ProtoMessage] struct {
+	marshalOpts   proto.MarshalOptions
+	unmarshalOpts proto.UnmarshalOptions
+	limits        codec.Limits
+	strict        bool
+	err           error
+}
 
-// New creates a new Protocol Buffers codec
-func New[T ProtoMessage]() *Codec[T] {
-	return &Codec[T]{}
+// New creates a new Protocol Buffers codec configured with the given options.
+// If an option does not apply to Protocol Buffers, every operation returns
+// the error, which is also reported by Err.
+func New[T ProtoMessage](opts ...codec.Option) *Codec[T] {
+	var cfg config
+	c := &Codec[T]{}
+	c.err = codec.ApplyOptions(codec.ProtoBuf, &cfg, opts)
+	c.marshalOpts = proto.MarshalOptions{
+		Deterministic: cfg.deterministic,
+		AllowPartial:  cfg.allowPartial,
+	}
+	c.unmarshalOpts = proto.UnmarshalOptions{
+		AllowPartial:   cfg.allowPartial,
+		DiscardUnknown: cfg.discardUnknown,
+	}
+	c.limits = cfg.Limits
+	c.strict = cfg.Strict
+	return c
+}
+
+// Err returns the error, if any, caused by the options passed to New
+func (c *Codec[T]) Err() error {
+	return c.err
 }
 
 // Encode serializes the given data to the writer using Protocol Buffers
 func (c *Codec[T]) Encode(w io.Writer, data T) error {
-	bytes, err := proto.Marshal(data)
+	bytes, err := c.Marshal(data)
 	if err == nil {
 		_, err = w.Write(bytes)
 	}
@@ -37,19 +63,41 @@ func (c *Codec[T]) Encode(w io.Writer, data T) error {
 
 // Decode deserializes Protocol Buffers data from the reader into the provided type
 func (c *Codec[T]) Decode(r io.Reader, data *T) error {
-	bytes, err := io.ReadAll(r)
+	if c.err != nil {
+		return c.err
+	}
+	bytes, err := io.ReadAll(codec.NewLimitedReader(r, codec.ProtoBuf, c.limits.MaxBytes))
 	if err != nil {
 		return err
 	}
-	return proto.Unmarshal(bytes, *data)
+	return c.Unmarshal(bytes, data)
 }
 
 // Marshal serializes the given data to Protocol Buffers bytes
 func (c *Codec[T]) Marshal(data T) ([]byte, error) {
-	return proto.Marshal(data)
+	if c.err != nil {
+		return nil, c.err
+	}
+	return c.marshalOpts.Marshal(data)
+}
+
+// appendMarshal appends the encoding of data to buf, writing directly into
+// buf while it has room
+func (c *Codec[T]) appendMarshal(buf []byte, data T) ([]byte, error) {
+	out, err := c.marshalOpts.MarshalAppend(buf, data)
+	if err != nil {
+		return buf, err
+	}
+	return out, nil
 }
 
 // Unmarshal deserializes Protocol Buffers bytes into the provided type
 func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
-	return proto.Unmarshal(data, *v)
+	if c.err != nil {
+		return c.err
+	}
+	if err := c.checkLimits(data, *v); err != nil {
+		return err
+	}
+	return decodeError(c.unmarshalOpts.Unmarshal(data, *v), data, *v)
 }
@@ -0,0 +1,93 @@
+//go:build codec_protobuf
+
+package protobuf
+
+import (
+	"errors"
+	"io"
+	"strconv"
+	"strings"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// decodeError converts an error returned by the protobuf runtime into a
+// codec.DecodeError. data is the complete encoding of msg when it is
+// available; it is rescanned at the wire level to locate the failing field, since the
+// runtime reports neither offsets nor field paths. Read errors and io.EOF
+// at the end of a stream are returned as is.
+func decodeError(err error, data []byte, msg proto.Message) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+
+	de := codec.DecodeError{Codec: codec.ProtoBuf, Kind: codec.ErrSyntax, Offset: -1, Err: err}
+	var sizeErr *protodelim.SizeTooLargeError
+	switch {
+	case errors.Is(err, io.ErrUnexpectedEOF):
+		de.Kind = codec.ErrTruncated
+	case errors.As(err, &sizeErr):
+		de.Kind = codec.ErrLimitExceeded
+	case !errors.Is(err, proto.Error):
+		return err
+	case strings.Contains(err.Error(), "recursion"):
+		de.Kind = codec.ErrLimitExceeded
+	}
+
+	if data != nil && msg != nil {
+		if offset, path, wireErr := scanWire(data, msg.ProtoReflect().Descriptor(), 0, ""); wireErr != nil {
+			de.Offset, de.Path = int64(offset), path
+			if errors.Is(wireErr, io.ErrUnexpectedEOF) {
+				de.Kind = codec.ErrTruncated
+			}
+		}
+	}
+	return de
+}
+
+// scanWire walks the fields of data, a message of type md starting at base
+// in the input, descending into nested messages. It returns the offset of
+// the tag and the field path of the first malformed field and the wire-level error, or a
+// nil error if data is well-formed on the wire.
+func scanWire(data []byte, md protoreflect.MessageDescriptor, base int, path string) (int, string, error) {
+	for offset := 0; offset < len(data); {
+		num, typ, n := protowire.ConsumeTag(data[offset:])
+		if n < 0 {
+			return base + offset, path, protowire.ParseError(n)
+		}
+
+		fd := md.Fields().ByNumber(num)
+		fieldPath := joinPath(path, fd, num)
+		value := offset + n
+		m := protowire.ConsumeFieldValue(num, typ, data[value:])
+		if m < 0 {
+			return base + offset, fieldPath, protowire.ParseError(m)
+		}
+
+		if typ == protowire.BytesType && fd != nil && fd.Kind() == protoreflect.MessageKind {
+			payload, _ := protowire.ConsumeBytes(data[value:])
+			start := value + m - len(payload)
+			if off, p, err := scanWire(payload, fd.Message(), base+start, fieldPath); err != nil {
+				return off, p, err
+			}
+		}
+		offset = value + m
+	}
+	return 0, "", nil
+}
+
+// joinPath appends the field fd (or its number, if unknown) to path
+func joinPath(path string, fd protoreflect.FieldDescriptor, num protowire.Number) string {
+	name := strconv.Itoa(int(num))
+	if fd != nil {
+		name = string(fd.Name())
+	}
+	if path == "" {
+		return name
+	}
+	return path + "." + name
+}
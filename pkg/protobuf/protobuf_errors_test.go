@@ -0,0 +1,85 @@
+//go:build codec_protobuf
+
+package protobuf
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/protobuf/testdata"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestUnmarshal_Truncated(t *testing.T) {
+	c := New[*testdata.TestMessage]()
+	data, err := c.Marshal(&testdata.TestMessage{Name: "John", Age: 30})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	msg := &testdata.TestMessage{}
+	err = c.Unmarshal(data[:3], &msg)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Codec != codec.ProtoBuf || de.Kind != codec.ErrTruncated || de.Path != "name" || de.Offset != 0 {
+		t.Errorf("unexpected error: %+v", de)
+	}
+	if !errors.Is(err, proto.Error) {
+		t.Error("expected the library error to remain reachable")
+	}
+}
+
+func TestUnmarshal_NestedPath(t *testing.T) {
+	c := New[*testdata.IntMap]()
+	data, err := c.Marshal(&testdata.IntMap{Values: map[string]int32{"a": 1}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	// Corrupt the map entry's value tag into a reserved wire type
+	data[len(data)-2] = byte(protowire.EncodeTag(2, 7))
+
+	msg := &testdata.IntMap{}
+	err = c.Unmarshal(data, &msg)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Kind != codec.ErrSyntax || de.Path != "values.value" || de.Offset != int64(len(data)-2) {
+		t.Errorf("unexpected error: %+v", de)
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	c := New[*testdata.TestMessage]()
+	var buf bytes.Buffer
+	enc := c.NewEncoder(&buf)
+	if err := enc.Encode(&testdata.TestMessage{Name: "John"}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	data := buf.Bytes()
+
+	dec := c.NewDecoder(bytes.NewReader(append(append([]byte{}, data...), data[:3]...)))
+	msg := &testdata.TestMessage{}
+	if err := dec.Decode(&msg); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if err := dec.Decode(&msg); !errors.Is(err, codec.ErrTruncated) {
+		t.Fatalf("expected truncated error, got %v", err)
+	}
+
+	dec = c.NewDecoder(bytes.NewReader(data))
+	if err := dec.Decode(&msg); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if err := dec.Decode(&msg); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
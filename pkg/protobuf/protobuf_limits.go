@@ -0,0 +1,133 @@
+//go:build codec_protobuf
+
+package protobuf
+
+import (
+	"fmt"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// checkLimits enforces the codec's limits on data, an encoding of msg. When
+// decoding strictly, the same scan rejects unknown fields, duplicate map
+// keys and integers too large for their 32-bit fields.
+func (c *Codec[T]) checkLimits(data []byte, msg proto.Message) error {
+	if err := c.limits.CheckBytes(int64(len(data))); err != nil {
+		return limitError(err, c.limits.MaxBytes, "")
+	}
+	if (!c.limits.Structural() && !c.strict) || msg == nil {
+		return nil
+	}
+	s := scanner{limits: c.limits, strict: c.strict}
+	return s.scan(data, msg.ProtoReflect().Descriptor(), 0, 1, "")
+}
+
+// scanner walks encoded messages without decoding them
+type scanner struct {
+	limits codec.Limits
+	strict bool
+}
+
+// scan walks the fields of data, a message of type md at the given nesting
+// depth starting at base in the input, checking nested messages, the number
+// of values of each repeated field and the length of strings and bytes.
+// Malformed input is left for the runtime to report.
+func (s *scanner) scan(data []byte, md protoreflect.MessageDescriptor, base, depth int, path string) error {
+	limits := s.limits
+	if err := limits.CheckDepth(depth); err != nil {
+		return limitError(err, int64(base), path)
+	}
+
+	var counts map[protowire.Number]int64
+	var keys map[protowire.Number]map[string]bool
+	for offset := 0; offset < len(data); {
+		num, typ, n := protowire.ConsumeTag(data[offset:])
+		if n < 0 {
+			return nil
+		}
+		value := offset + n
+		m := protowire.ConsumeFieldValue(num, typ, data[value:])
+		if m < 0 {
+			return nil
+		}
+
+		fd := md.Fields().ByNumber(num)
+		fieldPath := joinPath(path, fd, num)
+		if s.strict {
+			if err := checkStrict(md, fd, num, typ, data[value:value+m], int64(base+offset), fieldPath); err != nil {
+				return err
+			}
+		}
+		if fd != nil && fd.IsMap() && s.strict && typ == protowire.BytesType {
+			if keys == nil {
+				keys = make(map[protowire.Number]map[string]bool)
+			}
+			if keys[num] == nil {
+				keys[num] = make(map[string]bool)
+			}
+			payload, _ := protowire.ConsumeBytes(data[value:])
+			key := mapKey(fd.MapKey(), payload)
+			if keys[num][key] {
+				return strictError(codec.ErrDuplicateKey, int64(base+offset), fieldPath+"."+key, fmt.Errorf("proto: duplicate map key %q", key))
+			}
+			keys[num][key] = true
+		}
+		if fd != nil && fd.Cardinality() == protoreflect.Repeated {
+			if counts == nil {
+				counts = make(map[protowire.Number]int64)
+			}
+			payload, _ := protowire.ConsumeBytes(data[value:])
+			counts[num] += valueCount(fd, typ, payload)
+			if err := limits.CheckElements(counts[num]); err != nil {
+				return limitError(err, int64(base+offset), fieldPath)
+			}
+		}
+
+		if typ == protowire.BytesType && fd != nil {
+			payload, _ := protowire.ConsumeBytes(data[value:])
+			start := value + m - len(payload)
+			switch fd.Kind() {
+			case protoreflect.MessageKind:
+				if err := s.scan(payload, fd.Message(), base+start, depth+1, fieldPath); err != nil {
+					return err
+				}
+			case protoreflect.StringKind, protoreflect.BytesKind:
+				if err := limits.CheckStringLength(int64(len(payload))); err != nil {
+					return limitError(err, int64(base+offset), fieldPath)
+				}
+			}
+		}
+		offset = value + m
+	}
+	return nil
+}
+
+// valueCount returns the number of values of the repeated field fd held by
+// one occurrence on the wire, which is more than one for packed scalars
+func valueCount(fd protoreflect.FieldDescriptor, typ protowire.Type, payload []byte) int64 {
+	if typ != protowire.BytesType || fd.Kind() == protoreflect.MessageKind ||
+		fd.Kind() == protoreflect.StringKind || fd.Kind() == protoreflect.BytesKind {
+		return 1
+	}
+	switch fd.Kind() {
+	case protoreflect.Fixed32Kind, protoreflect.Sfixed32Kind, protoreflect.FloatKind:
+		return int64(len(payload) / 4)
+	case protoreflect.Fixed64Kind, protoreflect.Sfixed64Kind, protoreflect.DoubleKind:
+		return int64(len(payload) / 8)
+	}
+	count := int64(0)
+	for _, b := range payload {
+		if b < 0x80 {
+			count++
+		}
+	}
+	return count
+}
+
+// limitError wraps err, a codec.LimitError, in a codec.DecodeError
+func limitError(err error, offset int64, path string) error {
+	return codec.DecodeError{Codec: codec.ProtoBuf, Kind: codec.ErrLimitExceeded, Offset: offset, Path: path, Err: err}
+}
@@ -0,0 +1,111 @@
+//go:build codec_protobuf
+
+package protobuf
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/protobuf/testdata"
+	"google.golang.org/protobuf/proto"
+)
+
+func TestLimits_Unmarshal(t *testing.T) {
+	list, _ := proto.Marshal(&testdata.StringList{Values: []string{"a", "b", "c", "Johnny"}})
+	intMap, _ := proto.Marshal(&testdata.IntMap{Values: map[string]int32{"a": 1}})
+	unmarshalList := func(limits codec.Limits) error {
+		msg := &testdata.StringList{}
+		return New[*testdata.StringList](codec.WithLimits(limits)).Unmarshal(list, &msg)
+	}
+	unmarshalMap := func(limits codec.Limits) error {
+		msg := &testdata.IntMap{}
+		return New[*testdata.IntMap](codec.WithLimits(limits)).Unmarshal(intMap, &msg)
+	}
+
+	tests := []struct {
+		name   string
+		limits codec.Limits
+		decode func(codec.Limits) error
+		limit  string
+		path   string
+		offset int64
+	}{
+		{"bytes", codec.Limits{MaxBytes: 8}, unmarshalList, "MaxBytes", "", 8},
+		{"elements", codec.Limits{MaxElements: 3}, unmarshalList, "MaxElements", "values", 9},
+		{"string", codec.Limits{MaxStringLength: 4}, unmarshalList, "MaxStringLength", "values", 9},
+		{"depth", codec.Limits{MaxDepth: 1}, unmarshalMap, "MaxDepth", "values", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.decode(tt.limits)
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			var le codec.LimitError
+			if de.Kind != codec.ErrLimitExceeded || !errors.As(err, &le) || le.Limit != tt.limit {
+				t.Fatalf("expected %s exceeded, got %v", tt.limit, err)
+			}
+			if de.Path != tt.path || de.Offset != tt.offset {
+				t.Errorf("expected %q at offset %d, got %q at %d", tt.path, tt.offset, de.Path, de.Offset)
+			}
+		})
+	}
+}
+
+func TestLimits_WithinLimits(t *testing.T) {
+	c := New[*testdata.IntMap](codec.WithLimits(codec.Limits{MaxBytes: 64, MaxDepth: 2, MaxElements: 2, MaxStringLength: 4}))
+	data, err := c.Marshal(&testdata.IntMap{Values: map[string]int32{"a": 1, "b": 2}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	msg := &testdata.IntMap{}
+	if err := c.Unmarshal(data, &msg); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if msg.Values["b"] != 2 {
+		t.Errorf("unexpected value %v", msg.Values)
+	}
+}
+
+func TestLimits_Decoder(t *testing.T) {
+	c := New[*testdata.TestMessage](codec.WithLimits(codec.Limits{MaxStringLength: 8}))
+	var buf bytes.Buffer
+	enc := c.NewEncoder(&buf)
+	for _, name := range []string{"John", "Jane", "Johnathan Doe"} {
+		if err := enc.Encode(&testdata.TestMessage{Name: name}); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+	}
+
+	dec := c.NewDecoder(&buf)
+	for i := 0; i < 2; i++ {
+		msg := &testdata.TestMessage{}
+		if err := dec.Decode(&msg); err != nil {
+			t.Fatalf("Decode %d failed: %v", i, err)
+		}
+	}
+	msg := &testdata.TestMessage{}
+	if err := dec.Decode(&msg); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+
+	c = New[*testdata.TestMessage](codec.WithLimits(codec.Limits{MaxBytes: 64}))
+	buf.Reset()
+	if err := c.NewEncoder(&buf).Encode(&testdata.TestMessage{Name: strings.Repeat("x", 200)}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	data := bytes.Clone(buf.Bytes())
+	if err := c.NewDecoder(&buf).Decode(&msg); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+	if err := c.Decode(bytes.NewReader(data[2:]), &msg); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+}
@@ -0,0 +1,71 @@
+//go:build codec_protobuf
+
+package protobuf
+
+import (
+	"bytes"
+	"testing"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/protobuf/testdata"
+)
+
+var _ codec.OptimizedCodec[*testdata.TestMessage] = NewPool[*testdata.TestMessage]()
+
+func TestOptimizedCodec_MarshalTo(t *testing.T) {
+	c := NewPool[*testdata.TestMessage]()
+	data := &testdata.TestMessage{Name: "John Doe", Age: 30, Email: "john@example.com"}
+	want, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	buf := make([]byte, 0, 256)
+	result, err := c.MarshalTo(buf, data)
+	if err != nil {
+		t.Fatalf("MarshalTo failed: %v", err)
+	}
+	if !bytes.Equal(result, want) {
+		t.Errorf("MarshalTo = %x, want %x", result, want)
+	}
+	if &result[0] != &buf[:1][0] {
+		t.Error("MarshalTo did not write into the provided buffer")
+	}
+
+	result, err = c.MarshalTo(make([]byte, 0, 1), data)
+	if err != nil || !bytes.Equal(result, want) {
+		t.Errorf("MarshalTo with a small buffer = %x, %v", result, err)
+	}
+}
+
+func TestOptimizedCodec_AppendMarshal(t *testing.T) {
+	c := NewPool[*testdata.TestMessage]()
+	data := &testdata.TestMessage{Name: "John Doe", Age: 30, Email: "john@example.com"}
+
+	buf := append(make([]byte, 0, 256), "prefix:"...)
+	result, err := c.AppendMarshal(buf, data)
+	if err != nil {
+		t.Fatalf("AppendMarshal failed: %v", err)
+	}
+	if &result[0] != &buf[0] {
+		t.Error("AppendMarshal did not write into the provided buffer")
+	}
+
+	decoded := &testdata.TestMessage{}
+	if err := c.UnmarshalFrom(result[7:], &decoded, nil); err != nil {
+		t.Fatalf("UnmarshalFrom failed: %v", err)
+	}
+	if decoded.Name != data.Name || decoded.Age != data.Age || decoded.Email != data.Email {
+		t.Errorf("expected %+v, got %+v", data, decoded)
+	}
+}
+
+func TestOptimizedCodec_OptionError(t *testing.T) {
+	c := NewPool[*testdata.TestMessage](codec.NewOption("other.WithThing", func(*struct{}) {}))
+	if _, err := c.MarshalTo(nil, &testdata.TestMessage{}); err == nil {
+		t.Error("expected option error from MarshalTo")
+	}
+	if _, err := c.AppendMarshal(nil, &testdata.TestMessage{}); err == nil {
+		t.Error("expected option error from AppendMarshal")
+	}
+}
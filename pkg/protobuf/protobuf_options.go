@@ -0,0 +1,36 @@
+package protobuf
+
+import codec "github.com/jeremyhahn/go-codec"
+
+// config holds the settings applied by Protocol Buffers options
+type config struct {
+	codec.Config
+
+	deterministic  bool
+	allowPartial   bool
+	discardUnknown bool
+}
+
+// WithDeterministic orders map entries when encoding so that equal messages
+// produce identical bytes within the same binary
+func WithDeterministic() codec.Option {
+	return codec.NewOption("protobuf.WithDeterministic", func(c *config) {
+		c.deterministic = true
+	})
+}
+
+// WithAllowPartial skips the check for missing required fields (proto2)
+// when encoding and decoding
+func WithAllowPartial() codec.Option {
+	return codec.NewOption("protobuf.WithAllowPartial", func(c *config) {
+		c.allowPartial = true
+	})
+}
+
+// WithDiscardUnknown drops unknown fields while decoding instead of
+// retaining them in the message. codec.WithStrict takes precedence.
+func WithDiscardUnknown() codec.Option {
+	return codec.NewOption("protobuf.WithDiscardUnknown", func(c *config) {
+		c.discardUnknown = true
+	})
+}
@@ -0,0 +1,99 @@
+//go:build codec_protobuf
+
+package protobuf
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+	"github.com/jeremyhahn/go-codec/pkg/protobuf/testdata"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func TestWithDeterministic(t *testing.T) {
+	c := New[*testdata.IntMap](WithDeterministic())
+	data := &testdata.IntMap{Values: map[string]int32{"a": 1, "b": 2, "c": 3, "d": 4, "e": 5}}
+
+	first, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	for i := 0; i < 10; i++ {
+		again, err := c.Marshal(data)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if !bytes.Equal(first, again) {
+			t.Fatal("expected deterministic output")
+		}
+	}
+}
+
+func TestWithDiscardUnknown(t *testing.T) {
+	// A TestMessage with an extra field number 15 unknown to the schema
+	data := protowire.AppendTag(nil, 1, protowire.BytesType)
+	data = protowire.AppendString(data, "John")
+	data = protowire.AppendTag(data, 15, protowire.VarintType)
+	data = protowire.AppendVarint(data, 7)
+
+	kept := &testdata.TestMessage{}
+	if err := New[*testdata.TestMessage]().Unmarshal(data, &kept); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(kept.ProtoReflect().GetUnknown()) == 0 {
+		t.Error("expected unknown field to be retained by default")
+	}
+
+	c := New[*testdata.TestMessage](WithDiscardUnknown(), WithAllowPartial())
+	discarded := &testdata.TestMessage{}
+	if err := c.Unmarshal(data, &discarded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if len(discarded.ProtoReflect().GetUnknown()) != 0 {
+		t.Error("expected unknown field to be discarded")
+	}
+
+	var buf bytes.Buffer
+	if err := c.NewEncoder(&buf).Encode(kept); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	streamed := &testdata.TestMessage{}
+	if err := c.NewDecoder(&buf).Decode(&streamed); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if streamed.Name != "John" || len(streamed.ProtoReflect().GetUnknown()) != 0 {
+		t.Errorf("unexpected streamed message: %v", streamed)
+	}
+}
+
+func TestNew_OptionNotSupported(t *testing.T) {
+	c := New[*testdata.TestMessage](jsoncodec.WithUseNumber())
+
+	var notSupported codec.ErrOptionNotSupported
+	if !errors.As(c.Err(), &notSupported) {
+		t.Fatalf("expected ErrOptionNotSupported, got %v", c.Err())
+	}
+
+	result := &testdata.TestMessage{}
+	if _, err := c.Marshal(result); err == nil {
+		t.Error("Marshal: expected option error, got nil")
+	}
+	if err := c.Unmarshal(nil, &result); err == nil {
+		t.Error("Unmarshal: expected option error, got nil")
+	}
+	if err := c.Encode(&bytes.Buffer{}, result); err == nil {
+		t.Error("Encode: expected option error, got nil")
+	}
+	if err := c.Decode(bytes.NewReader(nil), &result); err == nil {
+		t.Error("Decode: expected option error, got nil")
+	}
+	if err := c.NewEncoder(&bytes.Buffer{}).Encode(result); err == nil {
+		t.Error("Encoder.Encode: expected option error, got nil")
+	}
+	if err := c.NewDecoder(bytes.NewReader(nil)).Decode(&result); err == nil {
+		t.Error("Decoder.Decode: expected option error, got nil")
+	}
+}
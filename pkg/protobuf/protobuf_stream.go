@@ -0,0 +1,102 @@
+//go:build codec_protobuf
+
+package protobuf
+
+import (
+	"bufio"
+	"encoding/binary"
+	"io"
+	"math"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"google.golang.org/protobuf/encoding/protodelim"
+)
+
+// defaultMaxSize is the largest message protodelim reads by default
+const defaultMaxSize = 4 << 20
+
+// Encoder writes a sequence of size-delimited Protocol Buffers messages to a
+// stream. Each message is prefixed with its length as a uvarint, the framing
+// used by protodelim and the Java writeDelimitedTo API.
+type Encoder[T ProtoMessage] struct {
+	codec *Codec[T]
+	w     io.Writer
+}
+
+// Decoder reads a sequence of size-delimited Protocol Buffers messages from a stream
+type Decoder[T ProtoMessage] struct {
+	codec *Codec[T]
+	r     protodelim.Reader
+}
+
+// NewEncoder returns an encoder session that writes successive delimited messages to w
+func (c *Codec[T]) NewEncoder(w io.Writer) codec.Encoder[T] {
+	return &Encoder[T]{codec: c, w: w}
+}
+
+// NewDecoder returns a decoder session that reads successive delimited messages
+// from r. If r does not implement io.ByteReader it is wrapped in a
+// bufio.Reader owned by the session.
+func (c *Codec[T]) NewDecoder(r io.Reader) codec.Decoder[T] {
+	br, ok := r.(protodelim.Reader)
+	if !ok {
+		br = bufio.NewReader(r)
+	}
+	return &Decoder[T]{codec: c, r: br}
+}
+
+// Encode writes the next delimited message to the stream
+func (e *Encoder[T]) Encode(data T) error {
+	if e.codec.err != nil {
+		return e.codec.err
+	}
+	opts := protodelim.MarshalOptions{MarshalOptions: e.codec.marshalOpts}
+	_, err := opts.MarshalTo(e.w, data)
+	return err
+}
+
+// Decode reads the next delimited message from the stream into the message
+// pointed to by data, which must be non-nil
+func (d *Decoder[T]) Decode(data *T) error {
+	if d.codec.err != nil {
+		return d.codec.err
+	}
+	if d.codec.limits == (codec.Limits{}) && !d.codec.strict {
+		opts := protodelim.UnmarshalOptions{UnmarshalOptions: d.codec.unmarshalOpts}
+		return decodeError(opts.UnmarshalFrom(d.r, *data), nil, nil)
+	}
+
+	// With limits set or strict decoding, the message is read whole so that
+	// it can be checked before it is decoded
+	bytes, err := readDelimited(d.r, d.codec.limits.MaxBytes)
+	if err != nil {
+		return decodeError(err, nil, nil)
+	}
+	return d.codec.Unmarshal(bytes, data)
+}
+
+// readDelimited reads the next size-delimited message from r. Messages
+// larger than maxBytes, or than protodelim's default limit if maxBytes is
+// not positive, are rejected before they are read.
+func readDelimited(r protodelim.Reader, maxBytes int64) ([]byte, error) {
+	size, err := binary.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if maxBytes <= 0 {
+		if size > defaultMaxSize {
+			return nil, &protodelim.SizeTooLargeError{Size: size, MaxSize: defaultMaxSize}
+		}
+	} else if err := (codec.Limits{MaxBytes: maxBytes}).CheckBytes(int64(min(size, math.MaxInt64))); err != nil {
+		return nil, limitError(err, 0, "")
+	}
+
+	bytes := make([]byte, size)
+	if _, err := io.ReadFull(r, bytes); err != nil {
+		if err == io.EOF {
+			err = io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return bytes, nil
+}
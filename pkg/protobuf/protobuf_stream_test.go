@@ -0,0 +1,87 @@
+//go:build codec_protobuf
+
+package protobuf
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/protobuf/testdata"
+)
+
+func TestCodec_StreamCodec(t *testing.T) {
+	var _ codec.StreamCodec[*testdata.TestMessage] = New[*testdata.TestMessage]()
+}
+
+func TestStream_RoundTrip(t *testing.T) {
+	c := New[*testdata.TestMessage]()
+	values := []*testdata.TestMessage{
+		{Name: "Alice", Age: 30, Email: "alice@example.com"},
+		{Name: "Bob", Age: 25, Email: "bob@example.com"},
+		{Name: "Carol", Age: 41, Email: "carol@example.com"},
+	}
+
+	var buf bytes.Buffer
+	enc := c.NewEncoder(&buf)
+	for _, v := range values {
+		if err := enc.Encode(v); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+	}
+
+	// Hide bytes.Buffer's ByteReader so the decoder has to buffer on its own
+	dec := c.NewDecoder(struct{ io.Reader }{&buf})
+	for i, want := range values {
+		got := &testdata.TestMessage{}
+		if err := dec.Decode(&got); err != nil {
+			t.Fatalf("Decode %d failed: %v", i, err)
+		}
+		if got.Name != want.Name || got.Age != want.Age || got.Email != want.Email {
+			t.Errorf("value %d: expected %v, got %v", i, want, got)
+		}
+	}
+
+	extra := &testdata.TestMessage{}
+	if err := dec.Decode(&extra); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF after last value, got %v", err)
+	}
+}
+
+func TestStream_Decode_DoesNotOverRead(t *testing.T) {
+	c := New[*testdata.TestMessage]()
+
+	var buf bytes.Buffer
+	if err := c.NewEncoder(&buf).Encode(&testdata.TestMessage{Name: "Alice"}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	trailer := []byte("trailing bytes")
+	r := bytes.NewReader(append(buf.Bytes(), trailer...))
+
+	result := &testdata.TestMessage{}
+	if err := c.NewDecoder(r).Decode(&result); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if r.Len() != len(trailer) {
+		t.Errorf("expected %d unread bytes, got %d", len(trailer), r.Len())
+	}
+}
+
+func TestStream_Decode_Invalid(t *testing.T) {
+	c := New[*testdata.TestMessage]()
+	dec := c.NewDecoder(bytes.NewReader([]byte{0x03, 0xff, 0xff, 0xff}))
+
+	result := &testdata.TestMessage{}
+	if err := dec.Decode(&result); err == nil {
+		t.Fatal("expected error for invalid Protocol Buffers stream, got nil")
+	}
+}
+
+func TestStream_Encode_WriteError(t *testing.T) {
+	c := New[*testdata.TestMessage]()
+	if err := c.NewEncoder(&errorWriter{}).Encode(&testdata.TestMessage{Name: "Alice"}); err == nil {
+		t.Fatal("expected error from writer, got nil")
+	}
+}
@@ -0,0 +1,132 @@
+//go:build codec_protobuf
+
+package protobuf
+
+import (
+	"fmt"
+	"math"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"google.golang.org/protobuf/encoding/protowire"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// checkStrict checks one field of a message of type md: field number num,
+// described by fd, of wire type typ and encoded as value. It rejects field
+// numbers that md does not declare, and integers that the runtime would
+// truncate to fit a 32-bit field.
+func checkStrict(md protoreflect.MessageDescriptor, fd protoreflect.FieldDescriptor, num protowire.Number, typ protowire.Type, value []byte, offset int64, path string) error {
+	if fd == nil {
+		if md.ExtensionRanges().Has(num) {
+			return nil
+		}
+		return strictError(codec.ErrUnknownField, offset, path, fmt.Errorf("proto: unknown field number %d in %s", num, md.FullName()))
+	}
+
+	switch typ {
+	case protowire.VarintType:
+		v, _ := protowire.ConsumeVarint(value)
+		if !fits32(fd.Kind(), v) {
+			return overflowError(fd, v, offset, path)
+		}
+	case protowire.BytesType:
+		// Packed repeated varints
+		if !fd.IsList() {
+			return nil
+		}
+		payload, _ := protowire.ConsumeBytes(value)
+		for len(payload) > 0 {
+			v, n := protowire.ConsumeVarint(payload)
+			if n < 0 {
+				return nil
+			}
+			if !fits32(fd.Kind(), v) {
+				return overflowError(fd, v, offset, path)
+			}
+			payload = payload[n:]
+		}
+	}
+	return nil
+}
+
+// fits32 reports whether v, a varint on the wire, holds a value of kind
+// without truncation. Only 32-bit kinds can be truncated.
+func fits32(kind protoreflect.Kind, v uint64) bool {
+	switch kind {
+	case protoreflect.Int32Kind, protoreflect.EnumKind:
+		return int64(v) >= math.MinInt32 && int64(v) <= math.MaxInt32
+	case protoreflect.Uint32Kind, protoreflect.Sint32Kind:
+		return v <= math.MaxUint32
+	}
+	return true
+}
+
+// mapKey returns the key of a map entry encoded as entry, formatted for
+// comparison and error paths. The runtime keeps the last key field of an
+// entry, and the zero value if there is none.
+func mapKey(fd protoreflect.FieldDescriptor, entry []byte) string {
+	key := fmt.Sprint(fd.Default().Interface())
+	for len(entry) > 0 {
+		num, typ, n := protowire.ConsumeTag(entry)
+		if n < 0 {
+			break
+		}
+		entry = entry[n:]
+		m := protowire.ConsumeFieldValue(num, typ, entry)
+		if m < 0 {
+			break
+		}
+		if num == 1 {
+			key = formatKey(fd.Kind(), typ, entry[:m])
+		}
+		entry = entry[m:]
+	}
+	return key
+}
+
+// formatKey formats a map key of the given kind encoded as value
+func formatKey(kind protoreflect.Kind, typ protowire.Type, value []byte) string {
+	switch typ {
+	case protowire.BytesType:
+		b, _ := protowire.ConsumeBytes(value)
+		return string(b)
+	case protowire.Fixed32Type:
+		v, _ := protowire.ConsumeFixed32(value)
+		if kind == protoreflect.Sfixed32Kind {
+			return fmt.Sprint(int32(v))
+		}
+		return fmt.Sprint(v)
+	case protowire.Fixed64Type:
+		v, _ := protowire.ConsumeFixed64(value)
+		if kind == protoreflect.Sfixed64Kind {
+			return fmt.Sprint(int64(v))
+		}
+		return fmt.Sprint(v)
+	}
+	v, _ := protowire.ConsumeVarint(value)
+	switch kind {
+	case protoreflect.BoolKind:
+		return fmt.Sprint(v != 0)
+	case protoreflect.Int32Kind:
+		return fmt.Sprint(int32(v))
+	case protoreflect.Int64Kind:
+		return fmt.Sprint(int64(v))
+	case protoreflect.Sint32Kind:
+		return fmt.Sprint(int32(protowire.DecodeZigZag(v & math.MaxUint32)))
+	case protoreflect.Sint64Kind:
+		return fmt.Sprint(protowire.DecodeZigZag(v))
+	case protoreflect.Uint32Kind:
+		return fmt.Sprint(uint32(v))
+	}
+	return fmt.Sprint(v)
+}
+
+// overflowError reports v, which does not fit the 32-bit field fd
+func overflowError(fd protoreflect.FieldDescriptor, v uint64, offset int64, path string) error {
+	return strictError(codec.ErrTypeMismatch, offset, path, fmt.Errorf("proto: %d overflows %s field %s", int64(v), fd.Kind(), fd.FullName()))
+}
+
+// strictError reports a strict decoding violation at offset and path
+func strictError(kind codec.ErrorKind, offset int64, path string, err error) error {
+	return codec.DecodeError{Codec: codec.ProtoBuf, Kind: kind, Offset: offset, Path: path, Err: err}
+}
@@ -0,0 +1,103 @@
+//go:build codec_protobuf
+
+package protobuf
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/protobuf/testdata"
+	"google.golang.org/protobuf/encoding/protodelim"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+// mapEntry encodes an entry of IntMap.values
+func mapEntry(key string, value int32) []byte {
+	var entry []byte
+	entry = protowire.AppendTag(entry, 1, protowire.BytesType)
+	entry = protowire.AppendString(entry, key)
+	entry = protowire.AppendTag(entry, 2, protowire.VarintType)
+	entry = protowire.AppendVarint(entry, uint64(value))
+
+	b := protowire.AppendTag(nil, 1, protowire.BytesType)
+	return protowire.AppendBytes(b, entry)
+}
+
+func TestStrict_Unmarshal(t *testing.T) {
+	value := protowire.AppendTag(nil, 1, protowire.VarintType)
+	value = protowire.AppendVarint(value, 42)
+	unknown := protowire.AppendTag(bytes.Clone(value), 5, protowire.VarintType)
+	unknown = protowire.AppendVarint(unknown, 1)
+	overflow := protowire.AppendTag(nil, 1, protowire.VarintType)
+	overflow = protowire.AppendVarint(overflow, 1<<33)
+
+	unmarshalInt := func(data []byte, opts ...codec.Option) error {
+		msg := &testdata.IntValue{}
+		return New[*testdata.IntValue](opts...).Unmarshal(data, &msg)
+	}
+	unmarshalMap := func(data []byte, opts ...codec.Option) error {
+		msg := &testdata.IntMap{}
+		return New[*testdata.IntMap](opts...).Unmarshal(data, &msg)
+	}
+
+	tests := []struct {
+		name   string
+		data   []byte
+		decode func([]byte, ...codec.Option) error
+		kind   codec.ErrorKind
+		path   string
+		offset int64
+	}{
+		{"unknown field", unknown, unmarshalInt, codec.ErrUnknownField, "5", 2},
+		{"overflow", overflow, unmarshalInt, codec.ErrTypeMismatch, "value", 0},
+		{"duplicate key", append(mapEntry("a", 1), mapEntry("a", 2)...), unmarshalMap, codec.ErrDuplicateKey, "values.a", 7},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.decode(tt.data, codec.WithStrict())
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			if de.Kind != tt.kind || de.Path != tt.path || de.Offset != tt.offset {
+				t.Errorf("expected %s at %q (offset %d), got %+v", tt.kind, tt.path, tt.offset, de)
+			}
+
+			if err := tt.decode(tt.data); err != nil {
+				t.Errorf("lenient Unmarshal failed: %v", err)
+			}
+		})
+	}
+}
+
+func TestStrict_Decoder(t *testing.T) {
+	var buf bytes.Buffer
+	for _, key := range []string{"a", "b"} {
+		if _, err := protodelim.MarshalTo(&buf, &testdata.IntMap{Values: map[string]int32{key: 1}}); err != nil {
+			t.Fatalf("MarshalTo failed: %v", err)
+		}
+	}
+	entries := append(mapEntry("a", 1), mapEntry("a", 2)...)
+	buf.Write(protowire.AppendVarint(nil, uint64(len(entries))))
+	buf.Write(entries)
+
+	dec := New[*testdata.IntMap](codec.WithStrict()).NewDecoder(&buf)
+	for _, key := range []string{"a", "b"} {
+		msg := &testdata.IntMap{}
+		if err := dec.Decode(&msg); err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		if msg.Values[key] != 1 {
+			t.Errorf("unexpected message: %v", msg)
+		}
+	}
+
+	msg := &testdata.IntMap{}
+	if err := dec.Decode(&msg); !errors.Is(err, codec.ErrDuplicateKey) {
+		t.Errorf("expected duplicate key error, got %v", err)
+	}
+}
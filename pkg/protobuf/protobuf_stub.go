@@ -18,10 +18,15 @@ type ProtoMessage interface{}
 type Codec[T ProtoMessage] struct{}
 
 // New returns a Protocol Buffers codec stub that will error on all operations.
-func New[T ProtoMessage]() *Codec[T] {
+func New[T ProtoMessage](opts ...codec.Option) *Codec[T] {
 	return &Codec[T]{}
 }
 
+// Err returns an error indicating Protocol Buffers codec is not supported.
+func (c *Codec[T]) Err() error {
+	return errNotSupported
+}
+
 // Encode returns an error indicating Protocol Buffers codec is not supported.
 func (c *Codec[T]) Encode(w io.Writer, data T) error {
 	return errNotSupported
@@ -41,3 +46,56 @@ func (c *Codec[T]) Marshal(data T) ([]byte, error) {
 func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
 	return errNotSupported
 }
+
+// Encoder is a stub for the Protocol Buffers encoder session.
+type Encoder[T ProtoMessage] struct{}
+
+// Decoder is a stub for the Protocol Buffers decoder session.
+type Decoder[T ProtoMessage] struct{}
+
+// NewEncoder returns a Protocol Buffers encoder stub that will error on all operations.
+func (c *Codec[T]) NewEncoder(w io.Writer) codec.Encoder[T] {
+	return &Encoder[T]{}
+}
+
+// NewDecoder returns a Protocol Buffers decoder stub that will error on all operations.
+func (c *Codec[T]) NewDecoder(r io.Reader) codec.Decoder[T] {
+	return &Decoder[T]{}
+}
+
+// Encode returns an error indicating Protocol Buffers codec is not supported.
+func (e *Encoder[T]) Encode(data T) error {
+	return errNotSupported
+}
+
+// Decode returns an error indicating Protocol Buffers codec is not supported.
+func (d *Decoder[T]) Decode(data *T) error {
+	return errNotSupported
+}
+
+// OptimizedCodec is a stub for the optimized Protocol Buffers codec.
+type OptimizedCodec[T ProtoMessage] struct {
+	*Codec[T]
+}
+
+// NewPool returns an optimized Protocol Buffers codec stub that will error on all operations.
+func NewPool[T ProtoMessage](opts ...codec.Option) *OptimizedCodec[T] {
+	return &OptimizedCodec[T]{
+		Codec: New[T](opts...),
+	}
+}
+
+// MarshalTo returns an error indicating Protocol Buffers codec is not supported.
+func (c *OptimizedCodec[T]) MarshalTo(buf []byte, data T) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+// AppendMarshal returns an error indicating Protocol Buffers codec is not supported.
+func (c *OptimizedCodec[T]) AppendMarshal(buf []byte, data T) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+// UnmarshalFrom returns an error indicating Protocol Buffers codec is not supported.
+func (c *OptimizedCodec[T]) UnmarshalFrom(data []byte, v *T, scratch []byte) error {
+	return errNotSupported
+}
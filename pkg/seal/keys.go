@@ -0,0 +1,89 @@
+package seal
+
+import (
+	"fmt"
+	"sync"
+)
+
+// KeySize is the length of the keys used by every Algorithm
+const KeySize = 32
+
+// KeyProvider supplies the keys used to seal and open values. Values are
+// sealed with the current key and opened with the key named in their
+// header, so keys can be rotated while values sealed with earlier keys
+// remain readable.
+type KeyProvider interface {
+	// CurrentKey returns the ID and the key used to seal new values
+	CurrentKey() (id string, key []byte, err error)
+
+	// Key returns the key with the given ID
+	Key(id string) ([]byte, error)
+}
+
+// ErrUnknownKey is returned by a Keyring for a key ID it does not hold
+type ErrUnknownKey struct {
+	KeyID string
+}
+
+func (e ErrUnknownKey) Error() string {
+	return fmt.Sprintf("seal: unknown key %q", e.KeyID)
+}
+
+// Keyring is a KeyProvider holding keys in memory. It is safe for
+// concurrent use.
+type Keyring struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string][]byte
+}
+
+// NewKeyring returns a Keyring whose current key is key, identified by id
+func NewKeyring(id string, key []byte) *Keyring {
+	return &Keyring{current: id, keys: map[string][]byte{id: key}}
+}
+
+// Add adds a key that opens values without becoming the current key
+func (k *Keyring) Add(id string, key []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[id] = key
+}
+
+// Rotate adds a key and makes it the current key. Earlier keys are kept to
+// open the values sealed with them until they are removed.
+func (k *Keyring) Rotate(id string, key []byte) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[id] = key
+	k.current = id
+}
+
+// Remove removes a key. Removing the current key leaves the Keyring unable
+// to seal until another key is rotated in.
+func (k *Keyring) Remove(id string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.keys, id)
+}
+
+// CurrentKey returns the ID and the key used to seal new values
+func (k *Keyring) CurrentKey() (string, []byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[k.current]
+	if !ok {
+		return "", nil, ErrUnknownKey{KeyID: k.current}
+	}
+	return k.current, key, nil
+}
+
+// Key returns the key with the given ID
+func (k *Keyring) Key(id string) ([]byte, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[id]
+	if !ok {
+		return nil, ErrUnknownKey{KeyID: id}
+	}
+	return key, nil
+}
@@ -0,0 +1,17 @@
+package seal
+
+import codec "github.com/jeremyhahn/go-codec"
+
+// config holds the settings applied by seal options
+type config struct {
+	associatedData []byte
+}
+
+// WithAssociatedData authenticates data alongside every value without
+// storing it, binding sealed values to a context such as a cache key.
+// Values only open with the same associated data they were sealed with.
+func WithAssociatedData(data []byte) codec.Option {
+	return codec.NewOption("seal.WithAssociatedData", func(c *config) {
+		c.associatedData = data
+	})
+}
@@ -0,0 +1,400 @@
+// Package seal wraps a codec so that its output is encrypted and
+// authenticated with AES-256-GCM or ChaCha20-Poly1305. Every value is
+// written as a versioned envelope naming the algorithm and the key that
+// sealed it, so keys can be rotated through a KeyProvider while earlier
+// values remain readable:
+//
+//	keys := seal.NewKeyring("2024-01", key)
+//	c := seal.Wrap[Session](json.New[Session](), seal.AES256GCM, keys)
+//	data, err := c.Marshal(session)
+//
+// The envelope is laid out as follows, and everything before the ciphertext
+// is authenticated along with it:
+//
+//	magic "GCE" | version | algorithm | key ID length | key ID | nonce (12) | ciphertext length (uint32) | ciphertext
+//
+// Values that fail authentication, because they were modified or sealed
+// with another key or associated data, are rejected with
+// ErrAuthenticationFailed.
+package seal
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"golang.org/x/crypto/chacha20poly1305"
+)
+
+const (
+	// version is the envelope version written by this package
+	version = 1
+
+	// prefixSize is the length of the fixed part of the header: the magic
+	// bytes, version, algorithm and key ID length
+	prefixSize = 6
+
+	// nonceSize is the nonce length of every Algorithm
+	nonceSize = 12
+
+	// maxKeyIDLength is the longest key ID that fits in the header
+	maxKeyIDLength = math.MaxUint8
+)
+
+// magic identifies data written by a Codec
+var magic = [3]byte{'G', 'C', 'E'}
+
+// errorCodec names the wrapper in the errors it reports
+const errorCodec codec.Type = "seal"
+
+// ErrInvalidHeader is reported, wrapped in a codec.DecodeError, for input
+// that does not start with the envelope header written by a Codec
+var ErrInvalidHeader = errors.New("seal: missing or invalid header")
+
+// Algorithm identifies an AEAD cipher. Its value is stored in the envelope
+// header.
+type Algorithm byte
+
+const (
+	// AES256GCM encrypts with AES-256 in Galois/Counter Mode
+	AES256GCM Algorithm = iota + 1
+	// ChaCha20Poly1305 encrypts with ChaCha20-Poly1305 as defined in RFC 8439
+	ChaCha20Poly1305
+)
+
+// String returns the name of the algorithm, e.g. "AES-256-GCM"
+func (a Algorithm) String() string {
+	switch a {
+	case AES256GCM:
+		return "AES-256-GCM"
+	case ChaCha20Poly1305:
+		return "ChaCha20-Poly1305"
+	default:
+		return fmt.Sprintf("Algorithm(%d)", byte(a))
+	}
+}
+
+// ErrAlgorithmNotSupported is returned for an unknown algorithm
+type ErrAlgorithmNotSupported struct {
+	Algorithm Algorithm
+}
+
+func (e ErrAlgorithmNotSupported) Error() string {
+	return fmt.Sprintf("seal: algorithm %s is not supported", e.Algorithm)
+}
+
+// ErrAuthenticationFailed is reported, wrapped in a codec.DecodeError, for a
+// value whose ciphertext or header was modified, or that was sealed with
+// another key or other associated data
+type ErrAuthenticationFailed struct {
+	KeyID string
+}
+
+func (e ErrAuthenticationFailed) Error() string {
+	return fmt.Sprintf("seal: message authentication failed with key %q", e.KeyID)
+}
+
+// envelope is the parsed header of a sealed value
+type envelope struct {
+	algo   Algorithm
+	keyID  string
+	nonce  []byte
+	length uint32
+}
+
+// Codec encrypts the output of another codec and decrypts its input
+type Codec[T any] struct {
+	inner codec.Codec[T]
+	algo  Algorithm
+	keys  KeyProvider
+	cfg   config
+	err   error
+}
+
+// Wrap returns a codec that encodes values with inner and seals the result
+// with algo and the current key of keys. Opening uses the algorithm and key
+// named in the header, so values sealed before a rotation or with the other
+// algorithm remain readable. If inner reports an error through an Err
+// method, an option does not apply to sealing, keys is nil or algo is
+// unknown, every operation returns the error, which is also reported by
+// Err.
+func Wrap[T any](inner codec.Codec[T], algo Algorithm, keys KeyProvider, opts ...codec.Option) *Codec[T] {
+	c := &Codec[T]{inner: inner, algo: algo, keys: keys}
+	if e, ok := inner.(interface{ Err() error }); ok {
+		if c.err = e.Err(); c.err != nil {
+			return c
+		}
+	}
+	if c.err = codec.ApplyOptions(errorCodec, &c.cfg, opts); c.err != nil {
+		return c
+	}
+	switch {
+	case keys == nil:
+		c.err = errors.New("seal: nil KeyProvider")
+	case algo != AES256GCM && algo != ChaCha20Poly1305:
+		c.err = ErrAlgorithmNotSupported{Algorithm: algo}
+	}
+	return c
+}
+
+// Err returns the error, if any, caused by the arguments passed to Wrap
+func (c *Codec[T]) Err() error {
+	return c.err
+}
+
+// Algorithm returns the algorithm used to seal values
+func (c *Codec[T]) Algorithm() Algorithm {
+	return c.algo
+}
+
+// Encode writes the sealed encoding of data to w. The value is encoded in
+// full before it is encrypted.
+func (c *Codec[T]) Encode(w io.Writer, data T) error {
+	out, err := c.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// Decode reads one sealed value from r and decodes it into data with the
+// inner codec. The length stored in the header keeps it from reading past
+// the end of the value, so consecutive values can be decoded from a stream.
+func (c *Codec[T]) Decode(r io.Reader, data *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	hdr := make([]byte, prefixSize, prefixSize+maxKeyIDLength+nonceSize+4)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		if err == io.EOF {
+			return err
+		}
+		return decodeError(err)
+	}
+	idLen, err := parsePrefix(hdr)
+	if err != nil {
+		return err
+	}
+	hdr = hdr[:headerSize(idLen)]
+	if _, err := io.ReadFull(r, hdr[prefixSize:]); err != nil {
+		return decodeError(err)
+	}
+	env, n, err := parseHeader(hdr)
+	if err != nil {
+		return err
+	}
+
+	// The ciphertext grows as it arrives rather than being allocated from
+	// the untrusted length up front
+	var body bytes.Buffer
+	if _, err := body.ReadFrom(io.LimitReader(r, int64(env.length))); err != nil {
+		return decodeError(err)
+	}
+	if body.Len() < int(env.length) {
+		return decodeError(io.ErrUnexpectedEOF)
+	}
+	raw, err := c.open(hdr[:n], env, body.Bytes())
+	if err != nil {
+		return err
+	}
+	return c.inner.Decode(bytes.NewReader(raw), data)
+}
+
+// Marshal encodes data with the inner codec and seals the result with the
+// current key
+func (c *Codec[T]) Marshal(data T) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	raw, err := c.inner.Marshal(data)
+	if err != nil {
+		return nil, err
+	}
+	return c.seal(raw)
+}
+
+// Unmarshal opens data with the key named in its header and decodes the
+// result into v with the inner codec
+func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	env, n, err := parseHeader(data)
+	if err != nil {
+		return err
+	}
+	body := data[n:]
+	switch {
+	case len(body) < int(env.length):
+		return decodeError(io.ErrUnexpectedEOF)
+	case len(body) > int(env.length):
+		return codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: int64(n) + int64(env.length),
+			Err:    errors.New("seal: trailing data after envelope"),
+		}
+	}
+	raw, err := c.open(data[:n], env, body)
+	if err != nil {
+		return err
+	}
+	return c.inner.Unmarshal(raw, v)
+}
+
+// seal encrypts raw with the current key into a new envelope
+func (c *Codec[T]) seal(raw []byte) ([]byte, error) {
+	id, key, err := c.keys.CurrentKey()
+	if err != nil {
+		return nil, err
+	}
+	if len(id) > maxKeyIDLength {
+		return nil, fmt.Errorf("seal: key ID %q is longer than %d bytes", id, maxKeyIDLength)
+	}
+	aead, err := newAEAD(c.algo, id, key)
+	if err != nil {
+		return nil, err
+	}
+	size := len(raw) + aead.Overhead()
+	if size > math.MaxUint32 {
+		return nil, fmt.Errorf("seal: value of %d bytes is too large", len(raw))
+	}
+
+	n := headerSize(len(id))
+	out := make([]byte, n, n+size)
+	copy(out, magic[:])
+	out[3] = version
+	out[4] = byte(c.algo)
+	out[5] = byte(len(id))
+	copy(out[prefixSize:], id)
+	nonce := out[prefixSize+len(id) : prefixSize+len(id)+nonceSize]
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	binary.BigEndian.PutUint32(out[n-4:], uint32(size))
+	return aead.Seal(out, nonce, raw, c.additionalData(out)), nil
+}
+
+// open authenticates and decrypts the ciphertext of the envelope whose
+// header is hdr
+func (c *Codec[T]) open(hdr []byte, env envelope, ciphertext []byte) ([]byte, error) {
+	key, err := c.keys.Key(env.keyID)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := newAEAD(env.algo, env.keyID, key)
+	if err != nil {
+		return nil, err
+	}
+	raw, err := aead.Open(nil, env.nonce, ciphertext, c.additionalData(hdr))
+	if err != nil {
+		return nil, codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: int64(len(hdr)),
+			Err:    ErrAuthenticationFailed{KeyID: env.keyID},
+		}
+	}
+	return raw, nil
+}
+
+// additionalData returns the data authenticated with the ciphertext: the
+// header followed by the configured associated data
+func (c *Codec[T]) additionalData(hdr []byte) []byte {
+	if len(c.cfg.associatedData) == 0 {
+		return hdr
+	}
+	return append(hdr[:len(hdr):len(hdr)], c.cfg.associatedData...)
+}
+
+// newAEAD returns the cipher of algo keyed with the key identified by id
+func newAEAD(algo Algorithm, id string, key []byte) (cipher.AEAD, error) {
+	if len(key) != KeySize {
+		return nil, fmt.Errorf("seal: key %q is %d bytes, want %d", id, len(key), KeySize)
+	}
+	switch algo {
+	case AES256GCM:
+		block, err := aes.NewCipher(key)
+		if err != nil {
+			return nil, err
+		}
+		return cipher.NewGCM(block)
+	case ChaCha20Poly1305:
+		return chacha20poly1305.New(key)
+	default:
+		return nil, ErrAlgorithmNotSupported{Algorithm: algo}
+	}
+}
+
+// headerSize returns the length of a header holding a key ID of idLen bytes
+func headerSize(idLen int) int {
+	return prefixSize + idLen + nonceSize + 4
+}
+
+// parsePrefix checks the fixed part of a header and returns the length of
+// its key ID
+func parsePrefix(data []byte) (int, error) {
+	if !bytes.HasPrefix(data, magic[:]) {
+		if len(data) < len(magic) && bytes.HasPrefix(magic[:], data) {
+			return 0, decodeError(io.ErrUnexpectedEOF)
+		}
+		return 0, codec.DecodeError{Codec: errorCodec, Kind: codec.ErrSyntax, Offset: 0, Err: ErrInvalidHeader}
+	}
+	if len(data) < prefixSize {
+		return 0, decodeError(io.ErrUnexpectedEOF)
+	}
+	if data[3] != version {
+		return 0, codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: 3,
+			Err:    fmt.Errorf("seal: unsupported envelope version %d", data[3]),
+		}
+	}
+	if algo := Algorithm(data[4]); algo != AES256GCM && algo != ChaCha20Poly1305 {
+		return 0, codec.DecodeError{Codec: errorCodec, Kind: codec.ErrSyntax, Offset: 4, Err: ErrAlgorithmNotSupported{Algorithm: algo}}
+	}
+	return int(data[5]), nil
+}
+
+// parseHeader parses the header at the start of data and returns it with
+// its length
+func parseHeader(data []byte) (envelope, int, error) {
+	idLen, err := parsePrefix(data)
+	if err != nil {
+		return envelope{}, 0, err
+	}
+	n := headerSize(idLen)
+	if len(data) < n {
+		return envelope{}, 0, decodeError(io.ErrUnexpectedEOF)
+	}
+	env := envelope{
+		algo:   Algorithm(data[4]),
+		keyID:  string(data[prefixSize : prefixSize+idLen]),
+		nonce:  data[prefixSize+idLen : n-4],
+		length: binary.BigEndian.Uint32(data[n-4:]),
+	}
+	return env, n, nil
+}
+
+// decodeError wraps a read failure in a codec.DecodeError, leaving errors
+// that already are one unchanged
+func decodeError(err error) error {
+	var de codec.DecodeError
+	if errors.As(err, &de) {
+		return err
+	}
+	kind := codec.ErrSyntax
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		kind = codec.ErrTruncated
+	}
+	return codec.DecodeError{Codec: errorCodec, Kind: kind, Offset: -1, Err: err}
+}
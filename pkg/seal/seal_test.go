@@ -0,0 +1,260 @@
+//go:build codec_json
+
+package seal
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"testing"
+
+	codec "github.com/jeremyhahn/go-codec"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+)
+
+type TestStruct struct {
+	Name  string `json:"name"`
+	Age   int    `json:"age"`
+	Email string `json:"email"`
+}
+
+var testData = TestStruct{Name: "John Doe", Age: 30, Email: "john@example.com"}
+
+func testKey(b byte) []byte {
+	return bytes.Repeat([]byte{b}, KeySize)
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	for _, algo := range []Algorithm{AES256GCM, ChaCha20Poly1305} {
+		t.Run(algo.String(), func(t *testing.T) {
+			c := Wrap[TestStruct](jsoncodec.New[TestStruct](), algo, NewKeyring("k1", testKey(1)))
+			if err := c.Err(); err != nil {
+				t.Fatalf("Wrap failed: %v", err)
+			}
+
+			data, err := c.Marshal(testData)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			if bytes.Contains(data, []byte("John Doe")) {
+				t.Error("sealed data contains the plaintext")
+			}
+			var decoded TestStruct
+			if err := c.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if decoded != testData {
+				t.Errorf("Unmarshal = %+v, want %+v", decoded, testData)
+			}
+
+			// Sealing twice uses different nonces
+			again, _ := c.Marshal(testData)
+			if bytes.Equal(data, again) {
+				t.Error("two values were sealed identically")
+			}
+		})
+	}
+}
+
+func TestCodec_EncodeDecodeStream(t *testing.T) {
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), ChaCha20Poly1305, NewKeyring("k1", testKey(1)))
+	values := []TestStruct{testData, {Name: "Jane"}, {Age: 7}}
+
+	var buf bytes.Buffer
+	for _, v := range values {
+		if err := c.Encode(&buf, v); err != nil {
+			t.Fatalf("Encode failed: %v", err)
+		}
+	}
+	for i, want := range values {
+		var got TestStruct
+		if err := c.Decode(&buf, &got); err != nil {
+			t.Fatalf("Decode %d failed: %v", i, err)
+		}
+		if got != want {
+			t.Errorf("Decode %d = %+v, want %+v", i, got, want)
+		}
+	}
+	var v TestStruct
+	if err := c.Decode(&buf, &v); err != io.EOF {
+		t.Errorf("Decode at end = %v, want io.EOF", err)
+	}
+}
+
+func TestCodec_KeyRotation(t *testing.T) {
+	keys := NewKeyring("2024-01", testKey(1))
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), AES256GCM, keys)
+	old, _ := c.Marshal(testData)
+
+	keys.Rotate("2024-02", testKey(2))
+	current, _ := c.Marshal(testData)
+	if !bytes.Contains(current, []byte("2024-02")) {
+		t.Error("the rotated key was not used")
+	}
+
+	// A codec with the other algorithm opens both values
+	reader := Wrap[TestStruct](jsoncodec.New[TestStruct](), ChaCha20Poly1305, keys)
+	for _, data := range [][]byte{old, current} {
+		var v TestStruct
+		if err := reader.Unmarshal(data, &v); err != nil || v != testData {
+			t.Errorf("Unmarshal = %+v, %v", v, err)
+		}
+	}
+
+	keys.Remove("2024-01")
+	var v TestStruct
+	var unknown ErrUnknownKey
+	if err := c.Unmarshal(old, &v); !errors.As(err, &unknown) || unknown.KeyID != "2024-01" {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+	keys.Remove("2024-02")
+	if _, err := c.Marshal(testData); !errors.As(err, &unknown) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestCodec_Tampered(t *testing.T) {
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), AES256GCM, NewKeyring("k1", testKey(1)))
+	data, _ := c.Marshal(testData)
+
+	// Flipping any bit after the algorithm fails authentication, except in
+	// the key ID and length, which fail to find the key or the data
+	for i := prefixSize + 2; i < len(data); i++ {
+		if i >= headerSize(2)-4 && i < headerSize(2) {
+			continue
+		}
+		tampered := bytes.Clone(data)
+		tampered[i] ^= 0x01
+		var v TestStruct
+		var authErr ErrAuthenticationFailed
+		if err := c.Unmarshal(tampered, &v); !errors.As(err, &authErr) || authErr.KeyID != "k1" {
+			t.Fatalf("byte %d: expected ErrAuthenticationFailed, got %v", i, err)
+		}
+	}
+
+	// Another key with the same ID
+	other := Wrap[TestStruct](jsoncodec.New[TestStruct](), AES256GCM, NewKeyring("k1", testKey(2)))
+	var v TestStruct
+	if err := other.Unmarshal(data, &v); !errors.As(err, new(ErrAuthenticationFailed)) {
+		t.Errorf("expected ErrAuthenticationFailed, got %v", err)
+	}
+
+	// Changing the algorithm in the header
+	swapped := bytes.Clone(data)
+	swapped[4] = byte(ChaCha20Poly1305)
+	if err := c.Unmarshal(swapped, &v); !errors.As(err, new(ErrAuthenticationFailed)) {
+		t.Errorf("expected ErrAuthenticationFailed, got %v", err)
+	}
+}
+
+func TestCodec_AssociatedData(t *testing.T) {
+	keys := NewKeyring("k1", testKey(1))
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), AES256GCM, keys, WithAssociatedData([]byte("user:1")))
+	data, err := c.Marshal(testData)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	var v TestStruct
+	if err := c.Unmarshal(data, &v); err != nil || v != testData {
+		t.Errorf("Unmarshal = %+v, %v", v, err)
+	}
+	for _, opts := range [][]codec.Option{nil, {WithAssociatedData([]byte("user:2"))}} {
+		other := Wrap[TestStruct](jsoncodec.New[TestStruct](), AES256GCM, keys, opts...)
+		if err := other.Unmarshal(data, &v); !errors.As(err, new(ErrAuthenticationFailed)) {
+			t.Errorf("expected ErrAuthenticationFailed, got %v", err)
+		}
+	}
+}
+
+func TestCodec_InvalidInput(t *testing.T) {
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), AES256GCM, NewKeyring("k1", testKey(1)))
+	valid, _ := c.Marshal(testData)
+
+	badVersion := bytes.Clone(valid)
+	badVersion[3] = 9
+	badAlgo := bytes.Clone(valid)
+	badAlgo[4] = 9
+
+	for _, tt := range []struct {
+		name string
+		data []byte
+		kind codec.ErrorKind
+	}{
+		{"no header", []byte(`{"name":"x"}`), codec.ErrSyntax},
+		{"short magic", []byte("GC"), codec.ErrTruncated},
+		{"short prefix", valid[:4], codec.ErrTruncated},
+		{"short header", valid[:prefixSize+3], codec.ErrTruncated},
+		{"truncated ciphertext", valid[:len(valid)-1], codec.ErrTruncated},
+		{"trailing data", append(bytes.Clone(valid), 0), codec.ErrSyntax},
+		{"version", badVersion, codec.ErrSyntax},
+		{"algorithm", badAlgo, codec.ErrSyntax},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var v TestStruct
+			var de codec.DecodeError
+			if err := c.Unmarshal(tt.data, &v); !errors.As(err, &de) || de.Kind != tt.kind {
+				t.Errorf("Unmarshal error = %v, want %s", err, tt.kind)
+			}
+			if tt.name == "trailing data" {
+				return
+			}
+			if err := c.Decode(bytes.NewReader(tt.data), &v); !errors.As(err, &de) || de.Kind != tt.kind {
+				t.Errorf("Decode error = %v, want %s", err, tt.kind)
+			}
+		})
+	}
+
+	var v TestStruct
+	if err := c.Unmarshal([]byte("xyz123"), &v); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("expected ErrInvalidHeader, got %v", err)
+	}
+}
+
+func TestWrap_Errors(t *testing.T) {
+	keys := NewKeyring("k1", testKey(1))
+
+	var notSupported codec.ErrOptionNotSupported
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), AES256GCM, keys, jsoncodec.WithIndent("", "  "))
+	if !errors.As(c.Err(), &notSupported) {
+		t.Errorf("expected ErrOptionNotSupported, got %v", c.Err())
+	}
+	if _, err := c.Marshal(testData); err != c.Err() {
+		t.Errorf("Marshal error = %v, want %v", err, c.Err())
+	}
+
+	c = Wrap[TestStruct](jsoncodec.New[TestStruct](codec.NewOption("other.WithThing", func(*struct{}) {})), AES256GCM, keys)
+	if !errors.As(c.Err(), &notSupported) {
+		t.Errorf("expected the inner codec's error, got %v", c.Err())
+	}
+
+	if c = Wrap[TestStruct](jsoncodec.New[TestStruct](), Algorithm(9), keys); !errors.As(c.Err(), new(ErrAlgorithmNotSupported)) {
+		t.Errorf("expected ErrAlgorithmNotSupported, got %v", c.Err())
+	}
+	if c = Wrap[TestStruct](jsoncodec.New[TestStruct](), AES256GCM, nil); c.Err() == nil {
+		t.Error("expected an error for a nil KeyProvider")
+	}
+
+	c = Wrap[TestStruct](jsoncodec.New[TestStruct](), AES256GCM, NewKeyring("short", []byte("16 byte key 1234")))
+	if _, err := c.Marshal(testData); err == nil {
+		t.Error("expected an error for a 16-byte key")
+	}
+	c = Wrap[TestStruct](jsoncodec.New[TestStruct](), AES256GCM, NewKeyring(string(make([]byte, 256)), testKey(1)))
+	if _, err := c.Marshal(testData); err == nil {
+		t.Error("expected an error for a 256-byte key ID")
+	}
+}
+
+func BenchmarkCodec_Marshal(b *testing.B) {
+	for _, algo := range []Algorithm{AES256GCM, ChaCha20Poly1305} {
+		b.Run(algo.String(), func(b *testing.B) {
+			c := Wrap[TestStruct](jsoncodec.New[TestStruct](), algo, NewKeyring("k1", testKey(1)))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.Marshal(testData); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,167 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MinHMACKeySize is the shortest HMAC-SHA256 secret accepted, the size of
+// the hash output as required by RFC 2104 and RFC 7518
+const MinHMACKeySize = sha256.Size
+
+// Key is a signing or verification key together with its algorithm. Create
+// one with HMACKey, Ed25519PrivateKey or Ed25519PublicKey.
+type Key struct {
+	algo    Algorithm
+	secret  []byte
+	private ed25519.PrivateKey
+	public  ed25519.PublicKey
+}
+
+// HMACKey returns a key that signs and verifies with HMAC-SHA256. The secret
+// must be at least MinHMACKeySize bytes.
+func HMACKey(secret []byte) Key {
+	return Key{algo: HMACSHA256, secret: secret}
+}
+
+// Ed25519PrivateKey returns a key that signs with priv and verifies with its
+// public key
+func Ed25519PrivateKey(priv ed25519.PrivateKey) Key {
+	k := Key{algo: Ed25519, private: priv}
+	if len(priv) == ed25519.PrivateKeySize {
+		k.public = priv.Public().(ed25519.PublicKey)
+	}
+	return k
+}
+
+// Ed25519PublicKey returns a key that only verifies signatures, for parties
+// that receive signed values without producing them
+func Ed25519PublicKey(pub ed25519.PublicKey) Key {
+	return Key{algo: Ed25519, public: pub}
+}
+
+// Algorithm returns the algorithm of the key
+func (k Key) Algorithm() Algorithm {
+	return k.algo
+}
+
+// sign returns the signature of msg
+func (k Key) sign(msg []byte) ([]byte, error) {
+	switch k.algo {
+	case HMACSHA256:
+		if len(k.secret) < MinHMACKeySize {
+			return nil, fmt.Errorf("sign: HMAC key of %d bytes is shorter than %d", len(k.secret), MinHMACKeySize)
+		}
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(msg)
+		return mac.Sum(nil), nil
+	case Ed25519:
+		if len(k.private) != ed25519.PrivateKeySize {
+			return nil, errors.New("sign: key cannot sign without an Ed25519 private key")
+		}
+		return ed25519.Sign(k.private, msg), nil
+	default:
+		return nil, ErrAlgorithmNotSupported{Algorithm: k.algo}
+	}
+}
+
+// verify reports whether sig is a valid signature of msg
+func (k Key) verify(msg, sig []byte) bool {
+	switch k.algo {
+	case HMACSHA256:
+		if len(k.secret) < MinHMACKeySize {
+			return false
+		}
+		mac := hmac.New(sha256.New, k.secret)
+		mac.Write(msg)
+		return hmac.Equal(mac.Sum(nil), sig)
+	case Ed25519:
+		return len(k.public) == ed25519.PublicKeySize && ed25519.Verify(k.public, msg, sig)
+	default:
+		return false
+	}
+}
+
+// KeyResolver supplies the keys used to sign and verify values. Values are
+// signed with the signing key and verified with the key named by their
+// signature, so keys can be rotated while values signed with earlier keys
+// remain verifiable.
+type KeyResolver interface {
+	// SigningKey returns the ID and the key used to sign new values
+	SigningKey() (id string, key Key, err error)
+
+	// VerificationKey returns the key with the given ID
+	VerificationKey(id string) (Key, error)
+}
+
+// ErrUnknownKey is returned by a Keyring for a key ID it does not hold
+type ErrUnknownKey struct {
+	KeyID string
+}
+
+func (e ErrUnknownKey) Error() string {
+	return fmt.Sprintf("sign: unknown key %q", e.KeyID)
+}
+
+// Keyring is a KeyResolver holding keys in memory. It is safe for
+// concurrent use.
+type Keyring struct {
+	mu      sync.RWMutex
+	current string
+	keys    map[string]Key
+}
+
+// NewKeyring returns a Keyring whose signing key is key, identified by id
+func NewKeyring(id string, key Key) *Keyring {
+	return &Keyring{current: id, keys: map[string]Key{id: key}}
+}
+
+// Add adds a key that verifies values without becoming the signing key
+func (k *Keyring) Add(id string, key Key) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[id] = key
+}
+
+// Rotate adds a key and makes it the signing key. Earlier keys are kept to
+// verify the values signed with them until they are removed.
+func (k *Keyring) Rotate(id string, key Key) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	k.keys[id] = key
+	k.current = id
+}
+
+// Remove removes a key. Removing the signing key leaves the Keyring unable
+// to sign until another key is rotated in.
+func (k *Keyring) Remove(id string) {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	delete(k.keys, id)
+}
+
+// SigningKey returns the ID and the key used to sign new values
+func (k *Keyring) SigningKey() (string, Key, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[k.current]
+	if !ok {
+		return "", Key{}, ErrUnknownKey{KeyID: k.current}
+	}
+	return k.current, key, nil
+}
+
+// VerificationKey returns the key with the given ID
+func (k *Keyring) VerificationKey(id string) (Key, error) {
+	k.mu.RLock()
+	defer k.mu.RUnlock()
+	key, ok := k.keys[id]
+	if !ok {
+		return Key{}, ErrUnknownKey{KeyID: id}
+	}
+	return key, nil
+}
@@ -0,0 +1,17 @@
+package sign
+
+import codec "github.com/jeremyhahn/go-codec"
+
+// config holds the settings applied by signing options
+type config struct {
+	maxSize int64
+}
+
+// WithMaxSize rejects signed values whose payload is longer than n bytes
+// before reading or verifying it. Exceeding it is reported as a
+// codec.DecodeError of kind codec.ErrLimitExceeded.
+func WithMaxSize(n int64) codec.Option {
+	return codec.NewOption("sign.WithMaxSize", func(c *config) {
+		c.maxSize = n
+	})
+}
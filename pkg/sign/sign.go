@@ -0,0 +1,356 @@
+// Package sign wraps a codec so that its output is signed with HMAC-SHA256
+// or Ed25519 and verified before it is decoded. Signatures are computed
+// over the exact bytes produced by the inner codec, and name the key that
+// made them, so keys can be rotated through a KeyResolver:
+//
+//	keys := sign.NewKeyring("2024-01", sign.HMACKey(secret))
+//	c := sign.Wrap[Event](json.New[Event](), keys)
+//	data, err := c.Marshal(event)
+//
+// Marshal embeds the payload and its signature in an envelope:
+//
+//	magic "GCS" | version | algorithm | key ID length | key ID | payload length (uint32) | payload | signature
+//
+// MarshalDetached instead returns the payload untouched alongside a
+// Signature, for protocols such as webhooks that carry the signature
+// separately. Values whose signature does not verify are rejected with
+// ErrInvalidSignature before the inner codec sees them.
+package sign
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+const (
+	// version is the envelope version written by this package
+	version = 1
+
+	// prefixSize is the length of the fixed part of the header: the magic
+	// bytes, version, algorithm and key ID length
+	prefixSize = 6
+
+	// maxKeyIDLength is the longest key ID that fits in the header
+	maxKeyIDLength = math.MaxUint8
+)
+
+// magic identifies data written by a Codec
+var magic = [3]byte{'G', 'C', 'S'}
+
+// errorCodec names the wrapper in the errors it reports
+const errorCodec codec.Type = "sign"
+
+// ErrInvalidHeader is reported, wrapped in a codec.DecodeError, for input
+// that does not start with the envelope header written by a Codec
+var ErrInvalidHeader = errors.New("sign: missing or invalid header")
+
+// ErrInvalidSignature is reported, wrapped in a codec.DecodeError, for a
+// value whose payload or signature was modified, or whose signature was
+// made with another key or algorithm than the key named by KeyID
+type ErrInvalidSignature struct {
+	KeyID     string
+	Algorithm Algorithm
+}
+
+func (e ErrInvalidSignature) Error() string {
+	return fmt.Sprintf("sign: invalid %s signature with key %q", e.Algorithm, e.KeyID)
+}
+
+// Codec signs the output of another codec and verifies its input
+type Codec[T any] struct {
+	inner codec.Codec[T]
+	keys  KeyResolver
+	cfg   config
+	err   error
+}
+
+// Wrap returns a codec that encodes values with inner and signs the result
+// with the signing key of keys. If inner reports an error through an Err
+// method, an option does not apply to signing or keys is nil, every
+// operation returns the error, which is also reported by Err.
+func Wrap[T any](inner codec.Codec[T], keys KeyResolver, opts ...codec.Option) *Codec[T] {
+	c := &Codec[T]{inner: inner, keys: keys}
+	if e, ok := inner.(interface{ Err() error }); ok {
+		if c.err = e.Err(); c.err != nil {
+			return c
+		}
+	}
+	if c.err = codec.ApplyOptions(errorCodec, &c.cfg, opts); c.err != nil {
+		return c
+	}
+	if keys == nil {
+		c.err = errors.New("sign: nil KeyResolver")
+	}
+	return c
+}
+
+// Err returns the error, if any, caused by the arguments passed to Wrap
+func (c *Codec[T]) Err() error {
+	return c.err
+}
+
+// Encode writes the signed envelope of data to w. The value is encoded in
+// full before it is signed.
+func (c *Codec[T]) Encode(w io.Writer, data T) error {
+	out, err := c.Marshal(data)
+	if err != nil {
+		return err
+	}
+	_, err = w.Write(out)
+	return err
+}
+
+// Decode reads one signed envelope from r, verifies it and decodes the
+// payload into data with the inner codec. The lengths stored in the header
+// keep it from reading past the end of the envelope, so consecutive values
+// can be decoded from a stream.
+func (c *Codec[T]) Decode(r io.Reader, data *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	hdr := make([]byte, prefixSize, prefixSize+maxKeyIDLength+4)
+	if _, err := io.ReadFull(r, hdr); err != nil {
+		if err == io.EOF {
+			return err
+		}
+		return decodeError(err)
+	}
+	_, idLen, err := parsePrefix(hdr)
+	if err != nil {
+		return err
+	}
+	hdr = hdr[:headerSize(idLen)]
+	if _, err := io.ReadFull(r, hdr[prefixSize:]); err != nil {
+		return decodeError(err)
+	}
+	env, n, err := parseHeader(hdr)
+	if err != nil {
+		return err
+	}
+	if err := c.checkSize(int64(env.length)); err != nil {
+		return err
+	}
+
+	// The payload grows as it arrives rather than being allocated from the
+	// unverified length up front
+	var body bytes.Buffer
+	size := int64(env.length) + int64(env.sig.Algorithm.size())
+	if _, err := body.ReadFrom(io.LimitReader(r, size)); err != nil {
+		return decodeError(err)
+	}
+	if int64(body.Len()) < size {
+		return decodeError(io.ErrUnexpectedEOF)
+	}
+	payload := body.Bytes()[:env.length]
+	env.sig.Value = body.Bytes()[env.length:]
+	if err := c.verify(payload, env.sig, int64(n)); err != nil {
+		return err
+	}
+	return c.inner.Unmarshal(payload, data)
+}
+
+// Marshal encodes data with the inner codec and returns the payload and its
+// signature in an envelope
+func (c *Codec[T]) Marshal(data T) ([]byte, error) {
+	payload, sig, err := c.MarshalDetached(data)
+	if err != nil {
+		return nil, err
+	}
+	if len(sig.KeyID) > maxKeyIDLength {
+		return nil, fmt.Errorf("sign: key ID %q is longer than %d bytes", sig.KeyID, maxKeyIDLength)
+	}
+	if len(payload) > math.MaxUint32 {
+		return nil, fmt.Errorf("sign: value of %d bytes is too large", len(payload))
+	}
+
+	n := headerSize(len(sig.KeyID))
+	out := make([]byte, n, n+len(payload)+len(sig.Value))
+	copy(out, magic[:])
+	out[3] = version
+	out[4] = byte(sig.Algorithm)
+	out[5] = byte(len(sig.KeyID))
+	copy(out[prefixSize:], sig.KeyID)
+	binary.BigEndian.PutUint32(out[n-4:], uint32(len(payload)))
+	out = append(out, payload...)
+	return append(out, sig.Value...), nil
+}
+
+// Unmarshal verifies the envelope in data and decodes its payload into v
+// with the inner codec
+func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	env, n, err := parseHeader(data)
+	if err != nil {
+		return err
+	}
+	if err := c.checkSize(int64(env.length)); err != nil {
+		return err
+	}
+	body := data[n:]
+	size := int(env.length) + env.sig.Algorithm.size()
+	switch {
+	case len(body) < size:
+		return decodeError(io.ErrUnexpectedEOF)
+	case len(body) > size:
+		return codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: int64(n + size),
+			Err:    errors.New("sign: trailing data after envelope"),
+		}
+	}
+	payload := body[:env.length]
+	env.sig.Value = body[env.length:]
+	if err := c.verify(payload, env.sig, int64(n)); err != nil {
+		return err
+	}
+	return c.inner.Unmarshal(payload, v)
+}
+
+// MarshalDetached encodes data with the inner codec and returns the encoded
+// bytes unchanged together with their signature
+func (c *Codec[T]) MarshalDetached(data T) ([]byte, Signature, error) {
+	if c.err != nil {
+		return nil, Signature{}, c.err
+	}
+	payload, err := c.inner.Marshal(data)
+	if err != nil {
+		return nil, Signature{}, err
+	}
+	id, key, err := c.keys.SigningKey()
+	if err != nil {
+		return nil, Signature{}, err
+	}
+	value, err := key.sign(payload)
+	if err != nil {
+		return nil, Signature{}, err
+	}
+	return payload, Signature{Algorithm: key.algo, KeyID: id, Value: value}, nil
+}
+
+// UnmarshalDetached verifies sig over the exact bytes of data and decodes
+// data into v with the inner codec
+func (c *Codec[T]) UnmarshalDetached(data []byte, sig Signature, v *T) error {
+	if c.err != nil {
+		return c.err
+	}
+	if err := c.checkSize(int64(len(data))); err != nil {
+		return err
+	}
+	if err := c.verify(data, sig, -1); err != nil {
+		return err
+	}
+	return c.inner.Unmarshal(data, v)
+}
+
+// verify checks sig over payload with the key it names. The key's own
+// algorithm must match the signature's, so that a public key is never used
+// as an HMAC secret. offset is the position of the payload in the input,
+// or -1 for detached signatures.
+func (c *Codec[T]) verify(payload []byte, sig Signature, offset int64) error {
+	key, err := c.keys.VerificationKey(sig.KeyID)
+	if err != nil {
+		return err
+	}
+	if key.algo != sig.Algorithm || !key.verify(payload, sig.Value) {
+		return codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: offset,
+			Err:    ErrInvalidSignature{KeyID: sig.KeyID, Algorithm: sig.Algorithm},
+		}
+	}
+	return nil
+}
+
+// checkSize rejects payloads longer than the configured maximum
+func (c *Codec[T]) checkSize(n int64) error {
+	if c.cfg.maxSize > 0 && n > c.cfg.maxSize {
+		return codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrLimitExceeded,
+			Offset: -1,
+			Err:    codec.LimitError{Limit: "MaxBytes", Max: c.cfg.maxSize},
+		}
+	}
+	return nil
+}
+
+// envelope is the parsed header of a signed value. The signature value
+// follows the payload and is filled in once it has been read.
+type envelope struct {
+	sig    Signature
+	length uint32
+}
+
+// headerSize returns the length of a header holding a key ID of idLen bytes
+func headerSize(idLen int) int {
+	return prefixSize + idLen + 4
+}
+
+// parsePrefix checks the fixed part of a header and returns the algorithm
+// and the length of the key ID
+func parsePrefix(data []byte) (Algorithm, int, error) {
+	if !bytes.HasPrefix(data, magic[:]) {
+		if len(data) < len(magic) && bytes.HasPrefix(magic[:], data) {
+			return 0, 0, decodeError(io.ErrUnexpectedEOF)
+		}
+		return 0, 0, codec.DecodeError{Codec: errorCodec, Kind: codec.ErrSyntax, Offset: 0, Err: ErrInvalidHeader}
+	}
+	if len(data) < prefixSize {
+		return 0, 0, decodeError(io.ErrUnexpectedEOF)
+	}
+	if data[3] != version {
+		return 0, 0, codec.DecodeError{
+			Codec:  errorCodec,
+			Kind:   codec.ErrSyntax,
+			Offset: 3,
+			Err:    fmt.Errorf("sign: unsupported envelope version %d", data[3]),
+		}
+	}
+	algo := Algorithm(data[4])
+	if algo.size() == 0 {
+		return 0, 0, codec.DecodeError{Codec: errorCodec, Kind: codec.ErrSyntax, Offset: 4, Err: ErrAlgorithmNotSupported{Algorithm: algo}}
+	}
+	return algo, int(data[5]), nil
+}
+
+// parseHeader parses the header at the start of data and returns it with
+// its length
+func parseHeader(data []byte) (envelope, int, error) {
+	algo, idLen, err := parsePrefix(data)
+	if err != nil {
+		return envelope{}, 0, err
+	}
+	n := headerSize(idLen)
+	if len(data) < n {
+		return envelope{}, 0, decodeError(io.ErrUnexpectedEOF)
+	}
+	env := envelope{
+		sig:    Signature{Algorithm: algo, KeyID: string(data[prefixSize : prefixSize+idLen])},
+		length: binary.BigEndian.Uint32(data[n-4:]),
+	}
+	return env, n, nil
+}
+
+// decodeError wraps a read failure in a codec.DecodeError, leaving errors
+// that already are one unchanged
+func decodeError(err error) error {
+	var de codec.DecodeError
+	if errors.As(err, &de) {
+		return err
+	}
+	kind := codec.ErrSyntax
+	if err == io.EOF || err == io.ErrUnexpectedEOF {
+		kind = codec.ErrTruncated
+	}
+	return codec.DecodeError{Codec: errorCodec, Kind: kind, Offset: -1, Err: err}
+}
@@ -0,0 +1,291 @@
+//go:build codec_json
+
+package sign
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"io"
+	"testing"
+
+	codec "github.com/jeremyhahn/go-codec"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+)
+
+type TestStruct struct {
+	Name  string `json:"name"`
+	Age   int    `json:"age"`
+	Email string `json:"email"`
+}
+
+var testData = TestStruct{Name: "John Doe", Age: 30, Email: "john@example.com"}
+
+var testSecret = bytes.Repeat([]byte{1}, MinHMACKeySize)
+
+func testKeys() map[string]Key {
+	_, priv, _ := ed25519.GenerateKey(nil)
+	return map[string]Key{
+		"HS256": HMACKey(testSecret),
+		"EdDSA": Ed25519PrivateKey(priv),
+	}
+}
+
+func TestCodec_RoundTrip(t *testing.T) {
+	for name, key := range testKeys() {
+		t.Run(name, func(t *testing.T) {
+			c := Wrap[TestStruct](jsoncodec.New[TestStruct](), NewKeyring("k1", key))
+			if err := c.Err(); err != nil {
+				t.Fatalf("Wrap failed: %v", err)
+			}
+
+			data, err := c.Marshal(testData)
+			if err != nil {
+				t.Fatalf("Marshal failed: %v", err)
+			}
+			raw, _ := jsoncodec.New[TestStruct]().Marshal(testData)
+			if !bytes.Contains(data, raw) {
+				t.Error("the envelope does not contain the exact encoded bytes")
+			}
+			var decoded TestStruct
+			if err := c.Unmarshal(data, &decoded); err != nil {
+				t.Fatalf("Unmarshal failed: %v", err)
+			}
+			if decoded != testData {
+				t.Errorf("Unmarshal = %+v, want %+v", decoded, testData)
+			}
+
+			var buf bytes.Buffer
+			for i := 0; i < 2; i++ {
+				if err := c.Encode(&buf, testData); err != nil {
+					t.Fatalf("Encode failed: %v", err)
+				}
+			}
+			for i := 0; i < 2; i++ {
+				decoded = TestStruct{}
+				if err := c.Decode(&buf, &decoded); err != nil || decoded != testData {
+					t.Fatalf("Decode %d = %+v, %v", i, decoded, err)
+				}
+			}
+			if err := c.Decode(&buf, &decoded); err != io.EOF {
+				t.Errorf("Decode at end = %v, want io.EOF", err)
+			}
+		})
+	}
+}
+
+func TestCodec_Detached(t *testing.T) {
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), NewKeyring("webhook", HMACKey(testSecret)))
+	payload, sig, err := c.MarshalDetached(testData)
+	if err != nil {
+		t.Fatalf("MarshalDetached failed: %v", err)
+	}
+	if raw, _ := jsoncodec.New[TestStruct]().Marshal(testData); !bytes.Equal(payload, raw) {
+		t.Errorf("payload = %q, want %q", payload, raw)
+	}
+	if sig.Algorithm != HMACSHA256 || sig.KeyID != "webhook" || len(sig.Value) != 32 {
+		t.Errorf("signature = %+v", sig)
+	}
+
+	parsed, err := ParseSignature(sig.String())
+	if err != nil {
+		t.Fatalf("ParseSignature failed: %v", err)
+	}
+	var v TestStruct
+	if err := c.UnmarshalDetached(payload, parsed, &v); err != nil || v != testData {
+		t.Errorf("UnmarshalDetached = %+v, %v", v, err)
+	}
+
+	// A changed byte of the payload, even insignificant whitespace, fails
+	if err := c.UnmarshalDetached(append(bytes.Clone(payload), ' '), sig, &v); !errors.As(err, new(ErrInvalidSignature)) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	for _, s := range []string{"", "HS256.a", "XX256.YQ.YQ", "HS256.!.YQ", "HS256.YQ.!"} {
+		if _, err := ParseSignature(s); err == nil {
+			t.Errorf("ParseSignature(%q) succeeded", s)
+		}
+	}
+}
+
+func TestCodec_Tampered(t *testing.T) {
+	for name, key := range testKeys() {
+		t.Run(name, func(t *testing.T) {
+			c := Wrap[TestStruct](jsoncodec.New[TestStruct](), NewKeyring("k1", key))
+			data, _ := c.Marshal(testData)
+
+			// Every bit of the payload and signature is covered
+			for i := headerSize(2); i < len(data); i++ {
+				tampered := bytes.Clone(data)
+				tampered[i] ^= 0x01
+				var v TestStruct
+				var sigErr ErrInvalidSignature
+				if err := c.Unmarshal(tampered, &v); !errors.As(err, &sigErr) || sigErr.KeyID != "k1" {
+					t.Fatalf("byte %d: expected ErrInvalidSignature, got %v", i, err)
+				}
+			}
+		})
+	}
+}
+
+func TestCodec_AlgorithmConfusion(t *testing.T) {
+	pub, priv, _ := ed25519.GenerateKey(nil)
+
+	// An attacker who knows the public key signs with it as an HMAC secret
+	// and names the Ed25519 key
+	forged := Wrap[TestStruct](jsoncodec.New[TestStruct](), NewKeyring("device", HMACKey(pub)))
+	data, err := forged.Marshal(testData)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), NewKeyring("device", Ed25519PublicKey(pub)))
+	var v TestStruct
+	if err := c.Unmarshal(data, &v); !errors.As(err, new(ErrInvalidSignature)) {
+		t.Errorf("expected ErrInvalidSignature, got %v", err)
+	}
+
+	// A verification-only key cannot sign
+	if _, err := c.Marshal(testData); err == nil {
+		t.Error("expected an error signing with a public key")
+	}
+	signed, _ := Wrap[TestStruct](jsoncodec.New[TestStruct](), NewKeyring("device", Ed25519PrivateKey(priv))).Marshal(testData)
+	if err := c.Unmarshal(signed, &v); err != nil || v != testData {
+		t.Errorf("Unmarshal = %+v, %v", v, err)
+	}
+}
+
+func TestCodec_KeyRotation(t *testing.T) {
+	keys := NewKeyring("2024-01", HMACKey(testSecret))
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), keys)
+	old, _ := c.Marshal(testData)
+
+	_, priv, _ := ed25519.GenerateKey(nil)
+	keys.Rotate("2024-02", Ed25519PrivateKey(priv))
+	current, _ := c.Marshal(testData)
+	if Algorithm(current[4]) != Ed25519 {
+		t.Errorf("signed with %s after rotation", Algorithm(current[4]))
+	}
+	for _, data := range [][]byte{old, current} {
+		var v TestStruct
+		if err := c.Unmarshal(data, &v); err != nil || v != testData {
+			t.Errorf("Unmarshal = %+v, %v", v, err)
+		}
+	}
+
+	keys.Remove("2024-01")
+	var v TestStruct
+	var unknown ErrUnknownKey
+	if err := c.Unmarshal(old, &v); !errors.As(err, &unknown) || unknown.KeyID != "2024-01" {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+	keys.Remove("2024-02")
+	if _, err := c.Marshal(testData); !errors.As(err, &unknown) {
+		t.Errorf("expected ErrUnknownKey, got %v", err)
+	}
+}
+
+func TestCodec_InvalidInput(t *testing.T) {
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), NewKeyring("k1", HMACKey(testSecret)))
+	valid, _ := c.Marshal(testData)
+
+	badVersion := bytes.Clone(valid)
+	badVersion[3] = 9
+	badAlgo := bytes.Clone(valid)
+	badAlgo[4] = 9
+
+	for _, tt := range []struct {
+		name string
+		data []byte
+		kind codec.ErrorKind
+	}{
+		{"no header", []byte(`{"name":"x"}`), codec.ErrSyntax},
+		{"short magic", []byte("GC"), codec.ErrTruncated},
+		{"short header", valid[:prefixSize+3], codec.ErrTruncated},
+		{"truncated signature", valid[:len(valid)-1], codec.ErrTruncated},
+		{"version", badVersion, codec.ErrSyntax},
+		{"algorithm", badAlgo, codec.ErrSyntax},
+	} {
+		t.Run(tt.name, func(t *testing.T) {
+			var v TestStruct
+			var de codec.DecodeError
+			if err := c.Unmarshal(tt.data, &v); !errors.As(err, &de) || de.Kind != tt.kind {
+				t.Errorf("Unmarshal error = %v, want %s", err, tt.kind)
+			}
+			if err := c.Decode(bytes.NewReader(tt.data), &v); !errors.As(err, &de) || de.Kind != tt.kind {
+				t.Errorf("Decode error = %v, want %s", err, tt.kind)
+			}
+		})
+	}
+
+	var v TestStruct
+	var de codec.DecodeError
+	if err := c.Unmarshal(append(bytes.Clone(valid), 0), &v); !errors.As(err, &de) || de.Kind != codec.ErrSyntax {
+		t.Errorf("expected a syntax error for trailing data, got %v", err)
+	}
+	if err := c.Unmarshal([]byte("xyz123"), &v); !errors.Is(err, ErrInvalidHeader) {
+		t.Errorf("expected ErrInvalidHeader, got %v", err)
+	}
+}
+
+func TestCodec_MaxSize(t *testing.T) {
+	keys := NewKeyring("k1", HMACKey(testSecret))
+	data, _ := Wrap[TestStruct](jsoncodec.New[TestStruct](), keys).Marshal(testData)
+	payload, sig, _ := Wrap[TestStruct](jsoncodec.New[TestStruct](), keys).MarshalDetached(testData)
+
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), keys, WithMaxSize(16))
+	var v TestStruct
+	if err := c.Unmarshal(data, &v); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Errorf("Unmarshal error = %v, want ErrLimitExceeded", err)
+	}
+	if err := c.Decode(bytes.NewReader(data), &v); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Errorf("Decode error = %v, want ErrLimitExceeded", err)
+	}
+	if err := c.UnmarshalDetached(payload, sig, &v); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Errorf("UnmarshalDetached error = %v, want ErrLimitExceeded", err)
+	}
+}
+
+func TestWrap_Errors(t *testing.T) {
+	keys := NewKeyring("k1", HMACKey(testSecret))
+
+	var notSupported codec.ErrOptionNotSupported
+	c := Wrap[TestStruct](jsoncodec.New[TestStruct](), keys, jsoncodec.WithIndent("", "  "))
+	if !errors.As(c.Err(), &notSupported) {
+		t.Errorf("expected ErrOptionNotSupported, got %v", c.Err())
+	}
+	if _, err := c.Marshal(testData); err != c.Err() {
+		t.Errorf("Marshal error = %v, want %v", err, c.Err())
+	}
+
+	c = Wrap[TestStruct](jsoncodec.New[TestStruct](codec.NewOption("other.WithThing", func(*struct{}) {})), keys)
+	if !errors.As(c.Err(), &notSupported) {
+		t.Errorf("expected the inner codec's error, got %v", c.Err())
+	}
+	if c = Wrap[TestStruct](jsoncodec.New[TestStruct](), nil); c.Err() == nil {
+		t.Error("expected an error for a nil KeyResolver")
+	}
+
+	c = Wrap[TestStruct](jsoncodec.New[TestStruct](), NewKeyring("short", HMACKey([]byte("secret"))))
+	if _, err := c.Marshal(testData); err == nil {
+		t.Error("expected an error for a short HMAC key")
+	}
+	c = Wrap[TestStruct](jsoncodec.New[TestStruct](), NewKeyring(string(make([]byte, 256)), HMACKey(testSecret)))
+	if _, err := c.Marshal(testData); err == nil {
+		t.Error("expected an error for a 256-byte key ID")
+	}
+}
+
+func BenchmarkCodec_Marshal(b *testing.B) {
+	for name, key := range testKeys() {
+		b.Run(name, func(b *testing.B) {
+			c := Wrap[TestStruct](jsoncodec.New[TestStruct](), NewKeyring("k1", key))
+			b.ReportAllocs()
+			for i := 0; i < b.N; i++ {
+				if _, err := c.Marshal(testData); err != nil {
+					b.Fatal(err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,99 @@
+package sign
+
+import (
+	"crypto/ed25519"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// Algorithm identifies a signature algorithm. Its value is stored in the
+// envelope header.
+type Algorithm byte
+
+const (
+	// HMACSHA256 signs with HMAC-SHA256 and a shared secret
+	HMACSHA256 Algorithm = iota + 1
+	// Ed25519 signs with an Ed25519 private key and verifies with its
+	// public key
+	Ed25519
+)
+
+// String returns the name of the algorithm as used in JOSE and COSE, e.g.
+// "HS256"
+func (a Algorithm) String() string {
+	switch a {
+	case HMACSHA256:
+		return "HS256"
+	case Ed25519:
+		return "EdDSA"
+	default:
+		return fmt.Sprintf("Algorithm(%d)", byte(a))
+	}
+}
+
+// size returns the length of the signatures of a, or 0 if a is unknown
+func (a Algorithm) size() int {
+	switch a {
+	case HMACSHA256:
+		return sha256.Size
+	case Ed25519:
+		return ed25519.SignatureSize
+	default:
+		return 0
+	}
+}
+
+// ErrAlgorithmNotSupported is returned for an unknown algorithm
+type ErrAlgorithmNotSupported struct {
+	Algorithm Algorithm
+}
+
+func (e ErrAlgorithmNotSupported) Error() string {
+	return fmt.Sprintf("sign: algorithm %s is not supported", e.Algorithm)
+}
+
+// Signature is a detached signature of encoded bytes, as returned by
+// MarshalDetached and sent alongside the payload, such as in a webhook
+// header
+type Signature struct {
+	Algorithm Algorithm
+	KeyID     string
+	Value     []byte
+}
+
+// String returns the compact form of the signature: the algorithm, the
+// key ID and the signature, the latter two base64url-encoded, separated by
+// dots. ParseSignature reverses it.
+func (s Signature) String() string {
+	return s.Algorithm.String() + "." +
+		base64.RawURLEncoding.EncodeToString([]byte(s.KeyID)) + "." +
+		base64.RawURLEncoding.EncodeToString(s.Value)
+}
+
+// ParseSignature parses the compact form returned by Signature.String
+func ParseSignature(s string) (Signature, error) {
+	parts := strings.Split(s, ".")
+	if len(parts) != 3 {
+		return Signature{}, fmt.Errorf("sign: signature %q does not have three parts", s)
+	}
+	var sig Signature
+	switch parts[0] {
+	case HMACSHA256.String():
+		sig.Algorithm = HMACSHA256
+	case Ed25519.String():
+		sig.Algorithm = Ed25519
+	default:
+		return Signature{}, fmt.Errorf("sign: unknown signature algorithm %q", parts[0])
+	}
+	id, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Signature{}, fmt.Errorf("sign: invalid key ID: %w", err)
+	}
+	sig.KeyID = string(id)
+	if sig.Value, err = base64.RawURLEncoding.DecodeString(parts[2]); err != nil {
+		return Signature{}, fmt.Errorf("sign: invalid signature: %w", err)
+	}
+	return sig, nil
+}
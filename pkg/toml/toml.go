@@ -8,38 +8,72 @@ import (
 
 	"github.com/BurntSushi/toml"
 	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/pool"
 )
 
 func init() {
 	codec.RegisterCodec(codec.TOML)
+	codec.RegisterValueCodec(codec.TOML, valueCodec{})
 }
 
 // Codec implements the codec.Codec interface for TOML serialization
-type Codec[T any] struct{}
+type Codec[T any] struct {
+	cfg config
+	err error
+}
+
+// New creates a new TOML codec configured with the given options. If an
+// option does not apply to TOML, every operation returns the error, which
+// is also reported by Err.
+func New[T any](opts ...codec.Option) *Codec[T] {
+	c := &Codec[T]{}
+	c.err = codec.ApplyOptions(codec.TOML, &c.cfg, opts)
+	return c
+}
 
-// New creates a new TOML codec
-func New[T any]() *Codec[T] {
-	return &Codec[T]{}
+// Err returns the error, if any, caused by the options passed to New
+func (c *Codec[T]) Err() error {
+	return c.err
 }
 
 // Encode serializes the given data to the writer using TOML
 func (c *Codec[T]) Encode(w io.Writer, data T) error {
-	encoder := toml.NewEncoder(w)
-	return encoder.Encode(data)
+	if c.err != nil {
+		return c.err
+	}
+	if v, ok := any(data).(codec.Value); ok {
+		raw, err := c.marshalValue(v)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(raw)
+		return err
+	}
+	return c.newEncoder(w).Encode(data)
 }
 
 // Decode deserializes TOML data from the reader into the provided type
 func (c *Codec[T]) Decode(r io.Reader, data *T) error {
-	decoder := toml.NewDecoder(r)
-	_, err := decoder.Decode(data)
-	return err
+	if c.err != nil {
+		return c.err
+	}
+	doc, err := io.ReadAll(codec.NewLimitedReader(r, codec.TOML, c.cfg.Limits.MaxBytes))
+	if err != nil {
+		return err
+	}
+	return c.Unmarshal(doc, data)
 }
 
 // Marshal serializes the given data to TOML bytes
 func (c *Codec[T]) Marshal(data T) ([]byte, error) {
+	if c.err != nil {
+		return nil, c.err
+	}
+	if v, ok := any(data).(codec.Value); ok {
+		return c.marshalValue(v)
+	}
 	var buf bytes.Buffer
-	encoder := toml.NewEncoder(&buf)
-	if err := encoder.Encode(data); err != nil {
+	if err := c.newEncoder(&buf).Encode(data); err != nil {
 		return nil, err
 	}
 	return buf.Bytes(), nil
@@ -47,5 +81,37 @@ func (c *Codec[T]) Marshal(data T) ([]byte, error) {
 
 // Unmarshal deserializes TOML bytes into the provided type
 func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
-	return toml.Unmarshal(data, v)
+	if c.err != nil {
+		return c.err
+	}
+	if value, ok := any(v).(*codec.Value); ok {
+		return c.unmarshalValue(data, value)
+	}
+	if err := c.checkLimits(data); err != nil {
+		return err
+	}
+	if c.cfg.Strict {
+		return c.decodeStrict(data, v)
+	}
+	return decodeError(toml.Unmarshal(data, v), data)
+}
+
+// appendMarshal appends the encoding of data to buf, writing directly into
+// buf while it has room
+func (c *Codec[T]) appendMarshal(buf []byte, data T) ([]byte, error) {
+	out := pool.GetAppendBuffer(buf)
+	defer pool.PutAppendBuffer(out)
+	if err := c.Encode(out, data); err != nil {
+		return buf, err
+	}
+	return out.Bytes(), nil
+}
+
+// newEncoder returns a toml.Encoder writing to w with the codec's settings
+func (c *Codec[T]) newEncoder(w io.Writer) *toml.Encoder {
+	encoder := toml.NewEncoder(w)
+	if c.cfg.indentSet {
+		encoder.Indent = c.cfg.indent
+	}
+	return encoder
 }
@@ -0,0 +1,58 @@
+//go:build codec_toml
+
+package toml
+
+import (
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// typeMessage matches the errors BurntSushi/toml reports while storing
+// parsed values, which carry the line and key of the offending value
+var typeMessage = regexp.MustCompile(`^toml: (?:line (\d+) )?\(last key "([^"]*)"\): `)
+
+// decodeError converts an error returned by BurntSushi/toml into a
+// codec.DecodeError. data is the complete input when it is available. Read
+// errors are returned as is.
+func decodeError(err error, data []byte) error {
+	if err == nil {
+		return err
+	}
+
+	de := codec.DecodeError{Codec: codec.TOML, Offset: -1, Err: err}
+	var parseErr toml.ParseError
+	switch {
+	case errors.As(err, &parseErr):
+		de.Kind = codec.ErrSyntax
+		switch {
+		case strings.Contains(parseErr.Message, "unexpected EOF"):
+			de.Kind = codec.ErrTruncated
+		case strings.Contains(parseErr.Message, "has already been defined"):
+			de.Kind = codec.ErrDuplicateKey
+		case strings.Contains(parseErr.Message, " is out of range for "):
+			// The number does not fit the destination type
+			de.Kind = codec.ErrTypeMismatch
+		}
+		de.Offset = int64(parseErr.Position.Start)
+		de.Line, de.Column = parseErr.Position.Line, parseErr.Position.Col
+		de.Path = parseErr.LastKey
+	case strings.HasPrefix(err.Error(), "toml: "):
+		de.Kind = codec.ErrTypeMismatch
+		if m := typeMessage.FindStringSubmatch(err.Error()); m != nil {
+			de.Line, _ = strconv.Atoi(m[1])
+			de.Path = m[2]
+		}
+		if data != nil && de.Line > 0 {
+			de.Column = 1
+			de.Offset = codec.Offset(data, de.Line, de.Column)
+		}
+	default:
+		return err
+	}
+	return de
+}
@@ -0,0 +1,88 @@
+//go:build codec_toml
+
+package toml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/BurntSushi/toml"
+	"github.com/jeremyhahn/go-codec"
+)
+
+type userRecord struct {
+	User struct {
+		Name string `toml:"name"`
+		Age  int    `toml:"age"`
+	} `toml:"user"`
+}
+
+func TestUnmarshal_SyntaxError(t *testing.T) {
+	input := "[user]\nname = \"John\"\nage = = 30\n"
+	var v userRecord
+	err := New[userRecord]().Unmarshal([]byte(input), &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Codec != codec.TOML || de.Kind != codec.ErrSyntax {
+		t.Errorf("unexpected codec/kind: %s/%s", de.Codec, de.Kind)
+	}
+	if de.Line != 3 || de.Offset < int64(strings.Index(input, "age")) {
+		t.Errorf("unexpected position: line %d offset %d", de.Line, de.Offset)
+	}
+
+	var parseErr toml.ParseError
+	if !errors.As(err, &parseErr) {
+		t.Error("expected the library error to remain reachable")
+	}
+}
+
+func TestUnmarshal_TypeMismatch(t *testing.T) {
+	var v userRecord
+	err := New[userRecord]().Unmarshal([]byte("[user]\nname = \"John\"\nage = \"thirty\"\n"), &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Kind != codec.ErrTypeMismatch || de.Path != "user.age" || de.Line != 3 {
+		t.Errorf("unexpected error: %+v", de)
+	}
+	if de.Offset != 21 {
+		t.Errorf("expected offset 21, got %d", de.Offset)
+	}
+}
+
+func TestUnmarshal_OutOfRange(t *testing.T) {
+	type logConfig struct {
+		Level int8 `toml:"level"`
+	}
+	var v logConfig
+	err := New[logConfig]().Unmarshal([]byte("level = 1000\n"), &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) || de.Kind != codec.ErrTypeMismatch || de.Path != "level" || de.Column != 9 {
+		t.Errorf("expected a type mismatch at level, got %v", err)
+	}
+}
+
+func TestUnmarshal_Truncated(t *testing.T) {
+	var v TestStruct
+	err := New[TestStruct]().Unmarshal([]byte(`name = "John`), &v)
+	if !errors.Is(err, codec.ErrTruncated) {
+		t.Fatalf("expected truncated error, got %v", err)
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	var v TestStruct
+	err := New[TestStruct]().NewDecoder(strings.NewReader("name = \nage = 30\n")).Decode(&v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) || de.Line != 1 {
+		t.Fatalf("expected DecodeError on line 1, got %v", err)
+	}
+}
@@ -0,0 +1,79 @@
+//go:build codec_toml
+
+package toml
+
+import (
+	"maps"
+	"slices"
+	"strconv"
+
+	"github.com/BurntSushi/toml"
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// checkLimits enforces the codec's limits on data, a complete TOML
+// document. Structural limits are checked on a generic decoding of the
+// document, since TOML has no aliases that could make it expand.
+func (c *Codec[T]) checkLimits(data []byte) error {
+	limits := c.cfg.Limits
+	if err := limits.CheckBytes(int64(len(data))); err != nil {
+		return codec.DecodeError{Codec: codec.TOML, Kind: codec.ErrLimitExceeded, Offset: limits.MaxBytes, Err: err}
+	}
+	if !limits.Structural() {
+		return nil
+	}
+
+	var tree map[string]any
+	if err := toml.Unmarshal(data, &tree); err != nil {
+		return decodeError(err, data)
+	}
+	if path, err := checkValue(tree, "", 0, limits); err != nil {
+		return codec.DecodeError{Codec: codec.TOML, Kind: codec.ErrLimitExceeded, Offset: -1, Path: path, Err: err}
+	}
+	return nil
+}
+
+// checkValue checks v, a value at path nested depth levels deep, against
+// limits and returns the path of the first violation
+func checkValue(v any, path string, depth int, limits codec.Limits) (string, error) {
+	switch v := v.(type) {
+	case string:
+		return path, limits.CheckStringLength(int64(len(v)))
+	case map[string]any:
+		if err := checkContainer(len(v), depth+1, limits); err != nil {
+			return path, err
+		}
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			if p, err := checkValue(v[key], joinPath(path, key), depth+1, limits); err != nil {
+				return p, err
+			}
+		}
+	case []map[string]any:
+		if err := checkContainer(len(v), depth+1, limits); err != nil {
+			return path, err
+		}
+		for i, item := range v {
+			if p, err := checkValue(item, path+"["+strconv.Itoa(i)+"]", depth+1, limits); err != nil {
+				return p, err
+			}
+		}
+	case []any:
+		if err := checkContainer(len(v), depth+1, limits); err != nil {
+			return path, err
+		}
+		for i, item := range v {
+			if p, err := checkValue(item, path+"["+strconv.Itoa(i)+"]", depth+1, limits); err != nil {
+				return p, err
+			}
+		}
+	}
+	return "", nil
+}
+
+// checkContainer checks a table or array of n entries at depth
+func checkContainer(n, depth int, limits codec.Limits) error {
+	if err := limits.CheckDepth(depth); err != nil {
+		return err
+	}
+	return limits.CheckElements(int64(n))
+}
@@ -0,0 +1,65 @@
+//go:build codec_toml
+
+package toml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestLimits_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits codec.Limits
+		input  string
+		limit  string
+		path   string
+	}{
+		{"bytes", codec.Limits{MaxBytes: 8}, "name = \"John\"\n", "MaxBytes", ""},
+		{"depth", codec.Limits{MaxDepth: 2}, "[user]\nname = \"John\"\n[user.address]\ncity = \"Paris\"\n", "MaxDepth", "user.address"},
+		{"elements", codec.Limits{MaxElements: 2}, "tags = [1, 2, 3]\n", "MaxElements", "tags"},
+		{"string", codec.Limits{MaxStringLength: 4}, "[user]\nname = \"Johnny\"\n", "MaxStringLength", "user.name"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v map[string]any
+			err := New[map[string]any](codec.WithLimits(tt.limits)).Unmarshal([]byte(tt.input), &v)
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			var le codec.LimitError
+			if de.Kind != codec.ErrLimitExceeded || !errors.As(err, &le) || le.Limit != tt.limit {
+				t.Fatalf("expected %s exceeded, got %v", tt.limit, err)
+			}
+			if de.Path != tt.path {
+				t.Errorf("expected path %q, got %q", tt.path, de.Path)
+			}
+		})
+	}
+}
+
+func TestLimits_Decode(t *testing.T) {
+	c := New[TestStruct](codec.WithLimits(codec.Limits{MaxBytes: 32, MaxStringLength: 16}))
+
+	var v TestStruct
+	if err := c.Decode(strings.NewReader("name = \"John\"\nage = 30\n"), &v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if v.Name != "John" || v.Age != 30 {
+		t.Errorf("unexpected value %+v", v)
+	}
+
+	input := "name = \"John\"\nemail = \"john@example.com\"\n"
+	if err := c.Decode(strings.NewReader(input), &v); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+	if err := c.NewDecoder(strings.NewReader(input)).Decode(&v); !errors.Is(err, codec.ErrLimitExceeded) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+}
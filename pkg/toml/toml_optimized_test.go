@@ -0,0 +1,113 @@
+//go:build codec_toml
+
+package toml
+
+import (
+	"bytes"
+	"testing"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+var _ codec.OptimizedCodec[TestStruct] = NewPool[TestStruct]()
+
+func TestOptimizedCodec_MarshalTo(t *testing.T) {
+	c := NewPool[TestStruct]()
+	data := TestStruct{Name: "John Doe", Age: 30, Email: "john@example.com"}
+	want, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+
+	buf := make([]byte, 0, 256)
+	result, err := c.MarshalTo(buf, data)
+	if err != nil {
+		t.Fatalf("MarshalTo failed: %v", err)
+	}
+	if !bytes.Equal(result, want) {
+		t.Errorf("MarshalTo = %q, want %q", result, want)
+	}
+	if &result[0] != &buf[:1][0] {
+		t.Error("MarshalTo did not write into the provided buffer")
+	}
+}
+
+func TestOptimizedCodec_MarshalTo_SmallBuffer(t *testing.T) {
+	c := NewPool[TestStruct]()
+	data := TestStruct{Name: "John Doe", Age: 30, Email: "john@example.com"}
+
+	result, err := c.MarshalTo(make([]byte, 0, 1), data)
+	if err != nil {
+		t.Fatalf("MarshalTo failed: %v", err)
+	}
+	var decoded TestStruct
+	if err := c.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("Unmarshal verification failed: %v", err)
+	}
+	if decoded != data {
+		t.Errorf("expected %+v, got %+v", data, decoded)
+	}
+}
+
+func TestOptimizedCodec_AppendMarshal(t *testing.T) {
+	c := NewPool[TestStruct]()
+	data := TestStruct{Name: "John Doe", Age: 30, Email: "john@example.com"}
+
+	buf := append(make([]byte, 0, 256), "prefix:"...)
+	result, err := c.AppendMarshal(buf, data)
+	if err != nil {
+		t.Fatalf("AppendMarshal failed: %v", err)
+	}
+	if string(result[:7]) != "prefix:" {
+		t.Errorf("expected prefix to be preserved, got %s", string(result[:7]))
+	}
+	if &result[0] != &buf[0] {
+		t.Error("AppendMarshal did not write into the provided buffer")
+	}
+	var decoded TestStruct
+	if err := c.Unmarshal(result[7:], &decoded); err != nil {
+		t.Fatalf("Unmarshal verification failed: %v", err)
+	}
+	if decoded != data {
+		t.Errorf("expected %+v, got %+v", data, decoded)
+	}
+}
+
+func TestOptimizedCodec_AppendMarshal_Error(t *testing.T) {
+	c := NewPool[map[string]any]()
+	buf := []byte("prefix")
+	result, err := c.AppendMarshal(buf, map[string]any{"ch": make(chan int)})
+	if err == nil {
+		t.Fatal("expected an error for a channel")
+	}
+	if string(result) != "prefix" {
+		t.Errorf("expected original buffer on error, got %s", result)
+	}
+}
+
+func TestOptimizedCodec_UnmarshalFrom(t *testing.T) {
+	c := NewPool[TestStruct]()
+	data := TestStruct{Name: "Jane Doe", Age: 25, Email: "jane@example.com"}
+
+	marshaled, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	var result TestStruct
+	if err := c.UnmarshalFrom(marshaled, &result, make([]byte, 0, 256)); err != nil {
+		t.Fatalf("UnmarshalFrom failed: %v", err)
+	}
+	if result != data {
+		t.Errorf("expected %+v, got %+v", data, result)
+	}
+}
+
+func TestOptimizedCodec_OptionError(t *testing.T) {
+	c := NewPool[TestStruct](codec.NewOption("other.WithThing", func(*struct{}) {}))
+	if _, err := c.MarshalTo(nil, TestStruct{}); err == nil {
+		t.Error("expected option error from MarshalTo")
+	}
+	if _, err := c.AppendMarshal(nil, TestStruct{}); err == nil {
+		t.Error("expected option error from AppendMarshal")
+	}
+}
@@ -0,0 +1,20 @@
+package toml
+
+import codec "github.com/jeremyhahn/go-codec"
+
+// config holds the settings applied by TOML options
+type config struct {
+	codec.Config
+
+	indent    string
+	indentSet bool
+}
+
+// WithIndent sets the string used for each level of indentation of nested
+// tables in encoded output. The TOML encoder defaults to two spaces.
+func WithIndent(indent string) codec.Option {
+	return codec.NewOption("toml.WithIndent", func(c *config) {
+		c.indent = indent
+		c.indentSet = true
+	})
+}
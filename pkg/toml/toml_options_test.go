@@ -0,0 +1,78 @@
+//go:build codec_toml
+
+package toml
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+)
+
+type nested struct {
+	Outer struct {
+		Inner TestStruct `toml:"inner"`
+	} `toml:"outer"`
+}
+
+func TestWithIndent(t *testing.T) {
+	var data nested
+	data.Outer.Inner.Name = "John"
+
+	c := New[nested](WithIndent("\t"))
+	result, err := c.Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(result), "\n\t[outer.inner]") {
+		t.Errorf("expected tab indentation, got %q", result)
+	}
+
+	flat, err := New[nested](WithIndent("")).Marshal(data)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(flat), "\n[outer.inner]") {
+		t.Errorf("expected no indentation, got %q", flat)
+	}
+
+	var decoded nested
+	if err := c.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Outer.Inner.Name != "John" {
+		t.Errorf("expected name John, got %q", decoded.Outer.Inner.Name)
+	}
+}
+
+func TestNew_OptionNotSupported(t *testing.T) {
+	c := New[TestStruct](jsoncodec.WithUseNumber())
+
+	var notSupported codec.ErrOptionNotSupported
+	if !errors.As(c.Err(), &notSupported) {
+		t.Fatalf("expected ErrOptionNotSupported, got %v", c.Err())
+	}
+
+	var result TestStruct
+	if _, err := c.Marshal(TestStruct{}); err == nil {
+		t.Error("Marshal: expected option error, got nil")
+	}
+	if err := c.Unmarshal([]byte(`name = "x"`), &result); err == nil {
+		t.Error("Unmarshal: expected option error, got nil")
+	}
+	if err := c.Encode(&bytes.Buffer{}, TestStruct{}); err == nil {
+		t.Error("Encode: expected option error, got nil")
+	}
+	if err := c.Decode(strings.NewReader(`name = "x"`), &result); err == nil {
+		t.Error("Decode: expected option error, got nil")
+	}
+	if err := c.NewEncoder(&bytes.Buffer{}).Encode(TestStruct{}); err == nil {
+		t.Error("Encoder.Encode: expected option error, got nil")
+	}
+	if err := c.NewDecoder(strings.NewReader(`name = "x"`)).Decode(&result); err == nil {
+		t.Error("Decoder.Decode: expected option error, got nil")
+	}
+}
@@ -0,0 +1,73 @@
+//go:build codec_toml
+
+package toml
+
+import (
+	"bytes"
+	"errors"
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// errMultipleDocuments is returned when a second value is written to a TOML
+// encoder session. TOML has no document separator, so a stream carries
+// exactly one document.
+var errMultipleDocuments = errors.New("toml: a stream holds a single document")
+
+// Encoder writes a TOML document to a stream. Because TOML has no document
+// separator, an encoder session accepts a single value.
+type Encoder[T any] struct {
+	codec *Codec[T]
+	w     io.Writer
+	done  bool
+}
+
+// Decoder reads a TOML document from a stream. The first call to Decode
+// consumes the entire stream; subsequent calls return io.EOF.
+type Decoder[T any] struct {
+	codec *Codec[T]
+	r     io.Reader
+	done  bool
+}
+
+// NewEncoder returns an encoder session that writes a TOML document to w
+func (c *Codec[T]) NewEncoder(w io.Writer) codec.Encoder[T] {
+	return &Encoder[T]{codec: c, w: w}
+}
+
+// NewDecoder returns a decoder session that reads a TOML document from r
+func (c *Codec[T]) NewDecoder(r io.Reader) codec.Decoder[T] {
+	return &Decoder[T]{codec: c, r: r}
+}
+
+// Encode writes the TOML document to the stream. It returns an error if a
+// document has already been written.
+func (e *Encoder[T]) Encode(data T) error {
+	if e.done {
+		return errMultipleDocuments
+	}
+	e.done = true
+	return e.codec.Encode(e.w, data)
+}
+
+// Decode reads the TOML document from the stream. It returns io.EOF if the
+// document has already been read or the stream is empty.
+func (d *Decoder[T]) Decode(data *T) error {
+	if d.codec.err != nil {
+		return d.codec.err
+	}
+	if d.done {
+		return io.EOF
+	}
+	d.done = true
+
+	doc, err := io.ReadAll(codec.NewLimitedReader(d.r, codec.TOML, d.codec.cfg.Limits.MaxBytes))
+	if err != nil {
+		return err
+	}
+	if len(bytes.TrimSpace(doc)) == 0 {
+		return io.EOF
+	}
+	return d.codec.Unmarshal(doc, data)
+}
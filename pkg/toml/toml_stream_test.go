@@ -0,0 +1,71 @@
+//go:build codec_toml
+
+package toml
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+	"testing/iotest"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestCodec_StreamCodec(t *testing.T) {
+	var _ codec.StreamCodec[TestStruct] = New[TestStruct]()
+}
+
+func TestStream_RoundTrip(t *testing.T) {
+	c := New[TestStruct]()
+	want := TestStruct{Name: "Alice", Age: 30, Email: "alice@example.com"}
+
+	var buf bytes.Buffer
+	if err := c.NewEncoder(&buf).Encode(want); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+
+	dec := c.NewDecoder(&buf)
+	var got TestStruct
+	if err := dec.Decode(&got); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if got != want {
+		t.Errorf("expected %+v, got %+v", want, got)
+	}
+
+	if err := dec.Decode(&got); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF after the document, got %v", err)
+	}
+}
+
+func TestStream_Encode_SingleDocument(t *testing.T) {
+	c := New[TestStruct]()
+	enc := c.NewEncoder(io.Discard)
+
+	if err := enc.Encode(TestStruct{Name: "Alice"}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if err := enc.Encode(TestStruct{Name: "Bob"}); err == nil {
+		t.Fatal("expected error encoding a second document, got nil")
+	}
+}
+
+func TestStream_Decode_Empty(t *testing.T) {
+	c := New[TestStruct]()
+
+	var result TestStruct
+	if err := c.NewDecoder(strings.NewReader("  \n")).Decode(&result); !errors.Is(err, io.EOF) {
+		t.Errorf("expected io.EOF for empty stream, got %v", err)
+	}
+}
+
+func TestStream_Decode_ReadError(t *testing.T) {
+	c := New[TestStruct]()
+
+	var result TestStruct
+	if err := c.NewDecoder(iotest.ErrReader(errors.New("read failed"))).Decode(&result); err == nil {
+		t.Fatal("expected error from reader, got nil")
+	}
+}
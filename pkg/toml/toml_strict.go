@@ -0,0 +1,139 @@
+//go:build codec_toml
+
+package toml
+
+import (
+	"bytes"
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/internal/exact"
+)
+
+// decodeStrict decodes data into v, rejecting keys v has no place for and
+// numbers v cannot hold exactly. TOML itself forbids duplicate keys.
+func (c *Codec[T]) decodeStrict(data []byte, v *T) error {
+	md, err := toml.NewDecoder(bytes.NewReader(data)).Decode(v)
+	if err != nil {
+		return decodeError(err, data)
+	}
+	if undecoded := md.Undecoded(); len(undecoded) > 0 {
+		return codec.DecodeError{
+			Codec:  codec.TOML,
+			Kind:   codec.ErrUnknownField,
+			Offset: -1,
+			Path:   undecoded[0].String(),
+			Err:    fmt.Errorf("toml: key %q has no matching field in the destination", undecoded[0].String()),
+		}
+	}
+	return c.checkExact(data, *v)
+}
+
+// checkExact compares the numbers in data with those held by v, which data
+// was decoded into. BurntSushi/toml lets negative integers wrap around when
+// decoded into unsigned fields. The values cannot be encoded again to
+// compare them, since TOML has no unsigned integers, so v is walked
+// directly.
+func (c *Codec[T]) checkExact(data []byte, v T) error {
+	var input map[string]any
+	if err := toml.Unmarshal(data, &input); err != nil {
+		return nil
+	}
+	path, value, lossy := compareValue(input, reflect.ValueOf(v), "")
+	if !lossy {
+		return nil
+	}
+	return codec.DecodeError{
+		Codec:  codec.TOML,
+		Kind:   codec.ErrTypeMismatch,
+		Offset: -1,
+		Path:   path,
+		Err:    fmt.Errorf("toml: %v cannot be stored exactly in the destination", value),
+	}
+}
+
+// compareValue compares input, a generic decoding of the value at path,
+// with v, the Go value it was decoded into, and returns the path and value
+// of the first number that was not stored exactly
+func compareValue(input any, v reflect.Value, path string) (string, any, bool) {
+	for v.Kind() == reflect.Pointer || v.Kind() == reflect.Interface {
+		if v.IsNil() {
+			return "", nil, false
+		}
+		v = v.Elem()
+	}
+
+	switch in := input.(type) {
+	case map[string]any:
+		for _, key := range slices.Sorted(maps.Keys(in)) {
+			var field reflect.Value
+			switch v.Kind() {
+			case reflect.Struct:
+				field = fieldByKey(v, key)
+			case reflect.Map:
+				if v.Type().Key().Kind() == reflect.String {
+					field = v.MapIndex(reflect.ValueOf(key).Convert(v.Type().Key()))
+				}
+			}
+			if field.IsValid() {
+				if p, value, lossy := compareValue(in[key], field, joinPath(path, key)); lossy {
+					return p, value, true
+				}
+			}
+		}
+	case []map[string]any:
+		for i := 0; (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && i < len(in) && i < v.Len(); i++ {
+			if p, value, lossy := compareValue(in[i], v.Index(i), path+"["+strconv.Itoa(i)+"]"); lossy {
+				return p, value, true
+			}
+		}
+	case []any:
+		for i := 0; (v.Kind() == reflect.Slice || v.Kind() == reflect.Array) && i < len(in) && i < v.Len(); i++ {
+			if p, value, lossy := compareValue(in[i], v.Index(i), path+"["+strconv.Itoa(i)+"]"); lossy {
+				return p, value, true
+			}
+		}
+	default:
+		if v.CanInterface() && !exact.Equal(input, v.Interface()) {
+			return path, input, true
+		}
+	}
+	return "", nil, false
+}
+
+// fieldByKey returns the field of the struct v that BurntSushi/toml decodes
+// key into: the field tagged with key, or else named key ignoring case.
+// Fields of embedded structs are searched too.
+func fieldByKey(v reflect.Value, key string) reflect.Value {
+	var byName reflect.Value
+	for i := 0; i < v.NumField(); i++ {
+		f := v.Type().Field(i)
+		name, _, _ := strings.Cut(f.Tag.Get("toml"), ",")
+		switch {
+		case name == "-" || !f.IsExported():
+		case f.Anonymous && name == "" && f.Type.Kind() == reflect.Struct:
+			if field := fieldByKey(v.Field(i), key); field.IsValid() {
+				return field
+			}
+		case name == key:
+			return v.Field(i)
+		case name == "" && strings.EqualFold(f.Name, key) && !byName.IsValid():
+			byName = v.Field(i)
+		}
+	}
+	return byName
+}
+
+// joinPath appends key to the dotted path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,66 @@
+//go:build codec_toml
+
+package toml
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+type serverConfig struct {
+	Server struct {
+		Host  string `toml:"host"`
+		Port  uint16 `toml:"port"`
+		Retry uint   `toml:"retry"`
+	} `toml:"server"`
+}
+
+func TestStrict_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		kind  codec.ErrorKind
+		path  string
+	}{
+		{"unknown field", "[server]\nhost = \"localhost\"\nhots = \"example.com\"\n", codec.ErrUnknownField, "server.hots"},
+		{"duplicate key", "[server]\nhost = \"a\"\nhost = \"b\"\n", codec.ErrDuplicateKey, "server.host"},
+		{"negative unsigned", "[server]\nretry = -1\n", codec.ErrTypeMismatch, "server.retry"},
+		{"out of range", "[server]\nport = 70000\n", codec.ErrTypeMismatch, "server.port"},
+		{"lossy number", "[server]\nport = 80.5\n", codec.ErrTypeMismatch, "server.port"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v serverConfig
+			err := New[serverConfig](codec.WithStrict()).Unmarshal([]byte(tt.input), &v)
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			if de.Kind != tt.kind || de.Path != tt.path {
+				t.Errorf("expected %v at %s, got %v", tt.kind, tt.path, err)
+			}
+		})
+	}
+}
+
+func TestStrict_Valid(t *testing.T) {
+	input := "[server]\nhost = \"localhost\"\nport = 8080\nretry = 3\n"
+
+	var v serverConfig
+	if err := New[serverConfig](codec.WithStrict()).Unmarshal([]byte(input), &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if v.Server.Port != 8080 || v.Server.Retry != 3 {
+		t.Errorf("unexpected value %+v", v)
+	}
+
+	// Without Strict the unknown key is ignored
+	input += "hots = \"example.com\"\n"
+	if err := New[serverConfig]().Unmarshal([]byte(input), &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+}
@@ -14,10 +14,15 @@ var errNotSupported = codec.ErrCodecNotSupported{CodecType: codec.TOML}
 type Codec[T any] struct{}
 
 // New returns a TOML codec stub that will error on all operations.
-func New[T any]() *Codec[T] {
+func New[T any](opts ...codec.Option) *Codec[T] {
 	return &Codec[T]{}
 }
 
+// Err returns an error indicating TOML codec is not supported.
+func (c *Codec[T]) Err() error {
+	return errNotSupported
+}
+
 // Encode returns an error indicating TOML codec is not supported.
 func (c *Codec[T]) Encode(w io.Writer, data T) error {
 	return errNotSupported
@@ -37,3 +42,56 @@ func (c *Codec[T]) Marshal(data T) ([]byte, error) {
 func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
 	return errNotSupported
 }
+
+// Encoder is a stub for the TOML encoder session.
+type Encoder[T any] struct{}
+
+// Decoder is a stub for the TOML decoder session.
+type Decoder[T any] struct{}
+
+// NewEncoder returns a TOML encoder stub that will error on all operations.
+func (c *Codec[T]) NewEncoder(w io.Writer) codec.Encoder[T] {
+	return &Encoder[T]{}
+}
+
+// NewDecoder returns a TOML decoder stub that will error on all operations.
+func (c *Codec[T]) NewDecoder(r io.Reader) codec.Decoder[T] {
+	return &Decoder[T]{}
+}
+
+// Encode returns an error indicating TOML codec is not supported.
+func (e *Encoder[T]) Encode(data T) error {
+	return errNotSupported
+}
+
+// Decode returns an error indicating TOML codec is not supported.
+func (d *Decoder[T]) Decode(data *T) error {
+	return errNotSupported
+}
+
+// OptimizedCodec is a stub for the optimized TOML codec.
+type OptimizedCodec[T any] struct {
+	*Codec[T]
+}
+
+// NewPool returns an optimized TOML codec stub that will error on all operations.
+func NewPool[T any](opts ...codec.Option) *OptimizedCodec[T] {
+	return &OptimizedCodec[T]{
+		Codec: New[T](opts...),
+	}
+}
+
+// MarshalTo returns an error indicating TOML codec is not supported.
+func (c *OptimizedCodec[T]) MarshalTo(buf []byte, data T) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+// AppendMarshal returns an error indicating TOML codec is not supported.
+func (c *OptimizedCodec[T]) AppendMarshal(buf []byte, data T) ([]byte, error) {
+	return nil, errNotSupported
+}
+
+// UnmarshalFrom returns an error indicating TOML codec is not supported.
+func (c *OptimizedCodec[T]) UnmarshalFrom(data []byte, v *T, scratch []byte) error {
+	return errNotSupported
+}
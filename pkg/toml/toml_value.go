@@ -0,0 +1,413 @@
+//go:build codec_toml
+
+package toml
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"maps"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// Layouts of the local date and time types, which BurntSushi/toml decodes
+// as times in locations of these names
+var localLayouts = map[string]string{
+	"datetime-local": "2006-01-02T15:04:05.999999999",
+	"date-local":     "2006-01-02",
+	"time-local":     "15:04:05.999999999",
+}
+
+// valueCodec converts between TOML and codec.Value
+type valueCodec struct{}
+
+// DecodeValue reads a TOML document from r, keeping keys in the order in
+// which they are defined. Local dates and times decode as times whose
+// location is named after the TOML type, e.g. "date-local", so that they
+// are written back unchanged.
+func (valueCodec) DecodeValue(r io.Reader, cfg codec.Config, lossy func(codec.Loss)) (codec.Value, error) {
+	data, err := io.ReadAll(codec.NewLimitedReader(r, codec.TOML, cfg.Limits.MaxBytes))
+	if err != nil {
+		return codec.Value{}, err
+	}
+	c := &Codec[codec.Value]{cfg: config{Config: cfg}}
+	return c.convertValue(data)
+}
+
+// convertValue checks data, a TOML document, against the codec's limits
+// and converts it
+func (c *Codec[T]) convertValue(data []byte) (codec.Value, error) {
+	if err := c.checkLimits(data); err != nil {
+		return codec.Value{}, err
+	}
+	var tree map[string]any
+	md, err := toml.Decode(string(data), &tree)
+	if err != nil {
+		return codec.Value{}, decodeError(err, data)
+	}
+	d := valueDecoder{order: keyOrder(tree, md.Keys())}
+	return d.decode(tree, ""), nil
+}
+
+// unmarshalValue decodes data into v for a Codec[codec.Value]
+func (c *Codec[T]) unmarshalValue(data []byte, v *codec.Value) error {
+	value, err := c.convertValue(data)
+	if err != nil {
+		return err
+	}
+	*v = value
+	return nil
+}
+
+// marshalValue returns the encoding of v for a Codec[codec.Value], failing
+// on lossy conversions if the codec is strict
+func (c *Codec[T]) marshalValue(v codec.Value) ([]byte, error) {
+	lossy, check := codec.CollectLosses(codec.TOML, c.cfg.Strict)
+	var buf bytes.Buffer
+	err := valueCodec{}.EncodeValue(&buf, v, lossy)
+	if err == nil {
+		err = check()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// keyOrder returns the names of the keys of each table in the order in
+// which they are first defined, indexed by the table's position in tree.
+// Positions join keys and array indexes with NUL bytes, which keys cannot
+// hold unescaped. Each element of an array of tables is listed in keys
+// under the array's key.
+func keyOrder(tree map[string]any, keys []toml.Key) map[string][]string {
+	order := make(map[string][]string)
+	defined := make(map[string]bool)
+	elements := make(map[string]int)
+	for _, key := range keys {
+		var table any = tree
+		position := ""
+		for i, name := range key {
+			m, ok := table.(map[string]any)
+			if !ok {
+				break
+			}
+			child := position + "\x00" + name
+			if !defined[child] {
+				defined[child] = true
+				order[position] = append(order[position], name)
+			}
+			table, position = m[name], child
+			if array, ok := table.([]map[string]any); ok {
+				if i == len(key)-1 {
+					elements[child]++
+				}
+				index := max(elements[child]-1, 0)
+				if index >= len(array) {
+					break
+				}
+				table, position = array[index], elementPosition(child, index)
+			}
+		}
+	}
+	return order
+}
+
+// elementPosition returns the position of element i of the array at
+// position
+func elementPosition(position string, i int) string {
+	return position + "\x00[" + strconv.Itoa(i) + "]"
+}
+
+// valueDecoder builds a codec.Value from a generic decoding of a document
+type valueDecoder struct {
+	order map[string][]string
+}
+
+func (d valueDecoder) decode(v any, position string) codec.Value {
+	switch v := v.(type) {
+	case map[string]any:
+		// Keys missing from the metadata, such as those of inline tables
+		// in arrays, follow in sorted order
+		keys := slices.Clone(d.order[position])
+		for _, key := range slices.Sorted(maps.Keys(v)) {
+			if !slices.Contains(keys, key) {
+				keys = append(keys, key)
+			}
+		}
+		members := make([]codec.Member, 0, len(v))
+		for _, key := range keys {
+			if value, ok := v[key]; ok {
+				members = append(members, codec.Member{
+					Key:   codec.StringValue(key),
+					Value: d.decode(value, position+"\x00"+key),
+				})
+			}
+		}
+		return codec.MapValue(members...)
+	case []map[string]any:
+		items := make([]codec.Value, len(v))
+		for i, item := range v {
+			items[i] = d.decode(item, elementPosition(position, i))
+		}
+		return codec.ArrayValue(items...)
+	case []any:
+		items := make([]codec.Value, len(v))
+		for i, item := range v {
+			items[i] = d.decode(item, elementPosition(position, i))
+		}
+		return codec.ArrayValue(items...)
+	case int64:
+		return codec.IntValue(v)
+	case float64:
+		return codec.FloatValue(v)
+	case bool:
+		return codec.BoolValue(v)
+	case string:
+		return codec.StringValue(v)
+	case time.Time:
+		return codec.TimeValue(v)
+	default:
+		return codec.StringValue(fmt.Sprint(v))
+	}
+}
+
+// EncodeValue writes v, which must be a map, to w as a TOML document. Keys
+// keep their order, except that each table lists its plain values before
+// its subtables, as TOML requires. TOML has no null or byte string:
+// nulls are omitted and byte strings are written as base64 text, as are
+// keys that are not strings, unsigned integers beyond the range of an int64
+// as floats and tagged Values as their content, each reported to lossy.
+func (valueCodec) EncodeValue(w io.Writer, v codec.Value, lossy func(codec.Loss)) error {
+	if v.Kind() != codec.KindMap {
+		return fmt.Errorf("toml: cannot encode %s value as a document", v.Kind())
+	}
+	e := valueEncoder{lossy: lossy}
+	e.table(nil, v.Members(), "")
+	_, err := w.Write(e.buf)
+	return err
+}
+
+// valueEncoder appends the TOML encoding of a codec.Value to buf
+type valueEncoder struct {
+	buf   []byte
+	lossy func(codec.Loss)
+}
+
+// table appends the members of the table named by keys, followed by its
+// subtables and arrays of tables
+func (e *valueEncoder) table(keys []string, members []codec.Member, path string) {
+	for _, m := range members {
+		key := e.key(m.Key, path)
+		switch {
+		case isTable(m.Value), isTableArray(m.Value):
+			continue
+		case m.Value.IsNull():
+			e.lossy(codec.Loss{Path: joinPath(path, key), Reason: "null omitted"})
+			continue
+		}
+		e.buf = appendKey(e.buf, key)
+		e.buf = append(e.buf, " = "...)
+		e.inline(m.Value, joinPath(path, key))
+		e.buf = append(e.buf, '\n')
+	}
+
+	for _, m := range members {
+		key := m.Key.String()
+		header := append(slices.Clip(keys), key)
+		switch {
+		case isTable(m.Value):
+			e.header("[", header, "]")
+			e.table(header, m.Value.Members(), joinPath(path, key))
+		case isTableArray(m.Value):
+			for i, item := range m.Value.Array() {
+				e.header("[[", header, "]]")
+				e.table(header, item.Members(), joinPath(path, key)+"["+strconv.Itoa(i)+"]")
+			}
+		}
+	}
+}
+
+// header appends a table header for keys
+func (e *valueEncoder) header(open string, keys []string, close string) {
+	if len(e.buf) > 0 {
+		e.buf = append(e.buf, '\n')
+	}
+	e.buf = append(e.buf, open...)
+	for i, key := range keys {
+		if i > 0 {
+			e.buf = append(e.buf, '.')
+		}
+		e.buf = appendKey(e.buf, key)
+	}
+	e.buf = append(e.buf, close...)
+	e.buf = append(e.buf, '\n')
+}
+
+// key returns the text of the key k of a member of the table at path
+func (e *valueEncoder) key(k codec.Value, path string) string {
+	key := k.String()
+	if k.Kind() != codec.KindString {
+		e.lossy(codec.Loss{Path: joinPath(path, key), Reason: k.Kind().String() + " key written as text"})
+	}
+	return key
+}
+
+// inline appends v as an inline value
+func (e *valueEncoder) inline(v codec.Value, path string) {
+	switch v.Kind() {
+	case codec.KindBool:
+		e.buf = strconv.AppendBool(e.buf, v.Bool())
+	case codec.KindInt:
+		e.buf = strconv.AppendInt(e.buf, v.Int(), 10)
+	case codec.KindUint:
+		if v.Uint() <= math.MaxInt64 {
+			e.buf = strconv.AppendUint(e.buf, v.Uint(), 10)
+			return
+		}
+		e.lossy(codec.Loss{Path: path, Reason: "integer beyond int64 written as float"})
+		e.buf = appendFloat(e.buf, float64(v.Uint()))
+	case codec.KindFloat:
+		e.buf = appendFloat(e.buf, v.Float())
+	case codec.KindString:
+		e.buf = appendString(e.buf, v.String())
+	case codec.KindBytes:
+		e.lossy(codec.Loss{Path: path, Reason: "byte string written as base64 text"})
+		e.buf = appendString(e.buf, v.String())
+	case codec.KindTime:
+		t := v.Time()
+		if layout, ok := localLayouts[t.Location().String()]; ok {
+			e.buf = t.AppendFormat(e.buf, layout)
+			return
+		}
+		e.buf = t.AppendFormat(e.buf, time.RFC3339Nano)
+	case codec.KindArray:
+		e.buf = append(e.buf, '[')
+		first := true
+		for i, item := range v.Array() {
+			itemPath := path + "[" + strconv.Itoa(i) + "]"
+			if item.IsNull() {
+				e.lossy(codec.Loss{Path: itemPath, Reason: "null omitted"})
+				continue
+			}
+			if !first {
+				e.buf = append(e.buf, ", "...)
+			}
+			first = false
+			e.inline(item, itemPath)
+		}
+		e.buf = append(e.buf, ']')
+	case codec.KindMap:
+		e.buf = append(e.buf, '{')
+		first := true
+		for _, m := range v.Members() {
+			key := e.key(m.Key, path)
+			if m.Value.IsNull() {
+				e.lossy(codec.Loss{Path: joinPath(path, key), Reason: "null omitted"})
+				continue
+			}
+			if first {
+				e.buf = append(e.buf, ' ')
+			} else {
+				e.buf = append(e.buf, ", "...)
+			}
+			first = false
+			e.buf = appendKey(e.buf, key)
+			e.buf = append(e.buf, " = "...)
+			e.inline(m.Value, joinPath(path, key))
+		}
+		if !first {
+			e.buf = append(e.buf, ' ')
+		}
+		e.buf = append(e.buf, '}')
+	case codec.KindTagged:
+		e.lossy(codec.Loss{Path: path, Reason: "tag " + v.Tag().String() + " dropped"})
+		e.inline(v.Content(), path)
+	}
+}
+
+// isTable reports whether v is written as a table
+func isTable(v codec.Value) bool {
+	return v.Kind() == codec.KindMap
+}
+
+// isTableArray reports whether v is written as an array of tables
+func isTableArray(v codec.Value) bool {
+	if v.Kind() != codec.KindArray || len(v.Array()) == 0 {
+		return false
+	}
+	for _, item := range v.Array() {
+		if item.Kind() != codec.KindMap {
+			return false
+		}
+	}
+	return true
+}
+
+// appendKey appends key, quoted unless it is a bare key
+func appendKey(buf []byte, key string) []byte {
+	bare := key != ""
+	for _, r := range key {
+		if !(r >= 'A' && r <= 'Z' || r >= 'a' && r <= 'z' || r >= '0' && r <= '9' || r == '_' || r == '-') {
+			bare = false
+			break
+		}
+	}
+	if bare {
+		return append(buf, key...)
+	}
+	return appendString(buf, key)
+}
+
+// appendString appends s as a TOML basic string
+func appendString(buf []byte, s string) []byte {
+	const hex = "0123456789ABCDEF"
+	buf = append(buf, '"')
+	for _, r := range s {
+		switch r {
+		case '"', '\\':
+			buf = append(buf, '\\', byte(r))
+		case '\b':
+			buf = append(buf, '\\', 'b')
+		case '\t':
+			buf = append(buf, '\\', 't')
+		case '\n':
+			buf = append(buf, '\\', 'n')
+		case '\f':
+			buf = append(buf, '\\', 'f')
+		case '\r':
+			buf = append(buf, '\\', 'r')
+		default:
+			if r < 0x20 || r == 0x7f {
+				buf = append(buf, '\\', 'u', '0', '0', hex[r>>4], hex[r&0xf])
+				continue
+			}
+			buf = append(buf, string(r)...)
+		}
+	}
+	return append(buf, '"')
+}
+
+// appendFloat appends f as a TOML float, keeping a fraction on integral
+// values so that they decode as floats again
+func appendFloat(buf []byte, f float64) []byte {
+	switch {
+	case math.IsNaN(f):
+		return append(buf, "nan"...)
+	case math.IsInf(f, 1):
+		return append(buf, "inf"...)
+	case math.IsInf(f, -1):
+		return append(buf, "-inf"...)
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return append(buf, s...)
+}
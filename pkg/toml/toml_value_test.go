@@ -0,0 +1,101 @@
+//go:build codec_toml
+
+package toml
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestValue_RoundTrip(t *testing.T) {
+	input := `zeta = 1
+alpha = 1.0
+when = 2024-01-02T03:04:05+02:00
+day = 2024-01-02
+tags = ["a", "b"]
+
+[point]
+y = 2
+x = 1
+
+[server]
+port = 8080
+host = "localhost"
+
+[[server.routes]]
+path = "/b"
+
+[[server.routes]]
+path = "/a"
+`
+	v, err := codec.DecodeValue(codec.TOML, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("DecodeValue failed: %v", err)
+	}
+
+	var out bytes.Buffer
+	if err := codec.EncodeValue(codec.TOML, &out, v); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	if out.String() != input {
+		t.Errorf("EncodeValue =\n%s\nwant\n%s", out.String(), input)
+	}
+}
+
+func TestValue_EncodeLosses(t *testing.T) {
+	v := codec.MapValue(
+		codec.Member{Key: codec.StringValue("a"), Value: codec.NullValue()},
+		codec.Member{Key: codec.StringValue("b"), Value: codec.BytesValue([]byte("hi"))},
+	)
+
+	var out bytes.Buffer
+	err := codec.EncodeValue(codec.TOML, &out, v)
+	var lossErr *codec.LossError
+	if !errors.As(err, &lossErr) || len(lossErr.Losses) != 2 {
+		t.Fatalf("Expected *LossError with 2 losses, got %v", err)
+	}
+
+	if err := codec.EncodeValue(codec.TOML, &out, v, codec.WithAllowLossy(nil)); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	if want := "b = \"aGk=\"\n"; out.String() != want {
+		t.Errorf("EncodeValue = %q, want %q", out.String(), want)
+	}
+}
+
+func TestValue_EncodeNotMap(t *testing.T) {
+	var out bytes.Buffer
+	if err := codec.EncodeValue(codec.TOML, &out, codec.IntValue(1)); err == nil {
+		t.Error("Expected an error encoding an int as a document")
+	}
+}
+
+func TestCodec_Value(t *testing.T) {
+	c := New[codec.Value]()
+	input := "b = 1\na = \"x\"\n"
+	var v codec.Value
+	if err := c.Unmarshal([]byte(input), &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	data, err := c.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if string(data) != input {
+		t.Errorf("Marshal = %q, want %q", data, input)
+	}
+
+	tagged := codec.MapValue(codec.Member{
+		Key:   codec.StringValue("id"),
+		Value: codec.TaggedValue(codec.Tag{Codec: codec.BSON, Number: 7, Name: "ObjectID"}, codec.StringValue("abc")),
+	})
+	_, err = New[codec.Value](codec.WithStrict()).Marshal(tagged)
+	var lossErr *codec.LossError
+	if !errors.As(err, &lossErr) || lossErr.Losses[0].Reason != "tag bson:ObjectID dropped" {
+		t.Errorf("Expected a dropped tag loss, got %v", err)
+	}
+}
@@ -3,28 +3,56 @@
 package yaml
 
 import (
+	"bytes"
 	"io"
 
 	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/pkg/pool"
 	"gopkg.in/yaml.v3"
 )
 
 func init() {
 	codec.RegisterCodec(codec.YAML)
+	codec.RegisterValueCodec(codec.YAML, valueCodec{})
 }
 
 // Codec implements the codec.Codec interface for YAML serialization
-type Codec[T any] struct{}
+type Codec[T any] struct {
+	cfg config
+	err error
+}
 
-// New creates a new YAML codec
-func New[T any]() *Codec[T] {
-	return &Codec[T]{}
+// New creates a new YAML codec configured with the given options. If an
+// option does not apply to YAML or has an invalid value, every operation
+// returns the error, which is also reported by Err.
+func New[T any](opts ...codec.Option) *Codec[T] {
+	c := &Codec[T]{}
+	if c.err = codec.ApplyOptions(codec.YAML, &c.cfg, opts); c.err == nil {
+		c.err = c.cfg.validate()
+	}
+	return c
+}
+
+// Err returns the error, if any, caused by the options passed to New
+func (c *Codec[T]) Err() error {
+	return c.err
 }
 
 // Encode serializes the given data to the writer using YAML
 func (c *Codec[T]) Encode(w io.Writer, data T) error {
-	encoder := yaml.NewEncoder(w)
-	if err := encoder.Encode(data); err != nil {
+	if c.err != nil {
+		return c.err
+	}
+	var value any = data
+	if v, ok := value.(codec.Value); ok {
+		node, err := c.marshalValue(v)
+		if err != nil {
+			return err
+		}
+		value = node
+	}
+	encoder := c.newEncoder(w)
+	if err := encoder.Encode(value); err != nil {
 		_ = encoder.Close()
 		return err
 	}
@@ -33,16 +61,107 @@ func (c *Codec[T]) Encode(w io.Writer, data T) error {
 
 // Decode deserializes YAML data from the reader into the provided type
 func (c *Codec[T]) Decode(r io.Reader, data *T) error {
-	decoder := yaml.NewDecoder(r)
-	return decoder.Decode(data)
+	if c.err != nil {
+		return c.err
+	}
+	r = codec.NewLimitedReader(r, codec.YAML, c.cfg.Limits.MaxBytes)
+	return c.decodeNext(yaml.NewDecoder(r), r, data)
 }
 
 // Marshal serializes the given data to YAML bytes
 func (c *Codec[T]) Marshal(data T) ([]byte, error) {
-	return yaml.Marshal(data)
+	if c.err != nil {
+		return nil, c.err
+	}
+	if _, ok := any(data).(codec.Value); !ok && c.cfg.indent == 0 {
+		return yaml.Marshal(data)
+	}
+
+	var buf bytes.Buffer
+	if err := c.Encode(&buf, data); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
 }
 
 // Unmarshal deserializes YAML bytes into the provided type
 func (c *Codec[T]) Unmarshal(data []byte, v *T) error {
-	return yaml.Unmarshal(data, v)
+	if c.err != nil {
+		return c.err
+	}
+	limits := c.cfg.Limits
+	if err := limits.CheckBytes(int64(len(data))); err != nil {
+		return codec.DecodeError{Codec: codec.YAML, Kind: codec.ErrLimitExceeded, Offset: limits.MaxBytes, Err: err}
+	}
+	if !c.prescans(v) {
+		return decodeError(yaml.Unmarshal(data, v), data)
+	}
+
+	var node yaml.Node
+	if err := yaml.Unmarshal(data, &node); err != nil {
+		return decodeError(err, data)
+	}
+	return c.decodeNode(&node, data, v)
+}
+
+// appendMarshal appends the encoding of data to buf, writing directly into
+// buf while it has room
+func (c *Codec[T]) appendMarshal(buf []byte, data T) ([]byte, error) {
+	out := pool.GetAppendBuffer(buf)
+	defer pool.PutAppendBuffer(out)
+	if err := c.Encode(out, data); err != nil {
+		return buf, err
+	}
+	return out.Bytes(), nil
+}
+
+// decodeNext decodes the next document from decoder, which reads from r.
+// When structural limits or strict decoding are set the document is parsed
+// into a node tree and checked before it is decoded.
+func (c *Codec[T]) decodeNext(decoder *yaml.Decoder, r io.Reader, v *T) error {
+	if !c.prescans(v) {
+		return readError(r, decodeError(decoder.Decode(v), nil))
+	}
+	var node yaml.Node
+	if err := decoder.Decode(&node); err != nil {
+		return readError(r, decodeError(err, nil))
+	}
+	return c.decodeNode(&node, nil, v)
+}
+
+// decodeNode checks node, parsed from data if available, against the
+// codec's limits and decodes it into v
+func (c *Codec[T]) decodeNode(node *yaml.Node, data []byte, v *T) error {
+	if node.Kind == 0 {
+		// Empty input holds no document
+		return nil
+	}
+	if c.cfg.Limits.Structural() {
+		if err := checkNode(node, c.cfg.Limits); err != nil {
+			return err.decodeError(data)
+		}
+	}
+	if value, ok := any(v).(*codec.Value); ok {
+		return c.convertValue(node, value)
+	}
+	if c.cfg.Strict {
+		return decodeStrict(node, data, v)
+	}
+	return decodeError(node.Decode(v), data)
+}
+
+// prescans reports whether documents decoded into v are parsed into a node
+// tree first, as they always are for a codec.Value
+func (c *Codec[T]) prescans(v *T) bool {
+	_, isValue := any(v).(*codec.Value)
+	return isValue || c.cfg.prescans()
+}
+
+// newEncoder returns a yaml.Encoder writing to w with the codec's settings
+func (c *Codec[T]) newEncoder(w io.Writer) *yaml.Encoder {
+	encoder := yaml.NewEncoder(w)
+	if c.cfg.indent != 0 {
+		encoder.SetIndent(c.cfg.indent)
+	}
+	return encoder
 }
@@ -0,0 +1,124 @@
+//go:build codec_yaml
+
+package yaml
+
+import (
+	"errors"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"gopkg.in/yaml.v3"
+)
+
+// lineMessage matches the "line N: message" form used by yaml.v3 in syntax
+// errors (after the "yaml: " prefix) and in TypeError entries
+var lineMessage = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+// decodeError converts an error returned by yaml.v3 into a
+// codec.DecodeError. data is the complete input when it is available and is
+// used to compute the offset, column and field path. Errors that do not
+// describe the input, such as read errors and io.EOF at the end of a stream,
+// are returned as is.
+func decodeError(err error, data []byte) error {
+	if err == nil || err == io.EOF {
+		return err
+	}
+
+	de := codec.DecodeError{Codec: codec.YAML, Offset: -1, Err: err}
+	var typeErr *yaml.TypeError
+	switch msg := err.Error(); {
+	case errors.As(err, &typeErr) && len(typeErr.Errors) > 0:
+		de.Kind = codec.ErrTypeMismatch
+		first := typeErr.Errors[0]
+		if m := lineMessage.FindStringSubmatch(first); m != nil {
+			de.Line, _ = strconv.Atoi(m[1])
+			switch {
+			case strings.HasPrefix(m[2], "field ") && strings.Contains(m[2], " not found in type "):
+				de.Kind = codec.ErrUnknownField
+			case strings.HasPrefix(m[2], "mapping key ") && strings.Contains(m[2], " already defined "):
+				de.Kind = codec.ErrDuplicateKey
+			}
+		}
+		if node := findNode(data, de.Line, de.Kind != codec.ErrTypeMismatch); node != nil {
+			de.Path, de.Column = node.path, node.column
+		}
+	case strings.HasPrefix(msg, "yaml: ") && !strings.HasPrefix(msg, "yaml: input error"):
+		de.Kind = codec.ErrSyntax
+		if strings.HasSuffix(msg, "found unexpected end of stream") {
+			de.Kind = codec.ErrTruncated
+		}
+		if m := lineMessage.FindStringSubmatch(strings.TrimPrefix(msg, "yaml: ")); m != nil {
+			de.Line, _ = strconv.Atoi(m[1])
+			de.Column = 1
+		}
+	default:
+		return err
+	}
+
+	if data != nil && de.Line > 0 {
+		de.Offset = codec.Offset(data, de.Line, de.Column)
+	}
+	return de
+}
+
+// nodeLocation identifies the node an error was reported for
+type nodeLocation struct {
+	path   string
+	column int
+}
+
+// findNode returns the location of the first mapping value (or mapping key,
+// if key is true) on the given line of data, or nil if data is unavailable
+// or cannot be parsed.
+func findNode(data []byte, line int, key bool) *nodeLocation {
+	if data == nil || line <= 0 {
+		return nil
+	}
+	var root yaml.Node
+	if err := yaml.Unmarshal(data, &root); err != nil {
+		return nil
+	}
+	return walkNode(&root, "", line, key)
+}
+
+func walkNode(n *yaml.Node, path string, line int, key bool) *nodeLocation {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, child := range n.Content {
+			if loc := walkNode(child, path, line, key); loc != nil {
+				return loc
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			p := k.Value
+			if path != "" {
+				p = path + "." + k.Value
+			}
+			if key && k.Line == line {
+				return &nodeLocation{path: p, column: k.Column}
+			}
+			if !key && v.Line == line && v.Kind != yaml.MappingNode {
+				return &nodeLocation{path: p, column: v.Column}
+			}
+			if loc := walkNode(v, p, line, key); loc != nil {
+				return loc
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			p := path + "[" + strconv.Itoa(i) + "]"
+			if !key && item.Line == line && item.Kind == yaml.ScalarNode {
+				return &nodeLocation{path: p, column: item.Column}
+			}
+			if loc := walkNode(item, p, line, key); loc != nil {
+				return loc
+			}
+		}
+	}
+	return nil
+}
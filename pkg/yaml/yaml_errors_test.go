@@ -0,0 +1,113 @@
+//go:build codec_yaml
+
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	"gopkg.in/yaml.v3"
+)
+
+type userRecord struct {
+	User struct {
+		Name string `yaml:"name"`
+		Tags []int  `yaml:"tags"`
+		Age  int    `yaml:"age"`
+	} `yaml:"user"`
+}
+
+func TestUnmarshal_SyntaxError(t *testing.T) {
+	var v TestStruct
+	err := New[TestStruct]().Unmarshal([]byte("name: John\nage: 30\n  email: x\n"), &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Codec != codec.YAML || de.Kind != codec.ErrSyntax {
+		t.Errorf("unexpected codec/kind: %s/%s", de.Codec, de.Kind)
+	}
+	if de.Line != 3 || de.Offset != 19 {
+		t.Errorf("expected line 3 offset 19, got line %d offset %d", de.Line, de.Offset)
+	}
+}
+
+func TestUnmarshal_TypeMismatch(t *testing.T) {
+	input := "user:\n  name: John\n  tags: [1, 2]\n  age: thirty\n"
+	var v userRecord
+	err := New[userRecord]().Unmarshal([]byte(input), &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Kind != codec.ErrTypeMismatch {
+		t.Errorf("expected type mismatch, got %s", de.Kind)
+	}
+	if de.Path != "user.age" || de.Line != 4 || de.Column != 8 {
+		t.Errorf("expected user.age at 4:8, got %q at %d:%d", de.Path, de.Line, de.Column)
+	}
+	if de.Offset != int64(strings.Index(input, "thirty")) {
+		t.Errorf("unexpected offset %d", de.Offset)
+	}
+
+	err = New[userRecord]().Unmarshal([]byte("user:\n  tags:\n    - 1\n    - two\n"), &v)
+	if !errors.As(err, &de) || de.Path != "user.tags[1]" {
+		t.Errorf("expected path user.tags[1], got %v", err)
+	}
+}
+
+func TestUnmarshal_Truncated(t *testing.T) {
+	var v TestStruct
+	err := New[TestStruct]().Unmarshal([]byte(`name: "John`), &v)
+	if !errors.Is(err, codec.ErrTruncated) {
+		t.Fatalf("expected truncated error, got %v", err)
+	}
+}
+
+func TestDecodeError_UnknownField(t *testing.T) {
+	input := []byte("user:\n  name: John\n  phone: 555\n")
+	decoder := yaml.NewDecoder(bytes.NewReader(input))
+	decoder.KnownFields(true)
+	var v userRecord
+	err := decodeError(decoder.Decode(&v), input)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Kind != codec.ErrUnknownField || de.Path != "user.phone" || de.Line != 3 || de.Column != 3 {
+		t.Errorf("unexpected error: %+v", de)
+	}
+}
+
+func TestDecoder_Errors(t *testing.T) {
+	dec := New[TestStruct]().NewDecoder(strings.NewReader("name: John\n---\nage: [\n"))
+
+	var v TestStruct
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	err := dec.Decode(&v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) {
+		t.Fatalf("expected DecodeError, got %v", err)
+	}
+	if de.Line == 0 || de.Offset != -1 {
+		t.Errorf("expected line without offset, got %+v", de)
+	}
+
+	dec = New[TestStruct]().NewDecoder(strings.NewReader("name: John\n"))
+	if err := dec.Decode(&v); err != nil {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if err := dec.Decode(&v); err != io.EOF {
+		t.Fatalf("expected io.EOF, got %v", err)
+	}
+}
@@ -0,0 +1,153 @@
+//go:build codec_yaml
+
+package yaml
+
+import (
+	"io"
+	"math"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"gopkg.in/yaml.v3"
+)
+
+// nodeLimitError is a limit violation found at a node of the tree
+type nodeLimitError struct {
+	node *yaml.Node
+	err  error
+}
+
+// decodeError returns the DecodeError for the violation; data is the
+// complete input when it is available and is used to compute the offset
+func (e *nodeLimitError) decodeError(data []byte) error {
+	de := codec.DecodeError{
+		Codec:  codec.YAML,
+		Kind:   codec.ErrLimitExceeded,
+		Offset: -1,
+		Line:   e.node.Line,
+		Column: e.node.Column,
+		Err:    e.err,
+	}
+	if data != nil {
+		de.Offset = codec.Offset(data, de.Line, de.Column)
+	}
+	return de
+}
+
+// checkNode checks the node tree rooted at root against limits. Aliases
+// count toward MaxAliasExpansion and MaxDepth with the size and depth of
+// the node they refer to, which bounds "billion laughs" documents.
+func checkNode(root *yaml.Node, limits codec.Limits) *nodeLimitError {
+	checker := nodeChecker{
+		limits: limits,
+		sizes:  make(map[*yaml.Node]int64),
+		depths: make(map[*yaml.Node]int),
+	}
+	return checker.check(root, 0)
+}
+
+// nodeChecker walks a node tree, memoizing the expanded size and depth of
+// anchored nodes
+type nodeChecker struct {
+	limits   codec.Limits
+	expanded int64
+	sizes    map[*yaml.Node]int64
+	depths   map[*yaml.Node]int
+}
+
+func (c *nodeChecker) check(n *yaml.Node, depth int) *nodeLimitError {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, child := range n.Content {
+			if err := c.check(child, depth); err != nil {
+				return err
+			}
+		}
+		return nil
+	case yaml.AliasNode:
+		c.expanded += c.size(n.Alias)
+		if err := c.limits.CheckAliasExpansion(c.expanded); err != nil {
+			return &nodeLimitError{node: n, err: err}
+		}
+		if err := c.limits.CheckDepth(depth + c.depth(n.Alias)); err != nil {
+			return &nodeLimitError{node: n, err: err}
+		}
+		return nil
+	case yaml.ScalarNode:
+		if err := c.limits.CheckStringLength(int64(len(n.Value))); err != nil {
+			return &nodeLimitError{node: n, err: err}
+		}
+		return nil
+	}
+
+	depth++
+	if err := c.limits.CheckDepth(depth); err != nil {
+		return &nodeLimitError{node: n, err: err}
+	}
+	elements := int64(len(n.Content))
+	if n.Kind == yaml.MappingNode {
+		elements /= 2
+	}
+	if err := c.limits.CheckElements(elements); err != nil {
+		return &nodeLimitError{node: n, err: err}
+	}
+	for _, child := range n.Content {
+		if err := c.check(child, depth); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// size returns the number of nodes n expands to, with aliases expanded
+func (c *nodeChecker) size(n *yaml.Node) int64 {
+	if n.Kind == yaml.AliasNode {
+		return c.size(n.Alias)
+	}
+	if size, ok := c.sizes[n]; ok {
+		if size < 0 {
+			// The node contains an alias to itself
+			return math.MaxInt32
+		}
+		return size
+	}
+	c.sizes[n] = -1
+	size := int64(1)
+	for _, child := range n.Content {
+		size += c.size(child)
+	}
+	c.sizes[n] = size
+	return size
+}
+
+// depth returns the nesting depth of n, with aliases expanded
+func (c *nodeChecker) depth(n *yaml.Node) int {
+	if n.Kind == yaml.AliasNode {
+		return c.depth(n.Alias)
+	}
+	if depth, ok := c.depths[n]; ok {
+		if depth < 0 {
+			return math.MaxInt32
+		}
+		return depth
+	}
+	c.depths[n] = -1
+	depth := 0
+	for _, child := range n.Content {
+		depth = max(depth, c.depth(child))
+	}
+	if n.Kind == yaml.MappingNode || n.Kind == yaml.SequenceNode {
+		depth++
+	}
+	c.depths[n] = depth
+	return depth
+}
+
+// readError returns the limit error of r, a reader returned by
+// codec.NewLimitedReader, in place of err. yaml.v3 reports read errors as
+// text, so the typed error has to be recovered from the reader.
+func readError(r io.Reader, err error) error {
+	if limit, ok := r.(*codec.LimitedReader); ok && limit.Err() != nil && err != nil {
+		return limit.Err()
+	}
+	return err
+}
@@ -0,0 +1,96 @@
+//go:build codec_yaml
+
+package yaml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+const billionLaughs = `a: &a ["lol","lol","lol","lol","lol","lol","lol","lol","lol"]
+b: &b [*a,*a,*a,*a,*a,*a,*a,*a,*a]
+c: &c [*b,*b,*b,*b,*b,*b,*b,*b,*b]
+d: &d [*c,*c,*c,*c,*c,*c,*c,*c,*c]
+`
+
+func TestLimits_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name   string
+		limits codec.Limits
+		input  string
+		limit  string
+		line   int
+	}{
+		{"bytes", codec.Limits{MaxBytes: 8}, "name: John\n", "MaxBytes", 0},
+		{"depth", codec.Limits{MaxDepth: 2}, "a:\n  b:\n    c: 1\n", "MaxDepth", 3},
+		{"elements", codec.Limits{MaxElements: 2}, "a: 1\nb: [1, 2, 3]\n", "MaxElements", 2},
+		{"string", codec.Limits{MaxStringLength: 4}, "name: Johnny\n", "MaxStringLength", 1},
+		{"aliases", codec.Limits{MaxAliasExpansion: 1000}, billionLaughs, "MaxAliasExpansion", 4},
+		{"alias depth", codec.Limits{MaxDepth: 3}, billionLaughs, "MaxDepth", 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v any
+			err := New[any](codec.WithLimits(tt.limits)).Unmarshal([]byte(tt.input), &v)
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			var le codec.LimitError
+			if de.Kind != codec.ErrLimitExceeded || !errors.As(err, &le) || le.Limit != tt.limit {
+				t.Fatalf("expected %s exceeded, got %v", tt.limit, err)
+			}
+			if de.Line != tt.line {
+				t.Errorf("expected line %d, got %d", tt.line, de.Line)
+			}
+		})
+	}
+}
+
+func TestLimits_WithinLimits(t *testing.T) {
+	c := New[TestStruct](codec.WithLimits(codec.Limits{
+		MaxBytes: 64, MaxDepth: 1, MaxElements: 3, MaxStringLength: 16, MaxAliasExpansion: 1,
+	}))
+
+	var v TestStruct
+	if err := c.Unmarshal([]byte("name: &n John\nage: 30\nemail: *n\n"), &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if v.Email != "John" {
+		t.Errorf("expected alias to be decoded, got %q", v.Email)
+	}
+	if err := c.Unmarshal(nil, &v); err != nil {
+		t.Fatalf("Unmarshal of empty input failed: %v", err)
+	}
+}
+
+func TestLimits_Decoder(t *testing.T) {
+	c := New[TestStruct](codec.WithLimits(codec.Limits{MaxBytes: 32}))
+	input := "name: John\n---\nname: Jane\n---\nname: " + strings.Repeat("x", 100) + "\n"
+	dec := c.NewDecoder(strings.NewReader(input))
+
+	var v TestStruct
+	for i := 0; i < 2; i++ {
+		if err := dec.Decode(&v); err != nil {
+			t.Fatalf("Decode %d failed: %v", i, err)
+		}
+	}
+	err := dec.Decode(&v)
+	var le codec.LimitError
+	if !errors.Is(err, codec.ErrLimitExceeded) || !errors.As(err, &le) {
+		t.Fatalf("expected limit exceeded, got %v", err)
+	}
+
+	c = New[TestStruct](codec.WithLimits(codec.Limits{MaxStringLength: 4}))
+	err = c.Decode(strings.NewReader("name: Johnny\n"), &v)
+
+	var de codec.DecodeError
+	if !errors.As(err, &de) || de.Kind != codec.ErrLimitExceeded || de.Line != 1 || de.Column != 7 {
+		t.Fatalf("expected limit exceeded at 1:7, got %v", err)
+	}
+}
@@ -0,0 +1,36 @@
+package yaml
+
+import (
+	"fmt"
+
+	codec "github.com/jeremyhahn/go-codec"
+)
+
+// config holds the settings applied by YAML options
+type config struct {
+	codec.Config
+
+	indent int
+}
+
+// prescans reports whether documents are parsed into a node tree and
+// checked before they are decoded
+func (c *config) prescans() bool {
+	return c.Strict || c.Limits.Structural()
+}
+
+// validate reports settings the YAML encoder cannot honour
+func (c *config) validate() error {
+	if c.indent != 0 && (c.indent < 2 || c.indent > 9) {
+		return fmt.Errorf("yaml: indent of %d spaces is outside the range 2 to 9", c.indent)
+	}
+	return nil
+}
+
+// WithIndent sets the number of spaces used for each level of indentation
+// in encoded output, from 2 to 9. The YAML encoder defaults to 4.
+func WithIndent(spaces int) codec.Option {
+	return codec.NewOption("yaml.WithIndent", func(c *config) {
+		c.indent = spaces
+	})
+}
@@ -0,0 +1,99 @@
+//go:build codec_yaml
+
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+	jsoncodec "github.com/jeremyhahn/go-codec/pkg/json"
+)
+
+type nested struct {
+	Inner TestStruct `yaml:"inner"`
+}
+
+func TestWithIndent(t *testing.T) {
+	c := New[nested](WithIndent(2))
+	result, err := c.Marshal(nested{Inner: TestStruct{Name: "John"}})
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if !strings.Contains(string(result), "\n  name: John") {
+		t.Errorf("expected two-space indentation, got %q", result)
+	}
+
+	var buf bytes.Buffer
+	if err := c.NewEncoder(&buf).Encode(nested{Inner: TestStruct{Name: "Jane"}}); err != nil {
+		t.Fatalf("Encode failed: %v", err)
+	}
+	if !strings.Contains(buf.String(), "\n  name: Jane") {
+		t.Errorf("expected two-space indentation, got %q", buf.String())
+	}
+
+	var decoded nested
+	if err := c.Unmarshal(result, &decoded); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if decoded.Inner.Name != "John" {
+		t.Errorf("expected name John, got %q", decoded.Inner.Name)
+	}
+}
+
+func TestWithIndent_Invalid(t *testing.T) {
+	for _, spaces := range []int{-1, 1, 10} {
+		c := New[nested](WithIndent(spaces))
+		if c.Err() == nil {
+			t.Fatalf("WithIndent(%d): expected an error from Err", spaces)
+		}
+		if _, err := c.Marshal(nested{}); err != c.Err() {
+			t.Errorf("WithIndent(%d): Marshal error = %v, want %v", spaces, err, c.Err())
+		}
+		if err := c.Encode(&bytes.Buffer{}, nested{}); err != c.Err() {
+			t.Errorf("WithIndent(%d): Encode error = %v, want %v", spaces, err, c.Err())
+		}
+		if err := c.NewEncoder(&bytes.Buffer{}).Encode(nested{}); err != c.Err() {
+			t.Errorf("WithIndent(%d): Encoder.Encode error = %v, want %v", spaces, err, c.Err())
+		}
+		if err := NewPool[nested](WithIndent(spaces)).Err(); err == nil {
+			t.Errorf("WithIndent(%d): NewPool accepted the width", spaces)
+		}
+	}
+}
+
+func TestNew_OptionNotSupported(t *testing.T) {
+	c := New[TestStruct](jsoncodec.WithUseNumber())
+
+	var notSupported codec.ErrOptionNotSupported
+	if !errors.As(c.Err(), &notSupported) {
+		t.Fatalf("expected ErrOptionNotSupported, got %v", c.Err())
+	}
+
+	var result TestStruct
+	if _, err := c.Marshal(TestStruct{}); err == nil {
+		t.Error("Marshal: expected option error, got nil")
+	}
+	if err := c.Unmarshal([]byte("name: x"), &result); err == nil {
+		t.Error("Unmarshal: expected option error, got nil")
+	}
+	if err := c.Encode(&bytes.Buffer{}, TestStruct{}); err == nil {
+		t.Error("Encode: expected option error, got nil")
+	}
+	if err := c.Decode(strings.NewReader("name: x"), &result); err == nil {
+		t.Error("Decode: expected option error, got nil")
+	}
+
+	enc := c.NewEncoder(&bytes.Buffer{})
+	if err := enc.Encode(TestStruct{}); err == nil {
+		t.Error("Encoder.Encode: expected option error, got nil")
+	}
+	if err := enc.(*Encoder[TestStruct]).Close(); err == nil {
+		t.Error("Encoder.Close: expected option error, got nil")
+	}
+	if err := c.NewDecoder(strings.NewReader("name: x")).Decode(&result); err == nil {
+		t.Error("Decoder.Decode: expected option error, got nil")
+	}
+}
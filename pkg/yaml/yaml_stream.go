@@ -0,0 +1,77 @@
+//go:build codec_yaml
+
+package yaml
+
+import (
+	"io"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"gopkg.in/yaml.v3"
+)
+
+// Encoder writes a sequence of YAML documents to a stream, separated by "---"
+type Encoder[T any] struct {
+	codec *Codec[T]
+	enc   *yaml.Encoder
+	err   error
+}
+
+// Decoder reads a sequence of YAML documents from a stream
+type Decoder[T any] struct {
+	codec *Codec[T]
+	dec   *yaml.Decoder
+	r     io.Reader
+	err   error
+}
+
+// NewEncoder returns an encoder session that writes successive YAML documents to w
+func (c *Codec[T]) NewEncoder(w io.Writer) codec.Encoder[T] {
+	if c.err != nil {
+		return &Encoder[T]{err: c.err}
+	}
+	return &Encoder[T]{codec: c, enc: c.newEncoder(w)}
+}
+
+// NewDecoder returns a decoder session that reads successive YAML documents from r
+func (c *Codec[T]) NewDecoder(r io.Reader) codec.Decoder[T] {
+	if c.err != nil {
+		return &Decoder[T]{err: c.err}
+	}
+	r = codec.NewLimitedReader(r, codec.YAML, c.cfg.Limits.MaxBytes)
+	return &Decoder[T]{codec: c, dec: yaml.NewDecoder(r), r: r}
+}
+
+// Encode writes the next YAML document to the stream
+func (e *Encoder[T]) Encode(data T) error {
+	if e.err != nil {
+		return e.err
+	}
+	if v, ok := any(data).(codec.Value); ok {
+		node, err := e.codec.marshalValue(v)
+		if err != nil {
+			return err
+		}
+		return e.enc.Encode(node)
+	}
+	return e.enc.Encode(data)
+}
+
+// Close terminates the YAML stream. Documents written by Encode are flushed
+// as they are encoded, so calling Close is optional.
+func (e *Encoder[T]) Close() error {
+	if e.err != nil {
+		return e.err
+	}
+	return e.enc.Close()
+}
+
+// Decode reads the next YAML document from the stream
+func (d *Decoder[T]) Decode(data *T) error {
+	if d.err != nil {
+		return d.err
+	}
+	if limit, ok := d.r.(*codec.LimitedReader); ok {
+		limit.Reset()
+	}
+	return d.codec.decodeNext(d.dec, d.r, data)
+}
@@ -0,0 +1,100 @@
+//go:build codec_yaml
+
+package yaml
+
+import (
+	"bytes"
+	"fmt"
+	"strconv"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"github.com/jeremyhahn/go-codec/internal/exact"
+	"gopkg.in/yaml.v3"
+)
+
+// decodeStrict decodes node, parsed from data if available, into v,
+// rejecting fields v has no place for and numbers v cannot hold exactly.
+// yaml.v3 applies KnownFields only when decoding text, so a node parsed
+// from a stream is encoded again first.
+func decodeStrict[T any](node *yaml.Node, data []byte, v *T) error {
+	src := data
+	if src == nil {
+		var err error
+		if src, err = yaml.Marshal(node); err != nil {
+			return err
+		}
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(src))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(v); err != nil {
+		return decodeError(err, data)
+	}
+	return checkExact(node, data, *v)
+}
+
+// checkExact compares the numbers in node, parsed from data if available,
+// with those held by v, which node was decoded into. yaml.v3 truncates
+// floats decoded into integers and lets float32 overflow to infinity.
+func checkExact[T any](node *yaml.Node, data []byte, v T) error {
+	var input, decoded any
+	if err := node.Decode(&input); err != nil {
+		return nil
+	}
+	out, err := yaml.Marshal(v)
+	if err != nil || yaml.Unmarshal(out, &decoded) != nil {
+		// Types that cannot be encoded again are not checked
+		return nil
+	}
+	path, value, lossy := exact.Compare(input, decoded)
+	if !lossy {
+		return nil
+	}
+	de := codec.DecodeError{
+		Codec:  codec.YAML,
+		Kind:   codec.ErrTypeMismatch,
+		Offset: -1,
+		Path:   path,
+		Err:    fmt.Errorf("yaml: %v cannot be stored exactly in the destination", value),
+	}
+	if n := nodeAt(node, "", path); n != nil {
+		de.Line, de.Column = n.Line, n.Column
+		if data != nil {
+			de.Offset = codec.Offset(data, n.Line, n.Column)
+		}
+	}
+	return de
+}
+
+// nodeAt returns the node below n, whose path is path, at the given target
+// path, or nil if there is none
+func nodeAt(n *yaml.Node, path, target string) *yaml.Node {
+	if path == target && n.Kind == yaml.ScalarNode {
+		return n
+	}
+	switch n.Kind {
+	case yaml.DocumentNode:
+		for _, child := range n.Content {
+			if found := nodeAt(child, path, target); found != nil {
+				return found
+			}
+		}
+	case yaml.MappingNode:
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			p := n.Content[i].Value
+			if path != "" {
+				p = path + "." + p
+			}
+			if found := nodeAt(n.Content[i+1], p, target); found != nil {
+				return found
+			}
+		}
+	case yaml.SequenceNode:
+		for i, item := range n.Content {
+			if found := nodeAt(item, path+"["+strconv.Itoa(i)+"]", target); found != nil {
+				return found
+			}
+		}
+	}
+	return nil
+}
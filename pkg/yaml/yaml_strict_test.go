@@ -0,0 +1,70 @@
+//go:build codec_yaml
+
+package yaml
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestStrict_Unmarshal(t *testing.T) {
+	tests := []struct {
+		name  string
+		input string
+		kind  codec.ErrorKind
+		path  string
+		line  int
+	}{
+		{"unknown field", "user:\n  name: John\n  nmae: Jane\n", codec.ErrUnknownField, "user.nmae", 3},
+		{"duplicate key", "user:\n  name: John\n  name: Jane\n", codec.ErrDuplicateKey, "user.name", 3},
+		{"truncated float", "user:\n  age: 30.5\n", codec.ErrTypeMismatch, "user.age", 2},
+		{"lossy list item", "user:\n  tags: [1, 2.5]\n", codec.ErrTypeMismatch, "user.tags[1]", 2},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var v userRecord
+			err := New[userRecord](codec.WithStrict()).Unmarshal([]byte(tt.input), &v)
+
+			var de codec.DecodeError
+			if !errors.As(err, &de) {
+				t.Fatalf("expected DecodeError, got %v", err)
+			}
+			if de.Kind != tt.kind || de.Path != tt.path || de.Line != tt.line {
+				t.Errorf("expected %v at %s (line %d), got %v", tt.kind, tt.path, tt.line, err)
+			}
+		})
+	}
+}
+
+func TestStrict_Exact(t *testing.T) {
+	c := New[userRecord](codec.WithStrict(), codec.WithLimits(codec.Limits{MaxDepth: 4}))
+	var v userRecord
+	if err := c.Unmarshal([]byte("user:\n  name: John\n  age: 30.0\n  tags: [1, 2]\n"), &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if v.User.Age != 30 || len(v.User.Tags) != 2 {
+		t.Errorf("unexpected value %+v", v)
+	}
+
+	var empty userRecord
+	if err := c.Unmarshal(nil, &empty); err != nil {
+		t.Errorf("expected empty input to decode, got %v", err)
+	}
+}
+
+func TestStrict_Decoder(t *testing.T) {
+	c := New[TestStruct](codec.WithStrict())
+	dec := c.NewDecoder(strings.NewReader("name: John\n---\nname: Jane\nnmae: Doe\n"))
+
+	var v TestStruct
+	if err := dec.Decode(&v); err != nil || v.Name != "John" {
+		t.Fatalf("Decode failed: %v", err)
+	}
+	if err := dec.Decode(&v); !errors.Is(err, codec.ErrUnknownField) {
+		t.Fatalf("expected unknown field, got %v", err)
+	}
+}
@@ -0,0 +1,414 @@
+//go:build codec_yaml
+
+package yaml
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	codec "github.com/jeremyhahn/go-codec"
+	"gopkg.in/yaml.v3"
+)
+
+// YAML tags with a counterpart in codec.Value
+const (
+	tagNull      = "!!null"
+	tagBool      = "!!bool"
+	tagInt       = "!!int"
+	tagFloat     = "!!float"
+	tagStr       = "!!str"
+	tagBinary    = "!!binary"
+	tagTimestamp = "!!timestamp"
+	tagSeq       = "!!seq"
+	tagMap       = "!!map"
+	tagMerge     = "!!merge"
+)
+
+// scalarTags are the tags of scalars with a counterpart in codec.Value
+var scalarTags = map[string]bool{
+	tagNull:      true,
+	tagBool:      true,
+	tagInt:       true,
+	tagFloat:     true,
+	tagStr:       true,
+	tagBinary:    true,
+	tagTimestamp: true,
+}
+
+// valueCodec converts between YAML and codec.Value
+type valueCodec struct{}
+
+// DecodeValue reads the next YAML document from r. Aliases decode as the
+// value of their anchor and merge keys are applied. Application-specific
+// tags decode as tagged Values holding the value they were applied to.
+func (valueCodec) DecodeValue(r io.Reader, cfg codec.Config, lossy func(codec.Loss)) (codec.Value, error) {
+	r = codec.NewLimitedReader(r, codec.YAML, cfg.Limits.MaxBytes)
+	var node yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&node); err != nil {
+		return codec.Value{}, readError(r, decodeError(err, nil))
+	}
+	if cfg.Limits.Structural() {
+		if err := checkNode(&node, cfg.Limits); err != nil {
+			return codec.Value{}, err.decodeError(nil)
+		}
+	}
+	return decodeValue(&node, lossy)
+}
+
+// decodeValue converts the node tree of a document
+func decodeValue(node *yaml.Node, lossy func(codec.Loss)) (codec.Value, error) {
+	d := valueDecoder{anchors: make(map[*yaml.Node]codec.Value), lossy: lossy}
+	return d.decode(node, "")
+}
+
+// convertValue converts node, which the codec's limits have been checked
+// against, into v for a Codec[codec.Value], failing on lossy conversions if
+// the codec is strict
+func (c *Codec[T]) convertValue(node *yaml.Node, v *codec.Value) error {
+	lossy, check := codec.CollectLosses(codec.YAML, c.cfg.Strict)
+	value, err := decodeValue(node, lossy)
+	if err == nil {
+		err = check()
+	}
+	if err != nil {
+		return err
+	}
+	*v = value
+	return nil
+}
+
+// marshalValue returns the node tree of v for a Codec[codec.Value],
+// failing on lossy conversions if the codec is strict
+func (c *Codec[T]) marshalValue(v codec.Value) (*yaml.Node, error) {
+	lossy, check := codec.CollectLosses(codec.YAML, c.cfg.Strict)
+	node := valueEncoder{lossy: lossy}.node(v, "")
+	if err := check(); err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+// valueDecoder builds a codec.Value from a node tree, converting each
+// anchored node once
+type valueDecoder struct {
+	anchors map[*yaml.Node]codec.Value
+	lossy   func(codec.Loss)
+}
+
+func (d valueDecoder) decode(n *yaml.Node, path string) (codec.Value, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return codec.NullValue(), nil
+		}
+		return d.decode(n.Content[0], path)
+	case yaml.AliasNode:
+		if v, ok := d.anchors[n.Alias]; ok {
+			return v, nil
+		}
+		return d.decode(n.Alias, path)
+	}
+
+	var v codec.Value
+	var err error
+	switch n.Kind {
+	case yaml.SequenceNode:
+		v, err = d.sequence(n, path)
+	case yaml.MappingNode:
+		v, err = d.mapping(n, path)
+	default:
+		v, err = d.scalar(n, path)
+	}
+	if err != nil {
+		return codec.Value{}, err
+	}
+	if n.Anchor != "" {
+		d.anchors[n] = v
+	}
+	return v, nil
+}
+
+func (d valueDecoder) sequence(n *yaml.Node, path string) (codec.Value, error) {
+	items := make([]codec.Value, 0, len(n.Content))
+	for i, child := range n.Content {
+		item, err := d.decode(child, path+"["+strconv.Itoa(i)+"]")
+		if err != nil {
+			return codec.Value{}, err
+		}
+		items = append(items, item)
+	}
+	return tagged(n, tagSeq, codec.ArrayValue(items...)), nil
+}
+
+// mapping decodes a mapping node. Keys from merged mappings do not replace
+// keys given explicitly, wherever they appear; a key given explicitly
+// twice is an error, as it is when decoding into a Go map.
+func (d valueDecoder) mapping(n *yaml.Node, path string) (codec.Value, error) {
+	var members []codec.Member
+	type entry struct {
+		index  int
+		merged bool
+	}
+	index := make(map[string]entry)
+	add := func(key, value codec.Value, merged bool, keyNode *yaml.Node) error {
+		id := key.Kind().String() + ":" + key.String()
+		e, ok := index[id]
+		switch {
+		case !ok:
+			index[id] = entry{len(members), merged}
+			members = append(members, codec.Member{Key: key, Value: value})
+		case merged:
+			// An explicit key or an earlier merge takes precedence
+		case e.merged:
+			index[id] = entry{e.index, false}
+			members[e.index].Value = value
+		default:
+			return duplicateKeyError(keyNode, path, key.String())
+		}
+		return nil
+	}
+
+	for i := 0; i+1 < len(n.Content); i += 2 {
+		keyNode, valueNode := n.Content[i], n.Content[i+1]
+		if keyNode.Kind == yaml.ScalarNode && keyNode.ShortTag() == tagMerge {
+			merged, err := d.merge(valueNode, path)
+			if err != nil {
+				return codec.Value{}, err
+			}
+			for _, m := range merged {
+				_ = add(m.Key, m.Value, true, keyNode)
+			}
+			continue
+		}
+		key, err := d.decode(keyNode, path)
+		if err != nil {
+			return codec.Value{}, err
+		}
+		value, err := d.decode(valueNode, joinPath(path, key.String()))
+		if err != nil {
+			return codec.Value{}, err
+		}
+		if err := add(key, value, false, keyNode); err != nil {
+			return codec.Value{}, err
+		}
+	}
+	return tagged(n, tagMap, codec.MapValue(members...)), nil
+}
+
+// merge returns the members merged by the value of a merge key: a mapping
+// or a sequence of mappings, of which earlier ones take precedence
+func (d valueDecoder) merge(n *yaml.Node, path string) ([]codec.Member, error) {
+	v, err := d.decode(n, path)
+	if err != nil {
+		return nil, err
+	}
+	switch v.Kind() {
+	case codec.KindMap:
+		return v.Members(), nil
+	case codec.KindArray:
+		var members []codec.Member
+		for _, item := range v.Array() {
+			if item.Kind() == codec.KindMap {
+				members = append(members, item.Members()...)
+			}
+		}
+		return members, nil
+	}
+	return nil, codec.DecodeError{
+		Codec:  codec.YAML,
+		Kind:   codec.ErrTypeMismatch,
+		Offset: -1,
+		Line:   n.Line,
+		Column: n.Column,
+		Path:   path,
+		Err:    fmt.Errorf("yaml: map merge requires map or sequence of maps as the value"),
+	}
+}
+
+func (d valueDecoder) scalar(n *yaml.Node, path string) (codec.Value, error) {
+	tag := n.ShortTag()
+	if !scalarTags[tag] {
+		// Resolve the value as if it were untagged
+		plain := *n
+		plain.Tag = ""
+		if plain.Style&(yaml.DoubleQuotedStyle|yaml.SingleQuotedStyle|yaml.LiteralStyle|yaml.FoldedStyle) != 0 {
+			plain.Tag = tagStr
+		}
+		if !scalarTags[plain.ShortTag()] {
+			// A plain "<<" outside a mapping key
+			plain.Tag = tagStr
+		}
+		content, err := d.scalar(&plain, path)
+		if err != nil {
+			return codec.Value{}, err
+		}
+		return codec.TaggedValue(codec.Tag{Codec: codec.YAML, Name: tag}, content), nil
+	}
+
+	var err error
+	switch tag {
+	case tagNull:
+		return codec.NullValue(), nil
+	case tagBool:
+		var b bool
+		if err = n.Decode(&b); err == nil {
+			return codec.BoolValue(b), nil
+		}
+	case tagInt:
+		var i int64
+		if err = n.Decode(&i); err == nil {
+			return codec.IntValue(i), nil
+		}
+		var u uint64
+		if n.Decode(&u) == nil {
+			return codec.UintValue(u), nil
+		}
+		var f float64
+		if n.Decode(&f) == nil {
+			d.lossy(codec.Loss{Path: path, Reason: "integer beyond 64 bits decoded as float"})
+			return codec.FloatValue(f), nil
+		}
+	case tagFloat:
+		var f float64
+		if err = n.Decode(&f); err == nil {
+			return codec.FloatValue(f), nil
+		}
+	case tagBinary:
+		var b []byte
+		if b, err = base64.StdEncoding.DecodeString(strings.Join(strings.Fields(n.Value), "")); err == nil {
+			return codec.BytesValue(b), nil
+		}
+	case tagTimestamp:
+		var t time.Time
+		if err = n.Decode(&t); err == nil {
+			return codec.TimeValue(t), nil
+		}
+	default:
+		return codec.StringValue(n.Value), nil
+	}
+	return codec.Value{}, codec.DecodeError{
+		Codec:  codec.YAML,
+		Kind:   codec.ErrTypeMismatch,
+		Offset: -1,
+		Line:   n.Line,
+		Column: n.Column,
+		Path:   path,
+		Err:    err,
+	}
+}
+
+// tagged returns v, decoded from the sequence or mapping n, tagged with
+// the tag of n unless it is the standard tag
+func tagged(n *yaml.Node, standard string, v codec.Value) codec.Value {
+	if tag := n.ShortTag(); tag != standard {
+		return codec.TaggedValue(codec.Tag{Codec: codec.YAML, Name: tag}, v)
+	}
+	return v
+}
+
+// duplicateKeyError returns the error for the key at keyNode, repeated in
+// the mapping at path
+func duplicateKeyError(keyNode *yaml.Node, path, key string) error {
+	return codec.DecodeError{
+		Codec:  codec.YAML,
+		Kind:   codec.ErrDuplicateKey,
+		Offset: -1,
+		Line:   keyNode.Line,
+		Column: keyNode.Column,
+		Path:   path,
+		Err:    fmt.Errorf("yaml: mapping key %q already defined", key),
+	}
+}
+
+// EncodeValue writes v to w as a YAML document. Byte strings are tagged
+// !!binary and times !!timestamp, so YAML represents every Value exactly
+// except those tagged by other codecs, whose tags are dropped in favor of
+// their content and reported to lossy.
+func (valueCodec) EncodeValue(w io.Writer, v codec.Value, lossy func(codec.Loss)) error {
+	encoder := yaml.NewEncoder(w)
+	if err := encoder.Encode(valueEncoder{lossy: lossy}.node(v, "")); err != nil {
+		_ = encoder.Close()
+		return err
+	}
+	return encoder.Close()
+}
+
+// valueEncoder builds the node tree of a codec.Value
+type valueEncoder struct {
+	lossy func(codec.Loss)
+}
+
+// node returns the node tree for v
+func (e valueEncoder) node(v codec.Value, path string) *yaml.Node {
+	scalar := func(tag, value string) *yaml.Node {
+		return &yaml.Node{Kind: yaml.ScalarNode, Tag: tag, Value: value}
+	}
+	switch v.Kind() {
+	case codec.KindBool:
+		return scalar(tagBool, v.String())
+	case codec.KindInt, codec.KindUint:
+		return scalar(tagInt, v.String())
+	case codec.KindFloat:
+		return scalar(tagFloat, formatFloat(v.Float()))
+	case codec.KindString:
+		return scalar(tagStr, v.String())
+	case codec.KindBytes:
+		return scalar(tagBinary, v.String())
+	case codec.KindTime:
+		return scalar(tagTimestamp, v.String())
+	case codec.KindArray:
+		n := &yaml.Node{Kind: yaml.SequenceNode, Tag: tagSeq}
+		for i, item := range v.Array() {
+			n.Content = append(n.Content, e.node(item, path+"["+strconv.Itoa(i)+"]"))
+		}
+		return n
+	case codec.KindMap:
+		n := &yaml.Node{Kind: yaml.MappingNode, Tag: tagMap}
+		for _, m := range v.Members() {
+			n.Content = append(n.Content, e.node(m.Key, path), e.node(m.Value, joinPath(path, m.Key.String())))
+		}
+		return n
+	case codec.KindTagged:
+		tag := v.Tag()
+		n := e.node(v.Content(), path)
+		if tag.Codec != codec.YAML {
+			e.lossy(codec.Loss{Path: path, Reason: "tag " + tag.String() + " dropped"})
+			return n
+		}
+		n.Tag = tag.Name
+		return n
+	default:
+		return scalar(tagNull, "null")
+	}
+}
+
+// formatFloat formats f as a YAML float, keeping a fraction on integral
+// values so that they resolve as floats again
+func formatFloat(f float64) string {
+	switch {
+	case math.IsNaN(f):
+		return ".nan"
+	case math.IsInf(f, 1):
+		return ".inf"
+	case math.IsInf(f, -1):
+		return "-.inf"
+	}
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if !strings.ContainsAny(s, ".e") {
+		s += ".0"
+	}
+	return s
+}
+
+// joinPath appends key to the dotted path
+func joinPath(path, key string) string {
+	if path == "" {
+		return key
+	}
+	return path + "." + key
+}
@@ -0,0 +1,115 @@
+//go:build codec_yaml
+
+package yaml
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/jeremyhahn/go-codec"
+)
+
+func TestValue_RoundTrip(t *testing.T) {
+	input := `name: demo
+count: 3
+ratio: 1.0
+big: 18446744073709551615
+when: 2024-01-02T03:04:05.5+02:00
+blob: !!binary aGVsbG8=
+nothing: null
+tags:
+    - a
+    - "1"
+`
+	v, err := codec.DecodeValue(codec.YAML, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("DecodeValue failed: %v", err)
+	}
+
+	kinds := []codec.Kind{codec.KindString, codec.KindInt, codec.KindFloat, codec.KindUint,
+		codec.KindTime, codec.KindBytes, codec.KindNull, codec.KindArray}
+	for i, m := range v.Members() {
+		if m.Value.Kind() != kinds[i] {
+			t.Errorf("%s decoded as %s, want %s", m.Key, m.Value.Kind(), kinds[i])
+		}
+	}
+
+	var out bytes.Buffer
+	if err := codec.EncodeValue(codec.YAML, &out, v); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	if out.String() != input {
+		t.Errorf("EncodeValue =\n%s\nwant\n%s", out.String(), input)
+	}
+}
+
+func TestValue_Merge(t *testing.T) {
+	input := `base: &base
+    x: 1
+    y: 2
+derived:
+    y: 3
+    <<: *base
+`
+	v, err := codec.DecodeValue(codec.YAML, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("DecodeValue failed: %v", err)
+	}
+	if got := v.String(); got != "{base:{x:1 y:2} derived:{y:3 x:1}}" {
+		t.Errorf("DecodeValue = %s", got)
+	}
+}
+
+func TestValue_DuplicateKey(t *testing.T) {
+	_, err := codec.DecodeValue(codec.YAML, strings.NewReader("a: 1\na: 2\n"))
+	if !errors.Is(err, codec.ErrDuplicateKey) {
+		t.Errorf("Expected ErrDuplicateKey, got %v", err)
+	}
+}
+
+func TestValue_CustomTag(t *testing.T) {
+	input := "a: !secret 42\nb: !point\n    x: 1\n"
+	v, err := codec.DecodeValue(codec.YAML, strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("DecodeValue failed: %v", err)
+	}
+	a, _ := v.Lookup("a")
+	if a.Kind() != codec.KindTagged || a.Tag().Name != "!secret" || a.Content().Int() != 42 {
+		t.Fatalf("Expected !secret 42, got %s", a)
+	}
+
+	var out bytes.Buffer
+	if err := codec.EncodeValue(codec.YAML, &out, v); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	if out.String() != input {
+		t.Errorf("EncodeValue = %q, want %q", out.String(), input)
+	}
+}
+
+func TestCodec_Value(t *testing.T) {
+	c := New[codec.Value](WithIndent(2))
+	var v codec.Value
+	if err := c.Unmarshal([]byte("list: [1, two]\n"), &v); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	data, err := c.Marshal(v)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if want := "list:\n  - 1\n  - two\n"; string(data) != want {
+		t.Errorf("Marshal = %q, want %q", data, want)
+	}
+
+	decoder := c.NewDecoder(strings.NewReader("a: 1\n---\nb: 2\n"))
+	for _, want := range []string{"{a:1}", "{b:2}"} {
+		if err := decoder.Decode(&v); err != nil {
+			t.Fatalf("Decode failed: %v", err)
+		}
+		if v.String() != want {
+			t.Errorf("Decode = %s, want %s", v, want)
+		}
+	}
+}
@@ -0,0 +1,64 @@
+//go:build !codec_none
+
+package codec
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Provider constructs codecs of a codec type registered with Register.
+//
+// Interface methods cannot be generic, so a provider's codecs work with
+// values of type any, and factory.New adapts them to the requested type.
+// Encode and Marshal receive values of the type passed to New. Decode and
+// Unmarshal receive a pointer to an any that holds a non-nil pointer to a
+// value of that type, and decode into the value it points to. A codec that
+// also implements StreamCodec[any] is returned by factory.NewStream.
+type Provider interface {
+	// New returns a codec for values of type typ configured with opts. It
+	// should report options that do not apply as ErrOptionNotSupported,
+	// as ApplyOptions does.
+	New(typ reflect.Type, opts ...Option) (Codec[any], error)
+}
+
+// ProviderFunc adapts a function to a Provider
+type ProviderFunc func(typ reflect.Type, opts ...Option) (Codec[any], error)
+
+// New calls f(typ, opts...)
+func (f ProviderFunc) New(typ reflect.Type, opts ...Option) (Codec[any], error) {
+	return f(typ, opts...)
+}
+
+// Register makes a codec type provided by another package available to
+// factory.New, SupportedCodecs and IsSupported. It is intended to be called
+// from the init function of the package implementing the codec. Register
+// panics if info.Type is empty, if provider is nil, or if the type is
+// already registered, including by one of this module's codec packages.
+func Register(info CodecInfo, provider Provider) {
+	if info.Type == "" {
+		panic("codec: Register with empty codec type")
+	}
+	if provider == nil {
+		panic(fmt.Sprintf("codec: Register of %q with nil provider", info.Type))
+	}
+
+	codecMu.Lock()
+	defer codecMu.Unlock()
+	if _, dup := registry[info.Type]; dup {
+		panic(fmt.Sprintf("codec: Register called twice for codec %q", info.Type))
+	}
+	registry[info.Type] = &registration{info: info.clone(), provider: provider}
+}
+
+// ProviderFor returns the Provider registered for t, if any. Codec types
+// implemented by this module's codec packages have no provider.
+func ProviderFor(t Type) (Provider, bool) {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	r, ok := registry[t]
+	if !ok || r.provider == nil {
+		return nil, false
+	}
+	return r.provider, true
+}
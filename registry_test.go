@@ -0,0 +1,55 @@
+//go:build !codec_none
+
+package codec
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRegister(t *testing.T) {
+	provider := ProviderFunc(func(typ reflect.Type, opts ...Option) (Codec[any], error) {
+		return nil, nil
+	})
+	Register(CodecInfo{Type: "test_provider", Name: "Test"}, provider)
+
+	if !IsSupported("test_provider") {
+		t.Error("Expected registered codec to be supported")
+	}
+	if _, ok := ProviderFor("test_provider"); !ok {
+		t.Error("Expected provider for registered codec")
+	}
+
+	RegisterCodec("test_builtin")
+	if _, ok := ProviderFor("test_builtin"); ok {
+		t.Error("Expected no provider for codec registered with RegisterCodec")
+	}
+}
+
+func TestRegister_Panics(t *testing.T) {
+	provider := ProviderFunc(func(typ reflect.Type, opts ...Option) (Codec[any], error) {
+		return nil, nil
+	})
+	RegisterCodec("test_taken")
+
+	tests := []struct {
+		name     string
+		info     CodecInfo
+		provider Provider
+	}{
+		{"empty type", CodecInfo{}, provider},
+		{"nil provider", CodecInfo{Type: "test_nil"}, nil},
+		{"duplicate", CodecInfo{Type: "test_taken"}, provider},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			defer func() {
+				if recover() == nil {
+					t.Error("Expected Register to panic")
+				}
+			}()
+			Register(tt.info, tt.provider)
+		})
+	}
+}
@@ -8,9 +8,16 @@ import (
 	"sync"
 )
 
+// registration records a codec type that is available in the build
+type registration struct {
+	info     CodecInfo
+	provider Provider
+	value    ValueCodec
+}
+
 var (
-	supportedCodecs = make(map[Type]bool)
-	codecMu         sync.RWMutex
+	registry = make(map[Type]*registration)
+	codecMu  sync.RWMutex
 )
 
 // RegisterCodec registers a codec as supported. This is called by codec packages
@@ -18,16 +25,23 @@ var (
 func RegisterCodec(t Type) {
 	codecMu.Lock()
 	defer codecMu.Unlock()
-	supportedCodecs[t] = true
+	if _, ok := registry[t]; !ok {
+		info, ok := builtinInfo[t]
+		if !ok {
+			info = CodecInfo{Type: t}
+		}
+		registry[t] = &registration{info: info}
+	}
 }
 
-// SupportedCodecs returns a sorted list of all codecs that are compiled into the build.
+// SupportedCodecs returns a sorted list of all codecs that are compiled into
+// the build or registered with Register.
 func SupportedCodecs() []Type {
 	codecMu.RLock()
 	defer codecMu.RUnlock()
 
-	result := make([]Type, 0, len(supportedCodecs))
-	for t := range supportedCodecs {
+	result := make([]Type, 0, len(registry))
+	for t := range registry {
 		result = append(result, t)
 	}
 
@@ -39,11 +53,13 @@ func SupportedCodecs() []Type {
 	return result
 }
 
-// IsSupported returns true if the given codec type is compiled into the build.
+// IsSupported returns true if the given codec type is compiled into the build
+// or registered with Register.
 func IsSupported(t Type) bool {
 	codecMu.RLock()
 	defer codecMu.RUnlock()
-	return supportedCodecs[t]
+	_, ok := registry[t]
+	return ok
 }
 
 // ErrCodecNotSupported is returned when attempting to use a codec that
@@ -0,0 +1,257 @@
+//go:build !codec_none
+
+package codec
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// ValueCodec converts between the encoding of a codec type and Values. The
+// codec packages that can decode without a schema register one with
+// RegisterValueCodec, which makes their type available to DecodeValue,
+// EncodeValue and Transcode.
+//
+// Both directions report data the Value model or the encoding cannot
+// represent exactly, such as a CBOR tag written to JSON or a byte string
+// encoded to JSON as base64, to lossy and carry on with the closest
+// representation available.
+type ValueCodec interface {
+	// DecodeValue reads a single value from r, applying the limits and
+	// strictness of cfg
+	DecodeValue(r io.Reader, cfg Config, lossy func(Loss)) (Value, error)
+
+	// EncodeValue writes v to w
+	EncodeValue(w io.Writer, v Value, lossy func(Loss)) error
+}
+
+// RegisterValueCodec registers vc as the ValueCodec of codec type t, which
+// must already be registered with RegisterCodec or Register. It is intended
+// to be called from the init function of the package implementing the
+// codec, and panics if t is not registered.
+func RegisterValueCodec(t Type, vc ValueCodec) {
+	codecMu.Lock()
+	defer codecMu.Unlock()
+	r, ok := registry[t]
+	if !ok {
+		panic(fmt.Sprintf("codec: RegisterValueCodec of unregistered codec %q", t))
+	}
+	r.value = vc
+}
+
+// valueCodecFor returns the ValueCodec registered for t
+func valueCodecFor(t Type) (ValueCodec, error) {
+	codecMu.RLock()
+	defer codecMu.RUnlock()
+	r, ok := registry[t]
+	switch {
+	case !ok:
+		return nil, ErrCodecNotSupported{CodecType: t}
+	case r.value == nil:
+		return nil, ErrTranscodeNotSupported{CodecType: t}
+	}
+	return r.value, nil
+}
+
+// ErrTranscodeNotSupported is returned by Transcode, DecodeValue and
+// EncodeValue for codec types that cannot be converted without a Go type,
+// such as Protocol Buffers and Avro, whose encodings need a schema.
+type ErrTranscodeNotSupported struct {
+	CodecType Type
+}
+
+func (e ErrTranscodeNotSupported) Error() string {
+	return fmt.Sprintf("codec %q does not support transcoding", e.CodecType)
+}
+
+// Loss describes data that could not be converted exactly, such as a byte
+// string encoded to JSON as base64 text
+type Loss struct {
+	// Codec is the codec that could not represent the data. Codec packages
+	// may leave it empty; DecodeValue, EncodeValue and Transcode fill it in.
+	Codec Type
+
+	// Path is the dotted path of the value, e.g. "user.tags[2]"
+	Path string
+
+	// Reason describes what was lost
+	Reason string
+}
+
+func (l Loss) String() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s", l.Codec, l.Reason)
+	if l.Path != "" {
+		fmt.Fprintf(&b, " at %s", l.Path)
+	}
+	return b.String()
+}
+
+// LossError is returned by DecodeValue, EncodeValue and Transcode when data
+// cannot be converted exactly and WithAllowLossy was not given. Nothing is
+// written in that case.
+type LossError struct {
+	// Losses lists each value that could not be converted, in order
+	Losses []Loss
+}
+
+func (e *LossError) Error() string {
+	var b strings.Builder
+	b.WriteString("codec: lossy conversion")
+	if len(e.Losses) > 0 {
+		fmt.Fprintf(&b, ": %s", e.Losses[0])
+	}
+	if n := len(e.Losses) - 1; n > 0 {
+		fmt.Fprintf(&b, " (and %d more)", n)
+	}
+	return b.String()
+}
+
+// transcodeConfig is the configuration of DecodeValue, EncodeValue and
+// Transcode
+type transcodeConfig struct {
+	Config
+
+	allowLossy bool
+	report     func(Loss)
+}
+
+// WithAllowLossy lets DecodeValue, EncodeValue and Transcode convert data
+// that cannot be represented exactly to the closest form available, instead
+// of failing with a LossError. report, if not nil, is called for each such
+// value.
+func WithAllowLossy(report func(Loss)) Option {
+	return NewOption("codec.WithAllowLossy", func(cfg *transcodeConfig) {
+		cfg.allowLossy = true
+		cfg.report = report
+	})
+}
+
+// losses collects the losses of a conversion
+type losses struct {
+	cfg  *transcodeConfig
+	list []Loss
+}
+
+// collect returns the function reporting the losses of codec type t
+func (l *losses) collect(t Type) func(Loss) {
+	return func(loss Loss) {
+		if loss.Codec == "" {
+			loss.Codec = t
+		}
+		l.list = append(l.list, loss)
+	}
+}
+
+// check returns a LossError for the losses collected, unless lossy
+// conversions are allowed, in which case it reports them
+func (l *losses) check() error {
+	if len(l.list) == 0 {
+		return nil
+	}
+	if !l.cfg.allowLossy {
+		return &LossError{Losses: l.list}
+	}
+	if l.cfg.report != nil {
+		for _, loss := range l.list {
+			l.cfg.report(loss)
+		}
+	}
+	return nil
+}
+
+// CollectLosses returns a function collecting the losses of a conversion
+// by codec type t, and a function returning them as a *LossError if strict
+// is set. Codec packages use it so that their Codec[Value] fails on lossy
+// conversions under WithStrict and otherwise converts to the closest form.
+func CollectLosses(t Type, strict bool) (lossy func(Loss), check func() error) {
+	l := &losses{cfg: &transcodeConfig{allowLossy: !strict}}
+	return l.collect(t), l.check
+}
+
+// DecodeValue reads a single value of codec type t from r. If the value
+// holds data the Value model cannot represent exactly, such as an integer
+// beyond 64 bits, it returns a *LossError unless WithAllowLossy is given. It also
+// accepts the options that apply to every codec, such as WithLimits and
+// WithStrict.
+func DecodeValue(t Type, r io.Reader, opts ...Option) (Value, error) {
+	var cfg transcodeConfig
+	if err := ApplyOptions(t, &cfg, opts); err != nil {
+		return Value{}, err
+	}
+	vc, err := valueCodecFor(t)
+	if err != nil {
+		return Value{}, err
+	}
+	l := &losses{cfg: &cfg}
+	v, err := vc.DecodeValue(r, cfg.Config, l.collect(t))
+	if err != nil {
+		return Value{}, err
+	}
+	if err := l.check(); err != nil {
+		return Value{}, err
+	}
+	return v, nil
+}
+
+// EncodeValue writes v to w in the encoding of codec type t. If the
+// encoding cannot represent v exactly it writes nothing and returns a
+// *LossError, unless WithAllowLossy is given.
+func EncodeValue(t Type, w io.Writer, v Value, opts ...Option) error {
+	var cfg transcodeConfig
+	if err := ApplyOptions(t, &cfg, opts); err != nil {
+		return err
+	}
+	vc, err := valueCodecFor(t)
+	if err != nil {
+		return err
+	}
+	return encodeValue(t, vc, w, v, &losses{cfg: &cfg})
+}
+
+// Transcode converts a single value read from in, in the encoding of codec
+// type src, to the encoding of codec type dst written to out, without a Go
+// type to decode into. Integers, floats, byte strings, times and the order
+// of map entries carry over where both encodings support them.
+//
+// If the value cannot be converted exactly, such as a byte string written
+// to TOML or a BSON ObjectID to JSON, Transcode writes nothing and returns a
+// *LossError listing each loss, unless WithAllowLossy is given. The options
+// that apply to every codec, such as WithLimits and WithStrict, apply to
+// decoding in.
+func Transcode(dst, src Type, in io.Reader, out io.Writer, opts ...Option) error {
+	var cfg transcodeConfig
+	if err := ApplyOptions(dst, &cfg, opts); err != nil {
+		return err
+	}
+	from, err := valueCodecFor(src)
+	if err != nil {
+		return err
+	}
+	to, err := valueCodecFor(dst)
+	if err != nil {
+		return err
+	}
+	l := &losses{cfg: &cfg}
+	v, err := from.DecodeValue(in, cfg.Config, l.collect(src))
+	if err != nil {
+		return err
+	}
+	return encodeValue(dst, to, out, v, l)
+}
+
+// encodeValue encodes v with vc, writing to w only if the conversion is
+// exact or lossy conversions are allowed
+func encodeValue(t Type, vc ValueCodec, w io.Writer, v Value, l *losses) error {
+	var buf bytes.Buffer
+	if err := vc.EncodeValue(&buf, v, l.collect(t)); err != nil {
+		return err
+	}
+	if err := l.check(); err != nil {
+		return err
+	}
+	_, err := buf.WriteTo(w)
+	return err
+}
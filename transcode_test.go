@@ -0,0 +1,154 @@
+//go:build !codec_none
+
+package codec
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strings"
+	"testing"
+)
+
+// textValueCodec decodes text as a string Value and encodes the text form
+// of a Value, reporting a loss for every value that is not a string
+type textValueCodec struct{}
+
+func (textValueCodec) DecodeValue(r io.Reader, cfg Config, lossy func(Loss)) (Value, error) {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return Value{}, err
+	}
+	if strings.HasPrefix(string(data), "lossy:") {
+		lossy(Loss{Path: "root", Reason: "prefix dropped"})
+		data = data[len("lossy:"):]
+	}
+	return StringValue(string(data)), nil
+}
+
+func (textValueCodec) EncodeValue(w io.Writer, v Value, lossy func(Loss)) error {
+	if v.Kind() != KindString {
+		lossy(Loss{Reason: v.Kind().String() + " written as text"})
+	}
+	_, err := io.WriteString(w, v.String())
+	return err
+}
+
+func init() {
+	RegisterCodec("test_text")
+	RegisterValueCodec("test_text", textValueCodec{})
+	RegisterCodec("test_schema")
+}
+
+func TestTranscode(t *testing.T) {
+	var out bytes.Buffer
+	if err := Transcode("test_text", "test_text", strings.NewReader("hello"), &out); err != nil {
+		t.Fatalf("Transcode failed: %v", err)
+	}
+	if out.String() != "hello" {
+		t.Errorf("Transcode wrote %q", out.String())
+	}
+}
+
+func TestTranscode_LossError(t *testing.T) {
+	var out bytes.Buffer
+	err := Transcode("test_text", "test_text", strings.NewReader("lossy:hello"), &out)
+	var lossErr *LossError
+	if !errors.As(err, &lossErr) {
+		t.Fatalf("Expected *LossError, got %v", err)
+	}
+	if len(lossErr.Losses) != 1 {
+		t.Fatalf("Expected 1 loss, got %d", len(lossErr.Losses))
+	}
+	want := Loss{Codec: "test_text", Path: "root", Reason: "prefix dropped"}
+	if lossErr.Losses[0] != want {
+		t.Errorf("Loss = %+v, want %+v", lossErr.Losses[0], want)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing written, got %q", out.String())
+	}
+	if got := err.Error(); got != "codec: lossy conversion: test_text: prefix dropped at root" {
+		t.Errorf("Error() = %q", got)
+	}
+}
+
+func TestTranscode_AllowLossy(t *testing.T) {
+	var reported []Loss
+	var out bytes.Buffer
+	err := Transcode("test_text", "test_text", strings.NewReader("lossy:hello"), &out,
+		WithAllowLossy(func(l Loss) { reported = append(reported, l) }))
+	if err != nil {
+		t.Fatalf("Transcode failed: %v", err)
+	}
+	if out.String() != "hello" {
+		t.Errorf("Transcode wrote %q", out.String())
+	}
+	if len(reported) != 1 || reported[0].Reason != "prefix dropped" {
+		t.Errorf("Reported losses = %v", reported)
+	}
+}
+
+func TestTranscode_NotSupported(t *testing.T) {
+	var out bytes.Buffer
+	err := Transcode("test_schema", "test_text", strings.NewReader("x"), &out)
+	var notSupported ErrTranscodeNotSupported
+	if !errors.As(err, &notSupported) || notSupported.CodecType != "test_schema" {
+		t.Errorf("Expected ErrTranscodeNotSupported for test_schema, got %v", err)
+	}
+
+	err = Transcode("test_text", "test_missing", strings.NewReader("x"), &out)
+	var unknown ErrCodecNotSupported
+	if !errors.As(err, &unknown) {
+		t.Errorf("Expected ErrCodecNotSupported, got %v", err)
+	}
+}
+
+func TestEncodeValue_LossError(t *testing.T) {
+	var out bytes.Buffer
+	err := EncodeValue("test_text", &out, IntValue(1))
+	var lossErr *LossError
+	if !errors.As(err, &lossErr) || lossErr.Losses[0].Codec != "test_text" {
+		t.Fatalf("Expected *LossError from test_text, got %v", err)
+	}
+	if out.Len() != 0 {
+		t.Errorf("Expected nothing written, got %q", out.String())
+	}
+
+	if err := EncodeValue("test_text", &out, IntValue(1), WithAllowLossy(nil)); err != nil {
+		t.Fatalf("EncodeValue failed: %v", err)
+	}
+	if out.String() != "1" {
+		t.Errorf("EncodeValue wrote %q", out.String())
+	}
+}
+
+func TestDecodeValue(t *testing.T) {
+	v, err := DecodeValue("test_text", strings.NewReader("hello"))
+	if err != nil {
+		t.Fatalf("DecodeValue failed: %v", err)
+	}
+	if v.Kind() != KindString || v.String() != "hello" {
+		t.Errorf("DecodeValue = %s %q", v.Kind(), v)
+	}
+}
+
+func TestLossError_Error(t *testing.T) {
+	err := &LossError{Losses: []Loss{
+		{Codec: JSON, Path: "a", Reason: "first"},
+		{Codec: JSON, Reason: "second"},
+		{Codec: JSON, Reason: "third"},
+	}}
+	want := "codec: lossy conversion: json: first at a (and 2 more)"
+	if got := err.Error(); got != want {
+		t.Errorf("Error() = %q, want %q", got, want)
+	}
+}
+
+func TestRegisterValueCodec_Unregistered(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected RegisterValueCodec to panic")
+		}
+	}()
+	RegisterValueCodec("test_unregistered", textValueCodec{})
+}
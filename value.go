@@ -0,0 +1,351 @@
+package codec
+
+import (
+	"bytes"
+	"encoding/base64"
+	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Kind identifies the kind of data held by a Value
+type Kind int
+
+const (
+	// KindNull is the kind of the zero Value
+	KindNull Kind = iota
+	KindBool
+	KindInt
+	KindUint
+	KindFloat
+	KindString
+	KindBytes
+	KindTime
+	KindArray
+	KindMap
+	KindTagged
+)
+
+// String returns the name of the kind
+func (k Kind) String() string {
+	switch k {
+	case KindNull:
+		return "null"
+	case KindBool:
+		return "bool"
+	case KindInt:
+		return "int"
+	case KindUint:
+		return "uint"
+	case KindFloat:
+		return "float"
+	case KindString:
+		return "string"
+	case KindBytes:
+		return "bytes"
+	case KindTime:
+		return "time"
+	case KindArray:
+		return "array"
+	case KindMap:
+		return "map"
+	case KindTagged:
+		return "tagged"
+	default:
+		return fmt.Sprintf("kind %d", int(k))
+	}
+}
+
+// Value is a value decoded without a Go type, in a model shared by every
+// codec. Unlike decoding into an any, a Value keeps integers apart from
+// floats, byte strings apart from text, timestamps as times, and map
+// entries in the order they were encoded. Data specific to one encoding,
+// such as a CBOR tag, a MessagePack extension or a BSON ObjectID, is kept as
+// a tagged Value, which its codec writes back unchanged. The zero Value is
+// null.
+type Value struct {
+	kind Kind
+	num  uint64
+	str  string
+	ref  any // []byte, time.Time, []Value, []Member or *tagged
+}
+
+// Member is an entry of a map Value. Keys are usually strings, but CBOR and
+// MessagePack maps may have keys of any kind.
+type Member struct {
+	Key   Value
+	Value Value
+}
+
+// Tag identifies the encoding-specific type of a tagged Value
+type Tag struct {
+	// Codec is the codec type the tag belongs to
+	Codec Type
+
+	// Number is the CBOR tag number, MessagePack extension type or BSON
+	// element type
+	Number int64
+
+	// Name is the YAML tag, e.g. "!secret", or the name of the BSON
+	// element type, e.g. "ObjectID"
+	Name string
+}
+
+// String returns the codec type followed by the name or number of the tag,
+// e.g. "cbor:37" or "bson:ObjectID"
+func (t Tag) String() string {
+	if t.Name != "" {
+		return string(t.Codec) + ":" + t.Name
+	}
+	return string(t.Codec) + ":" + strconv.FormatInt(t.Number, 10)
+}
+
+// tagged is the tag and content of a KindTagged Value
+type tagged struct {
+	tag     Tag
+	content Value
+}
+
+// NullValue returns a null Value
+func NullValue() Value {
+	return Value{}
+}
+
+// BoolValue returns a Value for b
+func BoolValue(b bool) Value {
+	v := Value{kind: KindBool}
+	if b {
+		v.num = 1
+	}
+	return v
+}
+
+// IntValue returns a Value for the signed integer i
+func IntValue(i int64) Value {
+	return Value{kind: KindInt, num: uint64(i)}
+}
+
+// UintValue returns a Value for the unsigned integer u
+func UintValue(u uint64) Value {
+	return Value{kind: KindUint, num: u}
+}
+
+// FloatValue returns a Value for f
+func FloatValue(f float64) Value {
+	return Value{kind: KindFloat, num: math.Float64bits(f)}
+}
+
+// StringValue returns a Value for the text s
+func StringValue(s string) Value {
+	return Value{kind: KindString, str: s}
+}
+
+// BytesValue returns a Value for the byte string b
+func BytesValue(b []byte) Value {
+	return Value{kind: KindBytes, ref: b}
+}
+
+// TimeValue returns a Value for t
+func TimeValue(t time.Time) Value {
+	return Value{kind: KindTime, ref: t}
+}
+
+// ArrayValue returns an array Value holding items
+func ArrayValue(items ...Value) Value {
+	return Value{kind: KindArray, ref: items}
+}
+
+// MapValue returns a map Value holding members in order
+func MapValue(members ...Member) Value {
+	return Value{kind: KindMap, ref: members}
+}
+
+// TaggedValue returns a Value for content tagged with tag. Codecs other
+// than tag.Codec write the content alone and report the tag as a loss.
+func TaggedValue(tag Tag, content Value) Value {
+	return Value{kind: KindTagged, ref: &tagged{tag: tag, content: content}}
+}
+
+// Kind returns the kind of v
+func (v Value) Kind() Kind {
+	return v.kind
+}
+
+// IsNull reports whether v is null
+func (v Value) IsNull() bool {
+	return v.kind == KindNull
+}
+
+// Bool returns the value of a KindBool Value. It panics for other kinds.
+func (v Value) Bool() bool {
+	v.mustBe(KindBool)
+	return v.num != 0
+}
+
+// Int returns the value of a KindInt Value. It panics for other kinds.
+func (v Value) Int() int64 {
+	v.mustBe(KindInt)
+	return int64(v.num)
+}
+
+// Uint returns the value of a KindUint Value. It panics for other kinds.
+func (v Value) Uint() uint64 {
+	v.mustBe(KindUint)
+	return v.num
+}
+
+// Float returns the value of a KindFloat Value. It panics for other kinds.
+func (v Value) Float() float64 {
+	v.mustBe(KindFloat)
+	return math.Float64frombits(v.num)
+}
+
+// Bytes returns the value of a KindBytes Value. It panics for other kinds.
+func (v Value) Bytes() []byte {
+	v.mustBe(KindBytes)
+	b, _ := v.ref.([]byte)
+	return b
+}
+
+// Time returns the value of a KindTime Value. It panics for other kinds.
+func (v Value) Time() time.Time {
+	v.mustBe(KindTime)
+	return v.ref.(time.Time)
+}
+
+// Array returns the items of a KindArray Value. It panics for other kinds.
+func (v Value) Array() []Value {
+	v.mustBe(KindArray)
+	items, _ := v.ref.([]Value)
+	return items
+}
+
+// Members returns the members of a KindMap Value in order. It panics for
+// other kinds.
+func (v Value) Members() []Member {
+	v.mustBe(KindMap)
+	members, _ := v.ref.([]Member)
+	return members
+}
+
+// Tag returns the tag of a KindTagged Value. It panics for other kinds.
+func (v Value) Tag() Tag {
+	v.mustBe(KindTagged)
+	return v.ref.(*tagged).tag
+}
+
+// Content returns the tagged content of a KindTagged Value. It panics for
+// other kinds.
+func (v Value) Content() Value {
+	v.mustBe(KindTagged)
+	return v.ref.(*tagged).content
+}
+
+// Len returns the number of items of an array, members of a map, bytes of
+// a byte string or text, and 0 for other kinds
+func (v Value) Len() int {
+	switch v.kind {
+	case KindString:
+		return len(v.str)
+	case KindBytes:
+		return len(v.Bytes())
+	case KindArray:
+		return len(v.Array())
+	case KindMap:
+		return len(v.Members())
+	default:
+		return 0
+	}
+}
+
+// Lookup returns the value of the member of a map with the string key key.
+// It reports false if v is not a map or has no such member.
+func (v Value) Lookup(key string) (Value, bool) {
+	if v.kind != KindMap {
+		return Value{}, false
+	}
+	for _, m := range v.Members() {
+		if m.Key.kind == KindString && m.Key.str == key {
+			return m.Value, true
+		}
+	}
+	return Value{}, false
+}
+
+// Equal reports whether v and w are identical: of the same kind, holding
+// floats with the same bits, times of the same instant and location, and
+// arrays and maps of equal items in the same order.
+func (v Value) Equal(w Value) bool {
+	if v.kind != w.kind {
+		return false
+	}
+	switch v.kind {
+	case KindBool, KindInt, KindUint, KindFloat:
+		return v.num == w.num
+	case KindString:
+		return v.str == w.str
+	case KindBytes:
+		return bytes.Equal(v.Bytes(), w.Bytes())
+	case KindTime:
+		return v.Time().Equal(w.Time()) && v.Time().Location().String() == w.Time().Location().String()
+	case KindArray:
+		return slices.EqualFunc(v.Array(), w.Array(), Value.Equal)
+	case KindMap:
+		return slices.EqualFunc(v.Members(), w.Members(), func(a, b Member) bool {
+			return a.Key.Equal(b.Key) && a.Value.Equal(b.Value)
+		})
+	case KindTagged:
+		return v.Tag() == w.Tag() && v.Content().Equal(w.Content())
+	default:
+		return true
+	}
+}
+
+// String returns the text of a KindString Value. For other kinds it returns
+// a textual form of v: decimal numbers, base64 for byte strings and RFC 3339
+// for times, which codecs also use for map keys that must be strings.
+func (v Value) String() string {
+	switch v.kind {
+	case KindNull:
+		return "null"
+	case KindBool:
+		return strconv.FormatBool(v.Bool())
+	case KindInt:
+		return strconv.FormatInt(v.Int(), 10)
+	case KindUint:
+		return strconv.FormatUint(v.Uint(), 10)
+	case KindFloat:
+		return strconv.FormatFloat(v.Float(), 'g', -1, 64)
+	case KindString:
+		return v.str
+	case KindBytes:
+		return base64.StdEncoding.EncodeToString(v.Bytes())
+	case KindTime:
+		return v.Time().Format(time.RFC3339Nano)
+	case KindArray:
+		items := make([]string, len(v.Array()))
+		for i, item := range v.Array() {
+			items[i] = item.String()
+		}
+		return "[" + strings.Join(items, " ") + "]"
+	case KindMap:
+		members := make([]string, len(v.Members()))
+		for i, m := range v.Members() {
+			members[i] = m.Key.String() + ":" + m.Value.String()
+		}
+		return "{" + strings.Join(members, " ") + "}"
+	case KindTagged:
+		return v.Tag().String() + "(" + v.Content().String() + ")"
+	default:
+		return v.kind.String()
+	}
+}
+
+func (v Value) mustBe(k Kind) {
+	if v.kind != k {
+		panic(fmt.Sprintf("codec: %s accessor called on %s Value", k, v.kind))
+	}
+}
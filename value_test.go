@@ -0,0 +1,144 @@
+package codec
+
+import (
+	"math"
+	"testing"
+	"time"
+)
+
+func TestValue_Accessors(t *testing.T) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+
+	if !NullValue().IsNull() || !(Value{}).IsNull() {
+		t.Error("Expected the zero Value to be null")
+	}
+	if !BoolValue(true).Bool() || BoolValue(false).Bool() {
+		t.Error("Bool round trip failed")
+	}
+	if got := IntValue(math.MinInt64).Int(); got != math.MinInt64 {
+		t.Errorf("Int() = %d", got)
+	}
+	if got := UintValue(math.MaxUint64).Uint(); got != math.MaxUint64 {
+		t.Errorf("Uint() = %d", got)
+	}
+	if got := FloatValue(1.5).Float(); got != 1.5 {
+		t.Errorf("Float() = %v", got)
+	}
+	if got := string(BytesValue([]byte("hi")).Bytes()); got != "hi" {
+		t.Errorf("Bytes() = %q", got)
+	}
+	if got := TimeValue(when).Time(); !got.Equal(when) {
+		t.Errorf("Time() = %v", got)
+	}
+	if got := ArrayValue(IntValue(1), IntValue(2)).Array(); len(got) != 2 {
+		t.Errorf("Array() has %d items", len(got))
+	}
+	members := MapValue(
+		Member{Key: StringValue("b"), Value: IntValue(1)},
+		Member{Key: StringValue("a"), Value: IntValue(2)},
+	).Members()
+	if len(members) != 2 || members[0].Key.String() != "b" || members[1].Key.String() != "a" {
+		t.Errorf("Members() = %v, want b before a", members)
+	}
+}
+
+func TestValue_AccessorPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("Expected Int on a string Value to panic")
+		}
+	}()
+	StringValue("1").Int()
+}
+
+func TestValue_String(t *testing.T) {
+	tests := []struct {
+		value Value
+		want  string
+	}{
+		{NullValue(), "null"},
+		{BoolValue(true), "true"},
+		{IntValue(-3), "-3"},
+		{UintValue(math.MaxUint64), "18446744073709551615"},
+		{FloatValue(0.25), "0.25"},
+		{StringValue("text"), "text"},
+		{BytesValue([]byte("hello")), "aGVsbG8="},
+		{TimeValue(time.Date(2024, 1, 2, 3, 4, 5, 6, time.UTC)), "2024-01-02T03:04:05.000000006Z"},
+		{ArrayValue(IntValue(1), StringValue("a")), "[1 a]"},
+		{MapValue(Member{Key: StringValue("k"), Value: BoolValue(false)}), "{k:false}"},
+	}
+
+	for _, tt := range tests {
+		if got := tt.value.String(); got != tt.want {
+			t.Errorf("%s Value String() = %q, want %q", tt.value.Kind(), got, tt.want)
+		}
+	}
+}
+
+func TestKind_String(t *testing.T) {
+	if got := KindMap.String(); got != "map" {
+		t.Errorf("KindMap.String() = %q", got)
+	}
+	if got := Kind(99).String(); got != "kind 99" {
+		t.Errorf("Kind(99).String() = %q", got)
+	}
+}
+
+func TestValue_Tagged(t *testing.T) {
+	tag := Tag{Codec: CBOR, Number: 32}
+	v := TaggedValue(tag, StringValue("https://example.com"))
+	if v.Kind() != KindTagged || v.Tag() != tag || v.Content().String() != "https://example.com" {
+		t.Errorf("TaggedValue = %s", v)
+	}
+	if got := v.String(); got != "cbor:32(https://example.com)" {
+		t.Errorf("String() = %q", got)
+	}
+	if got := (Tag{Codec: BSON, Number: 7, Name: "ObjectID"}).String(); got != "bson:ObjectID" {
+		t.Errorf("Tag.String() = %q", got)
+	}
+}
+
+func TestValue_Lookup(t *testing.T) {
+	v := MapValue(
+		Member{Key: IntValue(1), Value: StringValue("int key")},
+		Member{Key: StringValue("1"), Value: StringValue("string key")},
+	)
+	if got, ok := v.Lookup("1"); !ok || got.String() != "string key" {
+		t.Errorf("Lookup(\"1\") = %s, %v", got, ok)
+	}
+	if _, ok := v.Lookup("missing"); ok {
+		t.Error("Expected no member for a missing key")
+	}
+	if _, ok := IntValue(1).Lookup("1"); ok {
+		t.Error("Expected no member for a non-map")
+	}
+	if v.Len() != 2 || StringValue("abc").Len() != 3 || IntValue(1).Len() != 0 {
+		t.Error("Len() mismatch")
+	}
+}
+
+func TestValue_Equal(t *testing.T) {
+	when := time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC)
+	tests := []struct {
+		name string
+		a, b Value
+		want bool
+	}{
+		{"null", NullValue(), Value{}, true},
+		{"int and uint", IntValue(1), UintValue(1), false},
+		{"floats", FloatValue(1.5), FloatValue(1.5), true},
+		{"NaN", FloatValue(math.NaN()), FloatValue(math.NaN()), true},
+		{"bytes", BytesValue([]byte{1}), BytesValue([]byte{1}), true},
+		{"time zones", TimeValue(when), TimeValue(when.In(time.FixedZone("", 3600))), false},
+		{"arrays", ArrayValue(IntValue(1)), ArrayValue(IntValue(1), IntValue(2)), false},
+		{"member order", MapValue(Member{StringValue("a"), NullValue()}, Member{StringValue("b"), NullValue()}),
+			MapValue(Member{StringValue("b"), NullValue()}, Member{StringValue("a"), NullValue()}), false},
+		{"tags", TaggedValue(Tag{Codec: CBOR, Number: 1}, IntValue(0)), TaggedValue(Tag{Codec: CBOR, Number: 2}, IntValue(0)), false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.a.Equal(tt.b); got != tt.want {
+			t.Errorf("%s: Equal() = %v, want %v", tt.name, got, tt.want)
+		}
+	}
+}